@@ -0,0 +1,126 @@
+// Package client is the HTTP client of gost server, used by the commands in the remote mode (--server-url)
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/config"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// ErrNotFound is returned when the server responds 404
+var ErrNotFound = xerrors.New("Not found")
+
+// Client requests the server at the base URL with the bearer token of the API
+type Client struct {
+	baseURL *url.URL
+	token   string
+	http    *http.Client
+}
+
+// New returns the Client of the server at serverURL (e.g. https://gost.example.com:1325).
+// If token is not empty, it is sent as the bearer token. timeout is of each request, and 0 means no timeout.
+func New(serverURL, token string, timeout time.Duration) (*Client, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to parse server URL. URL: %s, err: %w", serverURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, xerrors.Errorf("Invalid server URL: %s. It must be http(s)://host[:port]", serverURL)
+	}
+	return &Client{baseURL: u, token: token, http: &http.Client{Timeout: timeout}}, nil
+}
+
+// GetJSON requests GET of the path with query, and decodes the JSON response into v
+func (c *Client) GetJSON(ctx context.Context, p string, query url.Values, v interface{}) error {
+	body, u, err := c.get(ctx, p, query, "application/json")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return xerrors.Errorf("Failed to decode response. URL: %s, err: %w", u, err)
+	}
+	return nil
+}
+
+// get requests GET of the path with query, and returns the body of the response of 200 and the URL requested
+func (c *Client) get(ctx context.Context, p string, query url.Values, accept string) ([]byte, string, error) {
+	u := *c.baseURL
+	u.Path = path.Join("/", u.Path, p)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", xerrors.Errorf("Failed to create request. err: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", fmt.Sprintf("gost/%s", config.Version))
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, "", xerrors.Errorf("Failed to request. URL: %s, err: %w", u.String(), err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", xerrors.Errorf("Failed to read response. URL: %s, err: %w", u.String(), err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		return nil, "", xerrors.Errorf("%s: %w", u.Path, ErrNotFound)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, "", xerrors.Errorf("Failed to request. URL: %s, status: %d, message: %s", u.String(), res.StatusCode, errorMessage(body))
+	}
+	return body, u.String(), nil
+}
+
+// errorMessage returns the message of the error response of echo ({"message": "..."}), or the body as it is
+func errorMessage(body []byte) string {
+	var e struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &e); err == nil && e.Message != "" {
+		return e.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// GetVulnInfo returns the CVE summarized over the sources (GET /cves/:id/vulninfo)
+func (c *Client) GetVulnInfo(ctx context.Context, cveID string) (models.VulnInfo, error) {
+	info := models.VulnInfo{}
+	err := c.GetJSON(ctx, "/cves/"+url.PathEscape(cveID)+"/vulninfo", nil, &info)
+	return info, err
+}
+
+// GetAttributions returns the licenses and the attributions of the sources fetched by the server (GET /attributions)
+func (c *Client) GetAttributions(ctx context.Context) ([]models.FetchSource, error) {
+	sources := []models.FetchSource{}
+	err := c.GetJSON(ctx, "/attributions", nil, &sources)
+	return sources, err
+}
+
+// GetCveIDs returns all the CVE-IDs of the source (GET /cveids/:source)
+func (c *Client) GetCveIDs(ctx context.Context, source string) ([]string, error) {
+	body, _, err := c.get(ctx, "/cveids/"+url.PathEscape(source), nil, "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	cveIDs := []string{}
+	for _, line := range strings.Split(string(body), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			cveIDs = append(cveIDs, line)
+		}
+	}
+	return cveIDs, nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// rubysecCmd represents the rubysec command
+var rubysecCmd = &cobra.Command{
+	Use:   "rubysec",
+	Short: "Fetch the advisories from ruby-advisory-db",
+	Long:  `Fetch the advisories of RubyGems from ruby-advisory-db (https://github.com/rubysec/ruby-advisory-db)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchPackageAdvisories(models.AdvisoryDBRubySec, func() ([]models.PackageAdvisory, error) {
+			advs, err := fetcher.RetrieveRubySec(viper.GetString("rubysec-archive"))
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertRubySec(advs, fetchWarnings), nil
+		})
+	},
+}
+
+// pypaCmd represents the pypa command
+var pypaCmd = &cobra.Command{
+	Use:   "pypa",
+	Short: "Fetch the advisories from PyPA advisory database",
+	Long:  `Fetch the advisories of PyPI from PyPA advisory database (https://github.com/pypa/advisory-database)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchPackageAdvisories(models.AdvisoryDBPyPA, func() ([]models.PackageAdvisory, error) {
+			vulns, err := fetcher.RetrievePyPA(viper.GetString("pypa-archive"))
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertPyPA(vulns, fetchWarnings), nil
+		})
+	},
+}
+
+// npmCmd represents the npm command
+var npmCmd = &cobra.Command{
+	Use:   "npm",
+	Short: "Fetch the advisories of npm from GitHub Advisory Database",
+	Long:  `Fetch the advisories of npm reviewed in GitHub Advisory Database (https://github.com/github/advisory-database) without GitHub token`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchPackageAdvisories(models.AdvisoryDBNpm, func() ([]models.PackageAdvisory, error) {
+			vulns, err := fetcher.RetrieveNpmAdvisories(viper.GetString("npm-archive"))
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertNpmAdvisories(vulns, fetchWarnings), nil
+		})
+	},
+}
+
+func init() {
+	fetchCmd.AddCommand(rubysecCmd)
+	fetchCmd.AddCommand(pypaCmd)
+	fetchCmd.AddCommand(npmCmd)
+
+	rubysecCmd.PersistentFlags().String("archive", fetcher.RubySecArchiveURL, "Path or URL of the tarball of ruby-advisory-db")
+	_ = viper.BindPFlag("rubysec-archive", rubysecCmd.PersistentFlags().Lookup("archive"))
+
+	pypaCmd.PersistentFlags().String("archive", fetcher.PyPAArchiveURL, "Path or URL of the tarball of PyPA advisory database")
+	_ = viper.BindPFlag("pypa-archive", pypaCmd.PersistentFlags().Lookup("archive"))
+
+	npmCmd.PersistentFlags().String("archive", fetcher.NpmArchiveURL, "Path or URL of the tarball of GitHub Advisory Database")
+	_ = viper.BindPFlag("npm-archive", npmCmd.PersistentFlags().Lookup("archive"))
+}
+
+// fetchPackageAdvisories replaces the advisories of the source by the ones retrieved
+func fetchPackageAdvisories(source string, retrieve func() ([]models.PackageAdvisory, error)) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch package advisories", "source", source)
+	advisories, err := retrieve()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "advisories", len(advisories))
+
+	log15.Info("Insert package advisories into DB", "source", source, "db", driver.Name())
+	if err := driver.InsertPackageAdvisories(source, advisories); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/client"
+	"github.com/knqyf263/gost/config"
+	"github.com/knqyf263/gost/encoder"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// analyticsCmd represents the analytics command
+var analyticsCmd = &cobra.Command{
+	Use:               "analytics",
+	Short:             "Analyze the stored data of the security tracker",
+	Long:              `Analyze the stored data of the security tracker`,
+	PersistentPreRunE: checkAnalytics,
+}
+
+// remoteAnnotation is the annotation of the subcommands of analytics supporting --server-url
+const remoteAnnotation = "gost:remote"
+
+func init() {
+	RootCmd.AddCommand(analyticsCmd)
+
+	analyticsCmd.PersistentFlags().String("format", "json", fmt.Sprintf("Output format (%s). cyclonedx, osv and sarif are of vulninfo", strings.Join(encoder.Names(), ", ")))
+	_ = viper.BindPFlag("format", analyticsCmd.PersistentFlags().Lookup("format"))
+
+	analyticsCmd.PersistentFlags().String("template", "", "/path/to/Go template of --format template, executed with .Value (the report), .Records (the rows of CSV) and .Vulns (the CVEs of vulninfo)")
+	_ = viper.BindPFlag("template", analyticsCmd.PersistentFlags().Lookup("template"))
+
+	analyticsCmd.PersistentFlags().String("output", "", "/path/to/output (default: stdout)")
+	_ = viper.BindPFlag("output", analyticsCmd.PersistentFlags().Lookup("output"))
+
+	analyticsCmd.PersistentFlags().String("server-url", "", "URL of gost server (e.g. https://gost.example.com:1325) requested instead of opening the DB. The subcommands not supporting it fail")
+	_ = viper.BindPFlag("server-url", analyticsCmd.PersistentFlags().Lookup("server-url"))
+
+	analyticsCmd.PersistentFlags().String("server-token", "", "API token of --server-url (default: $GOST_SERVER_TOKEN)")
+	_ = viper.BindPFlag("server-token", analyticsCmd.PersistentFlags().Lookup("server-token"))
+
+	analyticsCmd.PersistentFlags().Duration("server-timeout", 30*time.Second, "Timeout of each request to --server-url. 0 means no timeout")
+	_ = viper.BindPFlag("server-timeout", analyticsCmd.PersistentFlags().Lookup("server-timeout"))
+
+	analyticsCmd.PersistentFlags().Bool("attribution", false, `Wrap the JSON report as {"result": the report, "attributions": the licenses of the sources fetched}`)
+	_ = viper.BindPFlag("attribution", analyticsCmd.PersistentFlags().Lookup("attribution"))
+}
+
+func checkAnalytics(cmd *cobra.Command, args []string) error {
+	if viper.GetString("server-url") != "" && cmd.Annotations[remoteAnnotation] == "" {
+		return xerrors.Errorf("gost analytics %s needs the DB and does not support --server-url", cmd.Name())
+	}
+	if _, err := newEncoder(); err != nil {
+		return err
+	}
+	if viper.GetBool("attribution") && viper.GetString("format") != "json" {
+		return xerrors.New("--attribution requires --format json")
+	}
+	return nil
+}
+
+// remoteClient returns the client of --server-url, or nil if the subcommands use the DB
+func remoteClient() (*client.Client, error) {
+	serverURL := viper.GetString("server-url")
+	if serverURL == "" {
+		return nil, nil
+	}
+	token := viper.GetString("server-token")
+	if token == "" {
+		token = os.Getenv("GOST_SERVER_TOKEN")
+	}
+	return client.New(serverURL, token, viper.GetDuration("server-timeout"))
+}
+
+// attributions returns the licenses of the sources fetched in the DB, or in the server of --server-url
+func attributions() ([]models.FetchSource, error) {
+	c, err := remoteClient()
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		return c.GetAttributions(context.Background())
+	}
+	driver, _, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	return driver.GetFetchSources()
+}
+
+// newEncoder returns the encoder of --format
+func newEncoder() (encoder.Encoder, error) {
+	return encoder.New(viper.GetString("format"), encoder.Options{Template: viper.GetString("template"), Version: config.Version})
+}
+
+// writeAnalytics writes the report in --format, with the attributions if --attribution
+func writeAnalytics(report encoder.Report) error {
+	enc, err := newEncoder()
+	if err != nil {
+		return err
+	}
+	if viper.GetBool("attribution") {
+		sources, err := attributions()
+		if err != nil {
+			return xerrors.Errorf("Failed to get the attributions. err: %w", err)
+		}
+		report.Value = map[string]interface{}{"result": report.Value, "attributions": sources}
+	}
+
+	var w io.Writer = os.Stdout
+	if path := viper.GetString("output"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return xerrors.Errorf("Failed to create output. path: %s, err: %w", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return enc.Encode(w, report)
+}
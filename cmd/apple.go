@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// appleCmd represents the apple command
+var appleCmd = &cobra.Command{
+	Use:   "apple",
+	Short: "Fetch the Apple security releases",
+	Long:  `Fetch the CVEs fixed by the Apple security releases (https://support.apple.com/en-us/100100) of macOS, iOS, iPadOS, watchOS, tvOS, visionOS, Safari and Xcode`,
+	RunE:  fetchApple,
+}
+
+func init() {
+	fetchCmd.AddCommand(appleCmd)
+
+	appleCmd.PersistentFlags().String("url", fetcher.AppleSecurityReleasesURL, "URL of the page of the Apple security releases")
+	_ = viper.BindPFlag("apple-url", appleCmd.PersistentFlags().Lookup("url"))
+
+	appleCmd.PersistentFlags().String("pmv-url", fetcher.ApplePmvURL, "URL of Apple Software Lookup Service to get the builds of iOS and macOS. Empty to skip")
+	_ = viper.BindPFlag("apple-pmv-url", appleCmd.PersistentFlags().Lookup("pmv-url"))
+}
+
+func fetchApple(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	rows, err := fetcher.RetrieveAppleReleases(viper.GetString("apple-url"), fetchOptions()...)
+	if err != nil {
+		return err
+	}
+
+	// The builds are optional, since the service is not reachable from everywhere (e.g. its certificate is issued by Apple's own CA)
+	var pmv *models.ApplePmvJSON
+	if url := viper.GetString("apple-pmv-url"); url != "" {
+		if pmv, err = fetcher.RetrieveApplePmv(url); err != nil {
+			log15.Warn("Failed to get the builds of iOS and macOS. The builds are left empty", "err", err)
+		}
+	}
+	releases := db.ConvertApple(rows, pmv, fetchWarnings)
+
+	log15.Info("Fetched", "releases", len(releases))
+
+	log15.Info("Insert Apple security releases into DB", "db", driver.Name())
+	if err := driver.InsertApple(releases); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
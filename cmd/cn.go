@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// cnnvdCmd represents the cnnvd command
+var cnnvdCmd = &cobra.Command{
+	Use:   "cnnvd",
+	Short: "Fetch the advisories from CNNVD",
+	Long:  `Fetch the advisories from the XML data feeds of CNNVD (https://www.cnnvd.org.cn)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchCn(models.CnSourceCnnvd, func() ([]models.CnAdvisory, error) {
+			entries, err := fetcher.RetrieveCnnvd(viper.GetStringSlice("cnnvd-files"))
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertCnnvd(entries, fetchWarnings), nil
+		})
+	},
+}
+
+// cnvdCmd represents the cnvd command
+var cnvdCmd = &cobra.Command{
+	Use:   "cnvd",
+	Short: "Fetch the advisories from CNVD",
+	Long:  `Fetch the advisories from the shared XML data of CNVD (https://www.cnvd.org.cn)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchCn(models.CnSourceCnvd, func() ([]models.CnAdvisory, error) {
+			vulns, err := fetcher.RetrieveCnvd(viper.GetStringSlice("cnvd-files"))
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertCnvd(vulns, fetchWarnings), nil
+		})
+	},
+}
+
+func init() {
+	fetchCmd.AddCommand(cnnvdCmd)
+	fetchCmd.AddCommand(cnvdCmd)
+
+	cnnvdCmd.PersistentFlags().StringSlice("files", nil, "Paths or URLs of the XML data feeds of CNNVD")
+	_ = viper.BindPFlag("cnnvd-files", cnnvdCmd.PersistentFlags().Lookup("files"))
+
+	cnvdCmd.PersistentFlags().StringSlice("files", nil, "Paths or URLs of the shared XML data of CNVD")
+	_ = viper.BindPFlag("cnvd-files", cnvdCmd.PersistentFlags().Lookup("files"))
+}
+
+// fetchCn replaces the advisories of the source by the ones retrieved
+func fetchCn(source string, retrieve func() ([]models.CnAdvisory, error)) (err error) {
+	if len(viper.GetStringSlice(source+"-files")) == 0 {
+		return xerrors.Errorf("--files is required, since %s requires an account to download the data", source)
+	}
+
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch advisories", "source", source)
+	advisories, err := retrieve()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "advisories", len(advisories))
+
+	log15.Info("Insert advisories into DB", "source", source, "db", driver.Name())
+	if err := driver.InsertCn(source, advisories); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/client"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/encoder"
+	"github.com/knqyf263/gost/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// coverageCmd represents the coverage command
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Compare the CVEs in NVD with the CVEs covered by each source",
+	Long:  `Compare the CVEs in NVD with the CVEs covered by each source, and list the CVEs with no distro statement`,
+	RunE:  executeCoverage,
+	// GET /cveids/:source
+	Annotations: map[string]string{remoteAnnotation: "true"},
+}
+
+func init() {
+	analyticsCmd.AddCommand(coverageCmd)
+
+	coverageCmd.Flags().String("nvd-cve-ids", "", "/path/to/file of CVE-IDs in NVD (one per line). If empty, the CVE-IDs fetched by gost fetch nvd are used")
+	_ = viper.BindPFlag("nvd-cve-ids", coverageCmd.Flags().Lookup("nvd-cve-ids"))
+}
+
+func executeCoverage(cmd *cobra.Command, args []string) (err error) {
+	driver, err := coverageCveIDs()
+	if err != nil {
+		return err
+	}
+
+	var nvdCveIDs []string
+	if path := viper.GetString("nvd-cve-ids"); path != "" {
+		if nvdCveIDs, err = util.ReadLines(path); err != nil {
+			return err
+		}
+	} else {
+		if nvdCveIDs, err = driver.GetCveIDs("nvd"); err != nil {
+			log15.Error("Failed to get CVE-IDs of NVD.", "err", err)
+			return err
+		}
+		if len(nvdCveIDs) == 0 {
+			return xerrors.New("No CVE of NVD in DB. Run gost fetch nvd or specify --nvd-cve-ids")
+		}
+	}
+
+	report, covered, err := db.GetCoverage(driver, nvdCveIDs)
+	if err != nil {
+		log15.Error("Failed to compute coverage.", "err", err)
+		return err
+	}
+
+	sources := db.CoverageSources()
+	records := [][]string{append([]string{"cve_id"}, sources...)}
+	ids := []string{}
+	for id := range covered {
+		ids = append(ids, id)
+	}
+	ids = append(ids, report.Uncovered...)
+	sort.Strings(ids)
+	for _, id := range ids {
+		has := map[string]bool{}
+		for _, s := range covered[id] {
+			has[s] = true
+		}
+		record := []string{id}
+		for _, s := range sources {
+			if has[s] {
+				record = append(record, "1")
+			} else {
+				record = append(record, "0")
+			}
+		}
+		records = append(records, record)
+	}
+	return writeAnalytics(encoder.Report{Value: report, Records: records})
+}
+
+// remoteCveIDs gets the CVE-IDs of the sources from the server of --server-url
+type remoteCveIDs struct {
+	client *client.Client
+}
+
+func (r remoteCveIDs) GetCveIDs(source string) ([]string, error) {
+	return r.client.GetCveIDs(context.Background(), source)
+}
+
+// coverageCveIDs returns the DB, or the server of --server-url, to get the CVE-IDs of the sources from
+func coverageCveIDs() (db.CveIDsGetter, error) {
+	c, err := remoteClient()
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		return remoteCveIDs{client: c}, nil
+	}
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before analyzing", "err", err)
+		}
+		return nil, err
+	}
+	return driver, nil
+}
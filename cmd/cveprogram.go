@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// cveListCmd represents the cvelist command
+var cveListCmd = &cobra.Command{
+	Use:   "cvelist",
+	Short: "Fetch the CVE records from CVE Program (cvelistV5)",
+	Long:  `Fetch the CVE records from CVE Program (https://github.com/CVEProject/cvelistV5). After the first fetch, only the records changed since the last fetch are fetched by the deltas`,
+	RunE:  fetchCveList,
+}
+
+func init() {
+	fetchCmd.AddCommand(cveListCmd)
+
+	cveListCmd.PersistentFlags().String("zip", "", "Path to the archive of cvelistV5 (e.g. main.zip) to import instead of downloading")
+	_ = viper.BindPFlag("cvelist-zip", cveListCmd.PersistentFlags().Lookup("zip"))
+
+	cveListCmd.PersistentFlags().Bool("full", false, "Fetch all CVE records even if the deltas since the last fetch are available")
+	_ = viper.BindPFlag("cvelist-full", cveListCmd.PersistentFlags().Lookup("full"))
+}
+
+func fetchCveList(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	last, err := driver.GetCveProgramFetchTime()
+	if err != nil {
+		log15.Error("Failed to get the fetch time of cvelistV5 from DB.", "err", err)
+		return err
+	}
+
+	full := viper.GetBool("cvelist-full") || viper.GetString("cvelist-zip") != "" || last.IsZero()
+	if !full {
+		log15.Info("Fetch the CVE records changed since the last fetch", "last", last.Format(time.RFC3339))
+		records, fetchTime, err := fetcher.RetrieveCveListDelta(last, fetchOptions()...)
+		switch {
+		case errors.Is(err, fetcher.ErrCveDeltaLogExpired):
+			log15.Warn("The deltas since the last fetch are not available. Fetch all CVE records", "last", last.Format(time.RFC3339))
+			full = true
+		case err != nil:
+			return err
+		default:
+			log15.Info("Fetched", "records", len(records))
+			log15.Info("Upsert CVE Program records into DB", "db", driver.Name())
+			if err := driver.UpsertCveProgram(records, fetchTime); err != nil {
+				log15.Error("Failed to upsert.", "dbpath", viper.GetString("dbpath"), "err", err)
+				return err
+			}
+		}
+	}
+
+	if full {
+		log15.Info("Fetch all CVE records from cvelistV5")
+		list, err := fetcher.OpenCveList(viper.GetString("cvelist-zip"))
+		if err != nil {
+			return err
+		}
+		defer list.Close()
+		if list.FetchTime.IsZero() {
+			log15.Warn("delta.json is not in the archive. The next fetch fetches all CVE records again")
+		}
+
+		log15.Info("Fetched", "records", list.Len())
+		log15.Info("Insert CVE Program records into DB", "db", driver.Name())
+		if err := driver.InsertCveProgram(list, list.FetchTime); err != nil {
+			log15.Error("Failed to insert.", "dbpath", viper.GetString("dbpath"), "err", err)
+			return err
+		}
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
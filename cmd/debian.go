@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"sort"
+
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/db"
 	"github.com/knqyf263/gost/fetcher"
@@ -20,11 +22,14 @@ var debianCmd = &cobra.Command{
 
 func init() {
 	fetchCmd.AddCommand(debianCmd)
+
+	debianCmd.PersistentFlags().Bool("elts", false, "Fetch the statuses of Debian ELTS (Extended LTS) by Freexian in addition")
+	_ = viper.BindPFlag("debian-elts", debianCmd.PersistentFlags().Lookup("elts"))
 }
 
 func fetchDebian(cmd *cobra.Command, args []string) (err error) {
 	log15.Info("Initialize Database")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	driver, locked, err := openDB()
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
@@ -50,8 +55,38 @@ func fetchDebian(cmd *cobra.Command, args []string) (err error) {
 
 	log15.Info("Fetched", "CVEs", len(cves))
 
-	log15.Info("Insert Debian CVEs into DB", "db", driver.Name())
-	if err := driver.InsertDebian(cves); err != nil {
+	if viper.GetBool("debian-elts") {
+		elts, err := fetcher.RetrieveDebianELTSCveDetails()
+		if err != nil {
+			return err
+		}
+		log15.Info("Fetched", "ELTS packages", len(elts))
+		fetcher.MergeDebianELTS(cves, elts)
+	}
+
+	cveIDs := viper.GetStringSlice("cve")
+	advisories, err := fetcher.RetrieveDebianAdvisories()
+	if err != nil {
+		// The CVEs are fetched from the other host, so the failure of salsa does not drop the DSA/DLA stored
+		log15.Warn("Failed to fetch DSA/DLA. Keeping the ones stored", "err", err)
+		if len(cveIDs) > 0 {
+			advisories = db.StoredDebianAdvisories(driver, cveIDs)
+		} else {
+			advisories = db.StoredDebianAdvisories(driver, debianCveIDs(cves))
+		}
+		log15.Info("Kept the stored", "DSA/DLA", len(advisories))
+	} else {
+		log15.Info("Fetched", "DSA/DLA", len(advisories))
+	}
+
+	if len(cveIDs) > 0 {
+		log15.Info("Upsert Debian CVEs into DB", "db", driver.Name(), "CVEs", cveIDs)
+		err = driver.UpsertDebian(fetcher.FilterDebianCves(cves, cveIDs), advisories)
+	} else {
+		log15.Info("Insert Debian CVEs into DB", "db", driver.Name())
+		err = driver.InsertDebian(cves, advisories)
+	}
+	if err != nil {
 		log15.Error("Failed to insert.", "dbpath",
 			viper.GetString("dbpath"), "err", err)
 		return err
@@ -64,3 +99,19 @@ func fetchDebian(cmd *cobra.Command, args []string) (err error) {
 
 	return nil
 }
+
+// debianCveIDs returns the CVE-IDs of cves in order
+func debianCveIDs(cves models.DebianJSON) []string {
+	uniq := map[string]struct{}{}
+	for _, cveMap := range cves {
+		for cveID := range cveMap {
+			uniq[cveID] = struct{}{}
+		}
+	}
+	cveIDs := make([]string, 0, len(uniq))
+	for cveID := range uniq {
+		cveIDs = append(cveIDs, cveID)
+	}
+	sort.Strings(cveIDs)
+	return cveIDs
+}
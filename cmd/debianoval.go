@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/encoder"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// debianOVALCmd represents the debian-oval command
+var debianOVALCmd = &cobra.Command{
+	Use:   "debian-oval",
+	Short: "Cross-check the fix status of Debian Security Tracker with Debian OVAL",
+	Long:  `Cross-check the fix status of Debian Security Tracker in DB with Debian OVAL, and report the disagreements`,
+	RunE:  executeDebianOVAL,
+}
+
+func init() {
+	analyticsCmd.AddCommand(debianOVALCmd)
+
+	debianOVALCmd.Flags().StringSlice("releases", []string{"bullseye", "bookworm"}, "Releases of Debian to cross-check (e.g. 12, bookworm)")
+	_ = viper.BindPFlag("debian-oval-releases", debianOVALCmd.Flags().Lookup("releases"))
+}
+
+func executeDebianOVAL(cmd *cobra.Command, args []string) (err error) {
+	codenames := []string{}
+	for _, release := range viper.GetStringSlice("debian-oval-releases") {
+		codename := db.DebianCodename(release)
+		if codename == "" {
+			return xerrors.Errorf("Unknown release of Debian: %s", release)
+		}
+		codenames = append(codenames, codename)
+	}
+
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before analyzing", "err", err)
+		}
+		return err
+	}
+
+	ovals, err := fetcher.RetrieveDebianOVALs(codenames)
+	if err != nil {
+		log15.Error("Failed to fetch Debian OVAL.", "err", err)
+		return err
+	}
+
+	report, err := db.CrossCheckDebianOVAL(driver, ovals)
+	if err != nil {
+		log15.Error("Failed to cross-check Debian OVAL.", "err", err)
+		return err
+	}
+
+	records := [][]string{{"release", "cve_id", "package_name", "kind", "tracker_status", "tracker_fixed_version", "oval_fixed_version"}}
+	for _, d := range report.Disagreements {
+		records = append(records, []string{d.Release, d.CveID, d.PackageName, d.Kind, d.TrackerStatus, d.TrackerFixedVersion, d.OVALFixedVersion})
+	}
+	return writeAnalytics(encoder.Report{Value: report, Records: records})
+}
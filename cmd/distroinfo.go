@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// distroInfoCmd represents the distro-info command
+var distroInfoCmd = &cobra.Command{
+	Use:   "distro-info",
+	Short: "Fetch the releases of Debian and Ubuntu from distro-info-data",
+	Long:  `Fetch the versions and the codenames of Debian and Ubuntu from distro-info-data (https://salsa.debian.org/debian/distro-info-data), so that a new release works without updating gost`,
+	RunE:  fetchDistroInfo,
+}
+
+func init() {
+	fetchCmd.AddCommand(distroInfoCmd)
+
+	distroInfoCmd.PersistentFlags().String("url", fetcher.DistroInfoURL, "URL of the directory with debian.csv and ubuntu.csv")
+	_ = viper.BindPFlag("distro-info-url", distroInfoCmd.PersistentFlags().Lookup("url"))
+}
+
+func fetchDistroInfo(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	rows, err := fetcher.RetrieveDistroInfo(viper.GetString("distro-info-url"))
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "releases", len(rows))
+
+	log15.Info("Insert the releases of distro-info-data into DB", "db", driver.Name())
+	if err := driver.InsertDistroReleases(rows); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
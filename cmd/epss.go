@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// epssCmd represents the epss command
+var epssCmd = &cobra.Command{
+	Use:   "epss",
+	Short: "Fetch the EPSS scores of all CVEs from FIRST",
+	Long:  `Fetch the EPSS scores of all CVEs from FIRST`,
+	RunE:  fetchEpss,
+}
+
+func init() {
+	fetchCmd.AddCommand(epssCmd)
+}
+
+func fetchEpss(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch EPSS scores")
+	epss, err := fetcher.RetrieveEpss()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "scores", len(epss.Scores), "model_version", epss.ModelVersion, "score_date", epss.ScoreDate)
+
+	log15.Info("Insert EPSS scores into DB", "db", driver.Name())
+	if err := driver.InsertEpss(epss); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// exploitCmd represents the exploit command
+var exploitCmd = &cobra.Command{
+	Use:   "exploit",
+	Short: "Fetch the public exploits from Exploit-DB and Metasploit",
+	Long:  `Fetch the public exploits from Exploit-DB and the module metadata of Metasploit Framework`,
+	RunE:  fetchExploit,
+}
+
+func init() {
+	fetchCmd.AddCommand(exploitCmd)
+}
+
+func fetchExploit(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch Exploit-DB")
+	exploitDB, err := fetcher.RetrieveExploitDB()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetch Metasploit modules")
+	metasploit, err := fetcher.RetrieveMetasploit()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "Exploit-DB", len(exploitDB), "Metasploit", len(metasploit))
+
+	log15.Info("Insert exploits into DB", "db", driver.Name())
+	if err := driver.InsertExploit(exploitDB, metasploit); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
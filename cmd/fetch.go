@@ -1,15 +1,28 @@
 package cmd
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
 )
 
 // fetchCmd represents the fetch command
 var fetchCmd = &cobra.Command{
-	Use:   "fetch",
-	Short: "Fetch the data of the security tracker",
-	Long:  `Fetch the data of the security tracker`,
+	Use:                "fetch",
+	Short:              "Fetch the data of the security tracker",
+	Long:               `Fetch the data of the security tracker`,
+	PersistentPreRunE:  preFetch,
+	PersistentPostRunE: postFetch,
 }
 
 func init() {
@@ -26,4 +39,264 @@ func init() {
 
 	fetchCmd.PersistentFlags().Uint("expire", 0, "timeout to set for Redis keys in seconds. If set to 0, the key is persistent.")
 	_ = viper.BindPFlag("expire", fetchCmd.PersistentFlags().Lookup("expire"))
+
+	fetchCmd.PersistentFlags().String("redis-index-layout", "", "Layout of the indexes of the packages in a new Redis DB: zset or set (less memory). The layout of the DB is used if empty")
+	_ = viper.BindPFlag("redis-index-layout", fetchCmd.PersistentFlags().Lookup("redis-index-layout"))
+
+	fetchCmd.PersistentFlags().StringSlice("cve", nil, "Fetch and upsert only the specified CVEs (e.g. --cve CVE-2021-3449,CVE-2021-3450). Supported in redhat, redhatapi, redhat-csaf, debian and ubuntu")
+	_ = viper.BindPFlag("cve", fetchCmd.PersistentFlags().Lookup("cve"))
+
+	fetchCmd.PersistentFlags().String("pkg-list", "", "/path/to/file of package names (one per line). Only the CVEs touching the packages are stored")
+	_ = viper.BindPFlag("pkg-list", fetchCmd.PersistentFlags().Lookup("pkg-list"))
+
+	fetchCmd.PersistentFlags().Int("max-document-size", 0, "Max size in bytes of a JSON document stored into Redis. 0 means no limit. The sizes per source are logged at the end of fetch. NOTE: This Option does not work for dbtype: sqlite3, mysql, postgres.")
+	_ = viper.BindPFlag("max-document-size", fetchCmd.PersistentFlags().Lookup("max-document-size"))
+
+	fetchCmd.PersistentFlags().String("oversized-document", db.OversizedWarn, "Action on the documents exceeding --max-document-size: warn, reject (fail the fetch) or strip (truncate the free-text fields)")
+	_ = viper.BindPFlag("oversized-document", fetchCmd.PersistentFlags().Lookup("oversized-document"))
+
+	fetchCmd.PersistentFlags().Bool("remove-missing", false, "Remove the CVEs of the fetched source no longer present upstream from Redis. The fetch of a part of the CVEs (e.g. --cve, --pkg-list or filter.* of the config file) removes nothing. NOTE: RDB always replaces all the records of the source.")
+	_ = viper.BindPFlag("remove-missing", fetchCmd.PersistentFlags().Lookup("remove-missing"))
+
+	fetchCmd.PersistentFlags().String("tombstones-file", "", "/path/to/report the CVEs removed by --remove-missing are appended to as JSON lines (default: $log-dir/fetch-tombstones.json)")
+	_ = viper.BindPFlag("tombstones-file", fetchCmd.PersistentFlags().Lookup("tombstones-file"))
+
+	fetchCmd.PersistentFlags().String("warnings-file", "", "/path/to/report of malformed upstream entries (default: $log-dir/fetch-warnings.json)")
+	_ = viper.BindPFlag("warnings-file", fetchCmd.PersistentFlags().Lookup("warnings-file"))
+}
+
+// cveIDsSupported is the fetch subcommands which can fetch and upsert only the CVEs specified by --cve
+var cveIDsSupported = map[string]bool{
+	"redhat":      true,
+	"redhatapi":   true,
+	"redhat-csaf": true,
+	"debian":      true,
+	"ubuntu":      true,
+}
+
+// documentSizes records the sizes of the documents stored by the fetch. It is nil except in fetch.
+var documentSizes *db.DocumentSizes
+
+// fetchWarnings collects the malformed upstream entries of the fetch for the report. It is nil in the server.
+var fetchWarnings *util.Warnings
+
+// seenIDs records the CVE-IDs stored by the fetch with --remove-missing into Redis. Otherwise it is nil.
+var seenIDs *db.SeenIDs
+
+func preFetch(cmd *cobra.Command, args []string) error {
+	if err := checkCveIDs(cmd); err != nil {
+		return err
+	}
+	sizes, err := db.NewDocumentSizes(viper.GetInt("max-document-size"), viper.GetString("oversized-document"))
+	if err != nil {
+		return err
+	}
+	documentSizes = sizes
+	fetchWarnings = util.NewWarnings()
+	if viper.GetBool("remove-missing") && viper.GetString("dbtype") == "redis" {
+		seenIDs = db.NewSeenIDs()
+	}
+	if err := checkServerLock(); err != nil {
+		return err
+	}
+	return loadPkgList()
+}
+
+// checkServerLock refuses to fetch into the SQLite3 file a running server is using, unless the server uses WAL.
+// With WAL, the fetch opens the DB in WAL mode too, and the server keeps reading the snapshots until the fetch commits.
+func checkServerLock() error {
+	if viper.GetString("dbtype") != "sqlite3" {
+		return nil
+	}
+	lock, err := db.CheckServerLock(viper.GetString("dbpath"))
+	if err != nil {
+		log15.Error("Failed to fetch into the DB of a running server.", "err", err)
+		return err
+	}
+	if lock != nil {
+		log15.Warn("Switching to WAL mode to fetch into the DB of a running server", "pid", lock.PID, "hostname", lock.Hostname)
+		viper.Set("sqlite-wal", true)
+	}
+	return nil
+}
+
+func checkCveIDs(cmd *cobra.Command) error {
+	if len(viper.GetStringSlice("cve")) > 0 && !cveIDsSupported[cmd.Name()] {
+		return xerrors.Errorf("--cve is not supported in fetch %s", cmd.Name())
+	}
+	return nil
+}
+
+// loadPkgList adds the packages of --pkg-list to the insert filter (filter.packages)
+func loadPkgList() error {
+	path := viper.GetString("pkg-list")
+	if path == "" {
+		return nil
+	}
+	pkgs, err := util.ReadLines(path)
+	if err != nil {
+		return err
+	}
+	if len(pkgs) == 0 {
+		return xerrors.Errorf("No package in --pkg-list. path: %s", path)
+	}
+	viper.Set("filter.packages", append(viper.GetStringSlice("filter.packages"), pkgs...))
+	log15.Info("Only the CVEs touching the packages are stored", "packages", len(pkgs))
+	return nil
+}
+
+func postFetch(cmd *cobra.Command, args []string) error {
+	// image-preset records the sources fetched by itself
+	if cmd.Name() != "image-preset" {
+		if err := recordFetchSource(cmd.Name()); err != nil {
+			return err
+		}
+	}
+	if err := removeMissing(); err != nil {
+		return err
+	}
+	logDocumentSizes()
+	return writeWarnings(cmd, args)
+}
+
+// removeMissing removes the CVEs of the sources fetched which were not stored by the fetch
+func removeMissing() error {
+	if !viper.GetBool("remove-missing") {
+		return nil
+	}
+	if seenIDs == nil {
+		log15.Info("Nothing to remove. The insert replaces all the records of the source", "dbtype", viper.GetString("dbtype"))
+		return nil
+	}
+	driver, _, err := openDB()
+	if err != nil {
+		return err
+	}
+	removedAt := time.Now().UTC()
+	removed, err := driver.RemoveMissing(seenIDs, removedAt)
+	if err != nil {
+		log15.Error("Failed to remove the CVEs no longer present upstream.", "err", err)
+		return err
+	}
+	if len(seenIDs.Fields()) == 0 {
+		log15.Info("Nothing to remove. The fetch stored a part of the CVEs (e.g. --cve or the insert filter)")
+	}
+	for _, field := range seenIDs.Fields() {
+		log15.Info("Removed the CVEs no longer present upstream", "source", field, "fetched", seenIDs.Seen(field), "removed", len(removed[field]))
+		log15.Debug("Removed CVE-IDs", "source", field, "CVE-IDs", removed[field])
+	}
+	return writeTombstones(removed, removedAt)
+}
+
+// writeTombstones appends the CVEs removed by --remove-missing to the report as JSON lines.
+// The tombstones are stored into the DB as well, and served by /tombstones.
+func writeTombstones(removed map[string][]string, removedAt time.Time) error {
+	sources := make([]string, 0, len(removed))
+	for source := range removed {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	tombstones := []models.Tombstone{}
+	for _, source := range sources {
+		for _, cveID := range removed[source] {
+			tombstones = append(tombstones, models.Tombstone{Source: source, CveID: cveID, RemovedAt: removedAt})
+		}
+	}
+	if len(tombstones) == 0 {
+		return nil
+	}
+
+	path := viper.GetString("tombstones-file")
+	if path == "" {
+		path = filepath.Join(viper.GetString("log-dir"), "fetch-tombstones.json")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log15.Error("Failed to open tombstones.", "err", err)
+		return xerrors.Errorf("Failed to open tombstones. path: %s, err: %w", path, err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, t := range tombstones {
+		if err := enc.Encode(t); err != nil {
+			log15.Error("Failed to write tombstones.", "err", err)
+			return xerrors.Errorf("Failed to write tombstones. path: %s, err: %w", path, err)
+		}
+	}
+	log15.Info("Wrote the tombstones of the removed CVEs", "tombstones", len(tombstones), "path", path)
+	return nil
+}
+
+// logDocumentSizes logs the size distribution of the documents stored per source
+func logDocumentSizes() {
+	if documentSizes == nil {
+		return
+	}
+	stats := documentSizes.Stats()
+	sources := make([]string, 0, len(stats))
+	for source := range stats {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		s := stats[source]
+		ctx := []interface{}{"source", source, "documents", s.Documents, "total", s.TotalBytes, "max", s.MaxBytes, "maxID", s.MaxID}
+		for _, b := range db.DocumentSizeBuckets {
+			ctx = append(ctx, b, s.Buckets[b])
+		}
+		if s.Oversized > 0 || s.Stripped > 0 || s.Rejected > 0 {
+			log15.Warn("Document sizes", append(ctx, "oversized", s.Oversized, "stripped", s.Stripped, "rejected", s.Rejected)...)
+			continue
+		}
+		log15.Info("Document sizes", ctx...)
+	}
+}
+
+// sourceLicense returns the license of the source, overridden by source-licenses.<source> in the config file
+func sourceLicense(source string) models.SourceLicense {
+	license := models.SourceLicenses[source]
+	key := "source-licenses." + source
+	if !viper.IsSet(key) {
+		return license
+	}
+	if err := viper.UnmarshalKey(key, &license); err != nil {
+		log15.Warn("Failed to read the license in the config file. Use the default", "source", source, "err", err)
+		return models.SourceLicenses[source]
+	}
+	return license
+}
+
+// recordFetchSource records the time of the successful fetch of the source for GET /stale, and the license of the data.
+// The fetch of the CVEs specified by --cve is not recorded, since the other CVEs are not updated.
+func recordFetchSource(source string) error {
+	if len(viper.GetStringSlice("cve")) > 0 {
+		return nil
+	}
+	driver, _, err := openDB()
+	if err != nil {
+		return err
+	}
+	fetched := models.FetchSource{Source: source, FetchedAt: time.Now(), SourceLicense: sourceLicense(source)}
+	if err := driver.UpsertFetchSource(fetched); err != nil {
+		log15.Error("Failed to record the fetch time.", "source", source, "err", err)
+		return err
+	}
+	return nil
+}
+
+func writeWarnings(cmd *cobra.Command, args []string) error {
+	path := viper.GetString("warnings-file")
+	if path == "" {
+		path = filepath.Join(viper.GetString("log-dir"), "fetch-warnings.json")
+	}
+	n := len(fetchWarnings.List())
+	if n == 0 {
+		return nil
+	}
+	if err := fetchWarnings.Write(path); err != nil {
+		log15.Error("Failed to write warnings.", "err", err)
+		return err
+	}
+	log15.Warn("Some upstream entries are malformed. See the report", "warnings", n, "path", path)
+	return nil
 }
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// ghsaCmd represents the ghsa command
+var ghsaCmd = &cobra.Command{
+	Use:   "ghsa",
+	Short: "Fetch the advisories from GitHub Security Advisories",
+	Long:  `Fetch the advisories from GitHub Security Advisories`,
+	RunE:  fetchGhsa,
+}
+
+func init() {
+	fetchCmd.AddCommand(ghsaCmd)
+
+	ghsaCmd.PersistentFlags().String("github-token", "", "GitHub token for GitHub GraphQL API (default: $GITHUB_TOKEN)")
+	_ = viper.BindPFlag("github-token", ghsaCmd.PersistentFlags().Lookup("github-token"))
+	_ = viper.BindEnv("github-token", "GITHUB_TOKEN")
+
+	ghsaCmd.PersistentFlags().StringSlice("ecosystems", fetcher.GhsaEcosystems, "Ecosystems to fetch")
+	_ = viper.BindPFlag("ghsa-ecosystems", ghsaCmd.PersistentFlags().Lookup("ecosystems"))
+}
+
+func fetchGhsa(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch GitHub Security Advisories")
+	vulns, err := fetcher.RetrieveGhsa(viper.GetString("github-token"), viper.GetStringSlice("ghsa-ecosystems"))
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "vulnerabilities", len(vulns))
+
+	log15.Info("Insert GitHub Security Advisories into DB", "db", driver.Name())
+	if err := driver.InsertGhsa(vulns); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// govulnCmd represents the govuln command
+var govulnCmd = &cobra.Command{
+	Use:   "govuln",
+	Short: "Fetch the vulnerabilities from the Go vulnerability database",
+	Long:  `Fetch the vulnerabilities from the Go vulnerability database (https://vuln.go.dev)`,
+	RunE:  fetchGoVuln,
+}
+
+func init() {
+	fetchCmd.AddCommand(govulnCmd)
+}
+
+func fetchGoVuln(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch Go vulnerability database")
+	vulns, err := fetcher.RetrieveGoVulns(fetchOptions()...)
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "vulnerabilities", len(vulns))
+
+	log15.Info("Insert Go vulnerability database into DB", "db", driver.Name())
+	if err := driver.InsertGoVuln(vulns); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
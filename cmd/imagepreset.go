@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// imagePresetCmd represents the image-preset command
+var imagePresetCmd = &cobra.Command{
+	Use:   "image-preset",
+	Short: "Fetch only the sources and the releases of the container base images",
+	Long:  `Fetch only the sources of the container base images (e.g. debian:12, ubuntu:22.04, alpine:3.19), and store only the statuses of their releases`,
+	RunE:  fetchImagePreset,
+}
+
+func init() {
+	fetchCmd.AddCommand(imagePresetCmd)
+
+	imagePresetCmd.PersistentFlags().StringSlice("images", nil, "Base images (e.g. debian:12,ubuntu:22.04,alpine:3.19,amazonlinux:2023,redhat/ubi9)")
+	_ = viper.BindPFlag("image-preset-images", imagePresetCmd.PersistentFlags().Lookup("images"))
+}
+
+func fetchImagePreset(cmd *cobra.Command, args []string) error {
+	images := viper.GetStringSlice("image-preset-images")
+	if len(images) == 0 {
+		return xerrors.New("--images is required")
+	}
+
+	sources := []string{}
+	presetReleases := map[string][]string{}
+	allReleases := map[string]bool{}
+	for _, image := range images {
+		p, err := db.ResolveImagePreset(image)
+		if err != nil {
+			return err
+		}
+		if _, ok := presetReleases[p.Source]; !ok {
+			sources = append(sources, p.Source)
+		}
+		if p.Release == "" {
+			allReleases[p.Source] = true
+		}
+		presetReleases[p.Source] = appendUniq(presetReleases[p.Source], p.Release)
+	}
+
+	// The releases of the other sources in the config file are left as they are
+	releases := viper.GetStringMapStringSlice("filter.releases")
+	for source, rels := range presetReleases {
+		if allReleases[source] {
+			delete(releases, source)
+			continue
+		}
+		releases[source] = rels
+	}
+	viper.Set("filter.releases", releases)
+
+	for _, source := range sources {
+		c, _, err := fetchCmd.Find([]string{source})
+		if err != nil {
+			return xerrors.Errorf("Failed to find fetch %s. err: %w", source, err)
+		}
+		log15.Info("Fetch for the images", "source", source, "releases", releases[source])
+		if err := c.RunE(c, args); err != nil {
+			return err
+		}
+		if err := recordFetchSource(source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendUniq(ss []string, s string) []string {
+	if s == "" {
+		return ss
+	}
+	for _, e := range ss {
+		if e == s {
+			return ss
+		}
+	}
+	return append(ss, s)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// jvnCmd represents the jvn command
+var jvnCmd = &cobra.Command{
+	Use:   "jvn",
+	Short: "Fetch the advisories from JVN iPedia",
+	Long:  `Fetch the advisories from the feeds of JVN iPedia (https://jvndb.jvn.jp)`,
+	RunE:  fetchJvn,
+}
+
+func init() {
+	fetchCmd.AddCommand(jvnCmd)
+}
+
+func fetchJvn(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch JVN iPedia")
+	items, err := fetcher.RetrieveJvn(fetchOptions()...)
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "advisories", len(items))
+
+	log15.Info("Insert JVN iPedia into DB", "db", driver.Name())
+	if err := driver.InsertJvn(items); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
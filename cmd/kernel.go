@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// kernelCmd represents the kernel command
+var kernelCmd = &cobra.Command{
+	Use:   "kernel",
+	Short: "Fetch the CVEs of the Linux kernel from kernel.org",
+	Long:  `Fetch the CVE records of the Linux kernel CNA (https://git.kernel.org/pub/scm/linux/security/vulns.git) with the affected and fixed commits and versions`,
+	RunE:  fetchKernel,
+}
+
+func init() {
+	fetchCmd.AddCommand(kernelCmd)
+
+	kernelCmd.PersistentFlags().String("archive", fetcher.KernelVulnsArchiveURL, "Path or URL of the tarball of vulns.git")
+	_ = viper.BindPFlag("kernel-archive", kernelCmd.PersistentFlags().Lookup("archive"))
+}
+
+func fetchKernel(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch the CVE records of kernel.org")
+	records, err := fetcher.RetrieveKernelCves(viper.GetString("kernel-archive"))
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "records", len(records))
+
+	log15.Info("Insert kernel CVEs into DB", "db", driver.Name())
+	if err := driver.InsertKernelCves(records); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
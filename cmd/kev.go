@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// kevCmd represents the kev command
+var kevCmd = &cobra.Command{
+	Use:   "kev",
+	Short: "Fetch the CVEs in CISA Known Exploited Vulnerabilities catalog",
+	Long:  `Fetch the CVEs in CISA Known Exploited Vulnerabilities catalog`,
+	RunE:  fetchKEV,
+}
+
+func init() {
+	fetchCmd.AddCommand(kevCmd)
+}
+
+func fetchKEV(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch CISA KEV catalog")
+	cves, err := fetcher.RetrieveKEV()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "cves", len(cves))
+
+	log15.Info("Insert CISA KEV into DB", "db", driver.Name())
+	if err := driver.InsertKEV(cves); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"errors"
-
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/db"
 	"github.com/knqyf263/gost/fetcher"
@@ -24,12 +22,16 @@ func init() {
 	fetchCmd.AddCommand(microsoftCmd)
 
 	microsoftCmd.PersistentFlags().String("apikey", "", "microsoft apikey")
+	_ = microsoftCmd.PersistentFlags().MarkDeprecated("apikey", "MSRC CVRF API no longer requires an API key")
 	_ = viper.BindPFlag("apikey", microsoftCmd.PersistentFlags().Lookup("apikey"))
+
+	microsoftCmd.PersistentFlags().Bool("since-last", false, "fetch only the CVRF documents newer than the last month stored in DB, and keep the other CVEs")
+	_ = viper.BindPFlag("microsoft-since-last", microsoftCmd.PersistentFlags().Lookup("since-last"))
 }
 
 func fetchMicrosoft(cmd *cobra.Command, args []string) (err error) {
 	log15.Info("Initialize Database")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	driver, locked, err := openDB()
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
@@ -47,13 +49,23 @@ func fetchMicrosoft(cmd *cobra.Command, args []string) (err error) {
 		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
 	}
 
-	log15.Info("Fetched all CVEs from Microsoft")
-	apiKey := viper.GetString("apikey")
-	if len(apiKey) == 0 {
-		return errors.New("apikey is required")
+	if viper.GetBool("microsoft-since-last") {
+		if err := upsertMicrosoftSinceLast(driver); err != nil {
+			return err
+		}
+		if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+			log15.Error("Failed to upsert FetchMeta to DB.", "dbpath", viper.GetString("dbpath"), "err", err)
+			return err
+		}
+		return nil
 	}
-	cves, err := fetcher.RetrieveMicrosoftCveDetails(apiKey)
-	if err != nil {
+
+	log15.Info("Fetched all CVEs from Microsoft")
+	cves := []models.MicrosoftXML{}
+	if err := fetcher.RetrieveMicrosoftCveDetails("", func(cve models.MicrosoftXML) error {
+		cves = append(cves, cve)
+		return nil
+	}); err != nil {
 		return err
 	}
 
@@ -76,3 +88,28 @@ func fetchMicrosoft(cmd *cobra.Command, args []string) (err error) {
 
 	return nil
 }
+
+// upsertMicrosoftSinceLast upserts the CVRF documents newer than the last month in DB one by one,
+// so that an interrupted fetch resumes from the last document inserted.
+func upsertMicrosoftSinceLast(driver db.DB) error {
+	cvrfIDs, err := driver.GetMicrosoftCvrfIDs()
+	if err != nil {
+		log15.Error("Failed to get CVRF documents from DB.", "err", err)
+		return err
+	}
+	last := fetcher.LatestMicrosoftCvrfID(cvrfIDs)
+	if last == "" {
+		log15.Info("No CVRF document in DB. Fetch all CVRF documents")
+	} else {
+		log15.Info("Fetch the CVRF documents newer than the last month in DB", "last", last)
+	}
+
+	return fetcher.RetrieveMicrosoftCveDetails(last, func(cve models.MicrosoftXML) error {
+		log15.Info("Upsert Microsoft CVEs into DB", "db", driver.Name())
+		if err := driver.UpsertMicrosoft([]models.MicrosoftXML{cve}); err != nil {
+			log15.Error("Failed to upsert.", "dbpath", viper.GetString("dbpath"), "err", err)
+			return err
+		}
+		return nil
+	})
+}
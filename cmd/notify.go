@@ -52,7 +52,7 @@ func notifyRedhat(conf config.Config) error {
 	}
 
 	log15.Info(fmt.Sprintf("Fetched %d CVEs", len(watchCveURL)))
-	cveJSONs, err := fetcher.RetrieveRedhatCveDetails(watchCveURL)
+	cveJSONs, err := fetcher.RetrieveRedhatCveDetails(watchCveURL, fetchOptions()...)
 	if err != nil {
 		return err
 	}
@@ -64,7 +64,7 @@ func notifyRedhat(conf config.Config) error {
 	}
 
 	log15.Info("Initialize Database")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	driver, locked, err := openDB()
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// nvdCmd represents the nvd command
+var nvdCmd = &cobra.Command{
+	Use:   "nvd",
+	Short: "Fetch the CVE information from NVD CVE API 2.0",
+	Long:  `Fetch the CVE information from NVD CVE API 2.0`,
+	RunE:  fetchNvd,
+}
+
+func init() {
+	fetchCmd.AddCommand(nvdCmd)
+
+	// microsoft binds "apikey", so the key of viper is different
+	nvdCmd.PersistentFlags().String("apikey", "", "NVD API key (https://nvd.nist.gov/developers/request-an-api-key)")
+	_ = viper.BindPFlag("nvd-apikey", nvdCmd.PersistentFlags().Lookup("apikey"))
+}
+
+func fetchNvd(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	apikey := viper.GetString("nvd-apikey")
+	if apikey == "" {
+		log15.Warn("Fetching NVD without API key is slow due to the rate limit. Set --apikey")
+	}
+	log15.Info("Fetch all CVEs from NVD CVE API 2.0")
+	cves, err := fetcher.RetrieveNvdCVEs(apikey)
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "cves", len(cves))
+
+	log15.Info("Insert NVD CVEs into DB", "db", driver.Name())
+	if err := driver.InsertNvd(cves); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
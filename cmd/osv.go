@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// osvCmd represents the osv command
+var osvCmd = &cobra.Command{
+	Use:   "osv",
+	Short: "Fetch the vulnerabilities from OSV",
+	Long:  `Fetch the vulnerabilities from the OSV dumps of the ecosystems, or import a dump downloaded from osv.dev`,
+	RunE:  fetchOsv,
+}
+
+func init() {
+	fetchCmd.AddCommand(osvCmd)
+
+	osvCmd.PersistentFlags().StringSlice("ecosystems", fetcher.OsvEcosystems, "Ecosystems to fetch")
+	_ = viper.BindPFlag("osv-ecosystems", osvCmd.PersistentFlags().Lookup("ecosystems"))
+
+	osvCmd.PersistentFlags().String("zip", "", "Path to the OSV dump (all.zip) to import instead of downloading")
+	_ = viper.BindPFlag("osv-zip", osvCmd.PersistentFlags().Lookup("zip"))
+}
+
+func fetchOsv(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	var vulns []models.OsvJSON
+	if path := viper.GetString("osv-zip"); path != "" {
+		log15.Info("Read OSV dump", "path", path)
+		vulns, err = fetcher.ReadOsvZip(path)
+	} else {
+		log15.Info("Fetch OSV")
+		vulns, err = fetcher.RetrieveOsv(viper.GetStringSlice("osv-ecosystems"))
+	}
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "vulnerabilities", len(vulns))
+
+	log15.Info("Insert OSV into DB", "db", driver.Name())
+	if err := driver.InsertOsv(vulns); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
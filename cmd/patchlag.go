@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/encoder"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// patchLagCmd represents the patch-lag command
+var patchLagCmd = &cobra.Command{
+	Use:   "patch-lag",
+	Short: "Compute the distribution of days from the publication to the fix",
+	Long:  `Compute the distribution of days from the publication to the fix in a release of a distro`,
+	RunE:  executePatchLag,
+}
+
+func init() {
+	analyticsCmd.AddCommand(patchLagCmd)
+
+	patchLagCmd.Flags().String("distro", "", fmt.Sprintf("Distro to analyze (%s)", strings.Join(db.PatchLagDistros(), ", ")))
+	_ = viper.BindPFlag("distro", patchLagCmd.Flags().Lookup("distro"))
+
+	patchLagCmd.Flags().String("release", "", "Release of the distro (e.g. 22.04, bookworm, 8)")
+	_ = viper.BindPFlag("release", patchLagCmd.Flags().Lookup("release"))
+}
+
+func executePatchLag(cmd *cobra.Command, args []string) (err error) {
+	distro, release := viper.GetString("distro"), viper.GetString("release")
+	if distro == "" || release == "" {
+		return xerrors.New("--distro and --release are required")
+	}
+
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before analyzing", "err", err)
+		}
+		return err
+	}
+
+	report, err := db.GetPatchLag(driver, distro, release)
+	if err != nil {
+		log15.Error("Failed to compute patch lag.", "err", err)
+		return err
+	}
+
+	records := [][]string{{"cve_id", "advisory", "public_date", "fixed_date", "days_to_fix"}}
+	for _, r := range report.Cves {
+		records = append(records, []string{
+			r.CveID,
+			r.Advisory,
+			r.PublicDate.Format(time.RFC3339),
+			r.FixedDate.Format(time.RFC3339),
+			strconv.Itoa(r.DaysToFix),
+		})
+	}
+	return writeAnalytics(encoder.Report{Value: report, Records: records})
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// photonCmd represents the photon command
+var photonCmd = &cobra.Command{
+	Use:   "photon",
+	Short: "Fetch the CVE information from Photon OS CVE metadata",
+	Long:  `Fetch the CVE information from Photon OS CVE metadata`,
+	RunE:  fetchPhoton,
+}
+
+func init() {
+	fetchCmd.AddCommand(photonCmd)
+}
+
+func fetchPhoton(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch all Photon OS CVE metadata")
+	cves, err := fetcher.RetrievePhotonCVEs()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "cves", len(cves))
+
+	log15.Info("Insert Photon OS CVEs into DB", "db", driver.Name())
+	if err := driver.InsertPhoton(cves); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// ciscoCmd represents the cisco command
+var ciscoCmd = &cobra.Command{
+	Use:   "cisco",
+	Short: "Fetch the advisories from Cisco PSIRT",
+	Long:  `Fetch the advisories from Cisco openVuln API (https://developer.cisco.com/psirt/)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchPsirt(models.PsirtCisco, func() ([]models.PsirtAdvisory, error) {
+			advs, err := fetcher.RetrieveCiscoPsirt(viper.GetString("cisco-client-id"), viper.GetString("cisco-client-secret"))
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertCiscoPsirt(advs, fetchWarnings), nil
+		})
+	},
+}
+
+// vmwareCmd represents the vmware command
+var vmwareCmd = &cobra.Command{
+	Use:   "vmware",
+	Short: "Fetch the advisories from VMware Security Advisories",
+	Long:  `Fetch the VMware Security Advisories (VMSA) in CSAF 2.0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchPsirt(models.PsirtVMware, func() ([]models.PsirtAdvisory, error) {
+			docs, err := fetcher.RetrieveVMwarePsirt(viper.GetString("vmware-csaf-url"), fetchOptions()...)
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertVMwarePsirt(docs, fetchWarnings), nil
+		})
+	},
+}
+
+// fortinetCmd represents the fortinet command
+var fortinetCmd = &cobra.Command{
+	Use:   "fortinet",
+	Short: "Fetch the advisories from Fortinet PSIRT",
+	Long:  `Fetch the advisories from Fortinet PSIRT (https://www.fortiguard.com/psirt)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fetchPsirt(models.PsirtFortinet, func() ([]models.PsirtAdvisory, error) {
+			docs, err := fetcher.RetrieveFortinetPsirt(fetchOptions()...)
+			if err != nil {
+				return nil, err
+			}
+			return db.ConvertFortinetPsirt(docs, fetchWarnings), nil
+		})
+	},
+}
+
+func init() {
+	fetchCmd.AddCommand(ciscoCmd)
+	fetchCmd.AddCommand(vmwareCmd)
+	fetchCmd.AddCommand(fortinetCmd)
+
+	ciscoCmd.PersistentFlags().String("client-id", "", "Client ID of Cisco API Console (default: $CISCO_CLIENT_ID)")
+	_ = viper.BindPFlag("cisco-client-id", ciscoCmd.PersistentFlags().Lookup("client-id"))
+	_ = viper.BindEnv("cisco-client-id", "CISCO_CLIENT_ID")
+
+	ciscoCmd.PersistentFlags().String("client-secret", "", "Client secret of Cisco API Console (default: $CISCO_CLIENT_SECRET)")
+	_ = viper.BindPFlag("cisco-client-secret", ciscoCmd.PersistentFlags().Lookup("client-secret"))
+	_ = viper.BindEnv("cisco-client-secret", "CISCO_CLIENT_SECRET")
+
+	vmwareCmd.PersistentFlags().String("csaf-url", "", "URL of the CSAF directory with index.txt, or of a CSAF document of VMSA")
+	_ = viper.BindPFlag("vmware-csaf-url", vmwareCmd.PersistentFlags().Lookup("csaf-url"))
+}
+
+// fetchPsirt replaces the advisories of the vendor by the ones retrieved
+func fetchPsirt(vendor string, retrieve func() ([]models.PsirtAdvisory, error)) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch PSIRT advisories", "vendor", vendor)
+	advisories, err := retrieve()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "advisories", len(advisories))
+
+	log15.Info("Insert PSIRT advisories into DB", "vendor", vendor, "db", driver.Name())
+	if err := driver.InsertPsirt(vendor, advisories); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"github.com/inconshreveable/log15"
-	"github.com/knqyf263/gost/db"
 	"github.com/knqyf263/gost/fetcher"
 	"github.com/knqyf263/gost/models"
 	"github.com/spf13/cobra"
@@ -23,13 +22,14 @@ func init() {
 }
 
 func fetchRedHat(cmd *cobra.Command, args []string) (err error) {
-	cves, err := fetcher.FetchRedHatVulnList()
+	cveIDs := viper.GetStringSlice("cve")
+	cves, err := fetcher.FetchRedHatVulnList(cveIDs)
 	if err != nil {
 		return xerrors.Errorf("error in vulnerability DB initialize: %w", err)
 	}
 
 	log15.Info("Initialize Database")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	driver, locked, err := openDB()
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
@@ -47,8 +47,14 @@ func fetchRedHat(cmd *cobra.Command, args []string) (err error) {
 		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
 	}
 
-	log15.Info("Insert RedHat into DB", "db", driver.Name())
-	if err := driver.InsertRedhat(cves); err != nil {
+	if len(cveIDs) > 0 {
+		log15.Info("Upsert RedHat into DB", "db", driver.Name(), "CVEs", len(cves))
+		err = driver.UpsertRedhat(cves)
+	} else {
+		log15.Info("Insert RedHat into DB", "db", driver.Name())
+		err = driver.InsertRedhat(cves)
+	}
+	if err != nil {
 		log15.Error("Failed to insert.", "dbpath", viper.GetString("dbpath"), "err", err)
 		return err
 	}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/inconshreveable/log15"
-	"github.com/knqyf263/gost/db"
 	"github.com/knqyf263/gost/fetcher"
 	"github.com/knqyf263/gost/models"
 	"github.com/spf13/cobra"
@@ -35,7 +34,7 @@ func init() {
 
 func fetchRedHatAPI(cmd *cobra.Command, args []string) (err error) {
 	log15.Info("Initialize Database")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	driver, locked, err := openDB()
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
@@ -53,12 +52,20 @@ func fetchRedHatAPI(cmd *cobra.Command, args []string) (err error) {
 		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
 	}
 
-	log15.Info("Fetch the list of CVEs")
-	entries, err := fetcher.ListAllRedhatCves(
-		viper.GetString("before"), viper.GetString("after"), viper.GetInt("threads"))
-	if err != nil {
-		log15.Error("Failed to fetch the list of CVEs.", "err", err)
-		return err
+	cveIDs := viper.GetStringSlice("cve")
+	var entries []models.RedhatEntry
+	if len(cveIDs) > 0 {
+		for _, cveID := range cveIDs {
+			entries = append(entries, models.RedhatEntry{CveID: cveID, ResourceURL: fetcher.GetRedhatCveDetailURL(cveID)})
+		}
+	} else {
+		log15.Info("Fetch the list of CVEs")
+		entries, err = fetcher.ListAllRedhatCves(
+			viper.GetString("before"), viper.GetString("after"), viper.GetInt("threads"))
+		if err != nil {
+			log15.Error("Failed to fetch the list of CVEs.", "err", err)
+			return err
+		}
 	}
 	var resourceURLs []string
 	for _, entry := range entries {
@@ -73,14 +80,20 @@ func fetchRedHatAPI(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	log15.Info(fmt.Sprintf("Fetched %d CVEs", len(entries)))
-	cves, err := fetcher.RetrieveRedhatCveDetails(resourceURLs)
+	cves, err := fetcher.RetrieveRedhatCveDetails(resourceURLs, fetchOptions()...)
 	if err != nil {
 		log15.Error("Failed to fetch the CVE details.", "err", err)
 		return err
 	}
 
-	log15.Info("Insert RedHat into DB", "db", driver.Name())
-	if err := driver.InsertRedhat(cves); err != nil {
+	if len(cveIDs) > 0 {
+		log15.Info("Upsert RedHat into DB", "db", driver.Name())
+		err = driver.UpsertRedhat(cves)
+	} else {
+		log15.Info("Insert RedHat into DB", "db", driver.Name())
+		err = driver.InsertRedhat(cves)
+	}
+	if err != nil {
 		log15.Error("Failed to insert.", "dbpath", viper.GetString("dbpath"), "err", err)
 		return err
 	}
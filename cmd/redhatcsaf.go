@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// redHatCSAFCmd represents the redhatCSAF command
+var redHatCSAFCmd = &cobra.Command{
+	Use:   "redhat-csaf",
+	Short: "Fetch the CVE information from Red Hat CSAF/VEX",
+	Long:  `Fetch the CVE information from the tarball of Red Hat CSAF/VEX files`,
+	RunE:  fetchRedHatCSAF,
+}
+
+func init() {
+	fetchCmd.AddCommand(redHatCSAFCmd)
+
+	redHatCSAFCmd.PersistentFlags().String("csaf-url", fetcher.RedhatCSAFURL, "URL of the tarball of VEX files, or the directory having archive_latest.txt")
+	_ = viper.BindPFlag("redhat-csaf-url", redHatCSAFCmd.PersistentFlags().Lookup("csaf-url"))
+
+	redHatCSAFCmd.PersistentFlags().String("csaf-path", "", "Path of the tarball of VEX files downloaded in advance (.tar, .tar.gz, .tar.bz2 or .tar.zst)")
+	_ = viper.BindPFlag("redhat-csaf-path", redHatCSAFCmd.PersistentFlags().Lookup("csaf-path"))
+}
+
+func fetchRedHatCSAF(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	cveIDs := viper.GetStringSlice("cve")
+	log15.Info("Fetch the CVE information from Red Hat CSAF/VEX")
+	cves, err := fetcher.RetrieveRedhatCSAF(viper.GetString("redhat-csaf-url"), viper.GetString("redhat-csaf-path"), cveIDs)
+	if err != nil {
+		log15.Error("Failed to fetch Red Hat CSAF/VEX.", "err", err)
+		return err
+	}
+	log15.Info(fmt.Sprintf("Fetched %d CVEs", len(cves)))
+
+	if len(cveIDs) > 0 {
+		log15.Info("Upsert RedHat into DB", "db", driver.Name())
+		err = driver.UpsertRedhat(cves)
+	} else {
+		log15.Info("Insert RedHat into DB", "db", driver.Name())
+		err = driver.InsertRedhat(cves)
+	}
+	if err != nil {
+		log15.Error("Failed to insert.", "dbpath", viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "dbpath", viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	return nil
+}
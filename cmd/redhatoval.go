@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// redHatOVALCmd represents the redhat-oval command
+var redHatOVALCmd = &cobra.Command{
+	Use:   "redhat-oval",
+	Short: "Fetch the fix states of the AppStream module streams from Red Hat OVAL v2",
+	Long:  `Fetch the fix states of the AppStream module streams (e.g. nodejs:14) from Red Hat OVAL v2, which the unfixed CVEs of the packages of the streams are corrected by`,
+	RunE:  fetchRedHatOVAL,
+}
+
+func init() {
+	fetchCmd.AddCommand(redHatOVALCmd)
+
+	redHatOVALCmd.PersistentFlags().StringSlice("releases", []string{"8", "9"}, "Major releases of RHEL to fetch (e.g. 8, 9)")
+	_ = viper.BindPFlag("redhat-oval-releases", redHatOVALCmd.PersistentFlags().Lookup("releases"))
+}
+
+func fetchRedHatOVAL(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetch Red Hat OVAL v2")
+	ovals, err := fetcher.RetrieveRedhatOVALs(viper.GetStringSlice("redhat-oval-releases"))
+	if err != nil {
+		log15.Error("Failed to fetch Red Hat OVAL.", "err", err)
+		return err
+	}
+
+	log15.Info("Insert Red Hat OVAL into DB", "db", driver.Name())
+	if err := driver.InsertRedhatOVAL(ovals); err != nil {
+		log15.Error("Failed to insert.", "dbpath", viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
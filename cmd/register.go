@@ -13,8 +13,8 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/config"
-	"github.com/knqyf263/gost/db"
 	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
 	runewidth "github.com/mattn/go-runewidth"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -47,11 +47,11 @@ func executeRegister(cmd *cobra.Command, args []string) (err error) {
 	afterOption := viper.GetString("select-after")
 	var after time.Time
 	if afterOption != "" {
-		if after, err = time.Parse("2006-01-02", afterOption); err != nil {
+		if after, err = util.ParseDate(afterOption, "2006-01-02"); err != nil {
 			return fmt.Errorf("Failed to parse --select-after. err: %s", err)
 		}
 	} else {
-		now := time.Now()
+		now := time.Now().UTC()
 		after = now.Add(time.Duration(-1) * 24 * 30 * time.Hour)
 	}
 
@@ -68,7 +68,7 @@ func executeRegister(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	log15.Info("Initialize Database")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	driver, locked, err := openDB()
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
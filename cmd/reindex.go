@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/spf13/cobra"
+)
+
+// reindexCmd represents the reindex command
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the indexes of Redis from the CVEs stored",
+	Long:  `Rebuild the indexes of Redis (e.g. CVE#D#$PKGNAME, CVE#B#$BZID) from CVE#$CVEID, for repairing the DB where the indexes were evicted or lost but the CVEs survived. Only --dbtype redis is supported`,
+	RunE:  executeReindex,
+}
+
+func init() {
+	RootCmd.AddCommand(reindexCmd)
+}
+
+func executeReindex(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before reindexing", "err", err)
+		}
+		return err
+	}
+
+	log15.Info("Rebuild the indexes", "db", driver.Name())
+	report, err := driver.Reindex()
+	if err != nil {
+		log15.Error("Failed to reindex.", "err", err)
+		return err
+	}
+	for source, n := range report.Sources {
+		log15.Info("Reindexed", "source", source, "documents", n)
+	}
+	log15.Info("Finished reindexing", "CVEs", report.CVEs, "keys", report.Keys, "members", report.Members, "failed", report.Failed)
+	return nil
+}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/fetcher"
 	"github.com/knqyf263/gost/util"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
 )
 
 var cfgFile string
@@ -47,6 +52,9 @@ func init() {
 	RootCmd.PersistentFlags().String("dbtype", "sqlite3", "Database type to store data in (sqlite3, mysql, postgres or redis supported)")
 	_ = viper.BindPFlag("dbtype", RootCmd.PersistentFlags().Lookup("dbtype"))
 
+	RootCmd.PersistentFlags().Bool("require-persistence", false, "Fail to open Redis configured as a volatile cache (no AOF nor RDB snapshots, or evicting keys of gost) instead of warning")
+	_ = viper.BindPFlag("require-persistence", RootCmd.PersistentFlags().Lookup("require-persistence"))
+
 	RootCmd.PersistentFlags().String("http-proxy", "", "http://proxy-url:port (default: empty)")
 	_ = viper.BindPFlag("http-proxy", RootCmd.PersistentFlags().Lookup("http-proxy"))
 }
@@ -78,4 +86,100 @@ func initConfig() {
 	debug := viper.GetBool("debug")
 	logJSON := viper.GetBool("log-json")
 	util.SetLogger(logDir, debug, logJSON)
+	util.SetHTTPProxy(viper.GetString("http-proxy"))
+
+	mirrors := []util.Mirror{}
+	if err := viper.UnmarshalKey("mirrors", &mirrors); err != nil {
+		log15.Error("Failed to read mirrors in the config file", "err", err)
+	} else if err := util.SetMirrors(mirrors); err != nil {
+		log15.Error("Invalid mirrors in the config file", "err", err)
+	}
+}
+
+// dbOptions returns the options of db.NewDB from the flags and the config file
+func dbOptions() []db.Option {
+	return []db.Option{
+		db.WithBatchSize(viper.GetInt("batch-size")),
+		db.WithExpire(viper.GetUint("expire")),
+		db.WithMultiGetChunk(viper.GetInt("multi-get-chunk-size"), viper.GetInt("multi-get-concurrency")),
+		db.WithQueryTimeout(viper.GetDuration("query-timeout")),
+		db.WithSlowQueryThreshold(viper.GetDuration("slow-query-threshold")),
+		db.WithRequirePersistence(viper.GetBool("require-persistence")),
+		db.WithIndexLayout(viper.GetString("redis-index-layout")),
+		db.WithDocumentSizes(documentSizes),
+		db.WithSeenIDs(seenIDs),
+		db.WithWarnings(fetchWarnings),
+		db.WithSQLiteWAL(viper.GetBool("sqlite-wal")),
+		db.WithFilter(db.Filter{
+			MinSeverity: viper.GetString("filter.min-severity"),
+			MinCveYear:  viper.GetInt("filter.min-cve-year"),
+			Packages:    viper.GetStringSlice("filter.packages"),
+			Releases:    viper.GetStringMapStringSlice("filter.releases"),
+		}),
+	}
+}
+
+// openedDB is the drivers shared by openDB and openDBWith, keyed by openedDBKey
+var openedDB = struct {
+	mu      sync.Mutex
+	drivers map[string]*sharedDB
+}{drivers: map[string]*sharedDB{}}
+
+// sharedDB is a driver handed out by openDBWith
+type sharedDB struct {
+	driver db.DB
+	// checkedAt is the last time the DB responded to the driver
+	checkedAt time.Time
+}
+
+// openedDBHealthTTL is how long the shared driver is trusted without querying the DB again
+const openedDBHealthTTL = 10 * time.Second
+
+// openedDBKey is the key of the shared driver of the DB at dbPath of dbType
+func openedDBKey(dbType, dbPath string) string {
+	return dbType + "\x00" + dbPath
+}
+
+// openDB opens the DB of --dbtype and --dbpath with dbOptions by openDBWith.
+// The commands open it after validating the arguments, and the hooks (e.g. postFetch) share the connection pool of the command
+// instead of connecting and checking the schema again.
+func openDB() (db.DB, bool, error) {
+	return openDBWith(viper.GetString("dbtype"), viper.GetString("dbpath"), dbOptions()...)
+}
+
+// openDBWith opens the DB at dbPath of dbType at the first call, and returns the same driver for the same dbType and dbPath after that.
+// The driver keeps the opts of the first call.
+// If the DB has not responded for openedDBHealthTTL, the driver is checked, and the error is returned if the DB does not respond.
+// The driver is never closed here because the callers may still hold it, so the next call checks it again.
+// A failed open is not kept, so the next call connects again.
+func openDBWith(dbType, dbPath string, opts ...db.Option) (db.DB, bool, error) {
+	openedDB.mu.Lock()
+	defer openedDB.mu.Unlock()
+
+	key := openedDBKey(dbType, dbPath)
+	if shared, ok := openedDB.drivers[key]; ok {
+		if time.Since(shared.checkedAt) < openedDBHealthTTL {
+			return shared.driver, false, nil
+		}
+		if _, err := shared.driver.GetFetchMeta(); err != nil {
+			return nil, false, xerrors.Errorf("Failed to check DB. dbtype: %s, err: %w", dbType, err)
+		}
+		shared.checkedAt = time.Now()
+		return shared.driver, false, nil
+	}
+
+	driver, locked, err := db.NewDB(dbType, dbPath, viper.GetBool("debug-sql"), opts...)
+	if err != nil {
+		if driver != nil {
+			_ = driver.CloseDB()
+		}
+		return nil, locked, err
+	}
+	openedDB.drivers[key] = &sharedDB{driver: driver, checkedAt: time.Now()}
+	return driver, false, nil
+}
+
+// fetchOptions returns the options of the fetchers from the flags
+func fetchOptions() []fetcher.Option {
+	return []fetcher.Option{fetcher.WithConcurrency(viper.GetInt("threads"), viper.GetInt("wait"))}
 }
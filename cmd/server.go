@@ -1,6 +1,12 @@
 package cmd
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/db"
 	"github.com/knqyf263/gost/models"
@@ -26,11 +32,156 @@ func init() {
 
 	serverCmd.PersistentFlags().String("port", "1325", "HTTP server port number")
 	_ = viper.BindPFlag("port", serverCmd.PersistentFlags().Lookup("port"))
+
+	serverCmd.PersistentFlags().Int("multi-get-chunk-size", 1000, "The number of CVE-IDs in a pipeline of the multi-get. NOTE: This Option works only for dbtype: redis.")
+	_ = viper.BindPFlag("multi-get-chunk-size", serverCmd.PersistentFlags().Lookup("multi-get-chunk-size"))
+
+	serverCmd.PersistentFlags().Int("multi-get-concurrency", 4, "The number of pipelines of the multi-get executed concurrently. NOTE: This Option works only for dbtype: redis.")
+	_ = viper.BindPFlag("multi-get-concurrency", serverCmd.PersistentFlags().Lookup("multi-get-concurrency"))
+
+	serverCmd.PersistentFlags().Duration("query-timeout", 30*time.Second, "Timeout of each query to the DB (e.g. 5s). 0 means no timeout")
+	_ = viper.BindPFlag("query-timeout", serverCmd.PersistentFlags().Lookup("query-timeout"))
+
+	serverCmd.PersistentFlags().Duration("slow-query-threshold", 0, "Log the queries to the DB taking this duration or longer with the SQL or the Redis command (e.g. 500ms). 0 disables the log")
+	_ = viper.BindPFlag("slow-query-threshold", serverCmd.PersistentFlags().Lookup("slow-query-threshold"))
+
+	serverCmd.PersistentFlags().Int("circuit-breaker-threshold", 5, "The number of consecutive DB failures opening the circuit breaker. 0 disables the circuit breaker")
+	_ = viper.BindPFlag("circuit-breaker-threshold", serverCmd.PersistentFlags().Lookup("circuit-breaker-threshold"))
+
+	serverCmd.PersistentFlags().Duration("circuit-breaker-cooldown", 10*time.Second, "Duration the circuit breaker stays open before probing the DB")
+	_ = viper.BindPFlag("circuit-breaker-cooldown", serverCmd.PersistentFlags().Lookup("circuit-breaker-cooldown"))
+
+	serverCmd.PersistentFlags().Int("cve-cache-size", 0, "The number of the CVEs of RedHat, Debian, Ubuntu and Microsoft kept in memory by ID. 0 disables the cache")
+	_ = viper.BindPFlag("cve-cache-size", serverCmd.PersistentFlags().Lookup("cve-cache-size"))
+
+	serverCmd.PersistentFlags().Duration("cve-cache-ttl", 10*time.Minute, "Duration a CVE is kept by --cve-cache-size, which must be positive. gost fetch in another process (including KEV and EPSS) clears the cache within a second after it finishes, but the other writes by other processes (e.g. /admin of another server on the same Redis) are served stale until the TTL")
+	_ = viper.BindPFlag("cve-cache-ttl", serverCmd.PersistentFlags().Lookup("cve-cache-ttl"))
+
+	serverCmd.PersistentFlags().Bool("serve-stale", false, "Answer from the last successful responses kept in memory with a Warning header while the circuit breaker is open, instead of 503")
+	_ = viper.BindPFlag("serve-stale", serverCmd.PersistentFlags().Lookup("serve-stale"))
+
+	serverCmd.PersistentFlags().Int("serve-stale-entries", 10000, "The max number of the responses kept in memory for --serve-stale")
+	_ = viper.BindPFlag("serve-stale-entries", serverCmd.PersistentFlags().Lookup("serve-stale-entries"))
+
+	serverCmd.PersistentFlags().Bool("osv-passthrough", false, "Query api.osv.dev when no OSV vulnerability of the package is found in the DB")
+	_ = viper.BindPFlag("osv-passthrough", serverCmd.PersistentFlags().Lookup("osv-passthrough"))
+
+	serverCmd.PersistentFlags().String("self-test", "strict", "Self-test querying a known CVE from each source on start. strict: refuse to start on failure, ready: start but /ready responds 503, off: skip")
+	_ = viper.BindPFlag("self-test", serverCmd.PersistentFlags().Lookup("self-test"))
+
+	serverCmd.PersistentFlags().String("access-log", "", "/path/to/access log in JSON lines (default: $log-dir/access.log). - writes to stdout")
+	_ = viper.BindPFlag("access-log", serverCmd.PersistentFlags().Lookup("access-log"))
+
+	serverCmd.PersistentFlags().StringSlice("access-log-fields", nil, fmt.Sprintf("The fields of the access log (default: all of %s)", strings.Join(server.AccessLogFields, ",")))
+	_ = viper.BindPFlag("access-log-fields", serverCmd.PersistentFlags().Lookup("access-log-fields"))
+
+	serverCmd.PersistentFlags().Duration("ready-max-age", 0, "/readyz responds 503 if a source was fetched longer ago than this (e.g. 48h). 0 checks only that the sources are fetched")
+	_ = viper.BindPFlag("ready-max-age", serverCmd.PersistentFlags().Lookup("ready-max-age"))
+
+	serverCmd.PersistentFlags().StringSlice("ready-sources", nil, "The sources checked by /readyz, which must have been fetched (default: all sources fetched)")
+	_ = viper.BindPFlag("ready-sources", serverCmd.PersistentFlags().Lookup("ready-sources"))
+
+	// The tokens of /admin and /debug/vars are not flags, so that they are not shown in the process list.
+	// admin-token and read-token are read from the config file or the environment.
+	_ = viper.BindEnv("admin-token", "GOST_ADMIN_TOKEN")
+	_ = viper.BindEnv("read-token", "GOST_READ_TOKENS")
+
+	serverCmd.PersistentFlags().String("dbpath-sha256", "", "SHA-256 of the SQLite3 DB downloaded when --dbpath is a URL (default: read from the URL + .sha256)")
+	_ = viper.BindPFlag("dbpath-sha256", serverCmd.PersistentFlags().Lookup("dbpath-sha256"))
+
+	serverCmd.PersistentFlags().Bool("embedded-db", false, "Serve the SQLite3 DB compiled into the binary by `make build-embedded`, ignoring --dbtype and --dbpath")
+	_ = viper.BindPFlag("embedded-db", serverCmd.PersistentFlags().Lookup("embedded-db"))
+
+	serverCmd.PersistentFlags().String("derivatives", "", "JSON file mapping the releases of the derivative distributions to the upstream (e.g. linuxmint 21 to ubuntu jammy), added to the built-in mappings")
+	_ = viper.BindPFlag("derivatives", serverCmd.PersistentFlags().Lookup("derivatives"))
+
+	serverCmd.PersistentFlags().Bool("sqlite-wal", false, "Open the SQLite3 DB in WAL mode, so that gost fetch can update it while serving. NOTE: This Option works only for dbtype: sqlite3.")
+	_ = viper.BindPFlag("sqlite-wal", serverCmd.PersistentFlags().Lookup("sqlite-wal"))
+
+	serverCmd.PersistentFlags().String("tls-cert", "", "/path/to/certificate in PEM to serve HTTPS (and gRPC over TLS). Requires --tls-key")
+	_ = viper.BindPFlag("tls-cert", serverCmd.PersistentFlags().Lookup("tls-cert"))
+
+	serverCmd.PersistentFlags().String("tls-key", "", "/path/to/private key in PEM of --tls-cert")
+	_ = viper.BindPFlag("tls-key", serverCmd.PersistentFlags().Lookup("tls-key"))
+
+	serverCmd.PersistentFlags().String("tls-ca", "", "/path/to/CA certificates in PEM. The clients must present a certificate signed by them (mutual TLS)")
+	_ = viper.BindPFlag("tls-ca", serverCmd.PersistentFlags().Lookup("tls-ca"))
+
+	serverCmd.PersistentFlags().Bool("graphql", false, "Serve /graphql selecting the fields of the CVEs across the sources")
+	_ = viper.BindPFlag("graphql", serverCmd.PersistentFlags().Lookup("graphql"))
+
+	serverCmd.PersistentFlags().Bool("grpc", false, "Serve the gRPC service of the same lookups alongside the HTTP server")
+	_ = viper.BindPFlag("grpc", serverCmd.PersistentFlags().Lookup("grpc"))
+
+	serverCmd.PersistentFlags().String("grpc-port", "1336", "gRPC server port number")
+	_ = viper.BindPFlag("grpc-port", serverCmd.PersistentFlags().Lookup("grpc-port"))
 }
 
 func executeServer(cmd *cobra.Command, args []string) (err error) {
 	logDir := viper.GetString("log-dir")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	if path := viper.GetString("derivatives"); path != "" {
+		if err := db.LoadDerivatives(path); err != nil {
+			log15.Error("Failed to load derivatives.", "err", err)
+			return err
+		}
+	}
+	tlsConfig, err := server.NewTLSConfig(viper.GetString("tls-cert"), viper.GetString("tls-key"), viper.GetString("tls-ca"))
+	if err != nil {
+		log15.Error("Failed to load TLS config.", "err", err)
+		return err
+	}
+	tokens, err := apiTokens()
+	if err != nil {
+		log15.Error("Failed to load API tokens.", "err", err)
+		return err
+	}
+	opts := dbOptions()
+	var breaker *db.CircuitBreaker
+	if threshold := viper.GetInt("circuit-breaker-threshold"); threshold > 0 {
+		breaker = db.NewCircuitBreaker(threshold, viper.GetDuration("circuit-breaker-cooldown"))
+		opts = append(opts, db.WithCircuitBreaker(breaker))
+	}
+	var cache *db.CVECache
+	if size := viper.GetInt("cve-cache-size"); size > 0 {
+		if cache, err = db.NewCVECache(size, viper.GetDuration("cve-cache-ttl")); err != nil {
+			log15.Error("Failed to create CVE cache.", "err", err)
+			return err
+		}
+		opts = append(opts, db.WithCVECache(cache))
+	}
+	dbType, dbPath := viper.GetString("dbtype"), viper.GetString("dbpath")
+	// The embedded DB and the DB downloaded are placed in a temporary directory removed on exit
+	if embedded := viper.GetBool("embedded-db"); embedded || db.IsDBPathURL(dbType, dbPath) {
+		dir, err := ioutil.TempDir("", "gost-db-")
+		if err != nil {
+			log15.Error("Failed to create a temporary directory.", "err", err)
+			return err
+		}
+		defer os.RemoveAll(dir)
+		if embedded {
+			dbType = "sqlite3"
+			dbPath, err = db.ExtractEmbeddedSQLite(dir)
+		} else {
+			dbPath, err = db.DownloadSQLite(dbPath, viper.GetString("dbpath-sha256"), dir)
+		}
+		if err != nil {
+			log15.Error("Failed to prepare DB.", "err", err)
+			return err
+		}
+	} else if dbType == "sqlite3" {
+		// The lock metadata tells gost fetch into the same file that the server is using it
+		release, err := db.AcquireServerLock(dbPath, viper.GetBool("sqlite-wal"))
+		if err != nil {
+			log15.Error("Failed to acquire server lock.", "err", err)
+			return err
+		}
+		defer func() {
+			if err := release(); err != nil {
+				log15.Warn("Failed to release server lock.", "err", err)
+			}
+		}()
+	}
+	driver, locked, err := openDBWith(dbType, dbPath, opts...)
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
@@ -48,11 +199,54 @@ func executeServer(cmd *cobra.Command, args []string) (err error) {
 		return xerrors.New("Failed to start server. SchemaVersion is old")
 	}
 
+	if viper.GetBool("grpc") {
+		s, err := server.StartGRPC(fmt.Sprintf("%s:%s", viper.GetString("bind"), viper.GetString("grpc-port")), driver, tlsConfig, tokens)
+		if err != nil {
+			log15.Error("Failed to start gRPC server.", "err", err)
+			return err
+		}
+		defer s.Stop()
+	}
+
 	log15.Info("Starting HTTP Server...")
-	if err = server.Start(logDir, driver); err != nil {
+	if err = server.Start(logDir, driver, breaker, cache, tlsConfig, tokens); err != nil {
 		log15.Error("Failed to start server.", "err", err)
 		return err
 	}
 
 	return nil
 }
+
+// apiTokens returns the API tokens of api-tokens in the config file and GOST_API_TOKENS,
+// and admin-token and read-token as the tokens of the admin and the read roles. It returns nil if none is configured.
+func apiTokens() (*server.APITokens, error) {
+	tokens := []server.APIToken{}
+	if err := viper.UnmarshalKey("api-tokens", &tokens); err != nil {
+		return nil, xerrors.Errorf("Failed to read api-tokens. err: %w", err)
+	}
+	env, err := server.ParseAPITokens(os.Getenv("GOST_API_TOKENS"))
+	if err != nil {
+		return nil, err
+	}
+	tokens = append(tokens, env...)
+	if token := viper.GetString("admin-token"); token != "" {
+		tokens = append(tokens, server.APIToken{Name: "admin-token", Token: token, Role: "admin"})
+	}
+	for i, token := range splitTokens(viper.GetStringSlice("read-token")) {
+		tokens = append(tokens, server.APIToken{Name: fmt.Sprintf("read-token-%d", i+1), Token: token, Role: "read"})
+	}
+	return server.NewAPITokens(tokens)
+}
+
+// splitTokens splits the tokens separated by commas, e.g. GOST_READ_TOKENS=reader1,reader2
+func splitTokens(tokens []string) []string {
+	ss := []string{}
+	for _, t := range tokens {
+		for _, s := range strings.Split(t, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				ss = append(ss, s)
+			}
+		}
+	}
+	return ss
+}
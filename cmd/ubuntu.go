@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
+	"io/ioutil"
+
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/db"
 	"github.com/knqyf263/gost/fetcher"
@@ -20,16 +23,38 @@ var ubuntuCmd = &cobra.Command{
 
 func init() {
 	fetchCmd.AddCommand(ubuntuCmd)
+
+	ubuntuCmd.PersistentFlags().Bool("oval", false, "Merge Ubuntu OVAL into the CVEs fetched as a secondary source")
+	_ = viper.BindPFlag("ubuntu-oval", ubuntuCmd.PersistentFlags().Lookup("oval"))
+
+	ubuntuCmd.PersistentFlags().StringSlice("oval-releases", []string{"focal", "jammy"}, "Releases of Ubuntu to merge OVAL (e.g. 22.04, jammy)")
+	_ = viper.BindPFlag("ubuntu-oval-merge-releases", ubuntuCmd.PersistentFlags().Lookup("oval-releases"))
+
+	ubuntuCmd.PersistentFlags().String("oval-merge-policy", models.UbuntuOVALMergeTracker, "Which wins when the tracker and OVAL disagree: tracker (OVAL only fills the missing packages), oval, or flag (only report)")
+	_ = viper.BindPFlag("ubuntu-oval-merge-policy", ubuntuCmd.PersistentFlags().Lookup("oval-merge-policy"))
+
+	ubuntuCmd.PersistentFlags().String("oval-report", "", "Write the disagreements between the tracker and OVAL to the file in JSON")
+	_ = viper.BindPFlag("ubuntu-oval-report", ubuntuCmd.PersistentFlags().Lookup("oval-report"))
+
+	ubuntuCmd.PersistentFlags().Int("oval-max-disagreements", -1, "Fail the fetch without inserting when the disagreements between the tracker and OVAL exceed the number. -1 means no limit")
+	_ = viper.BindPFlag("ubuntu-oval-max-disagreements", ubuntuCmd.PersistentFlags().Lookup("oval-max-disagreements"))
 }
 
 func fetchUbuntu(cmd *cobra.Command, args []string) (err error) {
-	cves, err := fetcher.FetchUbuntuVulnList()
+	cveIDs := viper.GetStringSlice("cve")
+	cves, err := fetcher.FetchUbuntuVulnList(cveIDs)
 	if err != nil {
 		return xerrors.Errorf("error in vulnerability DB initialize: %w", err)
 	}
 
+	if viper.GetBool("ubuntu-oval") {
+		if err := mergeUbuntuOVAL(cves); err != nil {
+			return err
+		}
+	}
+
 	log15.Info("Initialize Database")
-	driver, locked, err := db.NewDB(viper.GetString("dbtype"), viper.GetString("dbpath"), viper.GetBool("debug-sql"))
+	driver, locked, err := openDB()
 	if err != nil {
 		if locked {
 			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
@@ -48,8 +73,14 @@ func fetchUbuntu(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	log15.Info("Fetched", "CVEs", len(cves))
-	log15.Info("Insert Ubuntu into DB", "db", driver.Name())
-	if err := driver.InsertUbuntu(cves); err != nil {
+	if len(cveIDs) > 0 {
+		log15.Info("Upsert Ubuntu into DB", "db", driver.Name())
+		err = driver.UpsertUbuntu(cves)
+	} else {
+		log15.Info("Insert Ubuntu into DB", "db", driver.Name())
+		err = driver.InsertUbuntu(cves)
+	}
+	if err != nil {
 		log15.Error("Failed to insert.", "dbpath", viper.GetString("dbpath"), "err", err)
 		return err
 	}
@@ -61,3 +92,38 @@ func fetchUbuntu(cmd *cobra.Command, args []string) (err error) {
 
 	return nil
 }
+
+// mergeUbuntuOVAL merges Ubuntu OVAL into cves by --oval-merge-policy
+func mergeUbuntuOVAL(cves []models.UbuntuCVEJSON) error {
+	codenames, err := ubuntuOVALCodenames(viper.GetStringSlice("ubuntu-oval-merge-releases"))
+	if err != nil {
+		return err
+	}
+	ovals, err := fetcher.RetrieveUbuntuOVALs(codenames)
+	if err != nil {
+		log15.Error("Failed to fetch Ubuntu OVAL.", "err", err)
+		return err
+	}
+	report, err := db.MergeUbuntuOVAL(cves, ovals, viper.GetString("ubuntu-oval-merge-policy"))
+	if err != nil {
+		log15.Error("Failed to merge Ubuntu OVAL.", "err", err)
+		return err
+	}
+
+	if path := viper.GetString("ubuntu-oval-report"); path != "" {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return xerrors.Errorf("Failed to marshal the report of Ubuntu OVAL. err: %w", err)
+		}
+		if err := ioutil.WriteFile(path, b, 0600); err != nil {
+			return xerrors.Errorf("Failed to write the report of Ubuntu OVAL. path: %s, err: %w", path, err)
+		}
+	}
+
+	// the report is written before failing, so that the disagreements can be investigated
+	if limit := viper.GetInt("ubuntu-oval-max-disagreements"); limit >= 0 && len(report.Disagreements) > limit {
+		log15.Error("Too many disagreements between Ubuntu CVE Tracker and Ubuntu OVAL. The upstream feed may be broken", "disagreements", len(report.Disagreements), "max", limit)
+		return xerrors.Errorf("Failed to cross-check Ubuntu OVAL. disagreements: %d > max: %d", len(report.Disagreements), limit)
+	}
+	return nil
+}
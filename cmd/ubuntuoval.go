@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/encoder"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// ubuntuOVALCmd represents the ubuntu-oval command
+var ubuntuOVALCmd = &cobra.Command{
+	Use:   "ubuntu-oval",
+	Short: "Cross-check the fix status of Ubuntu CVE Tracker with Ubuntu OVAL",
+	Long:  `Cross-check the fix status of Ubuntu CVE Tracker in DB with Ubuntu OVAL, and report the disagreements`,
+	RunE:  executeUbuntuOVAL,
+}
+
+func init() {
+	analyticsCmd.AddCommand(ubuntuOVALCmd)
+
+	ubuntuOVALCmd.Flags().StringSlice("releases", []string{"focal", "jammy"}, "Releases of Ubuntu to cross-check (e.g. 22.04, jammy)")
+	_ = viper.BindPFlag("ubuntu-oval-releases", ubuntuOVALCmd.Flags().Lookup("releases"))
+}
+
+// ubuntuOVALCodenames returns the codenames of the releases of Ubuntu (e.g. 22.04, jammy)
+func ubuntuOVALCodenames(releases []string) ([]string, error) {
+	codenames := []string{}
+	for _, release := range releases {
+		codename := db.UbuntuCodename(release)
+		if codename == "" {
+			return nil, xerrors.Errorf("Unknown release of Ubuntu: %s", release)
+		}
+		codenames = append(codenames, codename)
+	}
+	return codenames, nil
+}
+
+func executeUbuntuOVAL(cmd *cobra.Command, args []string) (err error) {
+	codenames, err := ubuntuOVALCodenames(viper.GetStringSlice("ubuntu-oval-releases"))
+	if err != nil {
+		return err
+	}
+
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before analyzing", "err", err)
+		}
+		return err
+	}
+
+	ovals, err := fetcher.RetrieveUbuntuOVALs(codenames)
+	if err != nil {
+		log15.Error("Failed to fetch Ubuntu OVAL.", "err", err)
+		return err
+	}
+
+	report, err := db.CrossCheckUbuntuOVAL(driver, ovals)
+	if err != nil {
+		log15.Error("Failed to cross-check Ubuntu OVAL.", "err", err)
+		return err
+	}
+	return writeAnalytics(encoder.Report{Value: report, Records: ubuntuOVALRecords(report)})
+}
+
+func ubuntuOVALRecords(report models.UbuntuOVALCrossCheckReport) [][]string {
+	records := [][]string{{"release", "cve_id", "package_name", "kind", "tracker_status", "tracker_note", "oval_status", "oval_fixed_version", "merged"}}
+	for _, d := range report.Disagreements {
+		records = append(records, []string{d.Release, d.CveID, d.PackageName, d.Kind, d.TrackerStatus, d.TrackerNote, d.OVALStatus, d.OVALFixedVersion, strconv.FormatBool(d.Merged)})
+	}
+	return records
+}
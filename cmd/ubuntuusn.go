@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// ubuntuUSNCmd represents the ubuntu-usn command
+var ubuntuUSNCmd = &cobra.Command{
+	Use:   "ubuntu-usn",
+	Short: "Fetch the Ubuntu Security Notices and link them to Ubuntu CVEs",
+	Long:  `Fetch the Ubuntu Security Notices and link them to Ubuntu CVEs`,
+	RunE:  fetchUbuntuUSN,
+}
+
+func init() {
+	fetchCmd.AddCommand(ubuntuUSNCmd)
+}
+
+func fetchUbuntuUSN(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	log15.Info("Fetched all USNs from Ubuntu")
+	usns, err := fetcher.RetrieveUbuntuUSNs()
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "USNs", len(usns))
+
+	log15.Info("Insert Ubuntu USNs into DB", "db", driver.Name())
+	if err := driver.InsertUbuntuUSN(usns); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/encoder"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// vulnInfoCmd represents the vulninfo command
+var vulnInfoCmd = &cobra.Command{
+	Use:   "vulninfo",
+	Short: "Export the CVEs in the source-agnostic format",
+	Long:  `Export the CVEs aggregated from RedHat, Debian, Ubuntu and Microsoft in the source-agnostic format`,
+	RunE:  executeVulnInfo,
+	// GET /cves/:id/vulninfo
+	Annotations: map[string]string{remoteAnnotation: "true"},
+}
+
+func init() {
+	analyticsCmd.AddCommand(vulnInfoCmd)
+
+	vulnInfoCmd.Flags().StringSlice("cve", nil, "CVE-IDs to export (e.g. --cve CVE-2021-3449,CVE-2021-3450)")
+	_ = viper.BindPFlag("vulninfo-cve", vulnInfoCmd.Flags().Lookup("cve"))
+
+	vulnInfoCmd.Flags().String("cve-list", "", "/path/to/file of CVE-IDs to export (one per line)")
+	_ = viper.BindPFlag("vulninfo-cve-list", vulnInfoCmd.Flags().Lookup("cve-list"))
+}
+
+func executeVulnInfo(cmd *cobra.Command, args []string) (err error) {
+	cveIDs := viper.GetStringSlice("vulninfo-cve")
+	if path := viper.GetString("vulninfo-cve-list"); path != "" {
+		lines, err := util.ReadLines(path)
+		if err != nil {
+			return err
+		}
+		cveIDs = append(cveIDs, lines...)
+	}
+	if len(cveIDs) == 0 {
+		return xerrors.New("--cve or --cve-list is required")
+	}
+
+	infos, err := vulnInfos(cveIDs)
+	if err != nil {
+		return err
+	}
+
+	// CSV has a row per affected package
+	records := [][]string{{"cve_id", "severity", "cvss_score", "source", "release", "package_name", "status", "fixed_version", "advisory"}}
+	for _, v := range infos {
+		score := ""
+		for _, c := range v.Cvss {
+			if c.Version != "2.0" {
+				score = strconv.FormatFloat(c.Score, 'f', -1, 64)
+				break
+			}
+		}
+		for _, a := range v.Affected {
+			records = append(records, []string{v.ID, v.Severity, score, a.Source, a.Release, a.PackageName, a.Status, a.FixedVersion, a.Advisory})
+		}
+	}
+	return writeAnalytics(encoder.Report{Value: infos, Records: records, Vulns: infos})
+}
+
+// vulnInfos returns the CVEs found in the DB, or in the server of --server-url
+func vulnInfos(cveIDs []string) ([]models.VulnInfo, error) {
+	c, err := remoteClient()
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		driver, locked, err := openDB()
+		if err != nil {
+			if locked {
+				log15.Error("Failed to initialize DB. Close DB connection before analyzing", "err", err)
+			}
+			return nil, err
+		}
+		return db.GetVulnInfoMulti(driver, cveIDs), nil
+	}
+
+	infos := []models.VulnInfo{}
+	for _, cveID := range cveIDs {
+		v, err := c.GetVulnInfo(context.Background(), cveID)
+		if err != nil {
+			log15.Error("Failed to get the CVE from the server.", "CVE-ID", cveID, "err", err)
+			return nil, err
+		}
+		// the same as db.GetVulnInfoMulti
+		if len(v.Sources) > 0 {
+			infos = append(infos, v)
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/xerrors"
+)
+
+// wolfiCmd represents the wolfi command
+var wolfiCmd = &cobra.Command{
+	Use:   "wolfi",
+	Short: "Fetch the CVE information of Wolfi and Chainguard",
+	Long:  `Fetch the advisories of the apk packages of Wolfi and Chainguard Images from the OSV dumps`,
+	RunE:  fetchWolfi,
+}
+
+func init() {
+	fetchCmd.AddCommand(wolfiCmd)
+
+	wolfiCmd.PersistentFlags().StringSlice("ecosystems", fetcher.WolfiEcosystems, "Ecosystems of OSV to fetch")
+	_ = viper.BindPFlag("wolfi-ecosystems", wolfiCmd.PersistentFlags().Lookup("ecosystems"))
+
+	wolfiCmd.PersistentFlags().String("zip", "", "Path to the OSV dump (all.zip) of Wolfi or Chainguard to import instead of downloading")
+	_ = viper.BindPFlag("wolfi-zip", wolfiCmd.PersistentFlags().Lookup("zip"))
+}
+
+func fetchWolfi(cmd *cobra.Command, args []string) (err error) {
+	log15.Info("Initialize Database")
+	driver, locked, err := openDB()
+	if err != nil {
+		if locked {
+			log15.Error("Failed to initialize DB. Close DB connection before fetching", "err", err)
+		}
+		return err
+	}
+
+	fetchMeta, err := driver.GetFetchMeta()
+	if err != nil {
+		log15.Error("Failed to get FetchMeta from DB.", "err", err)
+		return err
+	}
+	if fetchMeta.OutDated() {
+		log15.Error("Failed to Insert CVEs into DB. SchemaVersion is old", "SchemaVersion", map[string]uint{"latest": models.LatestSchemaVersion, "DB": fetchMeta.SchemaVersion})
+		return xerrors.New("Failed to Insert CVEs into DB. SchemaVersion is old")
+	}
+
+	var vulns []models.OsvJSON
+	if path := viper.GetString("wolfi-zip"); path != "" {
+		log15.Info("Read OSV dump", "path", path)
+		vulns, err = fetcher.ReadOsvZip(path)
+	} else {
+		log15.Info("Fetch the advisories of Wolfi and Chainguard")
+		vulns, err = fetcher.RetrieveOsv(viper.GetStringSlice("wolfi-ecosystems"))
+	}
+	if err != nil {
+		return err
+	}
+
+	log15.Info("Fetched", "advisories", len(vulns))
+
+	log15.Info("Insert Wolfi CVEs into DB", "db", driver.Name())
+	if err := driver.InsertWolfi(vulns); err != nil {
+		log15.Error("Failed to insert.", "dbpath",
+			viper.GetString("dbpath"), "err", err)
+		return err
+	}
+
+	if err := driver.UpsertFetchMeta(fetchMeta); err != nil {
+		log15.Error("Failed to upsert FetchMeta to DB.", "err", err)
+		return err
+	}
+
+	return nil
+}
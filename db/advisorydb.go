@@ -0,0 +1,391 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// versionsSeparator joins the constraints of the versions of PackageAdvisoryVulnerability
+const versionsSeparator = " || "
+
+// GetPackageAdvisory :
+func (r *RDBDriver) GetPackageAdvisory(source, advisoryID string) *models.PackageAdvisory {
+	a := models.PackageAdvisory{}
+	err := r.conn.
+		Preload("CveIDs").
+		Preload("Vulnerabilities").
+		Where(&models.PackageAdvisory{Source: source, AdvisoryID: advisoryID}).
+		First(&a).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get package advisory", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetPackageAdvisoriesByCveID gets the advisories of rubysec, pypa and npm linked to the CVE
+func (r *RDBDriver) GetPackageAdvisoriesByCveID(cveID string) map[string]models.PackageAdvisory {
+	m := map[string]models.PackageAdvisory{}
+	cves := []models.PackageAdvisoryCve{}
+	err := r.conn.Where(&models.PackageAdvisoryCve{CveID: cveID}).Find(&cves).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get package advisories by CVE-ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(cves))
+	for _, c := range cves {
+		a := models.PackageAdvisory{}
+		err := r.conn.
+			Preload("CveIDs").
+			Preload("Vulnerabilities").
+			Where(&models.PackageAdvisory{ID: c.PackageAdvisoryID}).
+			First(&a).Error
+		if err != nil {
+			r.log.Error("Failed to get package advisories by CVE-ID", "err", err)
+			return nil
+		}
+		m[a.AdvisoryID] = a
+	}
+	return m
+}
+
+// GetPackageAdvisoriesByPackage gets the advisories of the package in the ecosystem.
+// Vulnerabilities of the advisories are only the ones of the package.
+func (r *RDBDriver) GetPackageAdvisoriesByPackage(ecosystem, pkgName string) map[string]models.PackageAdvisory {
+	vulns := []models.PackageAdvisoryVulnerability{}
+	err := r.conn.Where(&models.PackageAdvisoryVulnerability{Ecosystem: ecosystem, PackageName: pkgName}).Find(&vulns).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get package advisories by package", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(vulns))
+	byID := map[int64]models.PackageAdvisory{}
+	for _, v := range vulns {
+		a, ok := byID[v.PackageAdvisoryID]
+		if !ok {
+			err := r.conn.
+				Preload("CveIDs").
+				Where(&models.PackageAdvisory{ID: v.PackageAdvisoryID}).
+				First(&a).Error
+			if err != nil {
+				r.log.Error("Failed to get package advisories by package", "err", err)
+				return nil
+			}
+		}
+		a.Vulnerabilities = append(a.Vulnerabilities, v)
+		byID[v.PackageAdvisoryID] = a
+	}
+
+	m := map[string]models.PackageAdvisory{}
+	for _, a := range byID {
+		m[a.AdvisoryID] = a
+	}
+	return m
+}
+
+// InsertPackageAdvisories replaces the advisories of the source (rubysec, pypa or npm)
+func (r *RDBDriver) InsertPackageAdvisories(source string, advisories []models.PackageAdvisory) (err error) {
+	advisories = r.filter.filterPackageAdvisories(advisories)
+	if err = r.deleteAndInsertPackageAdvisories(r.conn, source, advisories); err != nil {
+		return xerrors.Errorf("Failed to insert package advisories. source: %s, err: %s", source, err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertPackageAdvisories(conn *gorm.DB, source string, advisories []models.PackageAdvisory) (err error) {
+	bar := startProgress(r.insert.Progress, len(advisories))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete the old records of the source
+	ids := []int64{}
+	if err = tx.Model(&models.PackageAdvisory{}).Where("source = ?", source).Pluck("id", &ids).Error; err != nil {
+		return xerrors.Errorf("Failed to get old records. err: %w", err)
+	}
+	for idx := range chunkSlice(len(ids), 1000) {
+		var errs util.Errors
+		errs = errs.Add(tx.Where("package_advisory_id IN ?", ids[idx.From:idx.To]).Delete(models.PackageAdvisoryCve{}).Error)
+		errs = errs.Add(tx.Where("package_advisory_id IN ?", ids[idx.From:idx.To]).Delete(models.PackageAdvisoryVulnerability{}).Error)
+		errs = errs.Add(tx.Where("id IN ?", ids[idx.From:idx.To]).Delete(models.PackageAdvisory{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+		}
+	}
+
+	for idx := range chunkSlice(len(advisories), r.insert.BatchSize) {
+		if err = tx.Create(advisories[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertRubySec converts the advisories of ruby-advisory-db.
+// An advisory of several gems is in a file per gem with the same name, so they are merged into one.
+func ConvertRubySec(advs []models.RubySecAdvisoryYAML, warnings *util.Warnings) (advisories []models.PackageAdvisory) {
+	indexes := map[string]int{}
+	for _, adv := range advs {
+		i, ok := indexes[adv.ID]
+		if !ok {
+			a := models.PackageAdvisory{
+				Source:        models.AdvisoryDBRubySec,
+				AdvisoryID:    adv.ID,
+				Title:         strings.TrimSpace(adv.Title),
+				Description:   strings.TrimSpace(adv.Description),
+				CvssScore:     adv.CvssV3,
+				Severity:      cvssV3Severity(adv.CvssV3),
+				URL:           adv.URL,
+				PublishedDate: parseAdvisoryDBDate(models.AdvisoryDBRubySec, adv.ID, "date", adv.Date, warnings),
+			}
+			if a.CvssScore == 0 {
+				a.CvssScore = adv.CvssV2
+			}
+			a.LastModifiedDate = a.PublishedDate
+			if adv.Cve != "" {
+				a.CveIDs = []models.PackageAdvisoryCve{{CveID: "CVE-" + strings.TrimPrefix(adv.Cve, "CVE-")}}
+			}
+			i = len(advisories)
+			indexes[adv.ID] = i
+			advisories = append(advisories, a)
+		}
+
+		ecosystem := NormalizeGhsaEcosystem("rubygems")
+		advisories[i].Vulnerabilities = append(advisories[i].Vulnerabilities, models.PackageAdvisoryVulnerability{
+			Ecosystem:          ecosystem,
+			PackageName:        NormalizeGhsaPackageName(ecosystem, adv.Gem),
+			PatchedVersions:    strings.Join(adv.PatchedVersions, versionsSeparator),
+			UnaffectedVersions: strings.Join(adv.UnaffectedVersions, versionsSeparator),
+		})
+	}
+	return advisories
+}
+
+// ConvertPyPA converts the vulnerabilities of PyPA advisory database. Withdrawn vulnerabilities are skipped.
+func ConvertPyPA(vulns []models.OsvJSON, warnings *util.Warnings) []models.PackageAdvisory {
+	return convertOsvAdvisories(models.AdvisoryDBPyPA, "https://osv.dev/vulnerability/%s", vulns, warnings)
+}
+
+// ConvertNpmAdvisories converts the advisories of GitHub Advisory Database. Withdrawn advisories and the packages of the other ecosystems are skipped.
+func ConvertNpmAdvisories(vulns []models.OsvJSON, warnings *util.Warnings) []models.PackageAdvisory {
+	return convertOsvAdvisories(models.AdvisoryDBNpm, "https://github.com/advisories/%s", vulns, warnings)
+}
+
+func convertOsvAdvisories(source, urlFormat string, vulns []models.OsvJSON, warnings *util.Warnings) (advisories []models.PackageAdvisory) {
+	ecosystem := map[string]string{
+		models.AdvisoryDBPyPA: "PyPI",
+		models.AdvisoryDBNpm:  "npm",
+	}[source]
+	for _, v := range vulns {
+		if v.Withdrawn != "" {
+			continue
+		}
+		a := models.PackageAdvisory{
+			Source:           source,
+			AdvisoryID:       v.ID,
+			Title:            strings.TrimSpace(v.Summary),
+			Description:      strings.TrimSpace(v.Details),
+			Severity:         strings.ToLower(v.DatabaseSpecific.Severity),
+			URL:              fmt.Sprintf(urlFormat, v.ID),
+			PublishedDate:    parseAdvisoryDBDate(source, v.ID, "published", v.Published, warnings),
+			LastModifiedDate: parseAdvisoryDBDate(source, v.ID, "modified", v.Modified, warnings),
+		}
+		for _, s := range v.Severity {
+			if s.Type == "CVSS_V3" {
+				a.CvssVector = s.Score
+				break
+			}
+		}
+		for _, alias := range v.Aliases {
+			if strings.HasPrefix(alias, "CVE-") {
+				a.CveIDs = append(a.CveIDs, models.PackageAdvisoryCve{CveID: alias})
+			}
+		}
+		for _, affected := range v.Affected {
+			if affected.Package.Ecosystem != ecosystem {
+				continue
+			}
+			e := NormalizeGhsaEcosystem(affected.Package.Ecosystem)
+			a.Vulnerabilities = append(a.Vulnerabilities, models.PackageAdvisoryVulnerability{
+				Ecosystem:              e,
+				PackageName:            NormalizeGhsaPackageName(e, affected.Package.Name),
+				VulnerableVersionRange: strings.Join(osvVersionConstraints(affected), versionsSeparator),
+				PatchedVersions:        strings.Join(osvFixedVersions(affected), versionsSeparator),
+			})
+		}
+		if len(a.Vulnerabilities) > 0 {
+			advisories = append(advisories, a)
+		}
+	}
+	return advisories
+}
+
+// osvVersionConstraints converts the ranges of the affected package of OSV to the constraints (e.g. introduced 4.0.0 and fixed 4.0.6 to ">= 4.0.0, < 4.0.6").
+// The GIT ranges are skipped, and the versions listed are used if there is no other range.
+func osvVersionConstraints(affected models.OsvAffectedJSON) (constraints []string) {
+	for _, r := range affected.Ranges {
+		if r.Type == "GIT" {
+			continue
+		}
+		introduced := ""
+		for _, e := range r.Events {
+			switch {
+			case e.Introduced != "":
+				introduced = e.Introduced
+			case e.Fixed != "":
+				constraints = append(constraints, joinOsvConstraint(introduced, "< "+e.Fixed))
+				introduced = ""
+			case e.LastAffected != "":
+				constraints = append(constraints, joinOsvConstraint(introduced, "<= "+e.LastAffected))
+				introduced = ""
+			}
+		}
+		if introduced != "" {
+			constraints = append(constraints, joinOsvConstraint(introduced, ""))
+		}
+	}
+	if len(constraints) == 0 {
+		for _, v := range affected.Versions {
+			constraints = append(constraints, "= "+v)
+		}
+	}
+	return constraints
+}
+
+func joinOsvConstraint(introduced, upper string) string {
+	if introduced == "" || introduced == "0" {
+		if upper == "" {
+			return ">= 0"
+		}
+		return upper
+	}
+	if upper == "" {
+		return ">= " + introduced
+	}
+	return fmt.Sprintf(">= %s, %s", introduced, upper)
+}
+
+func osvFixedVersions(affected models.OsvAffectedJSON) (fixed []string) {
+	for _, r := range affected.Ranges {
+		if r.Type == "GIT" {
+			continue
+		}
+		for _, e := range r.Events {
+			if e.Fixed != "" {
+				fixed = append(fixed, ">= "+e.Fixed)
+			}
+		}
+	}
+	return fixed
+}
+
+// cvssV3Severity returns the qualitative severity rating of the CVSS v3 base score
+func cvssV3Severity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "medium"
+	case score > 0:
+		return "low"
+	default:
+		return ""
+	}
+}
+
+// parseAdvisoryDBDate parses the date of ruby-advisory-db (e.g. 2020-05-18) and OSV (e.g. 2021-12-10T00:40:56Z)
+func parseAdvisoryDBDate(source, advisoryID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339, "2006-01-02")
+	if err != nil {
+		warnings.Add(source, advisoryID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetVulnerablePackageAdvisories gets the advisories of rubysec, pypa and npm affecting the version of the package in the ecosystem.
+// The ecosystem and the package name are normalized as GHSA. If ver is empty, all advisories of the package are returned.
+func GetVulnerablePackageAdvisories(driver DB, ecosystem, pkgName, ver string) map[string]models.PackageAdvisory {
+	ecosystem = NormalizeGhsaEcosystem(ecosystem)
+	advisories := driver.GetPackageAdvisoriesByPackage(ecosystem, NormalizeGhsaPackageName(ecosystem, pkgName))
+	if ver == "" {
+		return advisories
+	}
+
+	m := map[string]models.PackageAdvisory{}
+	for id, a := range advisories {
+		vulns := []models.PackageAdvisoryVulnerability{}
+		for _, v := range a.Vulnerabilities {
+			if packageAdvisoryAffected(v, ver) {
+				vulns = append(vulns, v)
+			}
+		}
+		if len(vulns) > 0 {
+			a.Vulnerabilities = vulns
+			m[id] = a
+		}
+	}
+	return m
+}
+
+// packageAdvisoryAffected returns true if ver matches any constraint of VulnerableVersionRange, or,
+// without VulnerableVersionRange, if ver matches none of the constraints of PatchedVersions and UnaffectedVersions.
+// The constraints which cannot be parsed are regarded as affected so that the advisory is not missed.
+func packageAdvisoryAffected(v models.PackageAdvisoryVulnerability, ver string) bool {
+	if v.VulnerableVersionRange != "" {
+		for _, c := range strings.Split(v.VulnerableVersionRange, versionsSeparator) {
+			if ghsaVersionAffected(c, ver) {
+				return true
+			}
+		}
+		return false
+	}
+
+	sv, err := version.NewVersion(ver)
+	if err != nil {
+		log15.Debug("Failed to parse the version", "version", ver, "err", err)
+		return true
+	}
+	for _, versions := range []string{v.PatchedVersions, v.UnaffectedVersions} {
+		if versions == "" {
+			continue
+		}
+		for _, s := range strings.Split(versions, versionsSeparator) {
+			c, err := version.NewConstraint(s)
+			if err != nil {
+				log15.Debug("Failed to parse the versions not affected", "versions", s, "err", err)
+				return true
+			}
+			if c.Check(sv) {
+				return false
+			}
+		}
+	}
+	return true
+}
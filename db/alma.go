@@ -0,0 +1,179 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetAlma :
+func (r *RDBDriver) GetAlma(cveID string) *models.AlmaCVE {
+	c := models.AlmaCVE{}
+	err := r.conn.
+		Preload("Advisories.Packages").
+		Preload("Advisories").
+		Where(&models.AlmaCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Alma", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertAlma :
+func (r *RDBDriver) InsertAlma(errata []models.AlmaErrataJSON) (err error) {
+	cves := r.filter.filterAlma(ConvertAlma(errata))
+	if err = r.deleteAndInsertAlma(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Alma CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertAlma(conn *gorm.DB, cves []models.AlmaCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AlmaPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AlmaAdvisory{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AlmaCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertAlma converts ALSA (per advisory) to AlmaCVE (per CVE)
+func ConvertAlma(errata []models.AlmaErrataJSON) (cves []models.AlmaCVE) {
+	uniqCve := map[string]models.AlmaCVE{}
+	for _, e := range errata {
+		pkgs := []models.AlmaPackage{}
+		uniqPkg := map[string]struct{}{}
+		for _, p := range e.Pkglist.Packages {
+			if p.Arch == "src" {
+				continue
+			}
+			ver := almaPackageVersion(p)
+			if _, ok := uniqPkg[p.Name+"#"+ver]; ok {
+				continue
+			}
+			uniqPkg[p.Name+"#"+ver] = struct{}{}
+			pkgs = append(pkgs, models.AlmaPackage{
+				PackageName:  p.Name,
+				FixedVersion: ver,
+			})
+		}
+
+		for _, ref := range e.References {
+			if ref.Type != "cve" {
+				continue
+			}
+			advisory := models.AlmaAdvisory{
+				AdvisoryID:  e.ID,
+				ReleaseName: e.Release,
+				Title:       e.Title,
+				Severity:    e.Severity,
+				Description: e.Description,
+				IssuedDate:  parseAlmaDate(e.IssuedDate),
+				UpdatedDate: parseAlmaDate(e.UpdatedDate),
+				Packages:    append([]models.AlmaPackage(nil), pkgs...),
+			}
+
+			c, ok := uniqCve[ref.ID]
+			if !ok {
+				c = models.AlmaCVE{CveID: ref.ID}
+			}
+			c.Advisories = append(c.Advisories, advisory)
+			uniqCve[ref.ID] = c
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+func almaPackageVersion(p models.AlmaPackageJSON) string {
+	ver := fmt.Sprintf("%s-%s", p.Version, p.Release)
+	if p.Epoch != "" && p.Epoch != "0" {
+		ver = fmt.Sprintf("%s:%s", p.Epoch, ver)
+	}
+	return ver
+}
+
+func parseAlmaDate(date models.AlmaDateJSON) time.Time {
+	if date.Date == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, date.Date*int64(time.Millisecond)).UTC()
+}
+
+// GetFixedCvesAlma gets the CVEs fixed by ALSA related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesAlma(release, pkgName string) map[string]models.AlmaCVE {
+	m := map[string]models.AlmaCVE{}
+
+	type Result struct {
+		AlmaCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("alma_packages").
+		Select("alma_advisories.alma_cve_id").
+		Joins("JOIN alma_advisories ON alma_advisories.id = alma_packages.alma_advisory_id").
+		Where("alma_packages.package_name = ? AND alma_advisories.release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Alma", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.AlmaCVE{}
+		err := r.conn.
+			Preload("Advisories.Packages", "package_name = ?", pkgName).
+			Preload("Advisories", "release_name = ?", release).
+			Where(&models.AlmaCVE{ID: res.AlmaCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get AlmaCVE", "err", err)
+			return m
+		}
+
+		for _, a := range cve.Advisories {
+			if len(a.Packages) != 0 {
+				m[cve.CveID] = cve
+			}
+		}
+	}
+
+	return m
+}
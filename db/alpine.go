@@ -0,0 +1,141 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetAlpine :
+func (r *RDBDriver) GetAlpine(cveID string) *models.AlpineCVE {
+	c := models.AlpineCVE{}
+	err := r.conn.
+		Preload("Packages").
+		Where(&models.AlpineCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Alpine", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertAlpine :
+func (r *RDBDriver) InsertAlpine(secdbs []models.AlpineSecDBJSON) (err error) {
+	cves := r.filter.filterAlpine(ConvertAlpine(secdbs))
+	if err = r.deleteAndInsertAlpine(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Alpine CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertAlpine(conn *gorm.DB, cves []models.AlpineCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AlpinePackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AlpineCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertAlpine converts secdb (per release and package) to AlpineCVE (per CVE)
+func ConvertAlpine(secdbs []models.AlpineSecDBJSON) (cves []models.AlpineCVE) {
+	uniqCve := map[string]models.AlpineCVE{}
+	for _, secdb := range secdbs {
+		release := strings.TrimPrefix(secdb.DistroVersion, "v")
+		for _, p := range secdb.Packages {
+			for fixedVersion, ids := range p.Pkg.SecFixes {
+				for _, id := range ids {
+					// e.g. "CVE-2016-2183 CVE-2016-6329", "CVE-2018-1000849 ALPINE-9999"
+					for _, cveID := range strings.Fields(id) {
+						if !strings.HasPrefix(cveID, "CVE-") {
+							continue
+						}
+						c, ok := uniqCve[cveID]
+						if !ok {
+							c = models.AlpineCVE{CveID: cveID}
+						}
+						c.Packages = append(c.Packages, models.AlpinePackage{
+							ReleaseName:  release,
+							Repository:   secdb.RepoName,
+							PackageName:  p.Pkg.Name,
+							FixedVersion: fixedVersion,
+						})
+						uniqCve[cveID] = c
+					}
+				}
+			}
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+// GetFixedCvesAlpine gets the CVEs related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesAlpine(release, pkgName string) map[string]models.AlpineCVE {
+	m := map[string]models.AlpineCVE{}
+
+	type Result struct {
+		AlpineCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("alpine_packages").
+		Select("alpine_cve_id").
+		Where("package_name = ? AND release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Alpine", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.AlpineCVE{}
+		err := r.conn.
+			Preload("Packages", "package_name = ? AND release_name = ?", pkgName, release).
+			Where(&models.AlpineCVE{ID: res.AlpineCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get AlpineCVE", "err", err)
+			return m
+		}
+		if len(cve.Packages) != 0 {
+			m[cve.CveID] = cve
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetAmazon :
+func (r *RDBDriver) GetAmazon(cveID string) *models.AmazonCVE {
+	c := models.AmazonCVE{}
+	err := r.conn.
+		Preload("Advisories.Packages").
+		Preload("Advisories").
+		Where(&models.AmazonCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Amazon", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertAmazon :
+func (r *RDBDriver) InsertAmazon(alasJSONs []models.AmazonALASJSON) (err error) {
+	cves := r.filter.filterAmazon(ConvertAmazon(alasJSONs, r.warnings))
+	if err = r.deleteAndInsertAmazon(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Amazon CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertAmazon(conn *gorm.DB, cves []models.AmazonCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AmazonPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AmazonAdvisory{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AmazonCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertAmazon converts ALAS (per advisory) to AmazonCVE (per CVE)
+func ConvertAmazon(alasJSONs []models.AmazonALASJSON, warnings *util.Warnings) (cves []models.AmazonCVE) {
+	uniqCve := map[string]models.AmazonCVE{}
+	for _, alas := range alasJSONs {
+		pkgs := []models.AmazonPackage{}
+		for _, p := range alas.Packages {
+			pkgs = append(pkgs, models.AmazonPackage{
+				PackageName:  p.Name,
+				FixedVersion: amazonPackageVersion(p),
+				Arch:         p.Arch,
+			})
+		}
+
+		issued := parseAmazonDate(alas.ID, "issued", alas.Issued.Date, warnings)
+		updated := parseAmazonDate(alas.ID, "updated", alas.Updated.Date, warnings)
+		for _, cveID := range alas.CveIDs {
+			advisory := models.AmazonAdvisory{
+				AdvisoryID:  alas.ID,
+				ReleaseName: alas.Release,
+				Title:       alas.Title,
+				Severity:    alas.Severity,
+				Description: alas.Description,
+				IssuedDate:  issued,
+				UpdatedDate: updated,
+				Packages:    append([]models.AmazonPackage(nil), pkgs...),
+			}
+
+			c, ok := uniqCve[cveID]
+			if !ok {
+				c = models.AmazonCVE{CveID: cveID}
+			}
+			c.Advisories = append(c.Advisories, advisory)
+			uniqCve[cveID] = c
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+func amazonPackageVersion(p models.AmazonPackageJSON) string {
+	ver := fmt.Sprintf("%s-%s", p.Version, p.Release)
+	if p.Epoch != "" && p.Epoch != "0" {
+		ver = fmt.Sprintf("%s:%s", p.Epoch, ver)
+	}
+	return ver
+}
+
+func parseAmazonDate(alasID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	if t, err := util.ParseDate(date, "2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02"); err == nil {
+		return t
+	}
+	warnings.Add("amazon", alasID, field, fmt.Sprintf("Failed to parse date: %s", date))
+	return time.Time{}
+}
+
+// GetUnfixedCvesAmazon gets the unfixed CVEs.
+// ALAS only publishes the advisories that have already been fixed, so it always returns an empty map.
+func (r *RDBDriver) GetUnfixedCvesAmazon(release, pkgName string) map[string]models.AmazonCVE {
+	return map[string]models.AmazonCVE{}
+}
+
+// GetFixedCvesAmazon gets the CVEs fixed by ALAS related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesAmazon(release, pkgName string) map[string]models.AmazonCVE {
+	m := map[string]models.AmazonCVE{}
+
+	type Result struct {
+		AmazonCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("amazon_packages").
+		Select("amazon_advisories.amazon_cve_id").
+		Joins("JOIN amazon_advisories ON amazon_advisories.id = amazon_packages.amazon_advisory_id").
+		Where("amazon_packages.package_name = ? AND amazon_advisories.release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Amazon", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.AmazonCVE{}
+		err := r.conn.
+			Preload("Advisories.Packages", "package_name = ?", pkgName).
+			Preload("Advisories", "release_name = ?", release).
+			Where(&models.AmazonCVE{ID: res.AmazonCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get AmazonCVE", "err", err)
+			return m
+		}
+
+		for _, a := range cve.Advisories {
+			if len(a.Packages) != 0 {
+				m[cve.CveID] = cve
+			}
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetAnolis :
+func (r *RDBDriver) GetAnolis(cveID string) *models.AnolisCVE {
+	c := models.AnolisCVE{}
+	err := r.conn.
+		Preload("Advisories.Packages").
+		Preload("Advisories").
+		Where(&models.AnolisCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Anolis OS", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertAnolis :
+func (r *RDBDriver) InsertAnolis(ovals []models.AnolisOVALXML) (err error) {
+	cves := r.filter.filterAnolis(ConvertAnolis(ovals, r.warnings))
+	if err = r.deleteAndInsertAnolis(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Anolis OS CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertAnolis(conn *gorm.DB, cves []models.AnolisCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AnolisPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AnolisAdvisory{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AnolisCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertAnolis converts OVAL definitions (per advisory) to AnolisCVE (per CVE)
+func ConvertAnolis(ovals []models.AnolisOVALXML, warnings *util.Warnings) (cves []models.AnolisCVE) {
+	uniqCve := map[string]models.AnolisCVE{}
+	for _, oval := range ovals {
+		for _, def := range oval.Definitions {
+			advisoryID := anolisAdvisoryID(def)
+			pkgs := walkAnolisCriteria(def.Criteria, oval.Release, map[string]struct{}{})
+			if len(pkgs) == 0 {
+				warnings.Add("anolis", advisoryID, "criteria", "No fixed package in the criteria")
+				continue
+			}
+
+			cveIDs := def.Metadata.Cves
+			if len(cveIDs) == 0 {
+				for _, ref := range def.Metadata.References {
+					if ref.Source == "CVE" {
+						cveIDs = append(cveIDs, ref.RefID)
+					}
+				}
+			}
+
+			issued := parseAnolisDate(advisoryID, def.Metadata.Issued.Date, warnings)
+			for _, cveID := range cveIDs {
+				cveID = strings.TrimSpace(cveID)
+				advisory := models.AnolisAdvisory{
+					AdvisoryID:  advisoryID,
+					Title:       def.Metadata.Title,
+					Severity:    def.Metadata.Severity,
+					Description: def.Metadata.Description,
+					IssuedDate:  issued,
+					Packages:    append([]models.AnolisPackage(nil), pkgs...),
+				}
+
+				cve, ok := uniqCve[cveID]
+				if !ok {
+					cve = models.AnolisCVE{CveID: cveID}
+				}
+				cve.Advisories = append(cve.Advisories, advisory)
+				uniqCve[cveID] = cve
+			}
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+// e.g. "openssl is earlier than 1:1.1.1k-5.an8"
+var anolisPackageCriterion = regexp.MustCompile(`^(\S+) is earlier than (\S+)$`)
+
+// walkAnolisCriteria collects the fixed packages from the criteria.
+// The same package may be listed more than once, so it is deduplicated with uniq.
+func walkAnolisCriteria(criteria models.AnolisCriteriaXML, release string, uniq map[string]struct{}) (pkgs []models.AnolisPackage) {
+	for _, c := range criteria.Criterions {
+		ss := anolisPackageCriterion.FindStringSubmatch(c.Comment)
+		if ss == nil {
+			continue
+		}
+		pkg := models.AnolisPackage{
+			ReleaseName:  release,
+			PackageName:  ss[1],
+			FixedVersion: strings.TrimPrefix(ss[2], "0:"),
+		}
+		key := pkg.PackageName + "#" + pkg.FixedVersion
+		if _, ok := uniq[key]; ok {
+			continue
+		}
+		uniq[key] = struct{}{}
+		pkgs = append(pkgs, pkg)
+	}
+
+	for _, c := range criteria.Criterias {
+		pkgs = append(pkgs, walkAnolisCriteria(c, release, uniq)...)
+	}
+	return pkgs
+}
+
+// anolisAdvisoryID returns ANSA ID (e.g. ANSA-2021:0001)
+func anolisAdvisoryID(def models.AnolisDefinitionXML) string {
+	for _, ref := range def.Metadata.References {
+		if ref.Source == "ANSA" {
+			return ref.RefID
+		}
+	}
+	// e.g. "ANSA-2021:0001: openssl security update (Important)"
+	if ss := strings.SplitN(def.Metadata.Title, ": ", 2); len(ss) == 2 {
+		return strings.TrimSpace(ss[0])
+	}
+	return def.ID
+}
+
+func parseAnolisDate(advisoryID, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, "2006-01-02")
+	if err != nil {
+		warnings.Add("anolis", advisoryID, "issued", fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetFixedCvesAnolis gets the CVEs fixed by the advisories related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesAnolis(release, pkgName string) map[string]models.AnolisCVE {
+	m := map[string]models.AnolisCVE{}
+
+	type Result struct {
+		AnolisCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("anolis_packages").
+		Select("DISTINCT anolis_advisories.anolis_cve_id").
+		Joins("JOIN anolis_advisories ON anolis_advisories.id = anolis_packages.anolis_advisory_id").
+		Where("anolis_packages.package_name = ? AND anolis_packages.release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Anolis OS", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.AnolisCVE{}
+		err := r.conn.
+			Preload("Advisories.Packages", "package_name = ? AND release_name = ?", pkgName, release).
+			Preload("Advisories").
+			Where(&models.AnolisCVE{ID: res.AnolisCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get AnolisCVE", "err", err)
+			return m
+		}
+
+		advisories := []models.AnolisAdvisory{}
+		for _, a := range cve.Advisories {
+			if len(a.Packages) != 0 {
+				advisories = append(advisories, a)
+			}
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cve.CveID] = cve
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,242 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidAppleVersion is returned when the version is not dotted numbers, or the build is not of a release known
+var ErrInvalidAppleVersion = xerrors.New("Invalid version")
+
+// e.g. macOS Sonoma 14.4, macOS Big Sur 11.7.10, iOS 17.4 and iPadOS 17.4, Safari 17.4
+var appleProductVersionRegexp = regexp.MustCompile(`\b(macOS|iOS|iPadOS|watchOS|tvOS|visionOS|Safari|Xcode)(?:\s+[A-Z][a-z]+)*\s+(\d+(?:\.\d+)*)\b`)
+
+// appleUnversionedLines are the products whose releases are compared with all the versions,
+// since they are not maintained by the major versions in parallel as the OSes are
+var appleUnversionedLines = map[string]bool{"safari": true, "xcode": true}
+
+// applePmvProducts are the products of the asset sets of Apple Software Lookup Service
+var applePmvProducts = map[string]string{"ios": "iOS", "ipados": "iOS", "macos": "macOS"}
+
+// GetApple :
+func (r *RDBDriver) GetApple(releaseID string) *models.AppleSecurityRelease {
+	a := models.AppleSecurityRelease{}
+	err := r.conn.
+		Preload("CveIDs").
+		Preload("Products").
+		Where(&models.AppleSecurityRelease{ReleaseID: releaseID}).
+		First(&a).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Apple security release", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetAppleByCveID gets the Apple security releases fixing the CVE
+func (r *RDBDriver) GetAppleByCveID(cveID string) map[string]models.AppleSecurityRelease {
+	m := map[string]models.AppleSecurityRelease{}
+	cves := []models.AppleCve{}
+	err := r.conn.Where(&models.AppleCve{CveID: cveID}).Find(&cves).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Apple security releases by CVE-ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(cves))
+	for _, c := range cves {
+		a := models.AppleSecurityRelease{}
+		err := r.conn.
+			Preload("CveIDs").
+			Preload("Products").
+			Where(&models.AppleSecurityRelease{ID: c.AppleSecurityReleaseID}).
+			First(&a).Error
+		if err != nil {
+			r.log.Error("Failed to get Apple security releases by CVE-ID", "err", err)
+			return nil
+		}
+		m[a.ReleaseID] = a
+	}
+	return m
+}
+
+// GetAppleByProduct gets the Apple security releases of the product. Only the versions of the product are in Products.
+func (r *RDBDriver) GetAppleByProduct(product string) map[string]models.AppleSecurityRelease {
+	products := []models.AppleProduct{}
+	err := r.conn.Where(&models.AppleProduct{Product: strings.ToLower(product)}).Find(&products).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Apple security releases by product", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(products))
+	byID := map[int64]models.AppleSecurityRelease{}
+	for _, p := range products {
+		a, ok := byID[p.AppleSecurityReleaseID]
+		if !ok {
+			err := r.conn.
+				Preload("CveIDs").
+				Where(&models.AppleSecurityRelease{ID: p.AppleSecurityReleaseID}).
+				First(&a).Error
+			if err != nil {
+				r.log.Error("Failed to get Apple security releases by product", "err", err)
+				return nil
+			}
+		}
+		a.Products = append(a.Products, p)
+		byID[p.AppleSecurityReleaseID] = a
+	}
+
+	m := map[string]models.AppleSecurityRelease{}
+	for _, a := range byID {
+		m[a.ReleaseID] = a
+	}
+	return m
+}
+
+// GetUnfixedApple gets the Apple security releases of the product newer than the version, whose CVEs are not fixed in the version.
+// The OSes are compared in the same major version (e.g. 14 of macOS Sonoma, 10.15 of macOS Catalina), since the older major versions get their own releases.
+// If the version is empty, it is resolved from the build (e.g. 23E214) of the releases known. If both are empty, all the releases of the product are returned.
+func GetUnfixedApple(driver DB, product, ver, build string) (map[string]models.AppleSecurityRelease, error) {
+	product = strings.ToLower(product)
+	releases := driver.GetAppleByProduct(product)
+	if ver == "" && build != "" {
+		for _, a := range releases {
+			for _, p := range a.Products {
+				if p.Build == strings.TrimSpace(build) {
+					ver = p.Version
+				}
+			}
+		}
+		if ver == "" {
+			return nil, xerrors.Errorf("unknown build of %s %s: %w", product, build, ErrInvalidAppleVersion)
+		}
+	}
+	if ver == "" {
+		return releases, nil
+	}
+
+	v, err := version.NewVersion(strings.TrimSpace(ver))
+	if err != nil {
+		return nil, xerrors.Errorf("%s %s: %w", product, ver, ErrInvalidAppleVersion)
+	}
+	m := map[string]models.AppleSecurityRelease{}
+	for id, a := range releases {
+		for _, p := range a.Products {
+			pv, err := version.NewVersion(p.Version)
+			if err != nil {
+				continue
+			}
+			if !appleUnversionedLines[product] && appleMajor(product, pv) != appleMajor(product, v) {
+				continue
+			}
+			if pv.GreaterThan(v) {
+				m[id] = a
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+// appleMajor returns the major version of the OS, which is the first two numbers for Mac OS X (e.g. 10.15)
+func appleMajor(product string, v *version.Version) string {
+	segs := v.Segments()
+	if product == "macos" && segs[0] == 10 && len(segs) > 1 {
+		return fmt.Sprintf("%d.%d", segs[0], segs[1])
+	}
+	return fmt.Sprint(segs[0])
+}
+
+// InsertApple replaces the Apple security releases
+func (r *RDBDriver) InsertApple(releases []models.AppleSecurityRelease) (err error) {
+	releases = r.filter.filterApple(releases)
+	bar := startProgress(r.insert.Progress, len(releases))
+	tx := r.conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AppleCve{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AppleProduct{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.AppleSecurityRelease{}).Error)
+	errs = util.DeleteNil(errs)
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(releases), r.insert.BatchSize) {
+		if err = tx.Create(releases[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+	return nil
+}
+
+// ConvertApple converts the Apple security releases with the CVE entries published.
+// The products and the versions are from the name of the release, and the builds from Apple Software Lookup Service if pmv is not nil.
+func ConvertApple(rows []models.AppleReleaseHTML, pmv *models.ApplePmvJSON, warnings *util.Warnings) (releases []models.AppleSecurityRelease) {
+	builds := map[string]string{}
+	if pmv != nil {
+		for _, sets := range []map[string][]models.ApplePmvAssetJSON{pmv.PublicAssetSets, pmv.AssetSets} {
+			for product, assets := range sets {
+				for _, a := range assets {
+					if _, ok := builds[product+"#"+a.ProductVersion]; !ok {
+						builds[product+"#"+a.ProductVersion] = a.Build
+					}
+				}
+			}
+		}
+	}
+
+	for _, row := range rows {
+		if row.ReleaseID == "" || len(row.CveIDs) == 0 {
+			continue
+		}
+		date, err := util.ParseDate(row.ReleaseDate, "02 Jan 2006", "2 Jan 2006", "January 2, 2006")
+		if err != nil && row.ReleaseDate != "" {
+			warnings.Add("apple", row.ReleaseID, "release date", fmt.Sprintf("Failed to parse date: %s", row.ReleaseDate))
+		}
+		a := models.AppleSecurityRelease{
+			ReleaseID:    row.ReleaseID,
+			Name:         row.Name,
+			URL:          row.URL,
+			AvailableFor: row.AvailableFor,
+			ReleaseDate:  date,
+		}
+		for _, cveID := range row.CveIDs {
+			a.CveIDs = append(a.CveIDs, models.AppleCve{CveID: cveID})
+		}
+		uniq := map[string]struct{}{}
+		for _, m := range appleProductVersionRegexp.FindAllStringSubmatch(row.Name, -1) {
+			product := strings.ToLower(m[1])
+			if _, ok := uniq[product]; ok {
+				continue
+			}
+			uniq[product] = struct{}{}
+			a.Products = append(a.Products, models.AppleProduct{
+				Product: product,
+				Version: m[2],
+				Build:   builds[applePmvProducts[product]+"#"+m[2]],
+			})
+		}
+		releases = append(releases, a)
+	}
+	return releases
+}
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// ErrCircuitOpen is returned when the circuit breaker rejects the query without sending it to the DB
+var ErrCircuitOpen = xerrors.New("Circuit breaker is open")
+
+// The states of CircuitBreaker
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// CircuitBreaker opens after threshold consecutive failures of the DB, and rejects the queries until cooldown passes.
+// After cooldown, it is half-open and lets a probe through. The probe closes it on success, or opens it again on failure.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+	stats    CircuitBreakerStats
+}
+
+// CircuitBreakerStats : the metric of CircuitBreaker
+type CircuitBreakerStats struct {
+	State string `json:"state"`
+	// Failures is the number of the consecutive failures
+	Failures int `json:"failures"`
+	// Opened is the number of times the circuit opened
+	Opened int64 `json:"opened"`
+	// Rejected is the number of the queries and the requests rejected while open
+	Rejected int64 `json:"rejected"`
+}
+
+// NewCircuitBreaker returns the closed CircuitBreaker
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, state: CircuitClosed}
+}
+
+// Allow returns false if the circuit is open.
+// If cooldown has passed, the circuit becomes half-open and only the first caller is allowed as the probe until Done.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cooldown {
+		b.state = CircuitHalfOpen
+	}
+	switch b.state {
+	case CircuitOpen:
+		b.stats.Rejected++
+		return false
+	case CircuitHalfOpen:
+		if b.probing {
+			b.stats.Rejected++
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// Done releases the probe allowed by Allow, so that the next caller can probe if the probe did not query the DB
+func (b *CircuitBreaker) Done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// RetryAfter returns the duration until the circuit becomes half-open
+func (b *CircuitBreaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != CircuitOpen {
+		return 0
+	}
+	if d := b.cooldown - time.Since(b.openedAt); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Stats returns the current state and the counters
+func (b *CircuitBreaker) Stats() CircuitBreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := b.stats
+	stats.State = b.state
+	stats.Failures = b.failures
+	return stats
+}
+
+// open rejects the query if the circuit is open. While half-open, the queries are sent since the caller is the probe.
+func (b *CircuitBreaker) open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) < b.cooldown {
+		b.stats.Rejected++
+		return true
+	}
+	return false
+}
+
+// record counts err as a failure of the DB. Not found is not a failure.
+func (b *CircuitBreaker) record(err error) {
+	if errors.Is(err, ErrCircuitOpen) {
+		return
+	}
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, redis.Nil)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.state, b.failures = CircuitClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == CircuitHalfOpen || (b.state == CircuitClosed && b.failures >= b.threshold) {
+		b.state, b.openedAt = CircuitOpen, time.Now()
+		b.stats.Opened++
+	}
+}
+
+// registerCircuitBreaker records the result of each SELECT of gorm, and fails it without querying while the circuit is open
+func registerCircuitBreaker(conn *gorm.DB, b *CircuitBreaker) error {
+	if err := conn.Callback().Query().Before("gorm:query").Register("gost:circuit_breaker", func(db *gorm.DB) {
+		if b.open() {
+			_ = db.AddError(ErrCircuitOpen)
+		}
+	}); err != nil {
+		return xerrors.Errorf("Failed to register circuit breaker. err: %w", err)
+	}
+	if err := conn.Callback().Query().After("gorm:after_query").Register("gost:circuit_breaker_record", func(db *gorm.DB) {
+		b.record(db.Error)
+	}); err != nil {
+		return xerrors.Errorf("Failed to register circuit breaker. err: %w", err)
+	}
+	return nil
+}
+
+// circuitBreakerHook records the result of each command and pipeline of Redis, and fails them while the circuit is open
+type circuitBreakerHook struct {
+	breaker *CircuitBreaker
+}
+
+func (h circuitBreakerHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	if h.breaker.open() {
+		return ctx, ErrCircuitOpen
+	}
+	return ctx, nil
+}
+
+func (h circuitBreakerHook) AfterProcess(_ context.Context, cmd redis.Cmder) error {
+	h.breaker.record(cmd.Err())
+	return nil
+}
+
+func (h circuitBreakerHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	if h.breaker.open() {
+		return ctx, ErrCircuitOpen
+	}
+	return ctx, nil
+}
+
+func (h circuitBreakerHook) AfterProcessPipeline(_ context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if e := cmd.Err(); e != nil && !errors.Is(e, redis.Nil) {
+			err = e
+			break
+		}
+	}
+	h.breaker.record(err)
+	return nil
+}
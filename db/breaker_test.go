@@ -0,0 +1,90 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errDB := errors.New("connection refused")
+	b := NewCircuitBreaker(2, time.Hour)
+	expect := func(step, state string, failures int, opened, rejected int64) {
+		t.Helper()
+		stats := b.Stats()
+		if stats.State != state || stats.Failures != failures || stats.Opened != opened || stats.Rejected != rejected {
+			t.Errorf("[%s] expected: %s failures=%d opened=%d rejected=%d\n  actual: %+v\n", step, state, failures, opened, rejected, stats)
+		}
+	}
+	// cool lets the cooldown pass
+	cool := func() {
+		b.mu.Lock()
+		b.openedAt = time.Now().Add(-time.Hour)
+		b.mu.Unlock()
+	}
+
+	b.record(errDB)
+	expect("one failure", CircuitClosed, 1, 0, 0)
+	b.record(gorm.ErrRecordNotFound)
+	expect("not found is not a failure", CircuitClosed, 0, 0, 0)
+	b.record(redis.Nil)
+	b.record(errDB)
+	b.record(errDB)
+	expect("threshold", CircuitOpen, 2, 1, 0)
+
+	if b.Allow() {
+		t.Error("allowed while open")
+	}
+	if !b.open() {
+		t.Error("the query is not rejected while open")
+	}
+	if d := b.RetryAfter(); d <= 0 || d > time.Hour {
+		t.Errorf("unexpected RetryAfter while open: %s", d)
+	}
+	b.record(ErrCircuitOpen)
+	expect("the rejected queries are not failures", CircuitOpen, 2, 1, 2)
+
+	cool()
+	if !b.Allow() {
+		t.Error("the probe is not allowed after the cooldown")
+	}
+	if b.Allow() {
+		t.Error("the second probe is allowed while the first is in flight")
+	}
+	if b.open() {
+		t.Error("the query of the probe is rejected while half-open")
+	}
+	expect("half-open", CircuitHalfOpen, 2, 1, 3)
+
+	// the probe not querying the DB is released by Done
+	b.Done()
+	if !b.Allow() {
+		t.Error("the next probe is not allowed after Done")
+	}
+	b.record(errDB)
+	expect("the probe failed", CircuitOpen, 3, 2, 3)
+	if b.Allow() {
+		t.Error("allowed after the probe failed")
+	}
+	b.Done()
+
+	cool()
+	if !b.Allow() {
+		t.Error("the probe is not allowed after the second cooldown")
+	}
+	b.record(nil)
+	b.Done()
+	expect("the probe succeeded", CircuitClosed, 0, 2, 4)
+	for i := 0; i < 3; i++ {
+		if !b.Allow() {
+			t.Errorf("[%d] not allowed while closed", i)
+		}
+		b.Done()
+	}
+	if d := b.RetryAfter(); d != 0 {
+		t.Errorf("unexpected RetryAfter while closed: %s", d)
+	}
+}
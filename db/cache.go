@@ -0,0 +1,318 @@
+package db
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// CVECache keeps the CVEs of RedHat, Debian, Ubuntu and Microsoft got by ID in memory, least recently used first out,
+// so that the repeated scans of similar hosts do not decode the same large documents from the DB again.
+type CVECache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[cveCacheKey]*list.Element
+	order   *list.List
+	stats   CVECacheStats
+	// fetchedAt is the latest time a source was fetched into the DB, checked at checkedAt
+	fetchedAt time.Time
+	checkedAt time.Time
+}
+
+// cveCacheCheckInterval is the interval to check whether a source has been fetched into the DB (e.g. by gost fetch in another process)
+const cveCacheCheckInterval = time.Second
+
+type cveCacheKey struct {
+	source string
+	cveID  string
+}
+
+type cveCacheEntry struct {
+	key      cveCacheKey
+	cve      interface{}
+	storedAt time.Time
+}
+
+// CVECacheStats : the metric of CVECache
+type CVECacheStats struct {
+	Entries   int   `json:"entries"`
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	// Purges is the number of times the cache is cleared by the writes to the DB
+	Purges int64 `json:"purges"`
+}
+
+// NewCVECache returns the cache of size CVEs, each kept for ttl.
+// ttl is required, since the writes by the other processes not recorded as a fetch (e.g. the hotload of another server on the same Redis) are seen only after it.
+func NewCVECache(size int, ttl time.Duration) (*CVECache, error) {
+	if size <= 0 {
+		return nil, xerrors.Errorf("Invalid size of CVE cache: %d", size)
+	}
+	if ttl <= 0 {
+		return nil, xerrors.Errorf("Invalid TTL of CVE cache: %s. It must be positive", ttl)
+	}
+	return &CVECache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[cveCacheKey]*list.Element{},
+		order:   list.New(),
+	}, nil
+}
+
+func (c *CVECache) get(key cveCacheKey) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elm, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	entry := elm.Value.(*cveCacheEntry)
+	if time.Since(entry.storedAt) > c.ttl {
+		c.order.Remove(elm)
+		delete(c.entries, key)
+		c.stats.Misses++
+		return nil, false
+	}
+	c.order.MoveToFront(elm)
+	c.stats.Hits++
+	return entry.cve, true
+}
+
+func (c *CVECache) add(key cveCacheKey, cve interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elm, ok := c.entries[key]; ok {
+		c.order.Remove(elm)
+	}
+	c.entries[key] = c.order.PushFront(&cveCacheEntry{key: key, cve: cve, storedAt: time.Now()})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cveCacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// Purge removes all the CVEs kept
+func (c *CVECache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.purge()
+}
+
+func (c *CVECache) purge() {
+	c.entries = map[cveCacheKey]*list.Element{}
+	c.order.Init()
+	c.stats.Purges++
+}
+
+// checkFetched purges the cache if a source has been fetched into the DB since the last check, e.g. by gost fetch in another process,
+// so that the CVEs and KEV and EPSS joined into them are not served stale after the fetch. The DB is checked once per cveCacheCheckInterval,
+// and the cache is purged if the check fails.
+func (c *CVECache) checkFetched(driver DB) {
+	c.mu.Lock()
+	if time.Since(c.checkedAt) < cveCacheCheckInterval {
+		c.mu.Unlock()
+		return
+	}
+	c.checkedAt = time.Now()
+	c.mu.Unlock()
+
+	sources, err := driver.GetFetchSources()
+	latest := time.Time{}
+	for _, s := range sources {
+		if s.FetchedAt.After(latest) {
+			latest = s.FetchedAt
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil || !latest.Equal(c.fetchedAt) {
+		c.purge()
+		c.fetchedAt = latest
+	}
+}
+
+// Stats returns the number of the CVEs kept and the counters
+func (c *CVECache) Stats() CVECacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+// cacheDriver answers GetRedhat, GetDebian, GetUbuntu and GetMicrosoft from CVECache.
+// It wraps enrichDriver, so that KEV, EPSS and the exploits joined are cached as well.
+// The cache is purged by the writes through the driver, and by the fetches into the DB checked by checkFetched.
+// Only the CVEs found are kept, since the drivers return an empty CVE both when it is not found and when the query fails.
+type cacheDriver struct {
+	DB
+	cache *CVECache
+}
+
+// WithLogger :
+func (d *cacheDriver) WithLogger(l log15.Logger) DB {
+	return &cacheDriver{DB: d.DB.WithLogger(l), cache: d.cache}
+}
+
+// WithExplain bypasses the cache, so that the queries are explained
+func (d *cacheDriver) WithExplain(e *Explain) DB {
+	return d.DB.WithExplain(e)
+}
+
+// WithInsertOptions :
+func (d *cacheDriver) WithInsertOptions(o InsertOptions) DB {
+	return &cacheDriver{DB: d.DB.WithInsertOptions(o), cache: d.cache}
+}
+
+// The CVEs returned are shallow copies, so that the callers setting the fields do not change the cache.
+// The slices and the maps in them are shared and must not be modified.
+
+// GetRedhat :
+func (d *cacheDriver) GetRedhat(cveID string) *models.RedhatCVE {
+	d.cache.checkFetched(d.DB)
+	key := cveCacheKey{source: "redhat", cveID: cveID}
+	if v, ok := d.cache.get(key); ok {
+		c := *v.(*models.RedhatCVE)
+		return &c
+	}
+	c := d.DB.GetRedhat(cveID)
+	if c != nil && c.Name != "" {
+		cached := *c
+		d.cache.add(key, &cached)
+	}
+	return c
+}
+
+// GetDebian :
+func (d *cacheDriver) GetDebian(cveID string) *models.DebianCVE {
+	d.cache.checkFetched(d.DB)
+	key := cveCacheKey{source: "debian", cveID: cveID}
+	if v, ok := d.cache.get(key); ok {
+		c := *v.(*models.DebianCVE)
+		return &c
+	}
+	c := d.DB.GetDebian(cveID)
+	if c != nil && c.CveID != "" {
+		cached := *c
+		d.cache.add(key, &cached)
+	}
+	return c
+}
+
+// GetUbuntu :
+func (d *cacheDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
+	d.cache.checkFetched(d.DB)
+	key := cveCacheKey{source: "ubuntu", cveID: cveID}
+	if v, ok := d.cache.get(key); ok {
+		c := *v.(*models.UbuntuCVE)
+		return &c
+	}
+	c := d.DB.GetUbuntu(cveID)
+	if c != nil && c.Candidate != "" {
+		cached := *c
+		d.cache.add(key, &cached)
+	}
+	return c
+}
+
+// GetMicrosoft :
+func (d *cacheDriver) GetMicrosoft(cveID string) *models.MicrosoftCVE {
+	d.cache.checkFetched(d.DB)
+	key := cveCacheKey{source: "microsoft", cveID: cveID}
+	if v, ok := d.cache.get(key); ok {
+		c := *v.(*models.MicrosoftCVE)
+		return &c
+	}
+	c := d.DB.GetMicrosoft(cveID)
+	if c != nil && c.CveID != "" {
+		cached := *c
+		d.cache.add(key, &cached)
+	}
+	return c
+}
+
+// purgeAfter clears the cache after the write to the DB changing the CVEs cached or the data joined into them
+func (d *cacheDriver) purgeAfter(err error) error {
+	d.cache.Purge()
+	return err
+}
+
+// InsertRedhat :
+func (d *cacheDriver) InsertRedhat(cves []models.RedhatCVEJSON) error {
+	return d.purgeAfter(d.DB.InsertRedhat(cves))
+}
+
+// InsertDebian :
+func (d *cacheDriver) InsertDebian(cves models.DebianJSON, advisories []models.DebianAdvisoryJSON) error {
+	return d.purgeAfter(d.DB.InsertDebian(cves, advisories))
+}
+
+// InsertUbuntu :
+func (d *cacheDriver) InsertUbuntu(cves []models.UbuntuCVEJSON) error {
+	return d.purgeAfter(d.DB.InsertUbuntu(cves))
+}
+
+// InsertMicrosoft :
+func (d *cacheDriver) InsertMicrosoft(cves []models.MicrosoftXML, bulletins []models.MicrosoftBulletinSearch) error {
+	return d.purgeAfter(d.DB.InsertMicrosoft(cves, bulletins))
+}
+
+// InsertKEV :
+func (d *cacheDriver) InsertKEV(entries []models.KEVEntryJSON) error {
+	return d.purgeAfter(d.DB.InsertKEV(entries))
+}
+
+// InsertEpss :
+func (d *cacheDriver) InsertEpss(epss *models.EpssCSV) error {
+	return d.purgeAfter(d.DB.InsertEpss(epss))
+}
+
+// InsertExploit :
+func (d *cacheDriver) InsertExploit(exploits []models.ExploitDBCSV, modules []models.MetasploitModuleJSON) error {
+	return d.purgeAfter(d.DB.InsertExploit(exploits, modules))
+}
+
+// InsertCwe :
+func (d *cacheDriver) InsertCwe(catalog *models.CweCatalogXML) error {
+	return d.purgeAfter(d.DB.InsertCwe(catalog))
+}
+
+// UpsertRedhat :
+func (d *cacheDriver) UpsertRedhat(cves []models.RedhatCVEJSON) error {
+	return d.purgeAfter(d.DB.UpsertRedhat(cves))
+}
+
+// UpsertDebian :
+func (d *cacheDriver) UpsertDebian(cves models.DebianJSON, advisories []models.DebianAdvisoryJSON) error {
+	return d.purgeAfter(d.DB.UpsertDebian(cves, advisories))
+}
+
+// UpsertUbuntu :
+func (d *cacheDriver) UpsertUbuntu(cves []models.UbuntuCVEJSON) error {
+	return d.purgeAfter(d.DB.UpsertUbuntu(cves))
+}
+
+// UpsertMicrosoft :
+func (d *cacheDriver) UpsertMicrosoft(cves []models.MicrosoftXML) error {
+	return d.purgeAfter(d.DB.UpsertMicrosoft(cves))
+}
+
+// RemoveMissing :
+func (d *cacheDriver) RemoveMissing(seen *SeenIDs, removedAt time.Time) (map[string][]string, error) {
+	removed, err := d.DB.RemoveMissing(seen, removedAt)
+	d.cache.Purge()
+	return removed, err
+}
@@ -0,0 +1,51 @@
+package db
+
+import "strings"
+
+// Channels of the extended support
+const (
+	// ChannelUbuntuESMInfra : Ubuntu Pro esm-infra (main)
+	ChannelUbuntuESMInfra = "esm-infra"
+	// ChannelUbuntuESMApps : Ubuntu Pro esm-apps (universe)
+	ChannelUbuntuESMApps = "esm-apps"
+	// ChannelDebianELTS : Debian Extended LTS by Freexian
+	ChannelDebianELTS = "elts"
+)
+
+// isKnownCodename returns whether the name is a codename of Debian or Ubuntu
+func isKnownCodename(name string) bool {
+	distroVerCodename.RLock()
+	defer distroVerCodename.RUnlock()
+	for _, verCodename := range distroVerCodename.m {
+		for _, codename := range verCodename {
+			if name == codename {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitReleaseChannel splits the release of the tracker into the codename and the channel of the extended support.
+// e.g. esm-infra/xenial => (xenial, esm-infra), trusty/esm => (trusty, esm), buster/elts => (buster, elts), focal => (focal, "")
+func splitReleaseChannel(release string) (string, string) {
+	ss := strings.SplitN(release, "/", 2)
+	if len(ss) != 2 {
+		return release, ""
+	}
+	if isKnownCodename(ss[0]) {
+		return ss[0], ss[1]
+	}
+	return ss[1], ss[0]
+}
+
+// effectiveChannel returns the channel whose status applies to the subscriber of the channels,
+// among the channels of which the package has the status in a release: the first of the channels found, otherwise the standard one.
+func effectiveChannel(found map[string]bool, channels []string) string {
+	for _, c := range channels {
+		if found[c] {
+			return c
+		}
+	}
+	return ""
+}
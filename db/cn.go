@@ -0,0 +1,221 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+const cnvdURLFormat = "https://www.cnvd.org.cn/flaw/show/%s"
+
+// cnSeverities translates the severities of CNNVD (超危, 高危, 中危, 低危) and CNVD (高, 中, 低)
+var cnSeverities = map[string]string{
+	"超危": "critical",
+	"高危": "high",
+	"高":  "high",
+	"中危": "medium",
+	"中":  "medium",
+	"低危": "low",
+	"低":  "low",
+}
+
+// NormalizeCnSeverity returns the severity of CNNVD or CNVD as critical, high, medium or low, so that it is compared with the other sources.
+// It returns empty for an unknown severity.
+func NormalizeCnSeverity(severity string) string {
+	severity = strings.TrimSpace(severity)
+	if s, ok := cnSeverities[severity]; ok {
+		return s
+	}
+	if _, ok := severityRanks[strings.ToLower(severity)]; ok {
+		return strings.ToLower(severity)
+	}
+	return ""
+}
+
+// GetCn :
+func (r *RDBDriver) GetCn(source, advisoryID string) *models.CnAdvisory {
+	a := models.CnAdvisory{}
+	err := r.conn.
+		Preload("CveIDs").
+		Preload("Products").
+		Where(&models.CnAdvisory{Source: source, AdvisoryID: advisoryID}).
+		First(&a).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get CNNVD/CNVD advisory", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetCnByCveID gets the advisories of CNNVD and CNVD linked to the CVE
+func (r *RDBDriver) GetCnByCveID(cveID string) map[string]models.CnAdvisory {
+	m := map[string]models.CnAdvisory{}
+	cves := []models.CnCve{}
+	err := r.conn.Where(&models.CnCve{CveID: cveID}).Find(&cves).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get CNNVD/CNVD advisories by CVE-ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(cves))
+	for _, c := range cves {
+		a := models.CnAdvisory{}
+		err := r.conn.
+			Preload("CveIDs").
+			Preload("Products").
+			Where(&models.CnAdvisory{ID: c.CnAdvisoryID}).
+			First(&a).Error
+		if err != nil {
+			r.log.Error("Failed to get CNNVD/CNVD advisories by CVE-ID", "err", err)
+			return nil
+		}
+		m[a.AdvisoryID] = a
+	}
+	return m
+}
+
+// InsertCn replaces the advisories of the source (cnnvd or cnvd)
+func (r *RDBDriver) InsertCn(source string, advisories []models.CnAdvisory) (err error) {
+	advisories = r.filter.filterCn(advisories)
+	if err = r.deleteAndInsertCn(r.conn, source, advisories); err != nil {
+		return xerrors.Errorf("Failed to insert CNNVD/CNVD advisories. source: %s, err: %s", source, err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertCn(conn *gorm.DB, source string, advisories []models.CnAdvisory) (err error) {
+	bar := startProgress(r.insert.Progress, len(advisories))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete the old records of the source
+	ids := []int64{}
+	if err = tx.Model(&models.CnAdvisory{}).Where("source = ?", source).Pluck("id", &ids).Error; err != nil {
+		return xerrors.Errorf("Failed to get old records. err: %w", err)
+	}
+	for idx := range chunkSlice(len(ids), 1000) {
+		var errs util.Errors
+		errs = errs.Add(tx.Where("cn_advisory_id IN ?", ids[idx.From:idx.To]).Delete(models.CnCve{}).Error)
+		errs = errs.Add(tx.Where("cn_advisory_id IN ?", ids[idx.From:idx.To]).Delete(models.CnProduct{}).Error)
+		errs = errs.Add(tx.Where("id IN ?", ids[idx.From:idx.To]).Delete(models.CnAdvisory{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+		}
+	}
+
+	for idx := range chunkSlice(len(advisories), r.insert.BatchSize) {
+		if err = tx.Create(advisories[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertCnnvd converts the entries of CNNVD. An entry in several feeds is kept once, with the one modified last
+func ConvertCnnvd(entries []models.CnnvdEntryXML, warnings *util.Warnings) (advisories []models.CnAdvisory) {
+	indexes := map[string]int{}
+	for _, e := range entries {
+		id := strings.TrimSpace(e.VulnID)
+		if id == "" {
+			continue
+		}
+		a := models.CnAdvisory{
+			Source:           models.CnSourceCnnvd,
+			AdvisoryID:       id,
+			Title:            strings.TrimSpace(e.Name),
+			Description:      strings.TrimSpace(e.VulnDescript),
+			Solution:         strings.TrimSpace(e.VulnSolution),
+			Severity:         NormalizeCnSeverity(e.Severity),
+			OriginalSeverity: strings.TrimSpace(e.Severity),
+			PublishedDate:    parseCnDate(models.CnSourceCnnvd, id, "published", e.Published, warnings),
+			LastModifiedDate: parseCnDate(models.CnSourceCnnvd, id, "modified", e.Modified, warnings),
+		}
+		if cveID := strings.TrimSpace(e.CveID); strings.HasPrefix(cveID, "CVE-") {
+			a.CveIDs = []models.CnCve{{CveID: cveID}}
+		}
+		advisories = appendCnAdvisory(advisories, indexes, a)
+	}
+	return advisories
+}
+
+// ConvertCnvd converts the vulnerabilities of CNVD. The last modified date is the open time, since CNVD has no modified date
+func ConvertCnvd(vulns []models.CnvdVulnerabilityXML, warnings *util.Warnings) (advisories []models.CnAdvisory) {
+	indexes := map[string]int{}
+	for _, v := range vulns {
+		id := strings.TrimSpace(v.Number)
+		if id == "" {
+			continue
+		}
+		a := models.CnAdvisory{
+			Source:           models.CnSourceCnvd,
+			AdvisoryID:       id,
+			Title:            strings.TrimSpace(v.Title),
+			Description:      strings.TrimSpace(v.Description),
+			Solution:         strings.TrimSpace(v.FormalWay),
+			Severity:         NormalizeCnSeverity(v.Serverity),
+			OriginalSeverity: strings.TrimSpace(v.Serverity),
+			URL:              fmt.Sprintf(cnvdURLFormat, id),
+			PublishedDate:    parseCnDate(models.CnSourceCnvd, id, "openTime", v.OpenTime, warnings),
+			LastModifiedDate: parseCnDate(models.CnSourceCnvd, id, "openTime", v.OpenTime, warnings),
+		}
+		uniqCveID := map[string]struct{}{}
+		for _, cveID := range v.CveIDs {
+			cveID = strings.TrimSpace(cveID)
+			if _, ok := uniqCveID[cveID]; ok || !strings.HasPrefix(cveID, "CVE-") {
+				continue
+			}
+			uniqCveID[cveID] = struct{}{}
+			a.CveIDs = append(a.CveIDs, models.CnCve{CveID: cveID})
+		}
+		for _, p := range v.Products {
+			if p = strings.TrimSpace(p); p != "" {
+				a.Products = append(a.Products, models.CnProduct{Product: p})
+			}
+		}
+		advisories = appendCnAdvisory(advisories, indexes, a)
+	}
+	return advisories
+}
+
+// appendCnAdvisory appends the advisory, or replaces the same advisory if it is modified later
+func appendCnAdvisory(advisories []models.CnAdvisory, indexes map[string]int, a models.CnAdvisory) []models.CnAdvisory {
+	i, ok := indexes[a.AdvisoryID]
+	if !ok {
+		indexes[a.AdvisoryID] = len(advisories)
+		return append(advisories, a)
+	}
+	if !a.LastModifiedDate.Before(advisories[i].LastModifiedDate) {
+		advisories[i] = a
+	}
+	return advisories
+}
+
+// parseCnDate parses the date of CNNVD and CNVD (e.g. 2021-12-10)
+func parseCnDate(source, advisoryID, field, date string, warnings *util.Warnings) time.Time {
+	if strings.TrimSpace(date) == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, "2006-01-02", "2006-01-02 15:04:05")
+	if err != nil {
+		warnings.Add(source, advisoryID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
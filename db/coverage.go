@@ -0,0 +1,65 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/knqyf263/gost/models"
+)
+
+// CoverageSources returns the sources compared with NVD by GetCoverage
+func CoverageSources() []string {
+	sources := []string{}
+	for s := range cveIDColumns {
+		if s == "nvd" {
+			continue
+		}
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// CveIDsGetter gets all the CVE-IDs of a source. It is DB, or the client of gost server in the remote mode
+type CveIDsGetter interface {
+	GetCveIDs(source string) ([]string, error)
+}
+
+// GetCoverage compares the CVE-IDs in NVD with the CVE-IDs stored for each source.
+// covered has the sources having a statement for each CVE in NVD.
+func GetCoverage(driver CveIDsGetter, nvdCveIDs []string) (report models.CoverageReport, covered map[string][]string, err error) {
+	nvd := map[string]struct{}{}
+	for _, id := range nvdCveIDs {
+		nvd[id] = struct{}{}
+	}
+
+	covered = map[string][]string{}
+	report = models.CoverageReport{NVDCount: len(nvd), Sources: []models.CoverageSource{}, Uncovered: []string{}}
+	for _, source := range CoverageSources() {
+		cveIDs, err := driver.GetCveIDs(source)
+		if err != nil {
+			return models.CoverageReport{}, nil, err
+		}
+
+		s := models.CoverageSource{Source: source}
+		for _, id := range cveIDs {
+			if _, ok := nvd[id]; !ok {
+				s.NotInNVD++
+				continue
+			}
+			s.Covered++
+			covered[id] = append(covered[id], source)
+		}
+		if len(nvd) > 0 {
+			s.Ratio = float64(s.Covered) / float64(len(nvd))
+		}
+		report.Sources = append(report.Sources, s)
+	}
+
+	for id := range nvd {
+		if _, ok := covered[id]; !ok {
+			report.Uncovered = append(report.Uncovered, id)
+		}
+	}
+	sort.Strings(report.Uncovered)
+	return report, covered, nil
+}
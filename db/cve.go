@@ -0,0 +1,156 @@
+package db
+
+import (
+	"github.com/knqyf263/gost/models"
+)
+
+// GetCveByID gets the records of the CVE in all the sources.
+// The tables of the sources have no common key to JOIN, so they are queried in turn.
+func (r *RDBDriver) GetCveByID(cveID string) *models.CveDetail {
+	d := models.CveDetail{CveID: cveID}
+	if c := r.GetRedhat(cveID); c != nil && c.Name != "" {
+		d.RedHat = c
+	}
+	if c := r.GetDebian(cveID); c != nil && c.CveID != "" {
+		d.Debian = c
+	}
+	if c := r.GetUbuntu(cveID); c != nil && c.Candidate != "" {
+		d.Ubuntu = c
+	}
+	if c := r.GetMicrosoft(cveID); c != nil && c.CveID != "" {
+		d.Microsoft = c
+	}
+	if c := r.GetAmazon(cveID); c != nil && c.CveID != "" {
+		d.Amazon = c
+	}
+	if c := r.GetAlpine(cveID); c != nil && c.CveID != "" {
+		d.Alpine = c
+	}
+	if c := r.GetWolfi(cveID); c != nil && c.CveID != "" {
+		d.Wolfi = c
+	}
+	if c := r.GetOracle(cveID); c != nil && c.CveID != "" {
+		d.Oracle = c
+	}
+	if c := r.GetRocky(cveID); c != nil && c.CveID != "" {
+		d.Rocky = c
+	}
+	if c := r.GetAlma(cveID); c != nil && c.CveID != "" {
+		d.Alma = c
+	}
+	if c := r.GetPhoton(cveID); c != nil && c.CveID != "" {
+		d.Photon = c
+	}
+	if c := r.GetMariner(cveID); c != nil && c.CveID != "" {
+		d.Mariner = c
+	}
+	if c := r.GetOpenEuler(cveID); c != nil && c.CveID != "" {
+		d.OpenEuler = c
+	}
+	if c := r.GetAnolis(cveID); c != nil && c.CveID != "" {
+		d.Anolis = c
+	}
+	if c := r.GetNvd(cveID); c != nil && c.CveID != "" {
+		d.Nvd = c
+	}
+	if c := r.GetCveProgram(cveID); c != nil && c.CveID != "" {
+		d.CveProgram = c
+	}
+	if c := r.GetKEV(cveID); c != nil && c.CveID != "" {
+		d.KEV = c
+	}
+	if c := r.GetEpss(cveID); c != nil && c.CveID != "" {
+		d.Epss = c
+	}
+	if es := r.GetExploits(cveID); len(es) > 0 {
+		d.Exploits = es
+	}
+	return &d
+}
+
+// CveSources are the sources of CveDetail in the order of the fields
+var CveSources = []string{"redhat", "debian", "ubuntu", "microsoft", "amazon", "alpine", "wolfi", "oracle", "rocky", "alma", "photon", "mariner", "openeuler", "anolis", "nvd", "cveprogram", "kev", "epss", "exploits"}
+
+// GetCveOfSource gets the record of the CVE in the source of CveSources, so that a caller needing a few sources does not query all of them.
+// It returns nil if the source does not have the CVE.
+func GetCveOfSource(driver DB, source, cveID string) interface{} {
+	switch source {
+	case "redhat":
+		if c := driver.GetRedhat(cveID); c != nil && c.Name != "" {
+			return c
+		}
+	case "debian":
+		if c := driver.GetDebian(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "ubuntu":
+		if c := driver.GetUbuntu(cveID); c != nil && c.Candidate != "" {
+			return c
+		}
+	case "microsoft":
+		if c := driver.GetMicrosoft(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "amazon":
+		if c := driver.GetAmazon(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "alpine":
+		if c := driver.GetAlpine(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "wolfi":
+		if c := driver.GetWolfi(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "oracle":
+		if c := driver.GetOracle(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "rocky":
+		if c := driver.GetRocky(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "alma":
+		if c := driver.GetAlma(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "photon":
+		if c := driver.GetPhoton(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "mariner":
+		if c := driver.GetMariner(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "openeuler":
+		if c := driver.GetOpenEuler(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "anolis":
+		if c := driver.GetAnolis(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "nvd":
+		if c := driver.GetNvd(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "cveprogram":
+		if c := driver.GetCveProgram(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "kev":
+		if c := driver.GetKEV(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "epss":
+		if c := driver.GetEpss(cveID); c != nil && c.CveID != "" {
+			return c
+		}
+	case "exploits":
+		if es := driver.GetExploits(cveID); len(es) > 0 {
+			return es
+		}
+	}
+	return nil
+}
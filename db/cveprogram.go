@@ -0,0 +1,188 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetCveProgram :
+func (r *RDBDriver) GetCveProgram(cveID string) *models.CveProgramCVE {
+	c := models.CveProgramCVE{}
+	if err := r.conn.Where(&models.CveProgramCVE{CveID: cveID}).First(&c).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get CVE Program", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetCveProgramFetchTime returns the fetchTime of the latest delta of cvelistV5 applied. It is zero if cvelistV5 has not been fetched
+func (r *RDBDriver) GetCveProgramFetchTime() (time.Time, error) {
+	s := models.CveProgramSync{}
+	if err := r.conn.First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, xerrors.Errorf("Failed to get the fetch time of cvelistV5. err: %w", err)
+	}
+	return s.FetchTime, nil
+}
+
+// CveRecordReader reads the CVE records of cvelistV5 in batches (e.g. fetcher.CveList), not to hold all of them in memory
+type CveRecordReader interface {
+	// Len returns the number of the CVE records
+	Len() int
+	// Next returns the next n CVE records, and none after all of them are read
+	Next(n int) ([]models.CveRecordJSON, error)
+}
+
+// cveRecordReadSize is the number of the CVE records read and converted at once by InsertCveProgram
+const cveRecordReadSize = 1000
+
+// InsertCveProgram replaces all CVE records by the ones of cvelistV5 fetched at fetchTime, read from records in batches.
+// The old records are kept if it fails, since all the batches are inserted in a transaction.
+func (r *RDBDriver) InsertCveProgram(records CveRecordReader, fetchTime time.Time) (err error) {
+	bar := startProgress(r.insert.Progress, records.Len())
+	tx := r.conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			err = xerrors.Errorf("Failed to insert CVE Program data. err: %s", err)
+			return
+		}
+		tx.Commit()
+	}()
+
+	if err = tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.CveProgramCVE{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old records. err: %s", err)
+	}
+	for {
+		batch, rerr := records.Next(cveRecordReadSize)
+		if rerr != nil {
+			return rerr
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if err = r.createCveProgram(tx, r.filter.filterCveProgram(ConvertCveProgram(batch, r.warnings))); err != nil {
+			return err
+		}
+		bar.Add(len(batch))
+	}
+	if err = replaceCveProgramFetchTime(tx, fetchTime); err != nil {
+		return err
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// UpsertCveProgram replaces the CVE records in the delta of cvelistV5, and keeps the others
+func (r *RDBDriver) UpsertCveProgram(records []models.CveRecordJSON, fetchTime time.Time) (err error) {
+	cves := r.filter.filterCveProgram(ConvertCveProgram(records, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := r.conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			err = xerrors.Errorf("Failed to upsert CVE Program data. err: %s", err)
+			return
+		}
+		tx.Commit()
+	}()
+
+	cveIDs := make([]string, 0, len(cves))
+	for _, c := range cves {
+		cveIDs = append(cveIDs, c.CveID)
+	}
+	for idx := range chunkSlice(len(cveIDs), r.insert.BatchSize) {
+		if err = tx.Where("cve_id IN ?", cveIDs[idx.From:idx.To]).Delete(models.CveProgramCVE{}).Error; err != nil {
+			return xerrors.Errorf("Failed to delete old records. err: %s", err)
+		}
+	}
+	if err = r.createCveProgram(tx, cves); err != nil {
+		return err
+	}
+	bar.Add(len(cves))
+	if err = replaceCveProgramFetchTime(tx, fetchTime); err != nil {
+		return err
+	}
+	bar.Finish()
+
+	return nil
+}
+
+func (r *RDBDriver) createCveProgram(tx *gorm.DB, cves []models.CveProgramCVE) error {
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err := tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+	}
+	return nil
+}
+
+func replaceCveProgramFetchTime(tx *gorm.DB, fetchTime time.Time) error {
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.CveProgramSync{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete the old fetch time. err: %s", err)
+	}
+	if err := tx.Create(&models.CveProgramSync{FetchTime: fetchTime}).Error; err != nil {
+		return xerrors.Errorf("Failed to insert the fetch time. err: %w", err)
+	}
+	return nil
+}
+
+// ConvertCveProgram converts the CVE records of cvelistV5. The records without CVE-ID are skipped
+func ConvertCveProgram(records []models.CveRecordJSON, warnings *util.Warnings) (cves []models.CveProgramCVE) {
+	for _, r := range records {
+		m := r.CveMetadata
+		if m.CveID == "" {
+			continue
+		}
+		cve := models.CveProgramCVE{
+			CveID:             m.CveID,
+			State:             m.State,
+			AssignerShortName: m.AssignerShortName,
+			Title:             r.Containers.Cna.Title,
+			DateReserved:      parseCveProgramDate(m.CveID, "dateReserved", m.DateReserved, warnings),
+			DatePublished:     parseCveProgramDate(m.CveID, "datePublished", m.DatePublished, warnings),
+			DateUpdated:       parseCveProgramDate(m.CveID, "dateUpdated", m.DateUpdated, warnings),
+			DateRejected:      parseCveProgramDate(m.CveID, "dateRejected", m.DateRejected, warnings),
+		}
+		descriptions := r.Containers.Cna.Descriptions
+		if m.State == models.CveStateRejected {
+			descriptions = r.Containers.Cna.RejectedReasons
+		}
+		// the English one is taken. If there is none, the first one is taken
+		for _, d := range descriptions {
+			if d.Lang == "en" || d.Lang == "en-US" {
+				cve.Description = d.Value
+				break
+			}
+			if cve.Description == "" {
+				cve.Description = d.Value
+			}
+		}
+		cves = append(cves, cve)
+	}
+	return cves
+}
+
+// parseCveProgramDate parses the date of CVE JSON 5 format, which has the time zone or not (e.g. 2021-12-10T00:00:00.000Z, 2021-11-26T00:00:00)
+func parseCveProgramDate(cveID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04:05.000", "2006-01-02")
+	if err != nil {
+		warnings.Add("cveprogram", cveID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
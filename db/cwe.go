@@ -0,0 +1,140 @@
+package db
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// e.g. CWE-79, CWE-79->CWE-80 (Red Hat), (CWE-20|CWE-79) (Red Hat), NVD-CWE-Other (NVD, not matched)
+var cweIDRegexp = regexp.MustCompile(`(?i)(?:^|[^A-Z-])CWE-(\d+)`)
+
+// NormalizeCweIDs returns the CWE-IDs in the CWE value of the sources in the form of CWE-79, without duplicates.
+// The chains and the alternatives of Red Hat are split into the CWE-IDs in them.
+func NormalizeCweIDs(cwe string) (cweIDs []string) {
+	uniq := map[string]struct{}{}
+	for _, m := range cweIDRegexp.FindAllStringSubmatch(cwe, -1) {
+		cweID := normalizeCweNumber(m[1])
+		if cweID == "" {
+			continue
+		}
+		if _, ok := uniq[cweID]; ok {
+			continue
+		}
+		uniq[cweID] = struct{}{}
+		cweIDs = append(cweIDs, cweID)
+	}
+	return cweIDs
+}
+
+// NormalizeCweID returns the CWE-ID in the form of CWE-79 from 79, cwe-79 or CWE-079, or empty if it is not a CWE-ID
+func NormalizeCweID(cweID string) string {
+	cweID = strings.TrimSpace(cweID)
+	if len(cweID) > 4 && strings.EqualFold(cweID[:4], "CWE-") {
+		cweID = cweID[4:]
+	}
+	if cweID == "" || strings.TrimLeft(cweID, "0123456789") != "" {
+		return ""
+	}
+	return normalizeCweNumber(cweID)
+}
+
+func normalizeCweNumber(n string) string {
+	if n = strings.TrimLeft(n, "0"); n == "" {
+		return ""
+	}
+	return "CWE-" + n
+}
+
+// GetCwe :
+func (r *RDBDriver) GetCwe(cweID string) *models.Cwe {
+	c := models.Cwe{}
+	err := r.conn.Where(&models.Cwe{CweID: cweID}).First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get CWE", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetCweMulti returns the CWEs of cweIDs. The CWE-IDs not in the CWE list are omitted
+func (r *RDBDriver) GetCweMulti(cweIDs []string) map[string]models.Cwe {
+	m := map[string]models.Cwe{}
+	// split IN clause to stay under the limit of the bind variables
+	for idx := range chunkSlice(len(cweIDs), 500) {
+		cwes := []models.Cwe{}
+		if err := r.conn.Where("cwe_id IN ?", cweIDs[idx.From:idx.To]).Find(&cwes).Error; err != nil {
+			r.log.Error("Failed to get CWE", "err", err)
+			return nil
+		}
+		for _, c := range cwes {
+			m[c.CweID] = c
+		}
+	}
+	return m
+}
+
+// InsertCwe :
+func (r *RDBDriver) InsertCwe(catalog *models.CweCatalogXML) (err error) {
+	if err = r.deleteAndInsertCwe(r.conn, ConvertCwe(catalog)); err != nil {
+		return xerrors.Errorf("Failed to insert CWE list. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertCwe(conn *gorm.DB, cwes []models.Cwe) (err error) {
+	bar := startProgress(r.insert.Progress, len(cwes))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.Cwe{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old records. err: %s", err)
+	}
+
+	for idx := range chunkSlice(len(cwes), r.insert.BatchSize) {
+		if err = tx.Create(cwes[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertCwe converts the weaknesses and the categories of the CWE list. The insert filters are not applied to the dictionary
+func ConvertCwe(catalog *models.CweCatalogXML) (cwes []models.Cwe) {
+	if catalog == nil {
+		return nil
+	}
+	for _, w := range catalog.Weaknesses {
+		cwes = append(cwes, models.Cwe{
+			CweID:       NormalizeCweID(w.ID),
+			Name:        w.Name,
+			Description: strings.Join(strings.Fields(w.Description), " "),
+			Abstraction: w.Abstraction,
+			Status:      w.Status,
+		})
+	}
+	for _, c := range catalog.Categories {
+		cwes = append(cwes, models.Cwe{
+			CweID:       NormalizeCweID(c.ID),
+			Name:        c.Name,
+			Description: strings.Join(strings.Fields(c.Summary), " "),
+			Status:      c.Status,
+		})
+	}
+	return cwes
+}
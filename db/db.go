@@ -6,7 +6,6 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/models"
-	"github.com/spf13/viper"
 	"golang.org/x/xerrors"
 )
 
@@ -16,33 +15,150 @@ type DB interface {
 	OpenDB(string, string, bool) (bool, error)
 	CloseDB() error
 	MigrateDB() error
+	WithExplain(*Explain) DB
+	WithLogger(log15.Logger) DB
+	WithInsertOptions(InsertOptions) DB
 
 	IsGostModelV1() (bool, error)
 	GetFetchMeta() (*models.FetchMeta, error)
 	UpsertFetchMeta(*models.FetchMeta) error
+	GetFetchSources() ([]models.FetchSource, error)
+	UpsertFetchSource(models.FetchSource) error
 
 	GetAfterTimeRedhat(time.Time) ([]models.RedhatCVE, error)
 	GetRedhat(string) *models.RedhatCVE
 	GetRedhatMulti([]string) map[string]models.RedhatCVE
+	GetRedhatByBugzillaID(string) map[string]models.RedhatCVE
 	GetDebian(string) *models.DebianCVE
+	GetDebianByBugID(string) map[string]models.DebianCVE
+	GetAdvisoriesDebian(string) []models.DebianAdvisory
 	GetUbuntu(string) *models.UbuntuCVE
+	GetUbuntuByBugID(string, string) map[string]models.UbuntuCVE
+	GetCveIDsByUSN(string) []string
+	GetAmazon(string) *models.AmazonCVE
+	GetAlpine(string) *models.AlpineCVE
+	GetWolfi(string) *models.WolfiCVE
+	GetOracle(string) *models.OracleCVE
+	GetRocky(string) *models.RockyCVE
+	GetAlma(string) *models.AlmaCVE
+	GetPhoton(string) *models.PhotonCVE
+	GetMariner(string) *models.MarinerCVE
+	GetOpenEuler(string) *models.OpenEulerCVE
+	GetAnolis(string) *models.AnolisCVE
 	GetMicrosoft(string) *models.MicrosoftCVE
 	GetMicrosoftMulti([]string) map[string]models.MicrosoftCVE
+	GetMicrosoftCvrfIDs() ([]string, error)
+	GetMicrosoftProducts() ([]models.MicrosoftProduct, error)
+	GetSupersededKBs(string) ([]string, error)
+	GetRequiredKBsForBuild(string, string) ([]models.MicrosoftKBBuild, error)
+	GetNvd(string) *models.NvdCVE
+	GetCveProgram(string) *models.CveProgramCVE
+	GetCveProgramFetchTime() (time.Time, error)
+	GetKernelCve(string) *models.KernelCVE
+	GetKernelCves() map[string]models.KernelCVE
+	GetDistroReleases() ([]models.DistroRelease, error)
+	GetKEV(string) *models.KEVEntry
+	GetKnownExploited([]string) map[string]bool
+	GetEpss(string) *models.EpssScore
+	GetEpssMulti([]string) map[string]models.EpssScore
+	GetExploits(string) []models.Exploit
+	GetExploitsMulti([]string) map[string][]models.Exploit
+	GetCwe(string) *models.Cwe
+	GetCweMulti([]string) map[string]models.Cwe
+	GetGhsa(string) *models.GhsaAdvisory
+	GetGhsaByCveID(string) map[string]models.GhsaAdvisory
+	GetGhsaByPackage(string, string) map[string]models.GhsaAdvisory
+	GetOsvByPackage(string, string) map[string]models.OsvEntry
+	GetGoVulnsByModule(string) map[string]models.GoVuln
+	GetJvn(string) *models.JvnAdvisory
+	GetJvnByCveID(string) map[string]models.JvnAdvisory
+	GetPsirt(string, string) *models.PsirtAdvisory
+	GetPsirtByCveID(string) map[string]models.PsirtAdvisory
+	GetPsirtByProduct(string, string) map[string]models.PsirtAdvisory
+	GetApple(string) *models.AppleSecurityRelease
+	GetAppleByCveID(string) map[string]models.AppleSecurityRelease
+	GetAppleByProduct(string) map[string]models.AppleSecurityRelease
+	GetCn(string, string) *models.CnAdvisory
+	GetCnByCveID(string) map[string]models.CnAdvisory
+	GetPackageAdvisory(string, string) *models.PackageAdvisory
+	GetPackageAdvisoriesByCveID(string) map[string]models.PackageAdvisory
+	GetPackageAdvisoriesByPackage(string, string) map[string]models.PackageAdvisory
+	GetCveByID(string) *models.CveDetail
+	GetPackageNames(string, string, string) ([]string, error)
+	GetRaw(string, string) ([]byte, error)
+	GetCveIDs(string) ([]string, error)
+	ForEachCveID(string, func(string) error) error
 	GetUnfixedCvesRedhat(string, string, bool) map[string]models.RedhatCVE
-	GetUnfixedCvesDebian(string, string) map[string]models.DebianCVE
-	GetFixedCvesDebian(string, string) map[string]models.DebianCVE
-	GetUnfixedCvesUbuntu(string, string) map[string]models.UbuntuCVE
-	GetFixedCvesUbuntu(string, string) map[string]models.UbuntuCVE
+	GetUnfixedCvesRedhatMulti(string, []string, bool) map[string]map[string]models.RedhatCVE
+	GetFixedCvesRedhat(string, string) map[string]models.RedhatCVE
+	GetUnfixedCvesDebian(string, string, ...string) map[string]models.DebianCVE
+	GetUnfixedCvesDebianMulti(string, []string, ...string) map[string]map[string]models.DebianCVE
+	GetFixedCvesDebian(string, string, ...string) map[string]models.DebianCVE
+	GetUnfixedCvesUbuntu(string, string, ...string) map[string]models.UbuntuCVE
+	GetUnfixedCvesUbuntuMulti(string, []string, ...string) map[string]map[string]models.UbuntuCVE
+	GetFixedCvesUbuntu(string, string, ...string) map[string]models.UbuntuCVE
+	GetUnfixedCvesUbuntuSnap(string) map[string]models.UbuntuCVE
+	GetUnfixedCvesAmazon(string, string) map[string]models.AmazonCVE
+	GetFixedCvesAmazon(string, string) map[string]models.AmazonCVE
+	GetFixedCvesAlpine(string, string) map[string]models.AlpineCVE
+	GetUnfixedCvesWolfi(string, string) map[string]models.WolfiCVE
+	GetFixedCvesWolfi(string, string) map[string]models.WolfiCVE
+	GetFixedCvesOracle(string, string) map[string]models.OracleCVE
+	GetFixedCvesRocky(string, string) map[string]models.RockyCVE
+	GetFixedCvesAlma(string, string) map[string]models.AlmaCVE
+	GetFixedCvesPhoton(string, string) map[string]models.PhotonCVE
+	GetFixedCvesMariner(string, string) map[string]models.MarinerCVE
+	GetFixedCvesOpenEuler(string, string) map[string]models.OpenEulerCVE
+	GetFixedCvesAnolis(string, string) map[string]models.AnolisCVE
 
 	InsertRedhat([]models.RedhatCVEJSON) error
-	InsertDebian(models.DebianJSON) error
+	InsertRedhatOVAL([]models.RedhatOVALXML) error
+	InsertDebian(models.DebianJSON, []models.DebianAdvisoryJSON) error
 	InsertUbuntu([]models.UbuntuCVEJSON) error
+	InsertUbuntuUSN([]models.UbuntuUSNJSON) error
+	InsertAmazon([]models.AmazonALASJSON) error
+	InsertAlpine([]models.AlpineSecDBJSON) error
+	InsertWolfi([]models.OsvJSON) error
+	InsertOracle([]models.OracleOVALJSON) error
+	InsertRocky([]models.RockyAdvisoryJSON) error
+	InsertAlma([]models.AlmaErrataJSON) error
+	InsertPhoton([]models.PhotonCVEJSON) error
+	InsertMariner([]models.MarinerOVALXML) error
+	InsertOpenEuler([]models.OpenEulerCVRFXML) error
+	InsertAnolis([]models.AnolisOVALXML) error
 	InsertMicrosoft([]models.MicrosoftXML, []models.MicrosoftBulletinSearch) error
+	InsertNvd([]models.NvdCVEJSON) error
+	InsertCveProgram(CveRecordReader, time.Time) error
+	InsertKernelCves([]models.KernelCveJSON) error
+	InsertDistroReleases([]models.DistroInfoCSV) error
+	InsertKEV([]models.KEVEntryJSON) error
+	InsertEpss(*models.EpssCSV) error
+	InsertExploit([]models.ExploitDBCSV, []models.MetasploitModuleJSON) error
+	InsertCwe(*models.CweCatalogXML) error
+	InsertGhsa([]models.GhsaVulnerabilityJSON) error
+	InsertOsv([]models.OsvJSON) error
+	InsertGoVuln([]models.GoVulnJSON) error
+	InsertJvn([]models.JvnItemXML) error
+	InsertPsirt(string, []models.PsirtAdvisory) error
+	InsertApple([]models.AppleSecurityRelease) error
+	InsertCn(string, []models.CnAdvisory) error
+	InsertPackageAdvisories(string, []models.PackageAdvisory) error
+
+	UpsertRedhat([]models.RedhatCVEJSON) error
+	UpsertDebian(models.DebianJSON, []models.DebianAdvisoryJSON) error
+	UpsertUbuntu([]models.UbuntuCVEJSON) error
+	UpsertMicrosoft([]models.MicrosoftXML) error
+	UpsertCveProgram([]models.CveRecordJSON, time.Time) error
+
+	Reindex() (models.ReindexReport, error)
+	RemoveMissing(*SeenIDs, time.Time) (map[string][]string, error)
+	GetTombstones(time.Time) ([]models.Tombstone, error)
 }
 
 // NewDB returns db driver
-func NewDB(dbType, dbPath string, debugSQL bool) (driver DB, locked bool, err error) {
-	if driver, err = newDB(dbType); err != nil {
+func NewDB(dbType, dbPath string, debugSQL bool, opts ...Option) (driver DB, locked bool, err error) {
+	o := newOptions(opts...)
+	if driver, err = newDB(dbType, o); err != nil {
 		log15.Error("Failed to new db.", "err", err)
 		return driver, false, err
 	}
@@ -68,15 +184,19 @@ func NewDB(dbType, dbPath string, debugSQL bool) (driver DB, locked bool, err er
 		log15.Error("Failed to migrate db.", "err", err)
 		return driver, false, err
 	}
-	return driver, false, nil
+	loadDistroReleases(driver)
+	if o.cveCache != nil {
+		return &cacheDriver{DB: &enrichDriver{DB: driver}, cache: o.cveCache}, false, nil
+	}
+	return &enrichDriver{DB: driver}, false, nil
 }
 
-func newDB(dbType string) (DB, error) {
+func newDB(dbType string, o options) (DB, error) {
 	switch dbType {
 	case dialectSqlite3, dialectMysql, dialectPostgreSQL:
-		return &RDBDriver{name: dbType, batchSize: viper.GetInt("batch-size")}, nil
+		return &RDBDriver{name: dbType, log: log15.Root(), insert: o.insert, filter: o.filter, queryTimeout: o.queryTimeout, slowQueryThreshold: o.slowQueryThreshold, breaker: o.breaker, sqliteWAL: o.sqliteWAL, warnings: o.warnings}, nil
 	case dialectRedis:
-		return &RedisDriver{name: dbType}, nil
+		return &RedisDriver{name: dbType, log: log15.Root(), insert: o.insert, filter: o.filter, multiGet: o.multiGet, queryTimeout: o.queryTimeout, slowQueryThreshold: o.slowQueryThreshold, breaker: o.breaker, requirePersistence: o.requirePersistence, indexLayout: o.indexLayout, documentSizes: o.documentSizes, seenIDs: o.seenIDs, warnings: o.warnings}, nil
 	}
 	return nil, fmt.Errorf("Invalid database dialect. err: %s", dbType)
 }
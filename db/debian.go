@@ -3,11 +3,11 @@ package db
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
-	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/models"
 	"github.com/knqyf263/gost/util"
-	pb "gopkg.in/cheggaaa/pb.v1"
 	"gorm.io/gorm"
 )
 
@@ -16,12 +16,12 @@ func (r *RDBDriver) GetDebian(cveID string) *models.DebianCVE {
 	c := models.DebianCVE{}
 	err := r.conn.Where(&models.DebianCVE{CveID: cveID}).First(&c).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log15.Error("Failed to get Debian", "err", err)
+		r.log.Error("Failed to get Debian", "err", err)
 		return nil
 	}
 	err = r.conn.Model(&c).Association("Package").Find(&c.Package)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log15.Error("Failed to get Debian", "err", err)
+		r.log.Error("Failed to get Debian", "err", err)
 		return nil
 	}
 
@@ -29,25 +29,86 @@ func (r *RDBDriver) GetDebian(cveID string) *models.DebianCVE {
 	for _, pkg := range c.Package {
 		err = r.conn.Model(&pkg).Association("Release").Find(&pkg.Release)
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			log15.Error("Failed to get Debian", "err", err)
+			r.log.Error("Failed to get Debian", "err", err)
 			return nil
 		}
 		newPkg = append(newPkg, pkg)
 	}
 	c.Package = newPkg
+
+	err = r.conn.Model(&c).Association("Advisories").Find(&c.Advisories)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Debian", "err", err)
+		return nil
+	}
 	return &c
 }
 
+// GetAdvisoriesDebian gets the DSA/DLA which fix the CVE
+func (r *RDBDriver) GetAdvisoriesDebian(cveID string) []models.DebianAdvisory {
+	c := models.DebianCVE{}
+	if err := r.conn.Select("id").Where(&models.DebianCVE{CveID: cveID}).First(&c).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get Debian advisories", "err", err)
+			return nil
+		}
+		return []models.DebianAdvisory{}
+	}
+
+	advisories := []models.DebianAdvisory{}
+	if err := r.conn.Where(&models.DebianAdvisory{DebianCVEID: c.ID}).Find(&advisories).Error; err != nil {
+		r.log.Error("Failed to get Debian advisories", "err", err)
+		return nil
+	}
+	return advisories
+}
+
+// GetDebianByBugID gets the CVEs related to the Debian BTS bug number
+func (r *RDBDriver) GetDebianByBugID(bugID string) map[string]models.DebianCVE {
+	m := map[string]models.DebianCVE{}
+
+	type Result struct {
+		DebianCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("debian_packages").
+		Select("debian_cve_id").
+		Where("debian_bug = ?", bugID).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Debian by bug ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		c := models.DebianCVE{}
+		if err := r.conn.Select("cve_id").Where(&models.DebianCVE{ID: res.DebianCveID}).First(&c).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				r.log.Error("Failed to get Debian by bug ID", "err", err)
+				return nil
+			}
+			continue
+		}
+		if deb := r.GetDebian(c.CveID); deb != nil {
+			m[c.CveID] = *deb
+		}
+	}
+	return m
+}
+
 // InsertDebian :
-func (r *RDBDriver) InsertDebian(cveJSON models.DebianJSON) (err error) {
-	cves := ConvertDebian(cveJSON)
+func (r *RDBDriver) InsertDebian(cveJSON models.DebianJSON, advisoryJSONs []models.DebianAdvisoryJSON) (err error) {
+	cves := r.filter.filterDebian(ConvertDebian(cveJSON, advisoryJSONs, r.warnings))
 	if err = r.deleteAndInsertDebian(r.conn, cves); err != nil {
 		return fmt.Errorf("Failed to insert Debian CVE data. err: %s", err)
 	}
 	return nil
 }
 func (r *RDBDriver) deleteAndInsertDebian(conn *gorm.DB, cves []models.DebianCVE) (err error) {
-	bar := pb.StartNew(len(cves))
+	bar := startProgress(r.insert.Progress, len(cves))
 	tx := conn.Begin()
 
 	defer func() {
@@ -60,6 +121,7 @@ func (r *RDBDriver) deleteAndInsertDebian(conn *gorm.DB, cves []models.DebianCVE
 
 	// Delete all old records
 	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.DebianAdvisory{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.DebianRelease{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.DebianPackage{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.DebianCVE{}).Error)
@@ -69,7 +131,7 @@ func (r *RDBDriver) deleteAndInsertDebian(conn *gorm.DB, cves []models.DebianCVE
 		return fmt.Errorf("Failed to delete old records. err: %s", errs.Error())
 	}
 
-	for idx := range chunkSlice(len(cves), r.batchSize) {
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
 		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
 			return fmt.Errorf("Failed to insert. err: %s", err)
 		}
@@ -80,19 +142,79 @@ func (r *RDBDriver) deleteAndInsertDebian(conn *gorm.DB, cves []models.DebianCVE
 	return nil
 }
 
+// UpsertDebian replaces only the given CVEs and leaves the others as they are
+func (r *RDBDriver) UpsertDebian(cveJSON models.DebianJSON, advisoryJSONs []models.DebianAdvisoryJSON) (err error) {
+	cves := r.filter.filterDebian(ConvertDebian(cveJSON, advisoryJSONs, r.warnings))
+	if err = r.deleteAndUpsertDebian(r.conn, cves); err != nil {
+		return fmt.Errorf("Failed to upsert Debian CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndUpsertDebian(conn *gorm.DB, cves []models.DebianCVE) (err error) {
+	if len(cves) == 0 {
+		return nil
+	}
+
+	tx := conn.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	cveIDs := []string{}
+	for _, c := range cves {
+		cveIDs = append(cveIDs, c.CveID)
+	}
+	ids := []int64{}
+	if err = tx.Model(&models.DebianCVE{}).Where("cve_id IN ?", cveIDs).Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("Failed to get old records. err: %s", err)
+	}
+
+	// Delete old records of the given CVEs
+	if len(ids) > 0 {
+		pkgIDs := []int64{}
+		var errs util.Errors
+		errs = errs.Add(tx.Model(&models.DebianPackage{}).Where("debian_cve_id IN ?", ids).Pluck("id", &pkgIDs).Error)
+		if len(pkgIDs) > 0 {
+			errs = errs.Add(tx.Where("debian_package_id IN ?", pkgIDs).Delete(models.DebianRelease{}).Error)
+		}
+		errs = errs.Add(tx.Where("debian_cve_id IN ?", ids).Delete(models.DebianAdvisory{}).Error)
+		errs = errs.Add(tx.Where("debian_cve_id IN ?", ids).Delete(models.DebianPackage{}).Error)
+		errs = errs.Add(tx.Where("id IN ?", ids).Delete(models.DebianCVE{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return fmt.Errorf("Failed to delete old records. err: %s", errs.Error())
+		}
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return fmt.Errorf("Failed to insert. err: %s", err)
+		}
+	}
+	return nil
+}
+
 // ConvertDebian :
-func ConvertDebian(cveJSONs models.DebianJSON) (cves []models.DebianCVE) {
+func ConvertDebian(cveJSONs models.DebianJSON, advisoryJSONs []models.DebianAdvisoryJSON, warnings *util.Warnings) (cves []models.DebianCVE) {
 	uniqCve := map[string]models.DebianCVE{}
 	for pkgName, cveMap := range cveJSONs {
 		for cveID, cve := range cveMap {
 			var releases []models.DebianRelease
 			for release, releaseInfo := range cve.Releases {
+				// The statuses of ELTS (e.g. buster/elts) are of the release with the channel
+				codename, channel := splitReleaseChannel(release)
 				r := models.DebianRelease{
-					ProductName:  release,
+					ProductName:  codename,
 					Status:       releaseInfo.Status,
 					FixedVersion: releaseInfo.FixedVersion,
 					Urgency:      releaseInfo.Urgency,
-					Version:      releaseInfo.Repositories[release],
+					Version:      releaseInfo.Repositories[codename],
+					Channel:      channel,
 				}
 				releases = append(releases, r)
 			}
@@ -101,6 +223,9 @@ func ConvertDebian(cveJSONs models.DebianJSON) (cves []models.DebianCVE) {
 				PackageName: pkgName,
 				Release:     releases,
 			}
+			if cve.Debianbug != 0 {
+				pkg.DebianBug = strconv.Itoa(cve.Debianbug)
+			}
 
 			pkgs := []models.DebianPackage{pkg}
 			if oldCve, ok := uniqCve[cveID]; ok {
@@ -115,36 +240,59 @@ func ConvertDebian(cveJSONs models.DebianJSON) (cves []models.DebianCVE) {
 			}
 		}
 	}
+
+	for _, a := range advisoryJSONs {
+		for _, cveID := range a.CveIDs {
+			c, ok := uniqCve[cveID]
+			if !ok {
+				continue
+			}
+			for _, rel := range a.Releases {
+				c.Advisories = append(c.Advisories, models.DebianAdvisory{
+					AdvisoryID:   a.AdvisoryID,
+					IssuedDate:   parseDebianAdvisoryDate(a.AdvisoryID, a.Date, warnings),
+					PackageName:  rel.PackageName,
+					ProductName:  rel.Release,
+					FixedVersion: rel.FixedVersion,
+					Urgency:      cveJSONs[rel.PackageName][cveID].Releases[rel.Release].Urgency,
+				})
+			}
+			uniqCve[cveID] = c
+		}
+	}
+
 	for _, c := range uniqCve {
 		cves = append(cves, c)
 	}
 	return cves
 }
 
-var debVerCodename = map[string]string{
-	"8":  "jessie",
-	"9":  "stretch",
-	"10": "buster",
-	"11": "bullseye",
-	"12": "bookworm",
-	"13": "trixie",
+func parseDebianAdvisoryDate(advisoryID, date string, warnings *util.Warnings) time.Time {
+	t, err := util.ParseDate(date, "2 Jan 2006")
+	if err != nil {
+		warnings.Add("debian", advisoryID, "date", fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
 }
 
 // GetUnfixedCvesDebian gets the CVEs related to debian_release.status = 'open', major, pkgName.
-func (r *RDBDriver) GetUnfixedCvesDebian(major, pkgName string) map[string]models.DebianCVE {
-	return r.getCvesDebianWithFixStatus(major, pkgName, "open")
+// The statuses of the channels (e.g. elts) override the standard ones if given.
+func (r *RDBDriver) GetUnfixedCvesDebian(major, pkgName string, channels ...string) map[string]models.DebianCVE {
+	return r.getCvesDebianWithFixStatus(major, pkgName, "open", channels)
 }
 
 // GetFixedCvesDebian gets the CVEs related to debian_release.status = 'resolved', major, pkgName.
-func (r *RDBDriver) GetFixedCvesDebian(major, pkgName string) map[string]models.DebianCVE {
-	return r.getCvesDebianWithFixStatus(major, pkgName, "resolved")
+// The statuses of the channels (e.g. elts) override the standard ones if given.
+func (r *RDBDriver) GetFixedCvesDebian(major, pkgName string, channels ...string) map[string]models.DebianCVE {
+	return r.getCvesDebianWithFixStatus(major, pkgName, "resolved", channels)
 }
 
-func (r *RDBDriver) getCvesDebianWithFixStatus(major, pkgName, fixStatus string) map[string]models.DebianCVE {
+func (r *RDBDriver) getCvesDebianWithFixStatus(major, pkgName, fixStatus string, channels []string) map[string]models.DebianCVE {
 	m := map[string]models.DebianCVE{}
-	codeName, ok := debVerCodename[major]
+	codeName, ok := codenameOf(models.DistroDebian, major)
 	if !ok {
-		log15.Error("Debian %s is not supported yet", "err", major)
+		r.log.Error("Debian %s is not supported yet", "err", major)
 		return m
 	}
 
@@ -161,34 +309,125 @@ func (r *RDBDriver) getCvesDebianWithFixStatus(major, pkgName, fixStatus string)
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		if fixStatus == "open" {
-			log15.Error("Failed to get unfixed cves of Debian", "err", err)
+			r.log.Error("Failed to get unfixed cves of Debian", "err", err)
 		} else {
-			log15.Error("Failed to get fixed cves of Debian", "err", err)
+			r.log.Error("Failed to get fixed cves of Debian", "err", err)
 		}
 		return m
 	}
 
+	r.explain.addCandidates(len(results))
 	for _, res := range results {
 		debcve := models.DebianCVE{}
 		err := r.conn.
-			Preload("Package.Release", "status = ? AND product_name = ?", fixStatus, codeName).
+			Preload("Package.Release", "product_name = ?", codeName).
 			Preload("Package", "package_name = ?", pkgName).
 			Where(&models.DebianCVE{ID: res.DebianCveID}).
 			First(&debcve).Error
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			log15.Error("Failed to get DebianCVE", res.DebianCveID, err)
+			r.log.Error("Failed to get DebianCVE", res.DebianCveID, err)
 			return m
 		}
 
-		if len(debcve.Package) != 0 {
-			for _, pkg := range debcve.Package {
-				if len(pkg.Release) != 0 {
-					m[debcve.CveID] = debcve
-				}
-
+		pkgs := []models.DebianPackage{}
+		for _, pkg := range debcve.Package {
+			if pkg.Release = filterDebianReleases(pkg.Release, fixStatus, channels); len(pkg.Release) != 0 {
+				pkgs = append(pkgs, pkg)
 			}
 		}
+		if len(pkgs) != 0 {
+			debcve.Package = pkgs
+			m[debcve.CveID] = debcve
+		}
 	}
 
 	return m
 }
+
+// GetUnfixedCvesDebianMulti gets the unfixed CVEs of pkgNames by a query of the packages and a query of the CVEs
+func (r *RDBDriver) GetUnfixedCvesDebianMulti(major string, pkgNames []string, channels ...string) map[string]map[string]models.DebianCVE {
+	results := map[string]map[string]models.DebianCVE{}
+	codeName, ok := codenameOf(models.DistroDebian, major)
+	if !ok {
+		r.log.Error("Debian %s is not supported yet", "err", major)
+		return results
+	}
+
+	ids := []int64{}
+	for idx := range chunkSlice(len(pkgNames), 500) {
+		chunk := []int64{}
+		if err := r.conn.
+			Table("debian_packages").
+			Distinct("debian_cve_id").
+			Where("package_name IN ?", pkgNames[idx.From:idx.To]).
+			Pluck("debian_cve_id", &chunk).Error; err != nil {
+			r.log.Error("Failed to get unfixed cves of Debian", "err", err)
+			return results
+		}
+		ids = append(ids, chunk...)
+	}
+
+	r.explain.addCandidates(len(ids))
+	cves := []models.DebianCVE{}
+	for idx := range chunkSlice(len(ids), 500) {
+		chunk := []models.DebianCVE{}
+		if err := r.conn.
+			Preload("Package.Release", "product_name = ?", codeName).
+			// all packages of the CVEs, filtered by debianPackagesWithFixStatus, so as not to bind all pkgNames in a query
+			Preload("Package").
+			Where("id IN ?", ids[idx.From:idx.To]).
+			Find(&chunk).Error; err != nil {
+			r.log.Error("Failed to get unfixed cves of Debian", "err", err)
+			return results
+		}
+		cves = append(cves, chunk...)
+	}
+
+	for _, pkgName := range pkgNames {
+		m := map[string]models.DebianCVE{}
+		for _, c := range cves {
+			if c.Package = debianPackagesWithFixStatus(c.Package, codeName, pkgName, "open", channels); len(c.Package) != 0 {
+				m[c.CveID] = c
+			}
+		}
+		results[pkgName] = m
+	}
+	return results
+}
+
+// debianPackagesWithFixStatus returns the packages of pkgName having the releases of codeName in fixStatus, with those releases only
+func debianPackagesWithFixStatus(pkgs []models.DebianPackage, codeName, pkgName, fixStatus string, channels []string) []models.DebianPackage {
+	filtered := []models.DebianPackage{}
+	for _, pkg := range pkgs {
+		if pkg.PackageName != pkgName {
+			continue
+		}
+		rels := []models.DebianRelease{}
+		for _, rel := range pkg.Release {
+			if rel.ProductName == codeName {
+				rels = append(rels, rel)
+			}
+		}
+		if pkg.Release = filterDebianReleases(rels, fixStatus, channels); len(pkg.Release) != 0 {
+			filtered = append(filtered, pkg)
+		}
+	}
+	return filtered
+}
+
+// filterDebianReleases returns the releases in fixStatus, of the channel effective for the subscriber of the channels
+func filterDebianReleases(rels []models.DebianRelease, fixStatus string, channels []string) []models.DebianRelease {
+	found := map[string]bool{}
+	for _, rel := range rels {
+		found[rel.Channel] = true
+	}
+	channel := effectiveChannel(found, channels)
+
+	filtered := []models.DebianRelease{}
+	for _, rel := range rels {
+		if rel.Channel == channel && rel.Status == fixStatus {
+			filtered = append(filtered, rel)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// e.g. "openssl DPKG is earlier than 3.0.8-1"
+var debianOVALCriterionRegexp = regexp.MustCompile(`^(\S+) DPKG is earlier than (\S+)$`)
+
+// debianOVALUnfixedVersion is the version of the criterion of the packages not fixed yet in Debian OVAL
+const debianOVALUnfixedVersion = "0:0"
+
+// DebianCodename returns the codename of the release (e.g. 12, 12.1 or bookworm), or empty if unknown
+func DebianCodename(release string) string {
+	codename, _ := codenameOf(models.DistroDebian, NormalizeDebianRelease(release))
+	return codename
+}
+
+type debianOVALKey struct {
+	cveID   string
+	pkgName string
+}
+
+// debianOVALFixes returns the fixed version of each pair of CVE and package in OVAL. The version is empty if not fixed.
+func debianOVALFixes(oval models.DebianOVALXML) map[debianOVALKey]string {
+	fixes := map[debianOVALKey]string{}
+	for _, def := range oval.Definitions {
+		if def.Class != "" && def.Class != "vulnerability" {
+			continue
+		}
+		cveID := ""
+		for _, ref := range def.Metadata.References {
+			if ref.Source == "CVE" {
+				cveID = ref.RefID
+				break
+			}
+		}
+		if cveID == "" {
+			if fs := strings.Fields(def.Metadata.Title); len(fs) > 0 && strings.HasPrefix(fs[0], "CVE-") {
+				cveID = fs[0]
+			}
+		}
+		if cveID == "" {
+			continue
+		}
+
+		walkDebianOVALCriteria(def.Criteria, func(pkgName, version string) {
+			if version == debianOVALUnfixedVersion {
+				version = ""
+			}
+			fixes[debianOVALKey{cveID: cveID, pkgName: pkgName}] = version
+		})
+	}
+	return fixes
+}
+
+func walkDebianOVALCriteria(criteria models.DebianOVALCriteriaXML, fn func(pkgName, version string)) {
+	for _, c := range criteria.Criterions {
+		if m := debianOVALCriterionRegexp.FindStringSubmatch(c.Comment); m != nil {
+			fn(m[1], m[2])
+		}
+	}
+	for _, c := range criteria.Criterias {
+		walkDebianOVALCriteria(c, fn)
+	}
+}
+
+// trimDebianEpoch trims the epoch 0, which OVAL may have and the tracker does not
+func trimDebianEpoch(version string) string {
+	return strings.TrimPrefix(version, "0:")
+}
+
+// CrossCheckDebianOVAL compares the fix status of each pair of CVE and package in Debian OVAL with Debian Security Tracker in DB,
+// and reports the disagreements. The pairs only in the tracker (e.g. not-affected or undetermined) are not reported,
+// since OVAL does not have them.
+func CrossCheckDebianOVAL(driver DB, ovals []models.DebianOVALXML) (models.DebianOVALCrossCheckReport, error) {
+	report := models.DebianOVALCrossCheckReport{Releases: []models.DebianOVALCrossCheckRelease{}, Disagreements: []models.DebianOVALDisagreement{}}
+	trackerCves := map[string]*models.DebianCVE{}
+	for _, oval := range ovals {
+		codename := DebianCodename(oval.Release)
+		if codename == "" {
+			return models.DebianOVALCrossCheckReport{}, xerrors.Errorf("Failed to cross-check Debian OVAL. Unknown release: %s", oval.Release)
+		}
+
+		fixes := debianOVALFixes(oval)
+		keys := make([]debianOVALKey, 0, len(fixes))
+		for k := range fixes {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].cveID != keys[j].cveID {
+				return keys[i].cveID < keys[j].cveID
+			}
+			return keys[i].pkgName < keys[j].pkgName
+		})
+
+		summary := models.DebianOVALCrossCheckRelease{Release: codename, Checked: len(keys)}
+		for _, k := range keys {
+			cve, ok := trackerCves[k.cveID]
+			if !ok {
+				cve = driver.GetDebian(k.cveID)
+				trackerCves[k.cveID] = cve
+			}
+
+			d := models.DebianOVALDisagreement{Release: codename, CveID: k.cveID, PackageName: k.pkgName, OVALFixedVersion: fixes[k]}
+			found := false
+			if cve != nil {
+				for _, p := range cve.Package {
+					if p.PackageName != k.pkgName {
+						continue
+					}
+					for _, r := range p.Release {
+						if r.ProductName == codename && r.Channel == "" {
+							d.TrackerStatus, d.TrackerFixedVersion, found = r.Status, r.FixedVersion, true
+						}
+					}
+				}
+			}
+
+			switch {
+			case !found:
+				d.Kind = models.DebianOVALMissingInTracker
+				summary.MissingInTracker++
+			case (d.TrackerStatus == "resolved") != (d.OVALFixedVersion != ""):
+				d.Kind = models.DebianOVALStatusMismatch
+				summary.StatusMismatch++
+			case d.OVALFixedVersion != "" && trimDebianEpoch(d.OVALFixedVersion) != trimDebianEpoch(d.TrackerFixedVersion):
+				d.Kind = models.DebianOVALVersionMismatch
+				summary.VersionMismatch++
+			default:
+				continue
+			}
+			report.Disagreements = append(report.Disagreements, d)
+		}
+		log15.Info("Cross-checked Debian OVAL", "release", codename, "checked", summary.Checked,
+			"disagreements", summary.MissingInTracker+summary.StatusMismatch+summary.VersionMismatch)
+		report.Releases = append(report.Releases, summary)
+	}
+	return report, nil
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// ErrUnknownRelease is returned when the release of a derivative distribution is not mapped to the upstream
+var ErrUnknownRelease = xerrors.New("Unknown release")
+
+// ErrNotCovered is returned for the packages of a derivative distribution not built from the upstream (e.g. the kernel of Raspberry Pi OS),
+// which the upstream dataset does not cover
+var ErrNotCovered = xerrors.New("Not covered by the upstream")
+
+// Derivative maps a release of a derivative distribution (e.g. Linux Mint 21) to the release of the upstream (e.g. Ubuntu jammy)
+type Derivative struct {
+	Family          string `json:"family"`
+	Release         string `json:"release"`
+	UpstreamFamily  string `json:"upstream_family"`
+	UpstreamRelease string `json:"upstream_release"`
+}
+
+var defaultDerivatives = []Derivative{
+	{Family: "linuxmint", Release: "19", UpstreamFamily: "ubuntu", UpstreamRelease: "bionic"},
+	{Family: "linuxmint", Release: "20", UpstreamFamily: "ubuntu", UpstreamRelease: "focal"},
+	{Family: "linuxmint", Release: "21", UpstreamFamily: "ubuntu", UpstreamRelease: "jammy"},
+	{Family: "lmde", Release: "4", UpstreamFamily: "debian", UpstreamRelease: "buster"},
+	{Family: "lmde", Release: "5", UpstreamFamily: "debian", UpstreamRelease: "bullseye"},
+	{Family: "lmde", Release: "6", UpstreamFamily: "debian", UpstreamRelease: "bookworm"},
+	{Family: "elementary", Release: "5", UpstreamFamily: "ubuntu", UpstreamRelease: "bionic"},
+	{Family: "elementary", Release: "6", UpstreamFamily: "ubuntu", UpstreamRelease: "focal"},
+	{Family: "elementary", Release: "7", UpstreamFamily: "ubuntu", UpstreamRelease: "jammy"},
+	{Family: "pop", Release: "20.04", UpstreamFamily: "ubuntu", UpstreamRelease: "focal"},
+	{Family: "pop", Release: "22.04", UpstreamFamily: "ubuntu", UpstreamRelease: "jammy"},
+	{Family: "zorin", Release: "15", UpstreamFamily: "ubuntu", UpstreamRelease: "bionic"},
+	{Family: "zorin", Release: "16", UpstreamFamily: "ubuntu", UpstreamRelease: "focal"},
+	{Family: "zorin", Release: "17", UpstreamFamily: "ubuntu", UpstreamRelease: "jammy"},
+	{Family: "raspbian", Release: "10", UpstreamFamily: "debian", UpstreamRelease: "buster"},
+	{Family: "raspbian", Release: "11", UpstreamFamily: "debian", UpstreamRelease: "bullseye"},
+	{Family: "raspbian", Release: "12", UpstreamFamily: "debian", UpstreamRelease: "bookworm"},
+	{Family: "raspbian", Release: "buster", UpstreamFamily: "debian", UpstreamRelease: "buster"},
+	{Family: "raspbian", Release: "bullseye", UpstreamFamily: "debian", UpstreamRelease: "bullseye"},
+	{Family: "raspbian", Release: "bookworm", UpstreamFamily: "debian", UpstreamRelease: "bookworm"},
+}
+
+// derivativeFamilyAliases are the other names of the derivative distributions (e.g. ID in /etc/os-release)
+var derivativeFamilyAliases = map[string]string{
+	"raspios":        "raspbian",
+	"raspberrypi":    "raspbian",
+	"raspberrypi-os": "raspbian",
+}
+
+// derivativePackagePrefixes are the prefixes of the packages specific to the derivative distributions.
+// e.g. the kernel and the firmware of Raspberry Pi OS are built from github.com/raspberrypi, not from the source package of Debian.
+var derivativePackagePrefixes = map[string][]string{
+	"raspbian": {"raspberrypi-", "raspi-", "libraspberrypi", "rpi-", "linux-image-rpi-", "linux-headers-rpi-"},
+}
+
+var derivatives = struct {
+	sync.RWMutex
+	m map[string]map[string]Derivative
+}{m: indexDerivatives(nil, defaultDerivatives)}
+
+func indexDerivatives(m map[string]map[string]Derivative, ds []Derivative) map[string]map[string]Derivative {
+	if m == nil {
+		m = map[string]map[string]Derivative{}
+	}
+	for _, d := range ds {
+		d.Family = strings.ToLower(strings.TrimSpace(d.Family))
+		d.Release = strings.ToLower(strings.TrimSpace(d.Release))
+		if m[d.Family] == nil {
+			m[d.Family] = map[string]Derivative{}
+		}
+		m[d.Family][d.Release] = d
+	}
+	return m
+}
+
+// LoadDerivatives reads the mappings of the derivative distributions from the JSON file (an array of Derivative).
+// They are added to the built-in mappings, and override the same family and release.
+func LoadDerivatives(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return xerrors.Errorf("Failed to read derivatives. path: %s, err: %w", path, err)
+	}
+	ds := []Derivative{}
+	if err := json.Unmarshal(b, &ds); err != nil {
+		return xerrors.Errorf("Failed to decode derivatives. path: %s, err: %w", path, err)
+	}
+	for _, d := range ds {
+		if d.Family == "" || d.Release == "" || d.UpstreamFamily == "" || d.UpstreamRelease == "" {
+			return xerrors.Errorf("Failed to load derivatives. family, release, upstream_family and upstream_release are required. path: %s, derivative: %+v", path, d)
+		}
+	}
+
+	derivatives.Lock()
+	defer derivatives.Unlock()
+	derivatives.m = indexDerivatives(indexDerivatives(nil, defaultDerivatives), ds)
+	return nil
+}
+
+// Derivatives returns the mappings of the derivative distributions, in the order of the family and the release
+func Derivatives() []Derivative {
+	derivatives.RLock()
+	defer derivatives.RUnlock()
+	ds := []Derivative{}
+	for _, releases := range derivatives.m {
+		for _, d := range releases {
+			ds = append(ds, d)
+		}
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		if ds[i].Family != ds[j].Family {
+			return ds[i].Family < ds[j].Family
+		}
+		return ds[i].Release < ds[j].Release
+	})
+	return ds
+}
+
+// derivativeFamily returns the family of the derivative distribution of the alias (e.g. raspios), or the family as it is
+func derivativeFamily(family string) string {
+	family = strings.ToLower(strings.TrimSpace(family))
+	if f, ok := derivativeFamilyAliases[family]; ok {
+		return f
+	}
+	return family
+}
+
+// IsDerivativePackage returns whether the package is specific to the derivative distribution, and not covered by the upstream
+func IsDerivativePackage(family, pkgName string) bool {
+	for _, prefix := range derivativePackagePrefixes[derivativeFamily(family)] {
+		if strings.HasPrefix(pkgName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveDerivative returns the upstream family and release of the release of a derivative distribution.
+// The release is looked up as is (e.g. 21.1), then by the major version (e.g. 21).
+// The family not a derivative is returned as it is.
+func ResolveDerivative(family, release string) (string, string, error) {
+	derivatives.RLock()
+	defer derivatives.RUnlock()
+
+	releases, ok := derivatives.m[derivativeFamily(family)]
+	if !ok {
+		return family, release, nil
+	}
+	release = strings.ToLower(strings.TrimSpace(release))
+	d, ok := releases[release]
+	if !ok {
+		if d, ok = releases[util.Major(release)]; !ok {
+			return "", "", xerrors.Errorf("Failed to resolve the derivative. family: %s, release: %s, err: %w", family, release, ErrUnknownRelease)
+		}
+	}
+	return d.UpstreamFamily, d.UpstreamRelease, nil
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetDistroReleases :
+func (r *RDBDriver) GetDistroReleases() ([]models.DistroRelease, error) {
+	releases := []models.DistroRelease{}
+	if err := r.conn.Order("id").Find(&releases).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get the releases of distro-info-data. err: %w", err)
+	}
+	return releases, nil
+}
+
+// InsertDistroReleases :
+func (r *RDBDriver) InsertDistroReleases(rows []models.DistroInfoCSV) (err error) {
+	releases := ConvertDistroInfo(rows)
+	bar := startProgress(r.insert.Progress, len(releases))
+	tx := r.conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	if err = tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.DistroRelease{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old records. err: %w", err)
+	}
+	for idx := range chunkSlice(len(releases), r.insert.BatchSize) {
+		if err = tx.Create(releases[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+	setDistroReleases(releases)
+	return nil
+}
+
+// ConvertDistroInfo converts the rows of distro-info-data into the releases keyed by the version of gost (e.g. 12 of Debian, 2204 of Ubuntu).
+// The rows without a version (sid, experimental) are skipped, and the later row wins if the versions are the same (e.g. Debian 2.0 and 2.2).
+func ConvertDistroInfo(rows []models.DistroInfoCSV) []models.DistroRelease {
+	releases := []models.DistroRelease{}
+	index := map[string]int{}
+	for _, row := range rows {
+		fields := strings.Fields(row.Version)
+		if len(fields) == 0 || row.Series == "" {
+			continue
+		}
+
+		var ver string
+		switch row.Distro {
+		case models.DistroDebian:
+			ver = util.Major(fields[0])
+		case models.DistroUbuntu:
+			// e.g. 22.04 LTS
+			ver = strings.Replace(fields[0], ".", "", 1)
+		default:
+			continue
+		}
+
+		rel := models.DistroRelease{
+			Distro:      row.Distro,
+			Version:     ver,
+			Codename:    strings.ToLower(row.Series),
+			ReleaseDate: row.Release,
+			EOL:         row.EOL,
+		}
+		key := row.Distro + "#" + ver
+		if i, ok := index[key]; ok {
+			releases[i] = rel
+			continue
+		}
+		index[key] = len(releases)
+		releases = append(releases, rel)
+	}
+	return releases
+}
+
+// loadDistroReleases adds the releases of distro-info-data in DB to the codenames known.
+// The DB without them (e.g. distro-info is not fetched yet) keeps the built-in releases.
+func loadDistroReleases(driver DB) {
+	releases, err := driver.GetDistroReleases()
+	if err != nil {
+		log15.Warn("Failed to load the releases of distro-info-data. The built-in releases are used", "err", err)
+		return
+	}
+	setDistroReleases(releases)
+}
+
+// sortDistroReleases sorts the releases by distribution, and by version in the order of numbers
+func sortDistroReleases(releases []models.DistroRelease) {
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Distro != releases[j].Distro {
+			return releases[i].Distro < releases[j].Distro
+		}
+		if len(releases[i].Version) != len(releases[j].Version) {
+			return len(releases[i].Version) < len(releases[j].Version)
+		}
+		return releases[i].Version < releases[j].Version
+	})
+}
@@ -0,0 +1,296 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// ErrDocumentTooLarge is returned when the insert of a document exceeding the size limit is rejected
+var ErrDocumentTooLarge = xerrors.New("Document too large")
+
+// The actions on the documents exceeding the size limit
+const (
+	// OversizedWarn stores the document as it is and warns
+	OversizedWarn = "warn"
+	// OversizedReject fails the insert
+	OversizedReject = "reject"
+	// OversizedStrip truncates the free-text fields of the document, and warns if it still exceeds the limit
+	OversizedStrip = "strip"
+)
+
+// strippedTextLen is the length in bytes the free-text fields are truncated to by OversizedStrip
+const strippedTextLen = 1024
+
+// strippedFields are the free-text fields truncated by OversizedStrip (case-insensitive)
+var strippedFields = map[string]bool{
+	"description": true,
+	"details":     true,
+	"summary":     true,
+	"statement":   true,
+	"mitigation":  true,
+	"notes":       true,
+	"comment":     true,
+	"text":        true,
+}
+
+// DocumentSizeBuckets are the labels of the size ranges of DocumentSizeStats.Buckets in ascending order
+var DocumentSizeBuckets = []string{"<1KiB", "<10KiB", "<100KiB", "<1MiB", ">=1MiB"}
+
+// documentSizeLimits are the upper bounds (exclusive) of DocumentSizeBuckets except the last one
+var documentSizeLimits = []int{1 << 10, 10 << 10, 100 << 10, 1 << 20}
+
+// DocumentSizes records the sizes of the JSON documents stored into Redis per source,
+// and applies the action to the documents exceeding maxBytes
+type DocumentSizes struct {
+	maxBytes int
+	action   string
+
+	mu    sync.Mutex
+	stats map[string]*DocumentSizeStats
+}
+
+// DocumentSizeStats : the size distribution of the documents of a source
+type DocumentSizeStats struct {
+	Documents  int64 `json:"documents"`
+	TotalBytes int64 `json:"total_bytes"`
+	MaxBytes   int64 `json:"max_bytes"`
+	// MaxID is the ID of the largest document
+	MaxID string `json:"max_id"`
+	// Buckets is the number of the documents per size range
+	Buckets map[string]int64 `json:"buckets"`
+	// Oversized is the number of the documents exceeding the limit after strip
+	Oversized int64 `json:"oversized"`
+	Stripped  int64 `json:"stripped"`
+	Rejected  int64 `json:"rejected"`
+}
+
+// NewDocumentSizes returns DocumentSizes applying action (OversizedWarn, OversizedReject or OversizedStrip) to the documents exceeding maxBytes.
+// If maxBytes is 0, only the sizes are recorded.
+func NewDocumentSizes(maxBytes int, action string) (*DocumentSizes, error) {
+	switch action {
+	case "":
+		action = OversizedWarn
+	case OversizedWarn, OversizedReject, OversizedStrip:
+	default:
+		return nil, xerrors.Errorf("Invalid action on oversized documents: %s. It must be %s, %s or %s", action, OversizedWarn, OversizedReject, OversizedStrip)
+	}
+	if maxBytes < 0 {
+		return nil, xerrors.Errorf("Invalid max document size: %d", maxBytes)
+	}
+	return &DocumentSizes{maxBytes: maxBytes, action: action, stats: map[string]*DocumentSizeStats{}}, nil
+}
+
+// Stats returns the size distribution per source
+func (d *DocumentSizes) Stats() map[string]DocumentSizeStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := map[string]DocumentSizeStats{}
+	for source, s := range d.stats {
+		c := *s
+		c.Buckets = map[string]int64{}
+		for k, v := range s.Buckets {
+			c.Buckets[k] = v
+		}
+		stats[source] = c
+	}
+	return stats
+}
+
+func (d *DocumentSizes) sourceStats(source string) *DocumentSizeStats {
+	s, ok := d.stats[source]
+	if !ok {
+		s = &DocumentSizeStats{Buckets: map[string]int64{}}
+		d.stats[source] = s
+	}
+	return s
+}
+
+func (d *DocumentSizes) record(source, id string, size int, stripped, oversized, rejected bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s := d.sourceStats(source)
+	if rejected {
+		s.Rejected++
+		return
+	}
+	s.Documents++
+	s.TotalBytes += int64(size)
+	if int64(size) > s.MaxBytes {
+		s.MaxBytes, s.MaxID = int64(size), id
+	}
+	i := 0
+	for i < len(documentSizeLimits) && size >= documentSizeLimits[i] {
+		i++
+	}
+	s.Buckets[DocumentSizeBuckets[i]]++
+	if stripped {
+		s.Stripped++
+	}
+	if oversized {
+		s.Oversized++
+	}
+}
+
+// check records the document of HSET key field value, and returns the document to be stored instead of it
+func (d *DocumentSizes) check(key, field, value string, warnings *util.Warnings) (string, error) {
+	source, id := documentSource(key, field)
+	if d.maxBytes == 0 || len(value) <= d.maxBytes {
+		d.record(source, id, len(value), false, false, false)
+		return value, nil
+	}
+
+	reason := fmt.Sprintf("Document exceeds %d bytes: %d bytes", d.maxBytes, len(value))
+	switch d.action {
+	case OversizedReject:
+		d.record(source, id, len(value), false, false, true)
+		log15.Error("Rejected the oversized document", "source", source, "id", id, "size", len(value), "max", d.maxBytes)
+		return "", xerrors.Errorf("%s %s: %w", key, field, ErrDocumentTooLarge)
+	case OversizedStrip:
+		stripped, err := stripDocument(value)
+		if err != nil {
+			log15.Warn("Failed to strip the oversized document", "source", source, "id", id, "err", err)
+			break
+		}
+		if len(stripped) <= d.maxBytes {
+			d.record(source, id, len(stripped), true, false, false)
+			warnings.Add(source, id, field, fmt.Sprintf("%s. The free-text fields are truncated to %d bytes", reason, strippedTextLen))
+			return stripped, nil
+		}
+		d.record(source, id, len(stripped), true, true, false)
+		warnings.Add(source, id, field, fmt.Sprintf("Document exceeds %d bytes after strip: %d bytes", d.maxBytes, len(stripped)))
+		return stripped, nil
+	}
+	d.record(source, id, len(value), false, true, false)
+	warnings.Add(source, id, field, reason)
+	return value, nil
+}
+
+// documentSource returns the source and the ID of the document stored in field of the hash key.
+// The hash of a CVE holds a field per source (e.g. CVE#CVE-2021-3449 RedHat), and the other hashes are named after the source.
+func documentSource(key, field string) (source, id string) {
+	if strings.HasPrefix(key, hashKeyPrefix) {
+		return field, strings.TrimPrefix(key, hashKeyPrefix)
+	}
+	i := strings.Index(key, "#")
+	if i < 0 {
+		return key, field
+	}
+	switch key {
+	case hashKernelCveKey, hashCweKey, hashDistroReleaseKey:
+		return key[:i], field
+	}
+	return key[:i], key[i+1:]
+}
+
+// stripDocument truncates the free-text fields of the JSON document longer than strippedTextLen
+func stripDocument(value string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(value))
+	// keep the precision of the numbers
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return "", xerrors.Errorf("Failed to decode document. err: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(stripValue(doc)); err != nil {
+		return "", xerrors.Errorf("Failed to encode document. err: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func stripValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if s, ok := e.(string); ok && strippedFields[strings.ToLower(k)] {
+				v[k] = truncateText(s, strippedTextLen)
+				continue
+			}
+			v[k] = stripValue(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = stripValue(e)
+		}
+	}
+	return v
+}
+
+// truncateText truncates s to n bytes at most without splitting a UTF-8 character
+func truncateText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// isDocument returns true if the value looks like a JSON document, not a plain value (e.g. the fetch time)
+func isDocument(value string) bool {
+	return strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[")
+}
+
+// documentSizeHook applies DocumentSizes to the documents set by HSET of Redis.
+// The document replaced by strip is written back into the arguments of the command.
+type documentSizeHook struct {
+	sizes    *DocumentSizes
+	warnings *util.Warnings
+}
+
+func (h documentSizeHook) apply(cmd redis.Cmder) error {
+	args := cmd.Args()
+	if len(args) < 4 || !strings.EqualFold(cmd.Name(), "hset") {
+		return nil
+	}
+	key := fmt.Sprint(args[1])
+	for i := 2; i+1 < len(args); i += 2 {
+		value, ok := args[i+1].(string)
+		if !ok || !isDocument(value) {
+			continue
+		}
+		v, err := h.sizes.check(key, fmt.Sprint(args[i]), value, h.warnings)
+		if err != nil {
+			return err
+		}
+		args[i+1] = v
+	}
+	return nil
+}
+
+func (h documentSizeHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, h.apply(cmd)
+}
+
+func (h documentSizeHook) AfterProcess(context.Context, redis.Cmder) error {
+	return nil
+}
+
+func (h documentSizeHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	for _, cmd := range cmds {
+		if err := h.apply(cmd); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+func (h documentSizeHook) AfterProcessPipeline(context.Context, []redis.Cmder) error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// IsDBPathURL returns true if dbPath of SQLite3 is the URL of a pre-built DB to download on start
+func IsDBPathURL(dbType, dbPath string) bool {
+	return dbType == dialectSqlite3 && (strings.HasPrefix(dbPath, "http://") || strings.HasPrefix(dbPath, "https://"))
+}
+
+// DownloadSQLite downloads the SQLite3 DB from url into dir, verifies the SHA-256 of the download,
+// and decompresses it by the extension of url (.gz or .bz2). If checksum is empty, it is read from url + ".sha256" (the output of sha256sum).
+// It returns the DSN opening the DB read-only.
+func DownloadSQLite(url, checksum, dir string) (string, error) {
+	if checksum == "" {
+		body, err := util.FetchURL(url+".sha256", "")
+		if err != nil {
+			return "", xerrors.Errorf("Failed to fetch the checksum of the DB. Give the SHA-256 by --dbpath-sha256 if %s.sha256 does not exist. err: %w", url, err)
+		}
+		if fields := strings.Fields(string(body)); len(fields) > 0 {
+			checksum = fields[0]
+		}
+	}
+
+	downloaded := filepath.Join(dir, "download")
+	f, err := os.Create(downloaded)
+	if err != nil {
+		return "", xerrors.Errorf("Failed to create %s. err: %w", downloaded, err)
+	}
+	h := sha256.New()
+	log15.Info("Downloading DB", "URL", url)
+	err = util.DownloadURL(url, io.MultiWriter(f, h))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", xerrors.Errorf("Failed to download the DB. err: %w", err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, strings.TrimSpace(checksum)) {
+		return "", xerrors.Errorf("Failed to verify the DB. SHA-256 mismatch. expected: %s, actual: %s", checksum, sum)
+	}
+
+	path := filepath.Join(dir, "gost.sqlite3")
+	if err := decompressSQLite(strings.SplitN(url, "?", 2)[0], downloaded, path); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("file:%s?mode=ro", path), nil
+}
+
+func decompressSQLite(url, src, dst string) error {
+	if !strings.HasSuffix(url, ".gz") && !strings.HasSuffix(url, ".bz2") {
+		return os.Rename(src, dst)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return xerrors.Errorf("Failed to open %s. err: %w", src, err)
+	}
+	defer os.Remove(src)
+	defer in.Close()
+
+	var r io.Reader = bzip2.NewReader(in)
+	if strings.HasSuffix(url, ".gz") {
+		gr, err := gzip.NewReader(in)
+		if err != nil {
+			return xerrors.Errorf("Failed to decompress the DB. err: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return xerrors.Errorf("Failed to create %s. err: %w", dst, err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return xerrors.Errorf("Failed to decompress the DB. err: %w", err)
+	}
+	return out.Close()
+}
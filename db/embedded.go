@@ -0,0 +1,32 @@
+package db
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+// HasEmbeddedDB returns true if the binary is built with -tags embeddb
+func HasEmbeddedDB() bool {
+	return len(embeddedSQLite) > 0
+}
+
+// ExtractEmbeddedSQLite writes the SQLite3 DB compiled into the binary (gzipped) to dir.
+// It returns the DSN opening the DB read-only.
+func ExtractEmbeddedSQLite(dir string) (string, error) {
+	if !HasEmbeddedDB() {
+		return "", xerrors.New("Failed to extract the embedded DB. The binary is built without DB. Build it by `make build-embedded`")
+	}
+
+	compressed := filepath.Join(dir, "embedded.gz")
+	if err := ioutil.WriteFile(compressed, embeddedSQLite, 0600); err != nil {
+		return "", xerrors.Errorf("Failed to write %s. err: %w", compressed, err)
+	}
+	path := filepath.Join(dir, "gost.sqlite3")
+	if err := decompressSQLite(compressed, compressed, path); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("file:%s?mode=ro", path), nil
+}
@@ -0,0 +1,15 @@
+//go:build embeddb
+// +build embeddb
+
+package db
+
+import (
+	// embed the DB snapshot
+	_ "embed"
+)
+
+// embeddedSQLite is the SQLite3 DB gzipped by `make embedded-db` (or `make build-embedded`).
+// The file is not committed, so run it (or `make vet-embeddb` writing an empty one) before building or vetting with the tag
+//
+//go:embed embedded/gost.sqlite3.gz
+var embeddedSQLite []byte
@@ -0,0 +1,6 @@
+//go:build !embeddb
+// +build !embeddb
+
+package db
+
+var embeddedSQLite []byte
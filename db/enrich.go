@@ -0,0 +1,540 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+)
+
+// enrichDriver joins KEV, EPSS, the public exploits and the CWE names into the CVEs returned by the Get* methods of DB
+type enrichDriver struct {
+	DB
+}
+
+// WithLogger :
+func (d *enrichDriver) WithLogger(l log15.Logger) DB {
+	return &enrichDriver{DB: d.DB.WithLogger(l)}
+}
+
+// WithExplain :
+func (d *enrichDriver) WithExplain(e *Explain) DB {
+	return &enrichDriver{DB: d.DB.WithExplain(e)}
+}
+
+// WithInsertOptions :
+func (d *enrichDriver) WithInsertOptions(o InsertOptions) DB {
+	return &enrichDriver{DB: d.DB.WithInsertOptions(o)}
+}
+
+// enrich sets KnownExploited, Exploits and Epss of v, which is a pointer to CVE, a map of CVE-ID to CVE or a slice of CVE
+func (d *enrichDriver) enrich(v interface{}) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return
+		}
+		cveID := enrichCveID(rv.Elem())
+		if cveID == "" {
+			return
+		}
+		d.enrichCVEs([]string{cveID}, []reflect.Value{rv.Elem()})
+	case reflect.Slice:
+		cveIDs, cves := []string{}, []reflect.Value{}
+		for i := 0; i < rv.Len(); i++ {
+			cveIDs = append(cveIDs, enrichCveID(rv.Index(i)))
+			cves = append(cves, rv.Index(i))
+		}
+		d.enrichCVEs(cveIDs, cves)
+	case reflect.Map:
+		keys := rv.MapKeys()
+		cveIDs, cves := []string{}, []reflect.Value{}
+		for _, k := range keys {
+			// the values of map are not addressable
+			c := reflect.New(rv.Type().Elem()).Elem()
+			c.Set(rv.MapIndex(k))
+			cveIDs = append(cveIDs, k.String())
+			cves = append(cves, c)
+		}
+		d.enrichCVEs(cveIDs, cves)
+		for i, k := range keys {
+			rv.SetMapIndex(k, cves[i])
+		}
+	}
+}
+
+// enrichCVEs sets the fields of cves[i] whose CVE-ID is cveIDs[i]. EPSS is joined only into the CVEs having Epss field
+func (d *enrichDriver) enrichCVEs(cveIDs []string, cves []reflect.Value) {
+	if len(cves) == 0 {
+		return
+	}
+
+	kev := d.DB.GetKnownExploited(cveIDs)
+	exploits := d.DB.GetExploitsMulti(cveIDs)
+	var epss map[string]models.EpssScore
+	if cves[0].FieldByName("Epss").IsValid() {
+		epss = d.DB.GetEpssMulti(cveIDs)
+	}
+	for i, c := range cves {
+		c.FieldByName("KnownExploited").SetBool(kev[cveIDs[i]])
+		if es, ok := exploits[cveIDs[i]]; ok {
+			c.FieldByName("Exploits").Set(reflect.ValueOf(es))
+		}
+		if s, ok := epss[cveIDs[i]]; ok {
+			c.FieldByName("Epss").Set(reflect.ValueOf(&s))
+		}
+	}
+	d.enrichCweDetails(cves)
+}
+
+// enrichCwe sets CweDetails of v, which is a pointer to the advisory, a map of ID to the advisory or a slice of the advisory.
+// It is for the advisories not of a CVE (e.g. GHSA), which have no KEV and EPSS.
+func (d *enrichDriver) enrichCwe(v interface{}) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			d.enrichCweDetails([]reflect.Value{rv.Elem()})
+		}
+	case reflect.Slice:
+		cves := []reflect.Value{}
+		for i := 0; i < rv.Len(); i++ {
+			cves = append(cves, rv.Index(i))
+		}
+		d.enrichCweDetails(cves)
+	case reflect.Map:
+		keys := rv.MapKeys()
+		cves := []reflect.Value{}
+		for _, k := range keys {
+			c := reflect.New(rv.Type().Elem()).Elem()
+			c.Set(rv.MapIndex(k))
+			cves = append(cves, c)
+		}
+		d.enrichCweDetails(cves)
+		for i, k := range keys {
+			rv.SetMapIndex(k, cves[i])
+		}
+	}
+}
+
+// enrichCweDetails sets CweDetails of cves from the CWE list, for the CVEs having CweDetails field
+func (d *enrichDriver) enrichCweDetails(cves []reflect.Value) {
+	if len(cves) == 0 || !cves[0].FieldByName("CweDetails").IsValid() {
+		return
+	}
+
+	cweIDs := make([][]string, len(cves))
+	uniq := map[string]struct{}{}
+	all := []string{}
+	for i, c := range cves {
+		cweIDs[i] = enrichCweIDs(c)
+		for _, id := range cweIDs[i] {
+			if _, ok := uniq[id]; !ok {
+				uniq[id] = struct{}{}
+				all = append(all, id)
+			}
+		}
+	}
+	if len(all) == 0 {
+		return
+	}
+
+	cwes := d.DB.GetCweMulti(all)
+	for i, c := range cves {
+		details := []models.Cwe{}
+		for _, id := range cweIDs[i] {
+			if cwe, ok := cwes[id]; ok {
+				details = append(details, cwe)
+			}
+		}
+		if len(details) > 0 {
+			c.FieldByName("CweDetails").Set(reflect.ValueOf(details))
+		}
+	}
+}
+
+// enrichCweIDs returns the CWE-IDs of the CVE struct. RedhatCVE has them in Cwe, MicrosoftCVE in CWE, and NvdCVE and GhsaAdvisory in Cwes
+func enrichCweIDs(c reflect.Value) (cweIDs []string) {
+	for _, name := range []string{"Cwe", "CWE"} {
+		if f := c.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+			return NormalizeCweIDs(f.String())
+		}
+	}
+	if f := c.FieldByName("Cwes"); f.IsValid() && f.Kind() == reflect.Slice {
+		vals := []string{}
+		for i := 0; i < f.Len(); i++ {
+			if id := f.Index(i).FieldByName("CweID"); id.IsValid() {
+				vals = append(vals, id.String())
+			}
+		}
+		return NormalizeCweIDs(strings.Join(vals, ","))
+	}
+	return nil
+}
+
+// enrichCveID returns the CVE-ID of the CVE struct. RedhatCVE has it in Name, and UbuntuCVE has it in Candidate
+func enrichCveID(c reflect.Value) string {
+	for _, name := range []string{"CveID", "Name", "Candidate"} {
+		if f := c.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return ""
+}
+
+// GetAfterTimeRedhat :
+func (d *enrichDriver) GetAfterTimeRedhat(after time.Time) ([]models.RedhatCVE, error) {
+	cves, err := d.DB.GetAfterTimeRedhat(after)
+	d.enrich(cves)
+	return cves, err
+}
+
+// GetRedhat :
+func (d *enrichDriver) GetRedhat(cveID string) *models.RedhatCVE {
+	c := d.DB.GetRedhat(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetRedhatMulti :
+func (d *enrichDriver) GetRedhatMulti(cveIDs []string) map[string]models.RedhatCVE {
+	m := d.DB.GetRedhatMulti(cveIDs)
+	d.enrich(m)
+	return m
+}
+
+// GetRedhatByBugzillaID :
+func (d *enrichDriver) GetRedhatByBugzillaID(bugzillaID string) map[string]models.RedhatCVE {
+	m := d.DB.GetRedhatByBugzillaID(bugzillaID)
+	d.enrich(m)
+	return m
+}
+
+// GetDebian :
+func (d *enrichDriver) GetDebian(cveID string) *models.DebianCVE {
+	c := d.DB.GetDebian(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetDebianByBugID :
+func (d *enrichDriver) GetDebianByBugID(bugID string) map[string]models.DebianCVE {
+	m := d.DB.GetDebianByBugID(bugID)
+	d.enrich(m)
+	return m
+}
+
+// GetUbuntu :
+func (d *enrichDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
+	c := d.DB.GetUbuntu(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetUbuntuByBugID :
+func (d *enrichDriver) GetUbuntuByBugID(tracker, bugID string) map[string]models.UbuntuCVE {
+	m := d.DB.GetUbuntuByBugID(tracker, bugID)
+	d.enrich(m)
+	return m
+}
+
+// GetAmazon :
+func (d *enrichDriver) GetAmazon(cveID string) *models.AmazonCVE {
+	c := d.DB.GetAmazon(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetAlpine :
+func (d *enrichDriver) GetAlpine(cveID string) *models.AlpineCVE {
+	c := d.DB.GetAlpine(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetWolfi :
+func (d *enrichDriver) GetWolfi(cveID string) *models.WolfiCVE {
+	c := d.DB.GetWolfi(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetOracle :
+func (d *enrichDriver) GetOracle(cveID string) *models.OracleCVE {
+	c := d.DB.GetOracle(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetRocky :
+func (d *enrichDriver) GetRocky(cveID string) *models.RockyCVE {
+	c := d.DB.GetRocky(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetAlma :
+func (d *enrichDriver) GetAlma(cveID string) *models.AlmaCVE {
+	c := d.DB.GetAlma(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetPhoton :
+func (d *enrichDriver) GetPhoton(cveID string) *models.PhotonCVE {
+	c := d.DB.GetPhoton(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetMariner :
+func (d *enrichDriver) GetMariner(cveID string) *models.MarinerCVE {
+	c := d.DB.GetMariner(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetOpenEuler :
+func (d *enrichDriver) GetOpenEuler(cveID string) *models.OpenEulerCVE {
+	c := d.DB.GetOpenEuler(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetAnolis :
+func (d *enrichDriver) GetAnolis(cveID string) *models.AnolisCVE {
+	c := d.DB.GetAnolis(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetMicrosoft :
+func (d *enrichDriver) GetMicrosoft(cveID string) *models.MicrosoftCVE {
+	c := d.DB.GetMicrosoft(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetMicrosoftMulti :
+func (d *enrichDriver) GetMicrosoftMulti(cveIDs []string) map[string]models.MicrosoftCVE {
+	m := d.DB.GetMicrosoftMulti(cveIDs)
+	d.enrich(m)
+	return m
+}
+
+// GetNvd :
+func (d *enrichDriver) GetNvd(cveID string) *models.NvdCVE {
+	c := d.DB.GetNvd(cveID)
+	d.enrich(c)
+	return c
+}
+
+// GetCveByID sets KnownExploited, Exploits and Epss of the records from KEV, Exploits and Epss of the result without querying them again
+func (d *enrichDriver) GetCveByID(cveID string) *models.CveDetail {
+	c := d.DB.GetCveByID(cveID)
+	if c == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(c).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() || !f.Elem().FieldByName("KnownExploited").IsValid() {
+			continue
+		}
+		r := f.Elem()
+		r.FieldByName("KnownExploited").SetBool(c.KEV != nil)
+		if c.Exploits != nil {
+			r.FieldByName("Exploits").Set(reflect.ValueOf(c.Exploits))
+		}
+		if e := r.FieldByName("Epss"); e.IsValid() && c.Epss != nil {
+			s := *c.Epss
+			e.Set(reflect.ValueOf(&s))
+		}
+		d.enrichCweDetails([]reflect.Value{r})
+	}
+	return c
+}
+
+// GetGhsa :
+func (d *enrichDriver) GetGhsa(ghsaID string) *models.GhsaAdvisory {
+	a := d.DB.GetGhsa(ghsaID)
+	d.enrichCwe(a)
+	return a
+}
+
+// GetGhsaByCveID :
+func (d *enrichDriver) GetGhsaByCveID(cveID string) map[string]models.GhsaAdvisory {
+	m := d.DB.GetGhsaByCveID(cveID)
+	d.enrichCwe(m)
+	return m
+}
+
+// GetGhsaByPackage :
+func (d *enrichDriver) GetGhsaByPackage(ecosystem, pkgName string) map[string]models.GhsaAdvisory {
+	m := d.DB.GetGhsaByPackage(ecosystem, pkgName)
+	d.enrichCwe(m)
+	return m
+}
+
+// GetUnfixedCvesRedhat :
+func (d *enrichDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix bool) map[string]models.RedhatCVE {
+	m := d.DB.GetUnfixedCvesRedhat(major, pkgName, ignoreWillNotFix)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesRedhatMulti :
+func (d *enrichDriver) GetUnfixedCvesRedhatMulti(major string, pkgNames []string, ignoreWillNotFix bool) map[string]map[string]models.RedhatCVE {
+	ms := d.DB.GetUnfixedCvesRedhatMulti(major, pkgNames, ignoreWillNotFix)
+	for _, m := range ms {
+		d.enrich(m)
+	}
+	return ms
+}
+
+// GetFixedCvesRedhat :
+func (d *enrichDriver) GetFixedCvesRedhat(major, pkgName string) map[string]models.RedhatCVE {
+	m := d.DB.GetFixedCvesRedhat(major, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesDebian :
+func (d *enrichDriver) GetUnfixedCvesDebian(codeName, pkgName string, channels ...string) map[string]models.DebianCVE {
+	m := d.DB.GetUnfixedCvesDebian(codeName, pkgName, channels...)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesDebianMulti :
+func (d *enrichDriver) GetUnfixedCvesDebianMulti(codeName string, pkgNames []string, channels ...string) map[string]map[string]models.DebianCVE {
+	ms := d.DB.GetUnfixedCvesDebianMulti(codeName, pkgNames, channels...)
+	for _, m := range ms {
+		d.enrich(m)
+	}
+	return ms
+}
+
+// GetFixedCvesDebian :
+func (d *enrichDriver) GetFixedCvesDebian(codeName, pkgName string, channels ...string) map[string]models.DebianCVE {
+	m := d.DB.GetFixedCvesDebian(codeName, pkgName, channels...)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesUbuntu :
+func (d *enrichDriver) GetUnfixedCvesUbuntu(codeName, pkgName string, channels ...string) map[string]models.UbuntuCVE {
+	m := d.DB.GetUnfixedCvesUbuntu(codeName, pkgName, channels...)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesUbuntuMulti :
+func (d *enrichDriver) GetUnfixedCvesUbuntuMulti(codeName string, pkgNames []string, channels ...string) map[string]map[string]models.UbuntuCVE {
+	ms := d.DB.GetUnfixedCvesUbuntuMulti(codeName, pkgNames, channels...)
+	for _, m := range ms {
+		d.enrich(m)
+	}
+	return ms
+}
+
+// GetFixedCvesUbuntu :
+func (d *enrichDriver) GetFixedCvesUbuntu(codeName, pkgName string, channels ...string) map[string]models.UbuntuCVE {
+	m := d.DB.GetFixedCvesUbuntu(codeName, pkgName, channels...)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesUbuntuSnap :
+func (d *enrichDriver) GetUnfixedCvesUbuntuSnap(snapName string) map[string]models.UbuntuCVE {
+	m := d.DB.GetUnfixedCvesUbuntuSnap(snapName)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesAmazon :
+func (d *enrichDriver) GetUnfixedCvesAmazon(release, pkgName string) map[string]models.AmazonCVE {
+	m := d.DB.GetUnfixedCvesAmazon(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesAmazon :
+func (d *enrichDriver) GetFixedCvesAmazon(release, pkgName string) map[string]models.AmazonCVE {
+	m := d.DB.GetFixedCvesAmazon(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesAlpine :
+func (d *enrichDriver) GetFixedCvesAlpine(release, pkgName string) map[string]models.AlpineCVE {
+	m := d.DB.GetFixedCvesAlpine(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetUnfixedCvesWolfi :
+func (d *enrichDriver) GetUnfixedCvesWolfi(distro, pkgName string) map[string]models.WolfiCVE {
+	m := d.DB.GetUnfixedCvesWolfi(distro, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesWolfi :
+func (d *enrichDriver) GetFixedCvesWolfi(distro, pkgName string) map[string]models.WolfiCVE {
+	m := d.DB.GetFixedCvesWolfi(distro, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesOracle :
+func (d *enrichDriver) GetFixedCvesOracle(release, pkgName string) map[string]models.OracleCVE {
+	m := d.DB.GetFixedCvesOracle(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesRocky :
+func (d *enrichDriver) GetFixedCvesRocky(release, pkgName string) map[string]models.RockyCVE {
+	m := d.DB.GetFixedCvesRocky(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesAlma :
+func (d *enrichDriver) GetFixedCvesAlma(release, pkgName string) map[string]models.AlmaCVE {
+	m := d.DB.GetFixedCvesAlma(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesPhoton :
+func (d *enrichDriver) GetFixedCvesPhoton(release, pkgName string) map[string]models.PhotonCVE {
+	m := d.DB.GetFixedCvesPhoton(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesMariner :
+func (d *enrichDriver) GetFixedCvesMariner(release, pkgName string) map[string]models.MarinerCVE {
+	m := d.DB.GetFixedCvesMariner(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesOpenEuler :
+func (d *enrichDriver) GetFixedCvesOpenEuler(release, pkgName string) map[string]models.OpenEulerCVE {
+	m := d.DB.GetFixedCvesOpenEuler(release, pkgName)
+	d.enrich(m)
+	return m
+}
+
+// GetFixedCvesAnolis :
+func (d *enrichDriver) GetFixedCvesAnolis(release, pkgName string) map[string]models.AnolisCVE {
+	m := d.DB.GetFixedCvesAnolis(release, pkgName)
+	d.enrich(m)
+	return m
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetEpss :
+func (r *RDBDriver) GetEpss(cveID string) *models.EpssScore {
+	c := models.EpssScore{}
+	err := r.conn.Where(&models.EpssScore{CveID: cveID}).First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get EPSS", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetEpssMulti returns the EPSS scores of cveIDs. The CVE-IDs without the score are omitted
+func (r *RDBDriver) GetEpssMulti(cveIDs []string) map[string]models.EpssScore {
+	m := map[string]models.EpssScore{}
+	// split IN clause to stay under the limit of the bind variables
+	for idx := range chunkSlice(len(cveIDs), 500) {
+		scores := []models.EpssScore{}
+		if err := r.conn.Where("cve_id IN ?", cveIDs[idx.From:idx.To]).Find(&scores).Error; err != nil {
+			r.log.Error("Failed to get EPSS", "err", err)
+			return nil
+		}
+		for _, s := range scores {
+			m[s.CveID] = s
+		}
+	}
+	return m
+}
+
+// InsertEpss :
+func (r *RDBDriver) InsertEpss(epssCSV *models.EpssCSV) (err error) {
+	scores := r.filter.filterEpss(ConvertEpss(epssCSV))
+	if err = r.deleteAndInsertEpss(r.conn, scores); err != nil {
+		return xerrors.Errorf("Failed to insert EPSS data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertEpss(conn *gorm.DB, scores []models.EpssScore) (err error) {
+	bar := startProgress(r.insert.Progress, len(scores))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.EpssScore{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old records. err: %s", err)
+	}
+
+	for idx := range chunkSlice(len(scores), r.insert.BatchSize) {
+		if err = tx.Create(scores[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertEpss converts the rows of EPSS CSV to EpssScore
+func ConvertEpss(epssCSV *models.EpssCSV) (scores []models.EpssScore) {
+	if epssCSV == nil {
+		return nil
+	}
+
+	// e.g. 2023-03-07T00:00:00+0000
+	scoreDate, err := util.ParseDate(epssCSV.ScoreDate, "2006-01-02T15:04:05-0700", "2006-01-02")
+	if err != nil {
+		log15.Warn("Failed to parse score_date of EPSS", "score_date", epssCSV.ScoreDate, "err", err)
+	}
+
+	for _, s := range epssCSV.Scores {
+		scores = append(scores, models.EpssScore{
+			CveID:        s.CveID,
+			Score:        s.Epss,
+			Percentile:   s.Percentile,
+			ModelVersion: epssCSV.ModelVersion,
+			ScoreDate:    scoreDate,
+		})
+	}
+	return scores
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm/logger"
+)
+
+// Explain has the debug information of the queries issued to answer a request
+type Explain struct {
+	mu sync.Mutex
+
+	// Queries has SQL statements (RDB) or commands with index keys (Redis)
+	Queries []ExplainQuery `json:"queries"`
+	// Candidates is the number of CVEs scanned to build the result
+	Candidates int    `json:"candidates"`
+	Elapsed    string `json:"elapsed"`
+
+	start time.Time
+}
+
+// ExplainQuery is a query issued to DB
+type ExplainQuery struct {
+	Query   string `json:"query"`
+	Rows    int64  `json:"rows,omitempty"`
+	Elapsed string `json:"elapsed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewExplain returns Explain which starts measuring the elapsed time
+func NewExplain() *Explain {
+	return &Explain{Queries: []ExplainQuery{}, start: time.Now()}
+}
+
+// Finish sets the total elapsed time
+func (e *Explain) Finish() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Elapsed = time.Since(e.start).String()
+}
+
+func (e *Explain) addQuery(q ExplainQuery) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Queries = append(e.Queries, q)
+}
+
+func (e *Explain) addCandidates(n int) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Candidates += n
+}
+
+// explainLogger records the SQL statements issued by gorm
+type explainLogger struct {
+	explain *Explain
+}
+
+func (l *explainLogger) LogMode(logger.LogLevel) logger.Interface      { return l }
+func (l *explainLogger) Info(context.Context, string, ...interface{})  {}
+func (l *explainLogger) Warn(context.Context, string, ...interface{})  {}
+func (l *explainLogger) Error(context.Context, string, ...interface{}) {}
+
+func (l *explainLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, rows := fc()
+	q := ExplainQuery{Query: sql, Rows: rows, Elapsed: time.Since(begin).String()}
+	if err != nil {
+		q.Error = err.Error()
+	}
+	l.explain.addQuery(q)
+}
+
+type explainStartKey struct{}
+
+// explainHook records the commands issued to Redis
+type explainHook struct {
+	explain *Explain
+}
+
+func (h *explainHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, explainStartKey{}, time.Now()), nil
+}
+
+func (h *explainHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.explain.addQuery(newExplainRedisQuery(ctx, cmd.String(), cmd.Err()))
+	return nil
+}
+
+func (h *explainHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, explainStartKey{}, time.Now()), nil
+}
+
+func (h *explainHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var names []string
+	for _, cmd := range cmds {
+		names = append(names, strings.Join(cmdArgs(cmd), " "))
+	}
+	h.explain.addQuery(newExplainRedisQuery(ctx, fmt.Sprintf("pipeline(%d): %s", len(cmds), strings.Join(names, "; ")), nil))
+	return nil
+}
+
+func newExplainRedisQuery(ctx context.Context, query string, err error) ExplainQuery {
+	q := ExplainQuery{Query: query}
+	if start, ok := ctx.Value(explainStartKey{}).(time.Time); ok {
+		q.Elapsed = time.Since(start).String()
+	}
+	if err != nil && err != redis.Nil {
+		q.Error = err.Error()
+	}
+	return q
+}
+
+func cmdArgs(cmd redis.Cmder) (args []string) {
+	for _, a := range cmd.Args() {
+		args = append(args, fmt.Sprint(a))
+	}
+	return args
+}
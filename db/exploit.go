@@ -0,0 +1,144 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetExploits :
+func (r *RDBDriver) GetExploits(cveID string) []models.Exploit {
+	exploits := []models.Exploit{}
+	err := r.conn.Where(&models.Exploit{CveID: cveID}).Order("source, exploit_id").Find(&exploits).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get exploits", "err", err)
+		return nil
+	}
+	return exploits
+}
+
+// GetExploitsMulti returns the exploits of cveIDs. The CVE-IDs without exploit are omitted
+func (r *RDBDriver) GetExploitsMulti(cveIDs []string) map[string][]models.Exploit {
+	m := map[string][]models.Exploit{}
+	// split IN clause to stay under the limit of the bind variables
+	for idx := range chunkSlice(len(cveIDs), 500) {
+		exploits := []models.Exploit{}
+		if err := r.conn.Where("cve_id IN ?", cveIDs[idx.From:idx.To]).Order("source, exploit_id").Find(&exploits).Error; err != nil {
+			r.log.Error("Failed to get exploits", "err", err)
+			return nil
+		}
+		for _, e := range exploits {
+			m[e.CveID] = append(m[e.CveID], e)
+		}
+	}
+	return m
+}
+
+// InsertExploit :
+func (r *RDBDriver) InsertExploit(exploitDB []models.ExploitDBCSV, metasploit []models.MetasploitModuleJSON) (err error) {
+	exploits := r.filter.filterExploit(ConvertExploit(exploitDB, metasploit, r.warnings))
+	if err = r.deleteAndInsertExploit(r.conn, exploits); err != nil {
+		return xerrors.Errorf("Failed to insert exploits. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertExploit(conn *gorm.DB, exploits []models.Exploit) (err error) {
+	bar := startProgress(r.insert.Progress, len(exploits))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.Exploit{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old records. err: %s", err)
+	}
+
+	for idx := range chunkSlice(len(exploits), r.insert.BatchSize) {
+		if err = tx.Create(exploits[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertExploit indexes the exploits of Exploit-DB and the modules of Metasploit by CVE-ID.
+// An exploit for several CVEs is stored for each of them, and the ones without CVE-ID are dropped.
+func ConvertExploit(exploitDB []models.ExploitDBCSV, metasploit []models.MetasploitModuleJSON, warnings *util.Warnings) (exploits []models.Exploit) {
+	for _, e := range exploitDB {
+		for _, cveID := range exploitCveIDs(strings.Split(e.Codes, ";")) {
+			exploits = append(exploits, models.Exploit{
+				CveID:         cveID,
+				Source:        models.ExploitSourceExploitDB,
+				ExploitID:     e.ID,
+				Title:         e.Description,
+				Type:          e.Type,
+				Platform:      e.Platform,
+				URL:           fmt.Sprintf("https://www.exploit-db.com/exploits/%s", e.ID),
+				Verified:      e.Verified == "1",
+				PublishedDate: parseExploitDate(models.ExploitSourceExploitDB, cveID, e.DatePublished, warnings),
+			})
+		}
+	}
+
+	for _, m := range metasploit {
+		for _, cveID := range exploitCveIDs(m.References) {
+			exploits = append(exploits, models.Exploit{
+				CveID:         cveID,
+				Source:        models.ExploitSourceMetasploit,
+				ExploitID:     m.Fullname,
+				Title:         m.Name,
+				Type:          m.Type,
+				Platform:      m.Platform,
+				URL:           "https://github.com/rapid7/metasploit-framework/blob/master" + m.Path,
+				PublishedDate: parseExploitDate(models.ExploitSourceMetasploit, cveID, m.DisclosureDate, warnings),
+			})
+		}
+	}
+	return exploits
+}
+
+// exploitCveIDs returns the CVE-IDs among the references (e.g. CVE-2021-44228, OSVDB-12345, URL-https://...) without duplicates
+func exploitCveIDs(refs []string) (cveIDs []string) {
+	uniq := map[string]struct{}{}
+	for _, ref := range refs {
+		ref = strings.ToUpper(strings.TrimSpace(ref))
+		if !strings.HasPrefix(ref, "CVE-") {
+			continue
+		}
+		if _, ok := uniq[ref]; ok {
+			continue
+		}
+		uniq[ref] = struct{}{}
+		cveIDs = append(cveIDs, ref)
+	}
+	return cveIDs
+}
+
+// parseExploitDate parses the published date of Exploit-DB and the disclosure date of Metasploit (e.g. 2021-12-14)
+func parseExploitDate(source, cveID, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, "2006-01-02")
+	if err != nil {
+		warnings.Add(source, cveID, "date", fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,657 @@
+package db
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+)
+
+// Filter drops the CVEs on insert to shrink DB for constrained deployments (e.g. embedded scanners).
+// It is set by WithFilter. gost fetch configures it by filter.min-severity, filter.min-cve-year, filter.packages and filter.releases in the config file.
+type Filter struct {
+	// MinSeverity is low, moderate (medium), important (high) or critical
+	MinSeverity string
+	// MinCveYear is compared with the year in CVE-ID
+	MinCveYear int
+	// Packages keeps the CVEs touching any of the packages. It is not applied to Microsoft
+	Packages []string
+	// Releases keeps only the statuses of the releases by source (e.g. debian: [bookworm], ubuntu: [jammy], alpine: ["3.19"], amazon: ["2023"]),
+	// and drops the CVEs without them. The sources not in Releases keep all releases.
+	Releases map[string][]string
+}
+
+func (f Filter) isEmpty() bool {
+	return f.MinSeverity == "" && f.MinCveYear == 0 && len(f.Packages) == 0 && len(f.Releases) == 0
+}
+
+// severityRanks normalizes the severities of the sources
+var severityRanks = map[string]int{
+	"negligible":  0,
+	"unimportant": 0,
+	"low":         1,
+	"medium":      2,
+	"moderate":    2,
+	"high":        3,
+	"important":   3,
+	"critical":    4,
+}
+
+// severityOK returns false if the severity is lower than MinSeverity.
+// Unknown severities (e.g. "not yet assigned") are kept.
+func (f Filter) severityOK(severities ...string) bool {
+	min, ok := severityRanks[strings.ToLower(f.MinSeverity)]
+	if !ok {
+		return true
+	}
+	known := false
+	for _, s := range severities {
+		rank, ok := severityRanks[strings.ToLower(strings.TrimRight(strings.TrimSpace(s), "*"))]
+		if !ok {
+			continue
+		}
+		if rank >= min {
+			return true
+		}
+		known = true
+	}
+	return !known
+}
+
+func (f Filter) yearOK(cveID string) bool {
+	if f.MinCveYear == 0 {
+		return true
+	}
+	ss := strings.Split(cveID, "-")
+	if len(ss) < 2 {
+		return true
+	}
+	year, err := strconv.Atoi(ss[1])
+	if err != nil {
+		return true
+	}
+	return year >= f.MinCveYear
+}
+
+func (f Filter) packageOK(pkgNames ...string) bool {
+	if len(f.Packages) == 0 {
+		return true
+	}
+	for _, p := range f.Packages {
+		for _, name := range pkgNames {
+			if p == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// releaseOK returns false if Releases has the source and not the release
+func (f Filter) releaseOK(source, release string) bool {
+	releases, ok := f.Releases[source]
+	if !ok {
+		return true
+	}
+	for _, r := range releases {
+		if r == release {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Filter) logFiltered(source string, before, after int) {
+	if !f.isEmpty() {
+		log15.Info("Filtered CVEs on insert", "source", source, "before", before, "after", after)
+	}
+}
+
+// e.g. openssl-1:1.1.1g-15.el8_3
+var redhatNVRRegexp = regexp.MustCompile(`^(.+)-(?:\d+:)?[^-]+-[^-]+$`)
+
+func (f Filter) filterRedhat(cves []models.RedhatCVE) (filtered []models.RedhatCVE) {
+	for _, c := range cves {
+		pkgNames := []string{}
+		for _, p := range c.PackageState {
+			pkgNames = append(pkgNames, p.PackageName)
+		}
+		for _, a := range c.AffectedRelease {
+			if ss := redhatNVRRegexp.FindStringSubmatch(a.Package); ss != nil {
+				pkgNames = append(pkgNames, ss[1])
+			}
+		}
+		if f.yearOK(c.Name) && f.severityOK(c.ThreatSeverity) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("redhat", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterDebian(cves []models.DebianCVE) (filtered []models.DebianCVE) {
+	for _, c := range cves {
+		pkgs := []models.DebianPackage{}
+		for _, p := range c.Package {
+			releases := []models.DebianRelease{}
+			for _, r := range p.Release {
+				if f.releaseOK("debian", r.ProductName) {
+					releases = append(releases, r)
+				}
+			}
+			if len(releases) > 0 {
+				p.Release = releases
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(pkgs) == 0 && len(c.Package) > 0 {
+			continue
+		}
+		c.Package = pkgs
+
+		pkgNames, urgencies := []string{}, []string{}
+		for _, p := range c.Package {
+			pkgNames = append(pkgNames, p.PackageName)
+			for _, r := range p.Release {
+				urgencies = append(urgencies, r.Urgency)
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(urgencies...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("debian", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterUbuntu(cves []models.UbuntuCVE) (filtered []models.UbuntuCVE) {
+	for _, c := range cves {
+		patches := []models.UbuntuPatch{}
+		for _, p := range c.Patches {
+			releasePatches := []models.UbuntuReleasePatch{}
+			for _, r := range p.ReleasePatches {
+				if f.releaseOK("ubuntu", r.ReleaseName) {
+					releasePatches = append(releasePatches, r)
+				}
+			}
+			if len(releasePatches) > 0 {
+				p.ReleasePatches = releasePatches
+				patches = append(patches, p)
+			}
+		}
+		if len(patches) == 0 && len(c.Patches) > 0 {
+			continue
+		}
+		c.Patches = patches
+
+		pkgNames := []string{}
+		for _, p := range c.Patches {
+			pkgNames = append(pkgNames, p.PackageName)
+		}
+		if f.yearOK(c.Candidate) && f.severityOK(c.Priority) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("ubuntu", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterAmazon(cves []models.AmazonCVE) (filtered []models.AmazonCVE) {
+	for _, c := range cves {
+		advisories := []models.AmazonAdvisory{}
+		for _, a := range c.Advisories {
+			if f.releaseOK("amazon", a.ReleaseName) {
+				advisories = append(advisories, a)
+			}
+		}
+		if len(advisories) == 0 && len(c.Advisories) > 0 {
+			continue
+		}
+		c.Advisories = advisories
+
+		pkgNames, severities := []string{}, []string{}
+		for _, a := range c.Advisories {
+			severities = append(severities, a.Severity)
+			for _, p := range a.Packages {
+				pkgNames = append(pkgNames, p.PackageName)
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("amazon", len(cves), len(filtered))
+	return filtered
+}
+
+// filterAlpine does not filter by severity, since secdb has no severity
+func (f Filter) filterAlpine(cves []models.AlpineCVE) (filtered []models.AlpineCVE) {
+	for _, c := range cves {
+		pkgs := []models.AlpinePackage{}
+		for _, p := range c.Packages {
+			if f.releaseOK("alpine", p.ReleaseName) {
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(pkgs) == 0 && len(c.Packages) > 0 {
+			continue
+		}
+		c.Packages = pkgs
+
+		pkgNames := []string{}
+		for _, p := range c.Packages {
+			pkgNames = append(pkgNames, p.PackageName)
+		}
+		if f.yearOK(c.CveID) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("alpine", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterOracle(cves []models.OracleCVE) (filtered []models.OracleCVE) {
+	for _, c := range cves {
+		pkgNames, severities := []string{}, []string{}
+		for _, a := range c.Advisories {
+			severities = append(severities, a.Severity)
+			for _, p := range a.Packages {
+				pkgNames = append(pkgNames, p.PackageName)
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("oracle", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterRocky(cves []models.RockyCVE) (filtered []models.RockyCVE) {
+	for _, c := range cves {
+		pkgNames, severities := []string{}, []string{}
+		for _, a := range c.Advisories {
+			severities = append(severities, a.Severity)
+			for _, p := range a.Packages {
+				pkgNames = append(pkgNames, p.PackageName)
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("rocky", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterAlma(cves []models.AlmaCVE) (filtered []models.AlmaCVE) {
+	for _, c := range cves {
+		pkgNames, severities := []string{}, []string{}
+		for _, a := range c.Advisories {
+			severities = append(severities, a.Severity)
+			for _, p := range a.Packages {
+				pkgNames = append(pkgNames, p.PackageName)
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("alma", len(cves), len(filtered))
+	return filtered
+}
+
+// filterMicrosoft does not filter by packages, since Microsoft CVEs are tied to products and KBs
+func (f Filter) filterMicrosoft(cves []models.MicrosoftCVE) (filtered []models.MicrosoftCVE) {
+	for _, c := range cves {
+		severities := []string{}
+		for _, s := range c.Severity {
+			severities = append(severities, s.Description)
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("microsoft", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterPhoton(cves []models.PhotonCVE) (filtered []models.PhotonCVE) {
+	for _, c := range cves {
+		pkgNames, severities := []string{}, []string{}
+		for _, p := range c.Packages {
+			pkgNames = append(pkgNames, p.PackageName)
+			severities = append(severities, photonSeverity(p.CveScore))
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("photon", len(cves), len(filtered))
+	return filtered
+}
+
+// photonSeverity maps the CVSS v3 score to the qualitative rating, since Photon OS has no severity
+func photonSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "critical"
+	case score >= 7.0:
+		return "high"
+	case score >= 4.0:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return ""
+}
+
+func (f Filter) filterMariner(cves []models.MarinerCVE) (filtered []models.MarinerCVE) {
+	for _, c := range cves {
+		pkgNames, severities := []string{}, []string{}
+		for _, p := range c.Packages {
+			pkgNames = append(pkgNames, p.PackageName)
+			severities = append(severities, p.Severity)
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("mariner", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterOpenEuler(cves []models.OpenEulerCVE) (filtered []models.OpenEulerCVE) {
+	for _, c := range cves {
+		pkgNames, severities := []string{}, []string{}
+		for _, a := range c.Advisories {
+			severities = append(severities, a.Severity)
+			for _, p := range a.Packages {
+				pkgNames = append(pkgNames, p.PackageName)
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("openeuler", len(cves), len(filtered))
+	return filtered
+}
+
+func (f Filter) filterAnolis(cves []models.AnolisCVE) (filtered []models.AnolisCVE) {
+	for _, c := range cves {
+		pkgNames, severities := []string{}, []string{}
+		for _, a := range c.Advisories {
+			severities = append(severities, a.Severity)
+			for _, p := range a.Packages {
+				pkgNames = append(pkgNames, p.PackageName)
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("anolis", len(cves), len(filtered))
+	return filtered
+}
+
+// filterNvd matches Packages with the product of CPE (e.g. cpe:2.3:a:openssl:openssl:...)
+func (f Filter) filterNvd(cves []models.NvdCVE) (filtered []models.NvdCVE) {
+	for _, c := range cves {
+		products, severities := []string{}, []string{}
+		for _, cvss := range c.Cvss {
+			severities = append(severities, cvss.BaseSeverity)
+		}
+		for _, cpe := range c.Cpes {
+			if ss := strings.Split(cpe.Criteria, ":"); len(ss) > 4 {
+				products = append(products, ss[4])
+			}
+		}
+		if f.yearOK(c.CveID) && f.severityOK(severities...) && f.packageOK(products...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("nvd", len(cves), len(filtered))
+	return filtered
+}
+
+// filterCveProgram applies MinCveYear only, since the records of CVE Program have neither severity nor package
+func (f Filter) filterCveProgram(cves []models.CveProgramCVE) (filtered []models.CveProgramCVE) {
+	for _, c := range cves {
+		if f.yearOK(c.CveID) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("cveprogram", len(cves), len(filtered))
+	return filtered
+}
+
+// filterKernel applies MinCveYear only, since kernel.org gives no severity and the kernel is packaged in various names by the distros
+func (f Filter) filterKernel(cves []models.KernelCVE) (filtered []models.KernelCVE) {
+	for _, c := range cves {
+		if f.yearOK(c.CveID) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("kernel", len(cves), len(filtered))
+	return filtered
+}
+
+// filterKEV applies MinCveYear only, since KEV has no severity and its products are not package names
+func (f Filter) filterKEV(entries []models.KEVEntry) (filtered []models.KEVEntry) {
+	for _, e := range entries {
+		if f.yearOK(e.CveID) {
+			filtered = append(filtered, e)
+		}
+	}
+	f.logFiltered("kev", len(entries), len(filtered))
+	return filtered
+}
+
+// filterEpss applies MinCveYear only, since EPSS has no severity and no package
+func (f Filter) filterEpss(scores []models.EpssScore) (filtered []models.EpssScore) {
+	for _, s := range scores {
+		if f.yearOK(s.CveID) {
+			filtered = append(filtered, s)
+		}
+	}
+	f.logFiltered("epss", len(scores), len(filtered))
+	return filtered
+}
+
+// filterGhsa matches MinCveYear with the CVE-IDs of the advisory, and Packages with the package names.
+// The advisories without CVE-ID are kept by MinCveYear.
+func (f Filter) filterGhsa(advisories []models.GhsaAdvisory) (filtered []models.GhsaAdvisory) {
+	for _, a := range advisories {
+		yearOK := true
+		for _, id := range a.Identifiers {
+			if id.Type == "CVE" {
+				yearOK = f.yearOK(id.Value)
+				break
+			}
+		}
+		pkgNames := []string{}
+		for _, v := range a.Vulnerabilities {
+			pkgNames = append(pkgNames, v.PackageName)
+		}
+		if yearOK && f.severityOK(a.Severity) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, a)
+		}
+	}
+	f.logFiltered("ghsa", len(advisories), len(filtered))
+	return filtered
+}
+
+// filterOsv matches MinCveYear with the CVE-IDs in the aliases, and Packages with the package names.
+// The vulnerabilities without CVE-ID are kept by MinCveYear.
+func (f Filter) filterOsv(entries []models.OsvEntry) (filtered []models.OsvEntry) {
+	for _, e := range entries {
+		cveID := e.OsvID
+		if !strings.HasPrefix(cveID, "CVE-") {
+			v := models.OsvJSON{}
+			if err := json.Unmarshal([]byte(e.Raw), &v); err == nil {
+				for _, alias := range v.Aliases {
+					if strings.HasPrefix(alias, "CVE-") {
+						cveID = alias
+						break
+					}
+				}
+			}
+		}
+		pkgNames := []string{}
+		for _, p := range e.Packages {
+			pkgNames = append(pkgNames, p.PackageName)
+		}
+		if f.yearOK(cveID) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, e)
+		}
+	}
+	f.logFiltered("osv", len(entries), len(filtered))
+	return filtered
+}
+
+// filterGoVuln matches MinCveYear with the CVE-IDs in the aliases, and Packages with the module path.
+// The vulnerabilities without CVE-ID are kept by MinCveYear.
+func (f Filter) filterGoVuln(vulns []models.GoVuln) (filtered []models.GoVuln) {
+	for _, v := range vulns {
+		yearOK := true
+		for _, a := range v.Aliases {
+			if strings.HasPrefix(a.Alias, "CVE-") {
+				yearOK = f.yearOK(a.Alias)
+				break
+			}
+		}
+		if yearOK && f.packageOK(v.ModulePath) {
+			filtered = append(filtered, v)
+		}
+	}
+	f.logFiltered("govuln", len(vulns), len(filtered))
+	return filtered
+}
+
+// filterJvn matches MinCveYear with the first CVE-ID linked, and Packages with the product of CPE 2.2 (e.g. cpe:/a:apache:log4j)
+func (f Filter) filterJvn(advisories []models.JvnAdvisory) (filtered []models.JvnAdvisory) {
+	for _, a := range advisories {
+		yearOK := true
+		if len(a.CveIDs) > 0 {
+			yearOK = f.yearOK(a.CveIDs[0].CveID)
+		}
+		products := []string{}
+		for _, v := range a.Vendors {
+			if ss := strings.Split(v.Cpe, ":"); len(ss) > 3 {
+				products = append(products, ss[3])
+			}
+		}
+		if yearOK && f.severityOK(a.Cvss3Severity, a.Cvss2Severity) && f.packageOK(products...) {
+			filtered = append(filtered, a)
+		}
+	}
+	f.logFiltered("jvn", len(advisories), len(filtered))
+	return filtered
+}
+
+// filterPsirt matches MinCveYear with the first CVE-ID linked, and Packages with the products
+func (f Filter) filterPsirt(advisories []models.PsirtAdvisory) (filtered []models.PsirtAdvisory) {
+	for _, a := range advisories {
+		yearOK := true
+		if len(a.CveIDs) > 0 {
+			yearOK = f.yearOK(a.CveIDs[0].CveID)
+		}
+		products := []string{}
+		for _, p := range a.Products {
+			products = append(products, p.Product)
+		}
+		if yearOK && f.severityOK(a.Severity) && f.packageOK(products...) {
+			filtered = append(filtered, a)
+		}
+	}
+	f.logFiltered("psirt", len(advisories), len(filtered))
+	return filtered
+}
+
+// filterApple matches MinCveYear with the first CVE-ID fixed, and Packages with the products (e.g. macos, ios)
+func (f Filter) filterApple(releases []models.AppleSecurityRelease) (filtered []models.AppleSecurityRelease) {
+	for _, a := range releases {
+		yearOK := true
+		if len(a.CveIDs) > 0 {
+			yearOK = f.yearOK(a.CveIDs[0].CveID)
+		}
+		products := []string{}
+		for _, p := range a.Products {
+			products = append(products, p.Product)
+		}
+		if yearOK && f.packageOK(products...) {
+			filtered = append(filtered, a)
+		}
+	}
+	f.logFiltered("apple", len(releases), len(filtered))
+	return filtered
+}
+
+// filterWolfi does not filter by severity, since the advisories of Chainguard have no severity
+func (f Filter) filterWolfi(cves []models.WolfiCVE) (filtered []models.WolfiCVE) {
+	for _, c := range cves {
+		pkgNames := []string{}
+		for _, p := range c.Packages {
+			pkgNames = append(pkgNames, p.PackageName)
+		}
+		if f.yearOK(c.CveID) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, c)
+		}
+	}
+	f.logFiltered("wolfi", len(cves), len(filtered))
+	return filtered
+}
+
+// filterCn matches MinCveYear with the first CVE-ID linked, MinSeverity with the normalized severity, and Packages with the products.
+// CNNVD has no product, so Packages is not applied to the advisories without products
+func (f Filter) filterCn(advisories []models.CnAdvisory) (filtered []models.CnAdvisory) {
+	for _, a := range advisories {
+		yearOK := true
+		if len(a.CveIDs) > 0 {
+			yearOK = f.yearOK(a.CveIDs[0].CveID)
+		}
+		products := []string{}
+		for _, p := range a.Products {
+			products = append(products, p.Product)
+		}
+		if yearOK && f.severityOK(a.Severity) && (len(products) == 0 || f.packageOK(products...)) {
+			filtered = append(filtered, a)
+		}
+	}
+	f.logFiltered("cn", len(advisories), len(filtered))
+	return filtered
+}
+
+// filterPackageAdvisories matches MinCveYear with the first CVE-ID linked, MinSeverity with the severity, and Packages with the package names
+func (f Filter) filterPackageAdvisories(advisories []models.PackageAdvisory) (filtered []models.PackageAdvisory) {
+	for _, a := range advisories {
+		yearOK := true
+		if len(a.CveIDs) > 0 {
+			yearOK = f.yearOK(a.CveIDs[0].CveID)
+		}
+		pkgNames := []string{}
+		for _, v := range a.Vulnerabilities {
+			pkgNames = append(pkgNames, v.PackageName)
+		}
+		if yearOK && f.severityOK(a.Severity) && f.packageOK(pkgNames...) {
+			filtered = append(filtered, a)
+		}
+	}
+	f.logFiltered("advisorydb", len(advisories), len(filtered))
+	return filtered
+}
+
+// filterExploit applies MinCveYear only, since the exploits have no severity and no package
+func (f Filter) filterExploit(exploits []models.Exploit) (filtered []models.Exploit) {
+	for _, e := range exploits {
+		if f.yearOK(e.CveID) {
+			filtered = append(filtered, e)
+		}
+	}
+	f.logFiltered("exploit", len(exploits), len(filtered))
+	return filtered
+}
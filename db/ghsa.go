@@ -0,0 +1,282 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetGhsa :
+func (r *RDBDriver) GetGhsa(ghsaID string) *models.GhsaAdvisory {
+	a := models.GhsaAdvisory{}
+	err := r.conn.
+		Preload("Identifiers").
+		Preload("References").
+		Preload("Vulnerabilities").
+		Preload("Cwes").
+		Where(&models.GhsaAdvisory{GhsaID: ghsaID}).
+		First(&a).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get GHSA", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetGhsaByCveID gets the advisories of the CVE
+func (r *RDBDriver) GetGhsaByCveID(cveID string) map[string]models.GhsaAdvisory {
+	m := map[string]models.GhsaAdvisory{}
+	identifiers := []models.GhsaIdentifier{}
+	err := r.conn.Where(&models.GhsaIdentifier{Type: "CVE", Value: cveID}).Find(&identifiers).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get GHSA by CVE-ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(identifiers))
+	for _, i := range identifiers {
+		a := models.GhsaAdvisory{}
+		err := r.conn.
+			Preload("Identifiers").
+			Preload("References").
+			Preload("Vulnerabilities").
+			Preload("Cwes").
+			Where(&models.GhsaAdvisory{ID: i.GhsaAdvisoryID}).
+			First(&a).Error
+		if err != nil {
+			r.log.Error("Failed to get GHSA by CVE-ID", "err", err)
+			return nil
+		}
+		m[a.GhsaID] = a
+	}
+	return m
+}
+
+// GetGhsaByPackage gets the advisories of the package in the ecosystem.
+// Vulnerabilities of the advisories are only the ones of the package.
+func (r *RDBDriver) GetGhsaByPackage(ecosystem, pkgName string) map[string]models.GhsaAdvisory {
+	vulns := []models.GhsaVulnerability{}
+	err := r.conn.Where(&models.GhsaVulnerability{Ecosystem: ecosystem, PackageName: pkgName}).Find(&vulns).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get GHSA by package", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(vulns))
+	byID := map[int64]models.GhsaAdvisory{}
+	for _, v := range vulns {
+		a, ok := byID[v.GhsaAdvisoryID]
+		if !ok {
+			err := r.conn.
+				Preload("Identifiers").
+				Preload("References").
+				Preload("Cwes").
+				Where(&models.GhsaAdvisory{ID: v.GhsaAdvisoryID}).
+				First(&a).Error
+			if err != nil {
+				r.log.Error("Failed to get GHSA by package", "err", err)
+				return nil
+			}
+		}
+		a.Vulnerabilities = append(a.Vulnerabilities, v)
+		byID[v.GhsaAdvisoryID] = a
+	}
+
+	m := map[string]models.GhsaAdvisory{}
+	for _, a := range byID {
+		m[a.GhsaID] = a
+	}
+	return m
+}
+
+// InsertGhsa :
+func (r *RDBDriver) InsertGhsa(vulnJSONs []models.GhsaVulnerabilityJSON) (err error) {
+	advisories := r.filter.filterGhsa(ConvertGhsa(vulnJSONs, r.warnings))
+	if err = r.deleteAndInsertGhsa(r.conn, advisories); err != nil {
+		return xerrors.Errorf("Failed to insert GitHub Security Advisories. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertGhsa(conn *gorm.DB, advisories []models.GhsaAdvisory) (err error) {
+	bar := startProgress(r.insert.Progress, len(advisories))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GhsaIdentifier{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GhsaReference{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GhsaCwe{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GhsaVulnerability{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GhsaAdvisory{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(advisories), r.insert.BatchSize) {
+		if err = tx.Create(advisories[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertGhsa groups the vulnerabilities of GitHub GraphQL API by advisory. Withdrawn advisories are skipped.
+func ConvertGhsa(vulnJSONs []models.GhsaVulnerabilityJSON, warnings *util.Warnings) (advisories []models.GhsaAdvisory) {
+	indexes := map[string]int{}
+	for _, v := range vulnJSONs {
+		if v.Advisory.WithdrawnAt != nil {
+			continue
+		}
+
+		i, ok := indexes[v.Advisory.GhsaID]
+		if !ok {
+			a := v.Advisory
+			advisory := models.GhsaAdvisory{
+				GhsaID:           a.GhsaID,
+				Summary:          a.Summary,
+				Description:      a.Description,
+				Severity:         a.Severity,
+				CvssScore:        a.Cvss.Score,
+				CvssVector:       a.Cvss.VectorString,
+				PublishedDate:    parseGhsaDate(a.GhsaID, "publishedAt", a.PublishedAt, warnings),
+				LastModifiedDate: parseGhsaDate(a.GhsaID, "updatedAt", a.UpdatedAt, warnings),
+			}
+			for _, id := range a.Identifiers {
+				advisory.Identifiers = append(advisory.Identifiers, models.GhsaIdentifier{Type: id.Type, Value: id.Value})
+			}
+			for _, ref := range a.References {
+				advisory.References = append(advisory.References, models.GhsaReference{URL: ref.URL})
+			}
+			for _, c := range a.Cwes.Nodes {
+				for _, cweID := range NormalizeCweIDs(c.CweID) {
+					advisory.Cwes = append(advisory.Cwes, models.GhsaCwe{CweID: cweID})
+				}
+			}
+			i = len(advisories)
+			indexes[a.GhsaID] = i
+			advisories = append(advisories, advisory)
+		}
+
+		ecosystem := NormalizeGhsaEcosystem(v.Package.Ecosystem)
+		vuln := models.GhsaVulnerability{
+			Ecosystem:              ecosystem,
+			PackageName:            NormalizeGhsaPackageName(ecosystem, v.Package.Name),
+			VulnerableVersionRange: v.VulnerableVersionRange,
+		}
+		if v.FirstPatchedVersion != nil {
+			vuln.FirstPatchedVersion = v.FirstPatchedVersion.Identifier
+		}
+		advisories[i].Vulnerabilities = append(advisories[i].Vulnerabilities, vuln)
+	}
+	return advisories
+}
+
+// parseGhsaDate parses the date of GitHub GraphQL API (e.g. 2021-12-10T00:40:56Z)
+func parseGhsaDate(ghsaID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339)
+	if err != nil {
+		warnings.Add("ghsa", ghsaID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// ghsaEcosystems maps the common names of the ecosystems to the ones of GitHub
+var ghsaEcosystems = map[string]string{
+	"npm":      "NPM",
+	"pip":      "PIP",
+	"pypi":     "PIP",
+	"python":   "PIP",
+	"go":       "GO",
+	"golang":   "GO",
+	"maven":    "MAVEN",
+	"rubygems": "RUBYGEMS",
+	"gem":      "RUBYGEMS",
+	"ruby":     "RUBYGEMS",
+}
+
+// NormalizeGhsaEcosystem normalizes the ecosystem (e.g. pypi) to the one of GitHub (e.g. PIP)
+func NormalizeGhsaEcosystem(ecosystem string) string {
+	if e, ok := ghsaEcosystems[strings.ToLower(ecosystem)]; ok {
+		return e
+	}
+	return strings.ToUpper(ecosystem)
+}
+
+var pipNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizeGhsaPackageName normalizes the names of PyPI packages as PEP 503 (e.g. Django, zope.interface -> django, zope-interface).
+// The names in the other ecosystems are case-sensitive, so they are returned as they are.
+func NormalizeGhsaPackageName(ecosystem, pkgName string) string {
+	if ecosystem == "PIP" {
+		return pipNameSeparators.ReplaceAllString(strings.ToLower(pkgName), "-")
+	}
+	return pkgName
+}
+
+// GetVulnerableGhsa gets the advisories affecting the version of the package in the ecosystem.
+// The ecosystem and the package name are normalized. If ver is empty, all advisories of the package are returned.
+func GetVulnerableGhsa(driver DB, ecosystem, pkgName, ver string) map[string]models.GhsaAdvisory {
+	ecosystem = NormalizeGhsaEcosystem(ecosystem)
+	advisories := driver.GetGhsaByPackage(ecosystem, NormalizeGhsaPackageName(ecosystem, pkgName))
+	if ver == "" {
+		return advisories
+	}
+
+	m := map[string]models.GhsaAdvisory{}
+	for ghsaID, a := range advisories {
+		vulns := []models.GhsaVulnerability{}
+		for _, v := range a.Vulnerabilities {
+			if ghsaVersionAffected(v.VulnerableVersionRange, ver) {
+				vulns = append(vulns, v)
+			}
+		}
+		if len(vulns) > 0 {
+			a.Vulnerabilities = vulns
+			m[ghsaID] = a
+		}
+	}
+	return m
+}
+
+// ghsaVersionAffected returns true if ver is in the range (e.g. ">= 4.0.0, < 4.0.6").
+// If the range or the version cannot be parsed, it returns true so that the advisory is not missed.
+func ghsaVersionAffected(versionRange, ver string) bool {
+	c, err := version.NewConstraint(versionRange)
+	if err != nil {
+		log15.Debug("Failed to parse the version range of GHSA", "range", versionRange, "err", err)
+		return true
+	}
+	v, err := version.NewVersion(ver)
+	if err != nil {
+		log15.Debug("Failed to parse the version", "version", ver, "err", err)
+		return true
+	}
+	return c.Check(v)
+}
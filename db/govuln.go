@@ -0,0 +1,217 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetGoVulnsByModule gets the vulnerabilities of the module
+func (r *RDBDriver) GetGoVulnsByModule(modulePath string) map[string]models.GoVuln {
+	vulns := []models.GoVuln{}
+	err := r.conn.
+		Preload("Aliases").
+		Preload("Ranges").
+		Preload("Symbols").
+		Where(&models.GoVuln{ModulePath: modulePath}).
+		Find(&vulns).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Go vulnerabilities by module", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(vulns))
+	m := map[string]models.GoVuln{}
+	for _, v := range vulns {
+		m[v.GoID] = v
+	}
+	return m
+}
+
+// InsertGoVuln :
+func (r *RDBDriver) InsertGoVuln(vulnJSONs []models.GoVulnJSON) (err error) {
+	vulns := r.filter.filterGoVuln(ConvertGoVuln(vulnJSONs, r.warnings))
+	if err = r.deleteAndInsertGoVuln(r.conn, vulns); err != nil {
+		return xerrors.Errorf("Failed to insert Go vulnerabilities. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertGoVuln(conn *gorm.DB, vulns []models.GoVuln) (err error) {
+	bar := startProgress(r.insert.Progress, len(vulns))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GoVulnAlias{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GoVulnRange{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GoVulnSymbol{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.GoVuln{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(vulns), r.insert.BatchSize) {
+		if err = tx.Create(vulns[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertGoVuln converts the entries of the Go vulnerability database to a GoVuln per module.
+// Withdrawn entries are skipped.
+func ConvertGoVuln(vulnJSONs []models.GoVulnJSON, warnings *util.Warnings) (vulns []models.GoVuln) {
+	for _, v := range vulnJSONs {
+		if v.Withdrawn != "" {
+			continue
+		}
+
+		aliases := []models.GoVulnAlias{}
+		for _, a := range v.Aliases {
+			aliases = append(aliases, models.GoVulnAlias{Alias: a})
+		}
+		for _, a := range v.Affected {
+			vuln := models.GoVuln{
+				GoID:          v.ID,
+				ModulePath:    a.Package.Name,
+				Summary:       v.Summary,
+				Details:       v.Details,
+				PublishedDate: parseGoVulnDate(v.ID, "published", v.Published, warnings),
+				ModifiedDate:  parseGoVulnDate(v.ID, "modified", v.Modified, warnings),
+				Aliases:       append([]models.GoVulnAlias{}, aliases...),
+			}
+			for _, r := range a.Ranges {
+				if r.Type != "SEMVER" {
+					continue
+				}
+				vuln.Ranges = append(vuln.Ranges, convertGoVulnRanges(r.Events)...)
+			}
+			for _, i := range a.EcosystemSpecific.Imports {
+				goos, goarch := strings.Join(i.Goos, ","), strings.Join(i.Goarch, ",")
+				if len(i.Symbols) == 0 {
+					vuln.Symbols = append(vuln.Symbols, models.GoVulnSymbol{PackagePath: i.Path, Goos: goos, Goarch: goarch})
+					continue
+				}
+				for _, s := range i.Symbols {
+					vuln.Symbols = append(vuln.Symbols, models.GoVulnSymbol{PackagePath: i.Path, Symbol: s, Goos: goos, Goarch: goarch})
+				}
+			}
+			vulns = append(vulns, vuln)
+		}
+	}
+	return vulns
+}
+
+// convertGoVulnRanges pairs the introduced and the fixed events
+func convertGoVulnRanges(events []models.OsvEventJSON) (ranges []models.GoVulnRange) {
+	var cur *models.GoVulnRange
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if cur != nil {
+				ranges = append(ranges, *cur)
+			}
+			cur = &models.GoVulnRange{Introduced: e.Introduced}
+		case e.Fixed != "":
+			if cur == nil {
+				cur = &models.GoVulnRange{Introduced: "0"}
+			}
+			cur.Fixed = e.Fixed
+			ranges = append(ranges, *cur)
+			cur = nil
+		}
+	}
+	if cur != nil {
+		ranges = append(ranges, *cur)
+	}
+	return ranges
+}
+
+// parseGoVulnDate parses the date of the Go vulnerability database (e.g. 2021-04-14T20:04:52Z)
+func parseGoVulnDate(goID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339)
+	if err != nil {
+		warnings.Add("govuln", goID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetVulnerableGoVulns gets the vulnerabilities affecting the version of the module.
+// The version of stdlib and toolchain may be a Go version (e.g. go1.19.3). If ver is empty, all vulnerabilities of the module are returned.
+func GetVulnerableGoVulns(driver DB, modulePath, ver string) map[string]models.GoVuln {
+	vulns := driver.GetGoVulnsByModule(modulePath)
+	if ver == "" {
+		return vulns
+	}
+
+	m := map[string]models.GoVuln{}
+	for goID, v := range vulns {
+		if goVulnAffected(v.Ranges, strings.TrimPrefix(ver, "go")) {
+			m[goID] = v
+		}
+	}
+	return m
+}
+
+// goVulnAffected returns true if ver is in any of the ranges. No range means all versions are affected.
+// If the versions cannot be parsed, it returns true so that the vulnerability is not missed.
+func goVulnAffected(ranges []models.GoVulnRange, ver string) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	v, err := version.NewVersion(ver)
+	if err != nil {
+		log15.Debug("Failed to parse the version", "version", ver, "err", err)
+		return true
+	}
+	for _, r := range ranges {
+		if r.Introduced != "0" {
+			introduced, err := version.NewVersion(r.Introduced)
+			if err != nil {
+				log15.Debug("Failed to parse the version of Go vulnerability", "version", r.Introduced, "err", err)
+				return true
+			}
+			if v.LessThan(introduced) {
+				continue
+			}
+		}
+		if r.Fixed == "" {
+			return true
+		}
+		fixed, err := version.NewVersion(r.Fixed)
+		if err != nil {
+			log15.Debug("Failed to parse the version of Go vulnerability", "version", r.Fixed, "err", err)
+			return true
+		}
+		if v.LessThan(fixed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// ErrInvalidDocument is returned when the hotloaded document cannot be converted
+var ErrInvalidDocument = xerrors.New("Invalid document")
+
+// Hotload converts a raw upstream document of the source and upserts it immediately.
+// The document is the same format as fetched by gost fetch: a CVE of Security Data API for redhat,
+// a CVE of aquasecurity/vuln-list (ubuntu/YEAR/CVE-ID.json) for ubuntu, and the Debian Security Tracker JSON for debian.
+// It returns the upserted CVE-IDs.
+func Hotload(driver DB, source string, doc []byte) ([]string, error) {
+	switch source {
+	case "redhat":
+		cve := models.RedhatCVEJSON{}
+		if err := json.Unmarshal(doc, &cve); err != nil {
+			return nil, xerrors.Errorf("Failed to unmarshal RedHat CVE. err: %s: %w", err, ErrInvalidDocument)
+		}
+		if cve.Name == "" {
+			return nil, xerrors.Errorf("Failed to hotload RedHat CVE. err: name is empty: %w", ErrInvalidDocument)
+		}
+		if err := driver.UpsertRedhat([]models.RedhatCVEJSON{cve}); err != nil {
+			return nil, err
+		}
+		return []string{cve.Name}, nil
+	case "ubuntu":
+		cve := models.UbuntuCVEJSON{}
+		if err := json.Unmarshal(doc, &cve); err != nil {
+			return nil, xerrors.Errorf("Failed to unmarshal Ubuntu CVE. err: %s: %w", err, ErrInvalidDocument)
+		}
+		if cve.Candidate == "" {
+			return nil, xerrors.Errorf("Failed to hotload Ubuntu CVE. err: Candidate is empty: %w", ErrInvalidDocument)
+		}
+		if err := driver.UpsertUbuntu([]models.UbuntuCVEJSON{cve}); err != nil {
+			return nil, err
+		}
+		return []string{cve.Candidate}, nil
+	case "debian":
+		cves := models.DebianJSON{}
+		if err := json.Unmarshal(doc, &cves); err != nil {
+			return nil, xerrors.Errorf("Failed to unmarshal Debian CVEs. err: %s: %w", err, ErrInvalidDocument)
+		}
+		uniq := map[string]struct{}{}
+		for _, cveMap := range cves {
+			for cveID := range cveMap {
+				uniq[cveID] = struct{}{}
+			}
+		}
+		if len(uniq) == 0 {
+			return nil, xerrors.Errorf("Failed to hotload Debian CVEs. err: no CVE: %w", ErrInvalidDocument)
+		}
+		cveIDs := []string{}
+		for cveID := range uniq {
+			cveIDs = append(cveIDs, cveID)
+		}
+		sort.Strings(cveIDs)
+
+		// The document has no DSA/DLA, so keep the advisories already stored
+		if err := driver.UpsertDebian(cves, StoredDebianAdvisories(driver, cveIDs)); err != nil {
+			return nil, err
+		}
+		return cveIDs, nil
+	default:
+		return nil, xerrors.Errorf("Failed to hotload. source: %s, err: %w", source, ErrUnknownSource)
+	}
+}
+
+// StoredDebianAdvisories returns the DSA/DLA of the CVEs stored in the DB, in the form of the fetched ones to insert again
+func StoredDebianAdvisories(driver DB, cveIDs []string) (advisoryJSONs []models.DebianAdvisoryJSON) {
+	for _, cveID := range cveIDs {
+		for _, a := range driver.GetAdvisoriesDebian(cveID) {
+			advisoryJSONs = append(advisoryJSONs, models.DebianAdvisoryJSON{
+				AdvisoryID:  a.AdvisoryID,
+				Date:        a.IssuedDate.Format("2 Jan 2006"),
+				PackageName: a.PackageName,
+				CveIDs:      []string{cveID},
+				Releases: []models.DebianAdvisoryReleaseJSON{{
+					Release:      a.ProductName,
+					PackageName:  a.PackageName,
+					FixedVersion: a.FixedVersion,
+				}},
+			})
+		}
+	}
+	return advisoryJSONs
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// ImagePreset : the source and the release needed for a container base image. Release is empty if all releases of the source are needed
+type ImagePreset struct {
+	Source  string
+	Release string
+}
+
+// imagePresetSources maps the names of the base images to the sources
+var imagePresetSources = map[string]string{
+	"debian":      "debian",
+	"ubuntu":      "ubuntu",
+	"alpine":      "alpine",
+	"amazonlinux": "amazon",
+	"rhel":        "redhat",
+	"rockylinux":  "rocky",
+	"almalinux":   "alma",
+	"oraclelinux": "oracle",
+	"photon":      "photon",
+	"cbl-mariner": "mariner",
+	"azurelinux":  "mariner",
+	"chainguard":  "wolfi",
+	"wolfi-base":  "wolfi",
+}
+
+var alpineReleaseRegexp = regexp.MustCompile(`^\d+\.\d+$`)
+
+// ResolveImagePreset returns the source and the release as stored by the source for the base image
+// (e.g. debian:12-slim to debian bookworm, ubuntu:22.04 to ubuntu jammy, alpine:3.19.1 to alpine 3.19, amazonlinux:2023 to amazon 2023).
+// The registry and the digest are ignored, and the images of Red Hat UBI (e.g. registry.access.redhat.com/ubi9/ubi-minimal) are of redhat.
+// The images of Chainguard (e.g. cgr.dev/chainguard/python) are of wolfi.
+func ResolveImagePreset(image string) (ImagePreset, error) {
+	repo := strings.SplitN(image, "@", 2)[0]
+	tag := ""
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo, tag = repo[:i], repo[i+1:]
+	}
+
+	source := ""
+	names := strings.Split(strings.ToLower(repo), "/")
+	for i := len(names) - 1; i >= 0 && source == ""; i-- {
+		if s, ok := imagePresetSources[names[i]]; ok {
+			source = s
+		} else if strings.HasPrefix(names[i], "ubi") {
+			source = "redhat"
+		}
+	}
+	if source == "" {
+		return ImagePreset{}, xerrors.Errorf("Failed to resolve the image. Unknown base image: %s", image)
+	}
+
+	// e.g. 12-slim, bookworm-slim, 3.19.1
+	release := strings.SplitN(strings.ToLower(tag), "-", 2)[0]
+	var ok bool
+	switch source {
+	case "debian":
+		release, ok = codenameOf(models.DistroDebian, NormalizeDebianRelease(release))
+	case "ubuntu":
+		release, ok = codenameOf(models.DistroUbuntu, NormalizeUbuntuRelease(release))
+	case "alpine":
+		release = NormalizeAlpineRelease(release)
+		ok = alpineReleaseRegexp.MatchString(release)
+	case "amazon":
+		release = NormalizeAmazonRelease(release)
+		ok = release == "1" || release == "2" || release == "2022" || release == "2023"
+	default:
+		return ImagePreset{Source: source}, nil
+	}
+	if !ok {
+		return ImagePreset{}, xerrors.Errorf("Failed to resolve the image. Give the release in the tag (e.g. debian:12, ubuntu:22.04, alpine:3.19): %s", image)
+	}
+	return ImagePreset{Source: source, Release: release}, nil
+}
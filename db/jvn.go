@@ -0,0 +1,169 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetJvn :
+func (r *RDBDriver) GetJvn(jvnID string) *models.JvnAdvisory {
+	a := models.JvnAdvisory{}
+	err := r.conn.
+		Preload("CveIDs").
+		Preload("Vendors").
+		Preload("References").
+		Where(&models.JvnAdvisory{JvnID: jvnID}).
+		First(&a).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get JVN", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetJvnByCveID gets the advisories of JVN iPedia linked to the CVE
+func (r *RDBDriver) GetJvnByCveID(cveID string) map[string]models.JvnAdvisory {
+	m := map[string]models.JvnAdvisory{}
+	cves := []models.JvnCve{}
+	err := r.conn.Where(&models.JvnCve{CveID: cveID}).Find(&cves).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get JVN by CVE-ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(cves))
+	for _, c := range cves {
+		a := models.JvnAdvisory{}
+		err := r.conn.
+			Preload("CveIDs").
+			Preload("Vendors").
+			Preload("References").
+			Where(&models.JvnAdvisory{ID: c.JvnAdvisoryID}).
+			First(&a).Error
+		if err != nil {
+			r.log.Error("Failed to get JVN by CVE-ID", "err", err)
+			return nil
+		}
+		m[a.JvnID] = a
+	}
+	return m
+}
+
+// InsertJvn :
+func (r *RDBDriver) InsertJvn(items []models.JvnItemXML) (err error) {
+	advisories := r.filter.filterJvn(ConvertJvn(items, r.warnings))
+	if err = r.deleteAndInsertJvn(r.conn, advisories); err != nil {
+		return xerrors.Errorf("Failed to insert JVN. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertJvn(conn *gorm.DB, advisories []models.JvnAdvisory) (err error) {
+	bar := startProgress(r.insert.Progress, len(advisories))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.JvnCve{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.JvnVendor{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.JvnReference{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.JvnAdvisory{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(advisories), r.insert.BatchSize) {
+		if err = tx.Create(advisories[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertJvn converts the items of the feeds of JVN iPedia.
+// An advisory may be in the feeds of several years, so the one modified last is kept.
+func ConvertJvn(items []models.JvnItemXML, warnings *util.Warnings) (advisories []models.JvnAdvisory) {
+	indexes := map[string]int{}
+	for _, item := range items {
+		a := models.JvnAdvisory{
+			JvnID:            item.Identifier,
+			Title:            strings.TrimSpace(item.Title),
+			Summary:          strings.TrimSpace(item.Description),
+			JvnLink:          item.Link,
+			PublishedDate:    parseJvnDate(item.Identifier, "issued", item.Issued, warnings),
+			LastModifiedDate: parseJvnDate(item.Identifier, "modified", item.Modified, warnings),
+		}
+		for _, c := range item.Cvsses {
+			score, err := strconv.ParseFloat(c.Score, 64)
+			if err != nil {
+				warnings.Add("jvn", item.Identifier, "cvss", fmt.Sprintf("Failed to parse score: %s", c.Score))
+			}
+			switch {
+			case strings.HasPrefix(c.Version, "2"):
+				a.Cvss2Score, a.Cvss2Severity, a.Cvss2Vector = score, c.Severity, c.Vector
+			case strings.HasPrefix(c.Version, "3"):
+				a.Cvss3Score, a.Cvss3Severity, a.Cvss3Vector = score, c.Severity, c.Vector
+			}
+		}
+		uniqCveID := map[string]struct{}{}
+		for _, ref := range item.References {
+			a.References = append(a.References, models.JvnReference{Source: ref.Source, Title: ref.Title, URL: strings.TrimSpace(ref.URL)})
+			if ref.Source != "CVE" || ref.ID == "" {
+				continue
+			}
+			if _, ok := uniqCveID[ref.ID]; ok {
+				continue
+			}
+			uniqCveID[ref.ID] = struct{}{}
+			a.CveIDs = append(a.CveIDs, models.JvnCve{CveID: ref.ID})
+		}
+		for _, cpe := range item.Cpes {
+			a.Vendors = append(a.Vendors, models.JvnVendor{Vendor: cpe.Vendor, Product: cpe.Product, Cpe: strings.TrimSpace(cpe.Value)})
+		}
+
+		i, ok := indexes[a.JvnID]
+		if !ok {
+			indexes[a.JvnID] = len(advisories)
+			advisories = append(advisories, a)
+			continue
+		}
+		if a.LastModifiedDate.After(advisories[i].LastModifiedDate) {
+			advisories[i] = a
+		}
+	}
+	return advisories
+}
+
+// parseJvnDate parses the date of JVN iPedia (e.g. 2023-01-05T15:03:04+09:00)
+func parseJvnDate(jvnID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339)
+	if err != nil {
+		warnings.Add("jvn", jvnID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,246 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetKernelCve :
+func (r *RDBDriver) GetKernelCve(cveID string) *models.KernelCVE {
+	c := models.KernelCVE{}
+	err := r.conn.
+		Preload("Commits").
+		Preload("Versions").
+		Where(&models.KernelCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get kernel CVE", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetKernelCves gets all CVEs of the Linux kernel
+func (r *RDBDriver) GetKernelCves() map[string]models.KernelCVE {
+	cves := []models.KernelCVE{}
+	err := r.conn.
+		Preload("Commits").
+		Preload("Versions").
+		Find(&cves).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get kernel CVEs", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(cves))
+	m := map[string]models.KernelCVE{}
+	for _, c := range cves {
+		m[c.CveID] = c
+	}
+	return m
+}
+
+// InsertKernelCves :
+func (r *RDBDriver) InsertKernelCves(records []models.KernelCveJSON) (err error) {
+	cves := r.filter.filterKernel(ConvertKernelCves(records, r.warnings))
+	if err = r.deleteAndInsertKernelCves(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert kernel CVEs. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertKernelCves(conn *gorm.DB, cves []models.KernelCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.KernelCveCommit{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.KernelCveVersion{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.KernelCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertKernelCves converts the CVE records of kernel.org. The rejected records are skipped.
+// The git commits (versionType git) go to Commits, and the released versions to Versions with the default status of their entry.
+func ConvertKernelCves(records []models.KernelCveJSON, warnings *util.Warnings) (cves []models.KernelCVE) {
+	for _, r := range records {
+		m := r.CveMetadata
+		if m.CveID == "" || m.State == models.CveStateRejected {
+			continue
+		}
+		cve := models.KernelCVE{
+			CveID:         m.CveID,
+			Title:         r.Containers.Cna.Title,
+			PublishedDate: parseKernelCveDate(m.CveID, "datePublished", m.DatePublished, warnings),
+			UpdatedDate:   parseKernelCveDate(m.CveID, "dateUpdated", m.DateUpdated, warnings),
+		}
+		for _, d := range r.Containers.Cna.Descriptions {
+			if d.Lang == "en" || cve.Description == "" {
+				cve.Description = d.Value
+			}
+		}
+		for _, a := range r.Containers.Cna.Affected {
+			isGit := false
+			for _, v := range a.Versions {
+				if v.VersionType == "git" {
+					isGit = true
+					if v.Status == models.KernelStatusAffected {
+						cve.Commits = append(cve.Commits, models.KernelCveCommit{Introduced: v.Version, Fixed: v.LessThan})
+					}
+					continue
+				}
+				cve.Versions = append(cve.Versions, models.KernelCveVersion{
+					Version:         v.Version,
+					LessThan:        v.LessThan,
+					LessThanOrEqual: v.LessThanOrEqual,
+					Status:          v.Status,
+				})
+			}
+			if !isGit {
+				cve.DefaultStatus = a.DefaultStatus
+			}
+		}
+		cves = append(cves, cve)
+	}
+	return cves
+}
+
+// parseKernelCveDate parses the date of CVE JSON 5 format (e.g. 2024-02-23T14:46:24.232Z)
+func parseKernelCveDate(cveID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04:05.000")
+	if err != nil {
+		warnings.Add("kernel", cveID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetVulnerableKernelCves gets the CVEs affecting the kernel version (e.g. 6.1.80, 5.10.0-28-amd64).
+// The suffix of the distribution (e.g. -28-amd64) is ignored, except -rc. If ver is empty, all CVEs of the kernel are returned.
+func GetVulnerableKernelCves(driver DB, ver string) map[string]models.KernelCVE {
+	cves := driver.GetKernelCves()
+	if ver == "" {
+		return cves
+	}
+
+	m := map[string]models.KernelCVE{}
+	for cveID, c := range cves {
+		if kernelCveAffected(c, normalizeKernelVersion(ver)) {
+			m[cveID] = c
+		}
+	}
+	return m
+}
+
+func normalizeKernelVersion(ver string) string {
+	ver = strings.TrimPrefix(ver, "v")
+	if i := strings.Index(ver, "-"); i >= 0 && !strings.HasPrefix(ver[i+1:], "rc") {
+		ver = ver[:i]
+	}
+	return ver
+}
+
+// kernelCveAffected returns true if ver is in a range of affected, or in no range of unaffected and DefaultStatus is affected.
+// If the versions cannot be parsed or there are no versions, it returns true so that the CVE is not missed.
+func kernelCveAffected(c models.KernelCVE, ver string) bool {
+	if len(c.Versions) == 0 {
+		return true
+	}
+	v, err := version.NewVersion(ver)
+	if err != nil {
+		log15.Debug("Failed to parse the version", "version", ver, "err", err)
+		return true
+	}
+
+	unaffected := false
+	for _, r := range c.Versions {
+		in, err := kernelVersionInRange(v, r)
+		if err != nil {
+			log15.Debug("Failed to parse the version of kernel CVE", "cveID", c.CveID, "err", err)
+			return true
+		}
+		if !in {
+			continue
+		}
+		if r.Status == models.KernelStatusAffected {
+			return true
+		}
+		unaffected = true
+	}
+	return !unaffected && c.DefaultStatus != models.KernelStatusUnaffected
+}
+
+func kernelVersionInRange(v *version.Version, r models.KernelCveVersion) (bool, error) {
+	start, err := version.NewVersion(r.Version)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case r.LessThan != "":
+		end, err := version.NewVersion(r.LessThan)
+		if err != nil {
+			return false, err
+		}
+		return !v.LessThan(start) && v.LessThan(end), nil
+	case r.LessThanOrEqual == "*":
+		return !v.LessThan(start), nil
+	case r.LessThanOrEqual != "":
+		if v.LessThan(start) {
+			return false, nil
+		}
+		// e.g. 5.10.* is up to the last version of 5.10
+		end := strings.Split(strings.TrimSuffix(r.LessThanOrEqual, ".*"), ".")
+		segments := v.Segments()
+		for i, e := range end {
+			n, err := strconv.Atoi(e)
+			if err != nil {
+				return false, xerrors.Errorf("Failed to parse lessThanOrEqual: %s", r.LessThanOrEqual)
+			}
+			if i >= len(segments) || segments[i] < n {
+				return true, nil
+			}
+			if segments[i] > n {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return v.Equal(start), nil
+	}
+}
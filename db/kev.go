@@ -0,0 +1,116 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetKEV :
+func (r *RDBDriver) GetKEV(cveID string) *models.KEVEntry {
+	c := models.KEVEntry{}
+	err := r.conn.Where(&models.KEVEntry{CveID: cveID}).First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get KEV", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetKnownExploited returns the CVE-IDs in KEV catalog among cveIDs
+func (r *RDBDriver) GetKnownExploited(cveIDs []string) map[string]bool {
+	m := map[string]bool{}
+	// split IN clause to stay under the limit of the bind variables
+	for idx := range chunkSlice(len(cveIDs), 500) {
+		found := []string{}
+		err := r.conn.Model(&models.KEVEntry{}).Where("cve_id IN ?", cveIDs[idx.From:idx.To]).Pluck("cve_id", &found).Error
+		if err != nil {
+			r.log.Error("Failed to get known exploited CVEs", "err", err)
+			return nil
+		}
+		for _, cveID := range found {
+			m[cveID] = true
+		}
+	}
+	return m
+}
+
+// InsertKEV :
+func (r *RDBDriver) InsertKEV(entryJSONs []models.KEVEntryJSON) (err error) {
+	entries := r.filter.filterKEV(ConvertKEV(entryJSONs, r.warnings))
+	if err = r.deleteAndInsertKEV(r.conn, entries); err != nil {
+		return xerrors.Errorf("Failed to insert CISA KEV data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertKEV(conn *gorm.DB, entries []models.KEVEntry) (err error) {
+	bar := startProgress(r.insert.Progress, len(entries))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.KEVEntry{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old records. err: %s", err)
+	}
+
+	for idx := range chunkSlice(len(entries), r.insert.BatchSize) {
+		if err = tx.Create(entries[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertKEV converts CVE of CISA KEV catalog to KEVEntry
+func ConvertKEV(entryJSONs []models.KEVEntryJSON, warnings *util.Warnings) (entries []models.KEVEntry) {
+	uniqCve := map[string]struct{}{}
+	for _, e := range entryJSONs {
+		if _, ok := uniqCve[e.CveID]; ok {
+			continue
+		}
+		uniqCve[e.CveID] = struct{}{}
+
+		entries = append(entries, models.KEVEntry{
+			CveID:                      e.CveID,
+			VendorProject:              e.VendorProject,
+			Product:                    e.Product,
+			VulnerabilityName:          e.VulnerabilityName,
+			DateAdded:                  parseKEVDate(e.CveID, "dateAdded", e.DateAdded, warnings),
+			ShortDescription:           e.ShortDescription,
+			RequiredAction:             e.RequiredAction,
+			DueDate:                    parseKEVDate(e.CveID, "dueDate", e.DueDate, warnings),
+			KnownRansomwareCampaignUse: e.KnownRansomwareCampaignUse,
+			Notes:                      e.Notes,
+		})
+	}
+	return entries
+}
+
+// parseKEVDate parses the date of CISA KEV catalog (e.g. 2021-11-03)
+func parseKEVDate(cveID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, "2006-01-02")
+	if err != nil {
+		warnings.Add("kev", cveID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetMariner :
+func (r *RDBDriver) GetMariner(cveID string) *models.MarinerCVE {
+	c := models.MarinerCVE{}
+	err := r.conn.
+		Preload("Packages").
+		Where(&models.MarinerCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Mariner", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertMariner :
+func (r *RDBDriver) InsertMariner(ovals []models.MarinerOVALXML) (err error) {
+	cves := r.filter.filterMariner(ConvertMariner(ovals, r.warnings))
+	if err = r.deleteAndInsertMariner(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Mariner CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertMariner(conn *gorm.DB, cves []models.MarinerCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MarinerPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MarinerCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertMariner converts OVAL definitions (per CVE and package) to MarinerCVE (per CVE)
+func ConvertMariner(ovals []models.MarinerOVALXML, warnings *util.Warnings) (cves []models.MarinerCVE) {
+	uniqCve := map[string]models.MarinerCVE{}
+	for _, oval := range ovals {
+		for _, def := range oval.Definitions {
+			// Not patchable definitions have no fixed version
+			if def.Metadata.Patchable != "true" {
+				continue
+			}
+			cveID := def.Metadata.Reference.RefID
+			if !strings.HasPrefix(cveID, "CVE-") {
+				continue
+			}
+
+			pkgs := walkMarinerCriteria(def.Criteria)
+			if len(pkgs) == 0 {
+				warnings.Add("mariner", def.ID, "criteria", "No fixed package in the criteria")
+				continue
+			}
+
+			issued := parseMarinerDate(def.ID, def.Metadata.AdvisoryDate, warnings)
+			cve, ok := uniqCve[cveID]
+			if !ok {
+				cve = models.MarinerCVE{CveID: cveID}
+			}
+			for _, p := range pkgs {
+				p.AdvisoryID = def.Metadata.AdvisoryID
+				p.ReleaseName = oval.Release
+				p.Severity = def.Metadata.Severity
+				p.IssuedDate = issued
+				cve.Packages = append(cve.Packages, p)
+			}
+			uniqCve[cveID] = cve
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+// e.g. "Package bind is earlier than 9.16.37-1, affected by CVE-2022-3736"
+var marinerPackageCriterion = regexp.MustCompile(`^Package (\S+) is earlier than ([^,\s]+)`)
+
+func walkMarinerCriteria(criteria models.MarinerCriteriaXML) (pkgs []models.MarinerPackage) {
+	for _, c := range criteria.Criterions {
+		if ss := marinerPackageCriterion.FindStringSubmatch(c.Comment); ss != nil {
+			pkgs = append(pkgs, models.MarinerPackage{PackageName: ss[1], FixedVersion: ss[2]})
+		}
+	}
+	for _, c := range criteria.Criterias {
+		pkgs = append(pkgs, walkMarinerCriteria(c)...)
+	}
+	return pkgs
+}
+
+func parseMarinerDate(defID, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339)
+	if err != nil {
+		warnings.Add("mariner", defID, "advisory_date", fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetFixedCvesMariner gets the CVEs related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesMariner(release, pkgName string) map[string]models.MarinerCVE {
+	m := map[string]models.MarinerCVE{}
+
+	type Result struct {
+		MarinerCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("mariner_packages").
+		Select("mariner_cve_id").
+		Where("package_name = ? AND release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Mariner", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.MarinerCVE{}
+		err := r.conn.
+			Preload("Packages", "package_name = ? AND release_name = ?", pkgName, release).
+			Where(&models.MarinerCVE{ID: res.MarinerCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get MarinerCVE", "err", err)
+			return m
+		}
+		if len(cve.Packages) != 0 {
+			m[cve.CveID] = cve
+		}
+	}
+
+	return m
+}
@@ -3,11 +3,9 @@ package db
 import (
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/cheggaaa/pb/v3"
 	strip "github.com/grokify/html-strip-tags-go"
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/models"
@@ -20,7 +18,7 @@ func (r *RDBDriver) GetMicrosoft(cveID string) *models.MicrosoftCVE {
 	c := models.MicrosoftCVE{}
 	var errs util.Errors
 	errs = errs.Add(r.conn.Where(&models.MicrosoftCVE{CveID: cveID}).First(&c).Error)
-	log15.Debug("microsoft_cve_id", "ID", c.ID)
+	r.log.Debug("microsoft_cve_id", "ID", c.ID)
 
 	errs = errs.Add(r.conn.Model(&c).Association("MicrosoftProductStatuses").Find(&c.MicrosoftProductStatuses))
 	if len(c.MicrosoftProductStatuses) == 0 {
@@ -97,7 +95,7 @@ func (r *RDBDriver) GetMicrosoft(cveID string) *models.MicrosoftCVE {
 
 	errs = util.DeleteRecordNotFound(errs)
 	if len(errs.GetErrors()) > 0 {
-		log15.Error("Failed to find records", "err", errs.Error())
+		r.log.Error("Failed to find records", "err", errs.Error())
 	}
 
 	return &c
@@ -114,15 +112,17 @@ func (r *RDBDriver) GetMicrosoftMulti(cveIDs []string) map[string]models.Microso
 
 // InsertMicrosoft :
 func (r *RDBDriver) InsertMicrosoft(cveJSON []models.MicrosoftXML, cveXls []models.MicrosoftBulletinSearch) (err error) {
-	cves, _ := ConvertMicrosoft(cveJSON, cveXls)
-	if err = r.deleteAndInsertMicrosoft(r.conn, cves); err != nil {
+	cves, _ := ConvertMicrosoft(cveJSON, cveXls, r.warnings)
+	cves = r.filter.filterMicrosoft(cves)
+	supersedences, builds := ConvertMicrosoftKBs(cveJSON, cveXls)
+	if err = r.deleteAndInsertMicrosoft(r.conn, cves, ConvertMicrosoftCvrfDocuments(cveJSON), supersedences, builds); err != nil {
 		return fmt.Errorf("Failed to insert Microsoft CVE data. err: %s", err)
 	}
 	return nil
 }
 
-func (r *RDBDriver) deleteAndInsertMicrosoft(conn *gorm.DB, cves []models.MicrosoftCVE) (err error) {
-	bar := pb.StartNew(len(cves))
+func (r *RDBDriver) deleteAndInsertMicrosoft(conn *gorm.DB, cves []models.MicrosoftCVE, docs []models.MicrosoftCvrfDocument, supersedences []models.MicrosoftKBSupersedence, builds []models.MicrosoftKBBuild) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
 	tx := conn.Begin()
 
 	defer func() {
@@ -144,24 +144,164 @@ func (r *RDBDriver) deleteAndInsertMicrosoft(conn *gorm.DB, cves []models.Micros
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MicrosoftProduct{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MicrosoftScoreSet{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MicrosoftCVE{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MicrosoftCvrfDocument{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MicrosoftKBSupersedence{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.MicrosoftKBBuild{}).Error)
 	errs = util.DeleteNil(errs)
 	if len(errs.GetErrors()) > 0 {
 		return fmt.Errorf("Failed to delete old records. err: %s", errs.Error())
 	}
 
-	for idx := range chunkSlice(len(cves), r.batchSize) {
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
 		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
 			return fmt.Errorf("Failed to insert. err: %s", err)
 		}
 		bar.Add(idx.To - idx.From)
 	}
+	if len(docs) > 0 {
+		if err = tx.Create(docs).Error; err != nil {
+			return fmt.Errorf("Failed to insert CVRF documents. err: %s", err)
+		}
+	}
+	if err = insertMicrosoftKBs(tx, supersedences, builds, r.insert.BatchSize); err != nil {
+		return err
+	}
 	bar.Finish()
 
 	return nil
 }
 
+// UpsertMicrosoft replaces the CVEs in the CVRF documents, and keeps the other CVEs
+func (r *RDBDriver) UpsertMicrosoft(cveXMLs []models.MicrosoftXML) (err error) {
+	cves, _ := ConvertMicrosoft(cveXMLs, nil, r.warnings)
+	cves = r.filter.filterMicrosoft(cves)
+	supersedences, builds := ConvertMicrosoftKBs(cveXMLs, nil)
+	if err = r.deleteAndUpsertMicrosoft(r.conn, cves, ConvertMicrosoftCvrfDocuments(cveXMLs), supersedences, builds); err != nil {
+		return fmt.Errorf("Failed to upsert Microsoft CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndUpsertMicrosoft(conn *gorm.DB, cves []models.MicrosoftCVE, docs []models.MicrosoftCvrfDocument, supersedences []models.MicrosoftKBSupersedence, builds []models.MicrosoftKBBuild) (err error) {
+	tx := conn.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	cveIDs := []string{}
+	for _, c := range cves {
+		cveIDs = append(cveIDs, c.CveID)
+	}
+	ids := []int64{}
+	if len(cveIDs) > 0 {
+		if err = tx.Model(&models.MicrosoftCVE{}).Where("cve_id IN ?", cveIDs).Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("Failed to get old records. err: %s", err)
+		}
+	}
+
+	// Delete old records of the given CVEs
+	if len(ids) > 0 {
+		var errs util.Errors
+		errs = errs.Add(tx.Where("microsoft_cve_id IN ?", ids).Delete(models.MicrosoftScoreSet{}).Error)
+		errs = errs.Add(tx.Where("microsoft_cve_id IN ?", ids).Delete(models.MicrosoftReference{}).Error)
+		errs = errs.Add(tx.Where("microsoft_cve_id IN ?", ids).Delete(models.MicrosoftKBID{}).Error)
+		errs = errs.Add(tx.Where("microsoft_cve_id IN ?", ids).Delete(models.MicrosoftRemediation{}).Error)
+		errs = errs.Add(tx.Where("microsoft_cve_id IN ?", ids).Delete(models.MicrosoftThreat{}).Error)
+		errs = errs.Add(tx.Where("microsoft_cve_id IN ?", ids).Delete(models.MicrosoftProductStatus{}).Error)
+		errs = errs.Add(tx.Where("microsoft_cve_id IN ?", ids).Delete(models.MicrosoftProduct{}).Error)
+		errs = errs.Add(tx.Where("id IN ?", ids).Delete(models.MicrosoftCVE{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return fmt.Errorf("Failed to delete old records. err: %s", errs.Error())
+		}
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return fmt.Errorf("Failed to insert. err: %s", err)
+		}
+	}
+
+	if len(docs) > 0 {
+		cvrfIDs := []string{}
+		for _, d := range docs {
+			cvrfIDs = append(cvrfIDs, d.CvrfID)
+		}
+		if err = tx.Where("cvrf_id IN ?", cvrfIDs).Delete(models.MicrosoftCvrfDocument{}).Error; err != nil {
+			return fmt.Errorf("Failed to delete old CVRF documents. err: %s", err)
+		}
+		if err = tx.Create(docs).Error; err != nil {
+			return fmt.Errorf("Failed to insert CVRF documents. err: %s", err)
+		}
+	}
+
+	// The KBs in the documents are replaced as a whole, since a KB is in the document of the month it was released
+	kbIDs := map[string]bool{}
+	for _, s := range supersedences {
+		kbIDs[s.KBID] = true
+	}
+	for _, b := range builds {
+		kbIDs[b.KBID] = true
+	}
+	if len(kbIDs) > 0 {
+		ids := make([]string, 0, len(kbIDs))
+		for id := range kbIDs {
+			ids = append(ids, id)
+		}
+		var errs util.Errors
+		errs = errs.Add(tx.Where("kb_id IN ?", ids).Delete(models.MicrosoftKBSupersedence{}).Error)
+		errs = errs.Add(tx.Where("kb_id IN ?", ids).Delete(models.MicrosoftKBBuild{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return fmt.Errorf("Failed to delete old KBs. err: %s", errs.Error())
+		}
+	}
+	return insertMicrosoftKBs(tx, supersedences, builds, r.insert.BatchSize)
+}
+
+func insertMicrosoftKBs(tx *gorm.DB, supersedences []models.MicrosoftKBSupersedence, builds []models.MicrosoftKBBuild, batchSize int) error {
+	for idx := range chunkSlice(len(supersedences), batchSize) {
+		if err := tx.Create(supersedences[idx.From:idx.To]).Error; err != nil {
+			return fmt.Errorf("Failed to insert KB supersedences. err: %s", err)
+		}
+	}
+	for idx := range chunkSlice(len(builds), batchSize) {
+		if err := tx.Create(builds[idx.From:idx.To]).Error; err != nil {
+			return fmt.Errorf("Failed to insert the builds of KBs. err: %s", err)
+		}
+	}
+	return nil
+}
+
+// GetMicrosoftCvrfIDs returns the IDs of the CVRF documents stored (e.g. 2023-Jan)
+func (r *RDBDriver) GetMicrosoftCvrfIDs() ([]string, error) {
+	cvrfIDs := []string{}
+	if err := r.conn.Model(&models.MicrosoftCvrfDocument{}).Pluck("cvrf_id", &cvrfIDs).Error; err != nil {
+		return nil, fmt.Errorf("Failed to get CVRF documents. err: %s", err)
+	}
+	return cvrfIDs, nil
+}
+
+// ConvertMicrosoftCvrfDocuments returns the documents of the CVRFs, which are recorded to fetch the newer documents only
+func ConvertMicrosoftCvrfDocuments(cveXMLs []models.MicrosoftXML) (docs []models.MicrosoftCvrfDocument) {
+	for _, x := range cveXMLs {
+		if x.DocumentTracking == nil || x.DocumentTracking.ID == "" {
+			continue
+		}
+		docs = append(docs, models.MicrosoftCvrfDocument{
+			CvrfID:             x.DocumentTracking.ID,
+			CurrentReleaseDate: x.DocumentTracking.CurrentReleaseDate.Time,
+		})
+	}
+	return docs
+}
+
 // ConvertMicrosoft :
-func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBulletinSearch) (cves []models.MicrosoftCVE, msProducts []models.MicrosoftProduct) {
+func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBulletinSearch, warnings *util.Warnings) (cves []models.MicrosoftCVE, msProducts []models.MicrosoftProduct) {
 	uniqCve := map[string]models.MicrosoftCVE{}
 	uniqProduct := map[string]string{}
 
@@ -210,6 +350,7 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 						Category:    fmt.Sprintf("MicrosoftProductStatus:%d", i),
 						ProductID:   productID,
 						ProductName: uniqProduct[productID],
+						Arch:        microsoftProductArch(uniqProduct[productID]),
 					}
 					products = append(products, product)
 				}
@@ -230,6 +371,7 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 						Category:    "MicrosoftThreat",
 						ProductID:   productID,
 						ProductName: uniqProduct[productID],
+						Arch:        microsoftProductArch(uniqProduct[productID]),
 					}
 					products = append(products, product)
 				}
@@ -285,6 +427,7 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 					product := models.MicrosoftProduct{
 						ProductID:   productID,
 						ProductName: uniqProduct[productID],
+						Arch:        microsoftProductArch(uniqProduct[productID]),
 					}
 					products = append(products, product)
 				}
@@ -314,13 +457,14 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 
 			var mitigation, workaround string
 			var vendorFix, noneAvailable, willNotFix []models.MicrosoftRemediation
-			uniqKBIDs := map[string]bool{}
+			uniqKBIDs := map[string]string{}
 			for _, r := range vuln.Remediations {
 				var products []models.MicrosoftProduct
 				for _, productID := range r.ProductID {
 					product := models.MicrosoftProduct{
 						ProductID:   productID,
 						ProductName: uniqProduct[productID],
+						Arch:        microsoftProductArch(uniqProduct[productID]),
 					}
 					products = append(products, product)
 				}
@@ -331,6 +475,7 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 					RestartRequired: r.RestartRequired,
 					SubType:         r.SubType,
 					Supercedence:    r.Supercedence,
+					FixedBuild:      r.FixedBuild,
 					URL:             r.URL,
 					AttrType:        r.AttrType,
 				}
@@ -340,12 +485,15 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 				case "Mitigation":
 					mitigation = r.Description
 				case "Vendor Fix":
+					remediation.KBArticleURL, remediation.CatalogURL = getMicrosoftKBURLs(r.Description, r.URL)
 					for j := range remediation.Products {
 						remediation.Products[j].Category = fmt.Sprintf("VendorFix:%d", len(vendorFix))
 					}
 					vendorFix = append(vendorFix, remediation)
-					if _, err := strconv.Atoi(r.Description); err == nil {
-						uniqKBIDs[r.Description] = true
+					if kbID := normalizeMicrosoftKBID(r.Description); kbID != "" {
+						if uniqKBIDs[kbID] == "" {
+							uniqKBIDs[kbID] = getMicrosoftUpdateType(r.SubType)
+						}
 					}
 				case "None Available":
 					for j := range remediation.Products {
@@ -363,8 +511,8 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 			}
 
 			var kbIDs []models.MicrosoftKBID
-			for kbID := range uniqKBIDs {
-				kbIDs = append(kbIDs, models.MicrosoftKBID{KBID: kbID})
+			for kbID, updateType := range uniqKBIDs {
+				kbIDs = append(kbIDs, models.MicrosoftKBID{KBID: kbID, UpdateType: updateType})
 			}
 
 			var references []models.MicrosoftReference
@@ -386,7 +534,7 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 				}
 			}
 
-			uniqCve[vuln.CVE] = models.MicrosoftCVE{
+			cve := models.MicrosoftCVE{
 				Title:                    vuln.Title,
 				Description:              description,
 				FAQ:                      faq,
@@ -407,6 +555,10 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 				ScoreSets:                scoreSets,
 				LastUpdateDate:           lastUpdateDate,
 			}
+			if c, ok := uniqCve[vuln.CVE]; ok {
+				cve = mergeMicrosoftCVE(c, cve)
+			}
+			uniqCve[vuln.CVE] = cve
 		}
 	}
 
@@ -414,6 +566,7 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 		msProduct := models.MicrosoftProduct{
 			ProductID:   id,
 			ProductName: name,
+			Arch:        microsoftProductArch(name),
 		}
 		msProducts = append(msProducts, msProduct)
 	}
@@ -479,20 +632,19 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 				Supercedence:    bs.Supersedes,
 				AttrType:        "Vendor Fix",
 			}
+			rem.KBArticleURL, rem.CatalogURL = getMicrosoftKBURLs(bs.BulletinKB, "")
 			vendorFix = append(vendorFix, rem)
 
-			if len(bs.BulletinKB) != 0 {
-				uniqKBIDs[bs.BulletinKB] = true
+			if kbID := normalizeMicrosoftKBID(bs.BulletinKB); kbID != "" {
+				uniqKBIDs[kbID] = true
 			}
-			if len(bs.ComponentKB) != 0 {
-				uniqKBIDs[bs.ComponentKB] = true
+			if kbID := normalizeMicrosoftKBID(bs.ComponentKB); kbID != "" {
+				uniqKBIDs[kbID] = true
 			}
 			title = bs.Title
 			var err error
-			if publishDate, err = time.Parse("1/2/2006", bs.DatePosted); err != nil {
-				if publishDate, err = time.Parse("1-2-06", bs.DatePosted); err != nil {
-					log15.Warn("Failed to parse date", "date", bs.DatePosted)
-				}
+			if publishDate, err = util.ParseDate(bs.DatePosted, "1/2/2006", "1-2-06"); err != nil {
+				warnings.Add("microsoft", cveID, "Date Posted", fmt.Sprintf("Failed to parse date: %s", bs.DatePosted))
 			}
 		}
 
@@ -529,7 +681,7 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 			kbIDs = append(kbIDs, kbID)
 		}
 
-		uniqCve[cveID] = models.MicrosoftCVE{
+		cve := models.MicrosoftCVE{
 			Title:    title,
 			CveID:    cveID,
 			Impact:   impact,
@@ -539,6 +691,10 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 			PublishDate:    publishDate,
 			LastUpdateDate: publishDate,
 		}
+		if c, ok := uniqCve[cveID]; ok {
+			cve = mergeMicrosoftCVE(c, cve)
+		}
+		uniqCve[cveID] = cve
 	}
 
 	for _, c := range uniqCve {
@@ -550,6 +706,201 @@ func ConvertMicrosoft(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBu
 	return cves, msProducts
 }
 
+// mergeMicrosoftCVE merges the same CVE in the CVRF documents of the different months (e.g. re-released with new products).
+// The products, the KBs, the remediations and the references are the union of both,
+// and the texts are of the latest revision, which is b if both have the same LastUpdateDate.
+func mergeMicrosoftCVE(a, b models.MicrosoftCVE) models.MicrosoftCVE {
+	older, newer := a, b
+	if b.LastUpdateDate.Before(a.LastUpdateDate) {
+		older, newer = b, a
+	}
+
+	merged := newer
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&merged.Title, older.Title},
+		{&merged.Description, older.Description},
+		{&merged.FAQ, older.FAQ},
+		{&merged.CWE, older.CWE},
+		{&merged.ExploitStatus, older.ExploitStatus},
+		{&merged.Mitigation, older.Mitigation},
+		{&merged.Workaround, older.Workaround},
+	} {
+		if *f.dst == "" {
+			*f.dst = f.src
+		}
+	}
+	if merged.PublishDate.IsZero() || (!older.PublishDate.IsZero() && older.PublishDate.Before(merged.PublishDate)) {
+		merged.PublishDate = older.PublishDate
+	}
+
+	merged.MicrosoftProductStatuses = nil
+	for _, st := range append(append([]models.MicrosoftProductStatus{}, newer.MicrosoftProductStatuses...), older.MicrosoftProductStatuses...) {
+		i := 0
+		for ; i < len(merged.MicrosoftProductStatuses); i++ {
+			if merged.MicrosoftProductStatuses[i].ProductStatus == st.ProductStatus {
+				break
+			}
+		}
+		if i == len(merged.MicrosoftProductStatuses) {
+			merged.MicrosoftProductStatuses = append(merged.MicrosoftProductStatuses, models.MicrosoftProductStatus{ProductStatus: st.ProductStatus})
+		}
+		merged.MicrosoftProductStatuses[i].Products = unionMicrosoftProducts(merged.MicrosoftProductStatuses[i].Products, st.Products)
+	}
+	for i := range merged.MicrosoftProductStatuses {
+		setMicrosoftProductCategory(merged.MicrosoftProductStatuses[i].Products, fmt.Sprintf("MicrosoftProductStatus:%d", i))
+	}
+
+	merged.Impact = mergeMicrosoftThreats(newer.Impact, older.Impact, "Impact")
+	merged.Severity = mergeMicrosoftThreats(newer.Severity, older.Severity, "Severity")
+
+	merged.ScoreSets = nil
+	for _, ss := range append(append([]models.MicrosoftScoreSet{}, newer.ScoreSets...), older.ScoreSets...) {
+		i := 0
+		for ; i < len(merged.ScoreSets); i++ {
+			if merged.ScoreSets[i].Vector == ss.Vector {
+				break
+			}
+		}
+		if i == len(merged.ScoreSets) {
+			merged.ScoreSets = append(merged.ScoreSets, ss)
+			merged.ScoreSets[i].Products = nil
+		}
+		merged.ScoreSets[i].Products = unionMicrosoftProducts(merged.ScoreSets[i].Products, ss.Products)
+	}
+	for i := range merged.ScoreSets {
+		setMicrosoftProductCategory(merged.ScoreSets[i].Products, fmt.Sprintf("MicrosoftScoreSet:%d", i))
+	}
+
+	merged.VendorFix = mergeMicrosoftRemediations(newer.VendorFix, older.VendorFix, "VendorFix")
+	merged.NoneAvailable = mergeMicrosoftRemediations(newer.NoneAvailable, older.NoneAvailable, "NoneAvailable")
+	merged.WillNotFix = mergeMicrosoftRemediations(newer.WillNotFix, older.WillNotFix, "WillNotFix")
+
+	merged.KBIDs = nil
+	uniqKBIDs := map[string]int{}
+	for _, k := range append(append([]models.MicrosoftKBID{}, newer.KBIDs...), older.KBIDs...) {
+		i, ok := uniqKBIDs[k.KBID]
+		if !ok {
+			uniqKBIDs[k.KBID] = len(merged.KBIDs)
+			merged.KBIDs = append(merged.KBIDs, k)
+			continue
+		}
+		if merged.KBIDs[i].UpdateType == "" {
+			merged.KBIDs[i].UpdateType = k.UpdateType
+		}
+	}
+
+	merged.References = nil
+	uniqRefs := map[string]bool{}
+	for _, r := range append(append([]models.MicrosoftReference{}, newer.References...), older.References...) {
+		if uniqRefs[r.URL] {
+			continue
+		}
+		uniqRefs[r.URL] = true
+		merged.References = append(merged.References, r)
+	}
+
+	return merged
+}
+
+// mergeMicrosoftThreats merges the threats with the same description
+func mergeMicrosoftThreats(newer, older []models.MicrosoftThreat, category string) (merged []models.MicrosoftThreat) {
+	for _, t := range append(append([]models.MicrosoftThreat{}, newer...), older...) {
+		i := 0
+		for ; i < len(merged); i++ {
+			if merged[i].Description == t.Description {
+				break
+			}
+		}
+		if i == len(merged) {
+			merged = append(merged, models.MicrosoftThreat{Description: t.Description, AttrType: t.AttrType})
+		}
+		merged[i].Products = unionMicrosoftProducts(merged[i].Products, t.Products)
+	}
+	for i := range merged {
+		setMicrosoftProductCategory(merged[i].Products, fmt.Sprintf("%s:%d", category, i))
+	}
+	return merged
+}
+
+// mergeMicrosoftRemediations merges the remediations of the same KB (or text) and the same fixed build.
+// The fields of the newer one are kept.
+func mergeMicrosoftRemediations(newer, older []models.MicrosoftRemediation, category string) (merged []models.MicrosoftRemediation) {
+	for _, r := range append(append([]models.MicrosoftRemediation{}, newer...), older...) {
+		i := 0
+		for ; i < len(merged); i++ {
+			if merged[i].Description == r.Description && merged[i].SubType == r.SubType && merged[i].FixedBuild == r.FixedBuild {
+				break
+			}
+		}
+		if i == len(merged) {
+			merged = append(merged, r)
+			merged[i].Products = nil
+		}
+		merged[i].Products = unionMicrosoftProducts(merged[i].Products, r.Products)
+	}
+	for i := range merged {
+		setMicrosoftProductCategory(merged[i].Products, fmt.Sprintf("%s:%d", category, i))
+	}
+	return merged
+}
+
+// unionMicrosoftProducts appends the products of ps not in dst by the product ID
+func unionMicrosoftProducts(dst, ps []models.MicrosoftProduct) []models.MicrosoftProduct {
+	for _, p := range ps {
+		found := false
+		for _, d := range dst {
+			if d.ProductID == p.ProductID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, p)
+		}
+	}
+	return dst
+}
+
+func setMicrosoftProductCategory(ps []models.MicrosoftProduct, category string) {
+	for i := range ps {
+		ps[i].Category = category
+	}
+}
+
+// getMicrosoftUpdateType classifies the SubType of the remediation.
+// e.g. Security Update, Monthly Rollup, Security Only, Servicing Stack Update
+func getMicrosoftUpdateType(subType string) string {
+	s := strings.ToLower(subType)
+	switch {
+	case strings.Contains(s, "servicing stack"):
+		return models.MicrosoftUpdateTypeServicingStack
+	case strings.Contains(s, "cumulative"), strings.Contains(s, "monthly rollup"):
+		return models.MicrosoftUpdateTypeCumulative
+	case strings.Contains(s, "security only"):
+		return models.MicrosoftUpdateTypeSecurityOnly
+	case strings.Contains(s, "security update"):
+		return models.MicrosoftUpdateTypeSecurityUpdate
+	}
+	return ""
+}
+
+// getMicrosoftKBURLs returns the URL of KB article (release notes) and Microsoft Update Catalog.
+// If the remediation URL already points to Update Catalog, it is used as is.
+func getMicrosoftKBURLs(kbID, remediationURL string) (articleURL, catalogURL string) {
+	if kbID = normalizeMicrosoftKBID(kbID); kbID == "" {
+		return "", ""
+	}
+	articleURL = fmt.Sprintf("https://support.microsoft.com/help/%s", kbID)
+	catalogURL = fmt.Sprintf("https://catalog.update.microsoft.com/v7/site/Search.aspx?q=KB%s", kbID)
+	if strings.Contains(remediationURL, "catalog.update.microsoft.com") {
+		catalogURL = remediationURL
+	}
+	return articleURL, catalogURL
+}
+
 func getProductFromName(msProducts []models.MicrosoftProduct, productName string) models.MicrosoftProduct {
 	for _, msp := range msProducts {
 		if productName == msp.ProductName {
@@ -558,6 +909,7 @@ func getProductFromName(msProducts []models.MicrosoftProduct, productName string
 	}
 	return models.MicrosoftProduct{
 		ProductName: productName,
+		Arch:        microsoftProductArch(productName),
 	}
 }
 
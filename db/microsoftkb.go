@@ -0,0 +1,279 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// ErrInvalidBuild is returned when the build is not dotted numbers (e.g. 19044.2486)
+var ErrInvalidBuild = xerrors.New("Invalid build")
+
+// ErrInvalidKBID is returned when the KB is not a number with or without KB prefix (e.g. KB5022282, 5022282)
+var ErrInvalidKBID = xerrors.New("Invalid KB")
+
+var (
+	microsoftKBIDRegexp        = regexp.MustCompile(`\d{6,7}`)
+	microsoftBulletinKBIDRegex = regexp.MustCompile(`\[(\d{6,7})\]`)
+)
+
+// ConvertMicrosoftKBs returns the supersedence graph of the KBs and the OS builds each KB ships in.
+// The supersedences are from Supercedence of the remediations in CVRF and Supersedes of BulletinSearch (e.g. MS08-001[941644]).
+func ConvertMicrosoftKBs(cveXMLs []models.MicrosoftXML, cveXls []models.MicrosoftBulletinSearch) (supersedences []models.MicrosoftKBSupersedence, builds []models.MicrosoftKBBuild) {
+	uniqSupersedence := map[string]models.MicrosoftKBSupersedence{}
+	uniqBuild := map[string]models.MicrosoftKBBuild{}
+	productIDs := map[string]string{}
+
+	for _, cveXML := range cveXMLs {
+		if ptree := cveXML.ProductTree; ptree != nil {
+			for _, p := range ptree.FullProductName {
+				productIDs[p.Value] = p.AttrProductID
+			}
+			if ptree.Branch != nil {
+				for _, p := range ptree.Branch.FullProductName {
+					productIDs[p.Value] = p.AttrProductID
+				}
+			}
+		}
+		for _, vuln := range cveXML.Vulnerability {
+			for _, r := range vuln.Remediations {
+				if r.AttrType != "Vendor Fix" {
+					continue
+				}
+				kbID := normalizeMicrosoftKBID(r.Description)
+				if kbID == "" {
+					continue
+				}
+				for _, productID := range r.ProductID {
+					for _, superseded := range microsoftKBIDRegexp.FindAllString(r.Supercedence, -1) {
+						if superseded = normalizeMicrosoftKBID(superseded); superseded == "" || superseded == kbID {
+							continue
+						}
+						s := models.MicrosoftKBSupersedence{KBID: kbID, SupersededKBID: superseded, ProductID: productID}
+						uniqSupersedence[fmt.Sprintf("%s#%s#%s", kbID, superseded, productID)] = s
+					}
+
+					if r.FixedBuild == "" {
+						continue
+					}
+					key := fmt.Sprintf("%s#%s", kbID, productID)
+					if b, ok := uniqBuild[key]; ok {
+						if cmp, ok := compareMicrosoftBuild(b.FixedBuild, r.FixedBuild); ok && cmp >= 0 {
+							continue
+						}
+					}
+					uniqBuild[key] = models.MicrosoftKBBuild{KBID: kbID, ProductID: productID, FixedBuild: r.FixedBuild}
+				}
+			}
+		}
+	}
+
+	for _, bs := range cveXls {
+		kbID := normalizeMicrosoftKBID(bs.ComponentKB)
+		if kbID == "" {
+			continue
+		}
+		productID := productIDs[bs.AffectedProduct]
+		for _, m := range microsoftBulletinKBIDRegex.FindAllStringSubmatch(bs.Supersedes, -1) {
+			superseded := normalizeMicrosoftKBID(m[1])
+			if superseded == "" || superseded == kbID {
+				continue
+			}
+			s := models.MicrosoftKBSupersedence{KBID: kbID, SupersededKBID: superseded, ProductID: productID}
+			uniqSupersedence[fmt.Sprintf("%s#%s#%s", kbID, superseded, productID)] = s
+		}
+	}
+
+	for _, s := range uniqSupersedence {
+		supersedences = append(supersedences, s)
+	}
+	sort.Slice(supersedences, func(i, j int) bool {
+		if supersedences[i].KBID != supersedences[j].KBID {
+			return supersedences[i].KBID < supersedences[j].KBID
+		}
+		if supersedences[i].SupersededKBID != supersedences[j].SupersededKBID {
+			return supersedences[i].SupersededKBID < supersedences[j].SupersededKBID
+		}
+		return supersedences[i].ProductID < supersedences[j].ProductID
+	})
+	for _, b := range uniqBuild {
+		builds = append(builds, b)
+	}
+	sort.Slice(builds, func(i, j int) bool {
+		if builds[i].KBID != builds[j].KBID {
+			return builds[i].KBID < builds[j].KBID
+		}
+		return builds[i].ProductID < builds[j].ProductID
+	})
+	return supersedences, builds
+}
+
+// normalizeMicrosoftKBID returns the number of the KB without KB prefix and leading zeros (e.g. KB5022282, kb 05022282 -> 5022282),
+// or empty if it is not a KB. The KBs are stored and queried in this form, since the clients and the sources give both forms.
+func normalizeMicrosoftKBID(kbID string) string {
+	kbID = strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(kbID)), "KB"))
+	if kbID == "" || strings.TrimLeft(kbID, "0123456789") != "" {
+		return ""
+	}
+	return strings.TrimLeft(kbID, "0")
+}
+
+// compareMicrosoftBuild compares the build the KB ships in (e.g. 10.0.19044.2486) with the build of winver (e.g. 19044.2486).
+// The shorter one is compared with the trailing components of the other.
+// ok is false if either is not a build, or they are different OS builds (e.g. 19044 and 19045).
+func compareMicrosoftBuild(fixedBuild, build string) (cmp int, ok bool) {
+	fs, ok := parseMicrosoftBuild(fixedBuild)
+	if !ok {
+		return 0, false
+	}
+	bs, ok := parseMicrosoftBuild(build)
+	if !ok {
+		return 0, false
+	}
+	n := len(fs)
+	if len(bs) < n {
+		n = len(bs)
+	}
+	fs, bs = fs[len(fs)-n:], bs[len(bs)-n:]
+	for i := 0; i < n-1; i++ {
+		if fs[i] != bs[i] {
+			return 0, false
+		}
+	}
+	switch {
+	case fs[n-1] < bs[n-1]:
+		return -1, true
+	case fs[n-1] > bs[n-1]:
+		return 1, true
+	}
+	return 0, true
+}
+
+func parseMicrosoftBuild(build string) ([]int, bool) {
+	ss := strings.Split(strings.TrimSpace(build), ".")
+	if len(ss) < 2 {
+		return nil, false
+	}
+	ns := make([]int, 0, len(ss))
+	for _, s := range ss {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, false
+		}
+		ns = append(ns, n)
+	}
+	return ns, true
+}
+
+// microsoftKBGraph is the queries of the KB supersedence graph implemented by each driver
+type microsoftKBGraph interface {
+	// getSupersededKBsDirectly returns the KBs superseded by kbIDs directly
+	getSupersededKBsDirectly(kbIDs []string) ([]string, error)
+	// getMicrosoftKBBuilds returns the builds of the KBs for the product
+	getMicrosoftKBBuilds(productID string) ([]models.MicrosoftKBBuild, error)
+}
+
+// getSupersededKBs returns the KBs superseded by kbID directly or transitively, in the order of KBID
+func getSupersededKBs(g microsoftKBGraph, kbID string) ([]string, error) {
+	id := normalizeMicrosoftKBID(kbID)
+	if id == "" {
+		return nil, xerrors.Errorf("%s: %w", kbID, ErrInvalidKBID)
+	}
+	kbID = id
+
+	visited := map[string]bool{kbID: true}
+	superseded := []string{}
+	queue := []string{kbID}
+	for len(queue) > 0 {
+		kbIDs, err := g.getSupersededKBsDirectly(queue)
+		if err != nil {
+			return nil, err
+		}
+		queue = nil
+		for _, id := range kbIDs {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			superseded = append(superseded, id)
+			queue = append(queue, id)
+		}
+	}
+	sort.Strings(superseded)
+	return superseded, nil
+}
+
+// getRequiredKBsForBuild returns the KBs of the product newer than build (e.g. 19044.2486 shown by winver),
+// except the KBs superseded by the others returned, in the order of the build.
+func getRequiredKBsForBuild(g microsoftKBGraph, productID, build string) ([]models.MicrosoftKBBuild, error) {
+	if _, ok := parseMicrosoftBuild(build); !ok {
+		return nil, xerrors.Errorf("%s: %w", build, ErrInvalidBuild)
+	}
+	builds, err := g.getMicrosoftKBBuilds(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	newer := []models.MicrosoftKBBuild{}
+	for _, b := range builds {
+		if cmp, ok := compareMicrosoftBuild(b.FixedBuild, build); ok && cmp > 0 {
+			newer = append(newer, b)
+		}
+	}
+
+	superseded := map[string]bool{}
+	for _, b := range newer {
+		kbIDs, err := getSupersededKBs(g, b.KBID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range kbIDs {
+			superseded[id] = true
+		}
+	}
+
+	required := []models.MicrosoftKBBuild{}
+	for _, b := range newer {
+		if !superseded[b.KBID] {
+			required = append(required, b)
+		}
+	}
+	sort.Slice(required, func(i, j int) bool {
+		if cmp, ok := compareMicrosoftBuild(required[i].FixedBuild, required[j].FixedBuild); ok && cmp != 0 {
+			return cmp < 0
+		}
+		return required[i].KBID < required[j].KBID
+	})
+	return required, nil
+}
+
+// GetSupersededKBs :
+func (r *RDBDriver) GetSupersededKBs(kbID string) ([]string, error) {
+	return getSupersededKBs(r, kbID)
+}
+
+// GetRequiredKBsForBuild :
+func (r *RDBDriver) GetRequiredKBsForBuild(productID, build string) ([]models.MicrosoftKBBuild, error) {
+	return getRequiredKBsForBuild(r, productID, build)
+}
+
+func (r *RDBDriver) getSupersededKBsDirectly(kbIDs []string) ([]string, error) {
+	superseded := []string{}
+	if err := r.conn.Model(&models.MicrosoftKBSupersedence{}).Distinct().Where("kb_id IN ?", kbIDs).Pluck("superseded_kb_id", &superseded).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get superseded KBs. err: %w", err)
+	}
+	return superseded, nil
+}
+
+func (r *RDBDriver) getMicrosoftKBBuilds(productID string) ([]models.MicrosoftKBBuild, error) {
+	builds := []models.MicrosoftKBBuild{}
+	if err := r.conn.Where(&models.MicrosoftKBBuild{ProductID: productID}).Find(&builds).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get the builds of KBs. err: %w", err)
+	}
+	return builds, nil
+}
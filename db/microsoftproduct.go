@@ -0,0 +1,272 @@
+package db
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+var (
+	microsoftProductTokenRegexp       = regexp.MustCompile(`[a-z0-9]+(?:[.-][a-z0-9]+)*`)
+	microsoftProductServicePackRegexp = regexp.MustCompile(`service pack (\d+)`)
+)
+
+// microsoftProductSynonyms normalizes the tokens of the architecture
+var microsoftProductSynonyms = map[string]string{
+	"x64-based":     "x64",
+	"amd64":         "x64",
+	"64-bit":        "x64",
+	"x86":           "32-bit",
+	"x86-based":     "32-bit",
+	"arm64-based":   "arm64",
+	"itanium-based": "itanium",
+}
+
+// microsoftProductStopwords are the tokens not in the product names of MSRC, e.g. the editions of Windows Server
+var microsoftProductStopwords = map[string]bool{
+	"microsoft":    true,
+	"for":          true,
+	"the":          true,
+	"systems":      true,
+	"edition":      true,
+	"installation": true,
+	"version":      true,
+	"datacenter":   true,
+	"standard":     true,
+	"essentials":   true,
+	"enterprise":   true,
+	"pro":          true,
+	"professional": true,
+	"home":         true,
+	"education":    true,
+}
+
+// normalizeMicrosoftProduct returns the tokens of the product name, e.g.
+// "Windows Server 2019 Datacenter (Server Core)" -> windows, server, 2019, core
+func normalizeMicrosoftProduct(name string) map[string]bool {
+	tokens := map[string]bool{}
+	name = microsoftProductServicePackRegexp.ReplaceAllString(strings.ToLower(name), "sp$1")
+	for _, t := range microsoftProductTokenRegexp.FindAllString(name, -1) {
+		if s, ok := microsoftProductSynonyms[t]; ok {
+			t = s
+		}
+		if microsoftProductStopwords[t] {
+			continue
+		}
+		tokens[t] = true
+	}
+	return tokens
+}
+
+// microsoftProductArch returns the architecture in the product name, e.g.
+// "Windows 11 Version 22H2 for ARM64-based Systems" -> arm64, "Microsoft Office 2019 for 32-bit editions" -> x86
+func microsoftProductArch(name string) string {
+	tokens := normalizeMicrosoftProduct(name)
+	switch {
+	case tokens["arm64"]:
+		return models.MicrosoftArchARM64
+	case tokens["x64"]:
+		return models.MicrosoftArchX64
+	case tokens["32-bit"]:
+		return models.MicrosoftArchX86
+	case tokens["itanium"]:
+		return models.MicrosoftArchItanium
+	default:
+		return ""
+	}
+}
+
+// NormalizeMicrosoftArch normalizes the architecture given by the client (e.g. amd64, AMD64, aarch64, 386) to the one of MicrosoftProduct.
+// The unknown one is returned in lower case.
+func NormalizeMicrosoftArch(arch string) string {
+	switch a := strings.ToLower(arch); a {
+	case "amd64", "x86_64", "x86-64", "64-bit":
+		return models.MicrosoftArchX64
+	case "386", "i386", "i686", "32-bit":
+		return models.MicrosoftArchX86
+	case "aarch64", "arm64ec":
+		return models.MicrosoftArchARM64
+	case "ia64":
+		return models.MicrosoftArchItanium
+	default:
+		return a
+	}
+}
+
+// microsoftArchOK returns true if the product is of arch, or has no architecture
+func microsoftArchOK(p models.MicrosoftProduct, arch string) bool {
+	return p.Arch == "" || p.Arch == arch
+}
+
+// FilterMicrosoftProductsByArch returns the products of arch and the ones without architecture. If arch is empty, all products are returned
+func FilterMicrosoftProductsByArch(products []models.MicrosoftProduct, arch string) []models.MicrosoftProduct {
+	if arch == "" {
+		return products
+	}
+	filtered := []models.MicrosoftProduct{}
+	for _, p := range products {
+		if microsoftArchOK(p, arch) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// FilterMicrosoftCveByArch removes the products of the other architectures from the CVE.
+// The statuses, the threats, the score sets and the remediations only of the other architectures are removed,
+// and so are the KBs only of the vendor fixes removed. If arch is empty, the CVE is returned as it is.
+func FilterMicrosoftCveByArch(cve models.MicrosoftCVE, arch string) models.MicrosoftCVE {
+	if arch == "" {
+		return cve
+	}
+
+	statuses := []models.MicrosoftProductStatus{}
+	for _, s := range cve.MicrosoftProductStatuses {
+		if narrowMicrosoftProducts(&s.Products, arch) {
+			statuses = append(statuses, s)
+		}
+	}
+	cve.MicrosoftProductStatuses = statuses
+	cve.Impact = filterMicrosoftThreatsByArch(cve.Impact, arch)
+	cve.Severity = filterMicrosoftThreatsByArch(cve.Severity, arch)
+
+	scoreSets := []models.MicrosoftScoreSet{}
+	for _, s := range cve.ScoreSets {
+		if narrowMicrosoftProducts(&s.Products, arch) {
+			scoreSets = append(scoreSets, s)
+		}
+	}
+	cve.ScoreSets = scoreSets
+
+	vendorFix := filterMicrosoftRemediationsByArch(cve.VendorFix, arch)
+	// The KBs not named by the vendor fixes (e.g. the KBs of the bulletins) are kept
+	kept, removed := map[string]bool{}, map[string]bool{}
+	for _, r := range vendorFix {
+		kept[normalizeMicrosoftKBID(r.Description)] = true
+	}
+	for _, r := range cve.VendorFix {
+		if kbID := normalizeMicrosoftKBID(r.Description); kbID != "" && !kept[kbID] {
+			removed[kbID] = true
+		}
+	}
+	cve.VendorFix = vendorFix
+	cve.NoneAvailable = filterMicrosoftRemediationsByArch(cve.NoneAvailable, arch)
+	cve.WillNotFix = filterMicrosoftRemediationsByArch(cve.WillNotFix, arch)
+
+	kbIDs := []models.MicrosoftKBID{}
+	for _, k := range cve.KBIDs {
+		if !removed[k.KBID] {
+			kbIDs = append(kbIDs, k)
+		}
+	}
+	cve.KBIDs = kbIDs
+	return cve
+}
+
+// narrowMicrosoftProducts removes the products of the other architectures,
+// and returns false if all the products were removed, i.e. the entry is only of the other architectures
+func narrowMicrosoftProducts(products *[]models.MicrosoftProduct, arch string) bool {
+	before := len(*products)
+	*products = FilterMicrosoftProductsByArch(*products, arch)
+	return before == 0 || len(*products) > 0
+}
+
+func filterMicrosoftThreatsByArch(threats []models.MicrosoftThreat, arch string) []models.MicrosoftThreat {
+	filtered := []models.MicrosoftThreat{}
+	for _, t := range threats {
+		if narrowMicrosoftProducts(&t.Products, arch) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+func filterMicrosoftRemediationsByArch(remediations []models.MicrosoftRemediation, arch string) []models.MicrosoftRemediation {
+	filtered := []models.MicrosoftRemediation{}
+	for _, r := range remediations {
+		if narrowMicrosoftProducts(&r.Products, arch) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// microsoftProductVersionTokenRegexp matches the tokens distinguishing the releases (e.g. 2019, 21h2, 1809, 8.1, r2)
+var microsoftProductVersionTokenRegexp = regexp.MustCompile(`^(?:\d|r\d+$)`)
+
+func isMicrosoftProductVersionToken(t string) bool {
+	return microsoftProductVersionTokenRegexp.MatchString(t)
+}
+
+// MatchMicrosoftProducts scores the products by the similarity (Jaccard index) of the normalized tokens to name,
+// and returns the matches in the order of the score. The products with the different versions (e.g. 2016 and 2019) never match.
+// If limit is 0, all the matches are returned.
+func MatchMicrosoftProducts(products []models.MicrosoftProduct, name string, limit int) []models.MicrosoftProductMatch {
+	query := normalizeMicrosoftProduct(name)
+	matches := []models.MicrosoftProductMatch{}
+	if len(query) == 0 {
+		return matches
+	}
+
+	for _, p := range products {
+		tokens := normalizeMicrosoftProduct(p.ProductName)
+		matched, versionMismatch := 0, false
+		for t := range tokens {
+			if query[t] {
+				matched++
+			} else if isMicrosoftProductVersionToken(t) {
+				versionMismatch = true
+			}
+		}
+		for t := range query {
+			if !tokens[t] && isMicrosoftProductVersionToken(t) {
+				versionMismatch = true
+			}
+		}
+		if matched == 0 || versionMismatch {
+			continue
+		}
+		matches = append(matches, models.MicrosoftProductMatch{
+			ProductID:   p.ProductID,
+			ProductName: p.ProductName,
+			Score:       float64(matched) / float64(len(tokens)+len(query)-matched),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ProductID < matches[j].ProductID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// ResolveMicrosoftProduct resolves a product string (e.g. "Windows Server 2019 Datacenter (Server Core)") to the MSRC product IDs.
+// If arch is given, only the products of arch and the ones without architecture are matched.
+func ResolveMicrosoftProduct(driver DB, name, arch string, limit int) ([]models.MicrosoftProductMatch, error) {
+	products, err := driver.GetMicrosoftProducts()
+	if err != nil {
+		return nil, err
+	}
+	return MatchMicrosoftProducts(FilterMicrosoftProductsByArch(products, arch), name, limit), nil
+}
+
+// GetMicrosoftProducts returns the products of MSRC, in the order of ProductID
+func (r *RDBDriver) GetMicrosoftProducts() ([]models.MicrosoftProduct, error) {
+	products := []models.MicrosoftProduct{}
+	if err := r.conn.Model(&models.MicrosoftProduct{}).
+		Distinct("product_id", "product_name", "arch").
+		Where("product_id <> ''").
+		Order("product_id").
+		Find(&products).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get Microsoft products. err: %w", err)
+	}
+	return products, nil
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/gost/models"
+)
+
+func TestFilterMicrosoftCveByArchKBIDs(t *testing.T) {
+	x64 := models.MicrosoftProduct{ProductID: "1", Arch: "x64"}
+	arm64 := models.MicrosoftProduct{ProductID: "2", Arch: "arm64"}
+	cve := models.MicrosoftCVE{
+		VendorFix: []models.MicrosoftRemediation{
+			{Description: "5001", Products: []models.MicrosoftProduct{x64}},
+			{Description: "5002", Products: []models.MicrosoftProduct{arm64}},
+			{Description: "5003", Products: []models.MicrosoftProduct{x64}},
+			{Description: "5003", Products: []models.MicrosoftProduct{arm64}},
+			// the remediations of the bulletins have no KB in Description
+			{Products: []models.MicrosoftProduct{arm64}},
+		},
+		KBIDs: []models.MicrosoftKBID{{KBID: "5001"}, {KBID: "5002"}, {KBID: "5003"}, {KBID: "3212646"}},
+	}
+
+	var tests = []struct {
+		arch string
+		out  []string
+	}{
+		{arch: "", out: []string{"5001", "5002", "5003", "3212646"}},
+		{arch: "x64", out: []string{"5001", "5003", "3212646"}},
+		{arch: "arm64", out: []string{"5002", "5003", "3212646"}},
+	}
+
+	for i, tt := range tests {
+		aout := []string{}
+		for _, k := range FilterMicrosoftCveByArch(cve, tt.arch).KBIDs {
+			aout = append(aout, k.KBID)
+		}
+		if !reflect.DeepEqual(tt.out, aout) {
+			t.Errorf("[%d] expected: %v\n  actual: %v\n", i, tt.out, aout)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetNvd :
+func (r *RDBDriver) GetNvd(cveID string) *models.NvdCVE {
+	c := models.NvdCVE{}
+	err := r.conn.
+		Preload("Cvss").
+		Preload("Cwes").
+		Preload("Cpes").
+		Preload("References").
+		Where(&models.NvdCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get NVD", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertNvd :
+func (r *RDBDriver) InsertNvd(cveJSONs []models.NvdCVEJSON) (err error) {
+	cves := r.filter.filterNvd(ConvertNvd(cveJSONs, r.warnings))
+	if err = r.deleteAndInsertNvd(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert NVD CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertNvd(conn *gorm.DB, cves []models.NvdCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.NvdCvss{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.NvdCwe{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.NvdCpe{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.NvdReference{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.NvdCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertNvd converts CVE of NVD CVE API 2.0 to NvdCVE
+func ConvertNvd(cveJSONs []models.NvdCVEJSON, warnings *util.Warnings) (cves []models.NvdCVE) {
+	for _, c := range cveJSONs {
+		// Rejected CVEs have no data
+		if c.VulnStatus == "Rejected" {
+			continue
+		}
+
+		cve := models.NvdCVE{
+			CveID:            c.ID,
+			VulnStatus:       c.VulnStatus,
+			PublishedDate:    parseNvdDate(c.ID, "published", c.Published, warnings),
+			LastModifiedDate: parseNvdDate(c.ID, "lastModified", c.LastModified, warnings),
+		}
+		for _, d := range c.Descriptions {
+			if d.Lang == "en" {
+				cve.Description = d.Value
+				break
+			}
+		}
+
+		for _, metrics := range [][]models.NvdCvssMetricJSON{c.Metrics.CvssMetricV31, c.Metrics.CvssMetricV30, c.Metrics.CvssMetricV2} {
+			for _, m := range metrics {
+				severity := m.CvssData.BaseSeverity
+				if severity == "" {
+					// v2 has baseSeverity in the metric
+					severity = m.BaseSeverity
+				}
+				cve.Cvss = append(cve.Cvss, models.NvdCvss{
+					Source:       m.Source,
+					Type:         m.Type,
+					Version:      m.CvssData.Version,
+					VectorString: m.CvssData.VectorString,
+					BaseScore:    m.CvssData.BaseScore,
+					BaseSeverity: severity,
+				})
+			}
+		}
+
+		for _, w := range c.Weaknesses {
+			for _, d := range w.Description {
+				cve.Cwes = append(cve.Cwes, models.NvdCwe{Source: w.Source, CweID: d.Value})
+			}
+		}
+
+		for i, conf := range c.Configurations {
+			for j, node := range conf.Nodes {
+				for _, m := range node.CpeMatch {
+					cve.Cpes = append(cve.Cpes, models.NvdCpe{
+						Configuration:         i,
+						ConfigurationOperator: conf.Operator,
+						Node:                  j,
+						NodeOperator:          node.Operator,
+						Negate:                node.Negate,
+						Vulnerable:            m.Vulnerable,
+						Criteria:              m.Criteria,
+						VersionStartIncluding: m.VersionStartIncluding,
+						VersionStartExcluding: m.VersionStartExcluding,
+						VersionEndIncluding:   m.VersionEndIncluding,
+						VersionEndExcluding:   m.VersionEndExcluding,
+					})
+				}
+			}
+		}
+
+		for _, ref := range c.References {
+			cve.References = append(cve.References, models.NvdReference{
+				URL:    ref.URL,
+				Source: ref.Source,
+				Tags:   strings.Join(ref.Tags, ","),
+			})
+		}
+
+		cves = append(cves, cve)
+	}
+	return cves
+}
+
+// parseNvdDate parses the date of NVD CVE API 2.0 (e.g. 2023-01-01T00:15:09.983)
+func parseNvdDate(cveID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, "2006-01-02T15:04:05.000", "2006-01-02T15:04:05")
+	if err != nil {
+		warnings.Add("nvd", cveID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,232 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetOpenEuler :
+func (r *RDBDriver) GetOpenEuler(cveID string) *models.OpenEulerCVE {
+	c := models.OpenEulerCVE{}
+	err := r.conn.
+		Preload("Advisories.Packages").
+		Preload("Advisories").
+		Where(&models.OpenEulerCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get openEuler", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertOpenEuler :
+func (r *RDBDriver) InsertOpenEuler(cvrfs []models.OpenEulerCVRFXML) (err error) {
+	cves := r.filter.filterOpenEuler(ConvertOpenEuler(cvrfs, r.warnings))
+	if err = r.deleteAndInsertOpenEuler(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert openEuler CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertOpenEuler(conn *gorm.DB, cves []models.OpenEulerCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OpenEulerPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OpenEulerAdvisory{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OpenEulerCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertOpenEuler converts CVRF (per advisory) to OpenEulerCVE (per CVE)
+func ConvertOpenEuler(cvrfs []models.OpenEulerCVRFXML, warnings *util.Warnings) (cves []models.OpenEulerCVE) {
+	uniqCve := map[string]models.OpenEulerCVE{}
+	for _, cvrf := range cvrfs {
+		advisoryID := cvrf.DocumentTracking.ID
+		pkgs := openEulerPackages(cvrf.ProductTree)
+		if len(pkgs) == 0 {
+			warnings.Add("openeuler", advisoryID, "ProductTree", "No package in the product tree")
+			continue
+		}
+
+		notes := map[string]string{}
+		for _, n := range cvrf.DocumentNotes {
+			notes[n.Title] = strings.TrimSpace(n.Value)
+		}
+		issued := parseOpenEulerDate(advisoryID, cvrf.DocumentTracking.InitialReleaseDate, warnings)
+
+		for _, v := range cvrf.Vulnerabilities {
+			fixed := map[string]struct{}{}
+			for _, s := range v.ProductStatuses {
+				if s.Type != "Fixed" {
+					continue
+				}
+				for _, id := range s.ProductIDs {
+					fixed[NormalizeOpenEulerRelease(id)] = struct{}{}
+				}
+			}
+
+			severity := notes["Severity"]
+			for _, t := range v.Threats {
+				if t.Type == "Impact" && t.Description != "" {
+					severity = t.Description
+				}
+			}
+
+			advisory := models.OpenEulerAdvisory{
+				AdvisoryID:  advisoryID,
+				Title:       strings.TrimSpace(cvrf.DocumentTitle),
+				Severity:    severity,
+				Description: notes["Description"],
+				IssuedDate:  issued,
+			}
+			for _, p := range pkgs {
+				if _, ok := fixed[p.ReleaseName]; ok {
+					advisory.Packages = append(advisory.Packages, p)
+				}
+			}
+			if len(advisory.Packages) == 0 {
+				continue
+			}
+
+			cve, ok := uniqCve[v.CVE]
+			if !ok {
+				cve = models.OpenEulerCVE{CveID: v.CVE}
+			}
+			cve.Advisories = append(cve.Advisories, advisory)
+			uniqCve[v.CVE] = cve
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+// e.g. openssl-1.1.1f-3.oe1.aarch64.rpm
+var openEulerRPMRegexp = regexp.MustCompile(`^(.+)-([^-]+-[^-]+)\.[^.]+\.rpm$`)
+
+// openEulerPackages collects the packages of "Package Arch" branches.
+// The release is taken from CPE (e.g. cpe:/a:openEuler:openEuler:20.03-LTS-SP1),
+// and the same package is listed for each arch, so it is deduplicated.
+func openEulerPackages(branches []models.OpenEulerBranchXML) (pkgs []models.OpenEulerPackage) {
+	uniq := map[string]struct{}{}
+	for _, b := range branches {
+		if b.Type != "Package Arch" {
+			continue
+		}
+		for _, p := range b.FullProductNames {
+			ss := openEulerRPMRegexp.FindStringSubmatch(strings.TrimSpace(p.Value))
+			cpe := strings.Split(p.CPE, ":")
+			if ss == nil || len(cpe) < 5 {
+				continue
+			}
+			pkg := models.OpenEulerPackage{
+				ReleaseName:  NormalizeOpenEulerRelease(cpe[4]),
+				PackageName:  ss[1],
+				FixedVersion: ss[2],
+			}
+			key := pkg.ReleaseName + "#" + pkg.PackageName + "#" + pkg.FixedVersion
+			if _, ok := uniq[key]; ok {
+				continue
+			}
+			uniq[key] = struct{}{}
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs
+}
+
+func parseOpenEulerDate(advisoryID, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, "2006-01-02")
+	if err != nil {
+		warnings.Add("openeuler", advisoryID, "InitialReleaseDate", fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetFixedCvesOpenEuler gets the CVEs fixed by the advisories related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesOpenEuler(release, pkgName string) map[string]models.OpenEulerCVE {
+	m := map[string]models.OpenEulerCVE{}
+
+	type Result struct {
+		OpenEulerCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("open_euler_packages").
+		Select("DISTINCT open_euler_advisories.open_euler_cve_id").
+		Joins("JOIN open_euler_advisories ON open_euler_advisories.id = open_euler_packages.open_euler_advisory_id").
+		Where("open_euler_packages.package_name = ? AND open_euler_packages.release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of openEuler", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.OpenEulerCVE{}
+		err := r.conn.
+			Preload("Advisories.Packages", "package_name = ? AND release_name = ?", pkgName, release).
+			Preload("Advisories").
+			Where(&models.OpenEulerCVE{ID: res.OpenEulerCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get OpenEulerCVE", "err", err)
+			return m
+		}
+
+		advisories := []models.OpenEulerAdvisory{}
+		for _, a := range cve.Advisories {
+			if len(a.Packages) != 0 {
+				advisories = append(advisories, a)
+			}
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cve.CveID] = cve
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,232 @@
+package db
+
+import (
+	"time"
+
+	"github.com/knqyf263/gost/util"
+	pb "gopkg.in/cheggaaa/pb.v1"
+)
+
+// Option configures the driver created by NewDB
+type Option func(*options)
+
+type options struct {
+	insert   InsertOptions
+	filter   Filter
+	multiGet multiGetOptions
+	// queryTimeout is the timeout of each query. 0 means no timeout
+	queryTimeout time.Duration
+	// slowQueryThreshold is the elapsed time of a query to be logged as slow. 0 disables the log
+	slowQueryThreshold time.Duration
+	breaker            *CircuitBreaker
+	cveCache           *CVECache
+	// requirePersistence fails to open Redis configured as a volatile cache
+	requirePersistence bool
+	indexLayout        string
+	documentSizes      *DocumentSizes
+	seenIDs            *SeenIDs
+	warnings           *util.Warnings
+	// sqliteWAL opens SQLite3 in WAL mode
+	sqliteWAL bool
+}
+
+// multiGetOptions splits the multi-get of Redis into the pipelines of chunkSize keys executed by concurrency goroutines
+type multiGetOptions struct {
+	chunkSize   int
+	concurrency int
+}
+
+const (
+	// defaultBatchSize is the same as the default of gost fetch --batch-size
+	defaultBatchSize = 15
+	// defaultMultiGetChunkSize and defaultMultiGetConcurrency are the same as the defaults of gost server
+	defaultMultiGetChunkSize   = 1000
+	defaultMultiGetConcurrency = 4
+)
+
+func newOptions(opts ...Option) options {
+	o := options{
+		insert:   InsertOptions{BatchSize: defaultBatchSize},
+		multiGet: multiGetOptions{chunkSize: defaultMultiGetChunkSize, concurrency: defaultMultiGetConcurrency},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithBatchSize sets the number of records inserted at once. It is not used for Redis.
+func WithBatchSize(batchSize int) Option {
+	return func(o *options) {
+		if batchSize > 0 {
+			o.insert.BatchSize = batchSize
+		}
+	}
+}
+
+// WithExpire sets the timeout of Redis keys in seconds. If it is 0, the keys are persistent.
+func WithExpire(expire uint) Option {
+	return func(o *options) {
+		o.insert.TTL = expire
+	}
+}
+
+// WithProgress sets the reporter of the insert progress
+func WithProgress(progress ProgressReporter) Option {
+	return func(o *options) {
+		o.insert.Progress = progress
+	}
+}
+
+// WithMultiGetChunk sets the number of keys in a pipeline and the number of the pipelines executed concurrently
+// for the multi-get of Redis (e.g. GetMicrosoftMulti). The values of 0 or less keep the defaults. It is not used for RDB.
+func WithMultiGetChunk(chunkSize, concurrency int) Option {
+	return func(o *options) {
+		if chunkSize > 0 {
+			o.multiGet.chunkSize = chunkSize
+		}
+		if concurrency > 0 {
+			o.multiGet.concurrency = concurrency
+		}
+	}
+}
+
+// WithQueryTimeout sets the timeout of each query to the DB, so that a slow query does not block the caller forever.
+// The timed out query fails as the other errors of the DB do. On RDB it applies to SELECT only. 0 means no timeout.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.queryTimeout = timeout
+	}
+}
+
+// WithSlowQueryThreshold logs the queries taking threshold or longer with the SQL or the Redis command, including the keys.
+// On RDB it applies to SELECT only. 0 disables the log.
+func WithSlowQueryThreshold(threshold time.Duration) Option {
+	return func(o *options) {
+		o.slowQueryThreshold = threshold
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker recording the failures of the queries.
+// The same breaker can be shared with the caller (e.g. the server) to fail fast while the DB is down. nil disables it.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(o *options) {
+		o.breaker = breaker
+	}
+}
+
+// WithCVECache answers GetRedhat, GetDebian, GetUbuntu and GetMicrosoft from the cache, cleared by the writes of the driver
+// and by the fetches recorded in the DB (e.g. gost fetch in another process). The other writes are seen after the TTL. nil disables it.
+func WithCVECache(cache *CVECache) Option {
+	return func(o *options) {
+		o.cveCache = cache
+	}
+}
+
+// WithRequirePersistence fails to open Redis when neither AOF nor RDB snapshots are enabled, or the keys of gost may be evicted by maxmemory-policy.
+// Without it, they are logged as warnings. It is not used for RDB.
+func WithRequirePersistence(require bool) Option {
+	return func(o *options) {
+		o.requirePersistence = require
+	}
+}
+
+// WithIndexLayout sets the layout of the indexes of the packages in a new Redis DB (IndexLayoutZSet or IndexLayoutSet).
+// The DB keeps the layout it is created with, so it fails to open the DB of the other layout. Empty uses the layout of the DB.
+func WithIndexLayout(layout string) Option {
+	return func(o *options) {
+		o.indexLayout = layout
+	}
+}
+
+// WithDocumentSizes records the sizes of the JSON documents stored into Redis per source, and applies the action of sizes
+// to the documents exceeding the limit. nil disables it. It is not used for RDB.
+func WithDocumentSizes(sizes *DocumentSizes) Option {
+	return func(o *options) {
+		o.documentSizes = sizes
+	}
+}
+
+// WithSeenIDs records the CVE-IDs stored into Redis per source into seen for RemoveMissing. nil disables it. It is not used for RDB.
+func WithSeenIDs(seen *SeenIDs) Option {
+	return func(o *options) {
+		o.seenIDs = seen
+	}
+}
+
+// WithWarnings collects the malformed upstream entries converted on insert into warnings. nil only logs them.
+func WithWarnings(warnings *util.Warnings) Option {
+	return func(o *options) {
+		o.warnings = warnings
+	}
+}
+
+// WithSQLiteWAL opens SQLite3 in WAL mode with a busy timeout, so that a fetch can write the DB while a server reads it.
+// The readers see the snapshot of the DB at the start of each query. It is not used for the other DBs.
+func WithSQLiteWAL(wal bool) Option {
+	return func(o *options) {
+		o.sqliteWAL = wal
+	}
+}
+
+// WithFilter sets the filter applied on insert
+func WithFilter(filter Filter) Option {
+	return func(o *options) {
+		o.filter = filter
+	}
+}
+
+// InsertOptions are the options of Insert* and Upsert*.
+// They are set by NewDB options, and can be replaced per driver by DB.WithInsertOptions.
+type InsertOptions struct {
+	// TTL is the timeout of Redis keys in seconds. If it is 0, the keys are persistent.
+	TTL uint
+	// BatchSize is the number of records inserted at once. It is not used for Redis.
+	// If it is 0 or less, the default (15) is used.
+	BatchSize int
+	// Progress reports the progress of insert. If it is nil, a progress bar is shown.
+	Progress ProgressReporter
+}
+
+func (o InsertOptions) normalize() InsertOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultBatchSize
+	}
+	return o
+}
+
+// ProgressReporter receives the progress of insert.
+// Start is called with the number of records at the beginning of each insert.
+type ProgressReporter interface {
+	Start(total int)
+	Add(n int)
+	Finish()
+}
+
+// NoProgress is ProgressReporter which reports nothing (e.g. for library use)
+var NoProgress ProgressReporter = noProgress{}
+
+type noProgress struct{}
+
+func (noProgress) Start(int) {}
+func (noProgress) Add(int)   {}
+func (noProgress) Finish()   {}
+
+// progressBar shows the progress in the terminal
+type progressBar struct {
+	bar *pb.ProgressBar
+}
+
+func (p *progressBar) Start(total int) { p.bar = pb.StartNew(total) }
+func (p *progressBar) Add(n int)       { p.bar.Add(n) }
+func (p *progressBar) Finish()         { p.bar.Finish() }
+
+// startProgress starts reporting the progress of an insert of total records.
+// The default progress bar is created per insert, so concurrent inserts do not share it.
+func startProgress(progress ProgressReporter, total int) ProgressReporter {
+	if progress == nil {
+		progress = &progressBar{}
+	}
+	progress.Start(total)
+	return progress
+}
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+var (
+	// e.g. "Oracle Linux 8 is installed"
+	oracleReleaseCriterion = regexp.MustCompile(`^Oracle Linux (\d+) is installed$`)
+	// e.g. "python3-libs is earlier than 0:3.6.8-37.0.1.el8"
+	oraclePackageCriterion = regexp.MustCompile(`^(\S+) is earlier than (\S+)$`)
+)
+
+// GetOracle :
+func (r *RDBDriver) GetOracle(cveID string) *models.OracleCVE {
+	c := models.OracleCVE{}
+	err := r.conn.
+		Preload("Advisories.Packages").
+		Preload("Advisories").
+		Where(&models.OracleCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Oracle", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertOracle :
+func (r *RDBDriver) InsertOracle(elsaJSONs []models.OracleOVALJSON) (err error) {
+	cves := r.filter.filterOracle(ConvertOracle(elsaJSONs, r.warnings))
+	if err = r.deleteAndInsertOracle(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Oracle CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertOracle(conn *gorm.DB, cves []models.OracleCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OraclePackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OracleAdvisory{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OracleCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertOracle converts ELSA (per advisory) to OracleCVE (per CVE)
+func ConvertOracle(elsaJSONs []models.OracleOVALJSON, warnings *util.Warnings) (cves []models.OracleCVE) {
+	uniqCve := map[string]models.OracleCVE{}
+	for _, elsa := range elsaJSONs {
+		advisoryID := oracleAdvisoryID(elsa)
+		pkgs := walkOracleCriteria(elsa.Criteria, "", map[string]struct{}{})
+		if len(pkgs) == 0 {
+			warnings.Add("oracle", advisoryID, "Criteria", "No fixed package in the criteria")
+			continue
+		}
+
+		issued := parseOracleDate(advisoryID, "IssueDate", elsa.IssueDate.Date, warnings)
+		for _, c := range elsa.Cves {
+			advisory := models.OracleAdvisory{
+				AdvisoryID:  advisoryID,
+				Title:       elsa.Title,
+				Severity:    elsa.Severity,
+				Description: elsa.Description,
+				IssuedDate:  issued,
+				Packages:    append([]models.OraclePackage(nil), pkgs...),
+			}
+
+			cve, ok := uniqCve[c.ID]
+			if !ok {
+				cve = models.OracleCVE{CveID: c.ID}
+			}
+			cve.Advisories = append(cve.Advisories, advisory)
+			uniqCve[c.ID] = cve
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+// walkOracleCriteria collects the fixed packages from the criteria.
+// The release is taken from "Oracle Linux X is installed" in the same or parent criteria.
+// The same package is listed for each arch, so it is deduplicated with uniq.
+func walkOracleCriteria(criteria models.OracleCriteriaJSON, release string, uniq map[string]struct{}) (pkgs []models.OraclePackage) {
+	for _, c := range criteria.Criterions {
+		if ss := oracleReleaseCriterion.FindStringSubmatch(c.Comment); ss != nil {
+			release = ss[1]
+		}
+	}
+
+	for _, c := range criteria.Criterions {
+		ss := oraclePackageCriterion.FindStringSubmatch(c.Comment)
+		if ss == nil || release == "" {
+			continue
+		}
+		pkg := models.OraclePackage{
+			ReleaseName:  release,
+			PackageName:  ss[1],
+			FixedVersion: strings.TrimPrefix(ss[2], "0:"),
+		}
+		key := pkg.ReleaseName + "#" + pkg.PackageName + "#" + pkg.FixedVersion
+		if _, ok := uniq[key]; ok {
+			continue
+		}
+		uniq[key] = struct{}{}
+		pkgs = append(pkgs, pkg)
+	}
+
+	for _, c := range criteria.Criterias {
+		pkgs = append(pkgs, walkOracleCriteria(c, release, uniq)...)
+	}
+	return pkgs
+}
+
+// oracleAdvisoryID returns ELSA ID (e.g. ELSA-2021-1234)
+func oracleAdvisoryID(elsa models.OracleOVALJSON) string {
+	for _, ref := range elsa.References {
+		if ref.Source == "elsa" {
+			return ref.ID
+		}
+	}
+	// e.g. "ELSA-2021-1234:  openssl security update (IMPORTANT)"
+	return strings.TrimSpace(strings.SplitN(elsa.Title, ":", 2)[0])
+}
+
+func parseOracleDate(advisoryID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, "2006-01-02")
+	if err != nil {
+		warnings.Add("oracle", advisoryID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetFixedCvesOracle gets the CVEs fixed by ELSA related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesOracle(release, pkgName string) map[string]models.OracleCVE {
+	m := map[string]models.OracleCVE{}
+
+	type Result struct {
+		OracleCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("oracle_packages").
+		Select("DISTINCT oracle_advisories.oracle_cve_id").
+		Joins("JOIN oracle_advisories ON oracle_advisories.id = oracle_packages.oracle_advisory_id").
+		Where("oracle_packages.package_name = ? AND oracle_packages.release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Oracle", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.OracleCVE{}
+		err := r.conn.
+			Preload("Advisories.Packages", "package_name = ? AND release_name = ?", pkgName, release).
+			Preload("Advisories").
+			Where(&models.OracleCVE{ID: res.OracleCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get OracleCVE", "err", err)
+			return m
+		}
+
+		advisories := []models.OracleAdvisory{}
+		for _, a := range cve.Advisories {
+			if len(a.Packages) != 0 {
+				advisories = append(advisories, a)
+			}
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cve.CveID] = cve
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,273 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetOsvByPackage gets the vulnerabilities of the package in the ecosystem
+func (r *RDBDriver) GetOsvByPackage(ecosystem, pkgName string) map[string]models.OsvEntry {
+	m := map[string]models.OsvEntry{}
+	pkgs := []models.OsvPackage{}
+	err := r.conn.Where(&models.OsvPackage{Ecosystem: ecosystem, PackageName: pkgName}).Find(&pkgs).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get OSV by package", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(pkgs))
+	for _, p := range pkgs {
+		e := models.OsvEntry{}
+		if err := r.conn.Preload("Packages").Where(&models.OsvEntry{ID: p.OsvEntryID}).First(&e).Error; err != nil {
+			r.log.Error("Failed to get OSV by package", "err", err)
+			return nil
+		}
+		m[e.OsvID] = e
+	}
+	return m
+}
+
+// InsertOsv :
+func (r *RDBDriver) InsertOsv(vulnJSONs []models.OsvJSON) (err error) {
+	entries := r.filter.filterOsv(ConvertOsv(vulnJSONs, r.warnings))
+	if err = r.deleteAndInsertOsv(r.conn, entries); err != nil {
+		return xerrors.Errorf("Failed to insert OSV data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertOsv(conn *gorm.DB, entries []models.OsvEntry) (err error) {
+	bar := startProgress(r.insert.Progress, len(entries))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OsvPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.OsvEntry{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(entries), r.insert.BatchSize) {
+		if err = tx.Create(entries[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertOsv converts OSV JSON to OsvEntry. Withdrawn vulnerabilities are skipped.
+func ConvertOsv(vulnJSONs []models.OsvJSON, warnings *util.Warnings) (entries []models.OsvEntry) {
+	uniqID := map[string]struct{}{}
+	for _, v := range vulnJSONs {
+		if v.Withdrawn != "" {
+			continue
+		}
+		if _, ok := uniqID[v.ID]; ok {
+			continue
+		}
+		uniqID[v.ID] = struct{}{}
+
+		entry := models.OsvEntry{
+			OsvID:    v.ID,
+			Modified: parseOsvDate(v.ID, "modified", v.Modified, warnings),
+			Raw:      string(v.Raw),
+		}
+		uniqPkg := map[string]struct{}{}
+		for _, a := range v.Affected {
+			ecosystem := NormalizeOsvEcosystem(a.Package.Ecosystem)
+			pkgName := NormalizeOsvPackageName(ecosystem, a.Package.Name)
+			if _, ok := uniqPkg[ecosystem+"#"+pkgName]; ok {
+				continue
+			}
+			uniqPkg[ecosystem+"#"+pkgName] = struct{}{}
+			entry.Packages = append(entry.Packages, models.OsvPackage{Ecosystem: ecosystem, PackageName: pkgName})
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseOsvDate parses the date of OSV (RFC3339, e.g. 2021-12-10T00:40:56Z)
+func parseOsvDate(osvID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339)
+	if err != nil {
+		warnings.Add("osv", osvID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// osvEcosystems maps the lower-cased names of the ecosystems to the ones of OSV, which are case-sensitive
+var osvEcosystems = map[string]string{
+	"pypi":      "PyPI",
+	"npm":       "npm",
+	"go":        "Go",
+	"maven":     "Maven",
+	"rubygems":  "RubyGems",
+	"crates.io": "crates.io",
+	"packagist": "Packagist",
+	"nuget":     "NuGet",
+	"hex":       "Hex",
+	"pub":       "Pub",
+}
+
+// NormalizeOsvEcosystem normalizes the ecosystem (e.g. pypi) to the one of OSV (e.g. PyPI)
+func NormalizeOsvEcosystem(ecosystem string) string {
+	if e, ok := osvEcosystems[strings.ToLower(ecosystem)]; ok {
+		return e
+	}
+	return ecosystem
+}
+
+// NormalizeOsvPackageName normalizes the names of PyPI packages as PEP 503. The names in the other ecosystems are returned as they are.
+func NormalizeOsvPackageName(ecosystem, pkgName string) string {
+	if ecosystem == "PyPI" {
+		return NormalizeGhsaPackageName("PIP", pkgName)
+	}
+	return pkgName
+}
+
+// GetOsv gets the OSV documents of the vulnerabilities of the package in the ecosystem, sorted by ID.
+// The ecosystem and the package name are normalized. If ver is empty, all vulnerabilities of the package are returned.
+func GetOsv(driver DB, ecosystem, pkgName, ver string) ([]json.RawMessage, error) {
+	ecosystem = NormalizeOsvEcosystem(ecosystem)
+	pkgName = NormalizeOsvPackageName(ecosystem, pkgName)
+	entries := driver.GetOsvByPackage(ecosystem, pkgName)
+
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	docs := []json.RawMessage{}
+	for _, id := range ids {
+		raw := json.RawMessage(entries[id].Raw)
+		if ver != "" {
+			v := models.OsvJSON{}
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, xerrors.Errorf("Failed to decode OSV JSON. id: %s, err: %w", id, err)
+			}
+			if !osvAffected(v, ecosystem, pkgName, ver) {
+				continue
+			}
+		}
+		docs = append(docs, raw)
+	}
+	return docs, nil
+}
+
+// osvAffected returns true if ver of the package is listed in versions, or is in SEMVER or ECOSYSTEM ranges.
+// If the version cannot be compared, it returns true so that the vulnerability is not missed.
+func osvAffected(v models.OsvJSON, ecosystem, pkgName, ver string) bool {
+	for _, a := range v.Affected {
+		if NormalizeOsvEcosystem(a.Package.Ecosystem) != ecosystem || NormalizeOsvPackageName(ecosystem, a.Package.Name) != pkgName {
+			continue
+		}
+		for _, av := range a.Versions {
+			if av == ver {
+				return true
+			}
+		}
+		for _, r := range a.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			affected, err := osvInRange(r.Events, ver)
+			if err != nil {
+				log15.Debug("Failed to compare the version of OSV", "id", v.ID, "version", ver, "err", err)
+				return true
+			}
+			if affected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// osvInRange evaluates the events of the range in order as OSV schema
+func osvInRange(events []models.OsvEventJSON, ver string) (bool, error) {
+	v, err := version.NewVersion(ver)
+	if err != nil {
+		return false, err
+	}
+	compare := func(s string) (int, error) {
+		e, err := version.NewVersion(s)
+		if err != nil {
+			return 0, err
+		}
+		return v.Compare(e), nil
+	}
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" {
+				affected = true
+				continue
+			}
+			c, err := compare(e.Introduced)
+			if err != nil {
+				return false, err
+			}
+			if c >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			c, err := compare(e.Fixed)
+			if err != nil {
+				return false, err
+			}
+			if c >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			c, err := compare(e.LastAffected)
+			if err != nil {
+				return false, err
+			}
+			if c > 0 {
+				affected = false
+			}
+		case e.Limit != "":
+			c, err := compare(e.Limit)
+			if err != nil {
+				return false, err
+			}
+			if c >= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected, nil
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// patchLagReleaseNormalizers has the distros which have the fixed date, and how to normalize their releases
+var patchLagReleaseNormalizers = map[string]func(string) string{
+	"redhat":    util.Major,
+	"debian":    NormalizeDebianRelease,
+	"ubuntu":    NormalizeUbuntuRelease,
+	"amazon":    NormalizeAmazonRelease,
+	"oracle":    NormalizeOracleRelease,
+	"rocky":     NormalizeRockyRelease,
+	"alma":      NormalizeAlmaRelease,
+	"mariner":   NormalizeMarinerRelease,
+	"openeuler": NormalizeOpenEulerRelease,
+	"anolis":    NormalizeAnolisRelease,
+}
+
+// cveIDColumns has the table and the column of CVE-ID of each source
+var cveIDColumns = map[string][2]string{
+	"redhat":    {"redhat_cves", "name"},
+	"debian":    {"debian_cves", "cve_id"},
+	"ubuntu":    {"ubuntu_cves", "candidate"},
+	"amazon":    {"amazon_cves", "cve_id"},
+	"alpine":    {"alpine_cves", "cve_id"},
+	"wolfi":     {"wolfi_cves", "cve_id"},
+	"oracle":    {"oracle_cves", "cve_id"},
+	"rocky":     {"rocky_cves", "cve_id"},
+	"alma":      {"alma_cves", "cve_id"},
+	"photon":    {"photon_cves", "cve_id"},
+	"mariner":   {"mariner_cves", "cve_id"},
+	"openeuler": {"open_euler_cves", "cve_id"},
+	"anolis":    {"anolis_cves", "cve_id"},
+	"microsoft": {"microsoft_cves", "cve_id"},
+	"nvd":       {"nvd_cves", "cve_id"},
+}
+
+// GetCveIDs returns all CVE-IDs stored for the source
+func (r *RDBDriver) GetCveIDs(source string) ([]string, error) {
+	cveIDs := []string{}
+	if err := r.ForEachCveID(source, func(cveID string) error {
+		cveIDs = append(cveIDs, cveID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return cveIDs, nil
+}
+
+// ForEachCveID calls fn with each CVE-ID stored for the source in the order of CVE-ID, reading the rows one by one.
+// The error of fn stops the iteration and is returned.
+func (r *RDBDriver) ForEachCveID(source string, fn func(cveID string) error) error {
+	tc, ok := cveIDColumns[source]
+	if !ok {
+		return xerrors.Errorf("Failed to get CVE-IDs. source: %s, err: %w", source, ErrUnknownSource)
+	}
+	rows, err := r.conn.Table(tc[0]).Distinct(tc[1]).Order(tc[1]).Rows()
+	if err != nil {
+		return xerrors.Errorf("Failed to get CVE-IDs. source: %s, err: %w", source, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cveID string
+		if err := rows.Scan(&cveID); err != nil {
+			return xerrors.Errorf("Failed to scan CVE-ID. source: %s, err: %w", source, err)
+		}
+		if err := fn(cveID); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return xerrors.Errorf("Failed to get CVE-IDs. source: %s, err: %w", source, err)
+	}
+	return nil
+}
+
+// GetPatchLag computes the distribution of days from the publication to the first fix in the release of the distro.
+// The public date is the earliest one among all the sources, the same as GetTimeline.
+// CVEs without the public date or the fix in the release are skipped.
+func GetPatchLag(driver DB, distro, release string) (models.PatchLagReport, error) {
+	normalize, ok := patchLagReleaseNormalizers[distro]
+	if !ok {
+		return models.PatchLagReport{}, xerrors.Errorf("Failed to get patch lag. distro: %s, err: %w", distro, ErrUnknownSource)
+	}
+	release = normalize(release)
+
+	cveIDs, err := driver.GetCveIDs(distro)
+	if err != nil {
+		return models.PatchLagReport{}, err
+	}
+	sort.Strings(cveIDs)
+
+	report := models.PatchLagReport{Distro: distro, Release: release, Cves: []models.PatchLagRecord{}}
+	for _, cveID := range cveIDs {
+		timeline := GetTimeline(driver, cveID)
+		if timeline.PublicDate == nil {
+			continue
+		}
+		for _, fix := range timeline.Fixes {
+			if fix.Source != distro || normalize(fix.Release) != release {
+				continue
+			}
+			report.Cves = append(report.Cves, models.PatchLagRecord{
+				CveID:      cveID,
+				Advisory:   fix.Advisory,
+				PublicDate: *timeline.PublicDate,
+				FixedDate:  fix.Date,
+				DaysToFix:  *fix.DaysToFix,
+			})
+			break
+		}
+	}
+	log15.Info("Computed patch lag", "distro", distro, "release", release, "CVEs", len(cveIDs), "fixed", len(report.Cves))
+
+	report.Summary = summarizePatchLag(report.Cves)
+	return report, nil
+}
+
+func summarizePatchLag(records []models.PatchLagRecord) (s models.PatchLagSummary) {
+	if len(records) == 0 {
+		return s
+	}
+
+	days := []int{}
+	sum := 0
+	for _, r := range records {
+		days = append(days, r.DaysToFix)
+		sum += r.DaysToFix
+	}
+	sort.Ints(days)
+
+	// nearest-rank method
+	percentile := func(p int) int {
+		rank := (p*len(days) + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+		return days[rank-1]
+	}
+	return models.PatchLagSummary{
+		Count: len(days),
+		Min:   days[0],
+		Max:   days[len(days)-1],
+		Mean:  float64(sum) / float64(len(days)),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+	}
+}
+
+// PatchLagDistros returns the distros supported by GetPatchLag
+func PatchLagDistros() []string {
+	distros := []string{}
+	for d := range patchLagReleaseNormalizers {
+		distros = append(distros, d)
+	}
+	sort.Strings(distros)
+	return distros
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetPhoton :
+func (r *RDBDriver) GetPhoton(cveID string) *models.PhotonCVE {
+	c := models.PhotonCVE{}
+	err := r.conn.
+		Preload("Packages").
+		Where(&models.PhotonCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Photon", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertPhoton :
+func (r *RDBDriver) InsertPhoton(cveJSONs []models.PhotonCVEJSON) (err error) {
+	cves := r.filter.filterPhoton(ConvertPhoton(cveJSONs, r.warnings))
+	if err = r.deleteAndInsertPhoton(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Photon CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertPhoton(conn *gorm.DB, cves []models.PhotonCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.PhotonPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.PhotonCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertPhoton converts CVE metadata (per release and package) to PhotonCVE (per CVE)
+func ConvertPhoton(cveJSONs []models.PhotonCVEJSON, warnings *util.Warnings) (cves []models.PhotonCVE) {
+	uniqCve := map[string]models.PhotonCVE{}
+	for _, c := range cveJSONs {
+		// "NA" means not fixed yet
+		if c.ResVer == "" || c.ResVer == "NA" {
+			continue
+		}
+		if !strings.HasPrefix(c.CveID, "CVE-") {
+			warnings.Add("photon", c.CveID, "cve_id", "Not a CVE-ID")
+			continue
+		}
+
+		cve, ok := uniqCve[c.CveID]
+		if !ok {
+			cve = models.PhotonCVE{CveID: c.CveID}
+		}
+		cve.Packages = append(cve.Packages, models.PhotonPackage{
+			ReleaseName:  c.Release,
+			PackageName:  c.Pkg,
+			FixedVersion: c.ResVer,
+			CveScore:     c.CveScore,
+		})
+		uniqCve[c.CveID] = cve
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+// GetFixedCvesPhoton gets the CVEs related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesPhoton(release, pkgName string) map[string]models.PhotonCVE {
+	m := map[string]models.PhotonCVE{}
+
+	type Result struct {
+		PhotonCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("photon_packages").
+		Select("photon_cve_id").
+		Where("package_name = ? AND release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Photon", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.PhotonCVE{}
+		err := r.conn.
+			Preload("Packages", "package_name = ? AND release_name = ?", pkgName, release).
+			Where(&models.PhotonCVE{ID: res.PhotonCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get PhotonCVE", "err", err)
+			return m
+		}
+		if len(cve.Packages) != 0 {
+			m[cve.CveID] = cve
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// GetPackageNames gets the names of the packages starting with prefix in the release of the family, in the order of the name.
+// The release is normalized and the derivative distribution is resolved in the same way as GetUnfixedCves.
+// The empty release lists the packages of all the releases.
+func GetPackageNames(driver DB, family, release, prefix string) ([]string, error) {
+	family, release, err := ResolveDerivative(family, release)
+	if err != nil {
+		return nil, err
+	}
+	if release != "" {
+		switch family {
+		case "redhat":
+			release = util.Major(release)
+		case "debian":
+			release = NormalizeDebianRelease(release)
+		case "ubuntu":
+			release = NormalizeUbuntuRelease(release)
+		}
+	}
+	return driver.GetPackageNames(family, release, prefix)
+}
+
+// packageNamesRelease returns the value of the release in the DB: the CPE of RedHat, or the codename of Debian and Ubuntu
+func packageNamesRelease(family, release string) (string, error) {
+	switch family {
+	case "redhat":
+		if release == "" {
+			return "", nil
+		}
+		return fmt.Sprintf("cpe:/o:redhat:enterprise_linux:%s", release), nil
+	case "debian", "ubuntu":
+		if release == "" {
+			return "", nil
+		}
+		distro := models.DistroDebian
+		if family == "ubuntu" {
+			distro = models.DistroUbuntu
+		}
+		codeName, ok := codenameOf(distro, release)
+		if !ok {
+			return "", xerrors.Errorf("Failed to get package names. family: %s, release: %s, err: %w", family, release, ErrUnknownRelease)
+		}
+		return codeName, nil
+	default:
+		return "", xerrors.Errorf("Failed to get package names. family: %s, err: %w", family, ErrUnknownSource)
+	}
+}
+
+// GetPackageNames gets the names of the packages by SELECT DISTINCT of the package table of the family
+func (r *RDBDriver) GetPackageNames(family, release, prefix string) ([]string, error) {
+	rel, err := packageNamesRelease(family, release)
+	if err != nil {
+		return nil, err
+	}
+	// '!' escapes the wildcards of LIKE, since the backslash is also the escape of the string literal of MySQL
+	like := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(prefix) + "%"
+
+	var q = r.conn
+	switch family {
+	case "redhat":
+		q = q.Table("redhat_package_states").Select("DISTINCT package_name AS name")
+		if rel != "" {
+			q = q.Where("cpe = ?", rel)
+		}
+	case "debian":
+		q = q.Table("debian_packages").Select("DISTINCT debian_packages.package_name AS name")
+		if rel != "" {
+			q = q.Joins("JOIN debian_releases ON debian_releases.debian_package_id = debian_packages.id").Where("debian_releases.product_name = ?", rel)
+		}
+	case "ubuntu":
+		q = q.Table("ubuntu_patches").Select("DISTINCT ubuntu_patches.package_name AS name")
+		if rel != "" {
+			q = q.Joins("JOIN ubuntu_release_patches ON ubuntu_release_patches.ubuntu_patch_id = ubuntu_patches.id").Where("ubuntu_release_patches.release_name = ?", rel)
+		}
+	}
+	names := []string{}
+	if err := q.Where("package_name LIKE ? ESCAPE '!'", like).Order("name").Pluck("name", &names).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get package names. family: %s, err: %w", family, err)
+	}
+	return names, nil
+}
+
+// GetPackageNames gets the names of the packages by SCAN of the package indexes of the family.
+// The indexes are not by release, so the release is only validated and the packages of all the releases are listed.
+func (r *RedisDriver) GetPackageNames(family, release, prefix string) ([]string, error) {
+	if _, err := packageNamesRelease(family, release); err != nil {
+		return nil, err
+	}
+	keyPrefix := map[string]string{"redhat": zindRedHatPrefix, "debian": zindDebianPrefix, "ubuntu": zindUbuntuPrefix}[family]
+	if keyPrefix == "" {
+		return nil, xerrors.Errorf("Failed to get package names. family: %s, err: %w", family, ErrUnknownSource)
+	}
+	pattern := keyPrefix + strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(prefix) + "*"
+
+	ctx := context.Background()
+	// SCAN may return a key more than once
+	uniq := map[string]struct{}{}
+	var cursor uint64
+	for {
+		keys, next, err := r.conn.Scan(ctx, cursor, pattern, 1000).Result()
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to scan keys. err: %w", err)
+		}
+		for _, key := range keys {
+			uniq[strings.TrimPrefix(key, keyPrefix)] = struct{}{}
+		}
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	names := make([]string, 0, len(uniq))
+	for name := range uniq {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
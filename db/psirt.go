@@ -0,0 +1,374 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// e.g. Cisco IOS XE Software 17.3.1, Cisco Adaptive Security Appliance (ASA) Software 9.16.1.28
+var ciscoProductVersionRegexp = regexp.MustCompile(`^(.+?)\s+(\d+(?:\.[\w()-]+)+)$`)
+
+// NormalizePsirtProduct returns the product name compared case-insensitively (e.g. FortiOS => fortios)
+func NormalizePsirtProduct(product string) string {
+	return strings.ToLower(strings.Join(strings.Fields(product), " "))
+}
+
+// GetPsirt :
+func (r *RDBDriver) GetPsirt(vendor, advisoryID string) *models.PsirtAdvisory {
+	a := models.PsirtAdvisory{}
+	err := r.conn.
+		Preload("CveIDs").
+		Preload("Products").
+		Where(&models.PsirtAdvisory{Vendor: vendor, AdvisoryID: advisoryID}).
+		First(&a).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get PSIRT advisory", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetPsirtByCveID gets the advisories of the vendors linked to the CVE
+func (r *RDBDriver) GetPsirtByCveID(cveID string) map[string]models.PsirtAdvisory {
+	m := map[string]models.PsirtAdvisory{}
+	cves := []models.PsirtCve{}
+	err := r.conn.Where(&models.PsirtCve{CveID: cveID}).Find(&cves).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get PSIRT advisories by CVE-ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(cves))
+	for _, c := range cves {
+		a := models.PsirtAdvisory{}
+		err := r.conn.
+			Preload("CveIDs").
+			Preload("Products").
+			Where(&models.PsirtAdvisory{ID: c.PsirtAdvisoryID}).
+			First(&a).Error
+		if err != nil {
+			r.log.Error("Failed to get PSIRT advisories by CVE-ID", "err", err)
+			return nil
+		}
+		m[a.AdvisoryID] = a
+	}
+	return m
+}
+
+// GetPsirtByProduct gets the advisories of the vendor affecting the product. Only the versions of the product are in Products.
+func (r *RDBDriver) GetPsirtByProduct(vendor, product string) map[string]models.PsirtAdvisory {
+	products := []models.PsirtProduct{}
+	err := r.conn.
+		Joins("JOIN psirt_advisories ON psirt_advisories.id = psirt_products.psirt_advisory_id").
+		Where("psirt_advisories.vendor = ? AND psirt_products.product = ?", vendor, NormalizePsirtProduct(product)).
+		Find(&products).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get PSIRT advisories by product", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(products))
+	byID := map[int64]models.PsirtAdvisory{}
+	for _, p := range products {
+		a, ok := byID[p.PsirtAdvisoryID]
+		if !ok {
+			err := r.conn.
+				Preload("CveIDs").
+				Where(&models.PsirtAdvisory{ID: p.PsirtAdvisoryID}).
+				First(&a).Error
+			if err != nil {
+				r.log.Error("Failed to get PSIRT advisories by product", "err", err)
+				return nil
+			}
+		}
+		a.Products = append(a.Products, p)
+		byID[p.PsirtAdvisoryID] = a
+	}
+
+	m := map[string]models.PsirtAdvisory{}
+	for _, a := range byID {
+		m[a.AdvisoryID] = a
+	}
+	return m
+}
+
+// GetVulnerablePsirt gets the advisories of the vendor affecting the version of the product.
+// The products without the versions in the advisory are taken as affected, so that the advisory is not missed.
+func GetVulnerablePsirt(driver DB, vendor, product, ver string) map[string]models.PsirtAdvisory {
+	advisories := driver.GetPsirtByProduct(strings.ToLower(vendor), product)
+	if ver == "" {
+		return advisories
+	}
+
+	m := map[string]models.PsirtAdvisory{}
+	for id, a := range advisories {
+		products := []models.PsirtProduct{}
+		for _, p := range a.Products {
+			if p.Version == "" || p.Version == strings.TrimSpace(ver) {
+				products = append(products, p)
+			}
+		}
+		if len(products) > 0 {
+			a.Products = products
+			m[id] = a
+		}
+	}
+	return m
+}
+
+// InsertPsirt replaces the advisories of the vendor
+func (r *RDBDriver) InsertPsirt(vendor string, advisories []models.PsirtAdvisory) (err error) {
+	advisories = r.filter.filterPsirt(advisories)
+	if err = r.deleteAndInsertPsirt(r.conn, vendor, advisories); err != nil {
+		return xerrors.Errorf("Failed to insert PSIRT advisories. vendor: %s, err: %s", vendor, err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertPsirt(conn *gorm.DB, vendor string, advisories []models.PsirtAdvisory) (err error) {
+	bar := startProgress(r.insert.Progress, len(advisories))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete the old records of the vendor
+	ids := []int64{}
+	if err = tx.Model(&models.PsirtAdvisory{}).Where("vendor = ?", vendor).Pluck("id", &ids).Error; err != nil {
+		return xerrors.Errorf("Failed to get old records. err: %w", err)
+	}
+	if len(ids) > 0 {
+		var errs util.Errors
+		errs = errs.Add(tx.Where("psirt_advisory_id IN ?", ids).Delete(models.PsirtCve{}).Error)
+		errs = errs.Add(tx.Where("psirt_advisory_id IN ?", ids).Delete(models.PsirtProduct{}).Error)
+		errs = errs.Add(tx.Where("id IN ?", ids).Delete(models.PsirtAdvisory{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+		}
+	}
+
+	for idx := range chunkSlice(len(advisories), r.insert.BatchSize) {
+		if err = tx.Create(advisories[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// psirtBuilder collects the CVEs and the products of an advisory without duplicates
+type psirtBuilder struct {
+	models.PsirtAdvisory
+	cves     map[string]struct{}
+	products map[models.PsirtProduct]struct{}
+}
+
+func newPsirtBuilder(a models.PsirtAdvisory) *psirtBuilder {
+	return &psirtBuilder{PsirtAdvisory: a, cves: map[string]struct{}{}, products: map[models.PsirtProduct]struct{}{}}
+}
+
+func (b *psirtBuilder) addCve(cveID string) {
+	if cveID = strings.TrimSpace(cveID); cveID == "" || strings.EqualFold(cveID, "NA") {
+		return
+	}
+	if _, ok := b.cves[cveID]; ok {
+		return
+	}
+	b.cves[cveID] = struct{}{}
+	b.CveIDs = append(b.CveIDs, models.PsirtCve{CveID: cveID})
+}
+
+func (b *psirtBuilder) addProduct(product, version string) {
+	p := models.PsirtProduct{Product: NormalizePsirtProduct(product), Version: strings.TrimSpace(version)}
+	if p.Product == "" {
+		return
+	}
+	if _, ok := b.products[p]; ok {
+		return
+	}
+	b.products[p] = struct{}{}
+	b.Products = append(b.Products, p)
+}
+
+// ConvertCiscoPsirt converts the advisories of Cisco openVuln API.
+// The version of the product is split from the tail of the product name (e.g. Cisco IOS XE Software 17.3.1).
+func ConvertCiscoPsirt(advs []models.CiscoAdvisoryJSON, warnings *util.Warnings) (advisories []models.PsirtAdvisory) {
+	for _, adv := range advs {
+		score, err := strconv.ParseFloat(adv.CvssBaseScore, 64)
+		if err != nil && adv.CvssBaseScore != "" && adv.CvssBaseScore != "NA" {
+			warnings.Add("cisco", adv.AdvisoryID, "cvssBaseScore", fmt.Sprintf("Failed to parse score: %s", adv.CvssBaseScore))
+		}
+		b := newPsirtBuilder(models.PsirtAdvisory{
+			Vendor:           models.PsirtCisco,
+			AdvisoryID:       adv.AdvisoryID,
+			Title:            strings.TrimSpace(adv.AdvisoryTitle),
+			Summary:          strings.TrimSpace(adv.Summary),
+			Severity:         adv.Sir,
+			CvssScore:        score,
+			URL:              adv.PublicationURL,
+			PublishedDate:    parsePsirtDate(models.PsirtCisco, adv.AdvisoryID, "firstPublished", adv.FirstPublished, warnings),
+			LastModifiedDate: parsePsirtDate(models.PsirtCisco, adv.AdvisoryID, "lastUpdated", adv.LastUpdated, warnings),
+		})
+		for _, cveID := range adv.Cves {
+			b.addCve(cveID)
+		}
+		for _, name := range adv.ProductNames {
+			if m := ciscoProductVersionRegexp.FindStringSubmatch(strings.TrimSpace(name)); m != nil {
+				b.addProduct(m[1], m[2])
+			} else {
+				b.addProduct(name, "")
+			}
+		}
+		advisories = append(advisories, b.PsirtAdvisory)
+	}
+	return advisories
+}
+
+// walkPsirtCSAFBranches calls fn with the product and the version of each product ID in the product tree
+func walkPsirtCSAFBranches(branches []models.PsirtCSAFBranchJSON, product, version string, fn func(productID, product, version string)) {
+	for _, b := range branches {
+		p, v := product, version
+		switch b.Category {
+		case "product_name":
+			p = b.Name
+		case "product_version", "product_version_range":
+			v = b.Name
+		}
+		if b.Product.ProductID != "" {
+			fn(b.Product.ProductID, p, v)
+		}
+		walkPsirtCSAFBranches(b.Branches, p, v, fn)
+	}
+}
+
+// ConvertVMwarePsirt converts VMSA in CSAF 2.0. The products are the ones known_affected by any vulnerability
+func ConvertVMwarePsirt(docs []models.PsirtCSAFJSON, warnings *util.Warnings) (advisories []models.PsirtAdvisory) {
+	for _, doc := range docs {
+		id := doc.Document.Tracking.ID
+		b := newPsirtBuilder(models.PsirtAdvisory{
+			Vendor:           models.PsirtVMware,
+			AdvisoryID:       id,
+			Title:            strings.TrimSpace(doc.Document.Title),
+			Severity:         doc.Document.AggregateSeverity.Text,
+			PublishedDate:    parsePsirtDate(models.PsirtVMware, id, "initial_release_date", doc.Document.Tracking.InitialReleaseDate, warnings),
+			LastModifiedDate: parsePsirtDate(models.PsirtVMware, id, "current_release_date", doc.Document.Tracking.CurrentReleaseDate, warnings),
+		})
+		for _, n := range doc.Document.Notes {
+			if n.Category == "summary" {
+				b.Summary = strings.TrimSpace(n.Text)
+			}
+		}
+		for _, ref := range doc.Document.References {
+			if b.URL == "" || ref.Category == "self" {
+				b.URL = ref.URL
+			}
+		}
+
+		type productVersion struct{ product, version string }
+		tree := map[string]productVersion{}
+		walkPsirtCSAFBranches(doc.ProductTree.Branches, "", "", func(productID, product, version string) {
+			tree[productID] = productVersion{product: product, version: version}
+		})
+		for _, v := range doc.Vulnerabilities {
+			b.addCve(v.Cve)
+			for _, s := range v.Scores {
+				if s.CvssV3.BaseScore > b.CvssScore {
+					b.CvssScore = s.CvssV3.BaseScore
+				}
+			}
+			for _, productID := range v.ProductStatus.KnownAffected {
+				if pv, ok := tree[productID]; ok {
+					b.addProduct(pv.product, pv.version)
+				}
+			}
+		}
+		advisories = append(advisories, b.PsirtAdvisory)
+	}
+	return advisories
+}
+
+// walkFortinetCVRFBranches calls fn with the product and the version of each product ID in the product tree
+func walkFortinetCVRFBranches(branches []models.FortinetCVRFBranchXML, product, version string, fn func(productID, product, version string)) {
+	for _, b := range branches {
+		p, v := product, version
+		switch b.Type {
+		case "Product Name":
+			p = b.Name
+		case "Product Version":
+			v = b.Name
+		}
+		if b.FullProductName.ProductID != "" {
+			fn(b.FullProductName.ProductID, p, v)
+		}
+		walkFortinetCVRFBranches(b.Branches, p, v, fn)
+	}
+}
+
+// ConvertFortinetPsirt converts the CVRF documents of Fortinet PSIRT. The products are the ones Known Affected by any vulnerability
+func ConvertFortinetPsirt(docs []models.FortinetCVRFXML, warnings *util.Warnings) (advisories []models.PsirtAdvisory) {
+	for _, doc := range docs {
+		id := strings.TrimSpace(doc.Tracking.ID)
+		b := newPsirtBuilder(models.PsirtAdvisory{
+			Vendor:           models.PsirtFortinet,
+			AdvisoryID:       id,
+			Title:            strings.TrimSpace(doc.Title),
+			URL:              "https://www.fortiguard.com/psirt/" + id,
+			PublishedDate:    parsePsirtDate(models.PsirtFortinet, id, "InitialReleaseDate", doc.Tracking.InitialReleaseDate, warnings),
+			LastModifiedDate: parsePsirtDate(models.PsirtFortinet, id, "CurrentReleaseDate", doc.Tracking.CurrentReleaseDate, warnings),
+		})
+
+		type productVersion struct{ product, version string }
+		tree := map[string]productVersion{}
+		walkFortinetCVRFBranches(doc.Branches, "", "", func(productID, product, version string) {
+			tree[productID] = productVersion{product: product, version: version}
+		})
+		for _, v := range doc.Vulnerabilities {
+			b.addCve(v.CVE)
+			if score, err := strconv.ParseFloat(strings.TrimSpace(v.BaseScore), 64); err == nil && score > b.CvssScore {
+				b.CvssScore = score
+			}
+			for _, s := range v.Statuses {
+				if s.Type != "Known Affected" {
+					continue
+				}
+				for _, productID := range s.ProductIDs {
+					if pv, ok := tree[strings.TrimSpace(productID)]; ok {
+						b.addProduct(pv.product, pv.version)
+					}
+				}
+			}
+		}
+		advisories = append(advisories, b.PsirtAdvisory)
+	}
+	return advisories
+}
+
+// parsePsirtDate parses the dates of the vendors (e.g. 2023-10-16T15:00:00, 2023-10-16T15:00:00Z, 2023-10-16T15:00:00+00:00)
+func parsePsirtDate(vendor, advisoryID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02")
+	if err != nil {
+		warnings.Add(vendor, advisoryID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// GetUnfixedCves gets the unfixed CVEs related to release, pkgName of the family.
+// The release is normalized in the same way as the server mode.
+// The release of a derivative distribution (e.g. linuxmint 21) is resolved to the upstream (e.g. ubuntu jammy).
+// The packages specific to the derivative distribution (e.g. raspberrypi-kernel of raspbian) return ErrNotCovered.
+// The channels of the extended support (e.g. esm-infra, elts) apply to Debian and Ubuntu.
+func GetUnfixedCves(driver DB, family, release, pkgName string, channels ...string) (interface{}, error) {
+	if IsDerivativePackage(family, pkgName) {
+		return nil, xerrors.Errorf("Failed to get unfixed CVEs. family: %s, package: %s, err: %w", family, pkgName, ErrNotCovered)
+	}
+	family, release, err := ResolveDerivative(family, release)
+	if err != nil {
+		return nil, err
+	}
+	switch family {
+	case "redhat":
+		return driver.GetUnfixedCvesRedhat(util.Major(release), pkgName, false), nil
+	case "debian":
+		return driver.GetUnfixedCvesDebian(NormalizeDebianRelease(release), pkgName, channels...), nil
+	case "ubuntu":
+		return driver.GetUnfixedCvesUbuntu(NormalizeUbuntuRelease(release), pkgName, channels...), nil
+	case "amazon":
+		return driver.GetUnfixedCvesAmazon(NormalizeAmazonRelease(release), pkgName), nil
+	case models.WolfiDistroWolfi, models.WolfiDistroChainguard:
+		return driver.GetUnfixedCvesWolfi(family, pkgName), nil
+	default:
+		return nil, xerrors.Errorf("Failed to get unfixed CVEs. family: %s, err: %w", family, ErrUnknownSource)
+	}
+}
+
+// GetUnfixedCvesMulti gets the unfixed CVEs of pkgNames of the release at once.
+// RedHat, Debian and Ubuntu are queried in a batch, the other families package by package.
+// The packages specific to the derivative distribution are listed in NotCovered instead of failing the whole query.
+func GetUnfixedCvesMulti(driver DB, family, release string, pkgNames []string, channels ...string) (*models.PackagesCves, error) {
+	result := models.PackagesCves{Family: family, Release: release, Packages: map[string]interface{}{}}
+	covered := []string{}
+	for _, name := range pkgNames {
+		if IsDerivativePackage(family, name) {
+			result.NotCovered = append(result.NotCovered, name)
+			continue
+		}
+		covered = append(covered, name)
+	}
+	family, release, err := ResolveDerivative(family, release)
+	if err != nil {
+		return nil, err
+	}
+
+	switch family {
+	case "redhat":
+		for name, m := range driver.GetUnfixedCvesRedhatMulti(util.Major(release), covered, false) {
+			result.Packages[name] = m
+		}
+	case "debian":
+		for name, m := range driver.GetUnfixedCvesDebianMulti(NormalizeDebianRelease(release), covered, channels...) {
+			result.Packages[name] = m
+		}
+	case "ubuntu":
+		for name, m := range driver.GetUnfixedCvesUbuntuMulti(NormalizeUbuntuRelease(release), covered, channels...) {
+			result.Packages[name] = m
+		}
+	case "amazon", models.WolfiDistroWolfi, models.WolfiDistroChainguard:
+		for _, name := range covered {
+			cves, err := GetUnfixedCves(driver, family, release, name)
+			if err != nil {
+				return nil, err
+			}
+			result.Packages[name] = cves
+		}
+	default:
+		return nil, xerrors.Errorf("Failed to get unfixed CVEs. family: %s, err: %w", family, ErrUnknownSource)
+	}
+	return &result, nil
+}
+
+// GetFixedCves gets the fixed CVEs related to release, pkgName of the family.
+// The fixed versions are returned as they are, so the caller compares them with the installed version.
+func GetFixedCves(driver DB, family, release, pkgName string, channels ...string) (interface{}, error) {
+	if IsDerivativePackage(family, pkgName) {
+		return nil, xerrors.Errorf("Failed to get fixed CVEs. family: %s, package: %s, err: %w", family, pkgName, ErrNotCovered)
+	}
+	family, release, err := ResolveDerivative(family, release)
+	if err != nil {
+		return nil, err
+	}
+	switch family {
+	case "redhat":
+		return driver.GetFixedCvesRedhat(util.Major(release), pkgName), nil
+	case "debian":
+		return driver.GetFixedCvesDebian(NormalizeDebianRelease(release), pkgName, channels...), nil
+	case "ubuntu":
+		return driver.GetFixedCvesUbuntu(NormalizeUbuntuRelease(release), pkgName, channels...), nil
+	case "amazon":
+		return driver.GetFixedCvesAmazon(NormalizeAmazonRelease(release), pkgName), nil
+	case "alpine":
+		return driver.GetFixedCvesAlpine(NormalizeAlpineRelease(release), pkgName), nil
+	case models.WolfiDistroWolfi, models.WolfiDistroChainguard:
+		return driver.GetFixedCvesWolfi(family, pkgName), nil
+	case "oracle":
+		return driver.GetFixedCvesOracle(NormalizeOracleRelease(release), pkgName), nil
+	case "rocky":
+		return driver.GetFixedCvesRocky(NormalizeRockyRelease(release), pkgName), nil
+	case "alma":
+		return driver.GetFixedCvesAlma(NormalizeAlmaRelease(release), pkgName), nil
+	case "photon":
+		return driver.GetFixedCvesPhoton(NormalizePhotonRelease(release), pkgName), nil
+	case "mariner":
+		return driver.GetFixedCvesMariner(NormalizeMarinerRelease(release), pkgName), nil
+	case "openeuler":
+		return driver.GetFixedCvesOpenEuler(NormalizeOpenEulerRelease(release), pkgName), nil
+	case "anolis":
+		return driver.GetFixedCvesAnolis(NormalizeAnolisRelease(release), pkgName), nil
+	default:
+		return nil, xerrors.Errorf("Failed to get fixed CVEs. family: %s, err: %w", family, ErrUnknownSource)
+	}
+}
+
+// GetRedhatMultiSorted gets the CVEs of cveIDs as a slice sorted by CVE-ID.
+// The CVE-IDs not in RedHat are omitted.
+func GetRedhatMultiSorted(driver DB, cveIDs []string) []models.RedhatCVE {
+	m := driver.GetRedhatMulti(cveIDs)
+	ids := make([]string, 0, len(m))
+	for cveID, c := range m {
+		if c.Name != "" {
+			ids = append(ids, cveID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return CveIDLess(ids[i], ids[j]) })
+
+	cves := make([]models.RedhatCVE, 0, len(ids))
+	for _, cveID := range ids {
+		cves = append(cves, m[cveID])
+	}
+	return cves
+}
+
+// GetUnfixedCvesSorted is GetUnfixedCves returning a slice sorted by by (SortByCveID or SortByDate)
+func GetUnfixedCvesSorted(driver DB, family, release, pkgName, by string) (interface{}, error) {
+	cves, err := GetUnfixedCves(driver, family, release, pkgName)
+	if err != nil {
+		return nil, err
+	}
+	return SortCVEs(cves, by)
+}
+
+// GetFixedCvesSorted is GetFixedCves returning a slice sorted by by (SortByCveID or SortByDate)
+func GetFixedCvesSorted(driver DB, family, release, pkgName, by string) (interface{}, error) {
+	cves, err := GetFixedCves(driver, family, release, pkgName)
+	if err != nil {
+		return nil, err
+	}
+	return SortCVEs(cves, by)
+}
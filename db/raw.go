@@ -0,0 +1,109 @@
+package db
+
+import (
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrUnknownSource is returned when the source of the raw document is not supported
+var ErrUnknownSource = xerrors.New("Unknown source")
+
+// rawSourceFields maps the source name in the admin API to the field of the Redis HASH
+var rawSourceFields = map[string]string{
+	"redhat":     "RedHat",
+	"debian":     "Debian",
+	"ubuntu":     "Ubuntu",
+	"amazon":     "Amazon",
+	"alpine":     "Alpine",
+	"wolfi":      "Wolfi",
+	"oracle":     "Oracle",
+	"rocky":      "Rocky",
+	"alma":       "Alma",
+	"photon":     "Photon",
+	"mariner":    "Mariner",
+	"openeuler":  "OpenEuler",
+	"anolis":     "Anolis",
+	"microsoft":  "Microsoft",
+	"nvd":        "NVD",
+	"cveprogram": "CVEProgram",
+	"kev":        "KEV",
+	"epss":       "EPSS",
+}
+
+// GetRaw returns the rows of the source stored for the cveID as they are.
+// RDB does not keep the JSON document, so the rows are marshaled without filtering by release or package.
+// If there is no record, it returns nil.
+func (r *RDBDriver) GetRaw(source, cveID string) ([]byte, error) {
+	var (
+		found bool
+		v     interface{}
+	)
+	switch source {
+	case "redhat":
+		c := r.GetRedhat(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "debian":
+		c := r.GetDebian(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "ubuntu":
+		c := r.GetUbuntu(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "amazon":
+		c := r.GetAmazon(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "alpine":
+		c := r.GetAlpine(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "wolfi":
+		c := r.GetWolfi(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "oracle":
+		c := r.GetOracle(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "rocky":
+		c := r.GetRocky(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "alma":
+		c := r.GetAlma(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "photon":
+		c := r.GetPhoton(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "mariner":
+		c := r.GetMariner(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "openeuler":
+		c := r.GetOpenEuler(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "anolis":
+		c := r.GetAnolis(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "microsoft":
+		c := r.GetMicrosoft(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "nvd":
+		c := r.GetNvd(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "cveprogram":
+		c := r.GetCveProgram(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "kev":
+		c := r.GetKEV(cveID)
+		found, v = c != nil && c.ID != 0, c
+	case "epss":
+		c := r.GetEpss(cveID)
+		found, v = c != nil && c.ID != 0, c
+	default:
+		return nil, xerrors.Errorf("Failed to get raw document. source: %s, err: %w", source, ErrUnknownSource)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to marshal json. err: %w", err)
+	}
+	return b, nil
+}
@@ -8,8 +8,10 @@ import (
 	"os"
 	"time"
 
+	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/config"
 	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
 	sqlite3 "github.com/mattn/go-sqlite3"
 	"golang.org/x/xerrors"
 	"gorm.io/gorm"
@@ -32,9 +34,19 @@ const (
 
 // RDBDriver is Driver for RDB
 type RDBDriver struct {
-	name      string
-	conn      *gorm.DB
-	batchSize int
+	name    string
+	conn    *gorm.DB
+	insert  InsertOptions
+	filter  Filter
+	explain *Explain
+
+	queryTimeout       time.Duration
+	slowQueryThreshold time.Duration
+	breaker            *CircuitBreaker
+	sqliteWAL          bool
+	warnings           *util.Warnings
+	// log is the logger of the request (e.g. with request_id), or the root logger
+	log log15.Logger
 }
 
 // Name return db name
@@ -42,6 +54,29 @@ func (r *RDBDriver) Name() string {
 	return r.name
 }
 
+// WithExplain returns a copy of the driver which records the SQL statements into e
+func (r *RDBDriver) WithExplain(e *Explain) DB {
+	d := *r
+	d.conn = r.conn.Session(&gorm.Session{Logger: &explainLogger{explain: e}})
+	d.explain = e
+	return &d
+}
+
+// WithLogger returns a copy of the driver which logs by l, including the slow queries
+func (r *RDBDriver) WithLogger(l log15.Logger) DB {
+	d := *r
+	d.conn = r.conn.WithContext(withLogger(r.conn.Statement.Context, l))
+	d.log = l
+	return &d
+}
+
+// WithInsertOptions returns a copy of the driver which inserts with o
+func (r *RDBDriver) WithInsertOptions(o InsertOptions) DB {
+	d := *r
+	d.insert = o.normalize()
+	return &d
+}
+
 // OpenDB opens Database
 func (r *RDBDriver) OpenDB(dbType, dbPath string, debugSQL bool) (locked bool, err error) {
 	gormConfig := gorm.Config{
@@ -62,6 +97,9 @@ func (r *RDBDriver) OpenDB(dbType, dbPath string, debugSQL bool) (locked bool, e
 
 	switch r.name {
 	case dialectSqlite3:
+		if r.sqliteWAL {
+			dbPath = sqliteWALDSN(dbPath)
+		}
 		r.conn, err = gorm.Open(sqlite.Open(dbPath), &gormConfig)
 	case dialectMysql:
 		r.conn, err = gorm.Open(mysql.Open(dbPath), &gormConfig)
@@ -85,6 +123,21 @@ func (r *RDBDriver) OpenDB(dbType, dbPath string, debugSQL bool) (locked bool, e
 	if r.name == dialectSqlite3 {
 		r.conn.Exec("PRAGMA foreign_keys = ON")
 	}
+	if r.queryTimeout > 0 {
+		if err := registerQueryTimeout(r.conn, r.queryTimeout); err != nil {
+			return false, err
+		}
+	}
+	if r.slowQueryThreshold > 0 {
+		if err := registerSlowQueryLog(r.conn, r.slowQueryThreshold); err != nil {
+			return false, err
+		}
+	}
+	if r.breaker != nil {
+		if err := registerCircuitBreaker(r.conn, r.breaker); err != nil {
+			return false, err
+		}
+	}
 	return false, nil
 }
 
@@ -108,6 +161,7 @@ func (r *RDBDriver) CloseDB() (err error) {
 func (r *RDBDriver) MigrateDB() error {
 	if err := r.conn.AutoMigrate(
 		&models.FetchMeta{},
+		&models.FetchSource{},
 
 		&models.RedhatCVE{},
 		&models.RedhatDetail{},
@@ -117,10 +171,13 @@ func (r *RDBDriver) MigrateDB() error {
 		&models.RedhatCvss3{},
 		&models.RedhatAffectedRelease{},
 		&models.RedhatPackageState{},
+		&models.RedhatFixedPackage{},
+		&models.RedhatModuleState{},
 
 		&models.DebianCVE{},
 		&models.DebianPackage{},
 		&models.DebianRelease{},
+		&models.DebianAdvisory{},
 
 		&models.UbuntuCVE{},
 		&models.UbuntuReference{},
@@ -128,9 +185,43 @@ func (r *RDBDriver) MigrateDB() error {
 		&models.UbuntuBug{},
 		&models.UbuntuPatch{},
 		&models.UbuntuReleasePatch{},
+		&models.UbuntuSnapPatch{},
 		&models.UbuntuUpstream{},
 		&models.UbuntuUpstreamLink{},
+		&models.UbuntuUSN{},
 
+		&models.AmazonCVE{},
+		&models.AmazonAdvisory{},
+		&models.AmazonPackage{},
+		&models.OracleCVE{},
+		&models.OracleAdvisory{},
+		&models.OraclePackage{},
+		&models.RockyCVE{},
+		&models.RockyAdvisory{},
+		&models.RockyPackage{},
+		&models.AlmaCVE{},
+		&models.AlmaAdvisory{},
+		&models.AlmaPackage{},
+
+		&models.AlpineCVE{},
+		&models.AlpinePackage{},
+		&models.WolfiCVE{},
+		&models.WolfiPackage{},
+
+		&models.PhotonCVE{},
+		&models.PhotonPackage{},
+		&models.MarinerCVE{},
+		&models.MarinerPackage{},
+		&models.OpenEulerCVE{},
+		&models.OpenEulerAdvisory{},
+		&models.OpenEulerPackage{},
+		&models.AnolisCVE{},
+		&models.AnolisAdvisory{},
+		&models.AnolisPackage{},
+
+		&models.MicrosoftCvrfDocument{},
+		&models.MicrosoftKBSupersedence{},
+		&models.MicrosoftKBBuild{},
 		&models.MicrosoftCVE{},
 		&models.MicrosoftProductStatus{},
 		&models.MicrosoftThreat{},
@@ -139,6 +230,49 @@ func (r *RDBDriver) MigrateDB() error {
 		&models.MicrosoftScoreSet{},
 		&models.MicrosoftProduct{},
 		&models.MicrosoftKBID{},
+
+		&models.NvdCVE{},
+		&models.NvdCvss{},
+		&models.NvdCwe{},
+		&models.NvdCpe{},
+		&models.NvdReference{},
+		&models.CveProgramCVE{},
+		&models.CveProgramSync{},
+		&models.KernelCVE{},
+		&models.KernelCveCommit{},
+		&models.KernelCveVersion{},
+		&models.DistroRelease{},
+		&models.KEVEntry{},
+		&models.EpssScore{},
+		&models.Exploit{},
+		&models.Cwe{},
+		&models.GhsaAdvisory{},
+		&models.GhsaIdentifier{},
+		&models.GhsaReference{},
+		&models.GhsaCwe{},
+		&models.GhsaVulnerability{},
+		&models.OsvEntry{},
+		&models.OsvPackage{},
+		&models.GoVuln{},
+		&models.GoVulnAlias{},
+		&models.GoVulnRange{},
+		&models.GoVulnSymbol{},
+		&models.JvnAdvisory{},
+		&models.JvnCve{},
+		&models.JvnVendor{},
+		&models.JvnReference{},
+		&models.PsirtAdvisory{},
+		&models.PsirtCve{},
+		&models.PsirtProduct{},
+		&models.AppleSecurityRelease{},
+		&models.AppleCve{},
+		&models.AppleProduct{},
+		&models.CnAdvisory{},
+		&models.CnCve{},
+		&models.CnProduct{},
+		&models.PackageAdvisory{},
+		&models.PackageAdvisoryCve{},
+		&models.PackageAdvisoryVulnerability{},
 	); err != nil {
 		return xerrors.Errorf("Failed to migrate. err: %w", err)
 	}
@@ -190,6 +324,36 @@ func (r *RDBDriver) UpsertFetchMeta(fetchMeta *models.FetchMeta) error {
 	return r.conn.Save(fetchMeta).Error
 }
 
+// GetFetchSources returns the last fetch time of each source fetched
+func (r *RDBDriver) GetFetchSources() ([]models.FetchSource, error) {
+	sources := []models.FetchSource{}
+	if err := r.conn.Order("source").Find(&sources).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get fetch sources. err: %w", err)
+	}
+	return sources, nil
+}
+
+// UpsertFetchSource records the last fetch time and the license of the source
+func (r *RDBDriver) UpsertFetchSource(fetched models.FetchSource) (err error) {
+	tx := r.conn.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	if err = tx.Where("source = ?", fetched.Source).Delete(models.FetchSource{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old fetch source. err: %w", err)
+	}
+	fetched.ID, fetched.FetchedAt = 0, fetched.FetchedAt.UTC()
+	if err = tx.Create(&fetched).Error; err != nil {
+		return xerrors.Errorf("Failed to insert fetch source. err: %w", err)
+	}
+	return nil
+}
+
 // IndexChunk has a starting point and an ending point for Chunk
 type IndexChunk struct {
 	From, To int
@@ -212,3 +376,18 @@ func chunkSlice(length int, chunkSize int) <-chan IndexChunk {
 
 	return ch
 }
+
+// Reindex is not needed on RDB, where the indexes are maintained by the database
+func (r *RDBDriver) Reindex() (models.ReindexReport, error) {
+	return models.ReindexReport{}, xerrors.Errorf("Reindex is supported only on redis, not on %s", r.name)
+}
+
+// RemoveMissing is not needed on RDB, where the insert replaces all the records of the source
+func (r *RDBDriver) RemoveMissing(*SeenIDs, time.Time) (map[string][]string, error) {
+	return nil, xerrors.Errorf("RemoveMissing is supported only on redis, not on %s", r.name)
+}
+
+// GetTombstones returns no tombstone on RDB, where nothing is removed by RemoveMissing
+func (r *RDBDriver) GetTombstones(time.Time) ([]models.Tombstone, error) {
+	return []models.Tombstone{}, nil
+}
@@ -3,20 +3,18 @@ package db
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"regexp"
 	"time"
 
-	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/models"
 	"github.com/knqyf263/gost/util"
-	pb "gopkg.in/cheggaaa/pb.v1"
 	"gorm.io/gorm"
 )
 
 // GetAfterTimeRedhat :
 func (r *RDBDriver) GetAfterTimeRedhat(after time.Time) (allCves []models.RedhatCVE, err error) {
 	all := []models.RedhatCVE{}
-	if err = r.conn.Where("public_date >= ?", after.Format("2006-01-02")).Find(&all).Error; err != nil {
+	if err = r.conn.Where("public_date >= ?", after.Format("2006-01-02")).Order("public_date").Order("name").Find(&all).Error; err != nil {
 		return nil, err
 	}
 
@@ -48,9 +46,10 @@ func (r *RDBDriver) GetRedhat(cveID string) *models.RedhatCVE {
 	errs = errs.Add(r.conn.Model(&c).Association("Cvss3").Find(&c.Cvss3))
 	errs = errs.Add(r.conn.Model(&c).Association("AffectedRelease").Find(&c.AffectedRelease))
 	errs = errs.Add(r.conn.Model(&c).Association("PackageState").Find(&c.PackageState))
+	errs = errs.Add(r.conn.Model(&c).Association("FixedPackages").Find(&c.FixedPackages))
 	errs = util.DeleteRecordNotFound(errs)
 	if len(errs.GetErrors()) > 0 {
-		log15.Error("Failed to get RedhatCVE", "err", errs.Error())
+		r.log.Error("Failed to get RedhatCVE", "err", errs.Error())
 	}
 	return &c
 }
@@ -64,6 +63,32 @@ func (r *RDBDriver) GetRedhatMulti(cveIDs []string) map[string]models.RedhatCVE
 	return m
 }
 
+// GetRedhatByBugzillaID gets the CVEs related to the Bugzilla ID
+func (r *RDBDriver) GetRedhatByBugzillaID(bugzillaID string) map[string]models.RedhatCVE {
+	m := map[string]models.RedhatCVE{}
+	bugzillas := []models.RedhatBugzilla{}
+	err := r.conn.Where(&models.RedhatBugzilla{BugzillaID: bugzillaID}).Find(&bugzillas).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Redhat by Bugzilla ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(bugzillas))
+	for _, b := range bugzillas {
+		c := models.RedhatCVE{}
+		err := r.conn.Select("name").Where(&models.RedhatCVE{ID: b.RedhatCVEID}).First(&c).Error
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				r.log.Error("Failed to get Redhat by Bugzilla ID", "err", err)
+				return nil
+			}
+			continue
+		}
+		m[c.Name] = *r.GetRedhat(c.Name)
+	}
+	return m
+}
+
 // GetUnfixedCvesRedhat gets the unfixed CVEs.
 func (r *RDBDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix bool) map[string]models.RedhatCVE {
 	m := map[string]models.RedhatCVE{}
@@ -78,7 +103,7 @@ func (r *RDBDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix
 			PackageName: pkgName,
 		}).Find(&pkgStats).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log15.Error("Failed to get unfixed cves of Redhat", "err", err)
+		r.log.Error("Failed to get unfixed cves of Redhat", "err", err)
 		return nil
 	}
 
@@ -87,6 +112,7 @@ func (r *RDBDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix
 		redhatCVEIDs[p.RedhatCVEID] = true
 	}
 
+	r.explain.addCandidates(len(redhatCVEIDs))
 	for id := range redhatCVEIDs {
 		rhcve := models.RedhatCVE{}
 		err = r.conn.
@@ -95,11 +121,12 @@ func (r *RDBDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix
 			Preload("Cvss3").
 			Preload("AffectedRelease").
 			Preload("PackageState").
+			Preload("FixedPackages").
 			Preload("Details").
 			Preload("References").
 			Where(&models.RedhatCVE{ID: id}).First(&rhcve).Error
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			log15.Error("Failed to get unfixed cves of Redhat", "err", err)
+			r.log.Error("Failed to get unfixed cves of Redhat", "err", err)
 			return nil
 		}
 
@@ -122,15 +149,151 @@ func (r *RDBDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix
 		rhcve.PackageState = pkgStats
 		m[rhcve.Name] = rhcve
 	}
+	applyRedhatModuleStates(r, m, major, pkgName, ignoreWillNotFix)
 	return m
 }
 
+// GetUnfixedCvesRedhatMulti gets the unfixed CVEs of pkgNames by a query of the package states and a query of the CVEs
+func (r *RDBDriver) GetUnfixedCvesRedhatMulti(major string, pkgNames []string, ignoreWillNotFix bool) map[string]map[string]models.RedhatCVE {
+	cpe := fmt.Sprintf("cpe:/o:redhat:enterprise_linux:%s", major)
+	ids := []int64{}
+	uniq := map[int64]struct{}{}
+	// split IN clause to stay under the limit of the bind variables
+	for idx := range chunkSlice(len(pkgNames), 500) {
+		pkgStats := []models.RedhatPackageState{}
+		if err := r.conn.
+			Not(map[string]interface{}{"fix_state": []string{"Not affected", "New"}}).
+			Where("cpe = ? AND package_name IN ?", cpe, pkgNames[idx.From:idx.To]).
+			Find(&pkgStats).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get unfixed cves of Redhat", "err", err)
+			return nil
+		}
+		for _, p := range pkgStats {
+			if _, ok := uniq[p.RedhatCVEID]; !ok {
+				uniq[p.RedhatCVEID] = struct{}{}
+				ids = append(ids, p.RedhatCVEID)
+			}
+		}
+	}
+
+	r.explain.addCandidates(len(ids))
+	cves := []models.RedhatCVE{}
+	for idx := range chunkSlice(len(ids), 500) {
+		chunk := []models.RedhatCVE{}
+		if err := r.conn.
+			Preload("Bugzilla").
+			Preload("Cvss").
+			Preload("Cvss3").
+			Preload("AffectedRelease").
+			Preload("PackageState").
+			Preload("FixedPackages").
+			Preload("Details").
+			Preload("References").
+			Where("id IN ?", ids[idx.From:idx.To]).Find(&chunk).Error; err != nil {
+			r.log.Error("Failed to get unfixed cves of Redhat", "err", err)
+			return nil
+		}
+		cves = append(cves, chunk...)
+	}
+
+	results := map[string]map[string]models.RedhatCVE{}
+	for _, pkgName := range pkgNames {
+		m := map[string]models.RedhatCVE{}
+		for _, c := range cves {
+			if c.PackageState = unfixedRedhatPackageStates(c.PackageState, cpe, pkgName, ignoreWillNotFix); len(c.PackageState) != 0 {
+				m[c.Name] = c
+			}
+		}
+		applyRedhatModuleStates(r, m, major, pkgName, ignoreWillNotFix)
+		results[pkgName] = m
+	}
+	return results
+}
+
+// unfixedRedhatPackageStates returns the states of pkgName in the release of cpe which are not fixed
+// https://access.redhat.com/documentation/en-us/red_hat_security_data_api/0.1/html-single/red_hat_security_data_api/index#cve_format
+func unfixedRedhatPackageStates(states []models.RedhatPackageState, cpe, pkgName string, ignoreWillNotFix bool) []models.RedhatPackageState {
+	unfixed := []models.RedhatPackageState{}
+	for _, s := range states {
+		if s.Cpe != cpe || s.PackageName != pkgName || s.FixState == "Not affected" || s.FixState == "New" {
+			continue
+		}
+		if ignoreWillNotFix && s.FixState == "Will not fix" {
+			continue
+		}
+		unfixed = append(unfixed, s)
+	}
+	return unfixed
+}
+
+// GetFixedCvesRedhat gets the CVEs fixed in the package of the major release, with the fixed packages of the major release only
+func (r *RDBDriver) GetFixedCvesRedhat(major, pkgName string) map[string]models.RedhatCVE {
+	m := map[string]models.RedhatCVE{}
+	fixed := []models.RedhatFixedPackage{}
+	if err := r.conn.Where(&models.RedhatFixedPackage{Name: pkgName}).Find(&fixed).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Redhat", "err", err)
+		return nil
+	}
+
+	redhatCVEIDs := map[int64]bool{}
+	for _, p := range fixed {
+		if redhatCpeMajor(p.Cpe) == major {
+			redhatCVEIDs[p.RedhatCVEID] = true
+		}
+	}
+
+	r.explain.addCandidates(len(redhatCVEIDs))
+	for id := range redhatCVEIDs {
+		rhcve := models.RedhatCVE{}
+		err := r.conn.
+			Preload("Bugzilla").
+			Preload("Cvss").
+			Preload("Cvss3").
+			Preload("AffectedRelease").
+			Preload("PackageState").
+			Preload("FixedPackages").
+			Preload("Details").
+			Preload("References").
+			Where(&models.RedhatCVE{ID: id}).First(&rhcve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get fixed cves of Redhat", "err", err)
+			return nil
+		}
+		if rhcve.FixedPackages = filterRedhatFixedPackages(rhcve.FixedPackages, major, pkgName); len(rhcve.FixedPackages) > 0 {
+			m[rhcve.Name] = rhcve
+		}
+	}
+	return m
+}
+
+// redhatCpeMajor returns the major release of the CPE of RHEL (e.g. cpe:/a:redhat:enterprise_linux:9::appstream -> 9).
+// It is empty for the other products, e.g. EUS (cpe:/a:redhat:rhel_eus:9.2::appstream)
+func redhatCpeMajor(cpe string) string {
+	if m := redhatRHELCpeRegexp.FindStringSubmatch(cpe); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+var redhatRHELCpeRegexp = regexp.MustCompile(`^cpe:/[ao]:redhat:enterprise_linux:(\d+)`)
+
+func filterRedhatFixedPackages(fixed []models.RedhatFixedPackage, major, pkgName string) []models.RedhatFixedPackage {
+	filtered := []models.RedhatFixedPackage{}
+	for _, p := range fixed {
+		if p.Name == pkgName && redhatCpeMajor(p.Cpe) == major {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
 // InsertRedhat :
 func (r *RDBDriver) InsertRedhat(cveJSONs []models.RedhatCVEJSON) (err error) {
 	cves, err := ConvertRedhat(cveJSONs)
 	if err != nil {
 		return err
 	}
+	cves = r.filter.filterRedhat(cves)
 
 	if err := r.deleteAndInsertRedhat(r.conn, cves); err != nil {
 		return fmt.Errorf("Failed to insert RedHat CVE data. err: %s", err)
@@ -140,9 +303,9 @@ func (r *RDBDriver) InsertRedhat(cveJSONs []models.RedhatCVEJSON) (err error) {
 }
 
 func (r *RDBDriver) deleteAndInsertRedhat(conn *gorm.DB, cves []models.RedhatCVE) (err error) {
-	log15.Info(fmt.Sprintf("Insert %d CVEs", len(cves)))
+	r.log.Info(fmt.Sprintf("Insert %d CVEs", len(cves)))
 
-	bar := pb.StartNew(len(cves))
+	bar := startProgress(r.insert.Progress, len(cves))
 	tx := conn.Begin()
 	defer func() {
 		if err != nil {
@@ -160,6 +323,7 @@ func (r *RDBDriver) deleteAndInsertRedhat(conn *gorm.DB, cves []models.RedhatCVE
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RedhatCvss3{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RedhatAffectedRelease{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RedhatPackageState{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RedhatFixedPackage{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RedhatCVE{}).Error)
 	errs = util.DeleteNil(errs)
 
@@ -167,7 +331,7 @@ func (r *RDBDriver) deleteAndInsertRedhat(conn *gorm.DB, cves []models.RedhatCVE
 		return fmt.Errorf("Failed to delete old records. err: %s", errs.Error())
 	}
 
-	for idx := range chunkSlice(len(cves), r.batchSize) {
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
 		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
 			return fmt.Errorf("Failed to insert. err: %s", err)
 		}
@@ -178,6 +342,69 @@ func (r *RDBDriver) deleteAndInsertRedhat(conn *gorm.DB, cves []models.RedhatCVE
 	return nil
 }
 
+// UpsertRedhat replaces only the given CVEs and leaves the others as they are
+func (r *RDBDriver) UpsertRedhat(cveJSONs []models.RedhatCVEJSON) (err error) {
+	cves, err := ConvertRedhat(cveJSONs)
+	if err != nil {
+		return err
+	}
+	cves = r.filter.filterRedhat(cves)
+
+	if err := r.deleteAndUpsertRedhat(r.conn, cves); err != nil {
+		return fmt.Errorf("Failed to upsert RedHat CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndUpsertRedhat(conn *gorm.DB, cves []models.RedhatCVE) (err error) {
+	if len(cves) == 0 {
+		return nil
+	}
+
+	tx := conn.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	names := []string{}
+	for _, c := range cves {
+		names = append(names, c.Name)
+	}
+	ids := []int64{}
+	if err = tx.Model(&models.RedhatCVE{}).Where("name IN ?", names).Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("Failed to get old records. err: %s", err)
+	}
+
+	// Delete old records of the given CVEs
+	if len(ids) > 0 {
+		var errs util.Errors
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatDetail{}).Error)
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatReference{}).Error)
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatBugzilla{}).Error)
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatCvss{}).Error)
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatCvss3{}).Error)
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatAffectedRelease{}).Error)
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatPackageState{}).Error)
+		errs = errs.Add(tx.Where("redhat_cve_id IN ?", ids).Delete(models.RedhatFixedPackage{}).Error)
+		errs = errs.Add(tx.Where("id IN ?", ids).Delete(models.RedhatCVE{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return fmt.Errorf("Failed to delete old records. err: %s", errs.Error())
+		}
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return fmt.Errorf("Failed to insert. err: %s", err)
+		}
+	}
+	return nil
+}
+
 // ConvertRedhat :
 func ConvertRedhat(cveJSONs []models.RedhatCVEJSON) (cves []models.RedhatCVE, err error) {
 	for _, cve := range cveJSONs {
@@ -198,12 +425,8 @@ func ConvertRedhat(cveJSONs []models.RedhatCVEJSON) (cves []models.RedhatCVE, er
 
 		var publicDate time.Time
 		if cve.PublicDate != "" {
-			if strings.HasSuffix(cve.PublicDate, "Z") {
-				publicDate, err = time.Parse("2006-01-02T15:04:05Z", cve.PublicDate)
-			} else {
-				publicDate, err = time.Parse("2006-01-02T15:04:05", cve.PublicDate)
-			}
-			if err != nil {
+			// e.g. 2021-03-25T00:00:00Z, 2021-03-25T00:00:00+00:00, 2021-03-25T00:00:00
+			if publicDate, err = util.ParseDate(cve.PublicDate, time.RFC3339, "2006-01-02T15:04:05"); err != nil {
 				return nil, fmt.Errorf("Failed to parse date. date: %s err: %s", cve.PublicDate, err)
 			}
 		}
@@ -222,6 +445,7 @@ func ConvertRedhat(cveJSONs []models.RedhatCVEJSON) (cves []models.RedhatCVE, er
 			Mitigation:           cve.Mitigation,
 			AffectedRelease:      cve.AffectedRelease,
 			PackageState:         cve.PackageState,
+			FixedPackages:        convertRedhatFixedPackages(cve),
 			Name:                 cve.Name,
 			DocumentDistribution: cve.DocumentDistribution,
 
@@ -233,6 +457,25 @@ func ConvertRedhat(cveJSONs []models.RedhatCVEJSON) (cves []models.RedhatCVE, er
 	return cves, nil
 }
 
+// convertRedhatFixedPackages returns the fixed packages of CSAF, or the ones parsed from affected_release of Security Data API,
+// which gives the source package of the advisory per product
+func convertRedhatFixedPackages(cve models.RedhatCVEJSON) []models.RedhatFixedPackage {
+	if len(cve.FixedPackages) > 0 {
+		return cve.FixedPackages
+	}
+	fixed := []models.RedhatFixedPackage{}
+	for _, a := range cve.AffectedRelease {
+		if a.Cpe == "" {
+			continue
+		}
+		if fp, ok := util.ParseRedhatNEVRA(a.Package); ok {
+			fp.Advisory, fp.Cpe = a.Advisory, a.Cpe
+			fixed = append(fixed, fp)
+		}
+	}
+	return fixed
+}
+
 // ClearIDRedhat :
 func ClearIDRedhat(cve *models.RedhatCVE) {
 	cve.ID = 0
@@ -254,6 +497,13 @@ func ClearIDRedhat(cve *models.RedhatCVE) {
 		cve.PackageState = append(cve.PackageState, p)
 	}
 
+	fixedPackages := cve.FixedPackages
+	cve.FixedPackages = []models.RedhatFixedPackage{}
+	for _, p := range fixedPackages {
+		p.RedhatCVEID = 0
+		cve.FixedPackages = append(cve.FixedPackages, p)
+	}
+
 	details := cve.Details
 	cve.Details = []models.RedhatDetail{}
 	for _, d := range details {
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+var (
+	// e.g. "Module nodejs:14 is enabled"
+	redhatOVALModuleRegexp = regexp.MustCompile(`^Module (\S+) is enabled$`)
+	// e.g. "nodejs is earlier than 1:14.21.3-1.module+el8.7.0+18531+81f0d2d1"
+	redhatOVALEarlierRegexp = regexp.MustCompile(`^(\S+) is earlier than (\S+)$`)
+	redhatOVALCveIDRegexp   = regexp.MustCompile(`^CVE-\d+-\d+`)
+)
+
+// splitRedhatModulePackage splits the package of a module stream (e.g. nodejs:14/nodejs) into the stream and the package.
+// The module is empty if it is not a package of a module stream.
+func splitRedhatModulePackage(pkgName string) (module, name string) {
+	if i := strings.Index(pkgName, "/"); i > 0 && strings.Contains(pkgName[:i], ":") {
+		return pkgName[:i], pkgName[i+1:]
+	}
+	return "", pkgName
+}
+
+// redhatOVALCveIDs returns the CVE-IDs of the definition
+func redhatOVALCveIDs(def models.RedhatOVALDefinitionXML) []string {
+	cveIDs := []string{}
+	for _, cveID := range def.Metadata.Advisory.Cves {
+		if cveID = strings.TrimSpace(cveID); cveID != "" {
+			cveIDs = append(cveIDs, cveID)
+		}
+	}
+	if len(cveIDs) > 0 {
+		return cveIDs
+	}
+	for _, ref := range def.Metadata.References {
+		if ref.Source == "CVE" {
+			cveIDs = append(cveIDs, ref.RefID)
+		}
+	}
+	if len(cveIDs) == 0 {
+		if cveID := redhatOVALCveIDRegexp.FindString(def.Metadata.Title); cveID != "" {
+			cveIDs = append(cveIDs, cveID)
+		}
+	}
+	return cveIDs
+}
+
+// walkRedhatOVALModuleCriteria calls fn with the packages fixed in the module streams.
+// The module stream enabled in a criteria applies to the criterions under it.
+func walkRedhatOVALModuleCriteria(criteria models.RedhatOVALCriteriaXML, module string, fn func(module, pkgName, version string)) {
+	for _, c := range criteria.Criterions {
+		if m := redhatOVALModuleRegexp.FindStringSubmatch(c.Comment); m != nil {
+			module = m[1]
+		}
+	}
+	for _, c := range criteria.Criterions {
+		if m := redhatOVALEarlierRegexp.FindStringSubmatch(c.Comment); m != nil && module != "" {
+			fn(module, m[1], m[2])
+		}
+	}
+	for _, c := range criteria.Criterias {
+		walkRedhatOVALModuleCriteria(c, module, fn)
+	}
+}
+
+// ConvertRedhatOVAL returns the fix states of the packages of the module streams in OVAL v2.
+// The patch definitions (RHSA) give the fixed versions, and the vulnerability definitions the states of the unpatched CVEs.
+// If both have a package of a stream, the fix wins.
+func ConvertRedhatOVAL(ovals []models.RedhatOVALXML) []models.RedhatModuleState {
+	uniq := map[string]models.RedhatModuleState{}
+	add := func(s models.RedhatModuleState) {
+		key := fmt.Sprintf("%s#%s#%s#%s", s.CveID, s.Major, s.Module, s.PackageName)
+		if old, ok := uniq[key]; ok && old.FixState == models.RedhatModuleStateFixed {
+			return
+		}
+		uniq[key] = s
+	}
+
+	for _, oval := range ovals {
+		for _, def := range oval.Definitions {
+			cveIDs := redhatOVALCveIDs(def)
+			switch def.Class {
+			case "patch":
+				advisory := ""
+				for _, ref := range def.Metadata.References {
+					if ref.Source == "RHSA" {
+						advisory = ref.RefID
+					}
+				}
+				walkRedhatOVALModuleCriteria(def.Criteria, "", func(module, pkgName, version string) {
+					for _, cveID := range cveIDs {
+						add(models.RedhatModuleState{CveID: cveID, Major: oval.Major, Module: module, PackageName: pkgName,
+							FixState: models.RedhatModuleStateFixed, FixedVersion: version, Advisory: advisory})
+					}
+				})
+			case "vulnerability":
+				for _, r := range def.Metadata.Advisory.Affected.Resolutions {
+					for _, component := range r.Components {
+						module, pkgName := splitRedhatModulePackage(component)
+						if module == "" {
+							continue
+						}
+						for _, cveID := range cveIDs {
+							add(models.RedhatModuleState{CveID: cveID, Major: oval.Major, Module: module, PackageName: pkgName, FixState: r.State})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	states := make([]models.RedhatModuleState, 0, len(uniq))
+	for _, s := range uniq {
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].CveID != states[j].CveID {
+			return states[i].CveID < states[j].CveID
+		}
+		return fmt.Sprintf("%s#%s#%s", states[i].Major, states[i].Module, states[i].PackageName) <
+			fmt.Sprintf("%s#%s#%s", states[j].Major, states[j].Module, states[j].PackageName)
+	})
+	return states
+}
+
+// redhatModuleStates is the query of the states of the module streams implemented by each driver
+type redhatModuleStates interface {
+	getRedhatModuleStates(major, module, pkgName string) ([]models.RedhatModuleState, error)
+	GetRedhat(cveID string) *models.RedhatCVE
+}
+
+// applyRedhatModuleStates corrects the unfixed CVEs m of the package of a module stream (e.g. nodejs:14/nodejs) by OVAL v2.
+// The CVEs fixed in the stream are removed even if package_state has the package, and the CVEs unpatched in the stream are added.
+// m is left as it is for the packages not of the module streams or without OVAL v2.
+func applyRedhatModuleStates(g redhatModuleStates, m map[string]models.RedhatCVE, major, pkgName string, ignoreWillNotFix bool) {
+	module, name := splitRedhatModulePackage(pkgName)
+	if module == "" {
+		return
+	}
+	states, err := g.getRedhatModuleStates(major, module, name)
+	if err != nil {
+		log15.Error("Failed to get the states of the module stream", "module", module, "err", err)
+		return
+	}
+
+	cpe := fmt.Sprintf("cpe:/o:redhat:enterprise_linux:%s", major)
+	for _, s := range states {
+		switch {
+		case s.FixState == models.RedhatModuleStateFixed, s.FixState == "Not affected",
+			ignoreWillNotFix && s.FixState == "Will not fix":
+			delete(m, s.CveID)
+			continue
+		}
+		if _, ok := m[s.CveID]; ok {
+			continue
+		}
+		cve := g.GetRedhat(s.CveID)
+		if cve == nil || cve.Name == "" {
+			continue
+		}
+		cve.PackageState = []models.RedhatPackageState{{
+			ProductName: fmt.Sprintf("Red Hat Enterprise Linux %s", major),
+			FixState:    s.FixState,
+			PackageName: pkgName,
+			Cpe:         cpe,
+		}}
+		m[s.CveID] = *cve
+	}
+}
+
+// InsertRedhatOVAL :
+func (r *RDBDriver) InsertRedhatOVAL(ovals []models.RedhatOVALXML) (err error) {
+	majors := []string{}
+	for _, oval := range ovals {
+		majors = append(majors, oval.Major)
+	}
+	if err = r.deleteAndInsertRedhatModuleStates(r.conn, majors, ConvertRedhatOVAL(ovals)); err != nil {
+		return xerrors.Errorf("Failed to insert Red Hat OVAL data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertRedhatModuleStates(conn *gorm.DB, majors []string, states []models.RedhatModuleState) (err error) {
+	bar := startProgress(r.insert.Progress, len(states))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete the old records of the majors fetched
+	if err := tx.Where("major IN ?", majors).Delete(models.RedhatModuleState{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old records. err: %s", err)
+	}
+
+	for idx := range chunkSlice(len(states), r.insert.BatchSize) {
+		if err = tx.Create(states[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+func (r *RDBDriver) getRedhatModuleStates(major, module, pkgName string) ([]models.RedhatModuleState, error) {
+	states := []models.RedhatModuleState{}
+	if err := r.conn.Where(&models.RedhatModuleState{Major: major, Module: module, PackageName: pkgName}).Find(&states).Error; err != nil {
+		return nil, xerrors.Errorf("Failed to get the states of the module stream. err: %w", err)
+	}
+	return states, nil
+}
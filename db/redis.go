@@ -4,15 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/cheggaaa/pb/v3"
 	"github.com/go-redis/redis/v8"
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/config"
 	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
 	"github.com/labstack/gommon/log"
-	"github.com/spf13/viper"
 	"golang.org/x/xerrors"
 )
 
@@ -20,12 +22,60 @@ import (
 # Redis Data Structure
 
 - HASH
-  ┌───┬────────────┬──────────────────────────────────┬──────────┬─────────────────────────────────┐
-  │NO │    HASH    │         FIELD                    │  VALUE   │             PURPOSE             │
-  └───┴────────────┴──────────────────────────────────┴──────────┴─────────────────────────────────┘
-  ┌───┬────────────┬──────────────────────────────────┬──────────┬─────────────────────────────────┐
-  │ 1 │CVE#$CVEID  │  RedHat/Debian/Ubuntu/Microsoft  │ $CVEJSON │     TO GET CVEJSON BY CVEID     │
-  └───┴────────────┴──────────────────────────────────┴──────────┴─────────────────────────────────┘
+  ┌───┬────────────┬────────────────────────────────────────┬──────────┬─────────────────────────────────┐
+  │NO │    HASH    │                FIELD                   │  VALUE   │             PURPOSE             │
+  └───┴────────────┴────────────────────────────────────────┴──────────┴─────────────────────────────────┘
+  ┌───┬────────────┬────────────────────────────────────────┬──────────┬─────────────────────────────────┐
+  │ 1 │CVE#$CVEID  │RedHat/Debian/Ubuntu/Amazon/Alpine/...  │ $CVEJSON │     TO GET CVEJSON BY CVEID     │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 2 │CVE#$CVEID  │KEV                                     │ $KEVJSON │  TO FLAG KNOWN EXPLOITED CVEID  │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 3 │CVE#$CVEID  │EPSS                                    │$EPSSJSON │   TO JOIN EPSS SCORE BY CVEID   │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 3 │CVE#$CVEID  │EXPLOIT                                 │$EXPLOITS │  TO JOIN PUBLIC EXPLOITS BY ID  │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 4 │GHSA#$GHSAID│GHSA                                    │$GHSAJSON │ TO GET ADVISORY JSON BY GHSAID  │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 5 │OSV#$OSVID  │OSV                                     │ $OSVJSON │   TO GET OSV JSON BY OSVID      │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 6 │GOVULN#$GOID│$MODULEPATH                             │ $GOJSON  │ TO GET GO VULN BY GOID, MODULE  │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 7 │JVN#$JVNID  │JVN                                     │ $JVNJSON │ TO GET ADVISORY JSON BY JVNID   │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 8 │MS#CVRF     │$CVRFID (e.g. 2023-Jan)                 │$RELEASED │ TO FETCH NEWER CVRF DOCUMENTS   │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │ 9 │MS#KB#B#$PID│$KBID                                   │  $BUILD  │ TO GET KB BUILDS BY PRODUCT ID  │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │10 │RH#M#$MAJOR#│$CVEID                                  │$STATEJSON│ TO GET STATES OF MODULE STREAM  │
+  │   │$MODULE/$PKG│                                        │          │                                 │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │11 │PSIRT#$VEND │PSIRT                                   │$PSIRTJSON│ TO GET ADVISORY JSON BY VENDOR  │
+  │   │OR#$ID      │                                        │          │ AND ADVISORY ID                 │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │12 │CWE#DICT    │$CWEID (e.g. CWE-79)                    │ $CWEJSON │ TO GET NAME OF CWE BY CWEID     │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │13 │CVEPROG#SYNC│FETCHTIME                               │$RFC3339  │ TO FETCH NEWER DELTA OF CVELIST │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │14 │CN#$SOURCE# │CN                                      │ $CNJSON  │ TO GET CNNVD/CNVD ADVISORY JSON │
+  │   │$ID         │                                        │          │ BY SOURCE AND ADVISORY ID       │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │15 │FETCH#SOURCE│$SOURCE (e.g. debian)                   │$RFC3339  │ TO CHECK STALENESS OF SOURCES   │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │16 │PKGADV#$SOUR│PKGADV                                  │$ADVJSON  │ TO GET RUBYSEC/PYPA/NPM ADVISORY│
+  │   │CE#$ID      │                                        │          │ JSON BY SOURCE AND ADVISORY ID  │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │17 │KERNEL#CVE  │$CVEID                                  │$KERNJSON │ TO GET LINUX KERNEL CVE JSON BY │
+  │   │            │                                        │          │ CVEID, ALL FOR VERSION LOOKUP   │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │18 │DISTRO#REL  │$DISTRO#$VERSION (e.g. ubuntu#2404)     │ $RELJSON │ TO GET CODENAMES OF DEBIAN AND  │
+  │   │            │                                        │          │ UBUNTU FROM DISTRO-INFO-DATA    │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │19 │GOST#META   │INDEXLAYOUT                             │ zset/set │ TO READ PACKAGE INDEXES IN THE  │
+  │   │            │                                        │          │ LAYOUT THEY ARE STORED          │
+  ├───┼────────────┼────────────────────────────────────────┼──────────┼─────────────────────────────────┤
+  │20 │APPLE#$ID   │APPLE                                   │$APPLEJSON│ TO GET APPLE SECURITY RELEASE   │
+  │   │            │                                        │          │ JSON BY RELEASE ID              │
+  └───┴────────────┴────────────────────────────────────────┴──────────┴─────────────────────────────────┘
 
 
 - ZINDE  X
@@ -35,31 +85,165 @@ import (
   ┌───┬────────────────┬──────────┬────────────┬───────────────────────────────────────────┐
   │ 1 │CVE#R#$PKGNAME  │    0     │  $CVEID    │(RedHat) GET RELATED []CVEID BY PKGNAME    │
   ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 1 │CVE#RF#$PKGNAME │    0     │  $CVEID    │(RedHat) GET []CVEID BY FIXED PKGNAME      │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
   │ 2 │CVE#D#$PKGNAME  │    0     │  $CVEID    │(Debian) GET RELATED []CVEID BY PKGNAME    │
   ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
   │ 3 │CVE#U#$PKGNAME  │    0     │  $CVEID    │(Ubuntu) GET RELATED []CVEID BY PKGNAME    │
   ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#US#$SNAP    │    0     │  $CVEID    │(Ubuntu) GET RELATED []CVEID BY SNAP NAME  │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#DB#$BUGID   │    0     │  $CVEID    │(Debian) GET RELATED []CVEID BY BTS BUG ID │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#UB#$TRK#$ID │    0     │  $CVEID    │(Ubuntu) GET []CVEID BY TRACKER AND BUG ID │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#USN#$USNID  │    0     │  $CVEID    │(Ubuntu) GET []CVEID BY USN ID             │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#A#$PKGNAME  │    0     │  $CVEID    │(Amazon) GET RELATED []CVEID BY PKGNAME    │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#AL#$PKGNAME │    0     │  $CVEID    │(Alpine) GET RELATED []CVEID BY PKGNAME    │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#WO#$PKGNAME │    0     │  $CVEID    │(Wolfi) GET RELATED []CVEID BY PKGNAME     │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#O#$PKGNAME  │    0     │  $CVEID    │(Oracle) GET RELATED []CVEID BY PKGNAME    │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#RL#$PKGNAME │    0     │  $CVEID    │(Rocky) GET RELATED []CVEID BY PKGNAME     │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#AM#$PKGNAME │    0     │  $CVEID    │(Alma) GET RELATED []CVEID BY PKGNAME      │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#PH#$PKGNAME │    0     │  $CVEID    │(Photon) GET RELATED []CVEID BY PKGNAME    │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#CM#$PKGNAME │    0     │  $CVEID    │(Mariner) GET RELATED []CVEID BY PKGNAME   │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#OE#$PKGNAME │    0     │  $CVEID    │(openEuler) GET RELATED []CVEID BY PKGNAME │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#AN#$PKGNAME │    0     │  $CVEID    │(Anolis) GET RELATED []CVEID BY PKGNAME    │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 3 │CVE#B#$BZID     │    0     │  $CVEID    │(RedHat) GET RELATED []CVEID BY BUGZILLA ID│
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
   │ 3 │CVE#K#$KBID     │    0     │  $CVEID    │(Microsoft) GET RELATED []CVEID BY KBID    │
   ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
   │ 4 │CVE#P#$PRODUCTID│    0     │$PRODUCTNAME│(Microsoft) GET RELATED []PRODUCTNAME BY ID│
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 5 │GHSA#P#$ECO#$PKG│    0     │  $GHSAID   │(GHSA) GET []GHSAID BY ECOSYSTEM AND PKG   │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 5 │GHSA#C#$CVEID   │    0     │  $GHSAID   │(GHSA) GET []GHSAID BY CVEID               │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 6 │OSV#P#$ECO#$PKG │    0     │  $OSVID    │(OSV) GET []OSVID BY ECOSYSTEM AND PKG     │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 7 │GOVULN#M#$MODULE│    0     │   $GOID    │(Go) GET []GOID BY MODULE PATH             │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 8 │JVN#C#$CVEID    │    0     │  $JVNID    │(JVN) GET []JVNID BY CVEID                 │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │ 9 │MS#KB#S#$KBID   │    0     │   $KBID    │(Microsoft) GET []SUPERSEDED KBID BY KBID  │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │10 │PSIRT#P#$VENDOR#│    0     │    $ID     │(PSIRT) GET []ADVISORY ID BY VENDOR AND    │
+  │   │$PRODUCT        │          │            │PRODUCT                                    │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │10 │PSIRT#C#$CVEID  │    0     │$VENDOR#$ID │(PSIRT) GET []VENDOR AND ADVISORY ID BY    │
+  │   │                │          │            │CVEID                                      │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │11 │CN#C#$CVEID     │    0     │$SOURCE#$ID │(CNNVD/CNVD) GET []SOURCE AND ADVISORY ID  │
+  │   │                │          │            │BY CVEID                                   │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │12 │PKGADV#P#$ECO#  │    0     │$SOURCE#$ID │(RubySec/PyPA/npm) GET []SOURCE AND        │
+  │   │$PKG            │          │            │ADVISORY ID BY ECOSYSTEM AND PKG           │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │12 │PKGADV#C#$CVEID │    0     │$SOURCE#$ID │(RubySec/PyPA/npm) GET []SOURCE AND        │
+  │   │                │          │            │ADVISORY ID BY CVEID                       │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │13 │APPLE#C#$CVEID  │    0     │    $ID     │(Apple) GET []RELEASE ID BY CVEID          │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │13 │APPLE#P#$PRODUCT│    0     │    $ID     │(Apple) GET []RELEASE ID BY PRODUCT        │
+  ├───┼────────────────┼──────────┼────────────┼───────────────────────────────────────────┤
+  │14 │TOMBSTONE       │ $UNIXTIME│$SOURCE#    │GET []CVE REMOVED BY REMOVEMISSING SINCE   │
+  │   │                │          │$CVEID      │THE TIME                                   │
   └───┴────────────────┴──────────┴────────────┴───────────────────────────────────────────┘
 
+  The indexes of the packages of the distributions (CVE#R#, CVE#RF#, CVE#D#, CVE#U#, CVE#A#, CVE#AL#, CVE#WO#, CVE#O#,
+  CVE#RL#, CVE#AM#, CVE#PH#, CVE#CM#, CVE#OE# and CVE#AN#) are SET instead of ZSET if INDEXLAYOUT of GOST#META is set.
+
 **/
 
 const (
 	dialectRedis                 = "redis"
 	hashKeyPrefix                = "CVE#"
 	zindRedHatPrefix             = "CVE#R#"
+	zindRedHatBugzillaPrefix     = "CVE#B#"
+	zindRedHatFixedPrefix        = "CVE#RF#"
+	hashRedHatModuleStatePrefix  = "RH#M#"
 	zindDebianPrefix             = "CVE#D#"
+	zindDebianBugPrefix          = "CVE#DB#"
 	zindUbuntuPrefix             = "CVE#U#"
+	zindUbuntuSnapPrefix         = "CVE#US#"
+	zindUbuntuBugPrefix          = "CVE#UB#"
+	zindUbuntuUSNPrefix          = "CVE#USN#"
+	zindAmazonPrefix             = "CVE#A#"
+	zindAlpinePrefix             = "CVE#AL#"
+	zindWolfiPrefix              = "CVE#WO#"
+	zindOraclePrefix             = "CVE#O#"
+	zindRockyPrefix              = "CVE#RL#"
+	zindAlmaPrefix               = "CVE#AM#"
+	zindPhotonPrefix             = "CVE#PH#"
+	zindMarinerPrefix            = "CVE#CM#"
+	zindOpenEulerPrefix          = "CVE#OE#"
+	zindAnolisPrefix             = "CVE#AN#"
 	zindMicrosoftKBIDPrefix      = "CVE#K#"
 	zindMicrosoftProductIDPrefix = "CVE#P#"
+	hashMicrosoftCvrfKey         = "MS#CVRF"
+	hashMicrosoftKBBuildPrefix   = "MS#KB#B#"
+	zindMicrosoftKBPrefix        = "MS#KB#S#"
+	hashGhsaPrefix               = "GHSA#"
+	zindGhsaPackagePrefix        = "GHSA#P#"
+	zindGhsaCvePrefix            = "GHSA#C#"
+	hashOsvPrefix                = "OSV#"
+	zindOsvPackagePrefix         = "OSV#P#"
+	hashGoVulnPrefix             = "GOVULN#"
+	zindGoVulnModulePrefix       = "GOVULN#M#"
+	hashJvnPrefix                = "JVN#"
+	zindJvnCvePrefix             = "JVN#C#"
+	hashPsirtPrefix              = "PSIRT#"
+	zindPsirtProductPrefix       = "PSIRT#P#"
+	zindPsirtCvePrefix           = "PSIRT#C#"
+	hashApplePrefix              = "APPLE#"
+	zindAppleCvePrefix           = "APPLE#C#"
+	zindAppleProductPrefix       = "APPLE#P#"
+	hashCweKey                   = "CWE#DICT"
+	hashCveProgramSyncKey        = "CVEPROG#SYNC"
+	hashCnPrefix                 = "CN#"
+	zindCnCvePrefix              = "CN#C#"
+	hashFetchSourceKey           = "FETCH#SOURCE"
+	hashFetchLicenseKey          = "FETCH#LICENSE"
+	hashPackageAdvisoryPrefix    = "PKGADV#"
+	zindPackageAdvisoryPkgPrefix = "PKGADV#P#"
+	zindPackageAdvisoryCvePrefix = "PKGADV#C#"
+	hashKernelCveKey             = "KERNEL#CVE"
+	hashDistroReleaseKey         = "DISTRO#REL"
+	hashMetaKey                  = "GOST#META"
+	zindTombstoneKey             = "TOMBSTONE"
 )
 
 // RedisDriver is Driver for Redis
 type RedisDriver struct {
-	name string
-	conn *redis.Client
+	name     string
+	conn     *redis.Client
+	insert   InsertOptions
+	filter   Filter
+	multiGet multiGetOptions
+	explain  *Explain
+
+	queryTimeout       time.Duration
+	slowQueryThreshold time.Duration
+	breaker            *CircuitBreaker
+	// requirePersistence fails OpenDB when the dataset may be lost on restart or by eviction
+	requirePersistence bool
+	// indexLayout is the layout of the package indexes requested, and the one of the DB after OpenDB
+	indexLayout   string
+	documentSizes *DocumentSizes
+	seenIDs       *SeenIDs
+	warnings      *util.Warnings
+	// log is the logger of the request (e.g. with request_id), or the root logger
+	log log15.Logger
 }
 
 // Name return db name
@@ -67,6 +251,31 @@ func (r *RedisDriver) Name() string {
 	return r.name
 }
 
+// WithExplain returns a copy of the driver which records the commands into e
+func (r *RedisDriver) WithExplain(e *Explain) DB {
+	d := *r
+	d.conn = r.conn.WithContext(context.Background())
+	d.conn.AddHook(&explainHook{explain: e})
+	d.explain = e
+	return &d
+}
+
+// WithLogger returns a copy of the driver which logs by l. The slow queries are logged by l through loggerHook
+func (r *RedisDriver) WithLogger(l log15.Logger) DB {
+	d := *r
+	d.conn = r.conn.WithContext(context.Background())
+	d.conn.AddHook(loggerHook{log: l})
+	d.log = l
+	return &d
+}
+
+// WithInsertOptions returns a copy of the driver which inserts with o
+func (r *RedisDriver) WithInsertOptions(o InsertOptions) DB {
+	d := *r
+	d.insert = o.normalize()
+	return &d
+}
+
 // OpenDB opens Database
 func (r *RedisDriver) OpenDB(dbType, dbPath string, debugSQL bool) (locked bool, err error) {
 	if err = r.connectRedis(dbPath); err != nil {
@@ -80,12 +289,32 @@ func (r *RedisDriver) connectRedis(dbPath string) error {
 	var err error
 	var option *redis.Options
 	if option, err = redis.ParseURL(dbPath); err != nil {
-		log15.Error("Failed to parse url.", "err", err)
+		r.log.Error("Failed to parse url.", "err", err)
 		return err
 	}
 	r.conn = redis.NewClient(option)
-	err = r.conn.Ping(ctx).Err()
-	return err
+	if r.queryTimeout > 0 {
+		r.conn.AddHook(queryTimeoutHook{timeout: r.queryTimeout})
+	}
+	if r.slowQueryThreshold > 0 {
+		r.conn.AddHook(slowQueryHook{threshold: r.slowQueryThreshold})
+	}
+	if r.breaker != nil {
+		r.conn.AddHook(circuitBreakerHook{breaker: r.breaker})
+	}
+	if r.documentSizes != nil {
+		r.conn.AddHook(documentSizeHook{sizes: r.documentSizes, warnings: r.warnings})
+	}
+	if r.seenIDs != nil {
+		r.conn.AddHook(seenIDsHook{seen: r.seenIDs, filtered: !r.filter.isEmpty()})
+	}
+	if err = r.conn.Ping(ctx).Err(); err != nil {
+		return err
+	}
+	if err = r.checkRedisPersistence(ctx); err != nil {
+		return err
+	}
+	return r.resolveIndexLayout(ctx)
 }
 
 // CloseDB close Database
@@ -119,6 +348,53 @@ func (r *RedisDriver) UpsertFetchMeta(*models.FetchMeta) error {
 	return nil
 }
 
+// GetFetchSources :
+func (r *RedisDriver) GetFetchSources() ([]models.FetchSource, error) {
+	ctx := context.Background()
+	m, err := r.conn.HGetAll(ctx, hashFetchSourceKey).Result()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get fetch sources. err: %w", err)
+	}
+	licenses, err := r.conn.HGetAll(ctx, hashFetchLicenseKey).Result()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get the licenses of fetch sources. err: %w", err)
+	}
+	sources := make([]models.FetchSource, 0, len(m))
+	for source, v := range m {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to parse the fetch time. source: %s, err: %w", source, err)
+		}
+		fetched := models.FetchSource{Source: source, FetchedAt: t}
+		// the sources fetched by the older gost have no license
+		if l, ok := licenses[source]; ok {
+			if err := json.Unmarshal([]byte(l), &fetched.SourceLicense); err != nil {
+				return nil, xerrors.Errorf("Failed to unmarshal the license. source: %s, err: %w", source, err)
+			}
+		}
+		sources = append(sources, fetched)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Source < sources[j].Source })
+	return sources, nil
+}
+
+// UpsertFetchSource records the last fetch time and the license of the source. The keys are persistent,
+// so that a source expired by --expire is still reported as stale
+func (r *RedisDriver) UpsertFetchSource(fetched models.FetchSource) error {
+	ctx := context.Background()
+	license, err := json.Marshal(fetched.SourceLicense)
+	if err != nil {
+		return xerrors.Errorf("Failed to marshal the license. err: %w", err)
+	}
+	pipe := r.conn.Pipeline()
+	_ = pipe.HSet(ctx, hashFetchSourceKey, fetched.Source, fetched.FetchedAt.UTC().Format(time.RFC3339Nano))
+	_ = pipe.HSet(ctx, hashFetchLicenseKey, fetched.Source, string(license))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return xerrors.Errorf("Failed to HSet fetch source. err: %w", err)
+	}
+	return nil
+}
+
 // GetAfterTimeRedhat :
 func (r *RedisDriver) GetAfterTimeRedhat(time.Time) ([]models.RedhatCVE, error) {
 	return nil, fmt.Errorf("Not implemented yet")
@@ -129,12 +405,15 @@ func (r *RedisDriver) GetRedhat(cveID string) *models.RedhatCVE {
 	ctx := context.Background()
 	result := r.conn.HGetAll(ctx, hashKeyPrefix+cveID)
 	if result.Err() != nil {
-		log15.Error("Failed to get cve.", "err", result.Err())
+		r.log.Error("Failed to get cve.", "err", result.Err())
 		return nil
 	}
+	return decodeRedhat(result.Val())
+}
 
+func decodeRedhat(hash map[string]string) *models.RedhatCVE {
 	var redhat models.RedhatCVE
-	if j, ok := result.Val()["RedHat"]; ok {
+	if j, ok := hash["RedHat"]; ok {
 		if err := json.Unmarshal([]byte(j), &redhat); err != nil {
 			log15.Error("Failed to Unmarshal json.", "err", err)
 			return nil
@@ -145,26 +424,18 @@ func (r *RedisDriver) GetRedhat(cveID string) *models.RedhatCVE {
 
 // GetRedhatMulti :
 func (r *RedisDriver) GetRedhatMulti(cveIDs []string) map[string]models.RedhatCVE {
-	ctx := context.Background()
 	results := map[string]models.RedhatCVE{}
-	rs := map[string]*redis.StringStringMapCmd{}
-
-	pipe := r.conn.Pipeline()
-	for _, cveID := range cveIDs {
-		rs[cveID] = pipe.HGetAll(ctx, hashKeyPrefix+cveID)
-	}
-	if _, err := pipe.Exec(ctx); err != nil {
-		if err != redis.Nil {
-			log15.Error("Failed to get multi cve json.", "err", err)
-			return nil
-		}
+	hashes, err := r.hgetAllMulti(cveIDs)
+	if err != nil {
+		r.log.Error("Failed to get multi cve json.", "err", err)
+		return nil
 	}
 
-	for cveID, result := range rs {
+	for cveID, hash := range hashes {
 		var redhat models.RedhatCVE
-		if j, ok := result.Val()["RedHat"]; ok {
+		if j, ok := hash["RedHat"]; ok {
 			if err := json.Unmarshal([]byte(j), &redhat); err != nil {
-				log15.Error("Failed to Unmarshal json.", "err", err)
+				r.log.Error("Failed to Unmarshal json.", "err", err)
 				return nil
 			}
 		}
@@ -173,102 +444,181 @@ func (r *RedisDriver) GetRedhatMulti(cveIDs []string) map[string]models.RedhatCV
 	return results
 }
 
-// GetUnfixedCvesRedhat :
-func (r *RedisDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix bool) (m map[string]models.RedhatCVE) {
+// GetRedhatByBugzillaID :
+func (r *RedisDriver) GetRedhatByBugzillaID(bugzillaID string) map[string]models.RedhatCVE {
 	ctx := context.Background()
-	m = map[string]models.RedhatCVE{}
-
 	var result *redis.StringSliceCmd
-	if result = r.conn.ZRange(ctx, zindRedHatPrefix+pkgName, 0, -1); result.Err() != nil {
-		log.Error(result.Err())
-		return
+	if result = r.conn.ZRange(ctx, zindRedHatBugzillaPrefix+bugzillaID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get cve ids.", "err", result.Err())
+		return nil
 	}
+	r.explain.addCandidates(len(result.Val()))
+	return r.GetRedhatMulti(result.Val())
+}
+
+// GetUnfixedCvesRedhat :
+func (r *RedisDriver) GetUnfixedCvesRedhat(major, pkgName string, ignoreWillNotFix bool) (m map[string]models.RedhatCVE) {
+	m = map[string]models.RedhatCVE{}
 
 	cpe := fmt.Sprintf("cpe:/o:redhat:enterprise_linux:%s", major)
-	for _, cveID := range result.Val() {
-		red := r.GetRedhat(cveID)
+	if err := r.forEachIndexedCve(zindRedHatPrefix+pkgName, func(cveID string, hash map[string]string) {
+		red := decodeRedhat(hash)
 		if red == nil {
-			log15.Error("CVE is not found", "CVE-ID", cveID)
-			continue
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
 		}
 
-		// https://access.redhat.com/documentation/en-us/red_hat_security_data_api/0.1/html-single/red_hat_security_data_api/index#cve_format
-		pkgStats := []models.RedhatPackageState{}
-		for _, pkgstat := range red.PackageState {
-			if pkgstat.Cpe != cpe ||
-				pkgstat.PackageName != pkgName ||
-				pkgstat.FixState == "Not affected" ||
-				pkgstat.FixState == "New" {
-				continue
+		if red.PackageState = unfixedRedhatPackageStates(red.PackageState, cpe, pkgName, ignoreWillNotFix); len(red.PackageState) == 0 {
+			return
+		}
+		m[cveID] = *red
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	applyRedhatModuleStates(r, m, major, pkgName, ignoreWillNotFix)
+	return
+}
+
+// GetUnfixedCvesRedhatMulti gets the unfixed CVEs of pkgNames by a pipeline of the indexes and a multi-get of the CVEs
+func (r *RedisDriver) GetUnfixedCvesRedhatMulti(major string, pkgNames []string, ignoreWillNotFix bool) map[string]map[string]models.RedhatCVE {
+	ids, hashes, err := r.indexedCvesMulti(zindRedHatPrefix, pkgNames)
+	if err != nil {
+		r.log.Error("Failed to get unfixed cves of Redhat", "err", err)
+		return nil
+	}
 
-			} else if ignoreWillNotFix && pkgstat.FixState == "Will not fix" {
+	results := map[string]map[string]models.RedhatCVE{}
+	cpe := fmt.Sprintf("cpe:/o:redhat:enterprise_linux:%s", major)
+	for _, pkgName := range pkgNames {
+		m := map[string]models.RedhatCVE{}
+		for _, cveID := range ids[pkgName] {
+			red := decodeRedhat(hashes[cveID])
+			if red == nil {
+				r.log.Error("CVE is not found", "CVE-ID", cveID)
 				continue
 			}
-			pkgStats = append(pkgStats, pkgstat)
+			if red.PackageState = unfixedRedhatPackageStates(red.PackageState, cpe, pkgName, ignoreWillNotFix); len(red.PackageState) != 0 {
+				m[cveID] = *red
+			}
 		}
-		if len(pkgStats) == 0 {
-			continue
+		applyRedhatModuleStates(r, m, major, pkgName, ignoreWillNotFix)
+		results[pkgName] = m
+	}
+	return results
+}
+
+// GetFixedCvesRedhat :
+func (r *RedisDriver) GetFixedCvesRedhat(major, pkgName string) map[string]models.RedhatCVE {
+	m := map[string]models.RedhatCVE{}
+
+	if err := r.forEachIndexedCve(zindRedHatFixedPrefix+pkgName, func(cveID string, hash map[string]string) {
+		red := decodeRedhat(hash)
+		if red == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
 		}
-		red.PackageState = pkgStats
-		m[cveID] = *red
+		if red.FixedPackages = filterRedhatFixedPackages(red.FixedPackages, major, pkgName); len(red.FixedPackages) > 0 {
+			m[cveID] = *red
+		}
+	}); err != nil {
+		log.Error(err)
+		return m
 	}
-	return
+	return m
 }
 
 // GetUnfixedCvesDebian : get the CVEs related to debian_release.status = 'open', major, pkgName
-func (r *RedisDriver) GetUnfixedCvesDebian(major, pkgName string) map[string]models.DebianCVE {
-	return r.getCvesDebianWithFixStatus(major, pkgName, "open")
+func (r *RedisDriver) GetUnfixedCvesDebian(major, pkgName string, channels ...string) map[string]models.DebianCVE {
+	return r.getCvesDebianWithFixStatus(major, pkgName, "open", channels)
 }
 
 // GetFixedCvesDebian : get the CVEs related to debian_release.status = 'resolved', major, pkgName
-func (r *RedisDriver) GetFixedCvesDebian(major, pkgName string) map[string]models.DebianCVE {
-	return r.getCvesDebianWithFixStatus(major, pkgName, "resolved")
+func (r *RedisDriver) GetFixedCvesDebian(major, pkgName string, channels ...string) map[string]models.DebianCVE {
+	return r.getCvesDebianWithFixStatus(major, pkgName, "resolved", channels)
 }
 
-func (r *RedisDriver) getCvesDebianWithFixStatus(major, pkgName, fixStatus string) (m map[string]models.DebianCVE) {
-	ctx := context.Background()
+func (r *RedisDriver) getCvesDebianWithFixStatus(major, pkgName, fixStatus string, channels []string) (m map[string]models.DebianCVE) {
 	m = map[string]models.DebianCVE{}
-	codeName, ok := debVerCodename[major]
+	codeName, ok := codenameOf(models.DistroDebian, major)
 	if !ok {
-		log15.Error("Not supported yet", "major", major)
-		return
-	}
-	var result *redis.StringSliceCmd
-	if result = r.conn.ZRange(ctx, zindDebianPrefix+pkgName, 0, -1); result.Err() != nil {
-		log.Error(result.Err())
+		r.log.Error("Not supported yet", "major", major)
 		return
 	}
 
-	for _, cveID := range result.Val() {
-		deb := r.GetDebian(cveID)
+	if err := r.forEachIndexedCve(zindDebianPrefix+pkgName, func(cveID string, hash map[string]string) {
+		deb := decodeDebian(hash)
 		if deb == nil {
-			log15.Error("CVE is not found", "CVE-ID", cveID)
-			continue
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		if deb.Package = debianPackagesWithFixStatus(deb.Package, codeName, pkgName, fixStatus, channels); len(deb.Package) != 0 {
+			m[cveID] = *deb
 		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetUnfixedCvesDebianMulti gets the unfixed CVEs of pkgNames by a pipeline of the indexes and a multi-get of the CVEs
+func (r *RedisDriver) GetUnfixedCvesDebianMulti(major string, pkgNames []string, channels ...string) map[string]map[string]models.DebianCVE {
+	codeName, ok := codenameOf(models.DistroDebian, major)
+	if !ok {
+		r.log.Error("Not supported yet", "major", major)
+		return map[string]map[string]models.DebianCVE{}
+	}
+	ids, hashes, err := r.indexedCvesMulti(zindDebianPrefix, pkgNames)
+	if err != nil {
+		r.log.Error("Failed to get unfixed cves of Debian", "err", err)
+		return nil
+	}
 
-		pkgs := []models.DebianPackage{}
-		for _, pkg := range deb.Package {
-			if pkg.PackageName != pkgName {
+	results := map[string]map[string]models.DebianCVE{}
+	for _, pkgName := range pkgNames {
+		m := map[string]models.DebianCVE{}
+		for _, cveID := range ids[pkgName] {
+			deb := decodeDebian(hashes[cveID])
+			if deb == nil {
+				r.log.Error("CVE is not found", "CVE-ID", cveID)
 				continue
 			}
-			rels := []models.DebianRelease{}
-			for _, rel := range pkg.Release {
-				if rel.ProductName == codeName && rel.Status == fixStatus {
-					rels = append(rels, rel)
-				}
-			}
-			if len(rels) == 0 {
-				continue
+			if deb.Package = debianPackagesWithFixStatus(deb.Package, codeName, pkgName, "open", channels); len(deb.Package) != 0 {
+				m[cveID] = *deb
 			}
-			pkg.Release = rels
-			pkgs = append(pkgs, pkg)
 		}
-		if len(pkgs) != 0 {
-			deb.Package = pkgs
+		results[pkgName] = m
+	}
+	return results
+}
+
+// GetAdvisoriesDebian :
+func (r *RedisDriver) GetAdvisoriesDebian(cveID string) []models.DebianAdvisory {
+	c := r.GetDebian(cveID)
+	if c == nil || c.Advisories == nil {
+		return []models.DebianAdvisory{}
+	}
+	return c.Advisories
+}
+
+// GetDebianByBugID :
+func (r *RedisDriver) GetDebianByBugID(bugID string) map[string]models.DebianCVE {
+	ctx := context.Background()
+	m := map[string]models.DebianCVE{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindDebianBugPrefix+bugID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get cve ids.", "err", result.Err())
+		return nil
+	}
+	r.explain.addCandidates(len(result.Val()))
+	for _, cveID := range result.Val() {
+		if deb := r.GetDebian(cveID); deb != nil {
 			m[cveID] = *deb
 		}
 	}
-	return
+	return m
 }
 
 // GetDebian :
@@ -279,8 +629,12 @@ func (r *RedisDriver) GetDebian(cveID string) *models.DebianCVE {
 		log.Error(result.Err())
 		return nil
 	}
+	return decodeDebian(result.Val())
+}
+
+func decodeDebian(hash map[string]string) *models.DebianCVE {
 	deb := models.DebianCVE{}
-	j, ok := result.Val()["Debian"]
+	j, ok := hash["Debian"]
 	if !ok {
 		return nil
 	}
@@ -293,65 +647,113 @@ func (r *RedisDriver) GetDebian(cveID string) *models.DebianCVE {
 }
 
 // GetUnfixedCvesUbuntu :
-func (r *RedisDriver) GetUnfixedCvesUbuntu(major, pkgName string) map[string]models.UbuntuCVE {
-	return r.getCvesUbuntuWithFixStatus(major, pkgName, []string{"needed", "pending"})
+func (r *RedisDriver) GetUnfixedCvesUbuntu(major, pkgName string, channels ...string) map[string]models.UbuntuCVE {
+	return r.getCvesUbuntuWithFixStatus(major, pkgName, []string{"needed", "pending"}, channels)
 }
 
 // GetFixedCvesUbuntu :
-func (r *RedisDriver) GetFixedCvesUbuntu(major, pkgName string) map[string]models.UbuntuCVE {
-	return r.getCvesUbuntuWithFixStatus(major, pkgName, []string{"released"})
+func (r *RedisDriver) GetFixedCvesUbuntu(major, pkgName string, channels ...string) map[string]models.UbuntuCVE {
+	return r.getCvesUbuntuWithFixStatus(major, pkgName, []string{"released"}, channels)
 }
 
-func (r *RedisDriver) getCvesUbuntuWithFixStatus(major, pkgName string, fixStatus []string) (m map[string]models.UbuntuCVE) {
-	ctx := context.Background()
+func (r *RedisDriver) getCvesUbuntuWithFixStatus(major, pkgName string, fixStatus, channels []string) (m map[string]models.UbuntuCVE) {
 	m = map[string]models.UbuntuCVE{}
-	codeName, ok := ubuntuVerCodename[major]
+	codeName, ok := codenameOf(models.DistroUbuntu, major)
 	if !ok {
-		log15.Error("Not supported yet", "major", major)
+		r.log.Error("Not supported yet", "major", major)
+		return
+	}
+
+	if err := r.forEachIndexedCve(zindUbuntuPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeUbuntu(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		if cve.Patches = ubuntuPatchesWithFixStatus(cve.Patches, codeName, pkgName, fixStatus, channels); len(cve.Patches) != 0 {
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
 		return
 	}
+	return
+}
+
+// GetUnfixedCvesUbuntuMulti gets the unfixed CVEs of pkgNames by a pipeline of the indexes and a multi-get of the CVEs
+func (r *RedisDriver) GetUnfixedCvesUbuntuMulti(major string, pkgNames []string, channels ...string) map[string]map[string]models.UbuntuCVE {
+	codeName, ok := codenameOf(models.DistroUbuntu, major)
+	if !ok {
+		r.log.Error("Not supported yet", "major", major)
+		return map[string]map[string]models.UbuntuCVE{}
+	}
+	ids, hashes, err := r.indexedCvesMulti(zindUbuntuPrefix, pkgNames)
+	if err != nil {
+		r.log.Error("Failed to get unfixed cves of Ubuntu", "err", err)
+		return nil
+	}
+
+	results := map[string]map[string]models.UbuntuCVE{}
+	for _, pkgName := range pkgNames {
+		m := map[string]models.UbuntuCVE{}
+		for _, cveID := range ids[pkgName] {
+			cve := decodeUbuntu(hashes[cveID])
+			if cve == nil {
+				r.log.Error("CVE is not found", "CVE-ID", cveID)
+				continue
+			}
+			if cve.Patches = ubuntuPatchesWithFixStatus(cve.Patches, codeName, pkgName, []string{"needed", "pending"}, channels); len(cve.Patches) != 0 {
+				m[cveID] = *cve
+			}
+		}
+		results[pkgName] = m
+	}
+	return results
+}
+
+// GetUnfixedCvesUbuntuSnap :
+func (r *RedisDriver) GetUnfixedCvesUbuntuSnap(snapName string) (m map[string]models.UbuntuCVE) {
+	ctx := context.Background()
+	m = map[string]models.UbuntuCVE{}
 	var result *redis.StringSliceCmd
-	if result = r.conn.ZRange(ctx, zindUbuntuPrefix+pkgName, 0, -1); result.Err() != nil {
+	if result = r.conn.ZRange(ctx, zindUbuntuSnapPrefix+snapName, 0, -1); result.Err() != nil {
 		log.Error(result.Err())
 		return
 	}
 
+	r.explain.addCandidates(len(result.Val()))
 	for _, cveID := range result.Val() {
 		cve := r.GetUbuntu(cveID)
 		if cve == nil {
-			log15.Error("CVE is not found", "CVE-ID", cveID)
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
 			continue
 		}
-
-		patches := []models.UbuntuPatch{}
-		for _, p := range cve.Patches {
-			if p.PackageName != pkgName {
-				continue
-			}
-			relPatches := []models.UbuntuReleasePatch{}
-			for _, relPatch := range p.ReleasePatches {
-				if relPatch.ReleaseName == codeName {
-					for _, s := range fixStatus {
-						if s == relPatch.Status {
-							relPatches = append(relPatches, relPatch)
-						}
-					}
-				}
-			}
-			if len(relPatches) == 0 {
-				continue
-			}
-			p.ReleasePatches = relPatches
-			patches = append(patches, p)
-		}
-		if len(patches) != 0 {
-			cve.Patches = patches
+		if cve.SnapPatches = filterUbuntuSnapPatches(cve.SnapPatches, snapName); len(cve.SnapPatches) != 0 {
 			m[cveID] = *cve
 		}
 	}
 	return
 }
 
+// GetUbuntuByBugID :
+func (r *RedisDriver) GetUbuntuByBugID(tracker, bugID string) map[string]models.UbuntuCVE {
+	ctx := context.Background()
+	m := map[string]models.UbuntuCVE{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindUbuntuBugPrefix+tracker+"#"+bugID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get cve ids.", "err", result.Err())
+		return nil
+	}
+	r.explain.addCandidates(len(result.Val()))
+	for _, cveID := range result.Val() {
+		if cve := r.GetUbuntu(cveID); cve != nil {
+			m[cveID] = *cve
+		}
+	}
+	return m
+}
+
 // GetUbuntu :
 func (r *RedisDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
 	ctx := context.Background()
@@ -360,9 +762,12 @@ func (r *RedisDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
 		log.Error(result.Err())
 		return nil
 	}
+	return decodeUbuntu(result.Val())
+}
 
+func decodeUbuntu(hash map[string]string) *models.UbuntuCVE {
 	c := models.UbuntuCVE{}
-	j, ok := result.Val()["Ubuntu"]
+	j, ok := hash["Ubuntu"]
 	if !ok {
 		return nil
 	}
@@ -372,84 +777,3179 @@ func (r *RedisDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
 		return nil
 	}
 
+	// USNs are stored in the other field, so that they are kept when Ubuntu CVEs are fetched again
+	if j, ok := hash["UbuntuUSN"]; ok {
+		if err := json.Unmarshal([]byte(j), &c.USNs); err != nil {
+			log15.Error("Failed to Unmarshal json.", "err", err)
+			return nil
+		}
+	}
+
 	return &c
 }
 
-// GetMicrosoft :
-func (r *RedisDriver) GetMicrosoft(cveID string) *models.MicrosoftCVE {
-	ctx := context.Background()
-	result := r.conn.HGetAll(ctx, hashKeyPrefix+cveID)
-	if result.Err() != nil {
-		log15.Error("Failed to get cve.", "err", result.Err())
+// GetCveIDs returns all CVE-IDs stored for the source.
+// It scans all CVE#$CVEID keys, so it is slow on a large DB.
+func (r *RedisDriver) GetCveIDs(source string) ([]string, error) {
+	cveIDs := []string{}
+	if err := r.ForEachCveID(source, func(cveID string) error {
+		cveIDs = append(cveIDs, cveID)
 		return nil
+	}); err != nil {
+		return nil, err
 	}
+	return cveIDs, nil
+}
 
-	var ms models.MicrosoftCVE
-	if j, ok := result.Val()["Microsoft"]; ok {
-		if err := json.Unmarshal([]byte(j), &ms); err != nil {
-			log15.Error("Failed to Unmarshal json.", "err", err)
-			return nil
-		}
+// ForEachCveID calls fn with each CVE-ID stored for the source as the CVE#$CVEID keys are scanned, so not in the order of CVE-ID.
+// The error of fn stops the iteration and is returned.
+func (r *RedisDriver) ForEachCveID(source string, fn func(cveID string) error) error {
+	field, ok := rawSourceFields[source]
+	if !ok {
+		return xerrors.Errorf("Failed to get CVE-IDs. source: %s, err: %w", source, ErrUnknownSource)
 	}
-	return &ms
+	return r.forEachCveIDOfField(field, fn)
 }
 
-// GetMicrosoftMulti :
-func (r *RedisDriver) GetMicrosoftMulti(cveIDs []string) map[string]models.MicrosoftCVE {
+// forEachCveIDOfField calls fn for each CVE-ID whose CVE#$CVEID has the field
+func (r *RedisDriver) forEachCveIDOfField(field string, fn func(cveID string) error) error {
 	ctx := context.Background()
-	results := map[string]models.MicrosoftCVE{}
-	rs := map[string]*redis.StringStringMapCmd{}
-
-	pipe := r.conn.Pipeline()
-	for _, cveID := range cveIDs {
-		rs[cveID] = pipe.HGetAll(ctx, hashKeyPrefix+cveID)
-	}
-	if _, err := pipe.Exec(ctx); err != nil {
-		if err != redis.Nil {
-			log15.Error("Failed to get multi cve json.", "err", err)
-			return nil
+	// SCAN may return a key more than once
+	seen := map[string]struct{}{}
+	var cursor uint64
+	for {
+		keys, next, err := r.conn.Scan(ctx, cursor, hashKeyPrefix+"CVE-*", 1000).Result()
+		if err != nil {
+			return xerrors.Errorf("Failed to scan keys. err: %w", err)
 		}
-	}
 
-	for cveID, result := range rs {
-		var ms models.MicrosoftCVE
-		if j, ok := result.Val()["Microsoft"]; ok {
-			if err := json.Unmarshal([]byte(j), &ms); err != nil {
-				log15.Error("Failed to Unmarshal json.", "err", err)
-				return nil
+		pipe := r.conn.Pipeline()
+		cmds := make([]*redis.BoolCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.HExists(ctx, key, field)
+		}
+		if len(keys) > 0 {
+			if _, err := pipe.Exec(ctx); err != nil {
+				return xerrors.Errorf("Failed to exec pipeline. err: %w", err)
 			}
 		}
-		results[cveID] = ms
+		for i, cmd := range cmds {
+			if !cmd.Val() {
+				continue
+			}
+			cveID := keys[i][len(hashKeyPrefix):]
+			if _, ok := seen[cveID]; ok {
+				continue
+			}
+			seen[cveID] = struct{}{}
+			if err := fn(cveID); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			break
+		}
+		cursor = next
 	}
-	return results
+	return nil
 }
 
-//InsertRedhat :
-func (r *RedisDriver) InsertRedhat(cveJSONs []models.RedhatCVEJSON) (err error) {
-	expire := viper.GetUint("expire")
-
+// GetCveIDsByUSN :
+func (r *RedisDriver) GetCveIDsByUSN(usnID string) []string {
 	ctx := context.Background()
-	cves, err := ConvertRedhat(cveJSONs)
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindUbuntuUSNPrefix+normalizeUSNID(usnID), 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get CVE-IDs by USN", "err", result.Err())
+		return []string{}
+	}
+	return result.Val()
+}
+
+// GetUnfixedCvesAmazon :
+// ALAS only publishes the advisories that have already been fixed, so it always returns an empty map.
+func (r *RedisDriver) GetUnfixedCvesAmazon(release, pkgName string) map[string]models.AmazonCVE {
+	return map[string]models.AmazonCVE{}
+}
+
+// GetFixedCvesAmazon :
+func (r *RedisDriver) GetFixedCvesAmazon(release, pkgName string) (m map[string]models.AmazonCVE) {
+	m = map[string]models.AmazonCVE{}
+
+	if err := r.forEachIndexedCve(zindAmazonPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeAmazon(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		advisories := []models.AmazonAdvisory{}
+		for _, a := range cve.Advisories {
+			if a.ReleaseName != release {
+				continue
+			}
+			pkgs := []models.AmazonPackage{}
+			for _, p := range a.Packages {
+				if p.PackageName == pkgName {
+					pkgs = append(pkgs, p)
+				}
+			}
+			if len(pkgs) == 0 {
+				continue
+			}
+			a.Packages = pkgs
+			advisories = append(advisories, a)
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetAmazon :
+func (r *RedisDriver) GetAmazon(cveID string) *models.AmazonCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeAmazon(result.Val())
+}
+
+func decodeAmazon(hash map[string]string) *models.AmazonCVE {
+	c := models.AmazonCVE{}
+	j, ok := hash["Amazon"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetFixedCvesRocky :
+func (r *RedisDriver) GetFixedCvesRocky(release, pkgName string) (m map[string]models.RockyCVE) {
+	m = map[string]models.RockyCVE{}
+
+	if err := r.forEachIndexedCve(zindRockyPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeRocky(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		advisories := []models.RockyAdvisory{}
+		for _, a := range cve.Advisories {
+			if a.ReleaseName != release {
+				continue
+			}
+			pkgs := []models.RockyPackage{}
+			for _, p := range a.Packages {
+				if p.PackageName == pkgName {
+					pkgs = append(pkgs, p)
+				}
+			}
+			if len(pkgs) == 0 {
+				continue
+			}
+			a.Packages = pkgs
+			advisories = append(advisories, a)
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetRocky :
+func (r *RedisDriver) GetRocky(cveID string) *models.RockyCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeRocky(result.Val())
+}
+
+func decodeRocky(hash map[string]string) *models.RockyCVE {
+	c := models.RockyCVE{}
+	j, ok := hash["Rocky"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetFixedCvesAlma :
+func (r *RedisDriver) GetFixedCvesAlma(release, pkgName string) (m map[string]models.AlmaCVE) {
+	m = map[string]models.AlmaCVE{}
+
+	if err := r.forEachIndexedCve(zindAlmaPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeAlma(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		advisories := []models.AlmaAdvisory{}
+		for _, a := range cve.Advisories {
+			if a.ReleaseName != release {
+				continue
+			}
+			pkgs := []models.AlmaPackage{}
+			for _, p := range a.Packages {
+				if p.PackageName == pkgName {
+					pkgs = append(pkgs, p)
+				}
+			}
+			if len(pkgs) == 0 {
+				continue
+			}
+			a.Packages = pkgs
+			advisories = append(advisories, a)
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetAlma :
+func (r *RedisDriver) GetAlma(cveID string) *models.AlmaCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeAlma(result.Val())
+}
+
+func decodeAlma(hash map[string]string) *models.AlmaCVE {
+	c := models.AlmaCVE{}
+	j, ok := hash["Alma"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetFixedCvesOracle :
+func (r *RedisDriver) GetFixedCvesOracle(release, pkgName string) (m map[string]models.OracleCVE) {
+	m = map[string]models.OracleCVE{}
+
+	if err := r.forEachIndexedCve(zindOraclePrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeOracle(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		advisories := []models.OracleAdvisory{}
+		for _, a := range cve.Advisories {
+			pkgs := []models.OraclePackage{}
+			for _, p := range a.Packages {
+				if p.ReleaseName == release && p.PackageName == pkgName {
+					pkgs = append(pkgs, p)
+				}
+			}
+			if len(pkgs) == 0 {
+				continue
+			}
+			a.Packages = pkgs
+			advisories = append(advisories, a)
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetOracle :
+func (r *RedisDriver) GetOracle(cveID string) *models.OracleCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeOracle(result.Val())
+}
+
+func decodeOracle(hash map[string]string) *models.OracleCVE {
+	c := models.OracleCVE{}
+	j, ok := hash["Oracle"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetFixedCvesAlpine :
+func (r *RedisDriver) GetFixedCvesAlpine(release, pkgName string) (m map[string]models.AlpineCVE) {
+	m = map[string]models.AlpineCVE{}
+
+	if err := r.forEachIndexedCve(zindAlpinePrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeAlpine(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		pkgs := []models.AlpinePackage{}
+		for _, p := range cve.Packages {
+			if p.PackageName == pkgName && p.ReleaseName == release {
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(pkgs) != 0 {
+			cve.Packages = pkgs
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetAlpine :
+func (r *RedisDriver) GetAlpine(cveID string) *models.AlpineCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeAlpine(result.Val())
+}
+
+func decodeAlpine(hash map[string]string) *models.AlpineCVE {
+	c := models.AlpineCVE{}
+	j, ok := hash["Alpine"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetUnfixedCvesWolfi :
+func (r *RedisDriver) GetUnfixedCvesWolfi(distro, pkgName string) map[string]models.WolfiCVE {
+	return r.getCvesWolfi(distro, pkgName, false)
+}
+
+// GetFixedCvesWolfi :
+func (r *RedisDriver) GetFixedCvesWolfi(distro, pkgName string) map[string]models.WolfiCVE {
+	return r.getCvesWolfi(distro, pkgName, true)
+}
+
+func (r *RedisDriver) getCvesWolfi(distro, pkgName string, fixed bool) (m map[string]models.WolfiCVE) {
+	m = map[string]models.WolfiCVE{}
+
+	if err := r.forEachIndexedCve(zindWolfiPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeWolfi(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		pkgs := []models.WolfiPackage{}
+		for _, p := range cve.Packages {
+			if p.PackageName == pkgName && p.Distro == distro && (p.FixedVersion != "") == fixed {
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(pkgs) != 0 {
+			cve.Packages = pkgs
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetWolfi :
+func (r *RedisDriver) GetWolfi(cveID string) *models.WolfiCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeWolfi(result.Val())
+}
+
+func decodeWolfi(hash map[string]string) *models.WolfiCVE {
+	c := models.WolfiCVE{}
+	j, ok := hash["Wolfi"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetMicrosoft :
+func (r *RedisDriver) GetMicrosoft(cveID string) *models.MicrosoftCVE {
+	ctx := context.Background()
+	result := r.conn.HGetAll(ctx, hashKeyPrefix+cveID)
+	if result.Err() != nil {
+		r.log.Error("Failed to get cve.", "err", result.Err())
+		return nil
+	}
+
+	var ms models.MicrosoftCVE
+	if j, ok := result.Val()["Microsoft"]; ok {
+		if err := json.Unmarshal([]byte(j), &ms); err != nil {
+			r.log.Error("Failed to Unmarshal json.", "err", err)
+			return nil
+		}
+	}
+	return &ms
+}
+
+// GetMicrosoftMulti :
+func (r *RedisDriver) GetMicrosoftMulti(cveIDs []string) map[string]models.MicrosoftCVE {
+	results := map[string]models.MicrosoftCVE{}
+	hashes, err := r.hgetAllMulti(cveIDs)
 	if err != nil {
-		return err
+		r.log.Error("Failed to get multi cve json.", "err", err)
+		return nil
+	}
+
+	for cveID, hash := range hashes {
+		var ms models.MicrosoftCVE
+		if j, ok := hash["Microsoft"]; ok {
+			if err := json.Unmarshal([]byte(j), &ms); err != nil {
+				r.log.Error("Failed to Unmarshal json.", "err", err)
+				return nil
+			}
+		}
+		results[cveID] = ms
+	}
+	return results
+}
+
+// GetMicrosoftCvrfIDs :
+func (r *RedisDriver) GetMicrosoftCvrfIDs() ([]string, error) {
+	cvrfIDs, err := r.conn.HKeys(context.Background(), hashMicrosoftCvrfKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to HKeys CVRF documents. err: %s", err)
+	}
+	return cvrfIDs, nil
+}
+
+// GetMicrosoftProducts :
+func (r *RedisDriver) GetMicrosoftProducts() ([]models.MicrosoftProduct, error) {
+	ctx := context.Background()
+	products := []models.MicrosoftProduct{}
+	var cursor uint64
+	for {
+		keys, next, err := r.conn.Scan(ctx, cursor, zindMicrosoftProductIDPrefix+"*", 1000).Result()
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to scan keys. err: %w", err)
+		}
+
+		pipe := r.conn.Pipeline()
+		cmds := make([]*redis.StringSliceCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.ZRange(ctx, key, 0, -1)
+		}
+		if len(keys) > 0 {
+			if _, err := pipe.Exec(ctx); err != nil {
+				return nil, xerrors.Errorf("Failed to exec pipeline. err: %w", err)
+			}
+		}
+		for i, cmd := range cmds {
+			for _, name := range cmd.Val() {
+				products = append(products, models.MicrosoftProduct{ProductID: keys[i][len(zindMicrosoftProductIDPrefix):], ProductName: name, Arch: microsoftProductArch(name)})
+			}
+		}
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].ProductID != products[j].ProductID {
+			return products[i].ProductID < products[j].ProductID
+		}
+		return products[i].ProductName < products[j].ProductName
+	})
+	return products, nil
+}
+
+// GetSupersededKBs :
+func (r *RedisDriver) GetSupersededKBs(kbID string) ([]string, error) {
+	return getSupersededKBs(r, kbID)
+}
+
+// GetRequiredKBsForBuild :
+func (r *RedisDriver) GetRequiredKBsForBuild(productID, build string) ([]models.MicrosoftKBBuild, error) {
+	return getRequiredKBsForBuild(r, productID, build)
+}
+
+func (r *RedisDriver) getSupersededKBsDirectly(kbIDs []string) ([]string, error) {
+	ctx := context.Background()
+	pipe := r.conn.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, 0, len(kbIDs))
+	for _, kbID := range kbIDs {
+		cmds = append(cmds, pipe.ZRange(ctx, zindMicrosoftKBPrefix+kbID, 0, -1))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("Failed to get superseded KBs. err: %s", err)
+	}
+	superseded := []string{}
+	for _, cmd := range cmds {
+		superseded = append(superseded, cmd.Val()...)
+	}
+	return superseded, nil
+}
+
+func (r *RedisDriver) getMicrosoftKBBuilds(productID string) ([]models.MicrosoftKBBuild, error) {
+	result, err := r.conn.HGetAll(context.Background(), hashMicrosoftKBBuildPrefix+productID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to HGetAll the builds of KBs. err: %s", err)
+	}
+	builds := []models.MicrosoftKBBuild{}
+	for kbID, build := range result {
+		builds = append(builds, models.MicrosoftKBBuild{KBID: kbID, ProductID: productID, FixedBuild: build})
+	}
+	return builds, nil
+}
+
+// hgetAllMulti gets CVE#$CVEID of cveIDs by the pipelines of multiGet.chunkSize keys executed concurrently,
+// so that thousands of CVE-IDs do not make a huge blocking call and a huge buffer of Redis.
+func (r *RedisDriver) hgetAllMulti(cveIDs []string) (map[string]map[string]string, error) {
+	ctx := context.Background()
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    []error
+		results = map[string]map[string]string{}
+	)
+
+	sem := make(chan struct{}, r.multiGet.concurrency)
+	for idx := range chunkSlice(len(cveIDs), r.multiGet.chunkSize) {
+		chunk := cveIDs[idx.From:idx.To]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			pipe := r.conn.Pipeline()
+			rs := make([]*redis.StringStringMapCmd, len(chunk))
+			for i, cveID := range chunk {
+				rs[i] = pipe.HGetAll(ctx, hashKeyPrefix+cveID)
+			}
+			_, err := pipe.Exec(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && err != redis.Nil {
+				errs = append(errs, err)
+				return
+			}
+			for i, cveID := range chunk {
+				results[cveID] = rs[i].Val()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, xerrors.Errorf("Failed to exec pipeline. err: %w", errs[0])
+	}
+	return results, nil
+}
+
+// GetRaw returns the JSON stored in the field of the source in CVE#$CVEID as it is.
+// If there is no record, it returns nil.
+func (r *RedisDriver) GetRaw(source, cveID string) ([]byte, error) {
+	field, ok := rawSourceFields[source]
+	if !ok {
+		return nil, xerrors.Errorf("Failed to get raw document. source: %s, err: %w", source, ErrUnknownSource)
+	}
+
+	ctx := context.Background()
+	result := r.conn.HGet(ctx, hashKeyPrefix+cveID, field)
+	if err := result.Err(); err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("Failed to get raw document. err: %w", err)
+	}
+	return []byte(result.Val()), nil
+}
+
+// GetCveByID gets the records of the CVE in all the sources by a HGETALL of CVE#$CVEID
+func (r *RedisDriver) GetCveByID(cveID string) *models.CveDetail {
+	ctx := context.Background()
+	result := r.conn.HGetAll(ctx, hashKeyPrefix+cveID)
+	if result.Err() != nil {
+		r.log.Error("Failed to get cve.", "err", result.Err())
+		return nil
+	}
+	hash := result.Val()
+
+	d := models.CveDetail{CveID: cveID}
+	if _, ok := hash["RedHat"]; ok {
+		d.RedHat = decodeRedhat(hash)
+	}
+	d.Debian = decodeDebian(hash)
+	d.Ubuntu = decodeUbuntu(hash)
+	d.Amazon = decodeAmazon(hash)
+	d.Alpine = decodeAlpine(hash)
+	d.Wolfi = decodeWolfi(hash)
+	d.Oracle = decodeOracle(hash)
+	d.Rocky = decodeRocky(hash)
+	d.Alma = decodeAlma(hash)
+	d.Photon = decodePhoton(hash)
+	d.Mariner = decodeMariner(hash)
+	d.OpenEuler = decodeOpenEuler(hash)
+	d.Anolis = decodeAnolis(hash)
+	if c := (models.MicrosoftCVE{}); decodeCveField(hash, "Microsoft", &c) {
+		d.Microsoft = &c
+	}
+	if c := (models.NvdCVE{}); decodeCveField(hash, "NVD", &c) {
+		d.Nvd = &c
+	}
+	if c := (models.CveProgramCVE{}); decodeCveField(hash, "CVEProgram", &c) {
+		d.CveProgram = &c
+	}
+	if c := (models.KEVEntry{}); decodeCveField(hash, "KEV", &c) {
+		d.KEV = &c
+	}
+	if c := (models.EpssScore{}); decodeCveField(hash, "EPSS", &c) {
+		d.Epss = &c
+	}
+	if es := []models.Exploit{}; decodeCveField(hash, "EXPLOIT", &es) && len(es) > 0 {
+		d.Exploits = es
+	}
+	return &d
+}
+
+// decodeCveField decodes the JSON of the field of CVE#$CVEID into v. It returns false if the field does not exist or is broken
+func decodeCveField(hash map[string]string, field string, v interface{}) bool {
+	j, ok := hash[field]
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal([]byte(j), v); err != nil {
+		log15.Error("Failed to Unmarshal json.", "field", field, "err", err)
+		return false
+	}
+	return true
+}
+
+//InsertRedhat :
+func (r *RedisDriver) InsertRedhat(cveJSONs []models.RedhatCVEJSON) (err error) {
+	ctx := context.Background()
+	cves, err := ConvertRedhat(cveJSONs)
+	if err != nil {
+		return err
+	}
+	cves = r.filter.filterRedhat(cves)
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.Name
+		if result := pipe.HSet(ctx, key, "RedHat", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		if cve.Bugzilla.BugzillaID != "" {
+			key := zindRedHatBugzillaPrefix + cve.Bugzilla.BugzillaID
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: cve.Name},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd bugzilla id. err: %s", result.Err())
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		for _, pkg := range cve.PackageState {
+			key := zindRedHatPrefix + pkg.PackageName
+			if err := r.addIndexMember(ctx, pipe, key, cve.Name); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		fixedNames := map[string]bool{}
+		for _, p := range cve.FixedPackages {
+			fixedNames[p.Name] = true
+		}
+		for name := range fixedNames {
+			key := zindRedHatFixedPrefix + name
+			if err := r.addIndexMember(ctx, pipe, key, cve.Name); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// InsertRedhatOVAL :
+func (r *RedisDriver) InsertRedhatOVAL(ovals []models.RedhatOVALXML) (err error) {
+	ctx := context.Background()
+	states := ConvertRedhatOVAL(ovals)
+	bar := startProgress(r.insert.Progress, len(states))
+
+	byKey := map[string][]models.RedhatModuleState{}
+	for _, s := range states {
+		key := fmt.Sprintf("%s%s#%s/%s", hashRedHatModuleStatePrefix, s.Major, s.Module, s.PackageName)
+		byKey[key] = append(byKey[key], s)
+	}
+	for key, ss := range byKey {
+		pipe := r.conn.Pipeline()
+		if err := pipe.Del(ctx, key).Err(); err != nil {
+			return fmt.Errorf("Failed to Del the states of the module stream. err: %s", err)
+		}
+		for _, s := range ss {
+			bar.Add(1)
+			j, err := json.Marshal(s)
+			if err != nil {
+				return fmt.Errorf("Failed to marshal json. err: %s", err)
+			}
+			if err := pipe.HSet(ctx, key, s.CveID, string(j)).Err(); err != nil {
+				return fmt.Errorf("Failed to HSet the state of the module stream. err: %s", err)
+			}
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+
+	return nil
+}
+
+func (r *RedisDriver) getRedhatModuleStates(major, module, pkgName string) ([]models.RedhatModuleState, error) {
+	key := fmt.Sprintf("%s%s#%s/%s", hashRedHatModuleStatePrefix, major, module, pkgName)
+	result, err := r.conn.HGetAll(context.Background(), key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to HGetAll the states of the module stream. err: %s", err)
+	}
+	states := []models.RedhatModuleState{}
+	for _, j := range result {
+		s := models.RedhatModuleState{}
+		if err := json.Unmarshal([]byte(j), &s); err != nil {
+			return nil, fmt.Errorf("Failed to Unmarshal json. err: %s", err)
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+// InsertDebian :
+func (r *RedisDriver) InsertDebian(cveJSONs models.DebianJSON, advisoryJSONs []models.DebianAdvisoryJSON) error {
+	ctx := context.Background()
+	cves := r.filter.filterDebian(ConvertDebian(cveJSONs, advisoryJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Debian", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		for _, pkg := range cve.Package {
+			if pkg.DebianBug == "" {
+				continue
+			}
+			key := zindDebianBugPrefix + pkg.DebianBug
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: cve.CveID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd bug id. err: %s", result.Err())
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		for _, pkg := range cve.Package {
+			key := zindDebianPrefix + pkg.PackageName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// InsertUbuntu :
+func (r *RedisDriver) InsertUbuntu(cveJSONs []models.UbuntuCVEJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterUbuntu(ConvertUbuntu(cveJSONs))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.Candidate
+		if result := pipe.HSet(ctx, key, "Ubuntu", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		for _, bug := range cve.Bugs {
+			if bug.BugID == "" {
+				continue
+			}
+			key := zindUbuntuBugPrefix + bug.Tracker + "#" + bug.BugID
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: cve.Candidate},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd bug id. err: %s", result.Err())
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		for _, p := range cve.SnapPatches {
+			key := zindUbuntuSnapPrefix + p.SnapName
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: cve.Candidate},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd snap name. err: %s", result.Err())
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		for _, pkg := range cve.Patches {
+			key := zindUbuntuPrefix + pkg.PackageName
+			if err := r.addIndexMember(ctx, pipe, key, cve.Candidate); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// InsertUbuntuUSN :
+func (r *RedisDriver) InsertUbuntuUSN(usnJSONs []models.UbuntuUSNJSON) (err error) {
+	ctx := context.Background()
+	cveUSNs := map[string][]models.UbuntuUSN{}
+	for _, usn := range ConvertUbuntuUSN(usnJSONs) {
+		cveUSNs[usn.CveID] = append(cveUSNs[usn.CveID], usn)
+	}
+	bar := startProgress(r.insert.Progress, len(cveUSNs))
+
+	for cveID, usns := range cveUSNs {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(usns)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		keys := []string{hashKeyPrefix + cveID}
+		if result := pipe.HSet(ctx, keys[0], "UbuntuUSN", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+
+		uniq := map[string]struct{}{}
+		for _, usn := range usns {
+			key := zindUbuntuUSNPrefix + usn.USNID
+			if _, ok := uniq[key]; ok {
+				continue
+			}
+			uniq[key] = struct{}{}
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: cveID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd USN ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetFixedCvesPhoton :
+func (r *RedisDriver) GetFixedCvesPhoton(release, pkgName string) (m map[string]models.PhotonCVE) {
+	m = map[string]models.PhotonCVE{}
+
+	if err := r.forEachIndexedCve(zindPhotonPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodePhoton(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		pkgs := []models.PhotonPackage{}
+		for _, p := range cve.Packages {
+			if p.PackageName == pkgName && p.ReleaseName == release {
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(pkgs) != 0 {
+			cve.Packages = pkgs
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetPhoton :
+func (r *RedisDriver) GetPhoton(cveID string) *models.PhotonCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodePhoton(result.Val())
+}
+
+func decodePhoton(hash map[string]string) *models.PhotonCVE {
+	c := models.PhotonCVE{}
+	j, ok := hash["Photon"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertPhoton :
+func (r *RedisDriver) InsertPhoton(cveJSONs []models.PhotonCVEJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterPhoton(ConvertPhoton(cveJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Photon", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		pkgNames := map[string]struct{}{}
+		for _, p := range cve.Packages {
+			pkgNames[p.PackageName] = struct{}{}
+		}
+		for pkgName := range pkgNames {
+			key := zindPhotonPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetFixedCvesMariner :
+func (r *RedisDriver) GetFixedCvesMariner(release, pkgName string) (m map[string]models.MarinerCVE) {
+	m = map[string]models.MarinerCVE{}
+
+	if err := r.forEachIndexedCve(zindMarinerPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeMariner(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		pkgs := []models.MarinerPackage{}
+		for _, p := range cve.Packages {
+			if p.PackageName == pkgName && p.ReleaseName == release {
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(pkgs) != 0 {
+			cve.Packages = pkgs
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetMariner :
+func (r *RedisDriver) GetMariner(cveID string) *models.MarinerCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeMariner(result.Val())
+}
+
+func decodeMariner(hash map[string]string) *models.MarinerCVE {
+	c := models.MarinerCVE{}
+	j, ok := hash["Mariner"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertMariner :
+func (r *RedisDriver) InsertMariner(ovals []models.MarinerOVALXML) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterMariner(ConvertMariner(ovals, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Mariner", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		pkgNames := map[string]struct{}{}
+		for _, p := range cve.Packages {
+			pkgNames[p.PackageName] = struct{}{}
+		}
+		for pkgName := range pkgNames {
+			key := zindMarinerPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetFixedCvesOpenEuler :
+func (r *RedisDriver) GetFixedCvesOpenEuler(release, pkgName string) (m map[string]models.OpenEulerCVE) {
+	m = map[string]models.OpenEulerCVE{}
+
+	if err := r.forEachIndexedCve(zindOpenEulerPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeOpenEuler(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		advisories := []models.OpenEulerAdvisory{}
+		for _, a := range cve.Advisories {
+			pkgs := []models.OpenEulerPackage{}
+			for _, p := range a.Packages {
+				if p.ReleaseName == release && p.PackageName == pkgName {
+					pkgs = append(pkgs, p)
+				}
+			}
+			if len(pkgs) == 0 {
+				continue
+			}
+			a.Packages = pkgs
+			advisories = append(advisories, a)
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetOpenEuler :
+func (r *RedisDriver) GetOpenEuler(cveID string) *models.OpenEulerCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeOpenEuler(result.Val())
+}
+
+func decodeOpenEuler(hash map[string]string) *models.OpenEulerCVE {
+	c := models.OpenEulerCVE{}
+	j, ok := hash["OpenEuler"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertOpenEuler :
+func (r *RedisDriver) InsertOpenEuler(cvrfs []models.OpenEulerCVRFXML) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterOpenEuler(ConvertOpenEuler(cvrfs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "OpenEuler", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		pkgNames := map[string]struct{}{}
+		for _, a := range cve.Advisories {
+			for _, p := range a.Packages {
+				pkgNames[p.PackageName] = struct{}{}
+			}
+		}
+		for pkgName := range pkgNames {
+			key := zindOpenEulerPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetFixedCvesAnolis :
+func (r *RedisDriver) GetFixedCvesAnolis(release, pkgName string) (m map[string]models.AnolisCVE) {
+	m = map[string]models.AnolisCVE{}
+
+	if err := r.forEachIndexedCve(zindAnolisPrefix+pkgName, func(cveID string, hash map[string]string) {
+		cve := decodeAnolis(hash)
+		if cve == nil {
+			r.log.Error("CVE is not found", "CVE-ID", cveID)
+			return
+		}
+
+		advisories := []models.AnolisAdvisory{}
+		for _, a := range cve.Advisories {
+			pkgs := []models.AnolisPackage{}
+			for _, p := range a.Packages {
+				if p.ReleaseName == release && p.PackageName == pkgName {
+					pkgs = append(pkgs, p)
+				}
+			}
+			if len(pkgs) == 0 {
+				continue
+			}
+			a.Packages = pkgs
+			advisories = append(advisories, a)
+		}
+		if len(advisories) != 0 {
+			cve.Advisories = advisories
+			m[cveID] = *cve
+		}
+	}); err != nil {
+		log.Error(err)
+		return
+	}
+	return
+}
+
+// GetAnolis :
+func (r *RedisDriver) GetAnolis(cveID string) *models.AnolisCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+	return decodeAnolis(result.Val())
+}
+
+func decodeAnolis(hash map[string]string) *models.AnolisCVE {
+	c := models.AnolisCVE{}
+	j, ok := hash["Anolis"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		log15.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertAnolis :
+func (r *RedisDriver) InsertAnolis(ovals []models.AnolisOVALXML) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterAnolis(ConvertAnolis(ovals, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Anolis", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		pkgNames := map[string]struct{}{}
+		for _, a := range cve.Advisories {
+			for _, p := range a.Packages {
+				pkgNames[p.PackageName] = struct{}{}
+			}
+		}
+		for pkgName := range pkgNames {
+			key := zindAnolisPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetNvd :
+func (r *RedisDriver) GetNvd(cveID string) *models.NvdCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+
+	c := models.NvdCVE{}
+	j, ok := result.Val()["NVD"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertNvd :
+func (r *RedisDriver) InsertNvd(cveJSONs []models.NvdCVEJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterNvd(ConvertNvd(cveJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "NVD", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetCveProgram :
+func (r *RedisDriver) GetCveProgram(cveID string) *models.CveProgramCVE {
+	ctx := context.Background()
+	var result *redis.StringCmd
+	if result = r.conn.HGet(ctx, hashKeyPrefix+cveID, "CVEProgram"); result.Err() != nil {
+		if result.Err() != redis.Nil {
+			r.log.Error("Failed to get CVE Program.", "err", result.Err())
+		}
+		return nil
+	}
+
+	c := models.CveProgramCVE{}
+	if err := json.Unmarshal([]byte(result.Val()), &c); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetCveProgramFetchTime :
+func (r *RedisDriver) GetCveProgramFetchTime() (time.Time, error) {
+	ctx := context.Background()
+	v, err := r.conn.HGet(ctx, hashCveProgramSyncKey, "FETCHTIME").Result()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, xerrors.Errorf("Failed to get the fetch time of cvelistV5. err: %w", err)
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("Failed to parse the fetch time of cvelistV5. err: %w", err)
+	}
+	return t, nil
+}
+
+// InsertCveProgram overwrites the CVE records fetched. The records removed from cvelistV5 are kept until they expire or fetch --remove-missing
+// The records are read from records in batches, and the fetch time is stored after all of them.
+func (r *RedisDriver) InsertCveProgram(records CveRecordReader, fetchTime time.Time) (err error) {
+	bar := startProgress(r.insert.Progress, records.Len())
+	for {
+		batch, err := records.Next(cveRecordReadSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if err := r.hsetCveProgram(r.filter.filterCveProgram(ConvertCveProgram(batch, r.warnings))); err != nil {
+			return err
+		}
+		bar.Add(len(batch))
+	}
+	if err := r.setCveProgramFetchTime(fetchTime); err != nil {
+		return err
+	}
+	bar.Finish()
+	return nil
+}
+
+// UpsertCveProgram :
+func (r *RedisDriver) UpsertCveProgram(records []models.CveRecordJSON, fetchTime time.Time) (err error) {
+	r.seenIDs.markPartial("CVEProgram")
+	return r.upsertCveProgram(records, fetchTime)
+}
+
+func (r *RedisDriver) upsertCveProgram(records []models.CveRecordJSON, fetchTime time.Time) (err error) {
+	cves := r.filter.filterCveProgram(ConvertCveProgram(records, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+	if err := r.hsetCveProgram(cves); err != nil {
+		return err
+	}
+	bar.Add(len(cves))
+	if err := r.setCveProgramFetchTime(fetchTime); err != nil {
+		return err
+	}
+	bar.Finish()
+	return nil
+}
+
+func (r *RedisDriver) hsetCveProgram(cves []models.CveProgramCVE) (err error) {
+	ctx := context.Background()
+	for idx := range chunkSlice(len(cves), r.multiGet.chunkSize) {
+		pipe := r.conn.Pipeline()
+		for _, cve := range cves[idx.From:idx.To] {
+			j, err := json.Marshal(cve)
+			if err != nil {
+				return fmt.Errorf("Failed to marshal json. err: %s", err)
+			}
+
+			key := hashKeyPrefix + cve.CveID
+			if result := pipe.HSet(ctx, key, "CVEProgram", string(j)); result.Err() != nil {
+				return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	return nil
+}
+
+// setCveProgramFetchTime stores the fetch time, which expires with the CVE records, so that the next fetch after they expire is a full fetch
+func (r *RedisDriver) setCveProgramFetchTime(fetchTime time.Time) error {
+	ctx := context.Background()
+	pipe := r.conn.Pipeline()
+	if err := pipe.HSet(ctx, hashCveProgramSyncKey, "FETCHTIME", fetchTime.UTC().Format(time.RFC3339Nano)).Err(); err != nil {
+		return fmt.Errorf("Failed to HSet the fetch time. err: %s", err)
+	}
+	if r.insert.TTL > 0 {
+		if err := pipe.Expire(ctx, hashCveProgramSyncKey, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+			return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+		}
+	} else {
+		if err := pipe.Persist(ctx, hashCveProgramSyncKey).Err(); err != nil {
+			return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+	}
+	return nil
+}
+
+// GetKernelCve :
+func (r *RedisDriver) GetKernelCve(cveID string) *models.KernelCVE {
+	ctx := context.Background()
+	c := models.KernelCVE{}
+	var result *redis.StringCmd
+	if result = r.conn.HGet(ctx, hashKernelCveKey, cveID); result.Err() != nil {
+		if result.Err() != redis.Nil {
+			r.log.Error("Failed to get kernel CVE.", "err", result.Err())
+			return nil
+		}
+		return &c
+	}
+
+	if err := json.Unmarshal([]byte(result.Val()), &c); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetKernelCves :
+func (r *RedisDriver) GetKernelCves() map[string]models.KernelCVE {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKernelCveKey); result.Err() != nil {
+		r.log.Error("Failed to get kernel CVEs.", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	m := map[string]models.KernelCVE{}
+	for cveID, j := range result.Val() {
+		c := models.KernelCVE{}
+		if err := json.Unmarshal([]byte(j), &c); err != nil {
+			r.log.Error("Failed to Unmarshal json.", "err", err)
+			return nil
+		}
+		m[cveID] = c
+	}
+	return m
+}
+
+// InsertKernelCves :
+// All CVEs are in one hash, which is replaced so that the rejected CVEs are removed.
+func (r *RedisDriver) InsertKernelCves(records []models.KernelCveJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterKernel(ConvertKernelCves(records, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	pipe := r.conn.TxPipeline()
+	if err := pipe.Del(ctx, hashKernelCveKey).Err(); err != nil {
+		return fmt.Errorf("Failed to delete Key. err: %s", err)
+	}
+	for _, c := range cves {
+		bar.Add(1)
+
+		j, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+		if result := pipe.HSet(ctx, hashKernelCveKey, c.CveID, string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet kernel CVE. err: %s", result.Err())
+		}
+	}
+	if r.insert.TTL > 0 {
+		if err := pipe.Expire(ctx, hashKernelCveKey, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+			return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+		}
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetDistroReleases :
+func (r *RedisDriver) GetDistroReleases() ([]models.DistroRelease, error) {
+	ctx := context.Background()
+	m, err := r.conn.HGetAll(ctx, hashDistroReleaseKey).Result()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to HGetAll the releases of distro-info-data. err: %w", err)
+	}
+	releases := make([]models.DistroRelease, 0, len(m))
+	for _, j := range m {
+		rel := models.DistroRelease{}
+		if err := json.Unmarshal([]byte(j), &rel); err != nil {
+			return nil, xerrors.Errorf("Failed to Unmarshal json. err: %w", err)
+		}
+		releases = append(releases, rel)
+	}
+	sortDistroReleases(releases)
+	return releases, nil
+}
+
+// InsertDistroReleases :
+func (r *RedisDriver) InsertDistroReleases(rows []models.DistroInfoCSV) (err error) {
+	ctx := context.Background()
+	releases := ConvertDistroInfo(rows)
+	bar := startProgress(r.insert.Progress, len(releases))
+
+	pipe := r.conn.TxPipeline()
+	if err := pipe.Del(ctx, hashDistroReleaseKey).Err(); err != nil {
+		return fmt.Errorf("Failed to delete Key. err: %s", err)
+	}
+	for _, rel := range releases {
+		bar.Add(1)
+
+		j, err := json.Marshal(rel)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+		if result := pipe.HSet(ctx, hashDistroReleaseKey, rel.Distro+"#"+rel.Version, string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet distro release. err: %s", result.Err())
+		}
+	}
+	if r.insert.TTL > 0 {
+		if err := pipe.Expire(ctx, hashDistroReleaseKey, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+			return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+		}
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+	}
+	bar.Finish()
+	setDistroReleases(releases)
+	return nil
+}
+
+// GetKEV :
+func (r *RedisDriver) GetKEV(cveID string) *models.KEVEntry {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+
+	c := models.KEVEntry{}
+	j, ok := result.Val()["KEV"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetKnownExploited :
+func (r *RedisDriver) GetKnownExploited(cveIDs []string) map[string]bool {
+	ctx := context.Background()
+	rs := map[string]*redis.BoolCmd{}
+
+	pipe := r.conn.Pipeline()
+	for _, cveID := range cveIDs {
+		rs[cveID] = pipe.HExists(ctx, hashKeyPrefix+cveID, "KEV")
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		if err != redis.Nil {
+			r.log.Error("Failed to get known exploited CVEs.", "err", err)
+			return nil
+		}
+	}
+
+	m := map[string]bool{}
+	for cveID, result := range rs {
+		if result.Val() {
+			m[cveID] = true
+		}
+	}
+	return m
+}
+
+// InsertKEV :
+func (r *RedisDriver) InsertKEV(entryJSONs []models.KEVEntryJSON) (err error) {
+	ctx := context.Background()
+	entries := r.filter.filterKEV(ConvertKEV(entryJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(entries))
+
+	for _, entry := range entries {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + entry.CveID
+		if result := pipe.HSet(ctx, key, "KEV", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetEpss :
+func (r *RedisDriver) GetEpss(cveID string) *models.EpssScore {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashKeyPrefix+cveID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+
+	c := models.EpssScore{}
+	j, ok := result.Val()["EPSS"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &c); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetEpssMulti :
+func (r *RedisDriver) GetEpssMulti(cveIDs []string) map[string]models.EpssScore {
+	ctx := context.Background()
+	rs := map[string]*redis.StringCmd{}
+
+	pipe := r.conn.Pipeline()
+	for _, cveID := range cveIDs {
+		rs[cveID] = pipe.HGet(ctx, hashKeyPrefix+cveID, "EPSS")
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		if err != redis.Nil {
+			r.log.Error("Failed to get EPSS.", "err", err)
+			return nil
+		}
+	}
+
+	m := map[string]models.EpssScore{}
+	for cveID, result := range rs {
+		if result.Err() != nil {
+			continue
+		}
+		var s models.EpssScore
+		if err := json.Unmarshal([]byte(result.Val()), &s); err != nil {
+			r.log.Error("Failed to Unmarshal json.", "err", err)
+			return nil
+		}
+		m[cveID] = s
+	}
+	return m
+}
+
+// GetExploits :
+func (r *RedisDriver) GetExploits(cveID string) []models.Exploit {
+	ctx := context.Background()
+	var result *redis.StringCmd
+	if result = r.conn.HGet(ctx, hashKeyPrefix+cveID, "EXPLOIT"); result.Err() != nil {
+		if result.Err() != redis.Nil {
+			r.log.Error("Failed to get exploits.", "err", result.Err())
+			return nil
+		}
+		return []models.Exploit{}
+	}
+
+	exploits := []models.Exploit{}
+	if err := json.Unmarshal([]byte(result.Val()), &exploits); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return exploits
+}
+
+// GetExploitsMulti :
+func (r *RedisDriver) GetExploitsMulti(cveIDs []string) map[string][]models.Exploit {
+	ctx := context.Background()
+	rs := map[string]*redis.StringCmd{}
+
+	pipe := r.conn.Pipeline()
+	for _, cveID := range cveIDs {
+		rs[cveID] = pipe.HGet(ctx, hashKeyPrefix+cveID, "EXPLOIT")
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		if err != redis.Nil {
+			r.log.Error("Failed to get exploits.", "err", err)
+			return nil
+		}
+	}
+
+	m := map[string][]models.Exploit{}
+	for cveID, result := range rs {
+		if result.Err() != nil {
+			continue
+		}
+		var exploits []models.Exploit
+		if err := json.Unmarshal([]byte(result.Val()), &exploits); err != nil {
+			r.log.Error("Failed to Unmarshal json.", "err", err)
+			return nil
+		}
+		m[cveID] = exploits
+	}
+	return m
+}
+
+// InsertExploit :
+func (r *RedisDriver) InsertExploit(exploitDB []models.ExploitDBCSV, metasploit []models.MetasploitModuleJSON) (err error) {
+	ctx := context.Background()
+	exploits := r.filter.filterExploit(ConvertExploit(exploitDB, metasploit, r.warnings))
+
+	cveIDs := []string{}
+	byCveID := map[string][]models.Exploit{}
+	for _, e := range exploits {
+		if _, ok := byCveID[e.CveID]; !ok {
+			cveIDs = append(cveIDs, e.CveID)
+		}
+		byCveID[e.CveID] = append(byCveID[e.CveID], e)
+	}
+	bar := startProgress(r.insert.Progress, len(cveIDs))
+
+	for _, cveID := range cveIDs {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(byCveID[cveID])
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cveID
+		if result := pipe.HSet(ctx, key, "EXPLOIT", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetCwe :
+func (r *RedisDriver) GetCwe(cweID string) *models.Cwe {
+	ctx := context.Background()
+	c := models.Cwe{}
+	var result *redis.StringCmd
+	if result = r.conn.HGet(ctx, hashCweKey, cweID); result.Err() != nil {
+		if result.Err() != redis.Nil {
+			r.log.Error("Failed to get CWE.", "err", result.Err())
+			return nil
+		}
+		return &c
+	}
+
+	if err := json.Unmarshal([]byte(result.Val()), &c); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// GetCweMulti :
+func (r *RedisDriver) GetCweMulti(cweIDs []string) map[string]models.Cwe {
+	ctx := context.Background()
+	m := map[string]models.Cwe{}
+	if len(cweIDs) == 0 {
+		return m
+	}
+
+	var result *redis.SliceCmd
+	if result = r.conn.HMGet(ctx, hashCweKey, cweIDs...); result.Err() != nil {
+		r.log.Error("Failed to get CWE.", "err", result.Err())
+		return nil
+	}
+	for _, v := range result.Val() {
+		j, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var c models.Cwe
+		if err := json.Unmarshal([]byte(j), &c); err != nil {
+			r.log.Error("Failed to Unmarshal json.", "err", err)
+			return nil
+		}
+		m[c.CweID] = c
+	}
+	return m
+}
+
+// InsertCwe :
+func (r *RedisDriver) InsertCwe(catalog *models.CweCatalogXML) (err error) {
+	ctx := context.Background()
+	cwes := ConvertCwe(catalog)
+	bar := startProgress(r.insert.Progress, len(cwes))
+
+	pipe := r.conn.Pipeline()
+	for _, c := range cwes {
+		bar.Add(1)
+
+		j, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+		if result := pipe.HSet(ctx, hashCweKey, c.CweID, string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CWE. err: %s", result.Err())
+		}
+	}
+	if r.insert.TTL > 0 {
+		if err := pipe.Expire(ctx, hashCweKey, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+			return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+		}
+	} else {
+		if err := pipe.Persist(ctx, hashCweKey).Err(); err != nil {
+			return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+		}
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+	}
+	bar.Finish()
+	return nil
+}
+
+// InsertEpss :
+func (r *RedisDriver) InsertEpss(epssCSV *models.EpssCSV) (err error) {
+	ctx := context.Background()
+	scores := r.filter.filterEpss(ConvertEpss(epssCSV))
+	bar := startProgress(r.insert.Progress, len(scores))
+
+	for _, score := range scores {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(score)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + score.CveID
+		if result := pipe.HSet(ctx, key, "EPSS", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetGhsa :
+func (r *RedisDriver) GetGhsa(ghsaID string) *models.GhsaAdvisory {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashGhsaPrefix+ghsaID); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+
+	a := models.GhsaAdvisory{}
+	j, ok := result.Val()["GHSA"]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(j), &a); err != nil {
+		r.log.Error("Failed to Unmarshal json.", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetGhsaByCveID :
+func (r *RedisDriver) GetGhsaByCveID(cveID string) map[string]models.GhsaAdvisory {
+	ctx := context.Background()
+	m := map[string]models.GhsaAdvisory{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindGhsaCvePrefix+cveID, 0, -1); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, ghsaID := range result.Val() {
+		a := r.GetGhsa(ghsaID)
+		if a == nil {
+			r.log.Error("GHSA is not found", "GHSA-ID", ghsaID)
+			continue
+		}
+		m[ghsaID] = *a
+	}
+	return m
+}
+
+// GetGhsaByPackage :
+func (r *RedisDriver) GetGhsaByPackage(ecosystem, pkgName string) map[string]models.GhsaAdvisory {
+	ctx := context.Background()
+	m := map[string]models.GhsaAdvisory{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindGhsaPackagePrefix+ecosystem+"#"+pkgName, 0, -1); result.Err() != nil {
+		log.Error(result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, ghsaID := range result.Val() {
+		a := r.GetGhsa(ghsaID)
+		if a == nil {
+			r.log.Error("GHSA is not found", "GHSA-ID", ghsaID)
+			continue
+		}
+
+		vulns := []models.GhsaVulnerability{}
+		for _, v := range a.Vulnerabilities {
+			if v.Ecosystem == ecosystem && v.PackageName == pkgName {
+				vulns = append(vulns, v)
+			}
+		}
+		if len(vulns) != 0 {
+			a.Vulnerabilities = vulns
+			m[ghsaID] = *a
+		}
+	}
+	return m
+}
+
+// InsertGhsa :
+func (r *RedisDriver) InsertGhsa(vulnJSONs []models.GhsaVulnerabilityJSON) (err error) {
+	ctx := context.Background()
+	advisories := r.filter.filterGhsa(ConvertGhsa(vulnJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(advisories))
+
+	for _, a := range advisories {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		keys := []string{hashGhsaPrefix + a.GhsaID}
+		if result := pipe.HSet(ctx, keys[0], "GHSA", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet GHSA. err: %s", result.Err())
+		}
+
+		for _, v := range a.Vulnerabilities {
+			keys = append(keys, zindGhsaPackagePrefix+v.Ecosystem+"#"+v.PackageName)
+		}
+		for _, id := range a.Identifiers {
+			if id.Type == "CVE" {
+				keys = append(keys, zindGhsaCvePrefix+id.Value)
+			}
+		}
+		for _, key := range keys[1:] {
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: a.GhsaID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd GHSA-ID. err: %s", result.Err())
+			}
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetOsvByPackage :
+func (r *RedisDriver) GetOsvByPackage(ecosystem, pkgName string) map[string]models.OsvEntry {
+	ctx := context.Background()
+	m := map[string]models.OsvEntry{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindOsvPackagePrefix+ecosystem+"#"+pkgName, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get OSV by package", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, osvID := range result.Val() {
+		res := r.conn.HGet(ctx, hashOsvPrefix+osvID, "OSV")
+		if res.Err() != nil {
+			r.log.Error("OSV is not found", "OSV-ID", osvID, "err", res.Err())
+			continue
+		}
+		m[osvID] = models.OsvEntry{
+			OsvID:    osvID,
+			Raw:      res.Val(),
+			Packages: []models.OsvPackage{{Ecosystem: ecosystem, PackageName: pkgName}},
+		}
+	}
+	return m
+}
+
+// InsertOsv :
+func (r *RedisDriver) InsertOsv(vulnJSONs []models.OsvJSON) (err error) {
+	ctx := context.Background()
+	entries := r.filter.filterOsv(ConvertOsv(vulnJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(entries))
+
+	for _, e := range entries {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		keys := []string{hashOsvPrefix + e.OsvID}
+		if result := pipe.HSet(ctx, keys[0], "OSV", e.Raw); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet OSV. err: %s", result.Err())
+		}
+
+		for _, p := range e.Packages {
+			key := zindOsvPackagePrefix + p.Ecosystem + "#" + p.PackageName
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: e.OsvID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd OSV-ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetGoVulnsByModule :
+func (r *RedisDriver) GetGoVulnsByModule(modulePath string) map[string]models.GoVuln {
+	ctx := context.Background()
+	m := map[string]models.GoVuln{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindGoVulnModulePrefix+modulePath, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get Go vulnerabilities by module", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, goID := range result.Val() {
+		res := r.conn.HGet(ctx, hashGoVulnPrefix+goID, modulePath)
+		if res.Err() != nil {
+			r.log.Error("Go vulnerability is not found", "GO-ID", goID, "err", res.Err())
+			continue
+		}
+		v := models.GoVuln{}
+		if err := json.Unmarshal([]byte(res.Val()), &v); err != nil {
+			r.log.Error("Failed to Unmarshal json", "err", err)
+			return nil
+		}
+		m[goID] = v
+	}
+	return m
+}
+
+// InsertGoVuln :
+func (r *RedisDriver) InsertGoVuln(vulnJSONs []models.GoVulnJSON) (err error) {
+	ctx := context.Background()
+	vulns := r.filter.filterGoVuln(ConvertGoVuln(vulnJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(vulns))
+
+	for _, v := range vulns {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		keys := []string{hashGoVulnPrefix + v.GoID, zindGoVulnModulePrefix + v.ModulePath}
+		if result := pipe.HSet(ctx, keys[0], v.ModulePath, string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet Go vulnerability. err: %s", result.Err())
+		}
+		if result := pipe.ZAdd(
+			ctx,
+			keys[1],
+			&redis.Z{Score: 0, Member: v.GoID},
+		); result.Err() != nil {
+			return fmt.Errorf("Failed to ZAdd GO-ID. err: %s", result.Err())
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetJvn :
+func (r *RedisDriver) GetJvn(jvnID string) *models.JvnAdvisory {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashJvnPrefix+jvnID); result.Err() != nil {
+		r.log.Error("Failed to get JVN", "err", result.Err())
+		return nil
+	}
+
+	a := models.JvnAdvisory{}
+	j, ok := result.Val()["JVN"]
+	if !ok {
+		return &a
+	}
+	if err := json.Unmarshal([]byte(j), &a); err != nil {
+		r.log.Error("Failed to Unmarshal json", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetJvnByCveID :
+func (r *RedisDriver) GetJvnByCveID(cveID string) map[string]models.JvnAdvisory {
+	ctx := context.Background()
+	m := map[string]models.JvnAdvisory{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindJvnCvePrefix+cveID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get JVN by CVE-ID", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, jvnID := range result.Val() {
+		a := r.GetJvn(jvnID)
+		if a == nil || a.JvnID == "" {
+			r.log.Error("JVN is not found", "JVN-ID", jvnID)
+			continue
+		}
+		m[jvnID] = *a
+	}
+	return m
+}
+
+// InsertJvn :
+func (r *RedisDriver) InsertJvn(items []models.JvnItemXML) (err error) {
+	ctx := context.Background()
+	advisories := r.filter.filterJvn(ConvertJvn(items, r.warnings))
+	bar := startProgress(r.insert.Progress, len(advisories))
+
+	for _, a := range advisories {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		keys := []string{hashJvnPrefix + a.JvnID}
+		if result := pipe.HSet(ctx, keys[0], "JVN", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet JVN. err: %s", result.Err())
+		}
+
+		for _, c := range a.CveIDs {
+			key := zindJvnCvePrefix + c.CveID
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: a.JvnID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd JVN-ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetPsirt :
+func (r *RedisDriver) GetPsirt(vendor, advisoryID string) *models.PsirtAdvisory {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, fmt.Sprintf("%s%s#%s", hashPsirtPrefix, vendor, advisoryID)); result.Err() != nil {
+		r.log.Error("Failed to get PSIRT advisory", "err", result.Err())
+		return nil
+	}
+
+	a := models.PsirtAdvisory{}
+	j, ok := result.Val()["PSIRT"]
+	if !ok {
+		return &a
+	}
+	if err := json.Unmarshal([]byte(j), &a); err != nil {
+		r.log.Error("Failed to Unmarshal json", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetPsirtByCveID :
+func (r *RedisDriver) GetPsirtByCveID(cveID string) map[string]models.PsirtAdvisory {
+	ctx := context.Background()
+	m := map[string]models.PsirtAdvisory{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindPsirtCvePrefix+cveID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get PSIRT advisories by CVE-ID", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, member := range result.Val() {
+		ss := strings.SplitN(member, "#", 2)
+		if len(ss) != 2 {
+			continue
+		}
+		a := r.GetPsirt(ss[0], ss[1])
+		if a == nil || a.AdvisoryID == "" {
+			r.log.Error("PSIRT advisory is not found", "vendor", ss[0], "ID", ss[1])
+			continue
+		}
+		m[a.AdvisoryID] = *a
+	}
+	return m
+}
+
+// GetPsirtByProduct :
+func (r *RedisDriver) GetPsirtByProduct(vendor, product string) map[string]models.PsirtAdvisory {
+	ctx := context.Background()
+	m := map[string]models.PsirtAdvisory{}
+	product = NormalizePsirtProduct(product)
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, fmt.Sprintf("%s%s#%s", zindPsirtProductPrefix, vendor, product), 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get PSIRT advisories by product", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, advisoryID := range result.Val() {
+		a := r.GetPsirt(vendor, advisoryID)
+		if a == nil || a.AdvisoryID == "" {
+			r.log.Error("PSIRT advisory is not found", "vendor", vendor, "ID", advisoryID)
+			continue
+		}
+		products := []models.PsirtProduct{}
+		for _, p := range a.Products {
+			if p.Product == product {
+				products = append(products, p)
+			}
+		}
+		a.Products = products
+		m[advisoryID] = *a
+	}
+	return m
+}
+
+// InsertPsirt :
+func (r *RedisDriver) InsertPsirt(vendor string, advisories []models.PsirtAdvisory) (err error) {
+	ctx := context.Background()
+	advisories = r.filter.filterPsirt(advisories)
+	bar := startProgress(r.insert.Progress, len(advisories))
+
+	for _, a := range advisories {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		keys := []string{fmt.Sprintf("%s%s#%s", hashPsirtPrefix, vendor, a.AdvisoryID)}
+		if result := pipe.HSet(ctx, keys[0], "PSIRT", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet PSIRT advisory. err: %s", result.Err())
+		}
+
+		for _, c := range a.CveIDs {
+			key := zindPsirtCvePrefix + c.CveID
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: fmt.Sprintf("%s#%s", vendor, a.AdvisoryID)},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd advisory ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, p := range a.Products {
+			key := fmt.Sprintf("%s%s#%s", zindPsirtProductPrefix, vendor, p.Product)
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: a.AdvisoryID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd advisory ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetApple :
+func (r *RedisDriver) GetApple(releaseID string) *models.AppleSecurityRelease {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, hashApplePrefix+releaseID); result.Err() != nil {
+		r.log.Error("Failed to get Apple security release", "err", result.Err())
+		return nil
+	}
+
+	a := models.AppleSecurityRelease{}
+	j, ok := result.Val()["APPLE"]
+	if !ok {
+		return &a
+	}
+	if err := json.Unmarshal([]byte(j), &a); err != nil {
+		r.log.Error("Failed to Unmarshal json", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetAppleByCveID :
+func (r *RedisDriver) GetAppleByCveID(cveID string) map[string]models.AppleSecurityRelease {
+	ctx := context.Background()
+	m := map[string]models.AppleSecurityRelease{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindAppleCvePrefix+cveID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get Apple security releases by CVE-ID", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, releaseID := range result.Val() {
+		a := r.GetApple(releaseID)
+		if a == nil || a.ReleaseID == "" {
+			r.log.Error("Apple security release is not found", "ID", releaseID)
+			continue
+		}
+		m[a.ReleaseID] = *a
+	}
+	return m
+}
+
+// GetAppleByProduct :
+func (r *RedisDriver) GetAppleByProduct(product string) map[string]models.AppleSecurityRelease {
+	ctx := context.Background()
+	m := map[string]models.AppleSecurityRelease{}
+	product = strings.ToLower(product)
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindAppleProductPrefix+product, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get Apple security releases by product", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, releaseID := range result.Val() {
+		a := r.GetApple(releaseID)
+		if a == nil || a.ReleaseID == "" {
+			r.log.Error("Apple security release is not found", "ID", releaseID)
+			continue
+		}
+		products := []models.AppleProduct{}
+		for _, p := range a.Products {
+			if p.Product == product {
+				products = append(products, p)
+			}
+		}
+		a.Products = products
+		m[releaseID] = *a
+	}
+	return m
+}
+
+// InsertApple :
+func (r *RedisDriver) InsertApple(releases []models.AppleSecurityRelease) (err error) {
+	ctx := context.Background()
+	releases = r.filter.filterApple(releases)
+	bar := startProgress(r.insert.Progress, len(releases))
+
+	for _, a := range releases {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		keys := []string{hashApplePrefix + a.ReleaseID}
+		if result := pipe.HSet(ctx, keys[0], "APPLE", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet Apple security release. err: %s", result.Err())
+		}
+
+		for _, c := range a.CveIDs {
+			key := zindAppleCvePrefix + c.CveID
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: a.ReleaseID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd release ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, p := range a.Products {
+			key := zindAppleProductPrefix + p.Product
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: a.ReleaseID},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd release ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetCn :
+func (r *RedisDriver) GetCn(source, advisoryID string) *models.CnAdvisory {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, fmt.Sprintf("%s%s#%s", hashCnPrefix, source, advisoryID)); result.Err() != nil {
+		r.log.Error("Failed to get CNNVD/CNVD advisory", "err", result.Err())
+		return nil
+	}
+
+	a := models.CnAdvisory{}
+	j, ok := result.Val()["CN"]
+	if !ok {
+		return &a
+	}
+	if err := json.Unmarshal([]byte(j), &a); err != nil {
+		r.log.Error("Failed to Unmarshal json", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetCnByCveID :
+func (r *RedisDriver) GetCnByCveID(cveID string) map[string]models.CnAdvisory {
+	ctx := context.Background()
+	m := map[string]models.CnAdvisory{}
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindCnCvePrefix+cveID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get CNNVD/CNVD advisories by CVE-ID", "err", result.Err())
+		return nil
+	}
+
+	r.explain.addCandidates(len(result.Val()))
+	for _, member := range result.Val() {
+		ss := strings.SplitN(member, "#", 2)
+		if len(ss) != 2 {
+			continue
+		}
+		a := r.GetCn(ss[0], ss[1])
+		if a == nil || a.AdvisoryID == "" {
+			r.log.Error("CNNVD/CNVD advisory is not found", "source", ss[0], "ID", ss[1])
+			continue
+		}
+		m[a.AdvisoryID] = *a
+	}
+	return m
+}
+
+// InsertCn :
+func (r *RedisDriver) InsertCn(source string, advisories []models.CnAdvisory) (err error) {
+	ctx := context.Background()
+	advisories = r.filter.filterCn(advisories)
+	bar := startProgress(r.insert.Progress, len(advisories))
+
+	for _, a := range advisories {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		keys := []string{fmt.Sprintf("%s%s#%s", hashCnPrefix, source, a.AdvisoryID)}
+		if result := pipe.HSet(ctx, keys[0], "CN", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CNNVD/CNVD advisory. err: %s", result.Err())
+		}
+
+		for _, c := range a.CveIDs {
+			key := zindCnCvePrefix + c.CveID
+			if result := pipe.ZAdd(
+				ctx,
+				key,
+				&redis.Z{Score: 0, Member: fmt.Sprintf("%s#%s", source, a.AdvisoryID)},
+			); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd advisory ID. err: %s", result.Err())
+			}
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
+
+// GetPackageAdvisory :
+func (r *RedisDriver) GetPackageAdvisory(source, advisoryID string) *models.PackageAdvisory {
+	ctx := context.Background()
+	var result *redis.StringStringMapCmd
+	if result = r.conn.HGetAll(ctx, fmt.Sprintf("%s%s#%s", hashPackageAdvisoryPrefix, source, advisoryID)); result.Err() != nil {
+		r.log.Error("Failed to get package advisory", "err", result.Err())
+		return nil
+	}
+
+	a := models.PackageAdvisory{}
+	j, ok := result.Val()["PKGADV"]
+	if !ok {
+		return &a
+	}
+	if err := json.Unmarshal([]byte(j), &a); err != nil {
+		r.log.Error("Failed to Unmarshal json", "err", err)
+		return nil
+	}
+	return &a
+}
+
+// GetPackageAdvisoriesByCveID :
+func (r *RedisDriver) GetPackageAdvisoriesByCveID(cveID string) map[string]models.PackageAdvisory {
+	ctx := context.Background()
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, zindPackageAdvisoryCvePrefix+cveID, 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get package advisories by CVE-ID", "err", result.Err())
+		return nil
+	}
+	return r.getPackageAdvisoriesByMembers(result.Val())
+}
+
+// GetPackageAdvisoriesByPackage :
+func (r *RedisDriver) GetPackageAdvisoriesByPackage(ecosystem, pkgName string) map[string]models.PackageAdvisory {
+	ctx := context.Background()
+	var result *redis.StringSliceCmd
+	if result = r.conn.ZRange(ctx, fmt.Sprintf("%s%s#%s", zindPackageAdvisoryPkgPrefix, ecosystem, pkgName), 0, -1); result.Err() != nil {
+		r.log.Error("Failed to get package advisories by package", "err", result.Err())
+		return nil
+	}
+
+	m := r.getPackageAdvisoriesByMembers(result.Val())
+	for id, a := range m {
+		vulns := []models.PackageAdvisoryVulnerability{}
+		for _, v := range a.Vulnerabilities {
+			if v.Ecosystem == ecosystem && v.PackageName == pkgName {
+				vulns = append(vulns, v)
+			}
+		}
+		a.Vulnerabilities = vulns
+		m[id] = a
+	}
+	return m
+}
+
+// getPackageAdvisoriesByMembers gets the advisories of the members of the ZINDEX ($SOURCE#$ID)
+func (r *RedisDriver) getPackageAdvisoriesByMembers(members []string) map[string]models.PackageAdvisory {
+	m := map[string]models.PackageAdvisory{}
+	r.explain.addCandidates(len(members))
+	for _, member := range members {
+		ss := strings.SplitN(member, "#", 2)
+		if len(ss) != 2 {
+			continue
+		}
+		a := r.GetPackageAdvisory(ss[0], ss[1])
+		if a == nil || a.AdvisoryID == "" {
+			r.log.Error("Package advisory is not found", "source", ss[0], "ID", ss[1])
+			continue
+		}
+		m[a.AdvisoryID] = *a
+	}
+	return m
+}
+
+// InsertPackageAdvisories :
+func (r *RedisDriver) InsertPackageAdvisories(source string, advisories []models.PackageAdvisory) (err error) {
+	ctx := context.Background()
+	advisories = r.filter.filterPackageAdvisories(advisories)
+	bar := startProgress(r.insert.Progress, len(advisories))
+
+	for _, a := range advisories {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		member := fmt.Sprintf("%s#%s", source, a.AdvisoryID)
+		keys := []string{hashPackageAdvisoryPrefix + member}
+		if result := pipe.HSet(ctx, keys[0], "PKGADV", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet package advisory. err: %s", result.Err())
+		}
+
+		for _, c := range a.CveIDs {
+			keys = append(keys, zindPackageAdvisoryCvePrefix+c.CveID)
+		}
+		for _, v := range a.Vulnerabilities {
+			keys = append(keys, fmt.Sprintf("%s%s#%s", zindPackageAdvisoryPkgPrefix, v.Ecosystem, v.PackageName))
+		}
+		for _, key := range keys[1:] {
+			if result := pipe.ZAdd(ctx, key, &redis.Z{Score: 0, Member: member}); result.Err() != nil {
+				return fmt.Errorf("Failed to ZAdd advisory ID. err: %s", result.Err())
+			}
+		}
+
+		for _, key := range keys {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
 	}
-	bar := pb.StartNew(len(cves))
+	bar.Finish()
+	return nil
+}
+
+// UpsertRedhat :
+// Redis overwrites the fields of the given CVEs on insert, so it is the same as InsertRedhat.
+func (r *RedisDriver) UpsertRedhat(cveJSONs []models.RedhatCVEJSON) error {
+	r.seenIDs.markPartial("RedHat")
+	return r.InsertRedhat(cveJSONs)
+}
+
+// UpsertDebian :
+// Redis overwrites the fields of the given CVEs on insert, so it is the same as InsertDebian.
+func (r *RedisDriver) UpsertDebian(cveJSONs models.DebianJSON, advisoryJSONs []models.DebianAdvisoryJSON) error {
+	r.seenIDs.markPartial("Debian")
+	return r.InsertDebian(cveJSONs, advisoryJSONs)
+}
+
+// UpsertUbuntu :
+// Redis overwrites the fields of the given CVEs on insert, so it is the same as InsertUbuntu.
+func (r *RedisDriver) UpsertUbuntu(cveJSONs []models.UbuntuCVEJSON) error {
+	r.seenIDs.markPartial("Ubuntu")
+	return r.InsertUbuntu(cveJSONs)
+}
+
+// UpsertMicrosoft :
+// Redis overwrites the fields of the given CVEs on insert, so it is the same as InsertMicrosoft without the bulletins.
+func (r *RedisDriver) UpsertMicrosoft(cveXMLs []models.MicrosoftXML) error {
+	r.seenIDs.markPartial("Microsoft")
+	return r.InsertMicrosoft(cveXMLs, nil)
+}
+
+// InsertAmazon :
+func (r *RedisDriver) InsertAmazon(alasJSONs []models.AmazonALASJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterAmazon(ConvertAmazon(alasJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
 
 	for _, cve := range cves {
 		pipe := r.conn.Pipeline()
-		bar.Increment()
+		bar.Add(1)
 
 		j, err := json.Marshal(cve)
 		if err != nil {
 			return fmt.Errorf("Failed to marshal json. err: %s", err)
 		}
 
-		key := hashKeyPrefix + cve.Name
-		if result := pipe.HSet(ctx, key, "RedHat", string(j)); result.Err() != nil {
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Amazon", string(j)); result.Err() != nil {
 			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
 		}
-		if expire > 0 {
-			if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 			}
 		} else {
@@ -458,17 +3958,19 @@ func (r *RedisDriver) InsertRedhat(cveJSONs []models.RedhatCVEJSON) (err error)
 			}
 		}
 
-		for _, pkg := range cve.PackageState {
-			key := zindRedHatPrefix + pkg.PackageName
-			if result := pipe.ZAdd(
-				ctx,
-				key,
-				&redis.Z{Score: 0, Member: cve.Name},
-			); result.Err() != nil {
-				return fmt.Errorf("Failed to ZAdd pkg name. err: %s", result.Err())
+		pkgNames := map[string]struct{}{}
+		for _, a := range cve.Advisories {
+			for _, p := range a.Packages {
+				pkgNames[p.PackageName] = struct{}{}
+			}
+		}
+		for pkgName := range pkgNames {
+			key := zindAmazonPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
 			}
-			if expire > 0 {
-				if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 				}
 			} else {
@@ -483,21 +3985,136 @@ func (r *RedisDriver) InsertRedhat(cveJSONs []models.RedhatCVEJSON) (err error)
 		}
 	}
 	bar.Finish()
+	return nil
+}
+
+// InsertRocky :
+func (r *RedisDriver) InsertRocky(advisories []models.RockyAdvisoryJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterRocky(ConvertRocky(advisories, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Rocky", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
 
+		pkgNames := map[string]struct{}{}
+		for _, a := range cve.Advisories {
+			for _, p := range a.Packages {
+				pkgNames[p.PackageName] = struct{}{}
+			}
+		}
+		for pkgName := range pkgNames {
+			key := zindRockyPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
 	return nil
 }
 
-// InsertDebian :
-func (r *RedisDriver) InsertDebian(cveJSONs models.DebianJSON) error {
-	expire := viper.GetUint("expire")
+// InsertAlma :
+func (r *RedisDriver) InsertAlma(errata []models.AlmaErrataJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterAlma(ConvertAlma(errata))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Alma", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		pkgNames := map[string]struct{}{}
+		for _, a := range cve.Advisories {
+			for _, p := range a.Packages {
+				pkgNames[p.PackageName] = struct{}{}
+			}
+		}
+		for pkgName := range pkgNames {
+			key := zindAlmaPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
 
+// InsertOracle :
+func (r *RedisDriver) InsertOracle(elsaJSONs []models.OracleOVALJSON) (err error) {
 	ctx := context.Background()
-	cves := ConvertDebian(cveJSONs)
-	bar := pb.StartNew(len(cves))
+	cves := r.filter.filterOracle(ConvertOracle(elsaJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
 
 	for _, cve := range cves {
 		pipe := r.conn.Pipeline()
-		bar.Increment()
+		bar.Add(1)
 
 		j, err := json.Marshal(cve)
 		if err != nil {
@@ -505,11 +4122,11 @@ func (r *RedisDriver) InsertDebian(cveJSONs models.DebianJSON) error {
 		}
 
 		key := hashKeyPrefix + cve.CveID
-		if result := pipe.HSet(ctx, key, "Debian", string(j)); result.Err() != nil {
+		if result := pipe.HSet(ctx, key, "Oracle", string(j)); result.Err() != nil {
 			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
 		}
-		if expire > 0 {
-			if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 			}
 		} else {
@@ -518,17 +4135,19 @@ func (r *RedisDriver) InsertDebian(cveJSONs models.DebianJSON) error {
 			}
 		}
 
-		for _, pkg := range cve.Package {
-			key := zindDebianPrefix + pkg.PackageName
-			if result := pipe.ZAdd(
-				ctx,
-				key,
-				&redis.Z{Score: 0, Member: cve.CveID},
-			); result.Err() != nil {
-				return fmt.Errorf("Failed to ZAdd pkg name. err: %s", result.Err())
+		pkgNames := map[string]struct{}{}
+		for _, a := range cve.Advisories {
+			for _, p := range a.Packages {
+				pkgNames[p.PackageName] = struct{}{}
+			}
+		}
+		for pkgName := range pkgNames {
+			key := zindOraclePrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
 			}
-			if expire > 0 {
-				if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 				}
 			} else {
@@ -546,29 +4165,84 @@ func (r *RedisDriver) InsertDebian(cveJSONs models.DebianJSON) error {
 	return nil
 }
 
-// InsertUbuntu :
-func (r *RedisDriver) InsertUbuntu(cveJSONs []models.UbuntuCVEJSON) (err error) {
-	expire := viper.GetUint("expire")
+// InsertAlpine :
+func (r *RedisDriver) InsertAlpine(secdbs []models.AlpineSecDBJSON) (err error) {
+	ctx := context.Background()
+	cves := r.filter.filterAlpine(ConvertAlpine(secdbs))
+	bar := startProgress(r.insert.Progress, len(cves))
+
+	for _, cve := range cves {
+		pipe := r.conn.Pipeline()
+		bar.Add(1)
+
+		j, err := json.Marshal(cve)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal json. err: %s", err)
+		}
+
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Alpine", string(j)); result.Err() != nil {
+			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
+		}
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+
+		pkgNames := map[string]struct{}{}
+		for _, p := range cve.Packages {
+			pkgNames[p.PackageName] = struct{}{}
+		}
+		for pkgName := range pkgNames {
+			key := zindAlpinePrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
+			}
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+				}
+			} else {
+				if err := pipe.Persist(ctx, key).Err(); err != nil {
+					return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+				}
+			}
+		}
+
+		if _, err = pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+		}
+	}
+	bar.Finish()
+	return nil
+}
 
+// InsertWolfi :
+func (r *RedisDriver) InsertWolfi(vulnJSONs []models.OsvJSON) (err error) {
 	ctx := context.Background()
-	cves := ConvertUbuntu(cveJSONs)
-	bar := pb.StartNew(len(cves))
+	cves := r.filter.filterWolfi(ConvertWolfi(vulnJSONs, r.warnings))
+	bar := startProgress(r.insert.Progress, len(cves))
 
 	for _, cve := range cves {
 		pipe := r.conn.Pipeline()
-		bar.Increment()
+		bar.Add(1)
 
 		j, err := json.Marshal(cve)
 		if err != nil {
 			return fmt.Errorf("Failed to marshal json. err: %s", err)
 		}
 
-		key := hashKeyPrefix + cve.Candidate
-		if result := pipe.HSet(ctx, key, "Ubuntu", string(j)); result.Err() != nil {
+		key := hashKeyPrefix + cve.CveID
+		if result := pipe.HSet(ctx, key, "Wolfi", string(j)); result.Err() != nil {
 			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
 		}
-		if expire > 0 {
-			if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 			}
 		} else {
@@ -577,17 +4251,17 @@ func (r *RedisDriver) InsertUbuntu(cveJSONs []models.UbuntuCVEJSON) (err error)
 			}
 		}
 
-		for _, pkg := range cve.Patches {
-			key := zindUbuntuPrefix + pkg.PackageName
-			if result := pipe.ZAdd(
-				ctx,
-				key,
-				&redis.Z{Score: 0, Member: cve.Candidate},
-			); result.Err() != nil {
-				return fmt.Errorf("Failed to ZAdd pkg name. err: %s", result.Err())
+		pkgNames := map[string]struct{}{}
+		for _, p := range cve.Packages {
+			pkgNames[p.PackageName] = struct{}{}
+		}
+		for pkgName := range pkgNames {
+			key := zindWolfiPrefix + pkgName
+			if err := r.addIndexMember(ctx, pipe, key, cve.CveID); err != nil {
+				return err
 			}
-			if expire > 0 {
-				if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 				}
 			} else {
@@ -607,11 +4281,10 @@ func (r *RedisDriver) InsertUbuntu(cveJSONs []models.UbuntuCVEJSON) (err error)
 
 // InsertMicrosoft :
 func (r *RedisDriver) InsertMicrosoft(cveXMLs []models.MicrosoftXML, xls []models.MicrosoftBulletinSearch) (err error) {
-	expire := viper.GetUint("expire")
-
 	ctx := context.Background()
-	cves, products := ConvertMicrosoft(cveXMLs, xls)
-	bar := pb.StartNew(len(cves))
+	cves, products := ConvertMicrosoft(cveXMLs, xls, r.warnings)
+	cves = r.filter.filterMicrosoft(cves)
+	bar := startProgress(r.insert.Progress, len(cves))
 
 	pipe := r.conn.Pipeline()
 	for _, p := range products {
@@ -623,8 +4296,8 @@ func (r *RedisDriver) InsertMicrosoft(cveXMLs []models.MicrosoftXML, xls []model
 		); result.Err() != nil {
 			return fmt.Errorf("Failed to ZAdd kbID. err: %s", result.Err())
 		}
-		if expire > 0 {
-			if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 			}
 		} else {
@@ -639,7 +4312,7 @@ func (r *RedisDriver) InsertMicrosoft(cveXMLs []models.MicrosoftXML, xls []model
 
 	for _, cve := range cves {
 		pipe := r.conn.Pipeline()
-		bar.Increment()
+		bar.Add(1)
 
 		j, err := json.Marshal(cve)
 		if err != nil {
@@ -650,8 +4323,8 @@ func (r *RedisDriver) InsertMicrosoft(cveXMLs []models.MicrosoftXML, xls []model
 		if result := pipe.HSet(ctx, key, "Microsoft", string(j)); result.Err() != nil {
 			return fmt.Errorf("Failed to HSet CVE. err: %s", result.Err())
 		}
-		if expire > 0 {
-			if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 			}
 		} else {
@@ -669,8 +4342,8 @@ func (r *RedisDriver) InsertMicrosoft(cveXMLs []models.MicrosoftXML, xls []model
 			); result.Err() != nil {
 				return fmt.Errorf("Failed to ZAdd kbID. err: %s", result.Err())
 			}
-			if expire > 0 {
-				if err := pipe.Expire(ctx, key, time.Duration(expire*uint(time.Second))).Err(); err != nil {
+			if r.insert.TTL > 0 {
+				if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
 					return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
 				}
 			} else {
@@ -685,5 +4358,80 @@ func (r *RedisDriver) InsertMicrosoft(cveXMLs []models.MicrosoftXML, xls []model
 		}
 	}
 	bar.Finish()
+
+	if err := r.insertMicrosoftCvrfDocuments(ctx, ConvertMicrosoftCvrfDocuments(cveXMLs)); err != nil {
+		return err
+	}
+	return r.insertMicrosoftKBs(ctx, cveXMLs, xls)
+}
+
+// insertMicrosoftKBs replaces the supersedences of the KBs in the documents, and sets the builds of the KBs
+func (r *RedisDriver) insertMicrosoftKBs(ctx context.Context, cveXMLs []models.MicrosoftXML, xls []models.MicrosoftBulletinSearch) error {
+	supersedences, builds := ConvertMicrosoftKBs(cveXMLs, xls)
+	superseded := map[string][]string{}
+	for _, s := range supersedences {
+		superseded[s.KBID] = append(superseded[s.KBID], s.SupersededKBID)
+	}
+
+	pipe := r.conn.Pipeline()
+	keys := map[string]bool{}
+	for kbID, kbIDs := range superseded {
+		key := zindMicrosoftKBPrefix + kbID
+		if err := pipe.Del(ctx, key).Err(); err != nil {
+			return fmt.Errorf("Failed to Del superseded KBs. err: %s", err)
+		}
+		for _, id := range kbIDs {
+			if err := pipe.ZAdd(ctx, key, &redis.Z{Score: 0, Member: id}).Err(); err != nil {
+				return fmt.Errorf("Failed to ZAdd superseded KB. err: %s", err)
+			}
+		}
+		keys[key] = true
+	}
+	for _, b := range builds {
+		key := hashMicrosoftKBBuildPrefix + b.ProductID
+		if err := pipe.HSet(ctx, key, b.KBID, b.FixedBuild).Err(); err != nil {
+			return fmt.Errorf("Failed to HSet the build of KB. err: %s", err)
+		}
+		keys[key] = true
+	}
+	for key := range keys {
+		if r.insert.TTL > 0 {
+			if err := pipe.Expire(ctx, key, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+				return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+			}
+		} else {
+			if err := pipe.Persist(ctx, key).Err(); err != nil {
+				return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+			}
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RedisDriver) insertMicrosoftCvrfDocuments(ctx context.Context, docs []models.MicrosoftCvrfDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	pipe := r.conn.Pipeline()
+	for _, d := range docs {
+		if err := pipe.HSet(ctx, hashMicrosoftCvrfKey, d.CvrfID, d.CurrentReleaseDate.Format(time.RFC3339)).Err(); err != nil {
+			return fmt.Errorf("Failed to HSet CVRF document. err: %s", err)
+		}
+	}
+	if r.insert.TTL > 0 {
+		if err := pipe.Expire(ctx, hashMicrosoftCvrfKey, time.Duration(r.insert.TTL*uint(time.Second))).Err(); err != nil {
+			return fmt.Errorf("Failed to set Expire to Key. err: %s", err)
+		}
+	} else {
+		if err := pipe.Persist(ctx, hashMicrosoftCvrfKey).Err(); err != nil {
+			return fmt.Errorf("Failed to remove the existing timeout on Key. err: %s", err)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("Failed to exec pipeline. err: %s", err)
+	}
 	return nil
 }
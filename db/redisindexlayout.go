@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/xerrors"
+)
+
+// Layouts of the indexes of the packages in Redis
+const (
+	// IndexLayoutZSet : ZSET with the score 0 (default)
+	IndexLayoutZSet = "zset"
+	// IndexLayoutSet : SET, which takes less memory per member on the very large indexes (e.g. kernel)
+	IndexLayoutSet = "set"
+)
+
+const metaFieldIndexLayout = "INDEXLAYOUT"
+
+// resolveIndexLayout sets the layout of the indexes to the one recorded in GOST#META.
+// The layout requested is recorded in an empty DB only, so that the server on a replica does not write.
+// The DB without the record (e.g. created before the layouts) is of ZSET.
+func (r *RedisDriver) resolveIndexLayout(ctx context.Context) error {
+	switch r.indexLayout {
+	case "", IndexLayoutZSet, IndexLayoutSet:
+	default:
+		return xerrors.Errorf("Unknown index layout: %s. It must be zset or set", r.indexLayout)
+	}
+
+	stored, err := r.conn.HGet(ctx, hashMetaKey, metaFieldIndexLayout).Result()
+	if err != nil && err != redis.Nil {
+		return xerrors.Errorf("Failed to get the index layout. err: %w", err)
+	}
+	if err == redis.Nil {
+		size, err := r.conn.DBSize(ctx).Result()
+		if err != nil {
+			return xerrors.Errorf("Failed to get the size of DB. err: %w", err)
+		}
+		stored = IndexLayoutZSet
+		if size == 0 && r.indexLayout != "" {
+			stored = r.indexLayout
+			if err := r.conn.HSet(ctx, hashMetaKey, metaFieldIndexLayout, stored).Err(); err != nil {
+				return xerrors.Errorf("Failed to record the index layout. err: %w", err)
+			}
+		}
+	}
+
+	if r.indexLayout != "" && r.indexLayout != stored {
+		return xerrors.Errorf("The indexes of the DB are stored as %s, not %s. Flush the DB to change the layout", stored, r.indexLayout)
+	}
+	if stored != IndexLayoutZSet && stored != IndexLayoutSet {
+		return xerrors.Errorf("Unknown index layout of the DB: %s", stored)
+	}
+	r.indexLayout = stored
+	r.log.Debug("Index layout of Redis", "layout", stored)
+	return nil
+}
+
+// addIndexMember adds the member to the index of the package in the layout of the DB
+func (r *RedisDriver) addIndexMember(ctx context.Context, pipe redis.Pipeliner, key, member string) error {
+	if r.indexLayout == IndexLayoutSet {
+		if err := pipe.SAdd(ctx, key, member).Err(); err != nil {
+			return fmt.Errorf("Failed to SAdd pkg name. err: %s", err)
+		}
+		return nil
+	}
+	if err := pipe.ZAdd(ctx, key, &redis.Z{Score: 0, Member: member}).Err(); err != nil {
+		return fmt.Errorf("Failed to ZAdd pkg name. err: %s", err)
+	}
+	return nil
+}
+
+// sscanChunked gets the members of the SET key by SSCAN of about multiGet.chunkSize members, and calls fn for each chunk.
+// SSCAN may return a member more than once, so the members returned already are skipped.
+func (r *RedisDriver) sscanChunked(ctx context.Context, key string, fn func(members []string) error) error {
+	seen := map[string]struct{}{}
+	var cursor uint64
+	for {
+		members, next, err := r.conn.SScan(ctx, key, cursor, "", int64(r.multiGet.chunkSize)).Result()
+		if err != nil {
+			return xerrors.Errorf("Failed to SSCAN %s. err: %w", key, err)
+		}
+		chunk := make([]string, 0, len(members))
+		for _, m := range members {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			chunk = append(chunk, m)
+		}
+		if len(chunk) > 0 {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+		}
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// redisPersistence is the configuration of Redis which decides whether the dataset survives a restart and a full memory
+type redisPersistence struct {
+	appendOnly      bool
+	save            string
+	maxMemory       string
+	maxMemoryPolicy string
+}
+
+// getRedisPersistence gets the configuration by CONFIG GET. It fails when CONFIG is disabled (e.g. some managed services)
+func (r *RedisDriver) getRedisPersistence(ctx context.Context) (redisPersistence, error) {
+	p := redisPersistence{}
+	for _, param := range []string{"appendonly", "save", "maxmemory", "maxmemory-policy"} {
+		vals, err := r.conn.ConfigGet(ctx, param).Result()
+		if err != nil {
+			return p, xerrors.Errorf("Failed to CONFIG GET %s. err: %w", param, err)
+		}
+		if len(vals) != 2 {
+			return p, xerrors.Errorf("Failed to CONFIG GET %s. Unknown reply: %v", param, vals)
+		}
+		v := fmt.Sprint(vals[1])
+		switch param {
+		case "appendonly":
+			p.appendOnly = v == "yes"
+		case "save":
+			p.save = strings.TrimSpace(v)
+		case "maxmemory":
+			p.maxMemory = v
+		case "maxmemory-policy":
+			p.maxMemoryPolicy = v
+		}
+	}
+	return p, nil
+}
+
+// problems returns why the dataset may be lost.
+// The keys of gost have no timeout unless --expire is set, so the volatile-* policies evict them only with the TTL.
+func (p redisPersistence) problems(ttl uint) []string {
+	problems := []string{}
+	if !p.appendOnly && p.save == "" {
+		problems = append(problems, "neither AOF (appendonly) nor RDB snapshots (save) are enabled, so the dataset is lost on restart")
+	}
+	if p.maxMemory != "" && p.maxMemory != "0" {
+		switch {
+		case strings.HasPrefix(p.maxMemoryPolicy, "allkeys-"):
+			problems = append(problems, fmt.Sprintf("maxmemory-policy is %s, so any key may be evicted when maxmemory is reached", p.maxMemoryPolicy))
+		case strings.HasPrefix(p.maxMemoryPolicy, "volatile-") && ttl > 0:
+			problems = append(problems, fmt.Sprintf("maxmemory-policy is %s and --expire is set, so the keys may be evicted when maxmemory is reached", p.maxMemoryPolicy))
+		}
+	}
+	return problems
+}
+
+// checkRedisPersistence warns when the instance is configured as a volatile cache, or fails if the persistence is required
+func (r *RedisDriver) checkRedisPersistence(ctx context.Context) error {
+	p, err := r.getRedisPersistence(ctx)
+	if err != nil {
+		if r.requirePersistence {
+			return xerrors.Errorf("Failed to check the persistence of Redis, which is required. err: %w", err)
+		}
+		r.log.Warn("Failed to check the persistence of Redis", "err", err)
+		return nil
+	}
+
+	problems := p.problems(r.insert.TTL)
+	if len(problems) == 0 {
+		return nil
+	}
+	if r.requirePersistence {
+		return xerrors.Errorf("Redis is configured as a volatile cache: %s", strings.Join(problems, "; "))
+	}
+	for _, problem := range problems {
+		r.log.Warn("Redis is configured as a volatile cache. Configure the persistence, or set --require-persistence to refuse it", "problem", problem)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// redisIndexEntry is a member of an index derived from CVE#$CVEID
+type redisIndexEntry struct {
+	key    string
+	member string
+	// pkg is whether the key is an index of a package, which is stored in the layout of the DB
+	pkg bool
+}
+
+// Reindex rebuilds the indexes of the CVEs (e.g. CVE#D#$PKGNAME, CVE#B#$BZID, CVE#K#$KBID, CVE#P#$PRODUCTID) from CVE#$CVEID,
+// so that the indexes evicted or lost can be repaired while the documents survive. The members are added to the existing indexes.
+// The indexes of the other documents (e.g. GHSA, OSV) and the TTL of the keys are not rebuilt.
+func (r *RedisDriver) Reindex() (models.ReindexReport, error) {
+	ctx := context.Background()
+	report := models.ReindexReport{Sources: map[string]int{}}
+	keys := map[string]struct{}{}
+
+	var cursor uint64
+	for {
+		cveKeys, next, err := r.conn.Scan(ctx, cursor, hashKeyPrefix+"CVE-*", int64(r.multiGet.chunkSize)).Result()
+		if err != nil {
+			return report, xerrors.Errorf("Failed to scan keys. err: %w", err)
+		}
+
+		cveIDs := make([]string, 0, len(cveKeys))
+		for _, key := range cveKeys {
+			cveIDs = append(cveIDs, strings.TrimPrefix(key, hashKeyPrefix))
+		}
+		hashes, err := r.hgetAllMulti(cveIDs)
+		if err != nil {
+			return report, err
+		}
+
+		pipe := r.conn.Pipeline()
+		for _, cveID := range cveIDs {
+			report.CVEs++
+			entries, failed := redisCveIndexEntries(cveID, hashes[cveID])
+			report.Failed += failed
+			for field, es := range entries {
+				report.Sources[field]++
+				for _, e := range es {
+					if err := r.addReindexMember(ctx, pipe, e); err != nil {
+						return report, err
+					}
+					keys[e.key] = struct{}{}
+					report.Members++
+				}
+			}
+		}
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return report, xerrors.Errorf("Failed to exec pipeline. err: %w", err)
+		}
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	report.Keys = len(keys)
+	return report, nil
+}
+
+func (r *RedisDriver) addReindexMember(ctx context.Context, pipe redis.Pipeliner, e redisIndexEntry) error {
+	if e.pkg {
+		return r.addIndexMember(ctx, pipe, e.key, e.member)
+	}
+	if err := pipe.ZAdd(ctx, e.key, &redis.Z{Score: 0, Member: e.member}).Err(); err != nil {
+		return fmt.Errorf("Failed to ZAdd index. err: %s", err)
+	}
+	return nil
+}
+
+// redisCveIndexEntries returns the members of the indexes of each document in CVE#$CVEID by the field, in the same way as Insert*,
+// and the number of the documents which cannot be decoded
+func redisCveIndexEntries(cveID string, hash map[string]string) (map[string][]redisIndexEntry, int) {
+	entries := map[string][]redisIndexEntry{}
+	failed := 0
+	addMember := func(field, key, member string, pkg bool) {
+		for _, e := range entries[field] {
+			if e.key == key && e.member == member {
+				return
+			}
+		}
+		entries[field] = append(entries[field], redisIndexEntry{key: key, member: member, pkg: pkg})
+	}
+	add := func(field, key string, pkg bool) {
+		addMember(field, key, cveID, pkg)
+	}
+	decoded := func(field string, ok bool) bool {
+		if _, has := hash[field]; !has {
+			return false
+		}
+		if !ok {
+			log15.Error("Failed to decode the document to reindex", "CVE-ID", cveID, "field", field)
+			failed++
+		}
+		return ok
+	}
+
+	if c := decodeRedhat(hash); decoded("RedHat", c != nil) {
+		if c.Bugzilla.BugzillaID != "" {
+			add("RedHat", zindRedHatBugzillaPrefix+c.Bugzilla.BugzillaID, false)
+		}
+		for _, p := range c.PackageState {
+			add("RedHat", zindRedHatPrefix+p.PackageName, true)
+		}
+		for _, p := range c.FixedPackages {
+			add("RedHat", zindRedHatFixedPrefix+p.Name, true)
+		}
+	}
+	if c := decodeDebian(hash); decoded("Debian", c != nil) {
+		for _, p := range c.Package {
+			if p.DebianBug != "" {
+				add("Debian", zindDebianBugPrefix+p.DebianBug, false)
+			}
+			add("Debian", zindDebianPrefix+p.PackageName, true)
+		}
+	}
+	if c := decodeUbuntu(hash); decoded("Ubuntu", c != nil) {
+		for _, b := range c.Bugs {
+			if b.BugID != "" {
+				add("Ubuntu", zindUbuntuBugPrefix+b.Tracker+"#"+b.BugID, false)
+			}
+		}
+		for _, p := range c.SnapPatches {
+			add("Ubuntu", zindUbuntuSnapPrefix+p.SnapName, false)
+		}
+		for _, p := range c.Patches {
+			add("Ubuntu", zindUbuntuPrefix+p.PackageName, true)
+		}
+	}
+	if j, ok := hash["UbuntuUSN"]; ok {
+		usns := []models.UbuntuUSN{}
+		if decoded("UbuntuUSN", json.Unmarshal([]byte(j), &usns) == nil) {
+			for _, usn := range usns {
+				add("UbuntuUSN", zindUbuntuUSNPrefix+usn.USNID, false)
+			}
+		}
+	}
+	if c := decodeAmazon(hash); decoded("Amazon", c != nil) {
+		for _, a := range c.Advisories {
+			for _, p := range a.Packages {
+				add("Amazon", zindAmazonPrefix+p.PackageName, true)
+			}
+		}
+	}
+	if c := decodeRocky(hash); decoded("Rocky", c != nil) {
+		for _, a := range c.Advisories {
+			for _, p := range a.Packages {
+				add("Rocky", zindRockyPrefix+p.PackageName, true)
+			}
+		}
+	}
+	if c := decodeAlma(hash); decoded("Alma", c != nil) {
+		for _, a := range c.Advisories {
+			for _, p := range a.Packages {
+				add("Alma", zindAlmaPrefix+p.PackageName, true)
+			}
+		}
+	}
+	if c := decodeOracle(hash); decoded("Oracle", c != nil) {
+		for _, a := range c.Advisories {
+			for _, p := range a.Packages {
+				add("Oracle", zindOraclePrefix+p.PackageName, true)
+			}
+		}
+	}
+	if c := decodeAlpine(hash); decoded("Alpine", c != nil) {
+		for _, p := range c.Packages {
+			add("Alpine", zindAlpinePrefix+p.PackageName, true)
+		}
+	}
+	if c := decodeWolfi(hash); decoded("Wolfi", c != nil) {
+		for _, p := range c.Packages {
+			add("Wolfi", zindWolfiPrefix+p.PackageName, true)
+		}
+	}
+	if c := decodePhoton(hash); decoded("Photon", c != nil) {
+		for _, p := range c.Packages {
+			add("Photon", zindPhotonPrefix+p.PackageName, true)
+		}
+	}
+	if c := decodeMariner(hash); decoded("Mariner", c != nil) {
+		for _, p := range c.Packages {
+			add("Mariner", zindMarinerPrefix+p.PackageName, true)
+		}
+	}
+	if c := decodeOpenEuler(hash); decoded("OpenEuler", c != nil) {
+		for _, a := range c.Advisories {
+			for _, p := range a.Packages {
+				add("OpenEuler", zindOpenEulerPrefix+p.PackageName, true)
+			}
+		}
+	}
+	if c := decodeAnolis(hash); decoded("Anolis", c != nil) {
+		for _, a := range c.Advisories {
+			for _, p := range a.Packages {
+				add("Anolis", zindAnolisPrefix+p.PackageName, true)
+			}
+		}
+	}
+	if j, ok := hash["Microsoft"]; ok {
+		c := models.MicrosoftCVE{}
+		if decoded("Microsoft", json.Unmarshal([]byte(j), &c) == nil) {
+			for _, kb := range c.KBIDs {
+				add("Microsoft", zindMicrosoftKBIDPrefix+kb.KBID, false)
+			}
+			// CVE#P#$PRODUCTID is the name of the product, not the CVE-ID
+			for _, s := range c.MicrosoftProductStatuses {
+				for _, p := range s.Products {
+					if p.ProductID != "" {
+						addMember("Microsoft", zindMicrosoftProductIDPrefix+p.ProductID, p.ProductName, false)
+					}
+				}
+			}
+		}
+	}
+	return entries, failed
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/xerrors"
+)
+
+// zrangeChunked gets the members of the ZINDEX key by ZRANGE of multiGet.chunkSize members, and calls fn for each chunk in order,
+// so that the index of a package with tens of thousands of CVEs (e.g. kernel) does not make a huge single reply.
+// The members have the same score, so they are in the lexicographical order. A member added or removed during the scan may be skipped or repeated.
+func (r *RedisDriver) zrangeChunked(ctx context.Context, key string, fn func(members []string) error) error {
+	chunkSize := int64(r.multiGet.chunkSize)
+	for start := int64(0); ; start += chunkSize {
+		members, err := r.conn.ZRange(ctx, key, start, start+chunkSize-1).Result()
+		if err != nil {
+			return xerrors.Errorf("Failed to ZRANGE %s. err: %w", key, err)
+		}
+		if len(members) > 0 {
+			if err := fn(members); err != nil {
+				return err
+			}
+		}
+		if int64(len(members)) < chunkSize {
+			return nil
+		}
+	}
+}
+
+// forEachIndexedCve calls fn with CVE#$CVEID of each CVE-ID in the index of the package (e.g. CVE#D#$PKGNAME) in the layout of the DB.
+// The hashes of a chunk of the index are got by hgetAllMulti before the next chunk is read, so the memory is bounded by the chunk.
+// The hash is empty if CVE#$CVEID does not exist.
+func (r *RedisDriver) forEachIndexedCve(key string, fn func(cveID string, hash map[string]string)) error {
+	scan := r.zrangeChunked
+	if r.indexLayout == IndexLayoutSet {
+		scan = r.sscanChunked
+	}
+	return scan(context.Background(), key, func(cveIDs []string) error {
+		r.explain.addCandidates(len(cveIDs))
+		hashes, err := r.hgetAllMulti(cveIDs)
+		if err != nil {
+			return err
+		}
+		for _, cveID := range cveIDs {
+			fn(cveID, hashes[cveID])
+		}
+		return nil
+	})
+}
+
+// indexedCvesMulti gets the CVE-IDs in the indexes of the packages (prefix+$PKGNAME) by a pipeline of ZRANGE (SMEMBERS in IndexLayoutSet),
+// and then the hashes of all of them by hgetAllMulti. The CVE-IDs are returned by package name.
+func (r *RedisDriver) indexedCvesMulti(prefix string, pkgNames []string) (map[string][]string, map[string]map[string]string, error) {
+	ctx := context.Background()
+	pipe := r.conn.Pipeline()
+	rs := make([]*redis.StringSliceCmd, len(pkgNames))
+	for i, pkgName := range pkgNames {
+		if r.indexLayout == IndexLayoutSet {
+			rs[i] = pipe.SMembers(ctx, prefix+pkgName)
+		} else {
+			rs[i] = pipe.ZRange(ctx, prefix+pkgName, 0, -1)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, nil, xerrors.Errorf("Failed to get the indexes of %s. err: %w", prefix, err)
+	}
+
+	ids := map[string][]string{}
+	uniq := map[string]struct{}{}
+	all := []string{}
+	for i, pkgName := range pkgNames {
+		ids[pkgName] = rs[i].Val()
+		for _, cveID := range rs[i].Val() {
+			if _, ok := uniq[cveID]; !ok {
+				uniq[cveID] = struct{}{}
+				all = append(all, cveID)
+			}
+		}
+	}
+	r.explain.addCandidates(len(all))
+	hashes, err := r.hgetAllMulti(all)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ids, hashes, nil
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+)
+
+// distroVerCodename is the codename of each version of Debian (e.g. 12) and Ubuntu (e.g. 2204).
+// The built-in releases are extended by the releases of distro-info-data in DB (gost fetch distro-info) when the DB is opened,
+// so that a new release works without updating gost.
+var distroVerCodename = struct {
+	sync.RWMutex
+	m map[string]map[string]string
+}{m: map[string]map[string]string{
+	models.DistroDebian: {
+		"8":  "jessie",
+		"9":  "stretch",
+		"10": "buster",
+		"11": "bullseye",
+		"12": "bookworm",
+		"13": "trixie",
+	},
+	models.DistroUbuntu: {
+		"1404": "trusty",
+		"1604": "xenial",
+		"1804": "bionic",
+		"2004": "focal",
+		"2010": "groovy",
+		"2104": "hirsute",
+		"2110": "impish",
+		"2204": "jammy",
+	},
+}}
+
+// codenameOf returns the codename of the version of the distribution (e.g. debian 12 => bookworm)
+func codenameOf(distro, ver string) (string, bool) {
+	distroVerCodename.RLock()
+	defer distroVerCodename.RUnlock()
+	codename, ok := distroVerCodename.m[distro][ver]
+	return codename, ok
+}
+
+// setDistroReleases adds the releases to the codenames known. The release of the same version replaces the built-in one.
+func setDistroReleases(releases []models.DistroRelease) {
+	distroVerCodename.Lock()
+	defer distroVerCodename.Unlock()
+	for _, r := range releases {
+		if r.Version == "" || r.Codename == "" {
+			continue
+		}
+		if distroVerCodename.m[r.Distro] == nil {
+			distroVerCodename.m[r.Distro] = map[string]string{}
+		}
+		distroVerCodename.m[r.Distro][r.Version] = r.Codename
+	}
+}
+
+// NormalizeDebianRelease returns the major version of Debian from a version (e.g. 11, 11.2) or a codename (e.g. bullseye)
+func NormalizeDebianRelease(release string) string {
+	return normalizeRelease(util.Major(release), models.DistroDebian)
+}
+
+// NormalizeUbuntuRelease returns the version of Ubuntu without a dot (e.g. 2204) from a version (e.g. 22.04, 2204) or a codename (e.g. jammy)
+func NormalizeUbuntuRelease(release string) string {
+	return normalizeRelease(strings.Replace(release, ".", "", 1), models.DistroUbuntu)
+}
+
+// normalizeRelease converts the codename into the version of the distribution.
+// If the release is not a known codename, it is returned as is.
+func normalizeRelease(release, distro string) string {
+	release = strings.ToLower(strings.TrimSpace(release))
+	distroVerCodename.RLock()
+	defer distroVerCodename.RUnlock()
+	verCodename := distroVerCodename.m[distro]
+	if _, ok := verCodename[release]; ok {
+		return release
+	}
+	for ver, codename := range verCodename {
+		if release == codename {
+			return ver
+		}
+	}
+	return release
+}
+
+// NormalizeAmazonRelease returns the release of Amazon Linux (1, 2, 2022, 2023) from a version (e.g. 2018.03, 2, 2023.0.20230222)
+func NormalizeAmazonRelease(release string) string {
+	ver := util.Major(strings.TrimSpace(release))
+	switch {
+	case ver == "1" || ver == "2" || ver == "2022" || ver == "2023":
+		return ver
+	case len(ver) == 4 && ver < "2022":
+		// Amazon Linux AMI (e.g. 2017.09, 2018.03)
+		return "1"
+	}
+	return ver
+}
+
+// NormalizeAlpineRelease returns the release of Alpine (e.g. 3.15) from a version (e.g. 3.15.4, v3.15)
+func NormalizeAlpineRelease(release string) string {
+	ss := strings.Split(strings.TrimPrefix(strings.TrimSpace(release), "v"), ".")
+	if len(ss) < 2 {
+		return ss[0]
+	}
+	return ss[0] + "." + ss[1]
+}
+
+// NormalizeOracleRelease returns the major version of Oracle Linux (e.g. 8) from a version (e.g. 8.4, ol8)
+func NormalizeOracleRelease(release string) string {
+	return util.Major(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(release)), "ol"))
+}
+
+// NormalizeRockyRelease returns the major version of Rocky Linux (e.g. 8) from a version (e.g. 8.5)
+func NormalizeRockyRelease(release string) string {
+	return util.Major(strings.TrimSpace(release))
+}
+
+// NormalizeAlmaRelease returns the major version of AlmaLinux (e.g. 8) from a version (e.g. 8.5)
+func NormalizeAlmaRelease(release string) string {
+	return util.Major(strings.TrimSpace(release))
+}
+
+// NormalizePhotonRelease returns the release of Photon OS (e.g. 4.0) from a version (e.g. 4, 4.0)
+func NormalizePhotonRelease(release string) string {
+	return util.Major(strings.TrimSpace(release)) + ".0"
+}
+
+// NormalizeMarinerRelease returns the release of CBL-Mariner and Azure Linux (e.g. 2.0) from a version (e.g. 2, 2.0.20230126)
+func NormalizeMarinerRelease(release string) string {
+	return util.Major(strings.TrimSpace(release)) + ".0"
+}
+
+// NormalizeOpenEulerRelease returns the release of openEuler (e.g. 20.03-LTS-SP1) from a product (e.g. openEuler-20.03-LTS-SP1, 20.03 LTS SP1)
+func NormalizeOpenEulerRelease(release string) string {
+	release = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(release)), "OPENEULER")
+	return strings.Join(strings.Fields(strings.ReplaceAll(release, "-", " ")), "-")
+}
+
+// NormalizeAnolisRelease returns the major version of Anolis OS (e.g. 8) from a version (e.g. 8.6, an8)
+func NormalizeAnolisRelease(release string) string {
+	return util.Major(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(release)), "an"))
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestNormalizeDebianRelease(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out string
+	}{
+		{in: "11", out: "11"},
+		{in: "11.2", out: "11"},
+		{in: "bullseye", out: "11"},
+		{in: "Bullseye", out: "11"},
+		{in: "sid", out: "sid"},
+	}
+
+	for i, tt := range tests {
+		if aout := NormalizeDebianRelease(tt.in); tt.out != aout {
+			t.Errorf("[%d] expected: %s\n  actual: %s\n", i, tt.out, aout)
+		}
+	}
+}
+
+func TestNormalizeUbuntuRelease(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out string
+	}{
+		{in: "2204", out: "2204"},
+		{in: "22.04", out: "2204"},
+		{in: "jammy", out: "2204"},
+		{in: "focal", out: "2004"},
+		{in: "unknown", out: "unknown"},
+	}
+
+	for i, tt := range tests {
+		if aout := NormalizeUbuntuRelease(tt.in); tt.out != aout {
+			t.Errorf("[%d] expected: %s\n  actual: %s\n", i, tt.out, aout)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// e.g. "Rocky Linux 8"
+var rockyProductRegex = regexp.MustCompile(`^Rocky Linux (\d+)`)
+
+// GetRocky :
+func (r *RDBDriver) GetRocky(cveID string) *models.RockyCVE {
+	c := models.RockyCVE{}
+	err := r.conn.
+		Preload("Advisories.Packages").
+		Preload("Advisories").
+		Where(&models.RockyCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Rocky", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertRocky :
+func (r *RDBDriver) InsertRocky(advisories []models.RockyAdvisoryJSON) (err error) {
+	cves := r.filter.filterRocky(ConvertRocky(advisories, r.warnings))
+	if err = r.deleteAndInsertRocky(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Rocky CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertRocky(conn *gorm.DB, cves []models.RockyCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RockyPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RockyAdvisory{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.RockyCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertRocky converts RLSA (per advisory) to RockyCVE (per CVE)
+func ConvertRocky(advisories []models.RockyAdvisoryJSON, warnings *util.Warnings) (cves []models.RockyCVE) {
+	uniqCve := map[string]models.RockyCVE{}
+	for _, a := range advisories {
+		// The same release can be listed as several products (e.g. "Rocky Linux 8", "Rocky Linux 8 x86_64")
+		releasePkgs := map[string][]models.RockyPackage{}
+		uniqPkg := map[string]struct{}{}
+		for product, rpm := range a.Rpms {
+			ss := rockyProductRegex.FindStringSubmatch(product)
+			if ss == nil {
+				warnings.Add("rocky", a.Name, "rpms", fmt.Sprintf("Failed to get the release from the product: %s", product))
+				continue
+			}
+			release := ss[1]
+
+			for _, nvra := range rpm.Nvras {
+				name, ver, arch, err := parseRockyNVRA(nvra)
+				if err != nil {
+					warnings.Add("rocky", a.Name, "nvras", err.Error())
+					continue
+				}
+				key := release + "#" + name + "#" + ver
+				if _, ok := uniqPkg[key]; ok || arch == "src" {
+					continue
+				}
+				uniqPkg[key] = struct{}{}
+				releasePkgs[release] = append(releasePkgs[release], models.RockyPackage{
+					PackageName:  name,
+					FixedVersion: ver,
+				})
+			}
+		}
+
+		issued := parseRockyDate(a.Name, "publishedAt", a.PublishedAt, warnings)
+		for release, pkgs := range releasePkgs {
+			for _, c := range a.Cves {
+				advisory := models.RockyAdvisory{
+					AdvisoryID:  a.Name,
+					ReleaseName: release,
+					Title:       a.Synopsis,
+					Severity:    rockySeverity(a.Severity),
+					Description: a.Description,
+					IssuedDate:  issued,
+					Packages:    append([]models.RockyPackage(nil), pkgs...),
+				}
+
+				cve, ok := uniqCve[c.Name]
+				if !ok {
+					cve = models.RockyCVE{CveID: c.Name}
+				}
+				cve.Advisories = append(cve.Advisories, advisory)
+				uniqCve[c.Name] = cve
+			}
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+// parseRockyNVRA parses NVRA (e.g. openssl-libs-1:1.1.1k-6.el8_5.x86_64.rpm) into name, [epoch:]version-release and arch
+func parseRockyNVRA(nvra string) (name, ver, arch string, err error) {
+	nvra = strings.TrimSuffix(nvra, ".rpm")
+	i := strings.LastIndex(nvra, ".")
+	if i == -1 {
+		return "", "", "", xerrors.Errorf("Failed to parse arch. nvra: %s", nvra)
+	}
+	nvr, arch := nvra[:i], nvra[i+1:]
+
+	i = strings.LastIndex(nvr, "-")
+	if i == -1 {
+		return "", "", "", xerrors.Errorf("Failed to parse release. nvra: %s", nvra)
+	}
+	j := strings.LastIndex(nvr[:i], "-")
+	if j == -1 {
+		return "", "", "", xerrors.Errorf("Failed to parse version. nvra: %s", nvra)
+	}
+	return nvr[:j], strings.TrimPrefix(nvr[j+1:], "0:"), arch, nil
+}
+
+// rockySeverity converts SEVERITY_IMPORTANT into Important
+func rockySeverity(severity string) string {
+	s := strings.ToLower(strings.TrimPrefix(severity, "SEVERITY_"))
+	if s == "" {
+		return ""
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func parseRockyDate(advisoryID, field, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339)
+	if err != nil {
+		warnings.Add("rocky", advisoryID, field, fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetFixedCvesRocky gets the CVEs fixed by RLSA related to release, pkgName.
+func (r *RDBDriver) GetFixedCvesRocky(release, pkgName string) map[string]models.RockyCVE {
+	m := map[string]models.RockyCVE{}
+
+	type Result struct {
+		RockyCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("rocky_packages").
+		Select("rocky_advisories.rocky_cve_id").
+		Joins("JOIN rocky_advisories ON rocky_advisories.id = rocky_packages.rocky_advisory_id").
+		Where("rocky_packages.package_name = ? AND rocky_advisories.release_name = ?", pkgName, release).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get fixed cves of Rocky", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.RockyCVE{}
+		err := r.conn.
+			Preload("Advisories.Packages", "package_name = ?", pkgName).
+			Preload("Advisories", "release_name = ?", release).
+			Where(&models.RockyCVE{ID: res.RockyCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get RockyCVE", "err", err)
+			return m
+		}
+
+		for _, a := range cve.Advisories {
+			if len(a.Packages) != 0 {
+				m[cve.CveID] = cve
+			}
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,243 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// SeenIDs records the CVE-IDs stored into Redis per source (the field of CVE#$CVEID) by a fetch,
+// so that the CVEs no longer present upstream can be removed by RemoveMissing
+type SeenIDs struct {
+	mu  sync.Mutex
+	ids map[string]map[string]struct{}
+	// partial is the fields upserted for a part of the CVEs (e.g. --cve), whose missing CVEs are not removed
+	partial map[string]bool
+}
+
+// NewSeenIDs returns empty SeenIDs
+func NewSeenIDs() *SeenIDs {
+	return &SeenIDs{ids: map[string]map[string]struct{}{}, partial: map[string]bool{}}
+}
+
+func (s *SeenIDs) add(field, cveID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, ok := s.ids[field]
+	if !ok {
+		ids = map[string]struct{}{}
+		s.ids[field] = ids
+	}
+	ids[cveID] = struct{}{}
+}
+
+// markPartial excludes the field from RemoveMissing. It does nothing on nil.
+func (s *SeenIDs) markPartial(field string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partial[field] = true
+}
+
+// Fields returns the fields stored for all the CVEs of the source, in which the CVEs not seen can be removed
+func (s *SeenIDs) Fields() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := []string{}
+	for field, ids := range s.ids {
+		if len(ids) > 0 && !s.partial[field] {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Seen returns the number of the CVE-IDs seen in the field
+func (s *SeenIDs) Seen(field string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.ids[field])
+}
+
+func (s *SeenIDs) has(field, cveID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.ids[field][cveID]
+	return ok
+}
+
+// seenIDsHook records the fields of CVE#$CVEID set by HSET of Redis into SeenIDs.
+// With filtered, the fields are marked partial, because the CVEs dropped by the insert filter are not stored but still present upstream.
+type seenIDsHook struct {
+	seen     *SeenIDs
+	filtered bool
+}
+
+func (h seenIDsHook) record(cmd redis.Cmder) {
+	args := cmd.Args()
+	if cmd.Err() != nil || len(args) < 4 || !strings.EqualFold(cmd.Name(), "hset") {
+		return
+	}
+	key := fmt.Sprint(args[1])
+	if !strings.HasPrefix(key, hashKeyPrefix+"CVE-") {
+		return
+	}
+	for i := 2; i+1 < len(args); i += 2 {
+		if value, ok := args[i+1].(string); ok && isDocument(value) {
+			if h.filtered {
+				h.seen.markPartial(fmt.Sprint(args[i]))
+			}
+			h.seen.add(fmt.Sprint(args[i]), strings.TrimPrefix(key, hashKeyPrefix))
+		}
+	}
+}
+
+func (h seenIDsHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h seenIDsHook) AfterProcess(_ context.Context, cmd redis.Cmder) error {
+	h.record(cmd)
+	return nil
+}
+
+func (h seenIDsHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h seenIDsHook) AfterProcessPipeline(_ context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		h.record(cmd)
+	}
+	return nil
+}
+
+// RemoveMissing deletes the fields of seen.Fields() from CVE#$CVEID of the CVEs not seen, and their members of the indexes.
+// The CVE#$CVEID without any field left is deleted by Redis. It returns the removed CVE-IDs per field,
+// and records them as the tombstones removed at removedAt for GetTombstones.
+func (r *RedisDriver) RemoveMissing(seen *SeenIDs, removedAt time.Time) (map[string][]string, error) {
+	removed := map[string][]string{}
+	for _, field := range seen.Fields() {
+		missing := []string{}
+		if err := r.forEachCveIDOfField(field, func(cveID string) error {
+			if !seen.has(field, cveID) {
+				missing = append(missing, cveID)
+			}
+			return nil
+		}); err != nil {
+			return removed, err
+		}
+		sort.Strings(missing)
+
+		for idx := range chunkSlice(len(missing), r.multiGet.chunkSize) {
+			if err := r.removeFields(field, missing[idx.From:idx.To]); err != nil {
+				return removed, err
+			}
+			if err := r.addTombstones(field, missing[idx.From:idx.To], removedAt); err != nil {
+				return removed, err
+			}
+			removed[field] = append(removed[field], missing[idx.From:idx.To]...)
+		}
+	}
+	return removed, nil
+}
+
+// addTombstones records the CVEs removed from the field into TOMBSTONE scored by removedAt.
+// With TTL, the tombstones older than TTL are dropped, as the CVEs not removed expire by then.
+func (r *RedisDriver) addTombstones(field string, cveIDs []string, removedAt time.Time) error {
+	ctx := context.Background()
+	pipe := r.conn.Pipeline()
+	members := make([]*redis.Z, 0, len(cveIDs))
+	for _, cveID := range cveIDs {
+		members = append(members, &redis.Z{Score: float64(removedAt.Unix()), Member: field + "#" + cveID})
+	}
+	if err := pipe.ZAdd(ctx, zindTombstoneKey, members...).Err(); err != nil {
+		return xerrors.Errorf("Failed to ZAdd tombstones. err: %w", err)
+	}
+	if r.insert.TTL > 0 {
+		expired := removedAt.Add(-time.Duration(r.insert.TTL * uint(time.Second))).Unix()
+		if err := pipe.ZRemRangeByScore(ctx, zindTombstoneKey, "-inf", fmt.Sprintf("(%d", expired)).Err(); err != nil {
+			return xerrors.Errorf("Failed to ZRemRangeByScore tombstones. err: %w", err)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return xerrors.Errorf("Failed to exec pipeline. err: %w", err)
+	}
+	return nil
+}
+
+// GetTombstones returns the tombstones of the CVEs removed by RemoveMissing after since, in the order of the removal
+func (r *RedisDriver) GetTombstones(since time.Time) ([]models.Tombstone, error) {
+	zs, err := r.conn.ZRangeByScoreWithScores(context.Background(), zindTombstoneKey, &redis.ZRangeBy{Min: fmt.Sprintf("(%d", since.Unix()), Max: "+inf"}).Result()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to ZRangeByScore tombstones. err: %w", err)
+	}
+	tombstones := make([]models.Tombstone, 0, len(zs))
+	for _, z := range zs {
+		ss := strings.SplitN(fmt.Sprint(z.Member), "#", 2)
+		if len(ss) != 2 {
+			continue
+		}
+		tombstones = append(tombstones, models.Tombstone{Source: ss[0], CveID: ss[1], RemovedAt: time.Unix(int64(z.Score), 0).UTC()})
+	}
+	return tombstones, nil
+}
+
+// removeFields deletes the field from CVE#$CVEID of cveIDs and the CVE-IDs from the indexes derived from the field
+func (r *RedisDriver) removeFields(field string, cveIDs []string) error {
+	ctx := context.Background()
+	pipe := r.conn.Pipeline()
+	docs := make([]*redis.StringCmd, len(cveIDs))
+	for i, cveID := range cveIDs {
+		docs[i] = pipe.HGet(ctx, hashKeyPrefix+cveID, field)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return xerrors.Errorf("Failed to exec pipeline. err: %w", err)
+	}
+
+	pipe = r.conn.Pipeline()
+	for i, cveID := range cveIDs {
+		entries, _ := redisCveIndexEntries(cveID, map[string]string{field: docs[i].Val()})
+		for _, e := range entries[field] {
+			// the members of CVE#P#$PRODUCTID are shared by the CVEs
+			if e.member != cveID {
+				continue
+			}
+			if err := r.removeIndexMember(ctx, pipe, e); err != nil {
+				return err
+			}
+		}
+		if err := pipe.HDel(ctx, hashKeyPrefix+cveID, field).Err(); err != nil {
+			return xerrors.Errorf("Failed to HDel CVE. err: %w", err)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return xerrors.Errorf("Failed to exec pipeline. err: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisDriver) removeIndexMember(ctx context.Context, pipe redis.Pipeliner, e redisIndexEntry) error {
+	if e.pkg && r.indexLayout == IndexLayoutSet {
+		if err := pipe.SRem(ctx, e.key, e.member).Err(); err != nil {
+			return xerrors.Errorf("Failed to SRem index. err: %w", err)
+		}
+		return nil
+	}
+	if err := pipe.ZRem(ctx, e.key, e.member).Err(); err != nil {
+		return xerrors.Errorf("Failed to ZRem index. err: %w", err)
+	}
+	return nil
+}
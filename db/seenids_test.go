@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestSeenIDsHook(t *testing.T) {
+	ctx := context.Background()
+	var tests = []struct {
+		filtered bool
+		partial  []string
+		cmds     []redis.Cmder
+		expected []string
+	}{
+		{
+			cmds: []redis.Cmder{
+				redis.NewIntCmd(ctx, "hset", "CVE#CVE-2021-3449", "RedHat", `{"name":"CVE-2021-3449"}`, "Debian", `{"cve_id":"CVE-2021-3449"}`),
+				redis.NewIntCmd(ctx, "hset", "CVE#CVE-2021-3450", "RedHat", `{"name":"CVE-2021-3450"}`),
+				// not the documents of the CVEs
+				redis.NewIntCmd(ctx, "hset", "CVE#CVE-2021-3450", "Ubuntu", "1"),
+				redis.NewIntCmd(ctx, "hset", "CVE#K#DATE", "Nvd", `{"cve_id":"CVE-2021-3450"}`),
+				redis.NewIntCmd(ctx, "zadd", "CVE#D#openssl", 1, "CVE-2021-3449"),
+			},
+			expected: []string{"Debian", "RedHat"},
+		},
+		{
+			partial: []string{"Debian"},
+			cmds: []redis.Cmder{
+				redis.NewIntCmd(ctx, "hset", "CVE#CVE-2021-3449", "RedHat", `{"name":"CVE-2021-3449"}`, "Debian", `{"cve_id":"CVE-2021-3449"}`),
+			},
+			expected: []string{"RedHat"},
+		},
+		// the CVEs dropped by the insert filter are not removed
+		{
+			filtered: true,
+			cmds: []redis.Cmder{
+				redis.NewIntCmd(ctx, "hset", "CVE#CVE-2021-3449", "RedHat", `{"name":"CVE-2021-3449"}`, "Debian", `{"cve_id":"CVE-2021-3449"}`),
+			},
+			expected: []string{},
+		},
+	}
+
+	for i, tt := range tests {
+		seen := NewSeenIDs()
+		for _, field := range tt.partial {
+			seen.markPartial(field)
+		}
+		h := seenIDsHook{seen: seen, filtered: tt.filtered}
+		if err := h.AfterProcessPipeline(ctx, tt.cmds); err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err)
+		}
+		if actual := seen.Fields(); !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("[%d] expected: %v\n  actual: %v\n", i, tt.expected, actual)
+		}
+	}
+
+	seen := NewSeenIDs()
+	h := seenIDsHook{seen: seen}
+	_ = h.AfterProcess(ctx, redis.NewIntCmd(ctx, "hset", "CVE#CVE-2021-3449", "RedHat", `{"name":"CVE-2021-3449"}`))
+	if !seen.has("RedHat", "CVE-2021-3449") || seen.has("RedHat", "CVE-2021-3450") || seen.Seen("RedHat") != 1 {
+		t.Errorf("unexpected seen IDs: %v", seen.ids)
+	}
+}
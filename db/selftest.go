@@ -0,0 +1,78 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/knqyf263/gost/models"
+)
+
+// selfTestCveIDFields has the JSON field of CVE-ID of the sources whose field is not cve_id
+var selfTestCveIDFields = map[string]string{
+	"redhat": "Name",
+	"debian": "CveID",
+	"ubuntu": "candidate",
+}
+
+// SelfTest queries a known CVE from each loaded source and verifies that the stored document can be decoded and has the CVE-ID.
+// A source with no CVE is skipped, so an empty DB passes.
+func SelfTest(driver DB) models.SelfTestReport {
+	sources := []string{}
+	for s := range cveIDColumns {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+
+	report := models.SelfTestReport{OK: true, Sources: []models.SelfTestSource{}}
+	for _, source := range sources {
+		s := selfTestSource(driver, source)
+		if !s.OK {
+			report.OK = false
+		}
+		report.Sources = append(report.Sources, s)
+	}
+	return report
+}
+
+func selfTestSource(driver DB, source string) models.SelfTestSource {
+	s := models.SelfTestSource{Source: source}
+	cveIDs, err := driver.GetCveIDs(source)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	if len(cveIDs) == 0 {
+		s.OK = true
+		return s
+	}
+	sort.Strings(cveIDs)
+	s.CveID = cveIDs[0]
+
+	doc, err := driver.GetRaw(source, s.CveID)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	if doc == nil {
+		s.Error = "CVE is listed but not found"
+		return s
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		s.Error = fmt.Sprintf("Failed to decode the document. err: %s", err)
+		return s
+	}
+	field, ok := selfTestCveIDFields[source]
+	if !ok {
+		field = "cve_id"
+	}
+	var cveID string
+	if err := json.Unmarshal(fields[field], &cveID); err != nil || cveID != s.CveID {
+		s.Error = fmt.Sprintf("CVE-ID mismatch. field: %s, expected: %s, actual: %s", field, s.CveID, cveID)
+		return s
+	}
+	s.OK = true
+	return s
+}
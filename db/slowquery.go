@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/inconshreveable/log15"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+const slowQueryStartKey = "gost:slow_query_start"
+
+// registerSlowQueryLog logs each SELECT of gorm (including the preloads) taking threshold or longer with the SQL and its values,
+// so that the pathological packages or KBs can be found from the log
+func registerSlowQueryLog(conn *gorm.DB, threshold time.Duration) error {
+	if err := conn.Callback().Query().Before("gorm:query").Register("gost:slow_query", func(db *gorm.DB) {
+		db.InstanceSet(slowQueryStartKey, time.Now())
+	}); err != nil {
+		return xerrors.Errorf("Failed to register slow query log. err: %w", err)
+	}
+	if err := conn.Callback().Query().After("gorm:after_query").Register("gost:slow_query_log", func(db *gorm.DB) {
+		v, ok := db.InstanceGet(slowQueryStartKey)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(v.(time.Time))
+		if elapsed < threshold {
+			return
+		}
+		loggerFrom(db.Statement.Context).Warn("Slow query", "elapsed", elapsed.String(), "rows", db.Statement.RowsAffected,
+			"sql", db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...))
+	}); err != nil {
+		return xerrors.Errorf("Failed to register slow query log. err: %w", err)
+	}
+	return nil
+}
+
+type slowQueryStartCtxKey struct{}
+
+// slowQueryHook logs each command and pipeline of Redis taking threshold or longer with the keys involved
+type slowQueryHook struct {
+	threshold time.Duration
+}
+
+func (h slowQueryHook) start(ctx context.Context) context.Context {
+	return context.WithValue(ctx, slowQueryStartCtxKey{}, time.Now())
+}
+
+func (h slowQueryHook) log(ctx context.Context, query func() string) {
+	start, ok := ctx.Value(slowQueryStartCtxKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	if elapsed := time.Since(start); elapsed >= h.threshold {
+		loggerFrom(ctx).Warn("Slow query", "elapsed", elapsed.String(), "redis", query())
+	}
+}
+
+func (h slowQueryHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return h.start(ctx), nil
+}
+
+func (h slowQueryHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.log(ctx, func() string { return strings.Join(cmdArgs(cmd), " ") })
+	return nil
+}
+
+func (h slowQueryHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return h.start(ctx), nil
+}
+
+func (h slowQueryHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	h.log(ctx, func() string {
+		var names []string
+		for _, cmd := range cmds {
+			names = append(names, strings.Join(cmdArgs(cmd), " "))
+		}
+		return fmt.Sprintf("pipeline(%d): %s", len(cmds), strings.Join(names, "; "))
+	})
+	return nil
+}
+
+type loggerCtxKey struct{}
+
+func withLogger(ctx context.Context, l log15.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// loggerFrom returns the logger of WithLogger in ctx, or the root logger
+func loggerFrom(ctx context.Context) log15.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerCtxKey{}).(log15.Logger); ok {
+			return l
+		}
+	}
+	return log15.Root()
+}
+
+// loggerHook passes the logger of WithLogger to the hooks after it (e.g. slowQueryHook) by the context of the commands
+type loggerHook struct {
+	log log15.Logger
+}
+
+func (h loggerHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return withLogger(ctx, h.log), nil
+}
+
+func (loggerHook) AfterProcess(context.Context, redis.Cmder) error {
+	return nil
+}
+
+func (h loggerHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return withLogger(ctx, h.log), nil
+}
+
+func (loggerHook) AfterProcessPipeline(context.Context, []redis.Cmder) error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Sort keys of SortCVEs
+const (
+	// SortByCveID sorts by year and sequence number of CVE-ID
+	SortByCveID = "cve_id"
+	// SortByDate sorts by the public date. The CVEs without the date come last
+	SortByDate = "date"
+)
+
+// ErrInvalidSortKey is returned when the sort key is neither SortByCveID nor SortByDate
+var ErrInvalidSortKey = xerrors.New("Invalid sort key")
+
+// CveIDLess compares CVE-IDs by the year and the sequence number as numbers, so CVE-2021-9999 comes before CVE-2021-10000.
+// The IDs not in the form of CVE-YYYY-NNNN are compared as strings after CVE-IDs.
+func CveIDLess(a, b string) bool {
+	ya, na, okA := splitCveID(a)
+	yb, nb, okB := splitCveID(b)
+	switch {
+	case okA && okB:
+		if ya != yb {
+			return ya < yb
+		}
+		if na != nb {
+			return na < nb
+		}
+		return a < b
+	case okA != okB:
+		return okA
+	default:
+		return a < b
+	}
+}
+
+func splitCveID(cveID string) (year, number int, ok bool) {
+	ss := strings.Split(cveID, "-")
+	if len(ss) != 3 || !strings.EqualFold(ss[0], "CVE") {
+		return 0, 0, false
+	}
+	year, err := strconv.Atoi(ss[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	number, err = strconv.Atoi(ss[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	return year, number, true
+}
+
+// cveDateFields are the fields of the public date in the CVE structs
+var cveDateFields = []string{"PublicDate", "PublishDate", "PublishedDate"}
+
+// SortCVEs returns the values of cves, a map of CVE-ID to CVE (e.g. the result of GetUnfixedCvesDebian), as a slice sorted by by.
+// Ties are broken by CVE-ID, so the order is the same for the same DB.
+func SortCVEs(cves interface{}, by string) (interface{}, error) {
+	m := reflect.Indirect(reflect.ValueOf(cves))
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return nil, xerrors.Errorf("Failed to sort CVEs. type: %T", cves)
+	}
+
+	cveIDs := []string{}
+	for _, k := range m.MapKeys() {
+		cveIDs = append(cveIDs, k.String())
+	}
+
+	switch by {
+	case "", SortByCveID:
+		sort.Slice(cveIDs, func(i, j int) bool { return CveIDLess(cveIDs[i], cveIDs[j]) })
+	case SortByDate:
+		dates := map[string]time.Time{}
+		for _, cveID := range cveIDs {
+			dates[cveID] = cveDate(m.MapIndex(reflect.ValueOf(cveID)))
+		}
+		sort.Slice(cveIDs, func(i, j int) bool {
+			di, dj := dates[cveIDs[i]], dates[cveIDs[j]]
+			if !di.Equal(dj) {
+				if di.IsZero() || dj.IsZero() {
+					return dj.IsZero()
+				}
+				return di.Before(dj)
+			}
+			return CveIDLess(cveIDs[i], cveIDs[j])
+		})
+	default:
+		return nil, xerrors.Errorf("Failed to sort CVEs. sort: %s, err: %w", by, ErrInvalidSortKey)
+	}
+
+	sorted := reflect.MakeSlice(reflect.SliceOf(m.Type().Elem()), 0, len(cveIDs))
+	for _, cveID := range cveIDs {
+		sorted = reflect.Append(sorted, m.MapIndex(reflect.ValueOf(cveID)))
+	}
+	return sorted.Interface(), nil
+}
+
+func cveDate(c reflect.Value) time.Time {
+	for _, name := range cveDateFields {
+		if f := c.FieldByName(name); f.IsValid() {
+			if t, ok := f.Interface().(time.Time); ok {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Paginate returns the page of the slice from offset. If limit is 0 or less, the rest of the slice is returned.
+// total is the length of the slice.
+func Paginate(slice interface{}, offset, limit int) (page interface{}, total int) {
+	s := reflect.ValueOf(slice)
+	total = s.Len()
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if 0 < limit && offset+limit < total {
+		end = offset + limit
+	}
+	return s.Slice(offset, end).Interface(), total
+}
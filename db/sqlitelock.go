@@ -0,0 +1,171 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"golang.org/x/xerrors"
+)
+
+// ErrServerRunning is returned when a fetch targets the SQLite3 file a running server is using without WAL,
+// since writing it under the server corrupts the DB or the responses.
+var ErrServerRunning = xerrors.New("The SQLite3 DB is used by a running server")
+
+// ServerLock : the lock metadata written next to the SQLite3 file (dbpath + .server.lock) while a server is using it
+type ServerLock struct {
+	PID       int       `json:"pid"`
+	Hostname  string    `json:"hostname"`
+	StartedAt time.Time `json:"started_at"`
+	// UpdatedAt is refreshed every serverLockHeartbeat while the server is running
+	UpdatedAt time.Time `json:"updated_at"`
+	// WAL is true when the server opened the DB in WAL mode, so a fetch can write while the server reads the snapshots
+	WAL bool `json:"wal"`
+}
+
+const (
+	// serverLockHeartbeat is how often the server refreshes UpdatedAt of the lock
+	serverLockHeartbeat = time.Minute
+	// serverLockStaleAfter is how long the lock without refresh is regarded as left by a server gone,
+	// for the servers on the other hosts, whose processes cannot be checked
+	serverLockStaleAfter = 10 * time.Minute
+)
+
+// serverLockPath returns the path of the lock metadata. The parameters of the DSN (e.g. ?_busy_timeout=5000) are ignored.
+func serverLockPath(dbPath string) string {
+	if i := strings.IndexRune(dbPath, '?'); i >= 0 {
+		dbPath = dbPath[:i]
+	}
+	return strings.TrimPrefix(dbPath, "file:") + ".server.lock"
+}
+
+// AcquireServerLock writes the lock metadata of the server using the SQLite3 file of dbPath, and refreshes it every serverLockHeartbeat.
+// The returned function stops refreshing and removes it, and is called when the server stops.
+func AcquireServerLock(dbPath string, wal bool) (func() error, error) {
+	hostname, _ := os.Hostname()
+	now := time.Now()
+	lock := ServerLock{PID: os.Getpid(), Hostname: hostname, StartedAt: now, UpdatedAt: now, WAL: wal}
+	path := serverLockPath(dbPath)
+	if err := writeServerLock(path, lock); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(serverLockHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				lock.UpdatedAt = now
+				if err := writeServerLock(path, lock); err != nil {
+					log15.Warn("Failed to refresh server lock.", "err", err)
+				}
+			}
+		}
+	}()
+	return func() error {
+		close(done)
+		<-stopped
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return xerrors.Errorf("Failed to remove server lock. path: %s, err: %w", path, err)
+		}
+		return nil
+	}, nil
+}
+
+func writeServerLock(path string, lock ServerLock) error {
+	b, err := json.Marshal(lock)
+	if err != nil {
+		return xerrors.Errorf("Failed to marshal server lock. err: %w", err)
+	}
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		return xerrors.Errorf("Failed to write server lock. path: %s, err: %w", path, err)
+	}
+	return nil
+}
+
+// ReadServerLock reads the lock metadata of the server using the SQLite3 file of dbPath.
+// It returns nil if no server is using it, including the lock left by a server killed on this host,
+// and the lock of another host not refreshed for serverLockStaleAfter (e.g. left by a server killed there).
+func ReadServerLock(dbPath string) (*ServerLock, error) {
+	return readServerLock(dbPath, time.Now())
+}
+
+func readServerLock(dbPath string, now time.Time) (*ServerLock, error) {
+	b, err := ioutil.ReadFile(serverLockPath(dbPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("Failed to read server lock. err: %w", err)
+	}
+	lock := ServerLock{}
+	if err := json.Unmarshal(b, &lock); err != nil {
+		return nil, xerrors.Errorf("Failed to unmarshal server lock. err: %w", err)
+	}
+	if hostname, _ := os.Hostname(); lock.Hostname == hostname {
+		if !processAlive(lock.PID) {
+			return nil, nil
+		}
+		return &lock, nil
+	}
+	updatedAt := lock.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = lock.StartedAt
+	}
+	if now.Sub(updatedAt) > serverLockStaleAfter {
+		log15.Warn("Ignoring the stale server lock", "path", serverLockPath(dbPath), "pid", lock.PID, "hostname", lock.Hostname, "updatedAt", updatedAt.Format(time.RFC3339))
+		return nil, nil
+	}
+	return &lock, nil
+}
+
+// processAlive reports whether the process of pid is running on this host.
+// It is regarded as alive unless it is known to be gone, e.g. on the platforms not supporting the signal 0.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || !(errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.ESRCH))
+}
+
+// CheckServerLock checks that a fetch can write the SQLite3 file of dbPath.
+// It returns the lock of the server in WAL mode, with which the fetch coordinates by WAL too,
+// and ErrServerRunning with the guidance if the server does not use WAL.
+func CheckServerLock(dbPath string) (*ServerLock, error) {
+	lock, err := ReadServerLock(dbPath)
+	if err != nil || lock == nil {
+		return nil, err
+	}
+	if !lock.WAL {
+		return nil, xerrors.Errorf("pid: %d, hostname: %s, started at: %s. Stop the server, restart it with --sqlite-wal to fetch while serving, or fetch into another file and restart the server with it. err: %w",
+			lock.PID, lock.Hostname, lock.StartedAt.Format(time.RFC3339), ErrServerRunning)
+	}
+	return lock, nil
+}
+
+// sqliteBusyTimeout is how long a connection in WAL mode waits for the lock of the other process
+const sqliteBusyTimeout = 5 * time.Second
+
+// sqliteWALDSN adds the parameters of WAL mode and the busy timeout to the DSN of SQLite3,
+// so that every connection of the pool waits for the lock of the writer instead of failing with SQLITE_BUSY.
+func sqliteWALDSN(dbPath string) string {
+	sep := "?"
+	if strings.ContainsRune(dbPath, '?') {
+		sep = "&"
+	}
+	return dbPath + sep + "_journal_mode=WAL&_busy_timeout=" + strconv.FormatInt(sqliteBusyTimeout.Milliseconds(), 10)
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadServerLock(t *testing.T) {
+	hostname, _ := os.Hostname()
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		lock     *ServerLock
+		expected bool
+	}{
+		{lock: nil, expected: false},
+		{lock: &ServerLock{PID: os.Getpid(), Hostname: hostname, StartedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour)}, expected: true},
+		{lock: &ServerLock{PID: 1 << 30, Hostname: hostname, StartedAt: now, UpdatedAt: now}, expected: false},
+		{lock: &ServerLock{PID: 1, Hostname: "other-host", StartedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Minute)}, expected: true},
+		// the lock of another host is stale without refresh
+		{lock: &ServerLock{PID: 1, Hostname: "other-host", StartedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-serverLockStaleAfter - time.Second)}, expected: false},
+		// the lock without updated_at is stale since started_at
+		{lock: &ServerLock{PID: 1, Hostname: "other-host", StartedAt: now.Add(-time.Minute)}, expected: true},
+		{lock: &ServerLock{PID: 1, Hostname: "other-host", StartedAt: now.Add(-time.Hour)}, expected: false},
+	}
+
+	for i, tt := range tests {
+		dbPath := filepath.Join(t.TempDir(), "gost.sqlite3")
+		if tt.lock != nil {
+			b, err := json.Marshal(tt.lock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(serverLockPath(dbPath), b, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		lock, err := readServerLock(dbPath+"?_busy_timeout=5000", now)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err)
+			continue
+		}
+		if (lock != nil) != tt.expected {
+			t.Errorf("[%d] expected live lock: %t\n  actual: %+v\n", i, tt.expected, lock)
+		}
+	}
+}
+
+func TestAcquireServerLock(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "gost.sqlite3")
+	release, err := AcquireServerLock(dbPath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := CheckServerLock(dbPath)
+	if err != nil || lock == nil || !lock.WAL || lock.UpdatedAt.IsZero() {
+		t.Errorf("unexpected lock: %+v, %v", lock, err)
+	}
+	if err := release(); err != nil {
+		t.Fatal(err)
+	}
+	if lock, err := CheckServerLock(dbPath); lock != nil || err != nil {
+		t.Errorf("the lock is left after release: %+v, %v", lock, err)
+	}
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// CheckStaleness reports the sources fetched more than maxAge ago as stale.
+// If sources is empty, all sources fetched are checked. Otherwise, a source in sources never fetched is also stale.
+// The report is not OK if no source has been fetched, since there is no data to serve.
+func CheckStaleness(driver DB, maxAge time.Duration, sources []string, now time.Time) (models.StalenessReport, error) {
+	fetched, err := driver.GetFetchSources()
+	if err != nil {
+		return models.StalenessReport{}, xerrors.Errorf("Failed to check staleness. err: %w", err)
+	}
+	return Staleness(fetched, maxAge, sources, now), nil
+}
+
+// Staleness is CheckStaleness of the fetch times got by GetFetchSources. If maxAge is 0, only the sources never fetched are stale.
+func Staleness(fetched []models.FetchSource, maxAge time.Duration, sources []string, now time.Time) models.StalenessReport {
+	fetchedAt := map[string]time.Time{}
+	for _, s := range fetched {
+		fetchedAt[s.Source] = s.FetchedAt
+	}
+	if len(sources) == 0 {
+		for _, s := range fetched {
+			sources = append(sources, s.Source)
+		}
+	}
+
+	report := models.StalenessReport{OK: len(sources) > 0, Sources: []models.StalenessSource{}}
+	if maxAge > 0 {
+		report.MaxAge = maxAge.String()
+	}
+	for _, source := range sources {
+		s := models.StalenessSource{Source: source, Stale: true}
+		if t, ok := fetchedAt[source]; ok {
+			age := now.Sub(t)
+			s.FetchedAt, s.Age, s.Stale = &t, age.Truncate(time.Second).String(), maxAge > 0 && age > maxAge
+		}
+		if s.Stale {
+			report.OK = false
+		}
+		report.Sources = append(report.Sources, s)
+	}
+	return report
+}
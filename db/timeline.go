@@ -0,0 +1,168 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+)
+
+// GetTimeline builds the timeline of the CVE from all the sources stored in driver.
+// It is computed at query time, so it is always consistent with the stored data.
+func GetTimeline(driver DB, cveID string) models.Timeline {
+	events := []models.TimelineEvent{}
+	add := func(e models.TimelineEvent) {
+		if !e.Date.IsZero() {
+			events = append(events, e)
+		}
+	}
+
+	if c := driver.GetRedhat(cveID); c != nil && c.ID != 0 {
+		add(models.TimelineEvent{Date: c.PublicDate, Source: "redhat", Event: models.TimelineEventPublic})
+		for _, a := range c.AffectedRelease {
+			add(models.TimelineEvent{
+				Date:        parseRedhatReleaseDate(a.ReleaseDate),
+				Source:      "redhat",
+				Event:       models.TimelineEventFixed,
+				Release:     redhatRelease(a.Cpe, a.ProductName),
+				Advisory:    a.Advisory,
+				PackageName: a.Package,
+			})
+		}
+	}
+	if c := driver.GetUbuntu(cveID); c != nil && c.ID != 0 {
+		add(models.TimelineEvent{Date: c.PublicDate, Source: "ubuntu", Event: models.TimelineEventPublic})
+		add(models.TimelineEvent{Date: c.PublicDateAtUSN, Source: "ubuntu", Event: models.TimelineEventAcknowledged})
+		for _, u := range c.USNs {
+			add(models.TimelineEvent{Date: u.PublishedDate, Source: "ubuntu", Event: models.TimelineEventFixed, Release: u.ReleaseName, Advisory: u.USNID})
+		}
+	}
+	if c := driver.GetDebian(cveID); c != nil && c.ID != 0 {
+		for _, a := range c.Advisories {
+			add(models.TimelineEvent{Date: a.IssuedDate, Source: "debian", Event: models.TimelineEventFixed, Release: a.ProductName, Advisory: a.AdvisoryID, PackageName: a.PackageName})
+		}
+	}
+	if c := driver.GetAmazon(cveID); c != nil && c.ID != 0 {
+		for _, a := range c.Advisories {
+			add(models.TimelineEvent{Date: a.IssuedDate, Source: "amazon", Event: models.TimelineEventFixed, Release: a.ReleaseName, Advisory: a.AdvisoryID})
+		}
+	}
+	if c := driver.GetOracle(cveID); c != nil && c.ID != 0 {
+		for _, a := range c.Advisories {
+			releases := map[string]struct{}{}
+			for _, p := range a.Packages {
+				releases[p.ReleaseName] = struct{}{}
+			}
+			for release := range releases {
+				add(models.TimelineEvent{Date: a.IssuedDate, Source: "oracle", Event: models.TimelineEventFixed, Release: release, Advisory: a.AdvisoryID})
+			}
+		}
+	}
+	if c := driver.GetRocky(cveID); c != nil && c.ID != 0 {
+		for _, a := range c.Advisories {
+			add(models.TimelineEvent{Date: a.IssuedDate, Source: "rocky", Event: models.TimelineEventFixed, Release: a.ReleaseName, Advisory: a.AdvisoryID})
+		}
+	}
+	if c := driver.GetAlma(cveID); c != nil && c.ID != 0 {
+		for _, a := range c.Advisories {
+			add(models.TimelineEvent{Date: a.IssuedDate, Source: "alma", Event: models.TimelineEventFixed, Release: a.ReleaseName, Advisory: a.AdvisoryID})
+		}
+	}
+	if c := driver.GetMariner(cveID); c != nil && c.ID != 0 {
+		for _, p := range c.Packages {
+			add(models.TimelineEvent{Date: p.IssuedDate, Source: "mariner", Event: models.TimelineEventFixed, Release: p.ReleaseName, Advisory: p.AdvisoryID, PackageName: p.PackageName})
+		}
+	}
+	if c := driver.GetOpenEuler(cveID); c != nil && c.ID != 0 {
+		for _, a := range c.Advisories {
+			releases := map[string]struct{}{}
+			for _, p := range a.Packages {
+				releases[p.ReleaseName] = struct{}{}
+			}
+			for release := range releases {
+				add(models.TimelineEvent{Date: a.IssuedDate, Source: "openeuler", Event: models.TimelineEventFixed, Release: release, Advisory: a.AdvisoryID})
+			}
+		}
+	}
+	if c := driver.GetAnolis(cveID); c != nil && c.ID != 0 {
+		for _, a := range c.Advisories {
+			releases := map[string]struct{}{}
+			for _, p := range a.Packages {
+				releases[p.ReleaseName] = struct{}{}
+			}
+			for release := range releases {
+				add(models.TimelineEvent{Date: a.IssuedDate, Source: "anolis", Event: models.TimelineEventFixed, Release: release, Advisory: a.AdvisoryID})
+			}
+		}
+	}
+	if c := driver.GetMicrosoft(cveID); c != nil && c.ID != 0 {
+		add(models.TimelineEvent{Date: c.PublishDate, Source: "microsoft", Event: models.TimelineEventPublic})
+	}
+	if c := driver.GetNvd(cveID); c != nil && c.ID != 0 {
+		add(models.TimelineEvent{Date: c.PublishedDate, Source: "nvd", Event: models.TimelineEventPublic})
+	}
+	if c := driver.GetCveProgram(cveID); c != nil && c.CveID != "" && c.State == models.CveStatePublished {
+		add(models.TimelineEvent{Date: c.DatePublished, Source: "cveprogram", Event: models.TimelineEventPublic})
+	}
+	for _, a := range driver.GetGhsaByCveID(cveID) {
+		add(models.TimelineEvent{Date: a.PublishedDate, Source: "ghsa", Event: models.TimelineEventAcknowledged, Advisory: a.GhsaID})
+	}
+	if c := driver.GetKEV(cveID); c != nil && c.ID != 0 {
+		add(models.TimelineEvent{Date: c.DateAdded, Source: "kev", Event: models.TimelineEventExploited})
+	}
+
+	return newTimeline(cveID, events)
+}
+
+func newTimeline(cveID string, events []models.TimelineEvent) models.Timeline {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+
+	timeline := models.Timeline{CveID: cveID, Events: events, Fixes: []models.TimelineFix{}}
+	for _, e := range events {
+		if e.Event == models.TimelineEventPublic {
+			d := e.Date
+			timeline.PublicDate = &d
+			break
+		}
+	}
+
+	// events are sorted, so the first one is the first fix
+	uniq := map[string]struct{}{}
+	for _, e := range events {
+		if e.Event != models.TimelineEventFixed {
+			continue
+		}
+		key := e.Source + "#" + e.Release
+		if _, ok := uniq[key]; ok {
+			continue
+		}
+		uniq[key] = struct{}{}
+
+		fix := models.TimelineFix{Source: e.Source, Release: e.Release, Advisory: e.Advisory, Date: e.Date}
+		if timeline.PublicDate != nil {
+			days := int(e.Date.Sub(*timeline.PublicDate).Hours() / 24)
+			fix.DaysToFix = &days
+		}
+		timeline.Fixes = append(timeline.Fixes, fix)
+	}
+	return timeline
+}
+
+// parseRedhatReleaseDate parses release_date of Security Data API (e.g. 2021-03-25T00:00:00Z)
+func parseRedhatReleaseDate(date string) time.Time {
+	t, _ := util.ParseDate(date, time.RFC3339, "2006-01-02")
+	return t
+}
+
+// redhatRelease returns the major version from cpe (e.g. cpe:/a:redhat:enterprise_linux:8::appstream)
+func redhatRelease(cpe, productName string) string {
+	ss := strings.Split(cpe, ":")
+	if len(ss) >= 5 && ss[3] == "enterprise_linux" {
+		return ss[4]
+	}
+	return productName
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+const queryTimeoutCancelKey = "gost:query_timeout_cancel"
+
+// registerQueryTimeout sets the deadline to each SELECT of gorm (including the preloads) which has no deadline yet.
+// The rows are read in the callbacks of gorm, so the context is canceled after gorm:after_query.
+func registerQueryTimeout(conn *gorm.DB, timeout time.Duration) error {
+	if err := conn.Callback().Query().Before("gorm:query").Register("gost:query_timeout", func(db *gorm.DB) {
+		if _, ok := db.Statement.Context.Deadline(); ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(db.Statement.Context, timeout)
+		db.Statement.Context = ctx
+		db.InstanceSet(queryTimeoutCancelKey, cancel)
+	}); err != nil {
+		return xerrors.Errorf("Failed to register query timeout. err: %w", err)
+	}
+	if err := conn.Callback().Query().After("gorm:after_query").Register("gost:query_timeout_cancel", func(db *gorm.DB) {
+		if cancel, ok := db.InstanceGet(queryTimeoutCancelKey); ok {
+			cancel.(context.CancelFunc)()
+		}
+	}); err != nil {
+		return xerrors.Errorf("Failed to register query timeout. err: %w", err)
+	}
+	return nil
+}
+
+type queryTimeoutCancelCtxKey struct{}
+
+// queryTimeoutHook sets the deadline to each command and pipeline of Redis which has no deadline yet
+type queryTimeoutHook struct {
+	timeout time.Duration
+}
+
+func (h queryTimeoutHook) withTimeout(ctx context.Context) context.Context {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	return context.WithValue(ctx, queryTimeoutCancelCtxKey{}, cancel)
+}
+
+func (h queryTimeoutHook) cancel(ctx context.Context) {
+	if cancel, ok := ctx.Value(queryTimeoutCancelCtxKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+}
+
+func (h queryTimeoutHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return h.withTimeout(ctx), nil
+}
+
+func (h queryTimeoutHook) AfterProcess(ctx context.Context, _ redis.Cmder) error {
+	h.cancel(ctx)
+	return nil
+}
+
+func (h queryTimeoutHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return h.withTimeout(ctx), nil
+}
+
+func (h queryTimeoutHook) AfterProcessPipeline(ctx context.Context, _ []redis.Cmder) error {
+	h.cancel(ctx)
+	return nil
+}
@@ -2,13 +2,13 @@ package db
 
 import (
 	"errors"
+	"regexp"
 	"strings"
+	"time"
 
-	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/models"
 	"github.com/knqyf263/gost/util"
 	"golang.org/x/xerrors"
-	pb "gopkg.in/cheggaaa/pb.v1"
 	"gorm.io/gorm"
 )
 
@@ -28,6 +28,7 @@ func (r *RDBDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
 		patches = append(patches, p)
 	}
 	c.Patches = patches
+	errs = errs.Add(r.conn.Model(&c).Association("SnapPatches").Find(&c.SnapPatches))
 
 	errs = errs.Add(r.conn.Model(&c).Association("Upstreams").Find(&c.Upstreams))
 	upstreams := []models.UbuntuUpstream{}
@@ -37,9 +38,11 @@ func (r *RDBDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
 	}
 	c.Upstreams = upstreams
 
+	errs = errs.Add(r.conn.Model(&c).Association("USNs").Find(&c.USNs))
+
 	errs = util.DeleteRecordNotFound(errs)
 	if len(errs.GetErrors()) > 0 {
-		log15.Error("Failed to get Ubuntu", "err", errs.Error())
+		r.log.Error("Failed to get Ubuntu", "err", errs.Error())
 		return nil
 	}
 
@@ -48,7 +51,7 @@ func (r *RDBDriver) GetUbuntu(cveID string) *models.UbuntuCVE {
 
 // InsertUbuntu :
 func (r *RDBDriver) InsertUbuntu(cveJSONs []models.UbuntuCVEJSON) (err error) {
-	cves := ConvertUbuntu(cveJSONs)
+	cves := r.filter.filterUbuntu(ConvertUbuntu(cveJSONs))
 	if err = r.deleteAndInsertUbuntu(r.conn, cves); err != nil {
 		return xerrors.Errorf("Failed to insert Ubuntu CVE data. err: %s", err)
 	}
@@ -57,7 +60,7 @@ func (r *RDBDriver) InsertUbuntu(cveJSONs []models.UbuntuCVEJSON) (err error) {
 }
 
 func (r *RDBDriver) deleteAndInsertUbuntu(conn *gorm.DB, cves []models.UbuntuCVE) (err error) {
-	bar := pb.StartNew(len(cves))
+	bar := startProgress(r.insert.Progress, len(cves))
 	tx := conn.Begin()
 
 	defer func() {
@@ -74,6 +77,7 @@ func (r *RDBDriver) deleteAndInsertUbuntu(conn *gorm.DB, cves []models.UbuntuCVE
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuUpstream{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuReleasePatch{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuPatch{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuSnapPatch{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuBug{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuNote{}).Error)
 	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuReference{}).Error)
@@ -84,7 +88,7 @@ func (r *RDBDriver) deleteAndInsertUbuntu(conn *gorm.DB, cves []models.UbuntuCVE
 		return xerrors.Errorf("Failed to delete old. err: %s", errs.Error())
 	}
 
-	for idx := range chunkSlice(len(cves), r.batchSize) {
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
 		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
 			return xerrors.Errorf("Failed to insert. err: %w", err)
 		}
@@ -95,6 +99,72 @@ func (r *RDBDriver) deleteAndInsertUbuntu(conn *gorm.DB, cves []models.UbuntuCVE
 	return nil
 }
 
+// UpsertUbuntu replaces only the given CVEs and leaves the others as they are
+func (r *RDBDriver) UpsertUbuntu(cveJSONs []models.UbuntuCVEJSON) (err error) {
+	cves := r.filter.filterUbuntu(ConvertUbuntu(cveJSONs))
+	if err = r.deleteAndUpsertUbuntu(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to upsert Ubuntu CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndUpsertUbuntu(conn *gorm.DB, cves []models.UbuntuCVE) (err error) {
+	if len(cves) == 0 {
+		return nil
+	}
+
+	tx := conn.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	candidates := []string{}
+	for _, c := range cves {
+		candidates = append(candidates, c.Candidate)
+	}
+	ids := []int64{}
+	if err = tx.Model(&models.UbuntuCVE{}).Where("candidate IN ?", candidates).Pluck("id", &ids).Error; err != nil {
+		return xerrors.Errorf("Failed to get old records. err: %w", err)
+	}
+
+	// Delete old records of the given CVEs
+	if len(ids) > 0 {
+		patchIDs := []int64{}
+		upstreamIDs := []int64{}
+		var errs util.Errors
+		errs = errs.Add(tx.Model(&models.UbuntuPatch{}).Where("ubuntu_cve_id IN ?", ids).Pluck("id", &patchIDs).Error)
+		errs = errs.Add(tx.Model(&models.UbuntuUpstream{}).Where("ubuntu_cve_id IN ?", ids).Pluck("id", &upstreamIDs).Error)
+		if len(upstreamIDs) > 0 {
+			errs = errs.Add(tx.Where("ubuntu_upstream_id IN ?", upstreamIDs).Delete(models.UbuntuUpstreamLink{}).Error)
+		}
+		if len(patchIDs) > 0 {
+			errs = errs.Add(tx.Where("ubuntu_patch_id IN ?", patchIDs).Delete(models.UbuntuReleasePatch{}).Error)
+		}
+		errs = errs.Add(tx.Where("ubuntu_cve_id IN ?", ids).Delete(models.UbuntuUpstream{}).Error)
+		errs = errs.Add(tx.Where("ubuntu_cve_id IN ?", ids).Delete(models.UbuntuPatch{}).Error)
+		errs = errs.Add(tx.Where("ubuntu_cve_id IN ?", ids).Delete(models.UbuntuSnapPatch{}).Error)
+		errs = errs.Add(tx.Where("ubuntu_cve_id IN ?", ids).Delete(models.UbuntuBug{}).Error)
+		errs = errs.Add(tx.Where("ubuntu_cve_id IN ?", ids).Delete(models.UbuntuNote{}).Error)
+		errs = errs.Add(tx.Where("ubuntu_cve_id IN ?", ids).Delete(models.UbuntuReference{}).Error)
+		errs = errs.Add(tx.Where("id IN ?", ids).Delete(models.UbuntuCVE{}).Error)
+		errs = util.DeleteNil(errs)
+		if len(errs.GetErrors()) > 0 {
+			return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+		}
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+	}
+	return nil
+}
+
 // ConvertUbuntu :
 func ConvertUbuntu(cveJSONs []models.UbuntuCVEJSON) (cves []models.UbuntuCVE) {
 	for _, cve := range cveJSONs {
@@ -114,14 +184,26 @@ func ConvertUbuntu(cveJSONs []models.UbuntuCVEJSON) (cves []models.UbuntuCVE) {
 
 		bugs := []models.UbuntuBug{}
 		for _, b := range cve.Bugs {
-			bugs = append(bugs, models.UbuntuBug{Bug: b})
+			tracker, bugID := parseUbuntuBug(b)
+			bugs = append(bugs, models.UbuntuBug{Bug: b, Tracker: tracker, BugID: bugID})
 		}
 
 		patches := []models.UbuntuPatch{}
+		snapPatches := []models.UbuntuSnapPatch{}
 		for pkgName, p := range cve.Patches {
 			var releasePatch []models.UbuntuReleasePatch
 			for release, patch := range p {
-				releasePatch = append(releasePatch, models.UbuntuReleasePatch{ReleaseName: release, Status: patch.Status, Note: patch.Note})
+				if release == "snap" {
+					// Not a release of deb, so kept apart not to be taken as a package of the releases
+					snapPatches = append(snapPatches, models.UbuntuSnapPatch{SnapName: pkgName, Status: patch.Status, Note: patch.Note})
+					continue
+				}
+				// The statuses of ESM (e.g. esm-infra/xenial) are of the release with the channel
+				codename, channel := splitReleaseChannel(release)
+				releasePatch = append(releasePatch, models.UbuntuReleasePatch{ReleaseName: codename, Status: patch.Status, Note: patch.Note, Channel: channel})
+			}
+			if len(releasePatch) == 0 {
+				continue
 			}
 			patches = append(patches, models.UbuntuPatch{PackageName: pkgName, ReleasePatches: releasePatch})
 		}
@@ -136,10 +218,10 @@ func ConvertUbuntu(cveJSONs []models.UbuntuCVEJSON) (cves []models.UbuntuCVE) {
 		}
 
 		c := models.UbuntuCVE{
-			PublicDateAtUSN:   cve.PublicDateAtUSN,
-			CRD:               cve.CRD,
+			PublicDateAtUSN:   cve.PublicDateAtUSN.UTC(),
+			CRD:               cve.CRD.UTC(),
 			Candidate:         cve.Candidate,
-			PublicDate:        cve.PublicDate,
+			PublicDate:        cve.PublicDate.UTC(),
 			References:        references,
 			Description:       cve.Description,
 			UbuntuDescription: cve.UbuntuDescription,
@@ -149,6 +231,7 @@ func ConvertUbuntu(cveJSONs []models.UbuntuCVEJSON) (cves []models.UbuntuCVE) {
 			DiscoveredBy:      cve.DiscoveredBy,
 			AssignedTo:        cve.AssignedTo,
 			Patches:           patches,
+			SnapPatches:       snapPatches,
 			Upstreams:         upstreams,
 		}
 		cves = append(cves, c)
@@ -157,30 +240,68 @@ func ConvertUbuntu(cveJSONs []models.UbuntuCVEJSON) (cves []models.UbuntuCVE) {
 	return cves
 }
 
-var ubuntuVerCodename = map[string]string{
-	"1404": "trusty",
-	"1604": "xenial",
-	"1804": "bionic",
-	"2004": "focal",
-	"2010": "groovy",
-	"2104": "hirsute",
+var (
+	launchpadBugRegexp = regexp.MustCompile(`launchpad\.net/(?:.*/)?\+?bugs?/(\d+)`)
+	debianBugRegexp    = regexp.MustCompile(`bugs\.debian\.org/(?:cgi-bin/bugreport\.cgi\?bug=)?(\d+)`)
+)
+
+// parseUbuntuBug returns the bug tracker and the bug number from the URL of the bug.
+// e.g. https://launchpad.net/bugs/1234567, https://bugs.launchpad.net/ubuntu/+source/linux/+bug/1234567,
+// http://bugs.debian.org/cgi-bin/bugreport.cgi?bug=123456
+func parseUbuntuBug(bug string) (tracker, bugID string) {
+	if m := launchpadBugRegexp.FindStringSubmatch(bug); m != nil {
+		return models.UbuntuBugTrackerLaunchpad, m[1]
+	}
+	if m := debianBugRegexp.FindStringSubmatch(bug); m != nil {
+		return models.UbuntuBugTrackerDebian, m[1]
+	}
+	return "", ""
+}
+
+// GetUbuntuByBugID gets the CVEs related to the bug number of the bug tracker (launchpad or debian)
+func (r *RDBDriver) GetUbuntuByBugID(tracker, bugID string) map[string]models.UbuntuCVE {
+	m := map[string]models.UbuntuCVE{}
+	bugs := []models.UbuntuBug{}
+	err := r.conn.Where(&models.UbuntuBug{Tracker: tracker, BugID: bugID}).Find(&bugs).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Ubuntu by bug ID", "err", err)
+		return nil
+	}
+
+	r.explain.addCandidates(len(bugs))
+	for _, b := range bugs {
+		c := models.UbuntuCVE{}
+		if err := r.conn.Select("candidate").Where(&models.UbuntuCVE{ID: b.UbuntuCVEID}).First(&c).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				r.log.Error("Failed to get Ubuntu by bug ID", "err", err)
+				return nil
+			}
+			continue
+		}
+		if cve := r.GetUbuntu(c.Candidate); cve != nil {
+			m[c.Candidate] = *cve
+		}
+	}
+	return m
 }
 
 // GetUnfixedCvesUbuntu gets the CVEs related to debian_release.status IN ('needed', 'pending'), ver, pkgName.
-func (r *RDBDriver) GetUnfixedCvesUbuntu(ver, pkgName string) map[string]models.UbuntuCVE {
-	return r.getCvesUbuntuWithFixStatus(ver, pkgName, []string{"needed", "pending"})
+// The statuses of the channels (e.g. esm-infra, esm-apps) override the standard ones if given.
+func (r *RDBDriver) GetUnfixedCvesUbuntu(ver, pkgName string, channels ...string) map[string]models.UbuntuCVE {
+	return r.getCvesUbuntuWithFixStatus(ver, pkgName, []string{"needed", "pending"}, channels)
 }
 
 // GetFixedCvesUbuntu gets the CVEs related to debian_release.status IN ('released'), ver, pkgName.
-func (r *RDBDriver) GetFixedCvesUbuntu(ver, pkgName string) map[string]models.UbuntuCVE {
-	return r.getCvesUbuntuWithFixStatus(ver, pkgName, []string{"released"})
+// The statuses of the channels (e.g. esm-infra, esm-apps) override the standard ones if given.
+func (r *RDBDriver) GetFixedCvesUbuntu(ver, pkgName string, channels ...string) map[string]models.UbuntuCVE {
+	return r.getCvesUbuntuWithFixStatus(ver, pkgName, []string{"released"}, channels)
 }
 
-func (r *RDBDriver) getCvesUbuntuWithFixStatus(ver, pkgName string, fixStatus []string) map[string]models.UbuntuCVE {
+func (r *RDBDriver) getCvesUbuntuWithFixStatus(ver, pkgName string, fixStatus, channels []string) map[string]models.UbuntuCVE {
 	m := map[string]models.UbuntuCVE{}
-	codeName, ok := ubuntuVerCodename[ver]
+	codeName, ok := codenameOf(models.DistroUbuntu, ver)
 	if !ok {
-		log15.Error("Ubuntu %s is not supported yet", "err", ver)
+		r.log.Error("Ubuntu %s is not supported yet", "err", ver)
 		return m
 	}
 
@@ -197,21 +318,22 @@ func (r *RDBDriver) getCvesUbuntuWithFixStatus(ver, pkgName string, fixStatus []
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		if fixStatus[0] == "released" {
-			log15.Error("Failed to get fixed cves of Ubuntu", "err", err)
+			r.log.Error("Failed to get fixed cves of Ubuntu", "err", err)
 		} else {
-			log15.Error("Failed to get unfixed cves of Ubuntu", "err", err)
+			r.log.Error("Failed to get unfixed cves of Ubuntu", "err", err)
 		}
 	}
 
+	r.explain.addCandidates(len(results))
 	for _, res := range results {
 		cve := models.UbuntuCVE{}
 		err := r.conn.
-			Preload("Patches.ReleasePatches", "release_name = ? AND status IN (?)", codeName, fixStatus).
+			Preload("Patches.ReleasePatches", "release_name = ?", codeName).
 			Preload("Patches", "package_name = ?", pkgName).
 			Where(&models.UbuntuCVE{ID: res.UbuntuCveID}).
 			First(&cve).Error
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			log15.Error("Failed to getCvesUbuntuWithFixStatus", "err", err)
+			r.log.Error("Failed to getCvesUbuntuWithFixStatus", "err", err)
 			return m
 		}
 
@@ -230,18 +352,240 @@ func (r *RDBDriver) getCvesUbuntuWithFixStatus(ver, pkgName string, fixStatus []
 
 		errs = util.DeleteRecordNotFound(errs)
 		if len(errs.GetErrors()) > 0 {
-			log15.Error("Failed to get Ubuntu", "err", errs.Error())
+			r.log.Error("Failed to get Ubuntu", "err", errs.Error())
 			return map[string]models.UbuntuCVE{}
 		}
 
-		if len(cve.Patches) != 0 {
-			for _, p := range cve.Patches {
-				if len(p.ReleasePatches) != 0 {
-					m[cve.Candidate] = cve
-				}
+		patches := []models.UbuntuPatch{}
+		for _, p := range cve.Patches {
+			if p.ReleasePatches = filterUbuntuReleasePatches(p.ReleasePatches, fixStatus, channels); len(p.ReleasePatches) != 0 {
+				patches = append(patches, p)
 			}
 		}
+		if len(patches) != 0 {
+			cve.Patches = patches
+			m[cve.Candidate] = cve
+		}
+	}
+
+	return m
+}
+
+// GetUnfixedCvesUbuntuSnap gets the CVEs related to ubuntu_snap_patches.status IN ('needed', 'pending'), snapName.
+func (r *RDBDriver) GetUnfixedCvesUbuntuSnap(snapName string) map[string]models.UbuntuCVE {
+	m := map[string]models.UbuntuCVE{}
+	candidates := []string{}
+	err := r.conn.
+		Model(&models.UbuntuCVE{}).
+		Joins("JOIN ubuntu_snap_patches ON ubuntu_snap_patches.ubuntu_cve_id = ubuntu_cves.id").
+		Where("ubuntu_snap_patches.snap_name = ? AND ubuntu_snap_patches.status IN ?", snapName, []string{"needed", "pending"}).
+		Pluck("ubuntu_cves.candidate", &candidates).Error
+	if err != nil {
+		r.log.Error("Failed to get unfixed cves of Ubuntu snap", "err", err)
+		return m
 	}
 
+	r.explain.addCandidates(len(candidates))
+	for _, cveID := range candidates {
+		cve := r.GetUbuntu(cveID)
+		if cve == nil {
+			continue
+		}
+		if cve.SnapPatches = filterUbuntuSnapPatches(cve.SnapPatches, snapName); len(cve.SnapPatches) != 0 {
+			m[cveID] = *cve
+		}
+	}
 	return m
 }
+
+// filterUbuntuSnapPatches returns the unfixed patches of the snap
+func filterUbuntuSnapPatches(snapPatches []models.UbuntuSnapPatch, snapName string) []models.UbuntuSnapPatch {
+	filtered := []models.UbuntuSnapPatch{}
+	for _, p := range snapPatches {
+		if p.SnapName == snapName && (p.Status == "needed" || p.Status == "pending") {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// GetUnfixedCvesUbuntuMulti gets the unfixed CVEs of pkgNames by a query of the patches and a query of the CVEs
+func (r *RDBDriver) GetUnfixedCvesUbuntuMulti(ver string, pkgNames []string, channels ...string) map[string]map[string]models.UbuntuCVE {
+	results := map[string]map[string]models.UbuntuCVE{}
+	codeName, ok := codenameOf(models.DistroUbuntu, ver)
+	if !ok {
+		r.log.Error("Ubuntu %s is not supported yet", "err", ver)
+		return results
+	}
+
+	ids := []int64{}
+	for idx := range chunkSlice(len(pkgNames), 500) {
+		chunk := []int64{}
+		if err := r.conn.
+			Table("ubuntu_patches").
+			Distinct("ubuntu_cve_id").
+			Where("package_name IN ?", pkgNames[idx.From:idx.To]).
+			Pluck("ubuntu_cve_id", &chunk).Error; err != nil {
+			r.log.Error("Failed to get unfixed cves of Ubuntu", "err", err)
+			return results
+		}
+		ids = append(ids, chunk...)
+	}
+
+	r.explain.addCandidates(len(ids))
+	cves := []models.UbuntuCVE{}
+	for idx := range chunkSlice(len(ids), 500) {
+		chunk := []models.UbuntuCVE{}
+		if err := r.conn.
+			Preload("Patches.ReleasePatches", "release_name = ?", codeName).
+			Preload("Patches", "package_name IN ?", pkgNames).
+			Preload("References").
+			Preload("Notes").
+			Preload("Bugs").
+			Preload("Upstreams.UpstreamLinks").
+			Where("id IN ?", ids[idx.From:idx.To]).
+			Find(&chunk).Error; err != nil {
+			r.log.Error("Failed to get unfixed cves of Ubuntu", "err", err)
+			return results
+		}
+		cves = append(cves, chunk...)
+	}
+
+	for _, pkgName := range pkgNames {
+		m := map[string]models.UbuntuCVE{}
+		for _, c := range cves {
+			if c.Patches = ubuntuPatchesWithFixStatus(c.Patches, codeName, pkgName, []string{"needed", "pending"}, channels); len(c.Patches) != 0 {
+				m[c.Candidate] = c
+			}
+		}
+		results[pkgName] = m
+	}
+	return results
+}
+
+// ubuntuPatchesWithFixStatus returns the patches of pkgName having the release patches of codeName in fixStatus, with those release patches only
+func ubuntuPatchesWithFixStatus(patches []models.UbuntuPatch, codeName, pkgName string, fixStatus, channels []string) []models.UbuntuPatch {
+	filtered := []models.UbuntuPatch{}
+	for _, p := range patches {
+		if p.PackageName != pkgName {
+			continue
+		}
+		relPatches := []models.UbuntuReleasePatch{}
+		for _, relPatch := range p.ReleasePatches {
+			if relPatch.ReleaseName == codeName {
+				relPatches = append(relPatches, relPatch)
+			}
+		}
+		if p.ReleasePatches = filterUbuntuReleasePatches(relPatches, fixStatus, channels); len(p.ReleasePatches) != 0 {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// filterUbuntuReleasePatches returns the patches of the release in fixStatus, of the channel effective for the subscriber of the channels
+func filterUbuntuReleasePatches(relPatches []models.UbuntuReleasePatch, fixStatus, channels []string) []models.UbuntuReleasePatch {
+	found := map[string]bool{}
+	for _, p := range relPatches {
+		found[p.Channel] = true
+	}
+	channel := effectiveChannel(found, channels)
+
+	filtered := []models.UbuntuReleasePatch{}
+	for _, p := range relPatches {
+		if p.Channel != channel {
+			continue
+		}
+		for _, s := range fixStatus {
+			if s == p.Status {
+				filtered = append(filtered, p)
+			}
+		}
+	}
+	return filtered
+}
+
+// InsertUbuntuUSN replaces all USNs. The USNs are linked to UbuntuCVE by CVE-ID
+func (r *RDBDriver) InsertUbuntuUSN(usnJSONs []models.UbuntuUSNJSON) (err error) {
+	usns := ConvertUbuntuUSN(usnJSONs)
+	if err = r.deleteAndInsertUbuntuUSN(r.conn, usns); err != nil {
+		return xerrors.Errorf("Failed to insert Ubuntu USN data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertUbuntuUSN(conn *gorm.DB, usns []models.UbuntuUSN) (err error) {
+	bar := startProgress(r.insert.Progress, len(usns))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	if err = tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.UbuntuUSN{}).Error; err != nil {
+		return xerrors.Errorf("Failed to delete old. err: %w", err)
+	}
+
+	for idx := range chunkSlice(len(usns), r.insert.BatchSize) {
+		if err = tx.Create(usns[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertUbuntuUSN converts USN (per notice) to UbuntuUSN (per notice, CVE and release)
+func ConvertUbuntuUSN(usnJSONs []models.UbuntuUSNJSON) (usns []models.UbuntuUSN) {
+	for _, usn := range usnJSONs {
+		usnID := normalizeUSNID(usn.ID)
+		published := time.Unix(int64(usn.Timestamp), 0).UTC()
+		for _, cveID := range usn.Cves {
+			// Launchpad bugs are also listed
+			if !strings.HasPrefix(cveID, "CVE-") {
+				continue
+			}
+			for release := range usn.Releases {
+				usns = append(usns, models.UbuntuUSN{
+					CveID:         cveID,
+					USNID:         usnID,
+					ReleaseName:   release,
+					Title:         usn.Title,
+					PublishedDate: published,
+				})
+			}
+		}
+	}
+	return usns
+}
+
+// normalizeUSNID returns USN ID with "USN-" prefix (e.g. 4999-1 => USN-4999-1)
+func normalizeUSNID(usnID string) string {
+	if strings.HasPrefix(usnID, "USN-") {
+		return usnID
+	}
+	return "USN-" + usnID
+}
+
+// GetCveIDsByUSN gets the CVE-IDs fixed by the USN (e.g. USN-4999-1 or 4999-1)
+func (r *RDBDriver) GetCveIDsByUSN(usnID string) []string {
+	cveIDs := []string{}
+	err := r.conn.
+		Model(&models.UbuntuUSN{}).
+		Distinct("cve_id").
+		Where(&models.UbuntuUSN{USNID: normalizeUSNID(usnID)}).
+		Order("cve_id").
+		Pluck("cve_id", &cveIDs).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get CVE-IDs by USN", "err", err)
+		return []string{}
+	}
+	return cveIDs
+}
@@ -0,0 +1,242 @@
+package db
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// e.g. "openssl package in jammy was vulnerable but has been fixed (note: '3.0.2-0ubuntu1.8')."
+var ubuntuOVALCriterionRegexp = regexp.MustCompile(`^(\S+) package in (\S+) (.+?)(?: \(note: '([^']*)'\))?\.?$`)
+
+// UbuntuCodename returns the codename of the release (e.g. 22.04, 2204 or jammy), or empty if unknown
+func UbuntuCodename(release string) string {
+	codename, _ := codenameOf(models.DistroUbuntu, NormalizeUbuntuRelease(release))
+	return codename
+}
+
+type ubuntuOVALKey struct {
+	cveID   string
+	pkgName string
+}
+
+type ubuntuOVALState struct {
+	status string
+	// version is the fixed version, only if released
+	version string
+}
+
+// ubuntuOVALStatus returns the status of the tracker corresponding to the comment of the criterion
+func ubuntuOVALStatus(text string) string {
+	switch {
+	case strings.HasPrefix(text, "was vulnerable"):
+		return "released"
+	case strings.Contains(text, "pending publication"):
+		return "pending"
+	case strings.Contains(text, "defer"):
+		return "deferred"
+	case strings.Contains(text, "may need fixing"):
+		return "needs-triage"
+	case strings.Contains(text, "needs fixing"):
+		return "needed"
+	}
+	return ""
+}
+
+// ubuntuOVALStates returns the status of each pair of CVE and package in OVAL
+func ubuntuOVALStates(oval models.UbuntuOVALXML) map[ubuntuOVALKey]ubuntuOVALState {
+	states := map[ubuntuOVALKey]ubuntuOVALState{}
+	for _, def := range oval.Definitions {
+		if def.Class != "" && def.Class != "vulnerability" {
+			continue
+		}
+		cveID := ""
+		for _, ref := range def.Metadata.References {
+			if ref.Source == "CVE" {
+				cveID = ref.RefID
+				break
+			}
+		}
+		if cveID == "" {
+			if fs := strings.Fields(def.Metadata.Title); len(fs) > 0 && strings.HasPrefix(fs[0], "CVE-") {
+				cveID = fs[0]
+			}
+		}
+		if cveID == "" {
+			continue
+		}
+
+		walkUbuntuOVALCriteria(def.Criteria, func(pkgName, text, note string) {
+			s := ubuntuOVALState{status: ubuntuOVALStatus(text)}
+			if s.status == "" {
+				return
+			}
+			if s.status == "released" {
+				s.version = note
+			}
+			states[ubuntuOVALKey{cveID: cveID, pkgName: pkgName}] = s
+		})
+	}
+	return states
+}
+
+func walkUbuntuOVALCriteria(criteria models.UbuntuOVALCriteriaXML, fn func(pkgName, text, note string)) {
+	for _, c := range criteria.Criterions {
+		if m := ubuntuOVALCriterionRegexp.FindStringSubmatch(c.Comment); m != nil {
+			fn(m[1], m[3], m[4])
+		}
+	}
+	for _, c := range criteria.Criterias {
+		walkUbuntuOVALCriteria(c, fn)
+	}
+}
+
+// ubuntuStatusClass classifies the status of the tracker into released, unfixed, or the others (e.g. not-affected, DNE, ignored) as is
+func ubuntuStatusClass(status string) string {
+	switch status {
+	case "needed", "pending", "deferred", "needs-triage", "active":
+		return "unfixed"
+	}
+	return status
+}
+
+// ubuntuTrackerPatches returns the patches of the CVE in the tracker by package and release, or false if the tracker does not have the CVE
+type ubuntuTrackerPatches func(cveID string) (map[string]map[string]models.UbuntuPatchJSON, bool)
+
+// crossCheckUbuntuOVAL compares each pair of CVE and package in OVAL with the tracker.
+// The CVEs the tracker does not have are skipped if skipUnknown, otherwise reported as missing.
+// resolve is called with each disagreement, and returns whether the tracker took the status of OVAL.
+func crossCheckUbuntuOVAL(ovals []models.UbuntuOVALXML, patches ubuntuTrackerPatches, skipUnknown bool, resolve func(codename string, d models.UbuntuOVALDisagreement) bool) (models.UbuntuOVALCrossCheckReport, error) {
+	report := models.UbuntuOVALCrossCheckReport{Releases: []models.UbuntuOVALCrossCheckRelease{}, Disagreements: []models.UbuntuOVALDisagreement{}}
+	for _, oval := range ovals {
+		codename := UbuntuCodename(oval.Release)
+		if codename == "" {
+			return models.UbuntuOVALCrossCheckReport{}, xerrors.Errorf("Failed to cross-check Ubuntu OVAL. Unknown release: %s", oval.Release)
+		}
+
+		states := ubuntuOVALStates(oval)
+		keys := make([]ubuntuOVALKey, 0, len(states))
+		for k := range states {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].cveID != keys[j].cveID {
+				return keys[i].cveID < keys[j].cveID
+			}
+			return keys[i].pkgName < keys[j].pkgName
+		})
+
+		summary := models.UbuntuOVALCrossCheckRelease{Release: codename}
+		for _, k := range keys {
+			ps, ok := patches(k.cveID)
+			if !ok && skipUnknown {
+				continue
+			}
+			summary.Checked++
+
+			s := states[k]
+			d := models.UbuntuOVALDisagreement{Release: codename, CveID: k.cveID, PackageName: k.pkgName, OVALStatus: s.status, OVALFixedVersion: s.version}
+			p, found := ps[k.pkgName][codename]
+			d.TrackerStatus, d.TrackerNote = p.Status, p.Note
+
+			switch {
+			case !found:
+				d.Kind = models.UbuntuOVALMissingInTracker
+				summary.MissingInTracker++
+			case ubuntuStatusClass(d.TrackerStatus) != ubuntuStatusClass(d.OVALStatus):
+				d.Kind = models.UbuntuOVALStatusMismatch
+				summary.StatusMismatch++
+			case d.OVALStatus == "released" && d.OVALFixedVersion != "" && trimDebianEpoch(d.OVALFixedVersion) != trimDebianEpoch(d.TrackerNote):
+				d.Kind = models.UbuntuOVALVersionMismatch
+				summary.VersionMismatch++
+			default:
+				continue
+			}
+			if resolve != nil && resolve(codename, d) {
+				d.Merged = true
+				summary.Merged++
+			}
+			report.Disagreements = append(report.Disagreements, d)
+		}
+		log15.Info("Cross-checked Ubuntu OVAL", "release", codename, "checked", summary.Checked,
+			"disagreements", summary.MissingInTracker+summary.StatusMismatch+summary.VersionMismatch, "merged", summary.Merged)
+		report.Releases = append(report.Releases, summary)
+	}
+	return report, nil
+}
+
+// CrossCheckUbuntuOVAL compares the fix status of each pair of CVE and package in Ubuntu OVAL with Ubuntu CVE Tracker in DB,
+// and reports the disagreements.
+func CrossCheckUbuntuOVAL(driver DB, ovals []models.UbuntuOVALXML) (models.UbuntuOVALCrossCheckReport, error) {
+	trackerPatches := map[string]map[string]map[string]models.UbuntuPatchJSON{}
+	return crossCheckUbuntuOVAL(ovals, func(cveID string) (map[string]map[string]models.UbuntuPatchJSON, bool) {
+		ps, ok := trackerPatches[cveID]
+		if !ok {
+			if cve := driver.GetUbuntu(cveID); cve != nil {
+				ps = map[string]map[string]models.UbuntuPatchJSON{}
+				for _, p := range cve.Patches {
+					for _, r := range p.ReleasePatches {
+						if r.Channel != "" {
+							// OVAL is of the standard support
+							continue
+						}
+						if ps[p.PackageName] == nil {
+							ps[p.PackageName] = map[string]models.UbuntuPatchJSON{}
+						}
+						ps[p.PackageName][r.ReleaseName] = models.UbuntuPatchJSON{Status: r.Status, Note: r.Note}
+					}
+				}
+			}
+			trackerPatches[cveID] = ps
+		}
+		return ps, ps != nil
+	}, false, nil)
+}
+
+// MergeUbuntuOVAL merges Ubuntu OVAL into the CVEs of Ubuntu CVE Tracker fetched by the policy (tracker, oval or flag),
+// and reports the disagreements. The CVEs not in cveJSONs (e.g. not updated since the last fetch) are left as they are.
+func MergeUbuntuOVAL(cveJSONs []models.UbuntuCVEJSON, ovals []models.UbuntuOVALXML, policy string) (models.UbuntuOVALCrossCheckReport, error) {
+	switch policy {
+	case models.UbuntuOVALMergeTracker, models.UbuntuOVALMergeOVAL, models.UbuntuOVALMergeFlag:
+	default:
+		return models.UbuntuOVALCrossCheckReport{}, xerrors.Errorf("Unknown merge policy of Ubuntu OVAL: %s. It must be tracker, oval or flag", policy)
+	}
+
+	cves := map[string]*models.UbuntuCVEJSON{}
+	for i := range cveJSONs {
+		cves[cveJSONs[i].Candidate] = &cveJSONs[i]
+	}
+
+	report, err := crossCheckUbuntuOVAL(ovals, func(cveID string) (map[string]map[string]models.UbuntuPatchJSON, bool) {
+		cve, ok := cves[cveID]
+		if !ok {
+			return nil, false
+		}
+		return cve.Patches, true
+	}, true, func(codename string, d models.UbuntuOVALDisagreement) bool {
+		switch {
+		case policy == models.UbuntuOVALMergeFlag:
+			return false
+		case policy == models.UbuntuOVALMergeTracker && d.Kind != models.UbuntuOVALMissingInTracker:
+			return false
+		}
+		cve := cves[d.CveID]
+		if cve.Patches == nil {
+			cve.Patches = map[string]map[string]models.UbuntuPatchJSON{}
+		}
+		if cve.Patches[d.PackageName] == nil {
+			cve.Patches[d.PackageName] = map[string]models.UbuntuPatchJSON{}
+		}
+		cve.Patches[d.PackageName][codename] = models.UbuntuPatchJSON{Status: d.OVALStatus, Note: d.OVALFixedVersion}
+		return true
+	})
+	if err != nil {
+		return models.UbuntuOVALCrossCheckReport{}, err
+	}
+	report.Policy = policy
+	return report, nil
+}
@@ -0,0 +1,305 @@
+package db
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+)
+
+// severityNames are the names of severityRanks used in VulnInfo
+var severityNames = []string{"negligible", "low", "medium", "high", "critical"}
+
+// GetVulnInfo aggregates the records of the CVE in RedHat, Debian, Ubuntu and Microsoft into VulnInfo.
+// Sources is empty if none of them has the CVE.
+func GetVulnInfo(driver DB, cveID string) models.VulnInfo {
+	v := vulnInfoBuilder{info: models.VulnInfo{
+		ID:               cveID,
+		Sources:          []string{},
+		SourceSeverities: map[string]string{},
+		Cvss:             []models.VulnCvss{},
+		Affected:         []models.VulnAffected{},
+		References:       []string{},
+	}, severity: -1, sourceSeverity: map[string]int{}, refs: map[string]struct{}{}}
+
+	c := driver.GetCveByID(cveID)
+	if c == nil {
+		return v.build()
+	}
+	if c.RedHat != nil {
+		v.addRedhat(*c.RedHat)
+	}
+	if c.Debian != nil {
+		v.addDebian(*c.Debian)
+	}
+	if c.Ubuntu != nil {
+		v.addUbuntu(*c.Ubuntu)
+	}
+	if c.Microsoft != nil {
+		v.addMicrosoft(*c.Microsoft)
+	}
+	return v.build()
+}
+
+// GetVulnInfoMulti is GetVulnInfo of cveIDs. The CVE-IDs in none of the sources are omitted.
+func GetVulnInfoMulti(driver DB, cveIDs []string) []models.VulnInfo {
+	infos := []models.VulnInfo{}
+	for _, cveID := range cveIDs {
+		if v := GetVulnInfo(driver, cveID); len(v.Sources) > 0 {
+			infos = append(infos, v)
+		}
+	}
+	return infos
+}
+
+type vulnInfoBuilder struct {
+	info     models.VulnInfo
+	severity int
+	// sourceSeverity is the rank of SourceSeverities by source (-1 if unknown)
+	sourceSeverity map[string]int
+	refs           map[string]struct{}
+}
+
+func (v *vulnInfoBuilder) addSource(source string, knownExploited bool, epss *models.EpssScore) {
+	v.info.Sources = append(v.info.Sources, source)
+	v.info.KnownExploited = v.info.KnownExploited || knownExploited
+	if v.info.Epss == nil {
+		v.info.Epss = epss
+	}
+}
+
+// addSeverity records the severity of the source, and raises Severity if it is higher.
+// The source giving the severities per release (Debian) keeps the highest one.
+func (v *vulnInfoBuilder) addSeverity(source, severity string) {
+	if severity == "" {
+		return
+	}
+	rank, ok := severityRanks[strings.ToLower(strings.TrimRight(strings.TrimSpace(severity), "*"))]
+	if !ok {
+		rank = -1
+	}
+	if prev, found := v.sourceSeverity[source]; !found || rank > prev {
+		v.sourceSeverity[source] = rank
+		v.info.SourceSeverities[source] = severity
+	}
+	if rank > v.severity {
+		v.severity = rank
+	}
+}
+
+func (v *vulnInfoBuilder) addText(title, description string) {
+	if v.info.Title == "" {
+		v.info.Title = title
+	}
+	if v.info.Description == "" {
+		v.info.Description = description
+	}
+}
+
+func (v *vulnInfoBuilder) addPublished(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	if v.info.PublishedDate == nil || t.Before(*v.info.PublishedDate) {
+		v.info.PublishedDate = &t
+	}
+}
+
+// addCvss adds the score given as a string (e.g. "7.5") with the vector. The version is taken from the vector of CVSS v3 (e.g. CVSS:3.1/AV:N/...)
+func (v *vulnInfoBuilder) addCvss(source, version, score, vector string) {
+	s, err := strconv.ParseFloat(score, 64)
+	if err != nil {
+		return
+	}
+	v.addCvssScore(source, version, s, vector)
+}
+
+func (v *vulnInfoBuilder) addCvssScore(source, version string, score float64, vector string) {
+	if score == 0 && vector == "" {
+		return
+	}
+	if strings.HasPrefix(vector, "CVSS:") {
+		version = strings.SplitN(strings.TrimPrefix(vector, "CVSS:"), "/", 2)[0]
+	}
+	v.info.Cvss = append(v.info.Cvss, models.VulnCvss{Source: source, Version: version, Score: score, Vector: vector})
+}
+
+func (v *vulnInfoBuilder) addReference(ref string) {
+	if ref == "" {
+		return
+	}
+	if _, ok := v.refs[ref]; ok {
+		return
+	}
+	v.refs[ref] = struct{}{}
+	v.info.References = append(v.info.References, ref)
+}
+
+func (v *vulnInfoBuilder) addAffected(a models.VulnAffected) {
+	v.info.Affected = append(v.info.Affected, a)
+}
+
+func (v *vulnInfoBuilder) addRedhat(c models.RedhatCVE) {
+	v.addSource("redhat", c.KnownExploited, c.Epss)
+	v.addSeverity("redhat", c.ThreatSeverity)
+	v.addText(c.Bugzilla.Description, c.GetDetail("\n"))
+	v.addPublished(c.PublicDate)
+	v.addCvss("redhat", "3.0", c.Cvss3.Cvss3BaseScore, c.Cvss3.Cvss3ScoringVector)
+	v.addCvss("redhat", "2.0", c.Cvss.CvssBaseScore, c.Cvss.CvssScoringVector)
+	for _, r := range c.References {
+		v.addReference(r.Reference)
+	}
+	for _, a := range c.AffectedRelease {
+		name, fixed := a.Package, ""
+		if ss := redhatNVRRegexp.FindStringSubmatch(a.Package); ss != nil {
+			name, fixed = ss[1], strings.TrimPrefix(a.Package, ss[1]+"-")
+		}
+		v.addAffected(models.VulnAffected{Source: "redhat", Release: redhatRelease(a.Cpe, a.ProductName), PackageName: name, Status: models.VulnStatusFixed, FixedVersion: fixed, Advisory: a.Advisory})
+	}
+	for _, p := range c.PackageState {
+		v.addAffected(models.VulnAffected{Source: "redhat", Release: redhatRelease(p.Cpe, p.ProductName), PackageName: p.PackageName, Status: redhatVulnStatus(p.FixState)})
+	}
+}
+
+// redhatVulnStatus normalizes fix_state of package_state
+func redhatVulnStatus(fixState string) string {
+	switch fixState {
+	case "Affected", "Fix deferred", "New":
+		return models.VulnStatusAffected
+	case "Will not fix", "Out of support scope":
+		return models.VulnStatusWillNotFix
+	case "Not affected":
+		return models.VulnStatusNotAffected
+	default:
+		return models.VulnStatusUnknown
+	}
+}
+
+func (v *vulnInfoBuilder) addDebian(c models.DebianCVE) {
+	v.addSource("debian", c.KnownExploited, c.Epss)
+	v.addText("", c.Description)
+	for _, p := range c.Package {
+		for _, r := range p.Release {
+			v.addSeverity("debian", r.Urgency)
+			a := models.VulnAffected{Source: "debian", Release: r.ProductName, PackageName: p.PackageName, Status: debianVulnStatus(r.Status, r.FixedVersion)}
+			if a.Status == models.VulnStatusFixed {
+				a.FixedVersion = r.FixedVersion
+			}
+			v.addAffected(a)
+		}
+	}
+	for i := range v.info.Affected {
+		a := &v.info.Affected[i]
+		if a.Source != "debian" || a.Status != models.VulnStatusFixed {
+			continue
+		}
+		for _, adv := range c.Advisories {
+			if adv.PackageName == a.PackageName && adv.ProductName == a.Release {
+				a.Advisory = adv.AdvisoryID
+				break
+			}
+		}
+	}
+}
+
+// debianVulnStatus normalizes the status of Debian Security Tracker. The fixed version "0" means not affected
+func debianVulnStatus(status, fixedVersion string) string {
+	switch status {
+	case "resolved":
+		if fixedVersion == "0" {
+			return models.VulnStatusNotAffected
+		}
+		return models.VulnStatusFixed
+	case "open":
+		return models.VulnStatusAffected
+	default:
+		return models.VulnStatusUnknown
+	}
+}
+
+func (v *vulnInfoBuilder) addUbuntu(c models.UbuntuCVE) {
+	v.addSource("ubuntu", c.KnownExploited, c.Epss)
+	v.addSeverity("ubuntu", c.Priority)
+	v.addText("", c.Description)
+	v.addPublished(c.PublicDate)
+	for _, r := range c.References {
+		v.addReference(r.Reference)
+	}
+	usns := map[string]string{}
+	for _, u := range c.USNs {
+		usns[u.ReleaseName] = u.USNID
+	}
+	for _, p := range c.Patches {
+		for _, r := range p.ReleasePatches {
+			a := models.VulnAffected{Source: "ubuntu", Release: r.ReleaseName, PackageName: p.PackageName, Status: ubuntuVulnStatus(r.Status)}
+			if a.Status == models.VulnStatusFixed {
+				a.FixedVersion, a.Advisory = r.Note, usns[r.ReleaseName]
+			}
+			v.addAffected(a)
+		}
+	}
+}
+
+// ubuntuVulnStatus normalizes the status of Ubuntu CVE Tracker
+func ubuntuVulnStatus(status string) string {
+	switch status {
+	case "released":
+		return models.VulnStatusFixed
+	case "needed", "pending", "deferred", "active":
+		return models.VulnStatusAffected
+	case "ignored":
+		return models.VulnStatusWillNotFix
+	case "not-affected", "DNE":
+		return models.VulnStatusNotAffected
+	default:
+		return models.VulnStatusUnknown
+	}
+}
+
+func (v *vulnInfoBuilder) addMicrosoft(c models.MicrosoftCVE) {
+	v.addSource("microsoft", c.KnownExploited, c.Epss)
+	for _, s := range c.Severity {
+		v.addSeverity("microsoft", s.Description)
+	}
+	v.addText(c.Title, c.Description)
+	v.addPublished(c.PublishDate)
+	for _, s := range c.ScoreSets {
+		v.addCvssScore("microsoft", "3.0", s.BaseScore, s.Vector)
+	}
+	for _, r := range c.References {
+		v.addReference(r.URL)
+	}
+	add := func(remediations []models.MicrosoftRemediation, status string) {
+		for _, r := range remediations {
+			for _, p := range r.Products {
+				a := models.VulnAffected{Source: "microsoft", PackageName: p.ProductName, Status: status}
+				if status == models.VulnStatusFixed {
+					a.FixedVersion, a.Advisory = r.FixedBuild, r.Description
+				}
+				v.addAffected(a)
+			}
+		}
+	}
+	add(c.VendorFix, models.VulnStatusFixed)
+	add(c.NoneAvailable, models.VulnStatusAffected)
+	add(c.WillNotFix, models.VulnStatusWillNotFix)
+}
+
+func (v *vulnInfoBuilder) build() models.VulnInfo {
+	if v.severity >= 0 {
+		v.info.Severity = severityNames[v.severity]
+	}
+	sort.SliceStable(v.info.Affected, func(i, j int) bool {
+		a, b := v.info.Affected[i], v.info.Affected[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Release != b.Release {
+			return a.Release < b.Release
+		}
+		return a.PackageName < b.PackageName
+	})
+	return v.info
+}
@@ -0,0 +1,196 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gorm.io/gorm"
+)
+
+// GetWolfi :
+func (r *RDBDriver) GetWolfi(cveID string) *models.WolfiCVE {
+	c := models.WolfiCVE{}
+	err := r.conn.
+		Preload("Packages").
+		Where(&models.WolfiCVE{CveID: cveID}).
+		First(&c).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get Wolfi", "err", err)
+		return nil
+	}
+	return &c
+}
+
+// InsertWolfi :
+func (r *RDBDriver) InsertWolfi(vulnJSONs []models.OsvJSON) (err error) {
+	cves := r.filter.filterWolfi(ConvertWolfi(vulnJSONs, r.warnings))
+	if err = r.deleteAndInsertWolfi(r.conn, cves); err != nil {
+		return xerrors.Errorf("Failed to insert Wolfi CVE data. err: %s", err)
+	}
+	return nil
+}
+
+func (r *RDBDriver) deleteAndInsertWolfi(conn *gorm.DB, cves []models.WolfiCVE) (err error) {
+	bar := startProgress(r.insert.Progress, len(cves))
+	tx := conn.Begin()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		tx.Commit()
+	}()
+
+	// Delete all old records
+	var errs util.Errors
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.WolfiPackage{}).Error)
+	errs = errs.Add(tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(models.WolfiCVE{}).Error)
+	errs = util.DeleteNil(errs)
+
+	if len(errs.GetErrors()) > 0 {
+		return xerrors.Errorf("Failed to delete old records. err: %s", errs.Error())
+	}
+
+	for idx := range chunkSlice(len(cves), r.insert.BatchSize) {
+		if err = tx.Create(cves[idx.From:idx.To]).Error; err != nil {
+			return xerrors.Errorf("Failed to insert. err: %w", err)
+		}
+		bar.Add(idx.To - idx.From)
+	}
+	bar.Finish()
+
+	return nil
+}
+
+// ConvertWolfi converts the OSV advisories of Wolfi and Chainguard (e.g. CGA-xxxx-xxxx-xxxx) to WolfiCVE (per CVE).
+// The CVE-IDs are the ID or the aliases. The fixed version is the one of the ECOSYSTEM range, or empty if the package is not fixed yet.
+func ConvertWolfi(vulnJSONs []models.OsvJSON, warnings *util.Warnings) (cves []models.WolfiCVE) {
+	uniqCve := map[string]models.WolfiCVE{}
+	for _, v := range vulnJSONs {
+		if v.Withdrawn != "" {
+			continue
+		}
+		cveIDs := []string{}
+		for _, id := range append([]string{v.ID}, v.Aliases...) {
+			if strings.HasPrefix(id, "CVE-") && !util.StringInSlice(id, cveIDs) {
+				cveIDs = append(cveIDs, id)
+			}
+		}
+
+		pkgs := []models.WolfiPackage{}
+		index := map[string]int{}
+		for _, a := range v.Affected {
+			distro := strings.ToLower(a.Package.Ecosystem)
+			if distro != models.WolfiDistroWolfi && distro != models.WolfiDistroChainguard {
+				continue
+			}
+			p := models.WolfiPackage{Distro: distro, AdvisoryID: v.ID, PackageName: a.Package.Name}
+			for _, rng := range a.Ranges {
+				if rng.Type != "ECOSYSTEM" {
+					continue
+				}
+				for _, e := range rng.Events {
+					if e.Fixed != "" {
+						p.FixedVersion = e.Fixed
+					}
+				}
+			}
+			key := distro + "#" + p.PackageName
+			if i, ok := index[key]; ok {
+				if pkgs[i].FixedVersion == "" {
+					pkgs[i] = p
+				}
+				continue
+			}
+			index[key] = len(pkgs)
+			pkgs = append(pkgs, p)
+		}
+		if len(pkgs) == 0 {
+			continue
+		}
+
+		for _, cveID := range cveIDs {
+			c, ok := uniqCve[cveID]
+			if !ok {
+				c = models.WolfiCVE{CveID: cveID, PublishedDate: parseWolfiDate(v.ID, v.Published, warnings)}
+			}
+			c.Packages = append(c.Packages, pkgs...)
+			uniqCve[cveID] = c
+		}
+	}
+
+	for _, c := range uniqCve {
+		cves = append(cves, c)
+	}
+	return cves
+}
+
+func parseWolfiDate(advisoryID, date string, warnings *util.Warnings) time.Time {
+	if date == "" {
+		return time.Time{}
+	}
+	t, err := util.ParseDate(date, time.RFC3339)
+	if err != nil {
+		warnings.Add("wolfi", advisoryID, "published", fmt.Sprintf("Failed to parse date: %s", date))
+		return time.Time{}
+	}
+	return t
+}
+
+// GetUnfixedCvesWolfi gets the CVEs affecting the package of the distro (wolfi or chainguard) without the fixed version.
+func (r *RDBDriver) GetUnfixedCvesWolfi(distro, pkgName string) map[string]models.WolfiCVE {
+	return r.getCvesWolfi(distro, pkgName, false)
+}
+
+// GetFixedCvesWolfi gets the CVEs fixed in the package of the distro (wolfi or chainguard).
+func (r *RDBDriver) GetFixedCvesWolfi(distro, pkgName string) map[string]models.WolfiCVE {
+	return r.getCvesWolfi(distro, pkgName, true)
+}
+
+func (r *RDBDriver) getCvesWolfi(distro, pkgName string, fixed bool) map[string]models.WolfiCVE {
+	m := map[string]models.WolfiCVE{}
+
+	cond := "package_name = ? AND distro = ? AND fixed_version = ''"
+	if fixed {
+		cond = "package_name = ? AND distro = ? AND fixed_version <> ''"
+	}
+
+	type Result struct {
+		WolfiCveID int64
+	}
+
+	results := []Result{}
+	err := r.conn.
+		Table("wolfi_packages").
+		Select("wolfi_cve_id").
+		Where(cond, pkgName, distro).
+		Scan(&results).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Error("Failed to get cves of Wolfi", "err", err)
+		return m
+	}
+
+	r.explain.addCandidates(len(results))
+	for _, res := range results {
+		cve := models.WolfiCVE{}
+		err := r.conn.
+			Preload("Packages", cond, pkgName, distro).
+			Where(&models.WolfiCVE{ID: res.WolfiCveID}).
+			First(&cve).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Error("Failed to get WolfiCVE", "err", err)
+			return m
+		}
+		if len(cve.Packages) != 0 {
+			m[cve.CveID] = cve
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,155 @@
+package encoder
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// cycloneDX writes the CVEs as the vulnerabilities of a CycloneDX 1.5 BOM (VEX), with a component per package of the releases
+type cycloneDX struct {
+	version string
+}
+
+type cdxBOM struct {
+	BOMFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	Version         int                `json:"version"`
+	Metadata        cdxMetadata        `json:"metadata"`
+	Components      []cdxComponent     `json:"components"`
+	Vulnerabilities []cdxVulnerability `json:"vulnerabilities"`
+}
+
+type cdxMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cdxComponent `json:"components"`
+	} `json:"tools"`
+}
+
+type cdxComponent struct {
+	Type    string `json:"type"`
+	BOMRef  string `json:"bom-ref,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Group   string `json:"group,omitempty"`
+}
+
+type cdxVulnerability struct {
+	ID          string       `json:"id"`
+	Source      cdxSource    `json:"source"`
+	Ratings     []cdxRating  `json:"ratings,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Published   string       `json:"published,omitempty"`
+	Advisories  []cdxAdvisor `json:"advisories,omitempty"`
+	Affects     []cdxAffect  `json:"affects,omitempty"`
+}
+
+type cdxSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cdxRating struct {
+	Source   cdxSource `json:"source"`
+	Score    float64   `json:"score,omitempty"`
+	Severity string    `json:"severity,omitempty"`
+	Method   string    `json:"method,omitempty"`
+	Vector   string    `json:"vector,omitempty"`
+}
+
+type cdxAdvisor struct {
+	URL string `json:"url"`
+}
+
+type cdxAffect struct {
+	Ref      string              `json:"ref"`
+	Versions []cdxAffectsVersion `json:"versions,omitempty"`
+}
+
+type cdxAffectsVersion struct {
+	Version string `json:"version,omitempty"`
+	Range   string `json:"range,omitempty"`
+	Status  string `json:"status"`
+}
+
+// cdxMethods are the rating methods of the CVSS versions
+var cdxMethods = map[string]string{"2.0": "CVSSv2", "3.0": "CVSSv3", "3.1": "CVSSv31"}
+
+func (e cycloneDX) Encode(w io.Writer, r Report) error {
+	if r.Vulns == nil {
+		return ErrNoVulns
+	}
+	bom := cdxBOM{BOMFormat: "CycloneDX", SpecVersion: "1.5", Version: 1, Components: []cdxComponent{}, Vulnerabilities: []cdxVulnerability{}}
+	bom.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	bom.Metadata.Tools.Components = []cdxComponent{{Type: "application", Name: "gost", Version: e.version}}
+
+	refs := map[string]bool{}
+	for _, v := range r.Vulns {
+		vuln := cdxVulnerability{
+			ID:          v.ID,
+			Source:      cdxSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/" + v.ID},
+			Description: v.Description,
+		}
+		if v.PublishedDate != nil {
+			vuln.Published = v.PublishedDate.UTC().Format(time.RFC3339)
+		}
+		for _, c := range v.Cvss {
+			vuln.Ratings = append(vuln.Ratings, cdxRating{Source: cdxSource{Name: c.Source}, Score: c.Score, Method: cdxMethods[c.Version], Vector: c.Vector})
+		}
+		if v.Severity != "" {
+			vuln.Ratings = append(vuln.Ratings, cdxRating{Source: cdxSource{Name: "gost"}, Severity: cdxSeverity(v.Severity), Method: "other"})
+		}
+		for _, ref := range v.References {
+			vuln.Advisories = append(vuln.Advisories, cdxAdvisor{URL: ref})
+		}
+
+		for _, a := range v.Affected {
+			ref := a.Source + "/" + a.Release + "/" + a.PackageName
+			if !refs[ref] {
+				refs[ref] = true
+				bom.Components = append(bom.Components, cdxComponent{Type: "library", BOMRef: ref, Name: a.PackageName, Group: a.Source + "/" + a.Release})
+			}
+			affect := cdxAffect{Ref: ref}
+			switch a.Status {
+			case models.VulnStatusNotAffected:
+				affect.Versions = []cdxAffectsVersion{{Range: "vers:generic/*", Status: "unaffected"}}
+			case models.VulnStatusFixed:
+				if a.FixedVersion == "" {
+					affect.Versions = []cdxAffectsVersion{{Range: "vers:generic/*", Status: "unaffected"}}
+					break
+				}
+				affect.Versions = []cdxAffectsVersion{{Range: "vers:generic/<" + a.FixedVersion, Status: "affected"}, {Version: a.FixedVersion, Status: "unaffected"}}
+			case models.VulnStatusUnknown:
+				affect.Versions = []cdxAffectsVersion{{Range: "vers:generic/*", Status: "unknown"}}
+			default:
+				affect.Versions = []cdxAffectsVersion{{Range: "vers:generic/*", Status: "affected"}}
+			}
+			vuln.Affects = append(vuln.Affects, affect)
+		}
+		bom.Vulnerabilities = append(bom.Vulnerabilities, vuln)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	// keep < of the ranges of vers
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(bom); err != nil {
+		return xerrors.Errorf("Failed to write CycloneDX. err: %w", err)
+	}
+	return nil
+}
+
+// cdxSeverity returns the severity of CycloneDX of the severity of VulnInfo
+func cdxSeverity(severity string) string {
+	switch severity {
+	case "critical", "high", "medium", "low":
+		return severity
+	case "negligible":
+		return "info"
+	}
+	return "unknown"
+}
@@ -0,0 +1,173 @@
+// Package encoder has the output formats of the reports of gost analytics, registered by name,
+// so that a format registered once is available in all the reports
+package encoder
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// ErrNoVulns is returned by the formats of the vulnerabilities (cyclonedx, osv and sarif) for the reports not of CVEs
+var ErrNoVulns = xerrors.New("The format supports only the reports of CVEs (e.g. analytics vulninfo)")
+
+// Report : the report to encode.
+// Value is encoded by json, ndjson and template, Records by csv, and Vulns by the formats of the vulnerabilities.
+type Report struct {
+	Value interface{}
+	// Records are the rows of CSV with the header first
+	Records [][]string
+	// Vulns are the CVEs of the report. It is nil if the report is not of CVEs
+	Vulns []models.VulnInfo
+}
+
+// Options : the options of the encoders
+type Options struct {
+	// Template is the path of the Go template (text/template) of the format template
+	Template string
+	// Version is the version of gost reported by the formats having the tool (cyclonedx and sarif)
+	Version string
+}
+
+// Encoder writes the report in the format
+type Encoder interface {
+	Encode(w io.Writer, r Report) error
+}
+
+// EncoderFunc is the Encoder of the function
+type EncoderFunc func(w io.Writer, r Report) error
+
+// Encode calls f(w, r)
+func (f EncoderFunc) Encode(w io.Writer, r Report) error {
+	return f(w, r)
+}
+
+// Factory returns the Encoder of the format with the options
+type Factory func(opts Options) (Encoder, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+func init() {
+	Register("json", static(encodeJSON))
+	Register("ndjson", static(encodeNDJSON))
+	Register("csv", static(encodeCSV))
+	Register("template", newTemplate)
+	Register("cyclonedx", func(opts Options) (Encoder, error) { return cycloneDX{version: opts.Version}, nil })
+	Register("osv", static(encodeOSV))
+	Register("sarif", func(opts Options) (Encoder, error) { return sarif{version: opts.Version}, nil })
+}
+
+// Register registers the format by name. It panics if the name is registered twice, like database/sql.Register
+func Register(name string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if f == nil {
+		panic("encoder: Register factory is nil")
+	}
+	if _, ok := factories[name]; ok {
+		panic("encoder: Register called twice for " + name)
+	}
+	factories[name] = f
+}
+
+// Names returns the names of the formats registered, sorted
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns the Encoder of the format registered by name
+func New(name string, opts Options) (Encoder, error) {
+	mu.RLock()
+	f, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, xerrors.Errorf("Unknown format: %s. It must be one of %s", name, strings.Join(Names(), ", "))
+	}
+	return f(opts)
+}
+
+func static(f EncoderFunc) Factory {
+	return func(Options) (Encoder, error) { return f, nil }
+}
+
+func encodeJSON(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r.Value); err != nil {
+		return xerrors.Errorf("Failed to write JSON. err: %w", err)
+	}
+	return nil
+}
+
+// encodeNDJSON writes each element of the report in a line if the report is an array, or the report in a line
+func encodeNDJSON(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	v := reflect.ValueOf(r.Value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		if err := enc.Encode(r.Value); err != nil {
+			return xerrors.Errorf("Failed to write JSON. err: %w", err)
+		}
+		return nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			return xerrors.Errorf("Failed to write JSON. err: %w", err)
+		}
+	}
+	return nil
+}
+
+func encodeCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(r.Records); err != nil {
+		return xerrors.Errorf("Failed to write CSV. err: %w", err)
+	}
+	return nil
+}
+
+// newTemplate parses the template of opts.Template, executed with the Report
+func newTemplate(opts Options) (Encoder, error) {
+	if opts.Template == "" {
+		return nil, xerrors.New("The format template requires --template")
+	}
+	b, err := ioutil.ReadFile(opts.Template)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to read the template. path: %s, err: %w", opts.Template, err)
+	}
+	tmpl, err := template.New(filepath.Base(opts.Template)).Funcs(template.FuncMap{
+		"join": strings.Join,
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(string(b))
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to parse the template. path: %s, err: %w", opts.Template, err)
+	}
+	return EncoderFunc(func(w io.Writer, r Report) error {
+		if err := tmpl.Execute(w, r); err != nil {
+			return xerrors.Errorf("Failed to execute the template. err: %w", err)
+		}
+		return nil
+	}), nil
+}
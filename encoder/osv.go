@@ -0,0 +1,127 @@
+package encoder
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// osvEcosystems are the ecosystems of OSV of the sources of VulnInfo. The release is appended as Debian:11
+var osvEcosystems = map[string]string{
+	"debian":    "Debian",
+	"ubuntu":    "Ubuntu",
+	"redhat":    "Red Hat",
+	"microsoft": "Microsoft",
+}
+
+type osvRecord struct {
+	SchemaVersion    string                 `json:"schema_version"`
+	ID               string                 `json:"id"`
+	Modified         string                 `json:"modified"`
+	Published        string                 `json:"published,omitempty"`
+	Summary          string                 `json:"summary,omitempty"`
+	Details          string                 `json:"details,omitempty"`
+	Severity         []osvSeverity          `json:"severity,omitempty"`
+	Affected         []osvAffected          `json:"affected,omitempty"`
+	References       []osvReference         `json:"references,omitempty"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges            []osvRange        `json:"ranges,omitempty"`
+	EcosystemSpecific map[string]string `json:"ecosystem_specific,omitempty"`
+}
+
+type osvRange struct {
+	Type   string              `json:"type"`
+	Events []map[string]string `json:"events"`
+}
+
+type osvReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// encodeOSV writes the CVEs as an array of the records of OSV 1.6. The packages not affected are omitted,
+// and the packages not fixed are affected from the version 0
+func encodeOSV(w io.Writer, r Report) error {
+	if r.Vulns == nil {
+		return ErrNoVulns
+	}
+	modified := time.Now().UTC().Format(time.RFC3339)
+	records := []osvRecord{}
+	for _, v := range r.Vulns {
+		rec := osvRecord{
+			SchemaVersion:    "1.6.0",
+			ID:               v.ID,
+			Modified:         modified,
+			Summary:          v.Title,
+			Details:          v.Description,
+			DatabaseSpecific: map[string]interface{}{"severity": v.Severity, "known_exploited": v.KnownExploited},
+		}
+		if v.PublishedDate != nil {
+			rec.Published = v.PublishedDate.UTC().Format(time.RFC3339)
+		}
+		for _, c := range v.Cvss {
+			if c.Vector == "" {
+				continue
+			}
+			typ := "CVSS_V3"
+			if c.Version == "2.0" {
+				typ = "CVSS_V2"
+			}
+			rec.Severity = append(rec.Severity, osvSeverity{Type: typ, Score: c.Vector})
+		}
+		for _, a := range v.Affected {
+			if a.Status == models.VulnStatusNotAffected {
+				continue
+			}
+			affected := osvAffected{EcosystemSpecific: map[string]string{"status": a.Status}}
+			if a.Advisory != "" {
+				affected.EcosystemSpecific["advisory"] = a.Advisory
+			}
+			affected.Package.Name = a.PackageName
+			affected.Package.Ecosystem = osvEcosystem(a.Source, a.Release)
+			events := []map[string]string{{"introduced": "0"}}
+			if a.Status == models.VulnStatusFixed && a.FixedVersion != "" {
+				events = append(events, map[string]string{"fixed": a.FixedVersion})
+			}
+			affected.Ranges = []osvRange{{Type: "ECOSYSTEM", Events: events}}
+			rec.Affected = append(rec.Affected, affected)
+		}
+		for _, ref := range v.References {
+			rec.References = append(rec.References, osvReference{Type: "WEB", URL: ref})
+		}
+		records = append(records, rec)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(records); err != nil {
+		return xerrors.Errorf("Failed to write OSV. err: %w", err)
+	}
+	return nil
+}
+
+func osvEcosystem(source, release string) string {
+	ecosystem, ok := osvEcosystems[source]
+	if !ok {
+		ecosystem = source
+	}
+	if release == "" {
+		return ecosystem
+	}
+	return ecosystem + ":" + release
+}
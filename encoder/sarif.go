@@ -0,0 +1,118 @@
+package encoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// sarif writes the CVEs as the rules of SARIF 2.1.0, with a result per package affected,
+// so that code scanning (e.g. GitHub) shows them with the severities
+type sarif struct {
+	version string
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			Version        string      `json:"version,omitempty"`
+			InformationURI string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	FullDescription  *sarifMessage          `json:"fullDescription,omitempty"`
+	HelpURI          string                 `json:"helpUri"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string       `json:"ruleId"`
+	RuleIndex int          `json:"ruleIndex"`
+	Level     string       `json:"level"`
+	Message   sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+func (e sarif) Encode(w io.Writer, r Report) error {
+	if r.Vulns == nil {
+		return ErrNoVulns
+	}
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver.Name = "gost"
+	run.Tool.Driver.Version = e.version
+	run.Tool.Driver.InformationURI = "https://github.com/knqyf263/gost"
+	run.Tool.Driver.Rules = []sarifRule{}
+
+	for i, v := range r.Vulns {
+		rule := sarifRule{
+			ID:               v.ID,
+			ShortDescription: sarifMessage{Text: v.ID},
+			HelpURI:          "https://nvd.nist.gov/vuln/detail/" + v.ID,
+			Properties:       map[string]interface{}{"tags": []string{"security"}},
+		}
+		if v.Title != "" {
+			rule.ShortDescription.Text = v.Title
+		}
+		if v.Description != "" {
+			rule.FullDescription = &sarifMessage{Text: v.Description}
+		}
+		// security-severity is the CVSS score read by GitHub code scanning
+		for _, c := range v.Cvss {
+			if c.Version != "2.0" {
+				rule.Properties["security-severity"] = strconv.FormatFloat(c.Score, 'f', 1, 64)
+				break
+			}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		level := sarifLevel(v.Severity)
+		for _, a := range v.Affected {
+			if a.Status == models.VulnStatusNotAffected {
+				continue
+			}
+			text := fmt.Sprintf("%s of %s %s is %s", a.PackageName, a.Source, a.Release, a.Status)
+			if a.FixedVersion != "" {
+				text += ". Fixed version: " + a.FixedVersion
+			}
+			run.Results = append(run.Results, sarifResult{RuleID: v.ID, RuleIndex: i, Level: level, Message: sarifMessage{Text: text}})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sarifLog{Schema: "https://json.schemastore.org/sarif-2.1.0.json", Version: "2.1.0", Runs: []sarifRun{run}}); err != nil {
+		return xerrors.Errorf("Failed to write SARIF. err: %w", err)
+	}
+	return nil
+}
+
+// sarifLevel returns the level of the result of the severity of VulnInfo
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,176 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v2"
+)
+
+// The tarballs of the branches of the advisory databases on GitHub
+const (
+	RubySecArchiveURL = "https://github.com/rubysec/ruby-advisory-db/archive/refs/heads/master.tar.gz"
+	PyPAArchiveURL    = "https://github.com/pypa/advisory-database/archive/refs/heads/main.tar.gz"
+	NpmArchiveURL     = "https://github.com/github/advisory-database/archive/refs/heads/main.tar.gz"
+)
+
+// RetrieveRubySec returns the advisories in gems/ of ruby-advisory-db.
+// archive is the path or the URL of the tarball of the repository (RubySecArchiveURL if empty).
+func RetrieveRubySec(archive string) (advs []models.RubySecAdvisoryYAML, err error) {
+	err = walkAdvisoryDBArchive(archive, RubySecArchiveURL, func(name string, body []byte) error {
+		// e.g. gems/actionpack/CVE-2020-8164.yml
+		if !strings.HasPrefix(name, "gems/") || path.Ext(name) != ".yml" {
+			return nil
+		}
+		a := models.RubySecAdvisoryYAML{}
+		if err := yaml.Unmarshal(body, &a); err != nil {
+			return xerrors.Errorf("Failed to decode ruby-advisory-db YAML. file: %s, err: %w", name, err)
+		}
+		a.ID = strings.TrimSuffix(path.Base(name), ".yml")
+		advs = append(advs, a)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to retrieve ruby-advisory-db. err: %w", err)
+	}
+	return advs, nil
+}
+
+// RetrievePyPA returns the vulnerabilities in vulns/ of PyPA advisory database, which are OSV in YAML.
+// archive is the path or the URL of the tarball of the repository (PyPAArchiveURL if empty).
+func RetrievePyPA(archive string) (vulns []models.OsvJSON, err error) {
+	err = walkAdvisoryDBArchive(archive, PyPAArchiveURL, func(name string, body []byte) error {
+		// e.g. vulns/django/PYSEC-2021-9.yaml
+		if !strings.HasPrefix(name, "vulns/") || path.Ext(name) != ".yaml" {
+			return nil
+		}
+		b, err := yamlToJSON(body)
+		if err != nil {
+			return xerrors.Errorf("Failed to decode PyPA YAML. file: %s, err: %w", name, err)
+		}
+		v := models.OsvJSON{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return xerrors.Errorf("Failed to decode PyPA YAML. file: %s, err: %w", name, err)
+		}
+		v.Raw = b
+		vulns = append(vulns, v)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to retrieve PyPA advisory database. err: %w", err)
+	}
+	return vulns, nil
+}
+
+// RetrieveNpmAdvisories returns the advisories of npm packages reviewed in GitHub Advisory Database, which are OSV in JSON.
+// archive is the path or the URL of the tarball of the repository (NpmArchiveURL if empty).
+func RetrieveNpmAdvisories(archive string) (vulns []models.OsvJSON, err error) {
+	err = walkAdvisoryDBArchive(archive, NpmArchiveURL, func(name string, body []byte) error {
+		// e.g. advisories/github-reviewed/2021/12/GHSA-xxxx-xxxx-xxxx/GHSA-xxxx-xxxx-xxxx.json
+		if !strings.HasPrefix(name, "advisories/github-reviewed/") || path.Ext(name) != ".json" {
+			return nil
+		}
+		v := models.OsvJSON{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return xerrors.Errorf("Failed to decode GitHub Advisory Database JSON. file: %s, err: %w", name, err)
+		}
+		for _, a := range v.Affected {
+			if a.Package.Ecosystem == "npm" {
+				v.Raw = body
+				vulns = append(vulns, v)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to retrieve GitHub Advisory Database. err: %w", err)
+	}
+	return vulns, nil
+}
+
+// walkAdvisoryDBArchive calls fn with the files in the .tar.gz of a git repository (e.g. the archive of a branch on GitHub).
+// The names are relative to the top directory of the tarball (e.g. ruby-advisory-db-master/).
+func walkAdvisoryDBArchive(archive, defaultURL string, fn func(name string, body []byte) error) error {
+	var (
+		body []byte
+		err  error
+	)
+	if archive == "" {
+		archive = defaultURL
+	}
+	if strings.HasPrefix(archive, "http://") || strings.HasPrefix(archive, "https://") {
+		log15.Info("Fetching", "URL", archive)
+		if body, err = util.FetchURL(archive, ""); err != nil {
+			return xerrors.Errorf("Failed to fetch %s. err: %w", archive, err)
+		}
+	} else if body, err = ioutil.ReadFile(archive); err != nil {
+		return xerrors.Errorf("Failed to read %s. err: %w", archive, err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("Failed to decompress %s. err: %w", archive, err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("Failed to read %s. err: %w", archive, err)
+		}
+		ss := strings.SplitN(hdr.Name, "/", 2)
+		if hdr.Typeflag != tar.TypeReg || len(ss) != 2 {
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return xerrors.Errorf("Failed to read %s in %s. err: %w", hdr.Name, archive, err)
+		}
+		if err := fn(ss[1], b); err != nil {
+			return err
+		}
+	}
+}
+
+// yamlToJSON converts YAML to JSON, since the mappings of yaml.v2 have interface{} keys which encoding/json does not accept
+func yamlToJSON(body []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(stringifyYAMLKeys(v))
+}
+
+func stringifyYAMLKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = stringifyYAMLKeys(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = stringifyYAMLKeys(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,38 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+var (
+	almaErrataURL = "https://errata.almalinux.org/%s/errata.json"
+	almaReleases  = []string{"8", "9"}
+)
+
+// RetrieveAlmaErrata returns the errata (ALSA) of all releases from https://errata.almalinux.org/
+func RetrieveAlmaErrata() (errata []models.AlmaErrataJSON, err error) {
+	for _, release := range almaReleases {
+		url := fmt.Sprintf(almaErrataURL, release)
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch AlmaLinux errata. err: %w", err)
+		}
+
+		var es []models.AlmaErrataJSON
+		if err = json.Unmarshal(body, &es); err != nil {
+			return nil, xerrors.Errorf("Failed to decode AlmaLinux errata JSON. url: %s, err: %w", url, err)
+		}
+		for i := range es {
+			es[i].Release = release
+		}
+		errata = append(errata, es...)
+	}
+	return errata, nil
+}
@@ -0,0 +1,47 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+var (
+	alpineSecDBURL     = "https://secdb.alpinelinux.org/"
+	alpineRepositories = []string{"main", "community"}
+	alpineReleaseRegex = regexp.MustCompile(`href="(v\d+\.\d+)/"`)
+)
+
+// RetrieveAlpineSecDB returns secdb JSONs of all releases from https://secdb.alpinelinux.org/
+func RetrieveAlpineSecDB() (secdbs []models.AlpineSecDBJSON, err error) {
+	index, err := util.FetchURL(alpineSecDBURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch the list of releases from Alpine secdb. err: %w", err)
+	}
+
+	for _, match := range alpineReleaseRegex.FindAllStringSubmatch(string(index), -1) {
+		release := match[1]
+		for _, repo := range alpineRepositories {
+			url := fmt.Sprintf("%s%s/%s.json", alpineSecDBURL, release, repo)
+			log15.Info("Fetching", "URL", url)
+			body, err := util.FetchURL(url, "")
+			if err != nil {
+				// community repository does not exist in old releases
+				log15.Warn("Failed to fetch Alpine secdb. Skip", "URL", url, "err", err)
+				continue
+			}
+
+			var secdb models.AlpineSecDBJSON
+			if err = json.Unmarshal(body, &secdb); err != nil {
+				return nil, xerrors.Errorf("Failed to decode Alpine secdb JSON. url: %s, err: %w", url, err)
+			}
+			secdbs = append(secdbs, secdb)
+		}
+	}
+	return secdbs, nil
+}
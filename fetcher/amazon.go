@@ -0,0 +1,72 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/git"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	amazonDir = "amazon"
+)
+
+// FetchAmazonVulnList clones vuln-list and returns ALAS JSONs (Amazon Linux 1, 2, 2022 and 2023)
+func FetchAmazonVulnList() (entries []models.AmazonALASJSON, err error) {
+	// Clone vuln-list repository
+	dir := filepath.Join(util.CacheDir(), "vuln-list")
+	updatedFiles, err := git.CloneOrPull(repoURL, dir, amazonDir)
+	if err != nil {
+		return nil, xerrors.Errorf("error in vulnsrc clone or pull: %w", err)
+	}
+
+	// Only last_updated.json
+	if len(updatedFiles) <= 1 {
+		return nil, nil
+	}
+
+	rootDir := filepath.Join(dir, amazonDir)
+	targets, err := util.FilterTargets(amazonDir, updatedFiles)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to filter target files: %w", err)
+	} else if len(targets) == 0 {
+		log15.Debug("Amazon: no update file")
+		return nil, nil
+	}
+	log15.Debug(fmt.Sprintf("Amazon updated files: %d", len(targets)))
+
+	err = util.FileWalk(rootDir, targets, func(r io.Reader, path string) error {
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		alas := models.AmazonALASJSON{}
+		if err = json.Unmarshal(content, &alas); err != nil {
+			return xerrors.Errorf("failed to decode Amazon JSON: %w", err)
+		}
+
+		// e.g. amazon/2/ALAS2-2021-1234.json
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return xerrors.Errorf("error in filepath rel: %w", err)
+		}
+		alas.Release = strings.Split(filepath.ToSlash(rel), "/")[0]
+
+		entries = append(entries, alas)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("error in Amazon walk: %w", err)
+	}
+
+	return entries, nil
+}
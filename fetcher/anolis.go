@@ -0,0 +1,36 @@
+package fetcher
+
+import (
+	"encoding/xml"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// anolisOVALURLs has OVAL URL per release
+var anolisOVALURLs = map[string]string{
+	"7":  "https://anas.openanolis.cn/api/data/OVAL/anolis-7.oval.xml",
+	"8":  "https://anas.openanolis.cn/api/data/OVAL/anolis-8.oval.xml",
+	"23": "https://anas.openanolis.cn/api/data/OVAL/anolis-23.oval.xml",
+}
+
+// RetrieveAnolisOVALs returns OVAL of all releases of Anolis OS
+func RetrieveAnolisOVALs() (ovals []models.AnolisOVALXML, err error) {
+	for release, url := range anolisOVALURLs {
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch Anolis OS OVAL. err: %w", err)
+		}
+
+		oval := models.AnolisOVALXML{}
+		if err = xml.Unmarshal(body, &oval); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Anolis OS OVAL XML. url: %s, err: %w", url, err)
+		}
+		oval.Release = release
+		ovals = append(ovals, oval)
+	}
+	return ovals, nil
+}
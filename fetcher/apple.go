@@ -0,0 +1,144 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strings"
+
+	strip "github.com/grokify/html-strip-tags-go"
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// AppleSecurityReleasesURL is the page listing the Apple security releases
+	AppleSecurityReleasesURL = "https://support.apple.com/en-us/100100"
+	// ApplePmvURL is Apple Software Lookup Service, listing the versions and the builds of iOS and macOS signed currently
+	ApplePmvURL = "https://gdmf.apple.com/v2/pmv"
+
+	appleSupportURL = "https://support.apple.com"
+)
+
+var (
+	appleRowRegexp  = regexp.MustCompile(`(?s)<tr[^>]*>(.*?)</tr>`)
+	appleCellRegexp = regexp.MustCompile(`(?s)<td[^>]*>(.*?)</td>`)
+	appleHrefRegexp = regexp.MustCompile(`href="([^"]+)"`)
+	// e.g. <link rel="canonical" href="https://support.apple.com/en-us/120894">
+	appleCanonicalRegexp = regexp.MustCompile(`<link[^>]+rel="canonical"[^>]*href="[^"]*/([\w-]+)"`)
+	appleCveRegexp       = regexp.MustCompile(`CVE-\d{4}-\d{4,}`)
+)
+
+// RetrieveAppleReleases returns the security releases in the page of url (AppleSecurityReleasesURL if empty)
+// with the CVE-IDs in the page of each release. The releases without a page have no published CVE entries.
+func RetrieveAppleReleases(url string, opts ...Option) ([]models.AppleReleaseHTML, error) {
+	if url == "" {
+		url = AppleSecurityReleasesURL
+	}
+	log15.Info("Fetching", "URL", url)
+	body, err := util.FetchURL(url, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch the Apple security releases. err: %w", err)
+	}
+	releases := parseAppleReleases(string(body))
+
+	urls := []string{}
+	index := map[string]int{}
+	for i, r := range releases {
+		if r.ReleaseID != "" {
+			urls = append(urls, r.URL)
+			index[r.ReleaseID] = i
+		}
+	}
+	log15.Info("Fetching", "releases", len(urls))
+
+	o := newOptions(opts...)
+	bodies, err := util.FetchConcurrently(urls, o.threads, o.wait)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch the pages of the Apple security releases. err: %w", err)
+	}
+	// the bodies are not in the order of the URLs, so they are matched by the canonical URL in the page
+	for _, b := range bodies {
+		m := appleCanonicalRegexp.FindSubmatch(b)
+		if m == nil {
+			log15.Warn("Failed to find the release of the page. Skip the page")
+			continue
+		}
+		i, ok := index[string(m[1])]
+		if !ok {
+			log15.Warn("Unknown release of the page. Skip the page", "ID", string(m[1]))
+			continue
+		}
+		releases[i].CveIDs = uniqueAppleCveIDs(appleCveRegexp.FindAllString(string(b), -1))
+	}
+	return releases, nil
+}
+
+// parseAppleReleases parses the rows of name, available for and release date in the table of the security releases
+func parseAppleReleases(body string) []models.AppleReleaseHTML {
+	releases := []models.AppleReleaseHTML{}
+	for _, row := range appleRowRegexp.FindAllStringSubmatch(body, -1) {
+		cells := appleCellRegexp.FindAllStringSubmatch(row[1], -1)
+		if len(cells) < 3 {
+			continue
+		}
+		r := models.AppleReleaseHTML{
+			Name:         appleText(cells[0][1]),
+			AvailableFor: appleText(cells[1][1]),
+			ReleaseDate:  appleText(cells[2][1]),
+		}
+		if m := appleHrefRegexp.FindStringSubmatch(cells[0][1]); m != nil {
+			r.URL = html.UnescapeString(m[1])
+			if strings.HasPrefix(r.URL, "/") {
+				r.URL = appleSupportURL + r.URL
+			}
+			r.ReleaseID = r.URL[strings.LastIndex(r.URL, "/")+1:]
+		}
+		// e.g. This update has no published CVE entries.
+		if i := strings.Index(r.Name, "This update"); i > 0 {
+			r.Name = strings.TrimSpace(r.Name[:i])
+		}
+		if r.Name == "" {
+			continue
+		}
+		releases = append(releases, r)
+	}
+	return releases
+}
+
+func appleText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(strip.StripTags(s))), " ")
+}
+
+func uniqueAppleCveIDs(cveIDs []string) []string {
+	uniq := map[string]struct{}{}
+	ids := []string{}
+	for _, id := range cveIDs {
+		if _, ok := uniq[id]; ok {
+			continue
+		}
+		uniq[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// RetrieveApplePmv returns the builds of iOS and macOS from Apple Software Lookup Service (ApplePmvURL if url is empty).
+// The service has only the versions signed currently, so the builds of the older releases are not known.
+func RetrieveApplePmv(url string) (*models.ApplePmvJSON, error) {
+	if url == "" {
+		url = ApplePmvURL
+	}
+	log15.Info("Fetching", "URL", url)
+	body, err := util.FetchURL(url, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch Apple Software Lookup Service. err: %w", err)
+	}
+	pmv := models.ApplePmvJSON{}
+	if err := json.Unmarshal(body, &pmv); err != nil {
+		return nil, xerrors.Errorf("Failed to decode Apple Software Lookup Service. err: %w", err)
+	}
+	return &pmv, nil
+}
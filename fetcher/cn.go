@@ -0,0 +1,55 @@
+package fetcher
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// RetrieveCnnvd returns the entries in the XML data feeds of CNNVD.
+// CNNVD requires an account to download the feeds, so each path is a file downloaded beforehand or a URL.
+func RetrieveCnnvd(paths []string) (entries []models.CnnvdEntryXML, err error) {
+	for _, path := range paths {
+		body, err := readFileOrURL(path)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read CNNVD feed. err: %w", err)
+		}
+		feed := models.CnnvdXML{}
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, xerrors.Errorf("Failed to decode CNNVD feed. path: %s, err: %w", path, err)
+		}
+		entries = append(entries, feed.Entries...)
+	}
+	return entries, nil
+}
+
+// RetrieveCnvd returns the vulnerabilities in the shared XML data of CNVD.
+// CNVD requires an account to download the data, so each path is a file downloaded beforehand or a URL.
+func RetrieveCnvd(paths []string) (vulns []models.CnvdVulnerabilityXML, err error) {
+	for _, path := range paths {
+		body, err := readFileOrURL(path)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read CNVD data. err: %w", err)
+		}
+		data := models.CnvdXML{}
+		if err := xml.Unmarshal(body, &data); err != nil {
+			return nil, xerrors.Errorf("Failed to decode CNVD data. path: %s, err: %w", path, err)
+		}
+		vulns = append(vulns, data.Vulnerabilities...)
+	}
+	return vulns, nil
+}
+
+func readFileOrURL(path string) ([]byte, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		log15.Info("Fetching", "URL", path)
+		return util.FetchURL(path, "")
+	}
+	log15.Info("Reading", "path", path)
+	return ioutil.ReadFile(path)
+}
@@ -0,0 +1,232 @@
+package fetcher
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	cveListArchiveURL  = "https://github.com/CVEProject/cvelistV5/archive/refs/heads/main.zip"
+	cveListDeltaLogURL = "https://raw.githubusercontent.com/CVEProject/cvelistV5/main/cves/deltaLog.json"
+)
+
+// ErrCveDeltaLogExpired is returned when the changes since the last fetch are not in deltaLog.json any more
+var ErrCveDeltaLogExpired = xerrors.New("The last fetch is older than deltaLog.json of cvelistV5")
+
+var cveRecordFileRe = regexp.MustCompile(`^CVE-\d{4}-\d+\.json$`)
+
+// CveList is the archive of cvelistV5 (e.g. main.zip) opened by OpenCveList. The CVE records are read by Next in batches.
+type CveList struct {
+	zr    *zip.ReadCloser
+	files []*zip.File
+	next  int
+	// tmp is the temporary directory of the archive downloaded, removed by Close
+	tmp string
+	// FetchTime is the fetchTime of delta.json in the archive. It is zero without delta.json
+	FetchTime time.Time
+}
+
+// OpenCveList opens the archive of cvelistV5 at archive, or downloads it into a temporary file if archive is empty.
+// The archive is read from the file instead of memory, since it is too large to hold.
+func OpenCveList(archive string) (l *CveList, err error) {
+	l = &CveList{}
+	if archive == "" {
+		if l.tmp, err = ioutil.TempDir("", "gost-cvelist"); err != nil {
+			return nil, xerrors.Errorf("Failed to create temp dir. err: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				os.RemoveAll(l.tmp)
+			}
+		}()
+		archive = filepath.Join(l.tmp, "main.zip")
+		if err := downloadCveList(archive); err != nil {
+			return nil, err
+		}
+	} else {
+		log15.Info("Read cvelistV5", "path", archive)
+	}
+
+	if l.zr, err = zip.OpenReader(archive); err != nil {
+		return nil, xerrors.Errorf("Failed to open zip. err: %w", err)
+	}
+	for _, f := range l.zr.File {
+		switch name := path.Base(f.Name); {
+		case name == "delta.json":
+			if l.FetchTime, err = readCveDeltaFetchTime(f); err != nil {
+				l.zr.Close()
+				return nil, err
+			}
+		case cveRecordFileRe.MatchString(name):
+			l.files = append(l.files, f)
+		}
+	}
+	return l, nil
+}
+
+func downloadCveList(dst string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return xerrors.Errorf("Failed to create %s. err: %w", dst, err)
+	}
+	log15.Info("Fetching", "URL", cveListArchiveURL)
+	err = util.DownloadURL(cveListArchiveURL, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return xerrors.Errorf("Failed to fetch cvelistV5. err: %w", err)
+	}
+	return nil
+}
+
+func readCveDeltaFetchTime(f *zip.File) (time.Time, error) {
+	b, err := readZipFile(f)
+	if err != nil {
+		return time.Time{}, err
+	}
+	delta := models.CveDeltaJSON{}
+	if err := json.Unmarshal(b, &delta); err != nil {
+		return time.Time{}, xerrors.Errorf("Failed to decode delta.json. err: %w", err)
+	}
+	fetchTime, err := time.Parse(time.RFC3339, delta.FetchTime)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("Failed to parse fetchTime of delta.json. fetchTime: %s, err: %w", delta.FetchTime, err)
+	}
+	return fetchTime, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to open %s. err: %w", f.Name, err)
+	}
+	defer rc.Close()
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to read %s. err: %w", f.Name, err)
+	}
+	return b, nil
+}
+
+// Len returns the number of the CVE records in the archive
+func (l *CveList) Len() int {
+	return len(l.files)
+}
+
+// Next returns the next n CVE records, and none after all of them are read
+func (l *CveList) Next(n int) ([]models.CveRecordJSON, error) {
+	end := l.next + n
+	if end > len(l.files) {
+		end = len(l.files)
+	}
+	records := make([]models.CveRecordJSON, 0, end-l.next)
+	for ; l.next < end; l.next++ {
+		f := l.files[l.next]
+		b, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		r := models.CveRecordJSON{}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, xerrors.Errorf("Failed to decode CVE record. file: %s, err: %w", f.Name, err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// Close closes the archive, and removes it if it was downloaded
+func (l *CveList) Close() error {
+	err := l.zr.Close()
+	if l.tmp != "" {
+		if rerr := os.RemoveAll(l.tmp); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// RetrieveCveListDelta returns the CVE records new or updated after since according to deltaLog.json of cvelistV5,
+// and the latest fetchTime in it. If the changes since then are not in deltaLog.json, ErrCveDeltaLogExpired is returned.
+func RetrieveCveListDelta(since time.Time, opts ...Option) ([]models.CveRecordJSON, time.Time, error) {
+	log15.Info("Fetching", "URL", cveListDeltaLogURL)
+	body, err := util.FetchURL(cveListDeltaLogURL, "")
+	if err != nil {
+		return nil, time.Time{}, xerrors.Errorf("Failed to fetch deltaLog.json. err: %w", err)
+	}
+	deltas := []models.CveDeltaJSON{}
+	if err := json.Unmarshal(body, &deltas); err != nil {
+		return nil, time.Time{}, xerrors.Errorf("Failed to decode deltaLog.json. err: %w", err)
+	}
+
+	links, latest, err := cveDeltaLinks(deltas, since)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if len(links) == 0 {
+		return nil, latest, nil
+	}
+
+	o := newOptions(opts...)
+	bodies, err := util.FetchConcurrently(links, o.threads, o.wait)
+	if err != nil {
+		return nil, time.Time{}, xerrors.Errorf("Failed to fetch CVE records. err: %w", err)
+	}
+	records := make([]models.CveRecordJSON, 0, len(bodies))
+	for _, b := range bodies {
+		r := models.CveRecordJSON{}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, time.Time{}, xerrors.Errorf("Failed to decode CVE record. err: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, latest, nil
+}
+
+// cveDeltaLinks returns the URLs of the CVE records in the deltas fetched after since.
+// The deltas cover the changes after since only if a delta fetched at or before since is still in the log.
+func cveDeltaLinks(deltas []models.CveDeltaJSON, since time.Time) (links []string, latest time.Time, err error) {
+	covered := false
+	uniq := map[string]string{}
+	for _, d := range deltas {
+		t, err := time.Parse(time.RFC3339, d.FetchTime)
+		if err != nil {
+			return nil, time.Time{}, xerrors.Errorf("Failed to parse fetchTime of deltaLog.json. fetchTime: %s, err: %w", d.FetchTime, err)
+		}
+		if t.After(latest) {
+			latest = t
+		}
+		if !t.After(since) {
+			covered = true
+			continue
+		}
+		for _, items := range [][]models.CveDeltaItemJSON{d.New, d.Updated} {
+			for _, item := range items {
+				if item.GithubLink != "" {
+					uniq[item.CveID] = item.GithubLink
+				}
+			}
+		}
+	}
+	if !covered {
+		return nil, time.Time{}, ErrCveDeltaLogExpired
+	}
+	for _, link := range uniq {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+	return links, latest, nil
+}
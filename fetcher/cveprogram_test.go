@@ -0,0 +1,75 @@
+package fetcher
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenCveList(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "main.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range map[string]string{
+		"cvelistV5-main/cves/delta.json":                     `{"fetchTime": "2024-05-01T09:00:00Z"}`,
+		"cvelistV5-main/cves/2021/3xxx/CVE-2021-3449.json":   `{"cveMetadata": {"cveId": "CVE-2021-3449"}}`,
+		"cvelistV5-main/cves/2021/3xxx/CVE-2021-3450.json":   `{"cveMetadata": {"cveId": "CVE-2021-3450"}}`,
+		"cvelistV5-main/cves/2023/34xxx/CVE-2023-34414.json": `{"cveMetadata": {"cveId": "CVE-2023-34414"}}`,
+		"cvelistV5-main/README.md":                           "# cvelistV5",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := OpenCveList(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if expected := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC); !l.FetchTime.Equal(expected) {
+		t.Errorf("expected fetchTime: %s\n  actual: %s\n", expected, l.FetchTime)
+	}
+	if l.Len() != 3 {
+		t.Errorf("expected records: 3\n  actual: %d\n", l.Len())
+	}
+
+	var batches []int
+	ids := map[string]bool{}
+	for {
+		records, err := l.Next(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) == 0 {
+			break
+		}
+		batches = append(batches, len(records))
+		for _, r := range records {
+			ids[r.CveMetadata.CveID] = true
+		}
+	}
+	if len(batches) != 2 || batches[0] != 2 || batches[1] != 1 {
+		t.Errorf("expected batches: [2 1]\n  actual: %v\n", batches)
+	}
+	for _, id := range []string{"CVE-2021-3449", "CVE-2021-3450", "CVE-2023-34414"} {
+		if !ids[id] {
+			t.Errorf("%s is not read", id)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package fetcher
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"path/filepath"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const cweURL = "https://cwe.mitre.org/data/xml/cwec_latest.xml.zip"
+
+// RetrieveCwe returns the latest CWE list of MITRE
+func RetrieveCwe() (*models.CweCatalogXML, error) {
+	log15.Info("Fetching", "URL", cweURL)
+	body, err := util.FetchURL(cweURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch CWE list. err: %w", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to open zip. err: %w", err)
+	}
+	for _, f := range zr.File {
+		if filepath.Ext(f.Name) != ".xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to open %s. err: %w", f.Name, err)
+		}
+		catalog := models.CweCatalogXML{}
+		err = xml.NewDecoder(rc).Decode(&catalog)
+		rc.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to decode CWE list. file: %s, err: %w", f.Name, err)
+		}
+		log15.Info("Fetched CWE list", "version", catalog.Version)
+		return &catalog, nil
+	}
+	return nil, xerrors.New("Failed to fetch CWE list. XML is not found in the zip")
+}
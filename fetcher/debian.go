@@ -1,11 +1,33 @@
 package fetcher
 
 import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/models"
 	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+var (
+	debianAdvisoryListURLs = []string{
+		"https://salsa.debian.org/security-tracker-team/security-tracker/-/raw/master/data/DSA/list",
+		"https://salsa.debian.org/security-tracker-team/security-tracker/-/raw/master/data/DLA/list",
+	}
+
+	// e.g. "[21 Mar 2021] DSA-4875-1 openssl - security update"
+	debianAdvisoryHeaderRegex = regexp.MustCompile(`^\[(\d{1,2} \w{3} \d{4})\] (D[SL]A-\d+-\d+) (\S+)(?: - (.*))?$`)
+	// e.g. "	{CVE-2021-3449 CVE-2021-3450}"
+	debianAdvisoryCveRegex = regexp.MustCompile(`^\s+\{(.*)\}$`)
+	// e.g. "	[buster] - openssl 1.1.1d-0+deb10u6"
+	debianAdvisoryReleaseRegex = regexp.MustCompile(`^\s+\[(\w+)\] - (\S+) (\S+)`)
 )
 
 // RetrieveDebianCveDetails returns CVE details from https://security-tracker.debian.org/tracker/data/json
@@ -27,3 +49,137 @@ func RetrieveDebianCveDetails() (cves models.DebianJSON, err error) {
 
 	return cves, nil
 }
+
+// debianELTSURL is the data of the Debian ELTS (Extended LTS) Security Tracker by Freexian, the same format as Debian Security Tracker
+const debianELTSURL = "https://deb.freexian.com/extended-lts/tracker/data/json"
+
+// RetrieveDebianELTSCveDetails returns CVE details of Debian ELTS from https://deb.freexian.com/extended-lts/tracker/data/json
+func RetrieveDebianELTSCveDetails() (cves models.DebianJSON, err error) {
+	log15.Info("Fetching", "URL", debianELTSURL)
+	cveJSON, err := util.FetchURL(debianELTSURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch cve data from Debian ELTS. err: %w", err)
+	}
+	if err = json.Unmarshal(cveJSON, &cves); err != nil {
+		return nil, xerrors.Errorf("Failed to decode Debian ELTS JSON. err: %w", err)
+	}
+	return cves, nil
+}
+
+// MergeDebianELTS merges the statuses of ELTS into cves as the releases of the channel (e.g. buster/elts).
+// The CVEs only in ELTS are added.
+func MergeDebianELTS(cves, elts models.DebianJSON) {
+	for pkgName, eltsCveMap := range elts {
+		if _, ok := cves[pkgName]; !ok {
+			cves[pkgName] = models.DebianCveMap{}
+		}
+		for cveID, eltsCve := range eltsCveMap {
+			cve, ok := cves[pkgName][cveID]
+			if !ok {
+				cve = models.DebianCveJSON{Scope: eltsCve.Scope, Description: eltsCve.Description}
+			}
+			if cve.Releases == nil {
+				cve.Releases = map[string]models.DebianReleaseJSON{}
+			}
+			for release, r := range eltsCve.Releases {
+				cve.Releases[release+"/elts"] = r
+			}
+			cves[pkgName][cveID] = cve
+		}
+	}
+}
+
+// FilterDebianCves returns only the specified CVEs in cves
+func FilterDebianCves(cves models.DebianJSON, cveIDs []string) models.DebianJSON {
+	filtered := models.DebianJSON{}
+	for pkgName, cveMap := range cves {
+		for _, cveID := range cveIDs {
+			cve, ok := cveMap[cveID]
+			if !ok {
+				continue
+			}
+			if _, ok := filtered[pkgName]; !ok {
+				filtered[pkgName] = models.DebianCveMap{}
+			}
+			filtered[pkgName][cveID] = cve
+		}
+	}
+	return filtered
+}
+
+// RetrieveDebianAdvisories returns DSA and DLA from data/DSA/list and data/DLA/list of Debian Security Tracker
+func RetrieveDebianAdvisories() (advisories []models.DebianAdvisoryJSON, err error) {
+	for _, url := range debianAdvisoryListURLs {
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch Debian advisories. err: %s", err)
+		}
+		advisories = append(advisories, parseDebianAdvisoryList(body)...)
+	}
+	return advisories, nil
+}
+
+func parseDebianAdvisoryList(body []byte) (advisories []models.DebianAdvisoryJSON) {
+	var cur *models.DebianAdvisoryJSON
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := debianAdvisoryHeaderRegex.FindStringSubmatch(line); m != nil {
+			if cur != nil {
+				advisories = append(advisories, *cur)
+			}
+			cur = &models.DebianAdvisoryJSON{
+				Date:        m[1],
+				AdvisoryID:  m[2],
+				PackageName: m[3],
+				Description: m[4],
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		if m := debianAdvisoryCveRegex.FindStringSubmatch(line); m != nil {
+			cur.CveIDs = append(cur.CveIDs, strings.Fields(m[1])...)
+		} else if m := debianAdvisoryReleaseRegex.FindStringSubmatch(line); m != nil {
+			// e.g. "<not-affected>", "<end-of-life>"
+			if strings.HasPrefix(m[3], "<") {
+				continue
+			}
+			cur.Releases = append(cur.Releases, models.DebianAdvisoryReleaseJSON{
+				Release:      m[1],
+				PackageName:  m[2],
+				FixedVersion: m[3],
+			})
+		}
+	}
+	if cur != nil {
+		advisories = append(advisories, *cur)
+	}
+	return advisories
+}
+
+// debianOVALURLFormat is the URL of OVAL of a release (e.g. bookworm)
+const debianOVALURLFormat = "https://www.debian.org/security/oval/oval-definitions-%s.xml.bz2"
+
+// RetrieveDebianOVALs returns OVAL of the releases (e.g. bookworm) from https://www.debian.org/security/oval/
+func RetrieveDebianOVALs(codenames []string) (ovals []models.DebianOVALXML, err error) {
+	for _, codename := range codenames {
+		url := fmt.Sprintf(debianOVALURLFormat, codename)
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch Debian OVAL. err: %w", err)
+		}
+
+		oval := models.DebianOVALXML{}
+		if err = xml.NewDecoder(bzip2.NewReader(bytes.NewReader(body))).Decode(&oval); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Debian OVAL XML. url: %s, err: %w", url, err)
+		}
+		oval.Release = codename
+		ovals = append(ovals, oval)
+	}
+	return ovals, nil
+}
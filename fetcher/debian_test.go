@@ -0,0 +1,164 @@
+package fetcher
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/knqyf263/gost/models"
+)
+
+// the excerpts of data/DSA/list and data/DLA/list of Debian Security Tracker
+const (
+	debianDSAListExcerpt = `[25 Mar 2021] DSA-4875-1 openssl - security update
+	{CVE-2021-3449 CVE-2021-3450}
+	[buster] - openssl 1.1.1d-0+deb10u6
+[24 Mar 2021] DSA-4874-1 firefox-esr - security update
+	{CVE-2021-23981 CVE-2021-23982 CVE-2021-23984 CVE-2021-23987}
+	[buster] - firefox-esr 78.9.0esr-1~deb10u1
+[10 Jul 2023] DSA-5451-1 thunderbird - security update
+	{CVE-2023-34414 CVE-2023-34416}
+	[bullseye] - thunderbird 1:102.13.0-1~deb11u1
+	[bookworm] - thunderbird 1:102.13.0-1~deb12u1
+	NOTE: the releases of ESR are tracked by mozilla
+[07 Nov 2019] DSA-4555-2 pam-python - regression update
+	[buster] - pam-python <not-affected> (Vulnerable code not present)
+	[stretch] - pam-python 1.0.6-1.1+deb9u1
+[11 Apr 2000] DSA-012-1 ssh
+`
+	debianDLAListExcerpt = `[31 Mar 2021] DLA-2611-1 ldb - security update
+	{CVE-2020-27840 CVE-2021-20277}
+	[stretch] - ldb 2:1.1.27-1+deb9u2
+[30 Mar 2021] DLA-2610-1 linux-4.19 - security update
+	{CVE-2020-27170 CVE-2020-27171 CVE-2021-3348}
+	[stretch] - linux-4.19 4.19.181-1~deb9u1
+`
+)
+
+func TestDebianAdvisoryRegex(t *testing.T) {
+	var headers = []struct {
+		in       string
+		expected []string
+	}{
+		{in: "[25 Mar 2021] DSA-4875-1 openssl - security update", expected: []string{"25 Mar 2021", "DSA-4875-1", "openssl", "security update"}},
+		{in: "[31 Mar 2021] DLA-2611-1 ldb - security update", expected: []string{"31 Mar 2021", "DLA-2611-1", "ldb", "security update"}},
+		{in: "[07 Nov 2019] DSA-4555-2 pam-python - regression update", expected: []string{"07 Nov 2019", "DSA-4555-2", "pam-python", "regression update"}},
+		{in: "[1 Apr 2000] DSA-012-1 ssh", expected: []string{"1 Apr 2000", "DSA-012-1", "ssh", ""}},
+		{in: "	[buster] - openssl 1.1.1d-0+deb10u6"},
+		{in: "[25 Mar 2021] USN-4875-1 openssl - security update"},
+	}
+	for i, tt := range headers {
+		m := debianAdvisoryHeaderRegex.FindStringSubmatch(tt.in)
+		if tt.expected == nil {
+			if m != nil {
+				t.Errorf("[header %d] expected no match\n  actual: %q\n", i, m)
+			}
+			continue
+		}
+		if m == nil || !reflect.DeepEqual(m[1:], tt.expected) {
+			t.Errorf("[header %d] expected: %q\n  actual: %q\n", i, tt.expected, m)
+		}
+	}
+
+	var cves = []struct {
+		in       string
+		expected string
+	}{
+		{in: "	{CVE-2021-3449 CVE-2021-3450}", expected: "CVE-2021-3449 CVE-2021-3450"},
+		{in: "	{CVE-2020-27840}", expected: "CVE-2020-27840"},
+		{in: "{CVE-2021-3449}"},
+		{in: "	NOTE: {CVE-2021-3449}"},
+	}
+	for i, tt := range cves {
+		m := debianAdvisoryCveRegex.FindStringSubmatch(tt.in)
+		actual := ""
+		if m != nil {
+			actual = m[1]
+		}
+		if actual != tt.expected {
+			t.Errorf("[cve %d] expected: %q\n  actual: %q\n", i, tt.expected, actual)
+		}
+	}
+
+	var releases = []struct {
+		in       string
+		expected []string
+	}{
+		{in: "	[buster] - openssl 1.1.1d-0+deb10u6", expected: []string{"buster", "openssl", "1.1.1d-0+deb10u6"}},
+		{in: "	[bookworm] - thunderbird 1:102.13.0-1~deb12u1", expected: []string{"bookworm", "thunderbird", "1:102.13.0-1~deb12u1"}},
+		{in: "	[buster] - pam-python <not-affected> (Vulnerable code not present)", expected: []string{"buster", "pam-python", "<not-affected>"}},
+		{in: "	NOTE: the releases of ESR are tracked by mozilla"},
+		{in: "[25 Mar 2021] DSA-4875-1 openssl - security update"},
+	}
+	for i, tt := range releases {
+		m := debianAdvisoryReleaseRegex.FindStringSubmatch(tt.in)
+		if tt.expected == nil {
+			if m != nil {
+				t.Errorf("[release %d] expected no match\n  actual: %q\n", i, m)
+			}
+			continue
+		}
+		if m == nil || !reflect.DeepEqual(m[1:], tt.expected) {
+			t.Errorf("[release %d] expected: %q\n  actual: %q\n", i, tt.expected, m)
+		}
+	}
+}
+
+func TestParseDebianAdvisoryList(t *testing.T) {
+	var tests = []struct {
+		in       string
+		expected []models.DebianAdvisoryJSON
+	}{
+		{
+			in: debianDSAListExcerpt,
+			expected: []models.DebianAdvisoryJSON{
+				{
+					AdvisoryID: "DSA-4875-1", Date: "25 Mar 2021", PackageName: "openssl", Description: "security update",
+					CveIDs:   []string{"CVE-2021-3449", "CVE-2021-3450"},
+					Releases: []models.DebianAdvisoryReleaseJSON{{Release: "buster", PackageName: "openssl", FixedVersion: "1.1.1d-0+deb10u6"}},
+				},
+				{
+					AdvisoryID: "DSA-4874-1", Date: "24 Mar 2021", PackageName: "firefox-esr", Description: "security update",
+					CveIDs:   []string{"CVE-2021-23981", "CVE-2021-23982", "CVE-2021-23984", "CVE-2021-23987"},
+					Releases: []models.DebianAdvisoryReleaseJSON{{Release: "buster", PackageName: "firefox-esr", FixedVersion: "78.9.0esr-1~deb10u1"}},
+				},
+				{
+					AdvisoryID: "DSA-5451-1", Date: "10 Jul 2023", PackageName: "thunderbird", Description: "security update",
+					CveIDs: []string{"CVE-2023-34414", "CVE-2023-34416"},
+					Releases: []models.DebianAdvisoryReleaseJSON{
+						{Release: "bullseye", PackageName: "thunderbird", FixedVersion: "1:102.13.0-1~deb11u1"},
+						{Release: "bookworm", PackageName: "thunderbird", FixedVersion: "1:102.13.0-1~deb12u1"},
+					},
+				},
+				// the releases not affected are not fixed versions
+				{
+					AdvisoryID: "DSA-4555-2", Date: "07 Nov 2019", PackageName: "pam-python", Description: "regression update",
+					Releases: []models.DebianAdvisoryReleaseJSON{{Release: "stretch", PackageName: "pam-python", FixedVersion: "1.0.6-1.1+deb9u1"}},
+				},
+				{AdvisoryID: "DSA-012-1", Date: "11 Apr 2000", PackageName: "ssh"},
+			},
+		},
+		{
+			in: debianDLAListExcerpt,
+			expected: []models.DebianAdvisoryJSON{
+				{
+					AdvisoryID: "DLA-2611-1", Date: "31 Mar 2021", PackageName: "ldb", Description: "security update",
+					CveIDs:   []string{"CVE-2020-27840", "CVE-2021-20277"},
+					Releases: []models.DebianAdvisoryReleaseJSON{{Release: "stretch", PackageName: "ldb", FixedVersion: "2:1.1.27-1+deb9u2"}},
+				},
+				{
+					AdvisoryID: "DLA-2610-1", Date: "30 Mar 2021", PackageName: "linux-4.19", Description: "security update",
+					CveIDs:   []string{"CVE-2020-27170", "CVE-2020-27171", "CVE-2021-3348"},
+					Releases: []models.DebianAdvisoryReleaseJSON{{Release: "stretch", PackageName: "linux-4.19", FixedVersion: "4.19.181-1~deb9u1"}},
+				},
+			},
+		},
+		{in: "", expected: nil},
+	}
+
+	for i, tt := range tests {
+		actual := parseDebianAdvisoryList([]byte(tt.in))
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("[%d] expected: %+v\n  actual: %+v\n", i, tt.expected, actual)
+		}
+	}
+}
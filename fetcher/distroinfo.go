@@ -0,0 +1,84 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// DistroInfoURL : the directory of the CSV files of distro-info-data
+const DistroInfoURL = "https://salsa.debian.org/debian/distro-info-data/-/raw/main"
+
+// RetrieveDistroInfo returns the releases of Debian and Ubuntu in debian.csv and ubuntu.csv under baseURL (DistroInfoURL if empty)
+func RetrieveDistroInfo(baseURL string) ([]models.DistroInfoCSV, error) {
+	if baseURL == "" {
+		baseURL = DistroInfoURL
+	}
+	rows := []models.DistroInfoCSV{}
+	for _, distro := range []string{models.DistroDebian, models.DistroUbuntu} {
+		url := fmt.Sprintf("%s/%s.csv", baseURL, distro)
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch distro-info-data of %s. err: %w", distro, err)
+		}
+		rs, err := parseDistroInfoCSV(distro, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, rs...)
+	}
+	return rows, nil
+}
+
+// parseDistroInfoCSV reads the columns by the names in the header, since the columns of EOL differ by distribution (e.g. eol-lts, eol-esm)
+func parseDistroInfoCSV(distro string, r io.Reader) ([]models.DistroInfoCSV, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to read the header of distro-info-data of %s. err: %w", distro, err)
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		cols[h] = i
+	}
+	for _, h := range []string{"version", "codename", "series"} {
+		if _, ok := cols[h]; !ok {
+			return nil, xerrors.Errorf("Failed to parse distro-info-data of %s. Unknown header: %v", distro, header)
+		}
+	}
+
+	rows := []models.DistroInfoCSV{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read distro-info-data of %s. err: %w", distro, err)
+		}
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		rows = append(rows, models.DistroInfoCSV{
+			Distro:   distro,
+			Version:  get("version"),
+			Codename: get("codename"),
+			Series:   get("series"),
+			Created:  get("created"),
+			Release:  get("release"),
+			EOL:      get("eol"),
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,90 @@
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const epssURL = "https://epss.cyentia.com/epss_scores-current.csv.gz"
+
+// RetrieveEpss returns the EPSS scores of all CVEs published daily
+func RetrieveEpss() (*models.EpssCSV, error) {
+	log15.Info("Fetching", "URL", epssURL)
+	body, err := util.FetchURL(epssURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch EPSS scores. err: %w", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to decompress EPSS scores. err: %w", err)
+	}
+	defer gr.Close()
+
+	return parseEpssCSV(gr)
+}
+
+func parseEpssCSV(r io.Reader) (*models.EpssCSV, error) {
+	br := bufio.NewReader(r)
+	epss := models.EpssCSV{}
+
+	// #model_version:v2023.03.01,score_date:2023-03-07T00:00:00+0000
+	if b, err := br.Peek(1); err == nil && b[0] == '#' {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read the comment line of EPSS scores. err: %w", err)
+		}
+		for _, field := range strings.Split(strings.TrimSpace(strings.TrimPrefix(line, "#")), ",") {
+			ss := strings.SplitN(field, ":", 2)
+			if len(ss) != 2 {
+				continue
+			}
+			switch ss[0] {
+			case "model_version":
+				epss.ModelVersion = ss[1]
+			case "score_date":
+				epss.ScoreDate = ss[1]
+			}
+		}
+	}
+
+	cr := csv.NewReader(br)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to read the header of EPSS scores. err: %w", err)
+	}
+	if len(header) < 3 || header[0] != "cve" || header[1] != "epss" || header[2] != "percentile" {
+		return nil, xerrors.Errorf("Failed to parse EPSS scores. Unknown header: %v", header)
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read EPSS scores. err: %w", err)
+		}
+
+		score, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to parse EPSS score. cve: %s, err: %w", record[0], err)
+		}
+		percentile, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to parse EPSS percentile. cve: %s, err: %w", record[0], err)
+		}
+		epss.Scores = append(epss.Scores, models.EpssScoreCSV{CveID: record[0], Epss: score, Percentile: percentile})
+	}
+	return &epss, nil
+}
@@ -0,0 +1,98 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	exploitDBURL  = "https://gitlab.com/exploit-database/exploitdb/-/raw/main/files_exploits.csv"
+	metasploitURL = "https://raw.githubusercontent.com/rapid7/metasploit-framework/master/db/modules_metadata_base.json"
+)
+
+// RetrieveExploitDB returns the exploits in Exploit-DB
+func RetrieveExploitDB() ([]models.ExploitDBCSV, error) {
+	log15.Info("Fetching", "URL", exploitDBURL)
+	body, err := util.FetchURL(exploitDBURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch Exploit-DB. err: %w", err)
+	}
+	return parseExploitDBCSV(bytes.NewReader(body))
+}
+
+// parseExploitDBCSV reads the columns by the names in the header, since new columns are added from time to time
+func parseExploitDBCSV(r io.Reader) ([]models.ExploitDBCSV, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to read the header of Exploit-DB. err: %w", err)
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		cols[h] = i
+	}
+	for _, h := range []string{"id", "file", "description", "codes"} {
+		if _, ok := cols[h]; !ok {
+			return nil, xerrors.Errorf("Failed to parse Exploit-DB. Unknown header: %v", header)
+		}
+	}
+
+	exploits := []models.ExploitDBCSV{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read Exploit-DB. err: %w", err)
+		}
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		exploits = append(exploits, models.ExploitDBCSV{
+			ID:            get("id"),
+			File:          get("file"),
+			Description:   get("description"),
+			DatePublished: get("date_published"),
+			Type:          get("type"),
+			Platform:      get("platform"),
+			Verified:      get("verified"),
+			Codes:         get("codes"),
+		})
+	}
+	return exploits, nil
+}
+
+// RetrieveMetasploit returns the modules of Metasploit Framework in the order of the full name
+func RetrieveMetasploit() ([]models.MetasploitModuleJSON, error) {
+	log15.Info("Fetching", "URL", metasploitURL)
+	body, err := util.FetchURL(metasploitURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch Metasploit modules. err: %w", err)
+	}
+
+	m := map[string]models.MetasploitModuleJSON{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, xerrors.Errorf("Failed to decode Metasploit modules. err: %w", err)
+	}
+	modules := make([]models.MetasploitModuleJSON, 0, len(m))
+	for _, mod := range m {
+		modules = append(modules, mod)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Fullname < modules[j].Fullname
+	})
+	return modules, nil
+}
@@ -0,0 +1,78 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const ghsaGraphQLURL = "https://api.github.com/graphql"
+
+// GhsaEcosystems are the ecosystems fetched by default
+var GhsaEcosystems = []string{"NPM", "PIP", "GO", "MAVEN", "RUBYGEMS"}
+
+const ghsaQuery = `query($ecosystem: SecurityAdvisoryEcosystem, $cursor: String) {
+  securityVulnerabilities(ecosystem: $ecosystem, first: 100, after: $cursor) {
+    pageInfo { hasNextPage endCursor }
+    nodes {
+      advisory {
+        ghsaId summary description severity publishedAt updatedAt withdrawnAt
+        identifiers { type value }
+        references { url }
+        cvss { score vectorString }
+        cwes(first: 20) { nodes { cweId } }
+      }
+      package { ecosystem name }
+      vulnerableVersionRange
+      firstPatchedVersion { identifier }
+    }
+  }
+}`
+
+// RetrieveGhsa returns the vulnerabilities of GitHub Security Advisories in the ecosystems.
+// GitHub GraphQL API requires a token.
+func RetrieveGhsa(token string, ecosystems []string) (vulns []models.GhsaVulnerabilityJSON, err error) {
+	if token == "" {
+		return nil, xerrors.New("Failed to fetch GitHub Security Advisories. GitHub token is required")
+	}
+	header := map[string]string{"Authorization": "bearer " + token}
+
+	for _, ecosystem := range ecosystems {
+		ecosystem = strings.ToUpper(ecosystem)
+		log15.Info("Fetching GitHub Security Advisories", "ecosystem", ecosystem)
+		for cursor := ""; ; {
+			variables := map[string]interface{}{"ecosystem": ecosystem}
+			if cursor != "" {
+				variables["cursor"] = cursor
+			}
+			body, err := json.Marshal(map[string]interface{}{"query": ghsaQuery, "variables": variables})
+			if err != nil {
+				return nil, xerrors.Errorf("Failed to marshal GraphQL query. err: %w", err)
+			}
+
+			resBody, err := util.PostURLWithHeader(ghsaGraphQLURL, header, body)
+			if err != nil {
+				return nil, xerrors.Errorf("Failed to fetch GitHub Security Advisories. ecosystem: %s, err: %w", ecosystem, err)
+			}
+			res := models.GhsaGraphQLResponseJSON{}
+			if err := json.Unmarshal(resBody, &res); err != nil {
+				return nil, xerrors.Errorf("Failed to decode GitHub GraphQL API JSON. err: %w", err)
+			}
+			if len(res.Errors) > 0 {
+				return nil, xerrors.Errorf("Failed to fetch GitHub Security Advisories. ecosystem: %s, err: %s", ecosystem, res.Errors[0].Message)
+			}
+
+			conn := res.Data.SecurityVulnerabilities
+			vulns = append(vulns, conn.Nodes...)
+			if !conn.PageInfo.HasNextPage {
+				break
+			}
+			cursor = conn.PageInfo.EndCursor
+		}
+	}
+	return vulns, nil
+}
@@ -0,0 +1,45 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const goVulnDBURL = "https://vuln.go.dev/"
+
+// RetrieveGoVulns returns the entries of the Go vulnerability database listed in the index
+func RetrieveGoVulns(opts ...Option) (vulns []models.GoVulnJSON, err error) {
+	body, err := util.FetchURL(goVulnDBURL+"index/vulns.json", "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch Go vulnerability database index. err: %w", err)
+	}
+	index := []models.GoVulnIndexJSON{}
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, xerrors.Errorf("Failed to decode Go vulnerability database index. err: %w", err)
+	}
+
+	urls := make([]string, 0, len(index))
+	for _, i := range index {
+		urls = append(urls, fmt.Sprintf("%sID/%s.json", goVulnDBURL, i.ID))
+	}
+	log15.Info("Fetching", "entries", len(urls))
+
+	o := newOptions(opts...)
+	bodies, err := util.FetchConcurrently(urls, o.threads, o.wait)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch Go vulnerability database. err: %w", err)
+	}
+	for _, b := range bodies {
+		v := models.GoVulnJSON{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Go vulnerability. err: %w", err)
+		}
+		vulns = append(vulns, v)
+	}
+	return vulns, nil
+}
@@ -0,0 +1,41 @@
+package fetcher
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	jvnYearlyFeedURL = "https://jvndb.jvn.jp/ja/rss/years/jvndb_%d.rdf"
+	// jvnFirstYear is the oldest year of the feeds of JVN iPedia
+	jvnFirstYear = 1998
+)
+
+// RetrieveJvn returns the advisories in the yearly feeds of JVN iPedia from 1998 to this year
+func RetrieveJvn(opts ...Option) (items []models.JvnItemXML, err error) {
+	urls := []string{}
+	for year := jvnFirstYear; year <= time.Now().Year(); year++ {
+		urls = append(urls, fmt.Sprintf(jvnYearlyFeedURL, year))
+	}
+	log15.Info("Fetching", "feeds", len(urls))
+
+	o := newOptions(opts...)
+	bodies, err := util.FetchConcurrently(urls, o.threads, o.wait)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch JVN feeds. err: %w", err)
+	}
+	for _, b := range bodies {
+		rdf := models.JvnRdfXML{}
+		if err := xml.Unmarshal(b, &rdf); err != nil {
+			return nil, xerrors.Errorf("Failed to decode JVN feed. err: %w", err)
+		}
+		items = append(items, rdf.Items...)
+	}
+	return items, nil
+}
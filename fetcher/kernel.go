@@ -0,0 +1,34 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+)
+
+// KernelVulnsArchiveURL : the snapshot of the master branch of the CVE records of the Linux kernel CNA
+const KernelVulnsArchiveURL = "https://git.kernel.org/pub/scm/linux/security/vulns.git/snapshot/vulns-master.tar.gz"
+
+// RetrieveKernelCves returns the published CVE records in cve/published/ of vulns.git of kernel.org.
+// archive is the path or the URL of the tarball of the repository (KernelVulnsArchiveURL if empty).
+func RetrieveKernelCves(archive string) (records []models.KernelCveJSON, err error) {
+	err = walkAdvisoryDBArchive(archive, KernelVulnsArchiveURL, func(name string, body []byte) error {
+		// e.g. cve/published/2024/CVE-2024-26581.json
+		if !strings.HasPrefix(name, "cve/published/") || path.Ext(name) != ".json" {
+			return nil
+		}
+		r := models.KernelCveJSON{}
+		if err := json.Unmarshal(body, &r); err != nil {
+			return xerrors.Errorf("Failed to decode kernel CVE record. file: %s, err: %w", name, err)
+		}
+		records = append(records, r)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to retrieve the CVE records of kernel.org. err: %w", err)
+	}
+	return records, nil
+}
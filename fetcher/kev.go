@@ -0,0 +1,28 @@
+package fetcher
+
+import (
+	"encoding/json"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const kevURL = "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json"
+
+// RetrieveKEV returns the CVEs in CISA Known Exploited Vulnerabilities catalog
+func RetrieveKEV() ([]models.KEVEntryJSON, error) {
+	log15.Info("Fetching", "URL", kevURL)
+	body, err := util.FetchURL(kevURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch CISA KEV catalog. err: %w", err)
+	}
+
+	catalog := models.KEVCatalogJSON{}
+	if err := json.Unmarshal(body, &catalog); err != nil {
+		return nil, xerrors.Errorf("Failed to decode CISA KEV catalog JSON. err: %w", err)
+	}
+	log15.Info("Fetched CISA KEV catalog", "version", catalog.CatalogVersion, "released", catalog.DateReleased)
+	return catalog.Vulnerabilities, nil
+}
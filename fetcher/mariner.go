@@ -0,0 +1,36 @@
+package fetcher
+
+import (
+	"encoding/xml"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// marinerOVALURLs has OVAL URL per release. 1.0 and 2.0 are CBL-Mariner, 3.0 is Azure Linux
+var marinerOVALURLs = map[string]string{
+	"1.0": "https://raw.githubusercontent.com/microsoft/AzureLinuxVulnerabilityData/main/cbl-mariner-1.0-oval.xml",
+	"2.0": "https://raw.githubusercontent.com/microsoft/AzureLinuxVulnerabilityData/main/cbl-mariner-2.0-oval.xml",
+	"3.0": "https://raw.githubusercontent.com/microsoft/AzureLinuxVulnerabilityData/main/azurelinux-3.0-oval.xml",
+}
+
+// RetrieveMarinerOVALs returns OVAL of all releases of CBL-Mariner and Azure Linux
+func RetrieveMarinerOVALs() (ovals []models.MarinerOVALXML, err error) {
+	for release, url := range marinerOVALURLs {
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch CBL-Mariner/Azure Linux OVAL. err: %w", err)
+		}
+
+		oval := models.MarinerOVALXML{}
+		if err = xml.Unmarshal(body, &oval); err != nil {
+			return nil, xerrors.Errorf("Failed to decode CBL-Mariner/Azure Linux OVAL XML. url: %s, err: %w", url, err)
+		}
+		oval.Release = release
+		ovals = append(ovals, oval)
+	}
+	return ovals, nil
+}
@@ -4,48 +4,101 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/models"
 	"github.com/knqyf263/gost/util"
 	"github.com/pkg/errors"
 	"github.com/tealeg/xlsx"
+	"golang.org/x/xerrors"
 )
 
 var (
-	updateListURL                   = "https://api.msrc.microsoft.com/Updates?api-version=2016-08-01"
+	// updateListURL lists the monthly CVRF documents. MSRC CVRF API requires no API key since 2021
+	updateListURL                   = "https://api.msrc.microsoft.com/cvrf/v3.0/updates"
 	bulletinSearchURL               = "https://download.microsoft.com/download/6/7/3/673E4349-1CA5-40B9-8879-095C72D5B49D/BulletinSearch.xlsx"
 	bulletinSearchFrom2001To2008URL = "https://download.microsoft.com/download/6/7/3/673E4349-1CA5-40B9-8879-095C72D5B49D/BulletinSearch2001-2008.xlsx"
-	msDateRegexp                    = regexp.MustCompile(`\d+[-\/]\d+[-\/]\d+`)
+	msDateRegexp                    = regexp.MustCompile(`(\d+)([-/.])(\d+)[-/.](\d+)`)
 )
 
-// RetrieveMicrosoftCveDetails :
-// https://api.msrc.microsoft.com/cvrf/2017-Jan?api-version=2016-08-01
-func RetrieveMicrosoftCveDetails(apikey string) (cves []models.MicrosoftXML, err error) {
-	u, err := util.FetchURL(updateListURL, apikey)
+// ListMicrosoftCvrfUpdates returns the monthly CVRF documents in the order of the month
+func ListMicrosoftCvrfUpdates() (updates []models.MicrosoftCvrfUpdate, err error) {
+	u, err := util.FetchURL(updateListURL, "")
 	if err != nil {
-		return nil, err
+		return nil, xerrors.Errorf("Failed to fetch the list of CVRF documents from Microsoft. err: %w", err)
 	}
 	var updateList models.Updatelist
 	if err = json.Unmarshal(u, &updateList); err != nil {
-		return nil, err
+		return nil, xerrors.Errorf("Failed to decode the list of CVRF documents. err: %w", err)
+	}
+
+	updates = updateList.Value
+	sort.SliceStable(updates, func(i, j int) bool {
+		return MicrosoftCvrfMonth(updates[i].ID).Before(MicrosoftCvrfMonth(updates[j].ID))
+	})
+	return updates, nil
+}
+
+// MicrosoftCvrfMonth returns the month of the CVRF document ID (e.g. 2023-Jan).
+// If the ID is not a month, it returns the zero time.
+func MicrosoftCvrfMonth(cvrfID string) time.Time {
+	if len(cvrfID) < len("2006-Jan") {
+		return time.Time{}
 	}
+	t, err := util.ParseDate(cvrfID[:len("2006-Jan")], "2006-Jan")
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// LatestMicrosoftCvrfID returns the ID of the latest month in cvrfIDs, or empty if no ID is a month
+func LatestMicrosoftCvrfID(cvrfIDs []string) (latest string) {
+	for _, id := range cvrfIDs {
+		if MicrosoftCvrfMonth(id).IsZero() {
+			continue
+		}
+		if latest == "" || MicrosoftCvrfMonth(id).After(MicrosoftCvrfMonth(latest)) {
+			latest = id
+		}
+	}
+	return latest
+}
+
+// RetrieveMicrosoftCveDetails fetches the monthly CVRF documents newer than the month of after (e.g. 2023-Jan) one by one,
+// and passes each document to fn in the order of the month. If after is empty, all documents are fetched.
+// https://api.msrc.microsoft.com/cvrf/v3.0/cvrf/2023-Jan
+func RetrieveMicrosoftCveDetails(after string, fn func(models.MicrosoftXML) error) error {
+	updates, err := ListMicrosoftCvrfUpdates()
+	if err != nil {
+		return err
+	}
+
+	afterMonth := MicrosoftCvrfMonth(after)
+	for _, update := range updates {
+		if after != "" && !MicrosoftCvrfMonth(update.ID).After(afterMonth) {
+			continue
+		}
 
-	for _, update := range updateList.Value {
 		log15.Info("Fetching", "URL", update.CvrfURL)
-		cveXML, err := util.FetchURL(update.CvrfURL, apikey)
+		cveXML, err := util.FetchURLWithHeader(update.CvrfURL, map[string]string{"Accept": "application/xml"})
 		if err != nil {
-			return nil,
-				errors.Wrapf(err, "Failed to fetch cve data from Microsoft. targetURL: %s", update.CvrfURL)
+			return errors.Wrapf(err, "Failed to fetch cve data from Microsoft. targetURL: %s", update.CvrfURL)
 		}
 
 		var cve models.MicrosoftXML
 		if err = xml.Unmarshal(cveXML, &cve); err != nil {
-			return nil, err
+			return errors.Wrapf(err, "Failed to decode CVRF document. targetURL: %s", update.CvrfURL)
+		}
+		if err := fn(cve); err != nil {
+			return err
 		}
-		cves = append(cves, cve)
 	}
-	return cves, nil
+	return nil
 }
 
 // RetrieveMicrosoftBulletinSearch :
@@ -64,24 +117,37 @@ func RetrieveMicrosoftBulletinSearch() (cves []models.MicrosoftBulletinSearch, e
 	return cves, nil
 }
 
-// XlsToModel :
+// XlsToModel converts the sheets of BulletinSearch.xlsx.
+// The columns are looked up by the header, since the layouts of the files differ by the year they were published,
+// and Date Posted is read from the value of the cell rather than the text formatted by the locale of the file.
 func XlsToModel(bs []byte) (cves []models.MicrosoftBulletinSearch, err error) {
 	xlFile, err := xlsx.OpenBinary(bs)
 	if err != nil {
 		return nil, err
 	}
 	for _, sheet := range xlFile.Sheets {
-		for i, row := range sheet.Rows {
-			// ignore header
-			if i == 0 {
-				continue
-			}
+		if len(sheet.Rows) == 0 {
+			continue
+		}
+		columns := bulletinSearchColumnIndexes(sheet.Rows[0])
+		for _, row := range sheet.Rows[1:] {
 			var cve models.MicrosoftBulletinSearch
-			err := row.ReadStruct(&cve)
-			if err != nil {
-				return nil, err
+			for _, c := range bulletinSearchColumns {
+				idx := columns[c.name]
+				if idx < 0 || idx >= len(row.Cells) {
+					continue
+				}
+				cell := row.Cells[idx]
+				if c.name == "date posted" {
+					cve.DatePosted = bulletinDatePosted(cell, xlFile.Date1904)
+					continue
+				}
+				value, err := cell.FormattedValue()
+				if err != nil {
+					return nil, xerrors.Errorf("Failed to read %s of BulletinSearch. sheet: %s, err: %w", c.name, sheet.Name, err)
+				}
+				*c.field(&cve) = strings.TrimSpace(value)
 			}
-			cve.DatePosted = msDateRegexp.FindString(cve.DatePosted)
 			if len(cve.BulletinKB) == 0 {
 				continue
 			}
@@ -90,3 +156,95 @@ func XlsToModel(bs []byte) (cves []models.MicrosoftBulletinSearch, err error) {
 	}
 	return cves, nil
 }
+
+// bulletinSearchColumn is a column of BulletinSearch.xlsx: the header in lower case, its aliases in the other layouts,
+// and the position in the current layout used when no header is known
+type bulletinSearchColumn struct {
+	name    string
+	aliases []string
+	index   int
+	field   func(*models.MicrosoftBulletinSearch) *string
+}
+
+var bulletinSearchColumns = []bulletinSearchColumn{
+	{name: "date posted", aliases: []string{"posted date", "date"}, index: 0, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.DatePosted }},
+	{name: "bulletin id", aliases: []string{"bulletinid", "bulletin"}, index: 1, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.BulletinID }},
+	{name: "bulletin kb", aliases: []string{"bulletinkb", "kb"}, index: 2, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.BulletinKB }},
+	{name: "severity", aliases: []string{"max severity", "maximum severity rating"}, index: 3, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.Severity }},
+	{name: "impact", aliases: []string{"max impact"}, index: 4, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.Impact }},
+	{name: "title", aliases: []string{"bulletin title"}, index: 5, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.Title }},
+	{name: "affected product", aliases: []string{"product"}, index: 6, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.AffectedProduct }},
+	{name: "component kb", aliases: []string{"componentkb"}, index: 7, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.ComponentKB }},
+	{name: "affected component", aliases: []string{"component"}, index: 8, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.AffectedComponent }},
+	{name: "supersedes", aliases: []string{"supercedes"}, index: 11, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.Supersedes }},
+	{name: "reboot", aliases: []string{"restart required"}, index: 12, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.Reboot }},
+	{name: "cves", aliases: []string{"cve", "cve id", "cve ids"}, index: 13, field: func(b *models.MicrosoftBulletinSearch) *string { return &b.CVEs }},
+}
+
+// bulletinSearchColumnIndexes returns the position of each column by the header row, or -1 for the column not in the header.
+// If no header is known (e.g. localized), the columns are at the positions of the current layout.
+func bulletinSearchColumnIndexes(header *xlsx.Row) map[string]int {
+	positions := map[string]int{}
+	for i, cell := range header.Cells {
+		positions[strings.ToLower(strings.Join(strings.Fields(cell.Value), " "))] = i
+	}
+	indexes, known := map[string]int{}, false
+	for _, c := range bulletinSearchColumns {
+		indexes[c.name] = -1
+		for _, h := range append([]string{c.name}, c.aliases...) {
+			if i, ok := positions[h]; ok {
+				indexes[c.name], known = i, true
+				break
+			}
+		}
+	}
+	if !known {
+		for _, c := range bulletinSearchColumns {
+			indexes[c.name] = c.index
+		}
+	}
+	return indexes
+}
+
+// bulletinDateLayout is the layout of DatePosted parsed on insert
+const bulletinDateLayout = "1/2/2006"
+
+// bulletinDatePosted returns Date Posted in bulletinDateLayout.
+// The date serial of Excel is converted as it is. The text is parsed as year first (2016-03-08), day first with dots (08.03.2016),
+// or month first (3/8/16) unless the first number cannot be a month (23/08/2016).
+// The text not parsed is returned as it is, and warned on insert.
+func bulletinDatePosted(cell *xlsx.Cell, date1904 bool) string {
+	value := strings.TrimSpace(cell.Value)
+	// 2958466 is the serial of 10000-01-01
+	if f, err := strconv.ParseFloat(value, 64); err == nil && f > 0 && f < 2958466 {
+		return xlsx.TimeFromExcelTime(f, date1904).Format(bulletinDateLayout)
+	}
+	m := msDateRegexp.FindStringSubmatch(value)
+	if m == nil {
+		return value
+	}
+	nums := make([]int, 3)
+	for i, g := range []int{1, 3, 4} {
+		nums[i], _ = strconv.Atoi(m[g])
+	}
+	var y, mon, d int
+	switch {
+	case len(m[1]) == 4:
+		y, mon, d = nums[0], nums[1], nums[2]
+	case m[2] == "." || nums[0] > 12:
+		d, mon, y = nums[0], nums[1], nums[2]
+	default:
+		mon, d, y = nums[0], nums[1], nums[2]
+	}
+	switch {
+	case y < 70:
+		y += 2000
+	case y < 100:
+		y += 1900
+	}
+	t := time.Date(y, time.Month(mon), d, 0, 0, 0, 0, time.UTC)
+	if t.Year() != y || int(t.Month()) != mon || t.Day() != d {
+		return value
+	}
+	return t.Format(bulletinDateLayout)
+}
@@ -0,0 +1,123 @@
+package fetcher
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/knqyf263/gost/models"
+	"github.com/tealeg/xlsx"
+)
+
+// bulletinSearchXlsx builds BulletinSearch.xlsx of a sheet with the header and the rows.
+// The cells of time.Time are the date serials formatted by dateFormat, and the others are the text.
+func bulletinSearchXlsx(t *testing.T, dateFormat string, header []string, rows ...[]interface{}) []byte {
+	t.Helper()
+	f := xlsx.NewFile()
+	sheet, err := f.AddSheet("Bulletin Search")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := sheet.AddRow()
+	for _, h := range header {
+		r.AddCell().SetString(h)
+	}
+	for _, row := range rows {
+		r := sheet.AddRow()
+		for _, v := range row {
+			switch v := v.(type) {
+			case time.Time:
+				r.AddCell().SetDateWithOptions(v, xlsx.DateTimeOptions{Location: time.UTC, ExcelTimeFormat: dateFormat})
+			case string:
+				r.AddCell().SetString(v)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestXlsToModel(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   []byte
+		out  []models.MicrosoftBulletinSearch
+	}{
+		{
+			name: "current layout with the date formatted by the German locale",
+			in: bulletinSearchXlsx(t, "dd.mm.yyyy",
+				[]string{"Date Posted", "Bulletin Id", "Bulletin KB", "Severity", "Impact", "Title", "Affected Product", "Component KB", "Affected Component", "Impacted Product", "Impacted Component", "Supersedes", "Reboot", "CVEs"},
+				[]interface{}{time.Date(2016, 3, 8, 0, 0, 0, 0, time.UTC), "MS16-023", "3142015", "Critical", "Remote Code Execution", "Cumulative Security Update for Internet Explorer", "Windows 7", "3139929", "Internet Explorer 11", "", "", "MS16-009[3134220]", "Yes", "CVE-2016-0102,CVE-2016-0103"},
+				[]interface{}{time.Date(2016, 3, 8, 0, 0, 0, 0, time.UTC), "MS16-023", "", "Critical"},
+			),
+			out: []models.MicrosoftBulletinSearch{
+				{DatePosted: "3/8/2016", BulletinID: "MS16-023", BulletinKB: "3142015", Severity: "Critical", Impact: "Remote Code Execution", Title: "Cumulative Security Update for Internet Explorer", AffectedProduct: "Windows 7", ComponentKB: "3139929", AffectedComponent: "Internet Explorer 11", Supersedes: "MS16-009[3134220]", Reboot: "Yes", CVEs: "CVE-2016-0102,CVE-2016-0103"},
+			},
+		},
+		{
+			name: "2001-2008 layout in the other order with the date as the text",
+			in: bulletinSearchXlsx(t, "",
+				[]string{"Bulletin ID", "Date Posted", "Bulletin KB", "Title", "Affected Product", "Max Severity", "Max Impact", "Supercedes", "Restart Required", "CVE"},
+				[]interface{}{"MS05-039", "09/08/2005", "899588", "Vulnerability in Plug and Play", "Windows 2000", "Critical", "Remote Code Execution", "MS05-011[885250]", "Yes", "CVE-2005-1983"},
+				[]interface{}{"MS05-040", "23/08/2005", "893756", "Vulnerability in Telephony Service", "Windows XP", "Important", "Remote Code Execution", "", "Yes", "CVE-2005-0058"},
+			),
+			out: []models.MicrosoftBulletinSearch{
+				{DatePosted: "9/8/2005", BulletinID: "MS05-039", BulletinKB: "899588", Title: "Vulnerability in Plug and Play", AffectedProduct: "Windows 2000", Severity: "Critical", Impact: "Remote Code Execution", Supersedes: "MS05-011[885250]", Reboot: "Yes", CVEs: "CVE-2005-1983"},
+				{DatePosted: "8/23/2005", BulletinID: "MS05-040", BulletinKB: "893756", Title: "Vulnerability in Telephony Service", AffectedProduct: "Windows XP", Severity: "Important", Impact: "Remote Code Execution", Reboot: "Yes", CVEs: "CVE-2005-0058"},
+			},
+		},
+		{
+			name: "unknown header in the current positions",
+			in: bulletinSearchXlsx(t, "m/d/yyyy",
+				[]string{"Datum", "Bulletin-ID", "Bulletin-KB"},
+				[]interface{}{time.Date(2017, 1, 10, 0, 0, 0, 0, time.UTC), "MS17-001", "3214288"},
+			),
+			out: []models.MicrosoftBulletinSearch{
+				{DatePosted: "1/10/2017", BulletinID: "MS17-001", BulletinKB: "3214288"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := XlsToModel(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(out, tt.out) {
+				t.Errorf("expected %+v, actual %+v", tt.out, out)
+			}
+		})
+	}
+}
+
+func TestBulletinDatePosted(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out string
+	}{
+		{in: "42437", out: "3/8/2016"},
+		{in: "3/8/2016", out: "3/8/2016"},
+		{in: "3-8-16", out: "3/8/2016"},
+		{in: "3/8/2016 12:00:00 AM", out: "3/8/2016"},
+		{in: "23/08/2005", out: "8/23/2005"},
+		{in: "08.03.2016", out: "3/8/2016"},
+		{in: "2016-03-08", out: "3/8/2016"},
+		{in: "12/31/99", out: "12/31/1999"},
+		{in: "13/13/2016", out: "13/13/2016"},
+		{in: "TBD", out: "TBD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			cell := &xlsx.Cell{Value: tt.in}
+			if out := bulletinDatePosted(cell, false); out != tt.out {
+				t.Errorf("expected %s, actual %s", tt.out, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	nvdAPIURL         = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+	nvdResultsPerPage = 2000
+	nvdRetry          = 5
+	// NVD counts the requests in a rolling 30 second window
+	nvdRateLimitWindow = 30 * time.Second
+)
+
+// nvdInterval returns the interval between requests.
+// NVD allows 5 requests per 30 seconds without API key, and 50 requests with API key.
+func nvdInterval(apikey string) time.Duration {
+	if apikey != "" {
+		return nvdRateLimitWindow / 50
+	}
+	return nvdRateLimitWindow / 5
+}
+
+// RetrieveNvdCVEs returns all CVEs from NVD CVE API 2.0
+func RetrieveNvdCVEs(apikey string) (cves []models.NvdCVEJSON, err error) {
+	header := map[string]string{}
+	if apikey != "" {
+		header["apiKey"] = apikey
+	}
+
+	for startIndex, total := 0, 1; startIndex < total; {
+		if startIndex != 0 {
+			time.Sleep(nvdInterval(apikey))
+		}
+
+		url := fmt.Sprintf("%s?resultsPerPage=%d&startIndex=%d", nvdAPIURL, nvdResultsPerPage, startIndex)
+		log15.Info("Fetching", "URL", url)
+		body, err := fetchNvdPage(url, header)
+		if err != nil {
+			return nil, err
+		}
+
+		res := models.NvdAPIResponseJSON{}
+		if err := json.Unmarshal(body, &res); err != nil {
+			return nil, xerrors.Errorf("Failed to decode NVD CVE API JSON. url: %s, err: %w", url, err)
+		}
+		if res.ResultsPerPage == 0 {
+			break
+		}
+		for _, v := range res.Vulnerabilities {
+			cves = append(cves, v.Cve)
+		}
+		total = res.TotalResults
+		startIndex += res.ResultsPerPage
+	}
+	return cves, nil
+}
+
+// fetchNvdPage retries when the request fails, since NVD returns 403 or 503 if the rate limit is exceeded
+func fetchNvdPage(url string, header map[string]string) (body []byte, err error) {
+	for i := 1; i <= nvdRetry; i++ {
+		if body, err = util.FetchURLWithHeader(url, header); err == nil {
+			return body, nil
+		}
+		log15.Warn("Failed to fetch NVD CVE API. Retry after the rate limit window", "retry", i, "err", err)
+		time.Sleep(time.Duration(i) * nvdRateLimitWindow)
+	}
+	return nil, xerrors.Errorf("Failed to fetch NVD CVE API. err: %w", err)
+}
@@ -0,0 +1,50 @@
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const openEulerCVRFURL = "https://repo.openeuler.org/security/data/cvrf/"
+
+// RetrieveOpenEulerCVRFs returns all security advisories (SA) of openEuler in CVRF
+func RetrieveOpenEulerCVRFs(opts ...Option) (cvrfs []models.OpenEulerCVRFXML, err error) {
+	log15.Info("Fetching", "URL", openEulerCVRFURL+"index.txt")
+	body, err := util.FetchURL(openEulerCVRFURL+"index.txt", "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch openEuler CVRF index. err: %w", err)
+	}
+
+	// e.g. 2021/cvrf-openEuler-SA-2021-1001.xml
+	urls := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		if path := strings.TrimSpace(scanner.Text()); strings.HasSuffix(path, ".xml") {
+			urls = append(urls, openEulerCVRFURL+path)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("Failed to read openEuler CVRF index. err: %w", err)
+	}
+
+	o := newOptions(opts...)
+	bodies, err := util.FetchConcurrently(urls, o.threads, o.wait)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch openEuler CVRF. err: %w", err)
+	}
+	for _, b := range bodies {
+		cvrf := models.OpenEulerCVRFXML{}
+		if err := xml.Unmarshal(b, &cvrf); err != nil {
+			return nil, xerrors.Errorf("Failed to decode openEuler CVRF XML. err: %w", err)
+		}
+		cvrfs = append(cvrfs, cvrf)
+	}
+	return cvrfs, nil
+}
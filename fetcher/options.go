@@ -0,0 +1,30 @@
+package fetcher
+
+// Option configures the fetchers fetching the documents concurrently
+type Option func(*options)
+
+type options struct {
+	threads int
+	wait    int
+}
+
+func newOptions(opts ...Option) options {
+	// same as the defaults of gost fetch --threads and --wait
+	o := options{threads: 5, wait: 0}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithConcurrency sets the number of threads and the interval between fetch (seconds)
+func WithConcurrency(threads, wait int) Option {
+	return func(o *options) {
+		if threads > 0 {
+			o.threads = threads
+		}
+		if wait >= 0 {
+			o.wait = wait
+		}
+	}
+}
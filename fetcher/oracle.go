@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/git"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	oracleDir = "oval/oracle"
+)
+
+// FetchOracleVulnList clones vuln-list and returns ELSA JSONs
+func FetchOracleVulnList() (entries []models.OracleOVALJSON, err error) {
+	// Clone vuln-list repository
+	dir := filepath.Join(util.CacheDir(), "vuln-list")
+	updatedFiles, err := git.CloneOrPull(repoURL, dir, oracleDir)
+	if err != nil {
+		return nil, xerrors.Errorf("error in vulnsrc clone or pull: %w", err)
+	}
+
+	// Only last_updated.json
+	if len(updatedFiles) <= 1 {
+		return nil, nil
+	}
+
+	rootDir := filepath.Join(dir, oracleDir)
+	targets, err := util.FilterTargets(oracleDir, updatedFiles)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to filter target files: %w", err)
+	} else if len(targets) == 0 {
+		log15.Debug("Oracle: no update file")
+		return nil, nil
+	}
+	log15.Debug(fmt.Sprintf("Oracle updated files: %d", len(targets)))
+
+	err = util.FileWalk(rootDir, targets, func(r io.Reader, path string) error {
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		elsa := models.OracleOVALJSON{}
+		if err = json.Unmarshal(content, &elsa); err != nil {
+			return xerrors.Errorf("failed to decode Oracle JSON: %w", err)
+		}
+		entries = append(entries, elsa)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("error in Oracle walk: %w", err)
+	}
+
+	return entries, nil
+}
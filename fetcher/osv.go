@@ -0,0 +1,104 @@
+package fetcher
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	osvDumpURL  = "https://osv-vulnerabilities.storage.googleapis.com/%s/all.zip"
+	osvQueryURL = "https://api.osv.dev/v1/query"
+)
+
+// OsvEcosystems are the ecosystems fetched by default
+var OsvEcosystems = []string{"PyPI", "npm", "Go", "Maven", "RubyGems", "crates.io", "Packagist", "NuGet"}
+
+// RetrieveOsv returns the vulnerabilities in the OSV dumps (all.zip) of the ecosystems
+func RetrieveOsv(ecosystems []string) (vulns []models.OsvJSON, err error) {
+	for _, ecosystem := range ecosystems {
+		u := fmt.Sprintf(osvDumpURL, url.PathEscape(ecosystem))
+		log15.Info("Fetching", "URL", u)
+		body, err := util.FetchURL(u, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch OSV dump. ecosystem: %s, err: %w", ecosystem, err)
+		}
+		vs, err := parseOsvZip(body)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to parse OSV dump. ecosystem: %s, err: %w", ecosystem, err)
+		}
+		vulns = append(vulns, vs...)
+	}
+	return vulns, nil
+}
+
+// ReadOsvZip returns the vulnerabilities in the OSV dump downloaded from osv.dev
+func ReadOsvZip(path string) ([]models.OsvJSON, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to read OSV dump. path: %s, err: %w", path, err)
+	}
+	return parseOsvZip(body)
+}
+
+func parseOsvZip(body []byte) (vulns []models.OsvJSON, err error) {
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to open zip. err: %w", err)
+	}
+	for _, f := range zr.File {
+		if filepath.Ext(f.Name) != ".json" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to open %s. err: %w", f.Name, err)
+		}
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read %s. err: %w", f.Name, err)
+		}
+
+		v := models.OsvJSON{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, xerrors.Errorf("Failed to decode OSV JSON. file: %s, err: %w", f.Name, err)
+		}
+		v.Raw = b
+		vulns = append(vulns, v)
+	}
+	return vulns, nil
+}
+
+// QueryOsvAPI queries the vulnerabilities of the package to OSV API. If version is empty, all vulnerabilities of the package are returned
+func QueryOsvAPI(ecosystem, pkgName, version string) ([]json.RawMessage, error) {
+	query := map[string]interface{}{"package": map[string]string{"ecosystem": ecosystem, "name": pkgName}}
+	if version != "" {
+		query["version"] = version
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to marshal OSV query. err: %w", err)
+	}
+
+	resBody, err := util.PostURLWithHeader(osvQueryURL, map[string]string{}, body)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to query OSV API. err: %w", err)
+	}
+	res := struct {
+		Vulns []json.RawMessage `json:"vulns"`
+	}{}
+	if err := json.Unmarshal(resBody, &res); err != nil {
+		return nil, xerrors.Errorf("Failed to decode OSV API JSON. err: %w", err)
+	}
+	return res.Vulns, nil
+}
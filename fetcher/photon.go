@@ -0,0 +1,38 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+var (
+	photonCVEURL   = "https://packages.vmware.com/photon/photon_cve_metadata/cve_data_photon%s.json"
+	photonReleases = []string{"1.0", "2.0", "3.0", "4.0", "5.0"}
+)
+
+// RetrievePhotonCVEs returns the CVE metadata of all releases of Photon OS
+func RetrievePhotonCVEs() (cves []models.PhotonCVEJSON, err error) {
+	for _, release := range photonReleases {
+		url := fmt.Sprintf(photonCVEURL, release)
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch Photon OS CVE metadata. err: %w", err)
+		}
+
+		var cs []models.PhotonCVEJSON
+		if err = json.Unmarshal(body, &cs); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Photon OS CVE metadata JSON. url: %s, err: %w", url, err)
+		}
+		for i := range cs {
+			cs[i].Release = release
+		}
+		cves = append(cves, cs...)
+	}
+	return cves, nil
+}
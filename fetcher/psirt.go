@@ -0,0 +1,136 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	ciscoTokenURL      = "https://id.cisco.com/oauth2/default/v1/token"
+	ciscoAdvisoriesURL = "https://apix.cisco.com/security/advisories/v2/all"
+
+	// FortinetPsirtRSSURL is the RSS feed of the advisories of Fortinet PSIRT
+	FortinetPsirtRSSURL = "https://filestore.fortinet.com/fortiguard/rss/ir.xml"
+	// fortinetCVRFURLFormat is the CVRF document of the advisory (e.g. FG-IR-22-398)
+	fortinetCVRFURLFormat = "https://www.fortiguard.com/psirt/cvrf/%s"
+)
+
+// RetrieveCiscoPsirt returns all the advisories of Cisco openVuln API.
+// The API requires the client ID and the secret of the application registered at Cisco API Console.
+func RetrieveCiscoPsirt(clientID, clientSecret string) ([]models.CiscoAdvisoryJSON, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, xerrors.New("Failed to fetch Cisco openVuln API. The client ID and the secret are required")
+	}
+
+	body, err := util.PostFormURL(ciscoTokenURL, map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     clientID,
+		"client_secret": clientSecret,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to get the token of Cisco openVuln API. err: %w", err)
+	}
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, xerrors.Errorf("Failed to decode the token of Cisco openVuln API. err: %w", err)
+	}
+
+	log15.Info("Fetching", "URL", ciscoAdvisoriesURL)
+	body, err = util.FetchURLWithHeader(ciscoAdvisoriesURL, map[string]string{"Authorization": "Bearer " + token.AccessToken, "Accept": "application/json"})
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch Cisco openVuln API. err: %w", err)
+	}
+	res := models.CiscoAdvisoriesJSON{}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, xerrors.Errorf("Failed to decode Cisco openVuln API. err: %w", err)
+	}
+	return res.Advisories, nil
+}
+
+// RetrieveVMwarePsirt returns the VMSA in CSAF 2.0 under url.
+// url is the directory of the CSAF provider with index.txt listing the documents, or a document itself (*.json).
+func RetrieveVMwarePsirt(url string, opts ...Option) ([]models.PsirtCSAFJSON, error) {
+	if url == "" {
+		return nil, xerrors.New("Failed to fetch VMSA. The URL of CSAF is required")
+	}
+
+	urls := []string{url}
+	if !strings.HasSuffix(url, ".json") {
+		base := strings.TrimSuffix(url, "/") + "/"
+		log15.Info("Fetching", "URL", base+"index.txt")
+		index, err := util.FetchURL(base+"index.txt", "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch the index of VMSA. err: %w", err)
+		}
+		urls = []string{}
+		for _, line := range strings.Split(string(index), "\n") {
+			if line = strings.TrimSpace(line); strings.HasSuffix(line, ".json") {
+				urls = append(urls, base+line)
+			}
+		}
+	}
+	log15.Info("Fetching", "VMSA", len(urls))
+
+	o := newOptions(opts...)
+	bodies, err := util.FetchConcurrently(urls, o.threads, o.wait)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch VMSA. err: %w", err)
+	}
+	docs := []models.PsirtCSAFJSON{}
+	for _, b := range bodies {
+		doc := models.PsirtCSAFJSON{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, xerrors.Errorf("Failed to decode VMSA. err: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// RetrieveFortinetPsirt returns the CVRF documents of the advisories in the RSS feed of Fortinet PSIRT
+func RetrieveFortinetPsirt(opts ...Option) ([]models.FortinetCVRFXML, error) {
+	log15.Info("Fetching", "URL", FortinetPsirtRSSURL)
+	body, err := util.FetchURL(FortinetPsirtRSSURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch the RSS feed of Fortinet PSIRT. err: %w", err)
+	}
+	rss := models.FortinetRSSXML{}
+	if err := xml.Unmarshal(body, &rss); err != nil {
+		return nil, xerrors.Errorf("Failed to decode the RSS feed of Fortinet PSIRT. err: %w", err)
+	}
+
+	urls := []string{}
+	for _, item := range rss.Items {
+		// e.g. https://fortiguard.com/psirt/FG-IR-22-398
+		id := item.Link[strings.LastIndex(item.Link, "/")+1:]
+		if !strings.HasPrefix(id, "FG-IR-") {
+			continue
+		}
+		urls = append(urls, fmt.Sprintf(fortinetCVRFURLFormat, id))
+	}
+	log15.Info("Fetching", "CVRF", len(urls))
+
+	o := newOptions(opts...)
+	bodies, err := util.FetchConcurrently(urls, o.threads, o.wait)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch CVRF of Fortinet PSIRT. err: %w", err)
+	}
+	docs := []models.FortinetCVRFXML{}
+	for _, b := range bodies {
+		doc := models.FortinetCVRFXML{}
+		if err := xml.Unmarshal(b, &doc); err != nil {
+			return nil, xerrors.Errorf("Failed to decode CVRF of Fortinet PSIRT. err: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
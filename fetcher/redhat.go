@@ -1,7 +1,10 @@
 package fetcher
 
 import (
+	"bytes"
+	"compress/bzip2"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -19,8 +22,9 @@ const (
 	redhatDir = "redhat"
 )
 
-// FetchRedHatVulnList clones vuln-list and returns CVE JSONs
-func FetchRedHatVulnList() (entries []models.RedhatCVEJSON, err error) {
+// FetchRedHatVulnList clones vuln-list and returns CVE JSONs.
+// If cveIDs is given, only the JSONs of the CVEs are returned.
+func FetchRedHatVulnList(cveIDs []string) (entries []models.RedhatCVEJSON, err error) {
 	// Clone vuln-list repository
 	dir := filepath.Join(util.CacheDir(), "vuln-list")
 	updatedFiles, err := git.CloneOrPull(repoURL, dir, redhatDir)
@@ -28,18 +32,24 @@ func FetchRedHatVulnList() (entries []models.RedhatCVEJSON, err error) {
 		return nil, xerrors.Errorf("error in vulnsrc clone or pull: %w", err)
 	}
 
-	// Only last_updated.json
-	if len(updatedFiles) <= 1 {
-		return nil, nil
-	}
-
 	rootDir := filepath.Join(dir, redhatDir)
-	targets, err := util.FilterTargets(redhatDir, updatedFiles)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to filter target files: %w", err)
-	} else if len(targets) == 0 {
-		log15.Debug("Red Hat: no updated file")
-		return nil, nil
+	var targets map[string]struct{}
+	if len(cveIDs) > 0 {
+		// Only the specified CVEs even if they are not updated
+		targets = util.CveIDTargets(cveIDs)
+	} else {
+		// Only last_updated.json
+		if len(updatedFiles) <= 1 {
+			return nil, nil
+		}
+
+		targets, err = util.FilterTargets(redhatDir, updatedFiles)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to filter target files: %w", err)
+		} else if len(targets) == 0 {
+			log15.Debug("Red Hat: no updated file")
+			return nil, nil
+		}
 	}
 	log15.Debug(fmt.Sprintf("Red Hat updated files: %d", len(targets)))
 
@@ -248,3 +258,26 @@ type RedhatPackageState struct {
 	PackageName string `json:"package_name"`
 	Cpe         string `json:"cpe"`
 }
+
+// redhatOVALURLFormat is the URL of OVAL v2 of a RHEL major release including the unpatched CVEs
+const redhatOVALURLFormat = "https://access.redhat.com/security/data/oval/v2/RHEL%s/rhel-%s-including-unpatched.oval.xml.bz2"
+
+// RetrieveRedhatOVALs returns OVAL v2 of the RHEL major releases (e.g. 8) from https://access.redhat.com/security/data/oval/v2/
+func RetrieveRedhatOVALs(majors []string) (ovals []models.RedhatOVALXML, err error) {
+	for _, major := range majors {
+		url := fmt.Sprintf(redhatOVALURLFormat, major, major)
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch Red Hat OVAL. err: %w", err)
+		}
+
+		oval := models.RedhatOVALXML{}
+		if err = xml.NewDecoder(bzip2.NewReader(bytes.NewReader(body))).Decode(&oval); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Red Hat OVAL XML. url: %s, err: %w", url, err)
+		}
+		oval.Major = major
+		ovals = append(ovals, oval)
+	}
+	return ovals, nil
+}
@@ -6,8 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/spf13/viper"
-
 	"github.com/knqyf263/gost/models"
 	"github.com/knqyf263/gost/util"
 )
@@ -47,8 +45,9 @@ func GetRedhatCveDetailURL(cveID string) (url string) {
 
 // RetrieveRedhatCveDetails returns full CVE details from RedHat API
 // https://access.redhat.com/documentation/en-us/red_hat_security_data_api/0.1/html-single/red_hat_security_data_api/#retrieve_a_cve
-func RetrieveRedhatCveDetails(urls []string) (cves []models.RedhatCVEJSON, err error) {
-	cveJSONs, err := util.FetchConcurrently(urls, viper.GetInt("threads"), viper.GetInt("wait"))
+func RetrieveRedhatCveDetails(urls []string, opts ...Option) (cves []models.RedhatCVEJSON, err error) {
+	o := newOptions(opts...)
+	cveJSONs, err := util.FetchConcurrently(urls, o.threads, o.wait)
 	if err != nil {
 		return cves, fmt.Errorf("Failed to fetch cve data from RedHat. err: %s", err)
 	}
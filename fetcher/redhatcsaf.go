@@ -0,0 +1,378 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+// RedhatCSAFURL is the directory of the VEX files of Red Hat. archive_latest.txt in it has the name of the latest tarball.
+const RedhatCSAFURL = "https://security.access.redhat.com/data/csaf/v2/vex/"
+
+// redhatCSAFBugzillaURL is the URL of the bug of Red Hat Bugzilla ID in CSAF
+const redhatCSAFBugzillaURL = "https://bugzilla.redhat.com/show_bug.cgi?id="
+
+var (
+	// e.g. cpe:/o:redhat:enterprise_linux:9::baseos, cpe:/a:redhat:enterprise_linux:8::appstream
+	redhatCSAFRHELCpeRegexp = regexp.MustCompile(`^cpe:/[ao]:redhat:enterprise_linux:(\d+)`)
+	// e.g. pkg:rpmmod/redhat/nodejs@18:8090020230905112710:a75119d5
+	redhatCSAFModulePurlRegexp = regexp.MustCompile(`^pkg:rpmmod/redhat/([^@]+)@([^:]+):([^:]+):([^:?]+)`)
+	// e.g. openssl-1:3.0.7-18.el9_2.x86_64, openssl-1:3.0.7-18.el9_2.src
+	redhatCSAFArchRegexp = regexp.MustCompile(`\.(?:src|noarch|x86_64|i686|aarch64|ppc64le|ppc64|s390x)$`)
+)
+
+// RetrieveRedhatCSAF returns the CVEs converted from the VEX files in the tarball of Red Hat CSAF.
+// The tarball is read from localPath if given, otherwise fetched from url (RedhatCSAFURL if empty).
+// If cveIDs is given, only the CVEs are returned.
+func RetrieveRedhatCSAF(url, localPath string, cveIDs []string) ([]models.RedhatCVEJSON, error) {
+	var (
+		name string
+		body []byte
+		err  error
+	)
+	if localPath != "" {
+		name = localPath
+		if body, err = ioutil.ReadFile(localPath); err != nil {
+			return nil, xerrors.Errorf("Failed to read Red Hat CSAF. path: %s, err: %w", localPath, err)
+		}
+	} else {
+		if url == "" {
+			url = RedhatCSAFURL
+		}
+		if strings.HasSuffix(url, "/") {
+			latest, err := util.FetchURL(url+"archive_latest.txt", "")
+			if err != nil {
+				return nil, xerrors.Errorf("Failed to fetch the name of the latest Red Hat CSAF tarball. err: %w", err)
+			}
+			url += strings.TrimSpace(string(latest))
+		}
+		name = url
+		log15.Info("Fetching", "URL", url)
+		if body, err = util.FetchURL(url, ""); err != nil {
+			return nil, xerrors.Errorf("Failed to fetch Red Hat CSAF. err: %w", err)
+		}
+	}
+
+	r, closer, err := decompressRedhatCSAF(name, body)
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+
+	targets := map[string]bool{}
+	for _, cveID := range cveIDs {
+		targets[strings.ToUpper(cveID)] = true
+	}
+
+	merged := map[string]*models.RedhatCVEJSON{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to read Red Hat CSAF tarball. err: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !strings.HasSuffix(hdr.Name, ".json") {
+			continue
+		}
+		if len(targets) > 0 && !targets[strings.ToUpper(strings.TrimSuffix(path.Base(hdr.Name), ".json"))] {
+			continue
+		}
+
+		doc := models.RedhatCSAFJSON{}
+		if err := json.NewDecoder(tr).Decode(&doc); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Red Hat CSAF JSON. file: %s, err: %w", hdr.Name, err)
+		}
+		for _, cve := range ConvertRedhatCSAF(doc) {
+			if len(targets) > 0 && !targets[cve.Name] {
+				continue
+			}
+			if m, ok := merged[cve.Name]; ok {
+				mergeRedhatCSAFCve(m, cve)
+				continue
+			}
+			c := cve
+			merged[cve.Name] = &c
+		}
+	}
+
+	cves := make([]models.RedhatCVEJSON, 0, len(merged))
+	for _, c := range merged {
+		cves = append(cves, *c)
+	}
+	sort.Slice(cves, func(i, j int) bool { return cves[i].Name < cves[j].Name })
+	return cves, nil
+}
+
+// decompressRedhatCSAF decompresses the tarball by the extension of name. Since Go has no zstd, .tar.zst needs the zstd command.
+func decompressRedhatCSAF(name string, body []byte) (io.Reader, func(), error) {
+	switch {
+	case strings.HasSuffix(name, ".zst"):
+		if !util.IsCommandAvailable("zstd") {
+			return nil, nil, xerrors.New("zstd is required to decompress Red Hat CSAF tarball. Install zstd, or give the decompressed tarball by --csaf-path")
+		}
+		cmd := exec.Command("zstd", "-dc")
+		cmd.Stdin = bytes.NewReader(body)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, nil, xerrors.Errorf("Failed to decompress Red Hat CSAF tarball. err: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, nil, xerrors.Errorf("Failed to decompress Red Hat CSAF tarball. err: %w", err)
+		}
+		return stdout, func() {
+			_, _ = io.Copy(ioutil.Discard, stdout)
+			_ = cmd.Wait()
+		}, nil
+	case strings.HasSuffix(name, ".gz"), strings.HasSuffix(name, ".tgz"):
+		r, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, xerrors.Errorf("Failed to decompress Red Hat CSAF tarball. err: %w", err)
+		}
+		return r, func() { _ = r.Close() }, nil
+	case strings.HasSuffix(name, ".bz2"):
+		return bzip2.NewReader(bytes.NewReader(body)), func() {}, nil
+	default:
+		return bytes.NewReader(body), func() {}, nil
+	}
+}
+
+type redhatCSAFProduct struct {
+	name string
+	cpe  string
+	purl string
+}
+
+// walkRedhatCSAFBranches collects the products in the branches of the product tree
+func walkRedhatCSAFBranches(branches []models.RedhatCSAFBranchJSON, products map[string]redhatCSAFProduct) {
+	for _, b := range branches {
+		if p := b.Product; p != nil {
+			products[p.ProductID] = redhatCSAFProduct{
+				name: p.Name,
+				cpe:  p.ProductIdentificationHelper.Cpe,
+				purl: p.ProductIdentificationHelper.Purl,
+			}
+		}
+		walkRedhatCSAFBranches(b.Branches, products)
+	}
+}
+
+type redhatCSAFRelationship struct {
+	// product is the product stream (e.g. red_hat_enterprise_linux_9)
+	product string
+	// component is the package (e.g. openssl-1:3.0.7-18.el9_2.src) or the module (e.g. nodejs:18)
+	component string
+}
+
+// ConvertRedhatCSAF converts the vulnerabilities of the CSAF document into the CVEs of the Security Data API format.
+// The fixed products become affected_release, and the others package_state.
+func ConvertRedhatCSAF(doc models.RedhatCSAFJSON) []models.RedhatCVEJSON {
+	products := map[string]redhatCSAFProduct{}
+	walkRedhatCSAFBranches(doc.ProductTree.Branches, products)
+	relationships := map[string]redhatCSAFRelationship{}
+	for _, r := range doc.ProductTree.Relationships {
+		relationships[r.FullProductName.ProductID] = redhatCSAFRelationship{product: r.RelatesToProductReference, component: r.ProductReference}
+	}
+
+	cves := []models.RedhatCVEJSON{}
+	for _, v := range doc.Vulnerabilities {
+		if v.Cve == "" {
+			continue
+		}
+		cve := models.RedhatCVEJSON{
+			Name:                 v.Cve,
+			ThreatSeverity:       doc.Document.AggregateSeverity.Text,
+			Cwe:                  v.Cwe.ID,
+			DocumentDistribution: doc.Document.Distribution.Text,
+			Bugzilla:             models.RedhatBugzilla{Description: v.Title},
+		}
+		if t, err := util.ParseDate(v.ReleaseDate, time.RFC3339); err == nil {
+			cve.PublicDate = t.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		for _, t := range v.Threats {
+			if t.Category == "impact" && t.Details != "" {
+				cve.ThreatSeverity = t.Details
+			}
+		}
+		for _, id := range v.IDs {
+			if id.SystemName == "Red Hat Bugzilla ID" {
+				cve.Bugzilla.BugzillaID = id.Text
+				cve.Bugzilla.URL = redhatCSAFBugzillaURL + id.Text
+			}
+		}
+		for _, s := range v.Scores {
+			if s.CvssV3 != nil {
+				cve.Cvss3 = models.RedhatCvss3{
+					Cvss3BaseScore:     fmt.Sprintf("%.1f", s.CvssV3.BaseScore),
+					Cvss3ScoringVector: s.CvssV3.VectorString,
+					Status:             "verified",
+				}
+				break
+			}
+		}
+		for _, n := range v.Notes {
+			switch {
+			case n.Title == "Statement":
+				cve.Statement = n.Text
+			case n.Category == "description":
+				cve.Details = append(cve.Details, n.Text)
+			}
+		}
+		for _, r := range v.References {
+			cve.References = append(cve.References, r.URL)
+		}
+
+		// the fix state of the unfixed products, from the remediations
+		fixStates := map[string]string{}
+		for _, r := range v.Remediations {
+			switch r.Category {
+			case "workaround", "mitigation":
+				cve.Mitigation = r.Details
+				continue
+			case "no_fix_planned":
+				for _, id := range r.ProductIDs {
+					fixStates[id] = "Will not fix"
+				}
+			case "none_available":
+				for _, id := range r.ProductIDs {
+					fixStates[id] = r.Details
+				}
+			case "vendor_fix":
+				uniq, fixed := map[string]bool{}, map[string]bool{}
+				for _, id := range r.ProductIDs {
+					rel, ok := relationships[id]
+					if !ok {
+						continue
+					}
+					p := products[rel.product]
+					ar := models.RedhatAffectedRelease{
+						ProductName: p.name,
+						ReleaseDate: r.Date,
+						Advisory:    path.Base(r.URL),
+						Package:     redhatCSAFPackage(rel.component, products[rel.component]),
+						Cpe:         p.cpe,
+					}
+					if fp, ok := util.ParseRedhatNEVRA(rel.component); ok && p.cpe != "" {
+						fp.Advisory, fp.Cpe = ar.Advisory, p.cpe
+						if key := redhatFixedPackageKey(fp); !fixed[key] {
+							fixed[key] = true
+							cve.FixedPackages = append(cve.FixedPackages, fp)
+						}
+					}
+					key := fmt.Sprintf("%s#%s#%s", ar.ProductName, ar.Cpe, ar.Package)
+					if uniq[key] {
+						continue
+					}
+					uniq[key] = true
+					cve.AffectedRelease = append(cve.AffectedRelease, ar)
+				}
+			}
+		}
+
+		uniq := map[string]bool{}
+		appendState := func(ids []string, state func(id string) string) {
+			for _, id := range ids {
+				rel, ok := relationships[id]
+				if !ok {
+					continue
+				}
+				p := products[rel.product]
+				ps := models.RedhatPackageState{
+					ProductName: p.name,
+					FixState:    state(id),
+					PackageName: rel.component,
+					Cpe:         normalizeRedhatCSAFCpe(p.cpe),
+				}
+				key := fmt.Sprintf("%s#%s#%s", ps.ProductName, ps.Cpe, ps.PackageName)
+				if uniq[key] {
+					continue
+				}
+				uniq[key] = true
+				cve.PackageState = append(cve.PackageState, ps)
+			}
+		}
+		appendState(v.ProductStatus.KnownAffected, func(id string) string {
+			if s := fixStates[id]; s != "" {
+				return s
+			}
+			return "Affected"
+		})
+		appendState(v.ProductStatus.KnownNotAffected, func(string) string { return "Not affected" })
+		appendState(v.ProductStatus.UnderInvestigation, func(string) string { return "Under investigation" })
+
+		cves = append(cves, cve)
+	}
+	return cves
+}
+
+// redhatCSAFPackage returns the package of affected_release, e.g.
+// openssl-1:3.0.7-18.el9_2.src -> openssl-1:3.0.7-18.el9_2,
+// pkg:rpmmod/redhat/nodejs@18:8090020230905112710:a75119d5 -> nodejs:18-8090020230905112710.a75119d5
+func redhatCSAFPackage(component string, p redhatCSAFProduct) string {
+	if m := redhatCSAFModulePurlRegexp.FindStringSubmatch(p.purl); m != nil {
+		return fmt.Sprintf("%s:%s-%s.%s", m[1], m[2], m[3], m[4])
+	}
+	return redhatCSAFArchRegexp.ReplaceAllString(component, "")
+}
+
+// normalizeRedhatCSAFCpe returns the CPE of the major release for the products of RHEL (e.g. cpe:/a:redhat:enterprise_linux:9::appstream -> cpe:/o:redhat:enterprise_linux:9),
+// as package_state of Security Data API has. The other CPEs (e.g. EUS) are returned as is.
+func normalizeRedhatCSAFCpe(cpe string) string {
+	if m := redhatCSAFRHELCpeRegexp.FindStringSubmatch(cpe); m != nil {
+		return "cpe:/o:redhat:enterprise_linux:" + m[1]
+	}
+	return cpe
+}
+
+// mergeRedhatCSAFCve merges the fixes and states of the CVE in the other document (e.g. an advisory) into dst
+func mergeRedhatCSAFCve(dst *models.RedhatCVEJSON, src models.RedhatCVEJSON) {
+	releases := map[string]bool{}
+	for _, r := range dst.AffectedRelease {
+		releases[fmt.Sprintf("%s#%s#%s", r.ProductName, r.Cpe, r.Package)] = true
+	}
+	for _, r := range src.AffectedRelease {
+		if !releases[fmt.Sprintf("%s#%s#%s", r.ProductName, r.Cpe, r.Package)] {
+			dst.AffectedRelease = append(dst.AffectedRelease, r)
+		}
+	}
+	fixed := map[string]bool{}
+	for _, p := range dst.FixedPackages {
+		fixed[redhatFixedPackageKey(p)] = true
+	}
+	for _, p := range src.FixedPackages {
+		if !fixed[redhatFixedPackageKey(p)] {
+			dst.FixedPackages = append(dst.FixedPackages, p)
+		}
+	}
+	states := map[string]bool{}
+	for _, s := range dst.PackageState {
+		states[fmt.Sprintf("%s#%s#%s", s.ProductName, s.Cpe, s.PackageName)] = true
+	}
+	for _, s := range src.PackageState {
+		if !states[fmt.Sprintf("%s#%s#%s", s.ProductName, s.Cpe, s.PackageName)] {
+			dst.PackageState = append(dst.PackageState, s)
+		}
+	}
+}
+
+func redhatFixedPackageKey(p models.RedhatFixedPackage) string {
+	return fmt.Sprintf("%s#%s#%s-%s:%s-%s.%s", p.Advisory, p.Cpe, p.Name, p.Epoch, p.Version, p.Release, p.Arch)
+}
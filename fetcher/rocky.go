@@ -0,0 +1,44 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/models"
+	"github.com/knqyf263/gost/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	rockyAdvisoriesURL = "https://errata.rockylinux.org/api/v2/advisories?filters.type=TYPE_SECURITY&page=%d&limit=%d"
+	rockyPageSize      = 100
+)
+
+// RetrieveRockyAdvisories returns all security advisories (RLSA) from Rocky Linux Apollo
+func RetrieveRockyAdvisories() (advisories []models.RockyAdvisoryJSON, err error) {
+	for page := 0; ; page++ {
+		url := fmt.Sprintf(rockyAdvisoriesURL, page, rockyPageSize)
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch Rocky Linux advisories. err: %w", err)
+		}
+
+		var res models.RockyAdvisoriesJSON
+		if err = json.Unmarshal(body, &res); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Rocky Linux advisories JSON. url: %s, err: %w", url, err)
+		}
+		advisories = append(advisories, res.Advisories...)
+
+		total, err := strconv.Atoi(res.Total)
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to parse total. total: %s, err: %w", res.Total, err)
+		}
+		if len(res.Advisories) == 0 || len(advisories) >= total {
+			break
+		}
+	}
+	return advisories, nil
+}
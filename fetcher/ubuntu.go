@@ -1,7 +1,10 @@
 package fetcher
 
 import (
+	"bytes"
+	"compress/bzip2"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,11 +18,13 @@ import (
 )
 
 const (
-	ubuntuDir = "ubuntu"
+	ubuntuDir    = "ubuntu"
+	ubuntuUSNURL = "https://usn.ubuntu.com/usn-db/database.json"
 )
 
-// FetchUbuntuVulnList clones vuln-list and returns CVE JSONs
-func FetchUbuntuVulnList() (entries []models.UbuntuCVEJSON, err error) {
+// FetchUbuntuVulnList clones vuln-list and returns CVE JSONs.
+// If cveIDs is given, only the JSONs of the CVEs are returned.
+func FetchUbuntuVulnList(cveIDs []string) (entries []models.UbuntuCVEJSON, err error) {
 	// Clone vuln-list repository
 	dir := filepath.Join(util.CacheDir(), "vuln-list")
 	updatedFiles, err := git.CloneOrPull(repoURL, dir, ubuntuDir)
@@ -27,18 +32,24 @@ func FetchUbuntuVulnList() (entries []models.UbuntuCVEJSON, err error) {
 		return nil, xerrors.Errorf("error in vulnsrc clone or pull: %w", err)
 	}
 
-	// Only last_updated.json
-	if len(updatedFiles) <= 1 {
-		return nil, nil
-	}
-
 	rootDir := filepath.Join(dir, ubuntuDir)
-	targets, err := util.FilterTargets(ubuntuDir, updatedFiles)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to filter target files: %w", err)
-	} else if len(targets) == 0 {
-		log15.Debug("Ubuntu: no update file")
-		return nil, nil
+	var targets map[string]struct{}
+	if len(cveIDs) > 0 {
+		// Only the specified CVEs even if they are not updated
+		targets = util.CveIDTargets(cveIDs)
+	} else {
+		// Only last_updated.json
+		if len(updatedFiles) <= 1 {
+			return nil, nil
+		}
+
+		targets, err = util.FilterTargets(ubuntuDir, updatedFiles)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to filter target files: %w", err)
+		} else if len(targets) == 0 {
+			log15.Debug("Ubuntu: no update file")
+			return nil, nil
+		}
 	}
 	log15.Debug(fmt.Sprintf("Ubuntu updated files: %d", len(targets)))
 
@@ -62,3 +73,48 @@ func FetchUbuntuVulnList() (entries []models.UbuntuCVEJSON, err error) {
 
 	return entries, nil
 }
+
+// RetrieveUbuntuUSNs returns all Ubuntu Security Notices in the USN database
+func RetrieveUbuntuUSNs() (usns []models.UbuntuUSNJSON, err error) {
+	log15.Info("Fetching", "URL", ubuntuUSNURL)
+	body, err := util.FetchURL(ubuntuUSNURL, "")
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to fetch USN database. err: %w", err)
+	}
+
+	// e.g. {"4999-1": {"id": "4999-1", ...}}
+	m := map[string]models.UbuntuUSNJSON{}
+	if err = json.Unmarshal(body, &m); err != nil {
+		return nil, xerrors.Errorf("Failed to decode USN database JSON. err: %w", err)
+	}
+	for id, usn := range m {
+		if usn.ID == "" {
+			usn.ID = id
+		}
+		usns = append(usns, usn)
+	}
+	return usns, nil
+}
+
+// ubuntuOVALURLFormat is the URL of OVAL of the CVEs of a release (e.g. jammy)
+const ubuntuOVALURLFormat = "https://security-metadata.canonical.com/oval/com.ubuntu.%s.cve.oval.xml.bz2"
+
+// RetrieveUbuntuOVALs returns OVAL of the releases (e.g. jammy) from https://security-metadata.canonical.com/oval/
+func RetrieveUbuntuOVALs(codenames []string) (ovals []models.UbuntuOVALXML, err error) {
+	for _, codename := range codenames {
+		url := fmt.Sprintf(ubuntuOVALURLFormat, codename)
+		log15.Info("Fetching", "URL", url)
+		body, err := util.FetchURL(url, "")
+		if err != nil {
+			return nil, xerrors.Errorf("Failed to fetch Ubuntu OVAL. err: %w", err)
+		}
+
+		oval := models.UbuntuOVALXML{}
+		if err = xml.NewDecoder(bzip2.NewReader(bytes.NewReader(body))).Decode(&oval); err != nil {
+			return nil, xerrors.Errorf("Failed to decode Ubuntu OVAL XML. url: %s, err: %w", url, err)
+		}
+		oval.Release = codename
+		ovals = append(ovals, oval)
+	}
+	return ovals, nil
+}
@@ -0,0 +1,4 @@
+package fetcher
+
+// WolfiEcosystems are the ecosystems of the advisories of Wolfi and Chainguard in OSV
+var WolfiEcosystems = []string{"Wolfi", "Chainguard"}
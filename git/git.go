@@ -59,7 +59,7 @@ func CloneOrPull(url, repoPath, osDir string) (map[string]struct{}, error) {
 		if err = os.MkdirAll(repoPath, 0700); err != nil {
 			return nil, xerrors.Errorf("failed to mkdir: %w", err)
 		}
-		if err := clone(url, repoPath, osDir); err != nil {
+		if err := cloneWithMirrors(url, repoPath, osDir); err != nil {
 			return nil, xerrors.Errorf("failed to clone repository: %w", err)
 		}
 
@@ -84,6 +84,29 @@ func CloneOrPull(url, repoPath, osDir string) (map[string]struct{}, error) {
 	return updatedFiles, nil
 }
 
+// cloneWithMirrors clones the repository, or its mirrors in order if the clone fails.
+// The repository cloned is pulled after that, so the mirrors are not tried by the pulls.
+func cloneWithMirrors(url, repoPath, osDir string) error {
+	urls := util.MirrorURLs(url)
+	var err error
+	for i, u := range urls {
+		if err = clone(u, repoPath, osDir); err == nil {
+			return nil
+		}
+		if i == len(urls)-1 {
+			break
+		}
+		log15.Warn("Failed to clone. Falling back to the mirror", "url", u, "mirror", urls[i+1], "err", err)
+		if err := os.RemoveAll(repoPath); err != nil {
+			return xerrors.Errorf("failed to remove an existed directory: %w", err)
+		}
+		if err := os.MkdirAll(repoPath, 0700); err != nil {
+			return xerrors.Errorf("failed to mkdir: %w", err)
+		}
+	}
+	return err
+}
+
 func clone(url, repoPath, osDir string) error {
 	if util.IsCommandAvailable("git") {
 		return cloneByOSCommand(url, repoPath, osDir)
@@ -0,0 +1,469 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package gostpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// GostClient is the client API for Gost service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GostClient interface {
+	// GetCve responds the record of the CVE in the source (redhat, debian, ubuntu or microsoft). NOT_FOUND if the source does not have it
+	GetCve(ctx context.Context, in *CveRequest, opts ...grpc.CallOption) (*Record, error)
+	// GetCves responds the records of the CVEs found in the source in the order of cve_ids
+	GetCves(ctx context.Context, in *CvesRequest, opts ...grpc.CallOption) (Gost_GetCvesClient, error)
+	// GetUnfixedCves responds the unfixed CVEs of the package in the order of CVE-ID.
+	// The packages not covered by the upstream of a derivative distribution respond nothing with the trailer x-gost-not-covered: true
+	GetUnfixedCves(ctx context.Context, in *PackageRequest, opts ...grpc.CallOption) (Gost_GetUnfixedCvesClient, error)
+	// GetFixedCves responds the fixed CVEs of the package in the same way as GetUnfixedCves
+	GetFixedCves(ctx context.Context, in *PackageRequest, opts ...grpc.CallOption) (Gost_GetFixedCvesClient, error)
+	// GetVulnInfos responds the source-agnostic information of the CVEs found in any source in the order of cve_ids
+	GetVulnInfos(ctx context.Context, in *VulnInfosRequest, opts ...grpc.CallOption) (Gost_GetVulnInfosClient, error)
+	// GetSupersededKBs responds the KBs superseded by the KB directly or indirectly
+	GetSupersededKBs(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBs, error)
+	// GetRequiredKBs responds the KBs required for the build of the product
+	GetRequiredKBs(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (Gost_GetRequiredKBsClient, error)
+}
+
+type gostClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGostClient(cc grpc.ClientConnInterface) GostClient {
+	return &gostClient{cc}
+}
+
+func (c *gostClient) GetCve(ctx context.Context, in *CveRequest, opts ...grpc.CallOption) (*Record, error) {
+	out := new(Record)
+	err := c.cc.Invoke(ctx, "/gost.Gost/GetCve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gostClient) GetCves(ctx context.Context, in *CvesRequest, opts ...grpc.CallOption) (Gost_GetCvesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gost_ServiceDesc.Streams[0], "/gost.Gost/GetCves", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gostGetCvesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gost_GetCvesClient interface {
+	Recv() (*Record, error)
+	grpc.ClientStream
+}
+
+type gostGetCvesClient struct {
+	grpc.ClientStream
+}
+
+func (x *gostGetCvesClient) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gostClient) GetUnfixedCves(ctx context.Context, in *PackageRequest, opts ...grpc.CallOption) (Gost_GetUnfixedCvesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gost_ServiceDesc.Streams[1], "/gost.Gost/GetUnfixedCves", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gostGetUnfixedCvesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gost_GetUnfixedCvesClient interface {
+	Recv() (*Record, error)
+	grpc.ClientStream
+}
+
+type gostGetUnfixedCvesClient struct {
+	grpc.ClientStream
+}
+
+func (x *gostGetUnfixedCvesClient) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gostClient) GetFixedCves(ctx context.Context, in *PackageRequest, opts ...grpc.CallOption) (Gost_GetFixedCvesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gost_ServiceDesc.Streams[2], "/gost.Gost/GetFixedCves", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gostGetFixedCvesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gost_GetFixedCvesClient interface {
+	Recv() (*Record, error)
+	grpc.ClientStream
+}
+
+type gostGetFixedCvesClient struct {
+	grpc.ClientStream
+}
+
+func (x *gostGetFixedCvesClient) Recv() (*Record, error) {
+	m := new(Record)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gostClient) GetVulnInfos(ctx context.Context, in *VulnInfosRequest, opts ...grpc.CallOption) (Gost_GetVulnInfosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gost_ServiceDesc.Streams[3], "/gost.Gost/GetVulnInfos", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gostGetVulnInfosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gost_GetVulnInfosClient interface {
+	Recv() (*VulnInfo, error)
+	grpc.ClientStream
+}
+
+type gostGetVulnInfosClient struct {
+	grpc.ClientStream
+}
+
+func (x *gostGetVulnInfosClient) Recv() (*VulnInfo, error) {
+	m := new(VulnInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gostClient) GetSupersededKBs(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBs, error) {
+	out := new(KBs)
+	err := c.cc.Invoke(ctx, "/gost.Gost/GetSupersededKBs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gostClient) GetRequiredKBs(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (Gost_GetRequiredKBsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gost_ServiceDesc.Streams[4], "/gost.Gost/GetRequiredKBs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gostGetRequiredKBsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gost_GetRequiredKBsClient interface {
+	Recv() (*KBBuild, error)
+	grpc.ClientStream
+}
+
+type gostGetRequiredKBsClient struct {
+	grpc.ClientStream
+}
+
+func (x *gostGetRequiredKBsClient) Recv() (*KBBuild, error) {
+	m := new(KBBuild)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GostServer is the server API for Gost service.
+// All implementations must embed UnimplementedGostServer
+// for forward compatibility
+type GostServer interface {
+	// GetCve responds the record of the CVE in the source (redhat, debian, ubuntu or microsoft). NOT_FOUND if the source does not have it
+	GetCve(context.Context, *CveRequest) (*Record, error)
+	// GetCves responds the records of the CVEs found in the source in the order of cve_ids
+	GetCves(*CvesRequest, Gost_GetCvesServer) error
+	// GetUnfixedCves responds the unfixed CVEs of the package in the order of CVE-ID.
+	// The packages not covered by the upstream of a derivative distribution respond nothing with the trailer x-gost-not-covered: true
+	GetUnfixedCves(*PackageRequest, Gost_GetUnfixedCvesServer) error
+	// GetFixedCves responds the fixed CVEs of the package in the same way as GetUnfixedCves
+	GetFixedCves(*PackageRequest, Gost_GetFixedCvesServer) error
+	// GetVulnInfos responds the source-agnostic information of the CVEs found in any source in the order of cve_ids
+	GetVulnInfos(*VulnInfosRequest, Gost_GetVulnInfosServer) error
+	// GetSupersededKBs responds the KBs superseded by the KB directly or indirectly
+	GetSupersededKBs(context.Context, *KBRequest) (*KBs, error)
+	// GetRequiredKBs responds the KBs required for the build of the product
+	GetRequiredKBs(*BuildRequest, Gost_GetRequiredKBsServer) error
+	mustEmbedUnimplementedGostServer()
+}
+
+// UnimplementedGostServer must be embedded to have forward compatible implementations.
+type UnimplementedGostServer struct {
+}
+
+func (UnimplementedGostServer) GetCve(context.Context, *CveRequest) (*Record, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCve not implemented")
+}
+func (UnimplementedGostServer) GetCves(*CvesRequest, Gost_GetCvesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetCves not implemented")
+}
+func (UnimplementedGostServer) GetUnfixedCves(*PackageRequest, Gost_GetUnfixedCvesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetUnfixedCves not implemented")
+}
+func (UnimplementedGostServer) GetFixedCves(*PackageRequest, Gost_GetFixedCvesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetFixedCves not implemented")
+}
+func (UnimplementedGostServer) GetVulnInfos(*VulnInfosRequest, Gost_GetVulnInfosServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetVulnInfos not implemented")
+}
+func (UnimplementedGostServer) GetSupersededKBs(context.Context, *KBRequest) (*KBs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSupersededKBs not implemented")
+}
+func (UnimplementedGostServer) GetRequiredKBs(*BuildRequest, Gost_GetRequiredKBsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetRequiredKBs not implemented")
+}
+func (UnimplementedGostServer) mustEmbedUnimplementedGostServer() {}
+
+// UnsafeGostServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GostServer will
+// result in compilation errors.
+type UnsafeGostServer interface {
+	mustEmbedUnimplementedGostServer()
+}
+
+func RegisterGostServer(s grpc.ServiceRegistrar, srv GostServer) {
+	s.RegisterService(&Gost_ServiceDesc, srv)
+}
+
+func _Gost_GetCve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GostServer).GetCve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gost.Gost/GetCve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GostServer).GetCve(ctx, req.(*CveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gost_GetCves_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CvesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GostServer).GetCves(m, &gostGetCvesServer{stream})
+}
+
+type Gost_GetCvesServer interface {
+	Send(*Record) error
+	grpc.ServerStream
+}
+
+type gostGetCvesServer struct {
+	grpc.ServerStream
+}
+
+func (x *gostGetCvesServer) Send(m *Record) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Gost_GetUnfixedCves_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PackageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GostServer).GetUnfixedCves(m, &gostGetUnfixedCvesServer{stream})
+}
+
+type Gost_GetUnfixedCvesServer interface {
+	Send(*Record) error
+	grpc.ServerStream
+}
+
+type gostGetUnfixedCvesServer struct {
+	grpc.ServerStream
+}
+
+func (x *gostGetUnfixedCvesServer) Send(m *Record) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Gost_GetFixedCves_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PackageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GostServer).GetFixedCves(m, &gostGetFixedCvesServer{stream})
+}
+
+type Gost_GetFixedCvesServer interface {
+	Send(*Record) error
+	grpc.ServerStream
+}
+
+type gostGetFixedCvesServer struct {
+	grpc.ServerStream
+}
+
+func (x *gostGetFixedCvesServer) Send(m *Record) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Gost_GetVulnInfos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(VulnInfosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GostServer).GetVulnInfos(m, &gostGetVulnInfosServer{stream})
+}
+
+type Gost_GetVulnInfosServer interface {
+	Send(*VulnInfo) error
+	grpc.ServerStream
+}
+
+type gostGetVulnInfosServer struct {
+	grpc.ServerStream
+}
+
+func (x *gostGetVulnInfosServer) Send(m *VulnInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Gost_GetSupersededKBs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GostServer).GetSupersededKBs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gost.Gost/GetSupersededKBs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GostServer).GetSupersededKBs(ctx, req.(*KBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gost_GetRequiredKBs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BuildRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GostServer).GetRequiredKBs(m, &gostGetRequiredKBsServer{stream})
+}
+
+type Gost_GetRequiredKBsServer interface {
+	Send(*KBBuild) error
+	grpc.ServerStream
+}
+
+type gostGetRequiredKBsServer struct {
+	grpc.ServerStream
+}
+
+func (x *gostGetRequiredKBsServer) Send(m *KBBuild) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Gost_ServiceDesc is the grpc.ServiceDesc for Gost service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Gost_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gost.Gost",
+	HandlerType: (*GostServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCve",
+			Handler:    _Gost_GetCve_Handler,
+		},
+		{
+			MethodName: "GetSupersededKBs",
+			Handler:    _Gost_GetSupersededKBs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetCves",
+			Handler:       _Gost_GetCves_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetUnfixedCves",
+			Handler:       _Gost_GetUnfixedCves_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetFixedCves",
+			Handler:       _Gost_GetFixedCves_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetVulnInfos",
+			Handler:       _Gost_GetVulnInfos_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetRequiredKBs",
+			Handler:       _Gost_GetRequiredKBs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gost.proto",
+}
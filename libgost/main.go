@@ -0,0 +1,140 @@
+// Package main is built with -buildmode=c-shared to embed the gost lookups without the server mode.
+//
+// All the functions returning char* return a JSON envelope {"result": ..., "error": "..."} allocated by C.
+// The caller must release it with GostFree.
+// The ABI is versioned by GostABIVersion, and only compatible changes are made within the same version.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"sync"
+	"unsafe"
+
+	"github.com/knqyf263/gost/db"
+	"golang.org/x/xerrors"
+)
+
+// abiVersion is incremented when the signature or the envelope of the exported functions is changed
+const abiVersion = 1
+
+var (
+	mu     sync.RWMutex
+	driver db.DB
+)
+
+type envelope struct {
+	Result interface{} `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func response(result interface{}, err error) *C.char {
+	e := envelope{Result: result}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		b, _ = json.Marshal(envelope{Error: xerrors.Errorf("Failed to marshal json. err: %w", err).Error()})
+	}
+	return C.CString(string(b))
+}
+
+func withDriver(f func(db.DB) (interface{}, error)) *C.char {
+	mu.RLock()
+	defer mu.RUnlock()
+	if driver == nil {
+		return response(nil, xerrors.New("DB is not opened. Call GostOpen first"))
+	}
+	return response(f(driver))
+}
+
+// GostABIVersion returns the version of the ABI
+//
+//export GostABIVersion
+func GostABIVersion() C.int {
+	return abiVersion
+}
+
+// GostOpen opens the DB fetched by gost. dbType is sqlite3, mysql, postgres or redis.
+// The previously opened DB is closed.
+//
+//export GostOpen
+func GostOpen(dbType, dbPath *C.char) *C.char {
+	mu.Lock()
+	defer mu.Unlock()
+	if driver != nil {
+		_ = driver.CloseDB()
+		driver = nil
+	}
+
+	d, locked, err := db.NewDB(C.GoString(dbType), C.GoString(dbPath), false)
+	if err != nil {
+		if locked {
+			err = xerrors.Errorf("Failed to open DB. Close DB connection before opening. err: %w", err)
+		}
+		return response(nil, err)
+	}
+	driver = d
+	return response(true, nil)
+}
+
+// GostClose closes the DB
+//
+//export GostClose
+func GostClose() *C.char {
+	mu.Lock()
+	defer mu.Unlock()
+	if driver == nil {
+		return response(true, nil)
+	}
+	err := driver.CloseDB()
+	driver = nil
+	return response(err == nil, err)
+}
+
+// GostGetCve returns the CVE of the source (e.g. redhat, debian, ubuntu). The result is null if not found.
+//
+//export GostGetCve
+func GostGetCve(source, cveID *C.char) *C.char {
+	return withDriver(func(d db.DB) (interface{}, error) {
+		raw, err := d.GetRaw(C.GoString(source), C.GoString(cveID))
+		if err != nil || raw == nil {
+			return nil, err
+		}
+		return json.RawMessage(raw), nil
+	})
+}
+
+// GostGetUnfixedCves returns the unfixed CVEs related to the package of the release.
+// family is redhat, debian, ubuntu or amazon.
+//
+//export GostGetUnfixedCves
+func GostGetUnfixedCves(family, release, pkgName *C.char) *C.char {
+	return withDriver(func(d db.DB) (interface{}, error) {
+		return db.GetUnfixedCves(d, C.GoString(family), C.GoString(release), C.GoString(pkgName))
+	})
+}
+
+// GostGetFixedCves returns the fixed CVEs related to the package of the release with the fixed versions.
+// family is debian, ubuntu, amazon, alpine, oracle, rocky, alma, photon, mariner, openeuler or anolis.
+//
+//export GostGetFixedCves
+func GostGetFixedCves(family, release, pkgName *C.char) *C.char {
+	return withDriver(func(d db.DB) (interface{}, error) {
+		return db.GetFixedCves(d, C.GoString(family), C.GoString(release), C.GoString(pkgName))
+	})
+}
+
+// GostFree releases the string returned by the functions above
+//
+//export GostFree
+func GostFree(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+func main() {}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Advisory databases of the ecosystems, fetched without GitHub GraphQL API
+const (
+	// AdvisoryDBRubySec : rubysec/ruby-advisory-db
+	AdvisoryDBRubySec = "rubysec"
+	// AdvisoryDBPyPA : PyPA advisory database (PYSEC)
+	AdvisoryDBPyPA = "pypa"
+	// AdvisoryDBNpm : the npm advisories reviewed in GitHub Advisory Database
+	AdvisoryDBNpm = "npm"
+)
+
+// RubySecAdvisoryYAML : gems/$GEM/$ID.yml in ruby-advisory-db
+type RubySecAdvisoryYAML struct {
+	// ID is the name of the file without the extension (e.g. CVE-2020-8164, GHSA-xxxx-xxxx-xxxx, OSVDB-12345)
+	ID  string `yaml:"-"`
+	Gem string `yaml:"gem"`
+	// Cve and Ghsa have no prefix (e.g. 2020-8164, 8727-m6gj-mc37)
+	Cve                string   `yaml:"cve"`
+	Ghsa               string   `yaml:"ghsa"`
+	URL                string   `yaml:"url"`
+	Title              string   `yaml:"title"`
+	Date               string   `yaml:"date"`
+	Description        string   `yaml:"description"`
+	CvssV2             float64  `yaml:"cvss_v2"`
+	CvssV3             float64  `yaml:"cvss_v3"`
+	PatchedVersions    []string `yaml:"patched_versions"`
+	UnaffectedVersions []string `yaml:"unaffected_versions"`
+}
+
+// PackageAdvisory : an advisory of the advisory database of an ecosystem (rubysec, pypa or npm)
+type PackageAdvisory struct {
+	ID               int64                          `json:"-"`
+	Source           string                         `json:"source" gorm:"type:varchar(255);index:idx_package_advisories_source"`
+	AdvisoryID       string                         `json:"advisory_id" gorm:"type:varchar(255);index:idx_package_advisories_advisory_id"`
+	Title            string                         `json:"title" gorm:"type:text"`
+	Description      string                         `json:"description" gorm:"type:text"`
+	Severity         string                         `json:"severity" gorm:"type:varchar(255)"`
+	CvssScore        float64                        `json:"cvss_score"`
+	CvssVector       string                         `json:"cvss_vector" gorm:"type:varchar(255)"`
+	URL              string                         `json:"url" gorm:"type:text"`
+	PublishedDate    time.Time                      `json:"published_date"`
+	LastModifiedDate time.Time                      `json:"last_modified_date"`
+	CveIDs           []PackageAdvisoryCve           `json:"cve_ids"`
+	Vulnerabilities  []PackageAdvisoryVulnerability `json:"vulnerabilities"`
+}
+
+// PackageAdvisoryCve : the CVE linked to the advisory
+type PackageAdvisoryCve struct {
+	ID                int64  `json:"-"`
+	PackageAdvisoryID int64  `json:"-" gorm:"index:idx_package_advisory_cves_package_advisory_id"`
+	CveID             string `json:"cve_id" gorm:"type:varchar(255);index:idx_package_advisory_cves_cveid"`
+}
+
+// PackageAdvisoryVulnerability : the package affected. The constraints of the versions (e.g. ">= 4.0.0, < 4.0.6") are joined by " || ".
+// VulnerableVersionRange is empty if the database gives only the versions not affected (e.g. ruby-advisory-db)
+type PackageAdvisoryVulnerability struct {
+	ID                     int64  `json:"-"`
+	PackageAdvisoryID      int64  `json:"-" gorm:"index:idx_package_advisory_vulnerabilities_package_advisory_id"`
+	Ecosystem              string `json:"ecosystem" gorm:"type:varchar(255);index:idx_package_advisory_vulnerabilities_ecosystem_package_name"`
+	PackageName            string `json:"package_name" gorm:"type:varchar(255);index:idx_package_advisory_vulnerabilities_ecosystem_package_name"`
+	VulnerableVersionRange string `json:"vulnerable_version_range" gorm:"type:text"`
+	PatchedVersions        string `json:"patched_versions" gorm:"type:text"`
+	UnaffectedVersions     string `json:"unaffected_versions" gorm:"type:text"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// AlmaErrataJSON : ALSA in https://errata.almalinux.org/$RELEASE/errata.json
+type AlmaErrataJSON struct {
+	ID          string              `json:"id"`
+	Title       string              `json:"title"`
+	IssuedDate  AlmaDateJSON        `json:"issued_date"`
+	UpdatedDate AlmaDateJSON        `json:"updated_date"`
+	Severity    string              `json:"severity"`
+	Type        string              `json:"type"`
+	Description string              `json:"description"`
+	References  []AlmaReferenceJSON `json:"references"`
+	Pkglist     AlmaPkglistJSON     `json:"pkglist"`
+
+	// Release is not included in JSON, it is taken from the URL (e.g. 8, 9)
+	Release string `json:"-"`
+}
+
+// AlmaDateJSON : milliseconds since epoch
+type AlmaDateJSON struct {
+	Date int64 `json:"$date"`
+}
+
+// AlmaReferenceJSON :
+type AlmaReferenceJSON struct {
+	Href  string `json:"href"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Type  string `json:"type"`
+}
+
+// AlmaPkglistJSON :
+type AlmaPkglistJSON struct {
+	Name      string            `json:"name"`
+	Shortname string            `json:"shortname"`
+	Packages  []AlmaPackageJSON `json:"packages"`
+}
+
+// AlmaPackageJSON :
+type AlmaPackageJSON struct {
+	Name     string `json:"name"`
+	Epoch    string `json:"epoch"`
+	Version  string `json:"version"`
+	Release  string `json:"release"`
+	Arch     string `json:"arch"`
+	Filename string `json:"filename"`
+}
+
+// AlmaCVE :
+type AlmaCVE struct {
+	ID             int64          `json:"-"`
+	CveID          string         `json:"cve_id" gorm:"type:varchar(255);index:idx_alma_cves_cveid"`
+	Advisories     []AlmaAdvisory `json:"advisories"`
+	KnownExploited bool           `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit      `json:"exploits,omitempty" gorm:"-"`
+}
+
+// AlmaAdvisory :
+type AlmaAdvisory struct {
+	ID          int64         `json:"-"`
+	AlmaCVEID   int64         `json:"-" gorm:"index:idx_alma_advisories_alma_cve_id"`
+	AdvisoryID  string        `json:"advisory_id" gorm:"type:varchar(255)"`
+	ReleaseName string        `json:"release" gorm:"type:varchar(255);index:idx_alma_advisories_release_name"`
+	Title       string        `json:"title" gorm:"type:varchar(255)"`
+	Severity    string        `json:"severity" gorm:"type:varchar(255)"`
+	Description string        `json:"description" gorm:"type:text"`
+	IssuedDate  time.Time     `json:"issued_date"`
+	UpdatedDate time.Time     `json:"updated_date"`
+	Packages    []AlmaPackage `json:"packages"`
+}
+
+// AlmaPackage :
+type AlmaPackage struct {
+	ID             int64  `json:"-"`
+	AlmaAdvisoryID int64  `json:"-" gorm:"index:idx_alma_packages_alma_advisory_id"`
+	PackageName    string `json:"package_name" gorm:"type:varchar(255);index:idx_alma_packages_package_name"`
+	FixedVersion   string `json:"fixed_version" gorm:"type:varchar(255)"`
+}
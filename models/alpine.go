@@ -0,0 +1,40 @@
+package models
+
+// AlpineSecDBJSON : https://secdb.alpinelinux.org/v3.15/main.json
+type AlpineSecDBJSON struct {
+	ApkURL        string   `json:"apkurl"`
+	Archs         []string `json:"archs"`
+	RepoName      string   `json:"reponame"`
+	URLPrefix     string   `json:"urlprefix"`
+	DistroVersion string   `json:"distroversion"`
+	Packages      []struct {
+		Pkg AlpinePkgJSON `json:"pkg"`
+	} `json:"packages"`
+}
+
+// AlpinePkgJSON :
+type AlpinePkgJSON struct {
+	Name string `json:"name"`
+	// key: fixed version (e.g. 2.10.1-r0), value: CVE-IDs
+	SecFixes map[string][]string `json:"secfixes"`
+}
+
+// AlpineCVE :
+type AlpineCVE struct {
+	ID             int64           `json:"-"`
+	CveID          string          `json:"cve_id" gorm:"type:varchar(255);index:idx_alpine_cves_cveid"`
+	Packages       []AlpinePackage `json:"packages"`
+	KnownExploited bool            `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit       `json:"exploits,omitempty" gorm:"-"`
+}
+
+// AlpinePackage :
+type AlpinePackage struct {
+	ID          int64  `json:"-"`
+	AlpineCVEID int64  `json:"-" gorm:"index:idx_alpine_packages_alpine_cve_id"`
+	ReleaseName string `json:"release" gorm:"type:varchar(255);index:idx_alpine_packages_release_name"`
+	Repository  string `json:"repository" gorm:"type:varchar(255)"`
+	PackageName string `json:"package_name" gorm:"type:varchar(255);index:idx_alpine_packages_package_name"`
+	// "0" means that the package has never been affected in the release
+	FixedVersion string `json:"fixed_version" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// AmazonALASJSON : ALAS in aquasecurity/vuln-list
+type AmazonALASJSON struct {
+	ID          string                `json:"id"`
+	Title       string                `json:"title"`
+	Issued      AmazonDateJSON        `json:"issued"`
+	Updated     AmazonDateJSON        `json:"updated"`
+	Severity    string                `json:"severity"`
+	Description string                `json:"description"`
+	Packages    []AmazonPackageJSON   `json:"packages"`
+	References  []AmazonReferenceJSON `json:"references"`
+	CveIDs      []string              `json:"cveids"`
+
+	// Release is not included in JSON, it is taken from the directory name (e.g. 1, 2, 2022, 2023)
+	Release string `json:"-"`
+}
+
+// AmazonDateJSON :
+type AmazonDateJSON struct {
+	Date string `json:"date"`
+}
+
+// AmazonPackageJSON :
+type AmazonPackageJSON struct {
+	Name     string `json:"name"`
+	Epoch    string `json:"epoch"`
+	Version  string `json:"version"`
+	Release  string `json:"release"`
+	Arch     string `json:"arch"`
+	Filename string `json:"filename"`
+}
+
+// AmazonReferenceJSON :
+type AmazonReferenceJSON struct {
+	Href  string `json:"href"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Type  string `json:"type"`
+}
+
+// AmazonCVE :
+type AmazonCVE struct {
+	ID             int64            `json:"-"`
+	CveID          string           `json:"cve_id" gorm:"type:varchar(255);index:idx_amazon_cves_cveid"`
+	Advisories     []AmazonAdvisory `json:"advisories"`
+	KnownExploited bool             `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit        `json:"exploits,omitempty" gorm:"-"`
+}
+
+// AmazonAdvisory :
+type AmazonAdvisory struct {
+	ID          int64           `json:"-"`
+	AmazonCVEID int64           `json:"-" gorm:"index:idx_amazon_advisories_amazon_cve_id"`
+	AdvisoryID  string          `json:"advisory_id" gorm:"type:varchar(255)"`
+	ReleaseName string          `json:"release" gorm:"type:varchar(255);index:idx_amazon_advisories_release_name"`
+	Title       string          `json:"title" gorm:"type:varchar(255)"`
+	Severity    string          `json:"severity" gorm:"type:varchar(255)"`
+	Description string          `json:"description" gorm:"type:text"`
+	IssuedDate  time.Time       `json:"issued_date"`
+	UpdatedDate time.Time       `json:"updated_date"`
+	Packages    []AmazonPackage `json:"packages"`
+}
+
+// AmazonPackage :
+type AmazonPackage struct {
+	ID               int64  `json:"-"`
+	AmazonAdvisoryID int64  `json:"-" gorm:"index:idx_amazon_packages_amazon_advisory_id"`
+	PackageName      string `json:"package_name" gorm:"type:varchar(255);index:idx_amazon_packages_package_name"`
+	FixedVersion     string `json:"fixed_version" gorm:"type:varchar(255)"`
+	Arch             string `json:"arch" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// AnolisOVALXML : https://anas.openanolis.cn/api/data/OVAL/
+type AnolisOVALXML struct {
+	Definitions []AnolisDefinitionXML `xml:"definitions>definition"`
+	// Release is not in XML, it is set from URL
+	Release string `xml:"-"`
+}
+
+// AnolisDefinitionXML : ANSA
+type AnolisDefinitionXML struct {
+	ID       string            `xml:"id,attr"`
+	Metadata AnolisMetadataXML `xml:"metadata"`
+	Criteria AnolisCriteriaXML `xml:"criteria"`
+}
+
+// AnolisMetadataXML :
+type AnolisMetadataXML struct {
+	Title       string               `xml:"title"`
+	References  []AnolisReferenceXML `xml:"reference"`
+	Description string               `xml:"description"`
+	Severity    string               `xml:"advisory>severity"`
+	Issued      AnolisDateXML        `xml:"advisory>issued"`
+	Cves        []string             `xml:"advisory>cve"`
+}
+
+// AnolisReferenceXML : Source is ANSA or CVE
+type AnolisReferenceXML struct {
+	RefID  string `xml:"ref_id,attr"`
+	RefURL string `xml:"ref_url,attr"`
+	Source string `xml:"source,attr"`
+}
+
+// AnolisDateXML :
+type AnolisDateXML struct {
+	Date string `xml:"date,attr"`
+}
+
+// AnolisCriteriaXML :
+type AnolisCriteriaXML struct {
+	Criterions []AnolisCriterionXML `xml:"criterion"`
+	Criterias  []AnolisCriteriaXML  `xml:"criteria"`
+}
+
+// AnolisCriterionXML : e.g. "openssl is earlier than 1:1.1.1k-5.an8"
+type AnolisCriterionXML struct {
+	Comment string `xml:"comment,attr"`
+}
+
+// AnolisCVE :
+type AnolisCVE struct {
+	ID             int64            `json:"-"`
+	CveID          string           `json:"cve_id" gorm:"type:varchar(255);index:idx_anolis_cves_cveid"`
+	Advisories     []AnolisAdvisory `json:"advisories"`
+	KnownExploited bool             `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit        `json:"exploits,omitempty" gorm:"-"`
+}
+
+// AnolisAdvisory :
+type AnolisAdvisory struct {
+	ID          int64           `json:"-"`
+	AnolisCVEID int64           `json:"-" gorm:"index:idx_anolis_advisories_anolis_cve_id"`
+	AdvisoryID  string          `json:"advisory_id" gorm:"type:varchar(255)"`
+	Title       string          `json:"title" gorm:"type:varchar(255)"`
+	Severity    string          `json:"severity" gorm:"type:varchar(255)"`
+	Description string          `json:"description" gorm:"type:text"`
+	IssuedDate  time.Time       `json:"issued_date"`
+	Packages    []AnolisPackage `json:"packages"`
+}
+
+// AnolisPackage :
+type AnolisPackage struct {
+	ID               int64  `json:"-"`
+	AnolisAdvisoryID int64  `json:"-" gorm:"index:idx_anolis_packages_anolis_advisory_id"`
+	ReleaseName      string `json:"release" gorm:"type:varchar(255);index:idx_anolis_packages_release_name"`
+	PackageName      string `json:"package_name" gorm:"type:varchar(255);index:idx_anolis_packages_package_name"`
+	FixedVersion     string `json:"fixed_version" gorm:"type:varchar(255)"`
+}
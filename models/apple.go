@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// AppleReleaseHTML : a row of the table of the Apple security releases (https://support.apple.com/en-us/100100) with the CVE-IDs in the linked page
+type AppleReleaseHTML struct {
+	// ReleaseID is the ID of the page of the release (e.g. 120894), empty if the release has no published CVE entries
+	ReleaseID    string
+	Name         string
+	URL          string
+	AvailableFor string
+	// ReleaseDate is e.g. 07 Mar 2024
+	ReleaseDate string
+	CveIDs      []string
+}
+
+// ApplePmvJSON : the response of Apple Software Lookup Service (https://gdmf.apple.com/v2/pmv), with the builds signed currently
+type ApplePmvJSON struct {
+	PublicAssetSets map[string][]ApplePmvAssetJSON `json:"PublicAssetSets"`
+	AssetSets       map[string][]ApplePmvAssetJSON `json:"AssetSets"`
+}
+
+// ApplePmvAssetJSON : a version of iOS or macOS and its build
+type ApplePmvAssetJSON struct {
+	ProductVersion string `json:"ProductVersion"`
+	Build          string `json:"Build"`
+	PostingDate    string `json:"PostingDate"`
+}
+
+// AppleSecurityRelease : a security release of Apple (e.g. macOS Sonoma 14.4, iOS 17.4 and iPadOS 17.4)
+type AppleSecurityRelease struct {
+	ID           int64          `json:"-"`
+	ReleaseID    string         `json:"release_id" gorm:"type:varchar(255);index:idx_apple_security_releases_release_id"`
+	Name         string         `json:"name" gorm:"type:varchar(255)"`
+	URL          string         `json:"url" gorm:"type:text"`
+	AvailableFor string         `json:"available_for" gorm:"type:text"`
+	ReleaseDate  time.Time      `json:"release_date"`
+	CveIDs       []AppleCve     `json:"cve_ids"`
+	Products     []AppleProduct `json:"products"`
+}
+
+// AppleCve : the CVE fixed by the release
+type AppleCve struct {
+	ID                     int64  `json:"-"`
+	AppleSecurityReleaseID int64  `json:"-" gorm:"index:idx_apple_cves_apple_security_release_id"`
+	CveID                  string `json:"cve_id" gorm:"type:varchar(255);index:idx_apple_cves_cveid"`
+}
+
+// AppleProduct : the product and the version fixed by the release (e.g. macos 14.4).
+// Build is the build of the version (e.g. 23E214), empty if not known by Apple Software Lookup Service
+type AppleProduct struct {
+	ID                     int64  `json:"-"`
+	AppleSecurityReleaseID int64  `json:"-" gorm:"index:idx_apple_products_apple_security_release_id"`
+	Product                string `json:"product" gorm:"type:varchar(255);index:idx_apple_products_product"`
+	Version                string `json:"version" gorm:"type:varchar(255)"`
+	Build                  string `json:"build" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Sources of the Chinese vulnerability databases
+const (
+	// CnSourceCnnvd : China National Vulnerability Database of Information Security (https://www.cnnvd.org.cn)
+	CnSourceCnnvd = "cnnvd"
+	// CnSourceCnvd : China National Vulnerability Database (https://www.cnvd.org.cn)
+	CnSourceCnvd = "cnvd"
+)
+
+// CnnvdXML : the XML data feed of CNNVD
+type CnnvdXML struct {
+	Entries []CnnvdEntryXML `xml:"entry"`
+}
+
+// CnnvdEntryXML : VulnID is CNNVD-ID (e.g. CNNVD-202112-799). Severity is 超危, 高危, 中危 or 低危
+type CnnvdEntryXML struct {
+	Name         string `xml:"name"`
+	VulnID       string `xml:"vuln-id"`
+	Published    string `xml:"published"`
+	Modified     string `xml:"modified"`
+	Source       string `xml:"source"`
+	Severity     string `xml:"severity"`
+	VulnType     string `xml:"vuln-type"`
+	VulnDescript string `xml:"vuln-descript"`
+	CveID        string `xml:"other-id>cve-id"`
+	VulnSolution string `xml:"vuln-solution"`
+}
+
+// CnvdXML : the shared XML data of CNVD. The element names are spelled as CNVD does (vulnerabitys, serverity)
+type CnvdXML struct {
+	Vulnerabilities []CnvdVulnerabilityXML `xml:"vulnerabity"`
+}
+
+// CnvdVulnerabilityXML : Number is CNVD-ID (e.g. CNVD-2021-95914). Serverity is 高, 中 or 低
+type CnvdVulnerabilityXML struct {
+	Number        string   `xml:"number"`
+	CveIDs        []string `xml:"cves>cve>cveNumber"`
+	Title         string   `xml:"title"`
+	Serverity     string   `xml:"serverity"`
+	Products      []string `xml:"products>product"`
+	SubmitTime    string   `xml:"submitTime"`
+	OpenTime      string   `xml:"openTime"`
+	ReferenceLink string   `xml:"referenceLink"`
+	FormalWay     string   `xml:"formalWay"`
+	Description   string   `xml:"description"`
+}
+
+// CnAdvisory : an advisory of CNNVD or CNVD. The texts are in Chinese.
+// Severity is normalized to critical, high, medium or low, and OriginalSeverity is the one in the advisory.
+type CnAdvisory struct {
+	ID               int64       `json:"-"`
+	Source           string      `json:"source" gorm:"type:varchar(255);index:idx_cn_advisories_source"`
+	AdvisoryID       string      `json:"advisory_id" gorm:"type:varchar(255);index:idx_cn_advisories_advisory_id"`
+	Title            string      `json:"title" gorm:"type:text"`
+	Description      string      `json:"description" gorm:"type:text"`
+	Solution         string      `json:"solution" gorm:"type:text"`
+	Severity         string      `json:"severity" gorm:"type:varchar(255)"`
+	OriginalSeverity string      `json:"original_severity" gorm:"type:varchar(255)"`
+	URL              string      `json:"url" gorm:"type:text"`
+	PublishedDate    time.Time   `json:"published_date"`
+	LastModifiedDate time.Time   `json:"last_modified_date"`
+	CveIDs           []CnCve     `json:"cve_ids"`
+	Products         []CnProduct `json:"products"`
+}
+
+// CnCve : the CVE linked to the advisory
+type CnCve struct {
+	ID           int64  `json:"-"`
+	CnAdvisoryID int64  `json:"-" gorm:"index:idx_cn_cves_cn_advisory_id"`
+	CveID        string `json:"cve_id" gorm:"type:varchar(255);index:idx_cn_cves_cveid"`
+}
+
+// CnProduct : the product affected as written in the advisory (e.g. Apache Log4j 2.0-beta9-2.14.1)
+type CnProduct struct {
+	ID           int64  `json:"-"`
+	CnAdvisoryID int64  `json:"-" gorm:"index:idx_cn_products_cn_advisory_id"`
+	Product      string `json:"product" gorm:"type:text"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// CoverageReport : CVEs in NVD covered by each source
+type CoverageReport struct {
+	NVDCount int              `json:"nvd_count"`
+	Sources  []CoverageSource `json:"sources"`
+	// Uncovered has the CVEs in NVD which no source has a statement for
+	Uncovered []string `json:"uncovered"`
+}
+
+// CoverageSource :
+type CoverageSource struct {
+	Source  string `json:"source"`
+	Covered int    `json:"covered"`
+	// NotInNVD is the number of CVEs of the source which are not in NVD (e.g. rejected or reserved)
+	NotInNVD int     `json:"not_in_nvd"`
+	Ratio    float64 `json:"ratio"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// CveDetail : the records of a CVE in all the sources. The sources without the CVE are nil
+type CveDetail struct {
+	CveID      string         `json:"cve_id"`
+	RedHat     *RedhatCVE     `json:"redhat,omitempty"`
+	Debian     *DebianCVE     `json:"debian,omitempty"`
+	Ubuntu     *UbuntuCVE     `json:"ubuntu,omitempty"`
+	Microsoft  *MicrosoftCVE  `json:"microsoft,omitempty"`
+	Amazon     *AmazonCVE     `json:"amazon,omitempty"`
+	Alpine     *AlpineCVE     `json:"alpine,omitempty"`
+	Wolfi      *WolfiCVE      `json:"wolfi,omitempty"`
+	Oracle     *OracleCVE     `json:"oracle,omitempty"`
+	Rocky      *RockyCVE      `json:"rocky,omitempty"`
+	Alma       *AlmaCVE       `json:"alma,omitempty"`
+	Photon     *PhotonCVE     `json:"photon,omitempty"`
+	Mariner    *MarinerCVE    `json:"mariner,omitempty"`
+	OpenEuler  *OpenEulerCVE  `json:"openeuler,omitempty"`
+	Anolis     *AnolisCVE     `json:"anolis,omitempty"`
+	Nvd        *NvdCVE        `json:"nvd,omitempty"`
+	CveProgram *CveProgramCVE `json:"cveprogram,omitempty"`
+	KEV        *KEVEntry      `json:"kev,omitempty"`
+	Epss       *EpssScore     `json:"epss,omitempty"`
+	Exploits   []Exploit      `json:"exploits,omitempty"`
+}
+
+// PackagesCves : the CVEs of many packages of a release queried at once
+type PackagesCves struct {
+	Family     string                 `json:"family"`
+	Release    string                 `json:"release"`
+	Packages   map[string]interface{} `json:"packages"`
+	NotCovered []string               `json:"not_covered,omitempty"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// States of the CVE records of CVE Program
+const (
+	// CveStatePublished : the CVE record is published
+	CveStatePublished = "PUBLISHED"
+	// CveStateRejected : the CVE-ID was rejected (e.g. a duplicate or not a vulnerability)
+	CveStateRejected = "REJECTED"
+)
+
+// CveRecordJSON : a CVE record in CVE JSON 5 format of https://github.com/CVEProject/cvelistV5
+type CveRecordJSON struct {
+	DataVersion string `json:"dataVersion"`
+	CveMetadata struct {
+		CveID             string `json:"cveId"`
+		AssignerShortName string `json:"assignerShortName"`
+		State             string `json:"state"`
+		DateReserved      string `json:"dateReserved"`
+		DatePublished     string `json:"datePublished"`
+		DateUpdated       string `json:"dateUpdated"`
+		DateRejected      string `json:"dateRejected"`
+	} `json:"cveMetadata"`
+	Containers struct {
+		Cna struct {
+			Title           string              `json:"title"`
+			Descriptions    []CveLangStringJSON `json:"descriptions"`
+			RejectedReasons []CveLangStringJSON `json:"rejectedReasons"`
+		} `json:"cna"`
+	} `json:"containers"`
+}
+
+// CveLangStringJSON :
+type CveLangStringJSON struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+// CveDeltaJSON : cves/delta.json of cvelistV5, the CVE records changed since the previous fetch of CVE Services.
+// cves/deltaLog.json is the list of them in the last 30 days, newest first
+type CveDeltaJSON struct {
+	FetchTime       string             `json:"fetchTime"`
+	NumberOfChanges int                `json:"numberOfChanges"`
+	New             []CveDeltaItemJSON `json:"new"`
+	Updated         []CveDeltaItemJSON `json:"updated"`
+}
+
+// CveDeltaItemJSON : GithubLink is the raw URL of the CVE record
+type CveDeltaItemJSON struct {
+	CveID       string `json:"cveId"`
+	CveOrgLink  string `json:"cveOrgLink"`
+	GithubLink  string `json:"githubLink"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// CveProgramCVE : the state and the dates of the CVE record of CVE Program, which are independent of the analysis of NVD
+type CveProgramCVE struct {
+	ID                int64  `json:"-"`
+	CveID             string `json:"cve_id" gorm:"type:varchar(255);index:idx_cve_program_cves_cveid"`
+	State             string `json:"state" gorm:"type:varchar(255)"`
+	AssignerShortName string `json:"assigner_short_name" gorm:"type:varchar(255)"`
+	Title             string `json:"title" gorm:"type:text"`
+	// Description is the reason of the rejection for the rejected CVE
+	Description   string    `json:"description" gorm:"type:text"`
+	DateReserved  time.Time `json:"date_reserved"`
+	DatePublished time.Time `json:"date_published"`
+	DateUpdated   time.Time `json:"date_updated"`
+	DateRejected  time.Time `json:"date_rejected"`
+}
+
+// CveProgramSync : FetchTime is the fetchTime of the latest delta of cvelistV5 applied to DB
+type CveProgramSync struct {
+	ID        int64     `json:"-"`
+	FetchTime time.Time `json:"fetch_time"`
+}
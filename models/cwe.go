@@ -0,0 +1,31 @@
+package models
+
+// CweCatalogXML : the CWE list of MITRE (https://cwe.mitre.org/data/xml/cwec_latest.xml.zip)
+type CweCatalogXML struct {
+	Version    string `xml:"Version,attr"`
+	Weaknesses []struct {
+		ID          string `xml:"ID,attr"`
+		Name        string `xml:"Name,attr"`
+		Abstraction string `xml:"Abstraction,attr"`
+		Status      string `xml:"Status,attr"`
+		Description string `xml:"Description"`
+	} `xml:"Weaknesses>Weakness"`
+	Categories []struct {
+		ID      string `xml:"ID,attr"`
+		Name    string `xml:"Name,attr"`
+		Status  string `xml:"Status,attr"`
+		Summary string `xml:"Summary"`
+	} `xml:"Categories>Category"`
+}
+
+// Cwe : a weakness or a category in the CWE list
+type Cwe struct {
+	ID int64 `json:"-"`
+	// CweID is in the form of CWE-79
+	CweID       string `json:"cwe_id" gorm:"type:varchar(255);index:idx_cwes_cwe_id"`
+	Name        string `json:"name" gorm:"type:text"`
+	Description string `json:"description" gorm:"type:text"`
+	// Abstraction is Pillar, Class, Base or Variant of the weakness, and empty for the category
+	Abstraction string `json:"abstraction,omitempty" gorm:"type:varchar(255)"`
+	Status      string `json:"status" gorm:"type:varchar(255)"`
+}
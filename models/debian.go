@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // DebianJSON :
 type DebianJSON map[string]DebianCveMap
 
@@ -22,13 +24,34 @@ type DebianReleaseJSON struct {
 	Urgency      string            `json:"urgency"`
 }
 
+// DebianAdvisoryJSON : DSA/DLA in data/DSA/list and data/DLA/list of Debian Security Tracker
+type DebianAdvisoryJSON struct {
+	AdvisoryID  string
+	Date        string
+	PackageName string
+	Description string
+	CveIDs      []string
+	Releases    []DebianAdvisoryReleaseJSON
+}
+
+// DebianAdvisoryReleaseJSON : e.g. "[buster] - openssl 1.1.1d-0+deb10u6"
+type DebianAdvisoryReleaseJSON struct {
+	Release      string
+	PackageName  string
+	FixedVersion string
+}
+
 // DebianCVE :
 type DebianCVE struct {
-	ID          int64  `json:"-"`
-	CveID       string `gorm:"index:idx_debian_cves_cveid;type:varchar(255);"`
-	Scope       string `gorm:"type:varchar(255)"`
-	Description string `gorm:"type:text"`
-	Package     []DebianPackage
+	ID             int64  `json:"-"`
+	CveID          string `gorm:"index:idx_debian_cves_cveid;type:varchar(255);"`
+	Scope          string `gorm:"type:varchar(255)"`
+	Description    string `gorm:"type:text"`
+	Package        []DebianPackage
+	Advisories     []DebianAdvisory
+	KnownExploited bool       `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit  `json:"exploits,omitempty" gorm:"-"`
+	Epss           *EpssScore `json:"epss,omitempty" gorm:"-"`
 }
 
 // DebianPackage :
@@ -36,6 +59,7 @@ type DebianPackage struct {
 	ID          int64  `json:"-"`
 	DebianCVEID int64  `json:"-" gorm:"index:idx_debian_packages_debian_cve_id"`
 	PackageName string `gorm:"type:varchar(255);index:idx_debian_packages_package_name"`
+	DebianBug   string `gorm:"type:varchar(255);index:idx_debian_packages_debian_bug"`
 	Release     []DebianRelease
 }
 
@@ -48,4 +72,90 @@ type DebianRelease struct {
 	FixedVersion    string `gorm:"type:varchar(255);"`
 	Urgency         string `gorm:"type:varchar(255);"`
 	Version         string `gorm:"type:varchar(255);"`
+	// Channel is the channel of the extended support (e.g. elts), or empty for the standard support
+	Channel string `gorm:"type:varchar(255);"`
+}
+
+// DebianAdvisory : DSA/DLA which fixes the CVE in the release
+type DebianAdvisory struct {
+	ID           int64  `json:"-"`
+	DebianCVEID  int64  `json:"-" gorm:"index:idx_debian_advisories_debian_cve_id"`
+	AdvisoryID   string `gorm:"type:varchar(255);index:idx_debian_advisories_advisory_id"`
+	IssuedDate   time.Time
+	PackageName  string `gorm:"type:varchar(255)"`
+	ProductName  string `gorm:"type:varchar(255)"`
+	FixedVersion string `gorm:"type:varchar(255)"`
+	Urgency      string `gorm:"type:varchar(255)"`
+}
+
+// DebianOVALXML : OVAL of a Debian release in https://www.debian.org/security/oval/
+type DebianOVALXML struct {
+	Definitions []DebianOVALDefinitionXML `xml:"definitions>definition"`
+	// Release is not in XML, it is set from URL (e.g. bookworm)
+	Release string `xml:"-"`
+}
+
+// DebianOVALDefinitionXML :
+type DebianOVALDefinitionXML struct {
+	ID       string `xml:"id,attr"`
+	Class    string `xml:"class,attr"`
+	Metadata struct {
+		Title    string `xml:"title"`
+		Affected struct {
+			Platform string `xml:"platform"`
+			Product  string `xml:"product"`
+		} `xml:"affected"`
+		References []struct {
+			RefID  string `xml:"ref_id,attr"`
+			Source string `xml:"source,attr"`
+		} `xml:"reference"`
+	} `xml:"metadata"`
+	Criteria DebianOVALCriteriaXML `xml:"criteria"`
+}
+
+// DebianOVALCriteriaXML :
+type DebianOVALCriteriaXML struct {
+	Criterions []struct {
+		Comment string `xml:"comment,attr"`
+	} `xml:"criterion"`
+	Criterias []DebianOVALCriteriaXML `xml:"criteria"`
+}
+
+// Kinds of DebianOVALDisagreement
+const (
+	// DebianOVALMissingInTracker : OVAL has the package of the CVE, but the tracker has no status of it in the release
+	DebianOVALMissingInTracker = "missing_in_tracker"
+	// DebianOVALStatusMismatch : one is fixed, and the other is not
+	DebianOVALStatusMismatch = "status_mismatch"
+	// DebianOVALVersionMismatch : both are fixed in the different versions
+	DebianOVALVersionMismatch = "version_mismatch"
+)
+
+// DebianOVALCrossCheckReport : disagreements of the fix status between Debian Security Tracker and Debian OVAL
+type DebianOVALCrossCheckReport struct {
+	Releases      []DebianOVALCrossCheckRelease `json:"releases"`
+	Disagreements []DebianOVALDisagreement      `json:"disagreements"`
+}
+
+// DebianOVALCrossCheckRelease : the summary of a release
+type DebianOVALCrossCheckRelease struct {
+	Release string `json:"release"`
+	// Checked is the number of the pairs of CVE and package in OVAL
+	Checked          int `json:"checked"`
+	MissingInTracker int `json:"missing_in_tracker"`
+	StatusMismatch   int `json:"status_mismatch"`
+	VersionMismatch  int `json:"version_mismatch"`
+}
+
+// DebianOVALDisagreement :
+type DebianOVALDisagreement struct {
+	Release     string `json:"release"`
+	CveID       string `json:"cve_id"`
+	PackageName string `json:"package_name"`
+	Kind        string `json:"kind"`
+	// TrackerStatus is open, resolved or undetermined of the tracker, or empty if missing
+	TrackerStatus       string `json:"tracker_status"`
+	TrackerFixedVersion string `json:"tracker_fixed_version"`
+	// OVALFixedVersion is empty if it is not fixed in OVAL
+	OVALFixedVersion string `json:"oval_fixed_version"`
 }
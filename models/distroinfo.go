@@ -0,0 +1,34 @@
+package models
+
+// Distributions of distro-info-data
+const (
+	// DistroDebian : debian.csv of distro-info-data
+	DistroDebian = "debian"
+	// DistroUbuntu : ubuntu.csv of distro-info-data
+	DistroUbuntu = "ubuntu"
+)
+
+// DistroInfoCSV : a row of debian.csv or ubuntu.csv of https://salsa.debian.org/debian/distro-info-data
+type DistroInfoCSV struct {
+	Distro string
+	// Version is e.g. 12 of Debian, 22.04 LTS of Ubuntu, or empty for sid and experimental
+	Version  string
+	Codename string
+	// Series is the codename in lower case (e.g. bookworm, jammy)
+	Series  string
+	Created string
+	Release string
+	EOL     string
+}
+
+// DistroRelease : the codename of a version of Debian or Ubuntu
+type DistroRelease struct {
+	ID     int64  `json:"-"`
+	Distro string `json:"distro" gorm:"type:varchar(255);index:idx_distro_releases_distro"`
+	// Version is the key of the releases in gost, e.g. 12 of Debian, 2204 of Ubuntu
+	Version  string `json:"version" gorm:"type:varchar(255)"`
+	Codename string `json:"codename" gorm:"type:varchar(255)"`
+	// ReleaseDate and EOL are YYYY-MM-DD, or empty if not released or not decided yet
+	ReleaseDate string `json:"release_date" gorm:"type:varchar(255)"`
+	EOL         string `json:"eol" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// EpssCSV : https://epss.cyentia.com/epss_scores-current.csv.gz
+type EpssCSV struct {
+	// ModelVersion and ScoreDate are in the comment line (e.g. #model_version:v2023.03.01,score_date:2023-03-07T00:00:00+0000)
+	ModelVersion string
+	ScoreDate    string
+	Scores       []EpssScoreCSV
+}
+
+// EpssScoreCSV : a row of cve,epss,percentile
+type EpssScoreCSV struct {
+	CveID      string
+	Epss       float64
+	Percentile float64
+}
+
+// EpssScore : Exploit Prediction Scoring System (https://www.first.org/epss/)
+type EpssScore struct {
+	ID    int64  `json:"-"`
+	CveID string `json:"cve_id" gorm:"type:varchar(255);index:idx_epss_scores_cveid"`
+	// Score is the probability of exploitation in the next 30 days
+	Score        float64   `json:"score"`
+	Percentile   float64   `json:"percentile"`
+	ModelVersion string    `json:"model_version" gorm:"type:varchar(255)"`
+	ScoreDate    time.Time `json:"score_date"`
+}
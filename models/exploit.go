@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Sources of the public exploits
+const (
+	// ExploitSourceExploitDB : Exploit Database (https://www.exploit-db.com)
+	ExploitSourceExploitDB = "exploitdb"
+	// ExploitSourceMetasploit : the modules of Metasploit Framework
+	ExploitSourceMetasploit = "metasploit"
+)
+
+// ExploitDBCSV : a row of https://gitlab.com/exploit-database/exploitdb/-/raw/main/files_exploits.csv
+type ExploitDBCSV struct {
+	ID            string
+	File          string
+	Description   string
+	DatePublished string
+	Type          string
+	Platform      string
+	Verified      string
+	// Codes are the IDs separated by semicolons (e.g. CVE-2021-44228;OSVDB-12345)
+	Codes string
+}
+
+// MetasploitModuleJSON : a module in https://github.com/rapid7/metasploit-framework/blob/master/db/modules_metadata_base.json
+type MetasploitModuleJSON struct {
+	Name           string   `json:"name"`
+	Fullname       string   `json:"fullname"`
+	Type           string   `json:"type"`
+	Rank           int      `json:"rank"`
+	DisclosureDate string   `json:"disclosure_date"`
+	References     []string `json:"references"`
+	Platform       string   `json:"platform"`
+	Path           string   `json:"path"`
+}
+
+// Exploit : a public exploit of the CVE in Exploit-DB or Metasploit
+type Exploit struct {
+	ID     int64  `json:"-"`
+	CveID  string `json:"cve_id" gorm:"type:varchar(255);index:idx_exploits_cveid"`
+	Source string `json:"source" gorm:"type:varchar(255)"`
+	// ExploitID is EDB-ID of Exploit-DB or the full name of the Metasploit module (e.g. exploit/multi/http/log4shell_header_injection)
+	ExploitID     string    `json:"exploit_id" gorm:"type:varchar(255)"`
+	Title         string    `json:"title" gorm:"type:text"`
+	Type          string    `json:"type" gorm:"type:varchar(255)"`
+	Platform      string    `json:"platform" gorm:"type:varchar(255)"`
+	URL           string    `json:"url" gorm:"type:text"`
+	Verified      bool      `json:"verified"`
+	PublishedDate time.Time `json:"published_date"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import "time"
+
+// GhsaGraphQLResponseJSON : a page of securityVulnerabilities in GitHub GraphQL API
+type GhsaGraphQLResponseJSON struct {
+	Data struct {
+		SecurityVulnerabilities struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []GhsaVulnerabilityJSON `json:"nodes"`
+		} `json:"securityVulnerabilities"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// GhsaVulnerabilityJSON : the package and the version range affected by the advisory
+type GhsaVulnerabilityJSON struct {
+	Advisory GhsaAdvisoryJSON `json:"advisory"`
+	Package  struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	// VulnerableVersionRange : e.g. "< 1.2.3", ">= 4.0.0, < 4.0.6" and "= 0.2.0"
+	VulnerableVersionRange string `json:"vulnerableVersionRange"`
+	FirstPatchedVersion    *struct {
+		Identifier string `json:"identifier"`
+	} `json:"firstPatchedVersion"`
+}
+
+// GhsaAdvisoryJSON :
+type GhsaAdvisoryJSON struct {
+	GhsaID      string  `json:"ghsaId"`
+	Summary     string  `json:"summary"`
+	Description string  `json:"description"`
+	Severity    string  `json:"severity"`
+	PublishedAt string  `json:"publishedAt"`
+	UpdatedAt   string  `json:"updatedAt"`
+	WithdrawnAt *string `json:"withdrawnAt"`
+	Identifiers []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"identifiers"`
+	References []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+	Cvss struct {
+		Score        float64 `json:"score"`
+		VectorString string  `json:"vectorString"`
+	} `json:"cvss"`
+	Cwes struct {
+		Nodes []struct {
+			CweID string `json:"cweId"`
+		} `json:"nodes"`
+	} `json:"cwes"`
+}
+
+// GhsaAdvisory : GitHub Security Advisory
+type GhsaAdvisory struct {
+	ID          int64  `json:"-"`
+	GhsaID      string `json:"ghsa_id" gorm:"type:varchar(255);index:idx_ghsa_advisories_ghsa_id"`
+	Summary     string `json:"summary" gorm:"type:text"`
+	Description string `json:"description" gorm:"type:text"`
+	// Severity is LOW, MODERATE, HIGH or CRITICAL
+	Severity         string              `json:"severity" gorm:"type:varchar(255)"`
+	CvssScore        float64             `json:"cvss_score"`
+	CvssVector       string              `json:"cvss_vector" gorm:"type:varchar(255)"`
+	PublishedDate    time.Time           `json:"published_date"`
+	LastModifiedDate time.Time           `json:"last_modified_date"`
+	Identifiers      []GhsaIdentifier    `json:"identifiers"`
+	References       []GhsaReference     `json:"references"`
+	Vulnerabilities  []GhsaVulnerability `json:"vulnerabilities"`
+	Cwes             []GhsaCwe           `json:"cwes"`
+	CweDetails       []Cwe               `json:"cwe_details,omitempty" gorm:"-"`
+}
+
+// GhsaCwe : the CWE of the advisory (e.g. CWE-79)
+type GhsaCwe struct {
+	ID             int64  `json:"-"`
+	GhsaAdvisoryID int64  `json:"-" gorm:"index:idx_ghsa_cwes_ghsa_advisory_id"`
+	CweID          string `json:"cwe_id" gorm:"type:varchar(255)"`
+}
+
+// GhsaIdentifier : Type is GHSA or CVE
+type GhsaIdentifier struct {
+	ID             int64  `json:"-"`
+	GhsaAdvisoryID int64  `json:"-" gorm:"index:idx_ghsa_identifiers_ghsa_advisory_id"`
+	Type           string `json:"type" gorm:"type:varchar(255)"`
+	Value          string `json:"value" gorm:"type:varchar(255);index:idx_ghsa_identifiers_value"`
+}
+
+// GhsaReference :
+type GhsaReference struct {
+	ID             int64  `json:"-"`
+	GhsaAdvisoryID int64  `json:"-" gorm:"index:idx_ghsa_references_ghsa_advisory_id"`
+	URL            string `json:"url" gorm:"type:text"`
+}
+
+// GhsaVulnerability :
+type GhsaVulnerability struct {
+	ID             int64 `json:"-"`
+	GhsaAdvisoryID int64 `json:"-" gorm:"index:idx_ghsa_vulnerabilities_ghsa_advisory_id"`
+	// Ecosystem is NPM, PIP, GO, MAVEN or RUBYGEMS
+	Ecosystem              string `json:"ecosystem" gorm:"type:varchar(255);index:idx_ghsa_vulnerabilities_ecosystem_package_name"`
+	PackageName            string `json:"package_name" gorm:"type:varchar(255);index:idx_ghsa_vulnerabilities_ecosystem_package_name"`
+	VulnerableVersionRange string `json:"vulnerable_version_range" gorm:"type:varchar(255)"`
+	FirstPatchedVersion    string `json:"first_patched_version" gorm:"type:varchar(255)"`
+}
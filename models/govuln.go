@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// GoVulnIndexJSON : an entry of https://vuln.go.dev/index/vulns.json
+type GoVulnIndexJSON struct {
+	ID       string   `json:"id"`
+	Modified string   `json:"modified"`
+	Aliases  []string `json:"aliases"`
+}
+
+// GoVulnJSON : an entry of the Go vulnerability database in OSV schema with the Go specific fields
+// https://go.dev/security/vuln/database#schema
+type GoVulnJSON struct {
+	ID        string               `json:"id"`
+	Modified  string               `json:"modified"`
+	Published string               `json:"published"`
+	Withdrawn string               `json:"withdrawn"`
+	Aliases   []string             `json:"aliases"`
+	Summary   string               `json:"summary"`
+	Details   string               `json:"details"`
+	Affected  []GoVulnAffectedJSON `json:"affected"`
+}
+
+// GoVulnAffectedJSON : Package.Name is the module path (stdlib and toolchain for Go itself)
+type GoVulnAffectedJSON struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges            []OsvRangeJSON `json:"ranges"`
+	EcosystemSpecific struct {
+		Imports []GoVulnImportJSON `json:"imports"`
+	} `json:"ecosystem_specific"`
+}
+
+// GoVulnImportJSON : the package and the symbols affected. No symbol means the whole package
+type GoVulnImportJSON struct {
+	Path    string   `json:"path"`
+	Goos    []string `json:"goos"`
+	Goarch  []string `json:"goarch"`
+	Symbols []string `json:"symbols"`
+}
+
+// GoVuln : a vulnerability of a module
+type GoVuln struct {
+	ID            int64          `json:"-"`
+	GoID          string         `json:"go_id" gorm:"type:varchar(255);index:idx_go_vulns_go_id"`
+	ModulePath    string         `json:"module_path" gorm:"type:varchar(255);index:idx_go_vulns_module_path"`
+	Summary       string         `json:"summary" gorm:"type:text"`
+	Details       string         `json:"details" gorm:"type:text"`
+	PublishedDate time.Time      `json:"published_date"`
+	ModifiedDate  time.Time      `json:"modified_date"`
+	Aliases       []GoVulnAlias  `json:"aliases"`
+	Ranges        []GoVulnRange  `json:"ranges"`
+	Symbols       []GoVulnSymbol `json:"symbols"`
+}
+
+// GoVulnAlias : CVE-ID or GHSA-ID
+type GoVulnAlias struct {
+	ID       int64  `json:"-"`
+	GoVulnID int64  `json:"-" gorm:"index:idx_go_vuln_aliases_go_vuln_id"`
+	Alias    string `json:"alias" gorm:"type:varchar(255);index:idx_go_vuln_aliases_alias"`
+}
+
+// GoVulnRange : the versions from Introduced (inclusive) to Fixed (exclusive). Empty Fixed means not fixed
+type GoVulnRange struct {
+	ID         int64  `json:"-"`
+	GoVulnID   int64  `json:"-" gorm:"index:idx_go_vuln_ranges_go_vuln_id"`
+	Introduced string `json:"introduced" gorm:"type:varchar(255)"`
+	Fixed      string `json:"fixed" gorm:"type:varchar(255)"`
+}
+
+// GoVulnSymbol : the affected symbol of the package in the module. Empty Symbol means the whole package
+type GoVulnSymbol struct {
+	ID          int64  `json:"-"`
+	GoVulnID    int64  `json:"-" gorm:"index:idx_go_vuln_symbols_go_vuln_id"`
+	PackagePath string `json:"package_path" gorm:"type:varchar(255);index:idx_go_vuln_symbols_package_path_symbol"`
+	Symbol      string `json:"symbol" gorm:"type:varchar(255);index:idx_go_vuln_symbols_package_path_symbol"`
+	Goos        string `json:"goos,omitempty" gorm:"type:varchar(255)"`
+	Goarch      string `json:"goarch,omitempty" gorm:"type:varchar(255)"`
+}
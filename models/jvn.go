@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// JvnRdfXML : the RSS 1.0 feed of JVN iPedia (e.g. https://jvndb.jvn.jp/ja/rss/years/jvndb_2023.rdf)
+type JvnRdfXML struct {
+	Items []JvnItemXML `xml:"item"`
+}
+
+// JvnItemXML : Identifier is JVNDB-ID (e.g. JVNDB-2023-000001). Title and Description are in Japanese
+type JvnItemXML struct {
+	About       string            `xml:"about,attr"`
+	Title       string            `xml:"title"`
+	Link        string            `xml:"link"`
+	Description string            `xml:"description"`
+	Identifier  string            `xml:"identifier"`
+	References  []JvnReferenceXML `xml:"references"`
+	Cpes        []JvnCpeXML       `xml:"cpe"`
+	Cvsses      []JvnCvssXML      `xml:"cvss"`
+	Date        string            `xml:"date"`
+	Issued      string            `xml:"issued"`
+	Modified    string            `xml:"modified"`
+}
+
+// JvnReferenceXML : Source is CVE, JVN, US-CERT and so on. ID is CVE-ID if Source is CVE
+type JvnReferenceXML struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Title  string `xml:"title,attr"`
+	URL    string `xml:",chardata"`
+}
+
+// JvnCpeXML : the product of the vendor affected
+type JvnCpeXML struct {
+	Version string `xml:"version,attr"`
+	Vendor  string `xml:"vendor,attr"`
+	Product string `xml:"product,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// JvnCvssXML : Version is 2.0 or 3.0
+type JvnCvssXML struct {
+	Score    string `xml:"score,attr"`
+	Severity string `xml:"severity,attr"`
+	Vector   string `xml:"vector,attr"`
+	Version  string `xml:"version,attr"`
+}
+
+// JvnAdvisory : an advisory of JVN iPedia
+type JvnAdvisory struct {
+	ID               int64          `json:"-"`
+	JvnID            string         `json:"jvn_id" gorm:"type:varchar(255);index:idx_jvn_advisories_jvn_id"`
+	Title            string         `json:"title" gorm:"type:text"`
+	Summary          string         `json:"summary" gorm:"type:text"`
+	JvnLink          string         `json:"jvn_link" gorm:"type:text"`
+	Cvss2Score       float64        `json:"cvss2_score"`
+	Cvss2Severity    string         `json:"cvss2_severity" gorm:"type:varchar(255)"`
+	Cvss2Vector      string         `json:"cvss2_vector" gorm:"type:varchar(255)"`
+	Cvss3Score       float64        `json:"cvss3_score"`
+	Cvss3Severity    string         `json:"cvss3_severity" gorm:"type:varchar(255)"`
+	Cvss3Vector      string         `json:"cvss3_vector" gorm:"type:varchar(255)"`
+	PublishedDate    time.Time      `json:"published_date"`
+	LastModifiedDate time.Time      `json:"last_modified_date"`
+	CveIDs           []JvnCve       `json:"cve_ids"`
+	Vendors          []JvnVendor    `json:"vendors"`
+	References       []JvnReference `json:"references"`
+}
+
+// JvnCve : the CVE linked to the advisory
+type JvnCve struct {
+	ID            int64  `json:"-"`
+	JvnAdvisoryID int64  `json:"-" gorm:"index:idx_jvn_cves_jvn_advisory_id"`
+	CveID         string `json:"cve_id" gorm:"type:varchar(255);index:idx_jvn_cves_cveid"`
+}
+
+// JvnVendor : the vendor and the product affected, with CPE 2.2
+type JvnVendor struct {
+	ID            int64  `json:"-"`
+	JvnAdvisoryID int64  `json:"-" gorm:"index:idx_jvn_vendors_jvn_advisory_id"`
+	Vendor        string `json:"vendor" gorm:"type:varchar(255)"`
+	Product       string `json:"product" gorm:"type:varchar(255)"`
+	Cpe           string `json:"cpe" gorm:"type:varchar(255)"`
+}
+
+// JvnReference :
+type JvnReference struct {
+	ID            int64  `json:"-"`
+	JvnAdvisoryID int64  `json:"-" gorm:"index:idx_jvn_references_jvn_advisory_id"`
+	Source        string `json:"source" gorm:"type:varchar(255)"`
+	Title         string `json:"title" gorm:"type:text"`
+	URL           string `json:"url" gorm:"type:text"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// Statuses of the kernel versions in the CVE records of the Linux kernel CNA
+const (
+	// KernelStatusAffected : the versions are affected
+	KernelStatusAffected = "affected"
+	// KernelStatusUnaffected : the versions are not affected, e.g. before the vulnerability was introduced or after it was fixed
+	KernelStatusUnaffected = "unaffected"
+)
+
+// KernelCveJSON : a CVE record of the Linux kernel CNA in cve/published/ of https://git.kernel.org/pub/scm/linux/security/vulns.git, in CVE JSON 5 format
+type KernelCveJSON struct {
+	CveMetadata struct {
+		CveID         string `json:"cveId"`
+		State         string `json:"state"`
+		DatePublished string `json:"datePublished"`
+		DateUpdated   string `json:"dateUpdated"`
+	} `json:"cveMetadata"`
+	Containers struct {
+		Cna struct {
+			Title        string                  `json:"title"`
+			Descriptions []CveLangStringJSON     `json:"descriptions"`
+			Affected     []KernelCveAffectedJSON `json:"affected"`
+			References   []struct {
+				URL string `json:"url"`
+			} `json:"references"`
+		} `json:"cna"`
+	} `json:"containers"`
+}
+
+// KernelCveAffectedJSON : the kernel.org records have two entries, one of the git commits (versionType git) and one of the released versions
+type KernelCveAffectedJSON struct {
+	Product       string                 `json:"product"`
+	Vendor        string                 `json:"vendor"`
+	DefaultStatus string                 `json:"defaultStatus"`
+	Repo          string                 `json:"repo"`
+	ProgramFiles  []string               `json:"programFiles"`
+	Versions      []KernelCveVersionJSON `json:"versions"`
+}
+
+// KernelCveVersionJSON : e.g. {"version": "5.10.210", "lessThanOrEqual": "5.10.*", "status": "unaffected", "versionType": "semver"}
+type KernelCveVersionJSON struct {
+	Version         string `json:"version"`
+	LessThan        string `json:"lessThan"`
+	LessThanOrEqual string `json:"lessThanOrEqual"`
+	Status          string `json:"status"`
+	VersionType     string `json:"versionType"`
+}
+
+// KernelCVE : a CVE of the Linux kernel assigned by kernel.org
+type KernelCVE struct {
+	ID          int64  `json:"-"`
+	CveID       string `json:"cve_id" gorm:"type:varchar(255);index:idx_kernel_cves_cveid"`
+	Title       string `json:"title" gorm:"type:text"`
+	Description string `json:"description" gorm:"type:text"`
+	// DefaultStatus is the status of the versions not in Versions
+	DefaultStatus string             `json:"default_status" gorm:"type:varchar(255)"`
+	PublishedDate time.Time          `json:"published_date"`
+	UpdatedDate   time.Time          `json:"updated_date"`
+	Commits       []KernelCveCommit  `json:"commits"`
+	Versions      []KernelCveVersion `json:"versions"`
+}
+
+// KernelCveCommit : the commit introducing the vulnerability and the commit fixing it in a branch. Empty Fixed means not fixed
+type KernelCveCommit struct {
+	ID          int64  `json:"-"`
+	KernelCVEID int64  `json:"-" gorm:"index:idx_kernel_cve_commits_kernel_cve_id"`
+	Introduced  string `json:"introduced" gorm:"type:varchar(255)"`
+	Fixed       string `json:"fixed" gorm:"type:varchar(255)"`
+}
+
+// KernelCveVersion : the versions of Status from Version up to LessThan (exclusive) or LessThanOrEqual, which may have a wildcard (e.g. 5.10.*, *).
+// If both are empty, only Version is of Status.
+type KernelCveVersion struct {
+	ID              int64  `json:"-"`
+	KernelCVEID     int64  `json:"-" gorm:"index:idx_kernel_cve_versions_kernel_cve_id"`
+	Version         string `json:"version" gorm:"type:varchar(255)"`
+	LessThan        string `json:"less_than,omitempty" gorm:"type:varchar(255)"`
+	LessThanOrEqual string `json:"less_than_or_equal,omitempty" gorm:"type:varchar(255)"`
+	Status          string `json:"status" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// KEVCatalogJSON : https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json
+type KEVCatalogJSON struct {
+	Title           string         `json:"title"`
+	CatalogVersion  string         `json:"catalogVersion"`
+	DateReleased    string         `json:"dateReleased"`
+	Count           int            `json:"count"`
+	Vulnerabilities []KEVEntryJSON `json:"vulnerabilities"`
+}
+
+// KEVEntryJSON :
+type KEVEntryJSON struct {
+	CveID                      string `json:"cveID"`
+	VendorProject              string `json:"vendorProject"`
+	Product                    string `json:"product"`
+	VulnerabilityName          string `json:"vulnerabilityName"`
+	DateAdded                  string `json:"dateAdded"`
+	ShortDescription           string `json:"shortDescription"`
+	RequiredAction             string `json:"requiredAction"`
+	DueDate                    string `json:"dueDate"`
+	KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+	Notes                      string `json:"notes"`
+}
+
+// KEVEntry : CVE in CISA Known Exploited Vulnerabilities catalog
+type KEVEntry struct {
+	ID                int64     `json:"-"`
+	CveID             string    `json:"cve_id" gorm:"type:varchar(255);index:idx_kev_entries_cveid"`
+	VendorProject     string    `json:"vendor_project" gorm:"type:varchar(255)"`
+	Product           string    `json:"product" gorm:"type:varchar(255)"`
+	VulnerabilityName string    `json:"vulnerability_name" gorm:"type:text"`
+	DateAdded         time.Time `json:"date_added"`
+	ShortDescription  string    `json:"short_description" gorm:"type:text"`
+	RequiredAction    string    `json:"required_action" gorm:"type:text"`
+	DueDate           time.Time `json:"due_date"`
+	// KnownRansomwareCampaignUse is Known or Unknown
+	KnownRansomwareCampaignUse string `json:"known_ransomware_campaign_use" gorm:"type:varchar(255)"`
+	Notes                      string `json:"notes" gorm:"type:text"`
+}
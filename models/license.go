@@ -0,0 +1,52 @@
+package models
+
+// SourceLicense : the license of the data of a source and the attribution required to redistribute it
+type SourceLicense struct {
+	// License is the SPDX identifier or the name of the terms. It is empty if the upstream states none, see URL
+	License     string `json:"license,omitempty" gorm:"type:varchar(255)"`
+	Attribution string `json:"attribution,omitempty" gorm:"type:text"`
+	URL         string `json:"url,omitempty" gorm:"type:varchar(255)"`
+}
+
+// SourceLicenses are the licenses of the sources by the name of the fetch command, overridden by source-licenses in the config file
+var SourceLicenses = map[string]SourceLicense{
+	"alma":        {Attribution: "AlmaLinux OS Foundation", URL: "https://errata.almalinux.org/"},
+	"alpine":      {Attribution: "Alpine Linux secdb", URL: "https://secdb.alpinelinux.org/"},
+	"amazon":      {Attribution: "Amazon Linux Security Center", URL: "https://alas.aws.amazon.com/"},
+	"anolis":      {Attribution: "OpenAnolis", URL: "https://anas.openanolis.cn/"},
+	"apple":       {Attribution: "Apple security releases", URL: "https://support.apple.com/en-us/100100"},
+	"cisco":       {Attribution: "Cisco PSIRT openVuln API", URL: "https://developer.cisco.com/psirt/"},
+	"cnnvd":       {Attribution: "China National Vulnerability Database of Information Security (CNNVD)", URL: "https://www.cnnvd.org.cn"},
+	"cnvd":        {Attribution: "China National Vulnerability Database (CNVD)", URL: "https://www.cnvd.org.cn"},
+	"cvelist":     {License: "CVE Terms of Use", Attribution: "CVE® is a registered trademark of The MITRE Corporation", URL: "https://www.cve.org/Legal/TermsOfUse"},
+	"cwe":         {License: "CWE Terms of Use", Attribution: "CWE™ is a trademark of The MITRE Corporation", URL: "https://cwe.mitre.org/about/termsofuse.html"},
+	"debian":      {Attribution: "Debian Security Tracker", URL: "https://security-tracker.debian.org/tracker/"},
+	"distro-info": {Attribution: "Debian distro-info-data", URL: "https://salsa.debian.org/debian/distro-info-data"},
+	"epss":        {Attribution: "EPSS scores by FIRST", URL: "https://www.first.org/epss/"},
+	"exploit":     {License: "GPL-2.0 (Exploit-DB), BSD-3-Clause (Metasploit Framework)", Attribution: "Exploit-DB by OffSec, Metasploit Framework by Rapid7", URL: "https://www.exploit-db.com"},
+	"fortinet":    {Attribution: "Fortinet PSIRT", URL: "https://www.fortiguard.com/psirt"},
+	"ghsa":        {License: "CC-BY-4.0", Attribution: "GitHub Advisory Database", URL: "https://github.com/github/advisory-database"},
+	"govuln":      {License: "CC-BY-4.0", Attribution: "Go vulnerability database", URL: "https://vuln.go.dev"},
+	"jvn":         {Attribution: "JVN iPedia by IPA and JPCERT/CC", URL: "https://jvndb.jvn.jp"},
+	"kernel":      {Attribution: "Linux kernel CNA", URL: "https://git.kernel.org/pub/scm/linux/security/vulns.git"},
+	"kev":         {License: "CC0-1.0", Attribution: "CISA Known Exploited Vulnerabilities Catalog", URL: "https://www.cisa.gov/known-exploited-vulnerabilities-catalog"},
+	"mariner":     {Attribution: "Microsoft Azure Linux Vulnerability Data", URL: "https://github.com/microsoft/AzureLinuxVulnerabilityData"},
+	"microsoft":   {Attribution: "Microsoft Security Response Center", URL: "https://msrc.microsoft.com/update-guide"},
+	"npm":         {License: "CC-BY-4.0", Attribution: "GitHub Advisory Database", URL: "https://github.com/github/advisory-database"},
+	"nvd":         {License: "Public Domain", Attribution: "This product uses the NVD API but is not endorsed or certified by the NVD", URL: "https://nvd.nist.gov/developers/terms-of-use"},
+	"openeuler":   {Attribution: "openEuler security advisories", URL: "https://repo.openeuler.org/security/data/cvrf/"},
+	"oracle":      {Attribution: "Oracle Linux ELSA", URL: "https://linux.oracle.com/security/"},
+	"osv":         {Attribution: "OSV, the licenses vary by the ecosystem", URL: "https://osv.dev"},
+	"photon":      {Attribution: "VMware Photon OS", URL: "https://packages.vmware.com/photon/photon_cve_metadata/"},
+	"pypa":        {License: "CC-BY-4.0", Attribution: "PyPA advisory database", URL: "https://github.com/pypa/advisory-database"},
+	"redhat":      {License: "CC-BY-4.0", Attribution: "Red Hat, Inc.", URL: "https://access.redhat.com/security/data"},
+	"redhat-csaf": {License: "CC-BY-4.0", Attribution: "Red Hat, Inc.", URL: "https://access.redhat.com/security/data"},
+	"redhat-oval": {License: "CC-BY-4.0", Attribution: "Red Hat, Inc.", URL: "https://access.redhat.com/security/data"},
+	"redhatapi":   {License: "CC-BY-4.0", Attribution: "Red Hat, Inc.", URL: "https://access.redhat.com/security/data"},
+	"rocky":       {Attribution: "Rocky Enterprise Software Foundation", URL: "https://errata.rockylinux.org/"},
+	"rubysec":     {License: "Public Domain", Attribution: "ruby-advisory-db by RubySec", URL: "https://github.com/rubysec/ruby-advisory-db"},
+	"ubuntu":      {Attribution: "Ubuntu CVE Tracker by Canonical", URL: "https://ubuntu.com/security/cves"},
+	"ubuntu-usn":  {Attribution: "Ubuntu Security Notices by Canonical", URL: "https://ubuntu.com/security/notices"},
+	"vmware":      {Attribution: "VMware Security Advisories", URL: "https://www.vmware.com/security/advisories.html"},
+	"wolfi":       {Attribution: "Wolfi and Chainguard security advisories", URL: "https://packages.wolfi.dev/os/security.json"},
+}
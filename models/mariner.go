@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// MarinerOVALXML : OVAL of CBL-Mariner and Azure Linux in https://github.com/microsoft/AzureLinuxVulnerabilityData
+type MarinerOVALXML struct {
+	Definitions []MarinerDefinitionXML `xml:"definitions>definition"`
+	// Release is not in XML, it is set from URL
+	Release string `xml:"-"`
+}
+
+// MarinerDefinitionXML :
+type MarinerDefinitionXML struct {
+	ID       string             `xml:"id,attr"`
+	Metadata MarinerMetadataXML `xml:"metadata"`
+	Criteria MarinerCriteriaXML `xml:"criteria"`
+}
+
+// MarinerMetadataXML :
+type MarinerMetadataXML struct {
+	Title     string `xml:"title"`
+	Reference struct {
+		RefID  string `xml:"ref_id,attr"`
+		RefURL string `xml:"ref_url,attr"`
+		Source string `xml:"source,attr"`
+	} `xml:"reference"`
+	Patchable    string `xml:"patchable"`
+	AdvisoryDate string `xml:"advisory_date"`
+	AdvisoryID   string `xml:"advisory_id"`
+	Severity     string `xml:"severity"`
+	Description  string `xml:"description"`
+}
+
+// MarinerCriteriaXML :
+type MarinerCriteriaXML struct {
+	Criterions []struct {
+		Comment string `xml:"comment,attr"`
+	} `xml:"criterion"`
+	Criterias []MarinerCriteriaXML `xml:"criteria"`
+}
+
+// MarinerCVE :
+type MarinerCVE struct {
+	ID             int64            `json:"-"`
+	CveID          string           `json:"cve_id" gorm:"type:varchar(255);index:idx_mariner_cves_cveid"`
+	Packages       []MarinerPackage `json:"packages"`
+	KnownExploited bool             `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit        `json:"exploits,omitempty" gorm:"-"`
+}
+
+// MarinerPackage :
+type MarinerPackage struct {
+	ID           int64     `json:"-"`
+	MarinerCVEID int64     `json:"-" gorm:"index:idx_mariner_packages_mariner_cve_id"`
+	AdvisoryID   string    `json:"advisory_id" gorm:"type:varchar(255)"`
+	ReleaseName  string    `json:"release" gorm:"type:varchar(255);index:idx_mariner_packages_release_name"`
+	PackageName  string    `json:"package_name" gorm:"type:varchar(255);index:idx_mariner_packages_package_name"`
+	FixedVersion string    `json:"fixed_version" gorm:"type:varchar(255)"`
+	Severity     string    `json:"severity" gorm:"type:varchar(255)"`
+	IssuedDate   time.Time `json:"issued_date"`
+}
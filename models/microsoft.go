@@ -2,21 +2,31 @@ package models
 
 import (
 	"encoding/xml"
-	"strings"
+	"fmt"
 	"time"
 )
 
 // Updatelist :
 type Updatelist struct {
-	Value []struct {
-		ID                 string    `json:"ID"`
-		Alias              string    `json:"Alias"`
-		DocumentTitle      string    `json:"DocumentTitle"`
-		Severity           string    `json:"Severity"`
-		InitialReleaseDate time.Time `json:"InitialReleaseDate"`
-		CurrentReleaseDate time.Time `json:"CurrentReleaseDate"`
-		CvrfURL            string    `json:"CvrfUrl"`
-	} `json:"value"`
+	Value []MicrosoftCvrfUpdate `json:"value"`
+}
+
+// MicrosoftCvrfUpdate : a monthly CVRF document. ID is the month (e.g. 2023-Jan)
+type MicrosoftCvrfUpdate struct {
+	ID                 string    `json:"ID"`
+	Alias              string    `json:"Alias"`
+	DocumentTitle      string    `json:"DocumentTitle"`
+	Severity           string    `json:"Severity"`
+	InitialReleaseDate time.Time `json:"InitialReleaseDate"`
+	CurrentReleaseDate time.Time `json:"CurrentReleaseDate"`
+	CvrfURL            string    `json:"CvrfUrl"`
+}
+
+// MicrosoftCvrfDocument : the monthly CVRF document stored in the DB
+type MicrosoftCvrfDocument struct {
+	ID                 int64     `json:"-"`
+	CvrfID             string    `json:"cvrf_id" gorm:"type:varchar(255);index:idx_microsoft_cvrf_documents_cvrf_id"`
+	CurrentReleaseDate time.Time `json:"current_release_date"`
 }
 
 // MicrosoftXML :
@@ -167,6 +177,7 @@ type MicrosoftXML struct {
 			RestartRequired string   `xml:"RestartRequired"`
 			SubType         string   `xml:"SubType"`
 			Supercedence    string   `xml:"Supercedence"`
+			FixedBuild      string   `xml:"FixedBuild"`
 			URL             string   `xml:"URL"`
 		} `xml:"Remediations>Remediation"`
 		References []struct {
@@ -200,32 +211,30 @@ func (m *Mstime) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	if err := d.DecodeElement(&timeStr, &start); err != nil {
 		return err
 	}
-	format := "2006-01-02T15:04:05"
-	if strings.HasSuffix(timeStr, "Z") {
-		format = "2006-01-02T15:04:05Z"
-	}
-	t, err := time.Parse(format, timeStr)
-	if err != nil {
-		return err
+	// e.g. 2023-01-10T08:00:00Z, 2023-01-10T08:00:00-08:00, 2023-01-10T08:00:00 (taken as UTC)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, timeStr); err == nil {
+			*m = Mstime{t.UTC()}
+			return nil
+		}
 	}
-	*m = Mstime{t}
-	return nil
+	return fmt.Errorf("Failed to parse date: %s", timeStr)
 }
 
 // MicrosoftBulletinSearch :
 type MicrosoftBulletinSearch struct {
-	DatePosted        string `xlsx:"0"`
-	BulletinID        string `xlsx:"1"`
-	BulletinKB        string `xlsx:"2"`
-	Severity          string `xlsx:"3"`
-	Impact            string `xlsx:"4"`
-	Title             string `xlsx:"5"`
-	AffectedProduct   string `xlsx:"6"`
-	ComponentKB       string `xlsx:"7"`
-	AffectedComponent string `xlsx:"8"`
-	Supersedes        string `xlsx:"11"`
-	Reboot            string `xlsx:"12"`
-	CVEs              string `xlsx:"13"`
+	DatePosted        string
+	BulletinID        string
+	BulletinKB        string
+	Severity          string
+	Impact            string
+	Title             string
+	AffectedProduct   string
+	ComponentKB       string
+	AffectedComponent string
+	Supersedes        string
+	Reboot            string
+	CVEs              string
 }
 
 // MicrosoftCVE :
@@ -250,6 +259,10 @@ type MicrosoftCVE struct {
 	ScoreSets                []MicrosoftScoreSet      `json:"score_sets"`
 	PublishDate              time.Time                `json:"publish_date" gorm:"type:time"`
 	LastUpdateDate           time.Time                `json:"last_update_date" gorm:"type:time"`
+	KnownExploited           bool                     `json:"known_exploited" gorm:"-"`
+	Exploits                 []Exploit                `json:"exploits,omitempty" gorm:"-"`
+	CweDetails               []Cwe                    `json:"cwe_details,omitempty" gorm:"-"`
+	Epss                     *EpssScore               `json:"epss,omitempty" gorm:"-"`
 }
 
 // MicrosoftReference :
@@ -262,11 +275,29 @@ type MicrosoftReference struct {
 	Description string `json:"description" gorm:"type:text"`
 }
 
+// Update types of Microsoft KB
+const (
+	// MicrosoftUpdateTypeServicingStack : Servicing Stack Update (SSU). It is not a security fix by itself
+	MicrosoftUpdateTypeServicingStack = "Servicing Stack Update"
+	// MicrosoftUpdateTypeCumulative : Latest Cumulative Update (LCU) and Monthly Rollup
+	MicrosoftUpdateTypeCumulative = "Cumulative Update"
+	// MicrosoftUpdateTypeSecurityOnly : Security Only Update
+	MicrosoftUpdateTypeSecurityOnly = "Security Only"
+	// MicrosoftUpdateTypeSecurityUpdate : Security Update
+	MicrosoftUpdateTypeSecurityUpdate = "Security Update"
+)
+
 // MicrosoftKBID :
 type MicrosoftKBID struct {
 	ID             int64  `json:"-"`
 	MicrosoftCVEID int64  `json:"-" gorm:"index:idx_microsoft_kb_id_microsoft_cve_id"`
 	KBID           string `json:"kb_id" gorm:"type:varchar(255)"`
+	UpdateType     string `json:"update_type" gorm:"type:varchar(255)"`
+}
+
+// IsServicingStackUpdate returns whether the KB is a Servicing Stack Update
+func (k MicrosoftKBID) IsServicingStackUpdate() bool {
+	return k.UpdateType == MicrosoftUpdateTypeServicingStack
 }
 
 // MicrosoftProductStatus :
@@ -296,7 +327,10 @@ type MicrosoftRemediation struct {
 	RestartRequired string             `json:"restart_required" gorm:"type:varchar(255)"`
 	SubType         string             `json:"sub_type" gorm:"type:varchar(255)"`
 	Supercedence    string             `json:"supercedence" gorm:"type:text"`
+	FixedBuild      string             `json:"fixed_build" gorm:"type:varchar(255)"`
 	URL             string             `json:"url" gorm:"type:varchar(255)"`
+	KBArticleURL    string             `json:"kb_article_url" gorm:"type:varchar(255)"`
+	CatalogURL      string             `json:"catalog_url" gorm:"type:varchar(255)"`
 	AttrType        string             `json:"-" gorm:"type:varchar(255)"`
 }
 
@@ -311,11 +345,48 @@ type MicrosoftScoreSet struct {
 	Products           []MicrosoftProduct `json:"products" gorm:"foreignKey:MicrosoftCVEID;references:MicrosoftCVEID"`
 }
 
+// Architectures of Microsoft products
+const (
+	// MicrosoftArchX64 : x64-based Systems and 64-bit editions
+	MicrosoftArchX64 = "x64"
+	// MicrosoftArchX86 : 32-bit Systems and 32-bit editions
+	MicrosoftArchX86 = "x86"
+	// MicrosoftArchARM64 : ARM64-based Systems
+	MicrosoftArchARM64 = "arm64"
+	// MicrosoftArchItanium : Itanium-Based Systems of old Windows Server
+	MicrosoftArchItanium = "itanium"
+)
+
 // MicrosoftProduct :
+// Arch is parsed from the product name, and empty if the product has no architecture (e.g. Windows Server 2012 R2)
 type MicrosoftProduct struct {
 	ID             int64  `json:"-"`
 	MicrosoftCVEID int64  `json:"-" gorm:"index:idx_microsoft_product_microsoft_cve_id"`
 	Category       string `json:"-" gorm:"type:varchar(255)"`
 	ProductID      string `json:"product_id" gorm:"type:varchar(255)"`
 	ProductName    string `json:"product_name" gorm:"type:varchar(255)"`
+	Arch           string `json:"arch,omitempty" gorm:"type:varchar(255)"`
+}
+
+// MicrosoftProductMatch : a product of MSRC matching a product string. Score is from 0 to 1, and 1 is the exact match
+type MicrosoftProductMatch struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Score       float64 `json:"score"`
+}
+
+// MicrosoftKBSupersedence : KBID supersedes SupersededKBID on the product
+type MicrosoftKBSupersedence struct {
+	ID             int64  `json:"-"`
+	KBID           string `json:"kb_id" gorm:"type:varchar(255);index:idx_microsoft_kb_supersedences_kb_id"`
+	SupersededKBID string `json:"superseded_kb_id" gorm:"type:varchar(255);index:idx_microsoft_kb_supersedences_superseded_kb_id"`
+	ProductID      string `json:"product_id" gorm:"type:varchar(255)"`
+}
+
+// MicrosoftKBBuild : the OS build (e.g. 10.0.19044.2486) the KB ships in for the product
+type MicrosoftKBBuild struct {
+	ID         int64  `json:"-"`
+	KBID       string `json:"kb_id" gorm:"type:varchar(255);index:idx_microsoft_kb_builds_kb_id"`
+	ProductID  string `json:"product_id" gorm:"type:varchar(255);index:idx_microsoft_kb_builds_product_id"`
+	FixedBuild string `json:"fixed_build" gorm:"type:varchar(255)"`
 }
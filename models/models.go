@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // LatestSchemaVersion manages the Schema version used in the latest Gost.
 const LatestSchemaVersion = 2
@@ -16,3 +20,11 @@ type FetchMeta struct {
 func (f FetchMeta) OutDated() bool {
 	return f.SchemaVersion != LatestSchemaVersion
 }
+
+// FetchSource has the last time the source was fetched successfully (e.g. debian, nvd) and the license of the data
+type FetchSource struct {
+	ID            int64     `json:"-"`
+	Source        string    `json:"source" gorm:"type:varchar(255);index:idx_fetch_sources_source"`
+	FetchedAt     time.Time `json:"fetched_at"`
+	SourceLicense `gorm:"embedded"`
+}
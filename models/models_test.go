@@ -1,7 +1,9 @@
 package models
 
 import (
+	"encoding/xml"
 	"testing"
+	"time"
 )
 
 func Test_FetchMeta(t *testing.T) {
@@ -29,3 +31,24 @@ func Test_FetchMeta(t *testing.T) {
 		}
 	}
 }
+
+func Test_MstimeUnmarshalXML(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out time.Time
+	}{
+		{in: "<Date>2023-01-10T08:00:00Z</Date>", out: time.Date(2023, 1, 10, 8, 0, 0, 0, time.UTC)},
+		{in: "<Date>2023-01-10T08:00:00</Date>", out: time.Date(2023, 1, 10, 8, 0, 0, 0, time.UTC)},
+		{in: "<Date>2023-01-10T08:00:00-08:00</Date>", out: time.Date(2023, 1, 10, 16, 0, 0, 0, time.UTC)},
+	}
+
+	for i, tt := range tests {
+		var m Mstime
+		if err := xml.Unmarshal([]byte(tt.in), &m); err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err)
+		}
+		if !m.Equal(tt.out) || m.Location() != time.UTC {
+			t.Errorf("[%d] expected: %s\n  actual: %s\n", i, tt.out, m.Time)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package models
+
+import "time"
+
+// NvdAPIResponseJSON : a page of https://services.nvd.nist.gov/rest/json/cves/2.0
+type NvdAPIResponseJSON struct {
+	ResultsPerPage  int                    `json:"resultsPerPage"`
+	StartIndex      int                    `json:"startIndex"`
+	TotalResults    int                    `json:"totalResults"`
+	Vulnerabilities []NvdVulnerabilityJSON `json:"vulnerabilities"`
+}
+
+// NvdVulnerabilityJSON :
+type NvdVulnerabilityJSON struct {
+	Cve NvdCVEJSON `json:"cve"`
+}
+
+// NvdCVEJSON : CVE in NVD CVE API 2.0
+type NvdCVEJSON struct {
+	ID             string                 `json:"id"`
+	Published      string                 `json:"published"`
+	LastModified   string                 `json:"lastModified"`
+	VulnStatus     string                 `json:"vulnStatus"`
+	Descriptions   []NvdLangStringJSON    `json:"descriptions"`
+	Metrics        NvdMetricsJSON         `json:"metrics"`
+	Weaknesses     []NvdWeaknessJSON      `json:"weaknesses"`
+	Configurations []NvdConfigurationJSON `json:"configurations"`
+	References     []NvdReferenceJSON     `json:"references"`
+}
+
+// NvdLangStringJSON :
+type NvdLangStringJSON struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+// NvdMetricsJSON :
+type NvdMetricsJSON struct {
+	CvssMetricV31 []NvdCvssMetricJSON `json:"cvssMetricV31"`
+	CvssMetricV30 []NvdCvssMetricJSON `json:"cvssMetricV30"`
+	CvssMetricV2  []NvdCvssMetricJSON `json:"cvssMetricV2"`
+}
+
+// NvdCvssMetricJSON : baseSeverity is in cvssData for v3, and in the metric for v2
+type NvdCvssMetricJSON struct {
+	Source       string          `json:"source"`
+	Type         string          `json:"type"`
+	CvssData     NvdCvssDataJSON `json:"cvssData"`
+	BaseSeverity string          `json:"baseSeverity"`
+}
+
+// NvdCvssDataJSON :
+type NvdCvssDataJSON struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity"`
+}
+
+// NvdWeaknessJSON :
+type NvdWeaknessJSON struct {
+	Source      string              `json:"source"`
+	Type        string              `json:"type"`
+	Description []NvdLangStringJSON `json:"description"`
+}
+
+// NvdConfigurationJSON :
+type NvdConfigurationJSON struct {
+	Operator string        `json:"operator"`
+	Negate   bool          `json:"negate"`
+	Nodes    []NvdNodeJSON `json:"nodes"`
+}
+
+// NvdNodeJSON :
+type NvdNodeJSON struct {
+	Operator string            `json:"operator"`
+	Negate   bool              `json:"negate"`
+	CpeMatch []NvdCpeMatchJSON `json:"cpeMatch"`
+}
+
+// NvdCpeMatchJSON :
+type NvdCpeMatchJSON struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// NvdReferenceJSON :
+type NvdReferenceJSON struct {
+	URL    string   `json:"url"`
+	Source string   `json:"source"`
+	Tags   []string `json:"tags"`
+}
+
+// NvdCVE :
+type NvdCVE struct {
+	ID               int64          `json:"-"`
+	CveID            string         `json:"cve_id" gorm:"type:varchar(255);index:idx_nvd_cves_cveid"`
+	VulnStatus       string         `json:"vuln_status" gorm:"type:varchar(255)"`
+	Description      string         `json:"description" gorm:"type:text"`
+	PublishedDate    time.Time      `json:"published_date"`
+	LastModifiedDate time.Time      `json:"last_modified_date"`
+	Cvss             []NvdCvss      `json:"cvss"`
+	Cwes             []NvdCwe       `json:"cwes"`
+	Cpes             []NvdCpe       `json:"cpes"`
+	References       []NvdReference `json:"references"`
+	KnownExploited   bool           `json:"known_exploited" gorm:"-"`
+	Exploits         []Exploit      `json:"exploits,omitempty" gorm:"-"`
+	CweDetails       []Cwe          `json:"cwe_details,omitempty" gorm:"-"`
+}
+
+// NvdCvss :
+type NvdCvss struct {
+	ID       int64  `json:"-"`
+	NvdCVEID int64  `json:"-" gorm:"index:idx_nvd_cvsses_nvd_cve_id"`
+	Source   string `json:"source" gorm:"type:varchar(255)"`
+	// Type is Primary or Secondary
+	Type         string  `json:"type" gorm:"type:varchar(255)"`
+	Version      string  `json:"version" gorm:"type:varchar(255)"`
+	VectorString string  `json:"vector_string" gorm:"type:varchar(255)"`
+	BaseScore    float64 `json:"base_score"`
+	BaseSeverity string  `json:"base_severity" gorm:"type:varchar(255)"`
+}
+
+// NvdCwe :
+type NvdCwe struct {
+	ID       int64  `json:"-"`
+	NvdCVEID int64  `json:"-" gorm:"index:idx_nvd_cwes_nvd_cve_id"`
+	Source   string `json:"source" gorm:"type:varchar(255)"`
+	CweID    string `json:"cwe_id" gorm:"type:varchar(255)"`
+}
+
+// NvdCpe : cpeMatch flattened from configurations.
+// The match is in the node Node of the configuration Configuration, so the AND/OR tree can be rebuilt.
+type NvdCpe struct {
+	ID                    int64  `json:"-"`
+	NvdCVEID              int64  `json:"-" gorm:"index:idx_nvd_cpes_nvd_cve_id"`
+	Configuration         int    `json:"configuration"`
+	ConfigurationOperator string `json:"configuration_operator" gorm:"type:varchar(255)"`
+	Node                  int    `json:"node"`
+	NodeOperator          string `json:"node_operator" gorm:"type:varchar(255)"`
+	Negate                bool   `json:"negate"`
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria" gorm:"type:text"`
+	VersionStartIncluding string `json:"version_start_including" gorm:"type:varchar(255)"`
+	VersionStartExcluding string `json:"version_start_excluding" gorm:"type:varchar(255)"`
+	VersionEndIncluding   string `json:"version_end_including" gorm:"type:varchar(255)"`
+	VersionEndExcluding   string `json:"version_end_excluding" gorm:"type:varchar(255)"`
+}
+
+// NvdReference :
+type NvdReference struct {
+	ID       int64  `json:"-"`
+	NvdCVEID int64  `json:"-" gorm:"index:idx_nvd_references_nvd_cve_id"`
+	URL      string `json:"url" gorm:"type:text"`
+	Source   string `json:"source" gorm:"type:varchar(255)"`
+	Tags     string `json:"tags" gorm:"type:varchar(255)"`
+}
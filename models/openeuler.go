@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// OpenEulerCVRFXML : https://repo.openeuler.org/security/data/cvrf/
+type OpenEulerCVRFXML struct {
+	DocumentTitle    string                       `xml:"DocumentTitle"`
+	DocumentTracking OpenEulerDocumentTrackingXML `xml:"DocumentTracking"`
+	DocumentNotes    []OpenEulerNoteXML           `xml:"DocumentNotes>Note"`
+	ProductTree      []OpenEulerBranchXML         `xml:"ProductTree>Branch"`
+	Vulnerabilities  []OpenEulerVulnerabilityXML  `xml:"Vulnerability"`
+}
+
+// OpenEulerDocumentTrackingXML :
+type OpenEulerDocumentTrackingXML struct {
+	ID                 string `xml:"Identification>ID"`
+	InitialReleaseDate string `xml:"InitialReleaseDate"`
+}
+
+// OpenEulerNoteXML : e.g. Synopsis, Summary, Description and Severity
+type OpenEulerNoteXML struct {
+	Title string `xml:"Title,attr"`
+	Value string `xml:",chardata"`
+}
+
+// OpenEulerBranchXML : Branch Type is "Product Name" or "Package Arch"
+type OpenEulerBranchXML struct {
+	Type             string                        `xml:"Type,attr"`
+	Name             string                        `xml:"Name,attr"`
+	FullProductNames []OpenEulerFullProductNameXML `xml:"FullProductName"`
+}
+
+// OpenEulerFullProductNameXML : e.g. openssl-1.1.1f-3.oe1.aarch64.rpm, openEuler-20.03-LTS
+type OpenEulerFullProductNameXML struct {
+	ProductID string `xml:"ProductID,attr"`
+	CPE       string `xml:"CPE,attr"`
+	Value     string `xml:",chardata"`
+}
+
+// OpenEulerVulnerabilityXML :
+type OpenEulerVulnerabilityXML struct {
+	CVE             string               `xml:"CVE"`
+	Threats         []OpenEulerThreatXML `xml:"Threats>Threat"`
+	ProductStatuses []OpenEulerStatusXML `xml:"ProductStatuses>Status"`
+}
+
+// OpenEulerThreatXML : Description of Type "Impact" is the severity
+type OpenEulerThreatXML struct {
+	Type        string `xml:"Type,attr"`
+	Description string `xml:"Description"`
+}
+
+// OpenEulerStatusXML : ProductIDs are the products (e.g. openEuler-20.03-LTS) in Type "Fixed"
+type OpenEulerStatusXML struct {
+	Type       string   `xml:"Type,attr"`
+	ProductIDs []string `xml:"ProductID"`
+}
+
+// OpenEulerCVE :
+type OpenEulerCVE struct {
+	ID             int64               `json:"-"`
+	CveID          string              `json:"cve_id" gorm:"type:varchar(255);index:idx_open_euler_cves_cveid"`
+	Advisories     []OpenEulerAdvisory `json:"advisories"`
+	KnownExploited bool                `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit           `json:"exploits,omitempty" gorm:"-"`
+}
+
+// OpenEulerAdvisory :
+type OpenEulerAdvisory struct {
+	ID             int64              `json:"-"`
+	OpenEulerCVEID int64              `json:"-" gorm:"index:idx_open_euler_advisories_open_euler_cve_id"`
+	AdvisoryID     string             `json:"advisory_id" gorm:"type:varchar(255)"`
+	Title          string             `json:"title" gorm:"type:varchar(255)"`
+	Severity       string             `json:"severity" gorm:"type:varchar(255)"`
+	Description    string             `json:"description" gorm:"type:text"`
+	IssuedDate     time.Time          `json:"issued_date"`
+	Packages       []OpenEulerPackage `json:"packages"`
+}
+
+// OpenEulerPackage :
+type OpenEulerPackage struct {
+	ID                  int64  `json:"-"`
+	OpenEulerAdvisoryID int64  `json:"-" gorm:"index:idx_open_euler_packages_open_euler_advisory_id"`
+	ReleaseName         string `json:"release" gorm:"type:varchar(255);index:idx_open_euler_packages_release_name"`
+	PackageName         string `json:"package_name" gorm:"type:varchar(255);index:idx_open_euler_packages_package_name"`
+	FixedVersion        string `json:"fixed_version" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// OracleOVALJSON : ELSA in aquasecurity/vuln-list (oval/oracle)
+type OracleOVALJSON struct {
+	Title       string                `json:"Title"`
+	Description string                `json:"Description"`
+	Platform    []string              `json:"Platform"`
+	References  []OracleReferenceJSON `json:"References"`
+	Criteria    OracleCriteriaJSON    `json:"Criteria"`
+	Severity    string                `json:"Severity"`
+	Cves        []OracleCveJSON       `json:"Cves"`
+	IssueDate   OracleDateJSON        `json:"IssueDate"`
+}
+
+// OracleReferenceJSON :
+type OracleReferenceJSON struct {
+	Source string `json:"Source"`
+	URI    string `json:"URI"`
+	ID     string `json:"ID"`
+}
+
+// OracleCriteriaJSON :
+type OracleCriteriaJSON struct {
+	Operator   string                `json:"Operator"`
+	Criterias  []OracleCriteriaJSON  `json:"Criterias"`
+	Criterions []OracleCriterionJSON `json:"Criterions"`
+}
+
+// OracleCriterionJSON :
+type OracleCriterionJSON struct {
+	Comment string `json:"Comment"`
+}
+
+// OracleCveJSON :
+type OracleCveJSON struct {
+	Impact string `json:"Impact"`
+	Href   string `json:"Href"`
+	ID     string `json:"ID"`
+}
+
+// OracleDateJSON :
+type OracleDateJSON struct {
+	Date string `json:"Date"`
+}
+
+// OracleCVE :
+type OracleCVE struct {
+	ID             int64            `json:"-"`
+	CveID          string           `json:"cve_id" gorm:"type:varchar(255);index:idx_oracle_cves_cveid"`
+	Advisories     []OracleAdvisory `json:"advisories"`
+	KnownExploited bool             `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit        `json:"exploits,omitempty" gorm:"-"`
+}
+
+// OracleAdvisory :
+type OracleAdvisory struct {
+	ID          int64           `json:"-"`
+	OracleCVEID int64           `json:"-" gorm:"index:idx_oracle_advisories_oracle_cve_id"`
+	AdvisoryID  string          `json:"advisory_id" gorm:"type:varchar(255)"`
+	Title       string          `json:"title" gorm:"type:varchar(255)"`
+	Severity    string          `json:"severity" gorm:"type:varchar(255)"`
+	Description string          `json:"description" gorm:"type:text"`
+	IssuedDate  time.Time       `json:"issued_date"`
+	Packages    []OraclePackage `json:"packages"`
+}
+
+// OraclePackage :
+type OraclePackage struct {
+	ID               int64  `json:"-"`
+	OracleAdvisoryID int64  `json:"-" gorm:"index:idx_oracle_packages_oracle_advisory_id"`
+	ReleaseName      string `json:"release" gorm:"type:varchar(255);index:idx_oracle_packages_release_name"`
+	PackageName      string `json:"package_name" gorm:"type:varchar(255);index:idx_oracle_packages_package_name"`
+	FixedVersion     string `json:"fixed_version" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OsvJSON : a vulnerability in OSV schema (https://ossf.github.io/osv-schema/)
+type OsvJSON struct {
+	ID        string            `json:"id"`
+	Modified  string            `json:"modified"`
+	Published string            `json:"published"`
+	Withdrawn string            `json:"withdrawn"`
+	Aliases   []string          `json:"aliases"`
+	Summary   string            `json:"summary"`
+	Details   string            `json:"details"`
+	Affected  []OsvAffectedJSON `json:"affected"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+	DatabaseSpecific struct {
+		// Severity is given by GitHub Advisory Database (LOW, MODERATE, HIGH or CRITICAL)
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+
+	// Raw is the document as it is, since OSV has the fields specific to the ecosystems
+	Raw json.RawMessage `json:"-"`
+}
+
+// OsvAffectedJSON :
+type OsvAffectedJSON struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+		Purl      string `json:"purl"`
+	} `json:"package"`
+	Ranges   []OsvRangeJSON `json:"ranges"`
+	Versions []string       `json:"versions"`
+}
+
+// OsvRangeJSON : Type is SEMVER, ECOSYSTEM or GIT
+type OsvRangeJSON struct {
+	Type   string         `json:"type"`
+	Events []OsvEventJSON `json:"events"`
+}
+
+// OsvEventJSON : one of the fields is set
+type OsvEventJSON struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+// OsvEntry : a vulnerability of OSV. The document is kept in Raw
+type OsvEntry struct {
+	ID       int64        `json:"-"`
+	OsvID    string       `json:"osv_id" gorm:"type:varchar(255);index:idx_osv_entries_osv_id"`
+	Modified time.Time    `json:"modified"`
+	Raw      string       `json:"raw" gorm:"type:text"`
+	Packages []OsvPackage `json:"packages"`
+}
+
+// OsvPackage : the package affected by the vulnerability
+type OsvPackage struct {
+	ID          int64  `json:"-"`
+	OsvEntryID  int64  `json:"-" gorm:"index:idx_osv_packages_osv_entry_id"`
+	Ecosystem   string `json:"ecosystem" gorm:"type:varchar(255);index:idx_osv_packages_ecosystem_package_name"`
+	PackageName string `json:"package_name" gorm:"type:varchar(255);index:idx_osv_packages_ecosystem_package_name"`
+}
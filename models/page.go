@@ -0,0 +1,9 @@
+package models
+
+// CvePage : a page of the CVEs sorted by CVE-ID or date
+type CvePage struct {
+	Total  int         `json:"total"`
+	Offset int         `json:"offset"`
+	Limit  int         `json:"limit"`
+	CVEs   interface{} `json:"cves"`
+}
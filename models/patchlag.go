@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// PatchLagReport : distribution of days from the publication to the fix in a release of a distro
+type PatchLagReport struct {
+	Distro  string           `json:"distro"`
+	Release string           `json:"release"`
+	Summary PatchLagSummary  `json:"summary"`
+	Cves    []PatchLagRecord `json:"cves"`
+}
+
+// PatchLagSummary :
+type PatchLagSummary struct {
+	Count int     `json:"count"`
+	Min   int     `json:"min"`
+	Max   int     `json:"max"`
+	Mean  float64 `json:"mean"`
+	P50   int     `json:"p50"`
+	P90   int     `json:"p90"`
+	P99   int     `json:"p99"`
+}
+
+// PatchLagRecord : the first fix of a CVE in the release
+type PatchLagRecord struct {
+	CveID      string    `json:"cve_id"`
+	Advisory   string    `json:"advisory"`
+	PublicDate time.Time `json:"public_date"`
+	FixedDate  time.Time `json:"fixed_date"`
+	DaysToFix  int       `json:"days_to_fix"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// PhotonCVEJSON : https://packages.vmware.com/photon/photon_cve_metadata/cve_data_photon$RELEASE.json
+type PhotonCVEJSON struct {
+	CveID    string  `json:"cve_id"`
+	Pkg      string  `json:"pkg"`
+	CveScore float64 `json:"cve_score"`
+	AffVer   string  `json:"aff_ver"`
+	// ResVer is "NA" if not fixed yet
+	ResVer string `json:"res_ver"`
+	// Release is not in JSON, it is set from URL
+	Release string `json:"-"`
+}
+
+// PhotonCVE :
+type PhotonCVE struct {
+	ID             int64           `json:"-"`
+	CveID          string          `json:"cve_id" gorm:"type:varchar(255);index:idx_photon_cves_cveid"`
+	Packages       []PhotonPackage `json:"packages"`
+	KnownExploited bool            `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit       `json:"exploits,omitempty" gorm:"-"`
+}
+
+// PhotonPackage :
+type PhotonPackage struct {
+	ID           int64   `json:"-"`
+	PhotonCVEID  int64   `json:"-" gorm:"index:idx_photon_packages_photon_cve_id"`
+	ReleaseName  string  `json:"release" gorm:"type:varchar(255);index:idx_photon_packages_release_name"`
+	PackageName  string  `json:"package_name" gorm:"type:varchar(255);index:idx_photon_packages_package_name"`
+	FixedVersion string  `json:"fixed_version" gorm:"type:varchar(255)"`
+	CveScore     float64 `json:"cve_score"`
+}
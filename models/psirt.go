@@ -0,0 +1,151 @@
+package models
+
+import "time"
+
+// PSIRT vendors
+const (
+	// PsirtCisco : Cisco openVuln API
+	PsirtCisco = "cisco"
+	// PsirtVMware : VMware Security Advisories (VMSA)
+	PsirtVMware = "vmware"
+	// PsirtFortinet : Fortinet PSIRT
+	PsirtFortinet = "fortinet"
+)
+
+// CiscoAdvisoriesJSON : the response of https://apix.cisco.com/security/advisories/v2/all
+type CiscoAdvisoriesJSON struct {
+	Advisories []CiscoAdvisoryJSON `json:"advisories"`
+}
+
+// CiscoAdvisoryJSON : ProductNames are the names with the versions (e.g. Cisco IOS XE Software 17.3.1)
+type CiscoAdvisoryJSON struct {
+	AdvisoryID     string   `json:"advisoryId"`
+	AdvisoryTitle  string   `json:"advisoryTitle"`
+	Cves           []string `json:"cves"`
+	CvssBaseScore  string   `json:"cvssBaseScore"`
+	FirstPublished string   `json:"firstPublished"`
+	LastUpdated    string   `json:"lastUpdated"`
+	ProductNames   []string `json:"productNames"`
+	PublicationURL string   `json:"publicationUrl"`
+	// Sir is the Security Impact Rating: Critical, High, Medium, Low or Informational
+	Sir     string `json:"sir"`
+	Summary string `json:"summary"`
+}
+
+// PsirtCSAFJSON : a CSAF 2.0 document of VMSA
+type PsirtCSAFJSON struct {
+	Document struct {
+		Title    string `json:"title"`
+		Tracking struct {
+			ID                 string `json:"id"`
+			InitialReleaseDate string `json:"initial_release_date"`
+			CurrentReleaseDate string `json:"current_release_date"`
+		} `json:"tracking"`
+		AggregateSeverity struct {
+			Text string `json:"text"`
+		} `json:"aggregate_severity"`
+		References []struct {
+			Category string `json:"category"`
+			URL      string `json:"url"`
+		} `json:"references"`
+		Notes []struct {
+			Category string `json:"category"`
+			Text     string `json:"text"`
+		} `json:"notes"`
+	} `json:"document"`
+	ProductTree struct {
+		Branches []PsirtCSAFBranchJSON `json:"branches"`
+	} `json:"product_tree"`
+	Vulnerabilities []struct {
+		Cve           string `json:"cve"`
+		ProductStatus struct {
+			KnownAffected []string `json:"known_affected"`
+			Fixed         []string `json:"fixed"`
+		} `json:"product_status"`
+		Scores []struct {
+			CvssV3 struct {
+				BaseScore float64 `json:"baseScore"`
+			} `json:"cvss_v3"`
+		} `json:"scores"`
+	} `json:"vulnerabilities"`
+}
+
+// PsirtCSAFBranchJSON : Category is vendor, product_name or product_version
+type PsirtCSAFBranchJSON struct {
+	Category string                `json:"category"`
+	Name     string                `json:"name"`
+	Branches []PsirtCSAFBranchJSON `json:"branches"`
+	Product  struct {
+		ProductID string `json:"product_id"`
+	} `json:"product"`
+}
+
+// FortinetRSSXML : the RSS feed of Fortinet PSIRT (https://filestore.fortinet.com/fortiguard/rss/ir.xml)
+type FortinetRSSXML struct {
+	Items []struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		PubDate     string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+// FortinetCVRFXML : the CVRF 1.2 document of an advisory of Fortinet PSIRT
+type FortinetCVRFXML struct {
+	Title    string `xml:"DocumentTitle"`
+	Tracking struct {
+		ID                 string `xml:"Identification>ID"`
+		InitialReleaseDate string `xml:"InitialReleaseDate"`
+		CurrentReleaseDate string `xml:"CurrentReleaseDate"`
+	} `xml:"DocumentTracking"`
+	Branches        []FortinetCVRFBranchXML `xml:"ProductTree>Branch"`
+	Vulnerabilities []struct {
+		CVE      string `xml:"CVE"`
+		Statuses []struct {
+			Type       string   `xml:"Type,attr"`
+			ProductIDs []string `xml:"ProductID"`
+		} `xml:"ProductStatuses>Status"`
+		BaseScore string `xml:"CVSSScoreSets>ScoreSetV3>BaseScoreV3"`
+	} `xml:"Vulnerability"`
+}
+
+// FortinetCVRFBranchXML : Type is Vendor, Product Name or Product Version
+type FortinetCVRFBranchXML struct {
+	Type            string                  `xml:"Type,attr"`
+	Name            string                  `xml:"Name,attr"`
+	Branches        []FortinetCVRFBranchXML `xml:"Branch"`
+	FullProductName struct {
+		ProductID string `xml:"ProductID,attr"`
+	} `xml:"FullProductName"`
+}
+
+// PsirtAdvisory : an advisory of the PSIRT of a network appliance vendor (Cisco, VMware, Fortinet)
+type PsirtAdvisory struct {
+	ID               int64          `json:"-"`
+	Vendor           string         `json:"vendor" gorm:"type:varchar(255);index:idx_psirt_advisories_vendor"`
+	AdvisoryID       string         `json:"advisory_id" gorm:"type:varchar(255);index:idx_psirt_advisories_advisory_id"`
+	Title            string         `json:"title" gorm:"type:text"`
+	Summary          string         `json:"summary" gorm:"type:text"`
+	Severity         string         `json:"severity" gorm:"type:varchar(255)"`
+	CvssScore        float64        `json:"cvss_score"`
+	URL              string         `json:"url" gorm:"type:text"`
+	PublishedDate    time.Time      `json:"published_date"`
+	LastModifiedDate time.Time      `json:"last_modified_date"`
+	CveIDs           []PsirtCve     `json:"cve_ids"`
+	Products         []PsirtProduct `json:"products"`
+}
+
+// PsirtCve : the CVE linked to the advisory
+type PsirtCve struct {
+	ID              int64  `json:"-"`
+	PsirtAdvisoryID int64  `json:"-" gorm:"index:idx_psirt_cves_psirt_advisory_id"`
+	CveID           string `json:"cve_id" gorm:"type:varchar(255);index:idx_psirt_cves_cveid"`
+}
+
+// PsirtProduct : the product and the version affected. Version is empty if the advisory does not give the versions
+type PsirtProduct struct {
+	ID              int64  `json:"-"`
+	PsirtAdvisoryID int64  `json:"-" gorm:"index:idx_psirt_products_psirt_advisory_id"`
+	Product         string `json:"product" gorm:"type:varchar(255);index:idx_psirt_products_product"`
+	Version         string `json:"version" gorm:"type:varchar(255)"`
+}
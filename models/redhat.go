@@ -36,6 +36,7 @@ type RedhatCVEJSON struct {
 	AffectedRelease      []RedhatAffectedRelease
 	TempPackageState     interface{} `json:"package_state"` // package_state is array or object
 	PackageState         []RedhatPackageState
+	FixedPackages        []RedhatFixedPackage
 	Name                 string `json:"name"`
 	DocumentDistribution string `json:"document_distribution"`
 
@@ -80,11 +81,16 @@ type RedhatCVE struct {
 	Mitigation           string `gorm:"type:text"`
 	AffectedRelease      []RedhatAffectedRelease
 	PackageState         []RedhatPackageState
+	FixedPackages        []RedhatFixedPackage
 	Name                 string `gorm:"type:varchar(255);index:idx_redhat_cves_name"`
 	DocumentDistribution string `gorm:"type:text"`
 
-	Details    []RedhatDetail
-	References []RedhatReference
+	Details        []RedhatDetail
+	References     []RedhatReference
+	KnownExploited bool       `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit  `json:"exploits,omitempty" gorm:"-"`
+	CweDetails     []Cwe      `json:"cwe_details,omitempty" gorm:"-"`
+	Epss           *EpssScore `json:"epss,omitempty" gorm:"-"`
 }
 
 // GetDetail returns details
@@ -131,7 +137,7 @@ type RedhatBugzilla struct {
 	RedhatCVEID int64  `json:"-" gorm:"index:idx_redhat_bugzillas_redhat_cve_id"`
 	Description string `json:"description" gorm:"type:text"`
 
-	BugzillaID string `json:"id" gorm:"type:varchar(255)"`
+	BugzillaID string `json:"id" gorm:"type:varchar(255);index:idx_redhat_bugzillas_bugzilla_id"`
 	URL        string `json:"url" gorm:"type:varchar(255)"`
 }
 
@@ -164,6 +170,20 @@ type RedhatAffectedRelease struct {
 	Cpe         string `json:"cpe" gorm:"type:varchar(255)"`
 }
 
+// RedhatFixedPackage : a package fixed by the advisory in the product of Cpe, parsed from the NEVRA (e.g. openssl-libs-1:3.0.7-18.el9_2.x86_64).
+// Security Data API gives the source package without Arch (e.g. openssl-1:3.0.7-18.el9_2), and CSAF gives the binary and the source (src) packages.
+type RedhatFixedPackage struct {
+	ID          int64  `json:"-"`
+	RedhatCVEID int64  `json:"-" gorm:"index:idx_redhat_fixed_packages_redhat_cve_id"`
+	Advisory    string `json:"advisory" gorm:"type:varchar(255)"`
+	Cpe         string `json:"cpe" gorm:"type:varchar(255)"`
+	Name        string `json:"name" gorm:"type:varchar(255);index:idx_redhat_fixed_packages_name"`
+	Epoch       string `json:"epoch" gorm:"type:varchar(255)"`
+	Version     string `json:"version" gorm:"type:varchar(255)"`
+	Release     string `json:"release" gorm:"type:varchar(255)"`
+	Arch        string `json:"arch,omitempty" gorm:"type:varchar(255)"`
+}
+
 // RedhatPackageState :
 type RedhatPackageState struct {
 	ID          int64  `json:"-"`
@@ -173,3 +193,152 @@ type RedhatPackageState struct {
 	PackageName string `json:"package_name" gorm:"type:varchar(255);index:idx_redhat_package_states_package_name"`
 	Cpe         string `json:"cpe" gorm:"type:varchar(255);index:idx_redhat_package_states_cpe"`
 }
+
+// RedhatCSAFJSON : a CSAF document of Red Hat (a VEX per CVE, or an advisory per RHSA) in https://security.access.redhat.com/data/csaf/v2/
+type RedhatCSAFJSON struct {
+	Document struct {
+		Category          string `json:"category"`
+		AggregateSeverity struct {
+			Text string `json:"text"`
+		} `json:"aggregate_severity"`
+		Distribution struct {
+			Text string `json:"text"`
+		} `json:"distribution"`
+		Tracking struct {
+			ID string `json:"id"`
+		} `json:"tracking"`
+	} `json:"document"`
+	ProductTree     RedhatCSAFProductTreeJSON     `json:"product_tree"`
+	Vulnerabilities []RedhatCSAFVulnerabilityJSON `json:"vulnerabilities"`
+}
+
+// RedhatCSAFProductTreeJSON :
+type RedhatCSAFProductTreeJSON struct {
+	Branches      []RedhatCSAFBranchJSON `json:"branches"`
+	Relationships []struct {
+		Category        string `json:"category"`
+		FullProductName struct {
+			Name      string `json:"name"`
+			ProductID string `json:"product_id"`
+		} `json:"full_product_name"`
+		ProductReference          string `json:"product_reference"`
+		RelatesToProductReference string `json:"relates_to_product_reference"`
+	} `json:"relationships"`
+}
+
+// RedhatCSAFBranchJSON : category is vendor, product_family, product_name, product_version or architecture
+type RedhatCSAFBranchJSON struct {
+	Category string                 `json:"category"`
+	Name     string                 `json:"name"`
+	Branches []RedhatCSAFBranchJSON `json:"branches"`
+	Product  *struct {
+		Name                        string `json:"name"`
+		ProductID                   string `json:"product_id"`
+		ProductIdentificationHelper struct {
+			Cpe  string `json:"cpe"`
+			Purl string `json:"purl"`
+		} `json:"product_identification_helper"`
+	} `json:"product"`
+}
+
+// RedhatCSAFVulnerabilityJSON :
+type RedhatCSAFVulnerabilityJSON struct {
+	Cve   string `json:"cve"`
+	Title string `json:"title"`
+	Cwe   struct {
+		ID string `json:"id"`
+	} `json:"cwe"`
+	ReleaseDate string `json:"release_date"`
+	IDs         []struct {
+		SystemName string `json:"system_name"`
+		Text       string `json:"text"`
+	} `json:"ids"`
+	Notes []struct {
+		Category string `json:"category"`
+		Title    string `json:"title"`
+		Text     string `json:"text"`
+	} `json:"notes"`
+	ProductStatus struct {
+		Fixed              []string `json:"fixed"`
+		KnownAffected      []string `json:"known_affected"`
+		KnownNotAffected   []string `json:"known_not_affected"`
+		UnderInvestigation []string `json:"under_investigation"`
+	} `json:"product_status"`
+	References []struct {
+		Category string `json:"category"`
+		URL      string `json:"url"`
+	} `json:"references"`
+	Remediations []struct {
+		// vendor_fix, workaround, mitigation, no_fix_planned, none_available
+		Category   string   `json:"category"`
+		Date       string   `json:"date"`
+		Details    string   `json:"details"`
+		ProductIDs []string `json:"product_ids"`
+		URL        string   `json:"url"`
+	} `json:"remediations"`
+	Scores []struct {
+		CvssV3 *struct {
+			BaseScore    float64 `json:"baseScore"`
+			VectorString string  `json:"vectorString"`
+		} `json:"cvss_v3"`
+	} `json:"scores"`
+	Threats []struct {
+		Category string `json:"category"`
+		Details  string `json:"details"`
+	} `json:"threats"`
+}
+
+// RedhatOVALXML : OVAL v2 of a RHEL major release including the unpatched CVEs in https://access.redhat.com/security/data/oval/v2/
+type RedhatOVALXML struct {
+	Definitions []RedhatOVALDefinitionXML `xml:"definitions>definition"`
+	// Major is not in XML, it is set from URL (e.g. 8)
+	Major string `xml:"-"`
+}
+
+// RedhatOVALDefinitionXML : class is patch (RHSA) or vulnerability (unpatched CVE)
+type RedhatOVALDefinitionXML struct {
+	ID       string `xml:"id,attr"`
+	Class    string `xml:"class,attr"`
+	Metadata struct {
+		Title      string `xml:"title"`
+		References []struct {
+			RefID  string `xml:"ref_id,attr"`
+			Source string `xml:"source,attr"`
+		} `xml:"reference"`
+		Advisory struct {
+			Cves     []string `xml:"cve"`
+			Affected struct {
+				Resolutions []struct {
+					State string `xml:"state,attr"`
+					// Components are the packages (e.g. openssl) or the packages of the module streams (e.g. nodejs:14/nodejs)
+					Components []string `xml:"component"`
+				} `xml:"resolution"`
+			} `xml:"affected"`
+		} `xml:"advisory"`
+	} `xml:"metadata"`
+	Criteria RedhatOVALCriteriaXML `xml:"criteria"`
+}
+
+// RedhatOVALCriteriaXML :
+type RedhatOVALCriteriaXML struct {
+	Criterions []struct {
+		Comment string `xml:"comment,attr"`
+	} `xml:"criterion"`
+	Criterias []RedhatOVALCriteriaXML `xml:"criteria"`
+}
+
+// RedhatModuleStateFixed is FixState of RedhatModuleState fixed by an RHSA
+const RedhatModuleStateFixed = "Fixed"
+
+// RedhatModuleState : the fix state of a package of an AppStream module stream (e.g. nodejs:14) in Red Hat OVAL v2
+type RedhatModuleState struct {
+	ID          int64  `json:"-"`
+	CveID       string `json:"cve_id" gorm:"type:varchar(255);index:idx_redhat_module_states_cve_id"`
+	Major       string `json:"major" gorm:"type:varchar(255)"`
+	Module      string `json:"module" gorm:"type:varchar(255);index:idx_redhat_module_states_module"`
+	PackageName string `json:"package_name" gorm:"type:varchar(255);index:idx_redhat_module_states_package_name"`
+	// FixState is Fixed, or the state of the unpatched CVE (e.g. Affected, Will not fix, Fix deferred, Out of support scope)
+	FixState     string `json:"fix_state" gorm:"type:varchar(255)"`
+	FixedVersion string `json:"fixed_version" gorm:"type:varchar(255)"`
+	Advisory     string `json:"advisory" gorm:"type:varchar(255)"`
+}
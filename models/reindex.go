@@ -0,0 +1,14 @@
+package models
+
+// ReindexReport : the indexes of Redis rebuilt from the CVEs stored
+type ReindexReport struct {
+	// CVEs is the number of CVE#$CVEID scanned
+	CVEs int `json:"cves"`
+	// Sources is the number of the documents indexed by the field of CVE#$CVEID (e.g. RedHat, Debian)
+	Sources map[string]int `json:"sources"`
+	// Keys is the number of the index keys, and Members is the number of the members added to them
+	Keys    int `json:"keys"`
+	Members int `json:"members"`
+	// Failed is the number of the documents which cannot be decoded
+	Failed int `json:"failed"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// RockyAdvisoriesJSON : a page of https://errata.rockylinux.org/api/v2/advisories
+type RockyAdvisoriesJSON struct {
+	Advisories []RockyAdvisoryJSON `json:"advisories"`
+	Total      string              `json:"total"`
+	Page       int                 `json:"page"`
+	Size       int                 `json:"size"`
+}
+
+// RockyAdvisoryJSON : RLSA in Rocky Linux Apollo
+type RockyAdvisoryJSON struct {
+	Type             string                  `json:"type"`
+	Name             string                  `json:"name"`
+	Synopsis         string                  `json:"synopsis"`
+	Severity         string                  `json:"severity"`
+	Topic            string                  `json:"topic"`
+	Description      string                  `json:"description"`
+	AffectedProducts []string                `json:"affectedProducts"`
+	Cves             []RockyCveJSON          `json:"cves"`
+	References       []string                `json:"references"`
+	PublishedAt      string                  `json:"publishedAt"`
+	Rpms             map[string]RockyRpmJSON `json:"rpms"`
+}
+
+// RockyCveJSON :
+type RockyCveJSON struct {
+	Name       string `json:"name"`
+	SourceBy   string `json:"sourceBy"`
+	SourceLink string `json:"sourceLink"`
+}
+
+// RockyRpmJSON : RPMs per product (e.g. "Rocky Linux 8")
+type RockyRpmJSON struct {
+	Nvras []string `json:"nvras"`
+}
+
+// RockyCVE :
+type RockyCVE struct {
+	ID             int64           `json:"-"`
+	CveID          string          `json:"cve_id" gorm:"type:varchar(255);index:idx_rocky_cves_cveid"`
+	Advisories     []RockyAdvisory `json:"advisories"`
+	KnownExploited bool            `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit       `json:"exploits,omitempty" gorm:"-"`
+}
+
+// RockyAdvisory :
+type RockyAdvisory struct {
+	ID          int64          `json:"-"`
+	RockyCVEID  int64          `json:"-" gorm:"index:idx_rocky_advisories_rocky_cve_id"`
+	AdvisoryID  string         `json:"advisory_id" gorm:"type:varchar(255)"`
+	ReleaseName string         `json:"release" gorm:"type:varchar(255);index:idx_rocky_advisories_release_name"`
+	Title       string         `json:"title" gorm:"type:varchar(255)"`
+	Severity    string         `json:"severity" gorm:"type:varchar(255)"`
+	Description string         `json:"description" gorm:"type:text"`
+	IssuedDate  time.Time      `json:"issued_date"`
+	Packages    []RockyPackage `json:"packages"`
+}
+
+// RockyPackage :
+type RockyPackage struct {
+	ID              int64  `json:"-"`
+	RockyAdvisoryID int64  `json:"-" gorm:"index:idx_rocky_packages_rocky_advisory_id"`
+	PackageName     string `json:"package_name" gorm:"type:varchar(255);index:idx_rocky_packages_package_name"`
+	FixedVersion    string `json:"fixed_version" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// SelfTestReport : result of the query of a known CVE from each source on server start
+type SelfTestReport struct {
+	OK      bool             `json:"ok"`
+	Sources []SelfTestSource `json:"sources"`
+}
+
+// SelfTestSource :
+type SelfTestSource struct {
+	Source string `json:"source"`
+	// CveID is the CVE queried. It is empty if the source is not loaded.
+	CveID string `json:"cve_id,omitempty"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
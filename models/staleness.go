@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// StalenessReport : the ages of the data of the sources fetched, compared with MaxAge
+type StalenessReport struct {
+	OK      bool              `json:"ok"`
+	MaxAge  string            `json:"max_age,omitempty"`
+	Sources []StalenessSource `json:"sources"`
+}
+
+// StalenessSource : FetchedAt is nil if the source has never been fetched
+type StalenessSource struct {
+	Source    string     `json:"source"`
+	FetchedAt *time.Time `json:"fetched_at,omitempty"`
+	Age       string     `json:"age,omitempty"`
+	Stale     bool       `json:"stale"`
+}
+
+// HealthReport : the result of GET /healthz and /readyz
+type HealthReport struct {
+	OK bool     `json:"ok"`
+	DB DBHealth `json:"db"`
+	// Staleness is nil if the DB did not respond
+	Staleness *StalenessReport `json:"staleness,omitempty"`
+	// SelfTest and CircuitBreaker are reported by /readyz
+	SelfTest       *bool  `json:"self_test,omitempty"`
+	CircuitBreaker string `json:"circuit_breaker,omitempty"`
+}
+
+// DBHealth : the result of the query to the DB
+type DBHealth struct {
+	OK      bool   `json:"ok"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Timeline event types
+const (
+	// TimelineEventPublic : the CVE became public according to the source
+	TimelineEventPublic = "public"
+	// TimelineEventAcknowledged : the source published its own record of the CVE
+	TimelineEventAcknowledged = "acknowledged"
+	// TimelineEventFixed : the fix was released for the release of the source
+	TimelineEventFixed = "fixed"
+	// TimelineEventExploited : the CVE was added to CISA KEV catalog
+	TimelineEventExploited = "exploited"
+)
+
+// Timeline : events of a CVE across the sources, ordered by date
+type Timeline struct {
+	CveID string `json:"cve_id"`
+	// PublicDate is the earliest public date among the sources
+	PublicDate *time.Time      `json:"public_date,omitempty"`
+	Events     []TimelineEvent `json:"events"`
+	// Fixes has the first fix per source and release
+	Fixes []TimelineFix `json:"fixes"`
+}
+
+// TimelineEvent :
+type TimelineEvent struct {
+	Date        time.Time `json:"date"`
+	Source      string    `json:"source"`
+	Event       string    `json:"event"`
+	Release     string    `json:"release,omitempty"`
+	Advisory    string    `json:"advisory,omitempty"`
+	PackageName string    `json:"package_name,omitempty"`
+}
+
+// TimelineFix : the first fix released for the release of the source
+type TimelineFix struct {
+	Source   string    `json:"source"`
+	Release  string    `json:"release"`
+	Advisory string    `json:"advisory"`
+	Date     time.Time `json:"date"`
+	// DaysToFix is the number of days from PublicDate. It is nil if PublicDate is unknown
+	DaysToFix *int `json:"days_to_fix,omitempty"`
+}
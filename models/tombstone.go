@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Tombstone : a CVE removed from a source by fetch --remove-missing, so that the downstream caches can delete it too
+type Tombstone struct {
+	Source    string    `json:"source"`
+	CveID     string    `json:"cve_id"`
+	RemovedAt time.Time `json:"removed_at"`
+}
@@ -43,7 +43,12 @@ type UbuntuCVE struct {
 	DiscoveredBy      string            `json:"discovered_by" gorm:"type:varchar(255)"`
 	AssignedTo        string            `json:"assigned_to" gorm:"type:varchar(255)"`
 	Patches           []UbuntuPatch     `json:"patches"`
+	SnapPatches       []UbuntuSnapPatch `json:"snap_patches"`
 	Upstreams         []UbuntuUpstream  `json:"upstreams"`
+	USNs              []UbuntuUSN       `json:"usns" gorm:"foreignKey:CveID;references:Candidate"`
+	KnownExploited    bool              `json:"known_exploited" gorm:"-"`
+	Exploits          []Exploit         `json:"exploits,omitempty" gorm:"-"`
+	Epss              *EpssScore        `json:"epss,omitempty" gorm:"-"`
 }
 
 // UbuntuReference :
@@ -60,11 +65,21 @@ type UbuntuNote struct {
 	Note        string `json:"note" gorm:"type:text"`
 }
 
+// Bug trackers referenced from Ubuntu CVE Tracker
+const (
+	// UbuntuBugTrackerLaunchpad : https://launchpad.net/bugs/$ID
+	UbuntuBugTrackerLaunchpad = "launchpad"
+	// UbuntuBugTrackerDebian : https://bugs.debian.org/$ID
+	UbuntuBugTrackerDebian = "debian"
+)
+
 // UbuntuBug :
 type UbuntuBug struct {
 	ID          int64  `json:"-"`
 	UbuntuCVEID int64  `json:"-" gorm:"index:idx_ubuntu_bug_ubuntu_cve_id"`
 	Bug         string `json:"bug" gorm:"type:text"`
+	Tracker     string `json:"tracker" gorm:"type:varchar(255)"`
+	BugID       string `json:"bug_id" gorm:"type:varchar(255);index:idx_ubuntu_bug_bug_id"`
 }
 
 // UbuntuPatch :
@@ -82,6 +97,17 @@ type UbuntuReleasePatch struct {
 	ReleaseName   string `json:"release_name" gorm:"type:varchar(255);index:idx_ubuntu_release_patch_release_name"`
 	Status        string `json:"status" gorm:"type:varchar(255);index:idx_ubuntu_release_patch_status"`
 	Note          string `json:"note" gorm:"type:varchar(255)"`
+	// Channel is the channel of the extended support (e.g. esm-infra, esm-apps), or empty for the standard support
+	Channel string `json:"channel" gorm:"type:varchar(255)"`
+}
+
+// UbuntuSnapPatch : the fix state of the snap package (the release "snap" in the tracker), which is not of the releases of deb
+type UbuntuSnapPatch struct {
+	ID          int64  `json:"-"`
+	UbuntuCVEID int64  `json:"-" gorm:"index:idx_ubuntu_snap_patch_ubuntu_cve_id"`
+	SnapName    string `json:"snap_name" gorm:"type:varchar(255);index:idx_ubuntu_snap_patch_snap_name"`
+	Status      string `json:"status" gorm:"type:varchar(255)"`
+	Note        string `json:"note" gorm:"type:varchar(255)"`
 }
 
 // UbuntuUpstream :
@@ -98,3 +124,112 @@ type UbuntuUpstreamLink struct {
 	UbuntuUpstreamID int64  `json:"-" gorm:"index:idx_ubuntu_upstream_link_ubuntu_upstream_id"`
 	Link             string `json:"link" gorm:"type:text"`
 }
+
+// UbuntuUSNJSON : Ubuntu Security Notice in https://usn.ubuntu.com/usn-db/database.json
+type UbuntuUSNJSON struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Summary     string  `json:"summary"`
+	Description string  `json:"description"`
+	Timestamp   float64 `json:"timestamp"`
+	// Cves has CVE-IDs and the URLs of Launchpad bugs
+	Cves []string `json:"cves"`
+	// Releases has the fixed packages per codename (e.g. focal)
+	Releases map[string]interface{} `json:"releases"`
+}
+
+// UbuntuUSN : USN cross-linked to UbuntuCVE by CVE-ID.
+// It is not deleted when Ubuntu CVEs are fetched again, so the link is kept.
+type UbuntuUSN struct {
+	ID            int64     `json:"-"`
+	CveID         string    `json:"-" gorm:"type:varchar(255);index:idx_ubuntu_usn_cve_id"`
+	USNID         string    `json:"usn_id" gorm:"type:varchar(255);index:idx_ubuntu_usn_usn_id"`
+	ReleaseName   string    `json:"release_name" gorm:"type:varchar(255)"`
+	Title         string    `json:"title" gorm:"type:varchar(255)"`
+	PublishedDate time.Time `json:"published_date"`
+}
+
+// UbuntuOVALXML : OVAL of the CVEs of an Ubuntu release in https://security-metadata.canonical.com/oval/
+type UbuntuOVALXML struct {
+	Definitions []UbuntuOVALDefinitionXML `xml:"definitions>definition"`
+	// Release is not in XML, it is set from URL (e.g. jammy)
+	Release string `xml:"-"`
+}
+
+// UbuntuOVALDefinitionXML :
+type UbuntuOVALDefinitionXML struct {
+	ID       string `xml:"id,attr"`
+	Class    string `xml:"class,attr"`
+	Metadata struct {
+		Title      string `xml:"title"`
+		References []struct {
+			RefID  string `xml:"ref_id,attr"`
+			Source string `xml:"source,attr"`
+		} `xml:"reference"`
+	} `xml:"metadata"`
+	Criteria UbuntuOVALCriteriaXML `xml:"criteria"`
+}
+
+// UbuntuOVALCriteriaXML :
+type UbuntuOVALCriteriaXML struct {
+	Criterions []struct {
+		Comment string `xml:"comment,attr"`
+	} `xml:"criterion"`
+	Criterias []UbuntuOVALCriteriaXML `xml:"criteria"`
+}
+
+// Merge policies of Ubuntu OVAL into Ubuntu CVE Tracker
+const (
+	// UbuntuOVALMergeTracker : the tracker wins on the disagreements, and OVAL only fills the packages missing in the tracker
+	UbuntuOVALMergeTracker = "tracker"
+	// UbuntuOVALMergeOVAL : OVAL wins on the disagreements
+	UbuntuOVALMergeOVAL = "oval"
+	// UbuntuOVALMergeFlag : the tracker is kept as is, and the disagreements are only reported
+	UbuntuOVALMergeFlag = "flag"
+)
+
+// Kinds of UbuntuOVALDisagreement
+const (
+	// UbuntuOVALMissingInTracker : OVAL has the package of the CVE, but the tracker has no status of it in the release
+	UbuntuOVALMissingInTracker = "missing_in_tracker"
+	// UbuntuOVALStatusMismatch : one is released (fixed), and the other is not, or the tracker says not affected
+	UbuntuOVALStatusMismatch = "status_mismatch"
+	// UbuntuOVALVersionMismatch : both are released in the different versions
+	UbuntuOVALVersionMismatch = "version_mismatch"
+)
+
+// UbuntuOVALCrossCheckReport : disagreements of the fix status between Ubuntu CVE Tracker and Ubuntu OVAL
+type UbuntuOVALCrossCheckReport struct {
+	// Policy is the merge policy applied, or empty if only cross-checked
+	Policy        string                        `json:"policy,omitempty"`
+	Releases      []UbuntuOVALCrossCheckRelease `json:"releases"`
+	Disagreements []UbuntuOVALDisagreement      `json:"disagreements"`
+}
+
+// UbuntuOVALCrossCheckRelease : the summary of a release
+type UbuntuOVALCrossCheckRelease struct {
+	Release string `json:"release"`
+	// Checked is the number of the pairs of CVE and package in OVAL
+	Checked          int `json:"checked"`
+	MissingInTracker int `json:"missing_in_tracker"`
+	StatusMismatch   int `json:"status_mismatch"`
+	VersionMismatch  int `json:"version_mismatch"`
+	// Merged is the number of the pairs the tracker took from OVAL
+	Merged int `json:"merged"`
+}
+
+// UbuntuOVALDisagreement :
+type UbuntuOVALDisagreement struct {
+	Release     string `json:"release"`
+	CveID       string `json:"cve_id"`
+	PackageName string `json:"package_name"`
+	Kind        string `json:"kind"`
+	// TrackerStatus is the status of the tracker (e.g. released, needed, not-affected), or empty if missing
+	TrackerStatus string `json:"tracker_status"`
+	TrackerNote   string `json:"tracker_note"`
+	// OVALStatus is released, needed, needs-triage, pending or deferred
+	OVALStatus       string `json:"oval_status"`
+	OVALFixedVersion string `json:"oval_fixed_version"`
+	// Merged is true if the tracker took the status of OVAL
+	Merged bool `json:"merged"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Fix statuses of VulnAffected
+const (
+	// VulnStatusFixed : the fix is released
+	VulnStatusFixed = "fixed"
+	// VulnStatusAffected : the package is affected and the fix is not released yet
+	VulnStatusAffected = "affected"
+	// VulnStatusWillNotFix : the package is affected and the source will not fix it
+	VulnStatusWillNotFix = "will_not_fix"
+	// VulnStatusNotAffected : the package is not affected
+	VulnStatusNotAffected = "not_affected"
+	// VulnStatusUnknown : the source has not determined the status yet
+	VulnStatusUnknown = "unknown"
+)
+
+// VulnInfo : the source-agnostic view of a CVE aggregated from the records of RedHat, Debian, Ubuntu and Microsoft
+type VulnInfo struct {
+	ID string `json:"id"`
+	// Sources are the sources having the CVE
+	Sources     []string `json:"sources"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	// Severity is the highest severity among the sources normalized to critical, high, medium, low or negligible
+	Severity string `json:"severity,omitempty"`
+	// SourceSeverities are the severities as they are by source
+	SourceSeverities map[string]string `json:"source_severities,omitempty"`
+	Cvss             []VulnCvss        `json:"cvss"`
+	// PublishedDate is the earliest public date among the sources
+	PublishedDate  *time.Time     `json:"published_date,omitempty"`
+	Affected       []VulnAffected `json:"affected"`
+	References     []string       `json:"references"`
+	KnownExploited bool           `json:"known_exploited"`
+	Epss           *EpssScore     `json:"epss,omitempty"`
+}
+
+// VulnCvss : a CVSS score of the CVE given by the source
+type VulnCvss struct {
+	Source string `json:"source"`
+	// Version is 2.0, 3.0 or 3.1
+	Version string  `json:"version"`
+	Score   float64 `json:"score"`
+	Vector  string  `json:"vector,omitempty"`
+}
+
+// VulnAffected : the fix status of the package in the release of the source.
+// For Microsoft, PackageName is the product name and FixedVersion is the fixed build.
+type VulnAffected struct {
+	Source       string `json:"source"`
+	Release      string `json:"release,omitempty"`
+	PackageName  string `json:"package_name"`
+	Status       string `json:"status"`
+	FixedVersion string `json:"fixed_version,omitempty"`
+	Advisory     string `json:"advisory,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Distributions of the advisories of Chainguard
+const (
+	// WolfiDistroWolfi : the packages of Wolfi (https://packages.wolfi.dev/os)
+	WolfiDistroWolfi = "wolfi"
+	// WolfiDistroChainguard : the packages of Chainguard Images (https://packages.cgr.dev/chainguard)
+	WolfiDistroChainguard = "chainguard"
+)
+
+// WolfiCVE :
+type WolfiCVE struct {
+	ID             int64          `json:"-"`
+	CveID          string         `json:"cve_id" gorm:"type:varchar(255);index:idx_wolfi_cves_cveid"`
+	PublishedDate  time.Time      `json:"published_date"`
+	Packages       []WolfiPackage `json:"packages"`
+	KnownExploited bool           `json:"known_exploited" gorm:"-"`
+	Exploits       []Exploit      `json:"exploits,omitempty" gorm:"-"`
+}
+
+// WolfiPackage : the state of the apk package in the distribution (wolfi or chainguard).
+// FixedVersion is empty if the package is affected and not fixed yet.
+type WolfiPackage struct {
+	ID           int64  `json:"-"`
+	WolfiCVEID   int64  `json:"-" gorm:"index:idx_wolfi_packages_wolfi_cve_id"`
+	Distro       string `json:"distro" gorm:"type:varchar(255);index:idx_wolfi_packages_distro"`
+	AdvisoryID   string `json:"advisory_id" gorm:"type:varchar(255)"`
+	PackageName  string `json:"package_name" gorm:"type:varchar(255);index:idx_wolfi_packages_package_name"`
+	FixedVersion string `json:"fixed_version" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/labstack/echo"
+	"golang.org/x/xerrors"
+)
+
+// AccessLogFields are the fields of the access log in the order written, all written by default
+var AccessLogFields = []string{"time", "request_id", "remote_ip", "method", "route", "uri", "status", "latency_ms", "bytes_out", "api_token", "error"}
+
+// apiTokenNameKey is the key of the context having the name of the API token of the request
+const apiTokenNameKey = "api_token"
+
+// accessLog writes a line of JSON per request with the fields, after the request ID is given by middleware.RequestID
+func accessLog(w io.Writer, fields []string) (echo.MiddlewareFunc, error) {
+	if len(fields) == 0 {
+		fields = AccessLogFields
+	}
+	for _, f := range fields {
+		if !contains(AccessLogFields, f) {
+			return nil, xerrors.Errorf("Unknown field of access log: %s. It must be one of %s", f, strings.Join(AccessLogFields, ", "))
+		}
+	}
+
+	var mu sync.Mutex
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// respond the error here to log the status, and do not return it not to respond it again, like middleware.Logger
+				c.Error(err)
+			}
+
+			req, res := c.Request(), c.Response()
+			values := map[string]interface{}{
+				"time":       start.UTC().Format(time.RFC3339Nano),
+				"request_id": res.Header().Get(echo.HeaderXRequestID),
+				"remote_ip":  c.RealIP(),
+				"method":     req.Method,
+				"route":      c.Path(),
+				"uri":        req.RequestURI,
+				"status":     res.Status,
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"bytes_out":  res.Size,
+				"api_token":  c.Get(apiTokenNameKey),
+				"error":      nil,
+			}
+			if err != nil {
+				values["error"] = err.Error()
+			}
+
+			var buf bytes.Buffer
+			buf.WriteByte('{')
+			for i, f := range fields {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				k, _ := json.Marshal(f)
+				v, _ := json.Marshal(values[f])
+				buf.Write(k)
+				buf.WriteByte(':')
+				buf.Write(v)
+			}
+			buf.WriteString("}\n")
+
+			mu.Lock()
+			defer mu.Unlock()
+			if _, werr := w.Write(buf.Bytes()); werr != nil {
+				log15.Warn("Failed to write access log", "err", werr)
+			}
+			return nil
+		}
+	}, nil
+}
+
+// requestDriver returns the driver logging with the request ID, so that the lines of the DB layer are traced to the access log
+func requestDriver(c echo.Context, driver db.DB) db.DB {
+	id := c.Response().Header().Get(echo.HeaderXRequestID)
+	if id == "" {
+		return driver
+	}
+	return driver.WithLogger(log15.New("request_id", id))
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/labstack/echo"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIToken : a bearer token of the server, configured by api-tokens in the config file or GOST_API_TOKENS.
+// admin-token and read-token are the tokens of the admin and the read roles.
+type APIToken struct {
+	// Name identifies the token in the stats and the logs
+	Name  string `mapstructure:"name"`
+	Token string `mapstructure:"token"`
+	// Role is api (default), read or admin. The read and the admin tokens can also call /admin and /debug/vars
+	Role string `mapstructure:"role"`
+	// Rate is the number of the requests per second. 0 means no limit
+	Rate float64 `mapstructure:"rate"`
+	// Burst is the number of the requests allowed at once. 0 means Rate rounded up
+	Burst int `mapstructure:"burst"`
+}
+
+// ParseAPITokens parses the tokens of GOST_API_TOKENS, "name:token[:rate[:burst]]" separated by commas
+func ParseAPITokens(s string) ([]APIToken, error) {
+	tokens := []APIToken{}
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		ss := strings.Split(t, ":")
+		if len(ss) < 2 || len(ss) > 4 {
+			return nil, xerrors.Errorf("Invalid API token: %s. It must be name:token[:rate[:burst]]", strings.SplitN(t, ":", 2)[0])
+		}
+		token := APIToken{Name: ss[0], Token: ss[1]}
+		var err error
+		if len(ss) > 2 {
+			if token.Rate, err = strconv.ParseFloat(ss[2], 64); err != nil {
+				return nil, xerrors.Errorf("Invalid rate of API token %s: %s", token.Name, ss[2])
+			}
+		}
+		if len(ss) > 3 {
+			if token.Burst, err = strconv.Atoi(ss[3]); err != nil {
+				return nil, xerrors.Errorf("Invalid burst of API token %s: %s", token.Name, ss[3])
+			}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// APITokens authenticates the requests by the bearer tokens, and limits the rate of the requests per token by a token bucket.
+// The limits are shared by HTTP and gRPC.
+// Any token configured, of any role, requires a token for the API.
+type APITokens struct {
+	tokens []*apiTokenState
+	// admin is true if a token of the read or the admin role is configured, which enables /admin and /debug/vars
+	admin bool
+}
+
+type apiTokenState struct {
+	APIToken
+	burst float64
+
+	mu       sync.Mutex
+	bucket   float64
+	filledAt time.Time
+	stats    APITokenStats
+}
+
+// APITokenStats : the accounting of the requests of a token
+type APITokenStats struct {
+	Requests int64 `json:"requests"`
+	// Limited is the number of the requests rejected by the rate limit
+	Limited  int64     `json:"limited"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// NewAPITokens returns APITokens of tokens. It returns nil if tokens is empty, which disables the authentication.
+func NewAPITokens(tokens []APIToken) (*APITokens, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	names, values := map[string]bool{}, map[string]bool{}
+	a := &APITokens{}
+	for _, t := range tokens {
+		switch {
+		case t.Name == "" || t.Token == "":
+			return nil, xerrors.Errorf("API token requires both name and token. name: %q", t.Name)
+		case names[t.Name]:
+			return nil, xerrors.Errorf("Duplicate name of API token: %s", t.Name)
+		case values[t.Token]:
+			return nil, xerrors.Errorf("Duplicate API token. name: %s", t.Name)
+		case t.Rate < 0 || t.Burst < 0:
+			return nil, xerrors.Errorf("Invalid rate limit of API token %s. rate: %g, burst: %d", t.Name, t.Rate, t.Burst)
+		}
+		names[t.Name], values[t.Token] = true, true
+
+		switch t.Role {
+		case "", roleAPI:
+			t.Role = roleAPI
+		case roleRead, roleAdmin:
+			a.admin = true
+		default:
+			return nil, xerrors.Errorf("Invalid role of API token %s: %s. It must be api, read or admin", t.Name, t.Role)
+		}
+
+		burst := float64(t.Burst)
+		if burst == 0 {
+			burst = math.Max(1, math.Ceil(t.Rate))
+		}
+		a.tokens = append(a.tokens, &apiTokenState{APIToken: t, burst: burst, bucket: burst})
+	}
+	return a, nil
+}
+
+// Stats returns the accounting per name of the tokens
+func (a *APITokens) Stats() map[string]APITokenStats {
+	stats := map[string]APITokenStats{}
+	for _, t := range a.tokens {
+		t.mu.Lock()
+		stats[t.Name] = t.stats
+		t.mu.Unlock()
+	}
+	return stats
+}
+
+// lookup compares the key with all tokens in constant time, so that the response time does not tell which token is close
+func (a *APITokens) lookup(key string) *apiTokenState {
+	var found *apiTokenState
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(t.Token)) == 1 {
+			found = t
+		}
+	}
+	return found
+}
+
+// allow takes a request from the bucket, and returns the time until the next request is allowed if it is empty
+func (t *apiTokenState) allow(now time.Time) (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stats.Requests++
+	t.stats.LastUsed = now
+	if t.Rate == 0 {
+		return true, 0
+	}
+	if !t.filledAt.IsZero() {
+		t.bucket = math.Min(t.burst, t.bucket+now.Sub(t.filledAt).Seconds()*t.Rate)
+	}
+	t.filledAt = now
+	if t.bucket >= 1 {
+		t.bucket--
+		return true, 0
+	}
+	t.stats.Limited++
+	return false, time.Duration((1 - t.bucket) / t.Rate * float64(time.Second))
+}
+
+// authenticate returns the token of the bearer credential, or the error of the status to respond
+func (a *APITokens) authenticate(credential string) (*apiTokenState, int, string) {
+	key := strings.TrimSpace(strings.TrimPrefix(credential, "Bearer "))
+	if credential == "" || key == credential {
+		return nil, http.StatusUnauthorized, "Missing bearer token"
+	}
+	t := a.lookup(key)
+	if t == nil {
+		return nil, http.StatusUnauthorized, "Invalid token"
+	}
+	return t, 0, ""
+}
+
+// apiTokenAuth authenticates the requests by the tokens of all roles except the probes, the spec, /admin and /debug/vars (authenticated by adminAuth),
+// and responds 429 with Retry-After over the rate limit of the token
+func apiTokenAuth(tokens *APITokens) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch path := c.Path(); {
+			case path == "/health", path == "/ready", path == "/healthz", path == "/readyz", path == "/openapi.json", path == "/swagger", path == "/debug/vars", strings.HasPrefix(path, "/admin"):
+				return next(c)
+			}
+			req := c.Request()
+			t, code, msg := tokens.authenticate(req.Header.Get(echo.HeaderAuthorization))
+			if t == nil {
+				log15.Warn("API: unauthorized", "reason", msg, "method", req.Method, "path", req.URL.Path, "remote", c.RealIP())
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return echo.NewHTTPError(code, msg)
+			}
+			c.Set(apiTokenNameKey, t.Name)
+			if ok, retryAfter := t.allow(time.Now()); !ok {
+				c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "Rate limit exceeded")
+			}
+			return next(c)
+		}
+	}
+}
+
+// grpcAuthorize authenticates the gRPC call by "authorization: Bearer $TOKEN" in the metadata, in the same way as apiTokenAuth
+func (a *APITokens) grpcAuthorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	credential := ""
+	if vs := md.Get("authorization"); len(vs) > 0 {
+		credential = vs[0]
+	}
+	t, _, msg := a.authenticate(credential)
+	if t == nil {
+		log15.Warn("gRPC: unauthenticated", "reason", msg)
+		return status.Error(codes.Unauthenticated, msg)
+	}
+	if ok, retryAfter := t.allow(time.Now()); !ok {
+		return status.Errorf(codes.ResourceExhausted, "Rate limit exceeded. Retry after %s", retryAfter.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// grpcServerOptions returns the interceptors authenticating the calls
+func (a *APITokens) grpcServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := a.grpcAuthorize(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := a.grpcAuthorize(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewAPITokens(t *testing.T) {
+	var tests = []struct {
+		in    []APIToken
+		admin bool
+		err   bool
+	}{
+		{in: []APIToken{{Name: "scanner", Token: "s3cret"}}, admin: false},
+		{in: []APIToken{{Name: "ops", Token: "0ps", Role: "admin"}}, admin: true},
+		{in: []APIToken{{Name: "audit", Token: "r3ad", Role: "read"}}, admin: true},
+		{in: []APIToken{{Name: "ops", Token: "0ps", Role: "root"}}, err: true},
+		{in: []APIToken{{Name: "a", Token: "same"}, {Name: "b", Token: "same", Role: "admin"}}, err: true},
+		{in: []APIToken{{Name: "a", Token: "x"}, {Name: "a", Token: "y"}}, err: true},
+		{in: []APIToken{{Name: "a"}}, err: true},
+		{in: []APIToken{{Name: "a", Token: "x", Rate: -1}}, err: true},
+	}
+
+	for i, tt := range tests {
+		tokens, err := NewAPITokens(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("[%d] expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err)
+			continue
+		}
+		if tokens.admin != tt.admin {
+			t.Errorf("[%d] expected admin: %t\n  actual: %t\n", i, tt.admin, tokens.admin)
+		}
+	}
+
+	if tokens, err := NewAPITokens(nil); tokens != nil || err != nil {
+		t.Errorf("expected nil without tokens. actual: %v, %v", tokens, err)
+	}
+}
+
+func TestAPITokensAuthenticate(t *testing.T) {
+	tokens, err := NewAPITokens([]APIToken{{Name: "scanner", Token: "s3cret"}, {Name: "ops", Token: "0ps", Role: "admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		in   string
+		name string
+		code int
+	}{
+		{in: "Bearer s3cret", name: "scanner"},
+		{in: "Bearer 0ps", name: "ops"},
+		{in: "Bearer  s3cret ", name: "scanner"},
+		{in: "", code: http.StatusUnauthorized},
+		{in: "s3cret", code: http.StatusUnauthorized},
+		{in: "Basic s3cret", code: http.StatusUnauthorized},
+		{in: "Bearer wrong", code: http.StatusUnauthorized},
+		{in: "Bearer s3cre", code: http.StatusUnauthorized},
+		{in: "Bearer ", code: http.StatusUnauthorized},
+	}
+
+	for i, tt := range tests {
+		token, code, _ := tokens.authenticate(tt.in)
+		name := ""
+		if token != nil {
+			name = token.Name
+		}
+		if name != tt.name || code != tt.code {
+			t.Errorf("[%d] expected: %q %d\n  actual: %q %d\n", i, tt.name, tt.code, name, code)
+		}
+	}
+}
+
+func TestAPITokenAllow(t *testing.T) {
+	tokens, err := NewAPITokens([]APIToken{{Name: "scanner", Token: "s3cret", Rate: 2}, {Name: "unlimited", Token: "x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	limited, unlimited := tokens.tokens[0], tokens.tokens[1]
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	// the burst is the rate rounded up, and the bucket is refilled by 2 per second
+	var tests = []struct {
+		after      time.Duration
+		ok         bool
+		retryAfter time.Duration
+	}{
+		{after: 0, ok: true},
+		{after: 0, ok: true},
+		{after: 0, ok: false, retryAfter: 500 * time.Millisecond},
+		{after: 250 * time.Millisecond, ok: false, retryAfter: 250 * time.Millisecond},
+		{after: 500 * time.Millisecond, ok: true},
+		{after: 500 * time.Millisecond, ok: false, retryAfter: 500 * time.Millisecond},
+		// the bucket is not filled over the burst
+		{after: 10 * time.Second, ok: true},
+		{after: 10 * time.Second, ok: true},
+		{after: 10 * time.Second, ok: false, retryAfter: 500 * time.Millisecond},
+	}
+
+	for i, tt := range tests {
+		ok, retryAfter := limited.allow(start.Add(tt.after))
+		if ok != tt.ok || retryAfter.Round(time.Millisecond) != tt.retryAfter {
+			t.Errorf("[%d] expected: %t %s\n  actual: %t %s\n", i, tt.ok, tt.retryAfter, ok, retryAfter)
+		}
+	}
+	if stats := tokens.Stats()["scanner"]; stats.Requests != int64(len(tests)) || stats.Limited != 4 || !stats.LastUsed.Equal(start.Add(10*time.Second)) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	for i := 0; i < 100; i++ {
+		if ok, _ := unlimited.allow(start); !ok {
+			t.Fatalf("[%d] the token without rate is limited", i)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/models"
+	"github.com/labstack/echo"
+)
+
+// attributionPrefixes are the sources of the first segments of the paths not named after a source.
+// A segment not in it matches the sources starting with it (e.g. redhat matches redhat-csaf and redhatapi).
+var attributionPrefixes = map[string][]string{
+	"cveprogram": {"cvelist"},
+	"exploits":   {"exploit"},
+	"cwes":       {"cwe"},
+	"psirt":      {"cisco", "vmware", "fortinet"},
+	"cn":         {"cnnvd", "cnvd"},
+	"advisorydb": {"rubysec", "pypa", "npm"},
+}
+
+// attributionSources returns the fetched sources of the data responded for the path of the route.
+// The paths across the sources (e.g. /cves/:id) are attributed to all sources fetched.
+func attributionSources(path string, fetched []models.FetchSource) []models.FetchSource {
+	segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	prefixes, ok := attributionPrefixes[segment]
+	if !ok {
+		prefixes = []string{segment}
+	}
+	sources := []models.FetchSource{}
+	for _, f := range fetched {
+		for _, p := range prefixes {
+			if strings.HasPrefix(f.Source, p) {
+				sources = append(sources, f)
+				break
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return fetched
+	}
+	return sources
+}
+
+// responseAttribution wraps the JSON responses with the licenses and the attributions of the sources if ?attribution=true,
+// as {"result": ..., "attributions": [...]}. With ?debug=true, the attributions are added to the wrapper of the debug information.
+func responseAttribution(driver db.DB) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Path() {
+			case "/graphql", "/attributions":
+				return next(c)
+			}
+			if c.QueryParam("attribution") != "true" {
+				return next(c)
+			}
+
+			res := c.Response()
+			w := &fieldsRecorder{ResponseWriter: res.Writer, header: res.Header()}
+			res.Writer = w
+			defer func() { res.Writer = w.ResponseWriter }()
+
+			if err := next(c); err != nil {
+				return err
+			}
+			if !w.buffered {
+				return nil
+			}
+			fetched, err := driver.GetFetchSources()
+			if err != nil {
+				log15.Error("Failed to get the attributions", "err", err)
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the attributions")
+			}
+			body, err := attributeResponse(c, w.body.Bytes(), attributionSources(c.Path(), fetched))
+			if err != nil {
+				body = w.body.Bytes()
+			}
+			w.ResponseWriter.WriteHeader(w.status)
+			_, err = w.ResponseWriter.Write(body)
+			return err
+		}
+	}
+}
+
+func attributeResponse(c echo.Context, body []byte, sources []models.FetchSource) ([]byte, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if m, ok := v.(map[string]interface{}); ok && c.QueryParam("debug") == "true" {
+		if _, ok := m["result"]; ok {
+			m["attributions"] = sources
+			return marshalProjected(m)
+		}
+	}
+	return marshalProjected(map[string]interface{}{
+		"result":       v,
+		"attributions": sources,
+	})
+}
+
+// Handler
+// @summary Get the licenses and the attributions of the sources fetched
+// @response []models.FetchSource
+func getAttributions(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		fetched, err := driver.GetFetchSources()
+		if err != nil {
+			log15.Error("Failed to get the attributions", "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(http.StatusOK, fetched)
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/labstack/echo"
+)
+
+// Roles of the API tokens
+const (
+	// roleAPI can call the API only. It is the default of the tokens
+	roleAPI = "api"
+	// roleRead can also call the read-only endpoints (GET and HEAD) of /admin and /debug/vars
+	roleRead = "read"
+	// roleAdmin can call all endpoints, including the mutating ones (e.g. upsert)
+	roleAdmin = "admin"
+)
+
+// adminAuth authenticates /admin and /debug/vars by the tokens of the read and the admin roles,
+// and rejects the mutating requests of the read tokens with 403. The rate limits of the tokens apply as well.
+// Each request is logged with the name, the role and the fingerprint of the token for audit.
+func adminAuth(tokens *APITokens) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			credential := req.Header.Get(echo.HeaderAuthorization)
+			t, code, msg := tokens.authenticate(credential)
+			if t == nil {
+				log15.Warn("Admin API: unauthorized", "reason", msg, "token", tokenFingerprint(strings.TrimPrefix(credential, "Bearer ")), "method", req.Method, "path", req.URL.Path, "remote", c.RealIP())
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return echo.NewHTTPError(code, msg)
+			}
+			c.Set(apiTokenNameKey, t.Name)
+			fingerprint := tokenFingerprint(t.Token)
+			switch {
+			case t.Role != roleRead && t.Role != roleAdmin:
+				log15.Warn("Admin API: forbidden", "name", t.Name, "role", t.Role, "token", fingerprint, "method", req.Method, "path", req.URL.Path, "remote", c.RealIP())
+				return echo.NewHTTPError(http.StatusForbidden, "The token cannot call the admin endpoints")
+			case t.Role == roleRead && req.Method != http.MethodGet && req.Method != http.MethodHead:
+				log15.Warn("Admin API: forbidden", "name", t.Name, "role", t.Role, "token", fingerprint, "method", req.Method, "path", req.URL.Path, "remote", c.RealIP())
+				return echo.NewHTTPError(http.StatusForbidden, "The token is read-only")
+			}
+			if ok, retryAfter := t.allow(time.Now()); !ok {
+				c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "Rate limit exceeded")
+			}
+
+			err := next(c)
+			status := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			}
+			log15.Info("Admin API", "name", t.Name, "role", t.Role, "token", fingerprint, "method", req.Method, "path", req.URL.Path, "remote", c.RealIP(), "status", status)
+			return err
+		}
+	}
+}
+
+// tokenFingerprint identifies the token in the audit log without writing the token itself
+func tokenFingerprint(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:4])
+}
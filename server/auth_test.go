@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func TestAdminAuth(t *testing.T) {
+	tokens, err := NewAPITokens([]APIToken{
+		{Name: "scanner", Token: "s3cret"},
+		{Name: "audit", Token: "r3ad", Role: "read"},
+		{Name: "ops", Token: "0ps", Role: "admin"},
+		{Name: "slow", Token: "sl0w", Role: "admin", Rate: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := echo.New()
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	admin := e.Group("/admin", adminAuth(tokens))
+	admin.GET("/raw/:source/:cveID", ok)
+	admin.POST("/upsert/:source", ok)
+
+	var tests = []struct {
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{method: http.MethodGet, path: "/admin/raw/redhat/CVE-2021-3449", code: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/admin/raw/redhat/CVE-2021-3449", token: "wrong", code: http.StatusUnauthorized},
+		// the tokens of the api role cannot call /admin
+		{method: http.MethodGet, path: "/admin/raw/redhat/CVE-2021-3449", token: "s3cret", code: http.StatusForbidden},
+		{method: http.MethodPost, path: "/admin/upsert/redhat", token: "s3cret", code: http.StatusForbidden},
+		// the read tokens can call GET only
+		{method: http.MethodGet, path: "/admin/raw/redhat/CVE-2021-3449", token: "r3ad", code: http.StatusOK},
+		{method: http.MethodPost, path: "/admin/upsert/redhat", token: "r3ad", code: http.StatusForbidden},
+		{method: http.MethodGet, path: "/admin/raw/redhat/CVE-2021-3449", token: "0ps", code: http.StatusOK},
+		{method: http.MethodPost, path: "/admin/upsert/redhat", token: "0ps", code: http.StatusOK},
+		// the rate limits apply to the admin tokens as well
+		{method: http.MethodPost, path: "/admin/upsert/redhat", token: "sl0w", code: http.StatusOK},
+		{method: http.MethodPost, path: "/admin/upsert/redhat", token: "sl0w", code: http.StatusTooManyRequests},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("[%d] %s %s by %q expected: %d\n  actual: %d\n", i, tt.method, tt.path, tt.token, tt.code, rec.Code)
+		}
+		if tt.code == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "1" {
+			t.Errorf("[%d] expected Retry-After: 1\n  actual: %q\n", i, rec.Header().Get("Retry-After"))
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+// responseFields projects the JSON responses to the top-level fields of the records given by ?fields (e.g. fields=cvss3,package_state).
+// The record is the response itself, the values of a map (e.g. CVE-ID to CVE) or the elements of an array,
+// and in the page of responseCVEs and the wrapper of ?debug, the CVEs and the result.
+// The responses other than 200 of JSON (e.g. the errors and the streams) are passed as they are.
+func responseFields() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			fields := queryFields(c)
+			// GraphQL selects the fields by the query
+			if len(fields) == 0 || c.Path() == "/graphql" {
+				return next(c)
+			}
+
+			res := c.Response()
+			w := &fieldsRecorder{ResponseWriter: res.Writer, header: res.Header()}
+			res.Writer = w
+			defer func() { res.Writer = w.ResponseWriter }()
+
+			if err := next(c); err != nil {
+				return err
+			}
+			if !w.buffered {
+				return nil
+			}
+			body, err := projectResponse(c, w.body.Bytes(), fields)
+			if err != nil {
+				// e.g. the body is not JSON in spite of Content-Type
+				body = w.body.Bytes()
+			}
+			w.ResponseWriter.WriteHeader(w.status)
+			_, err = w.ResponseWriter.Write(body)
+			return err
+		}
+	}
+}
+
+// queryFields returns the set of the fields of ?fields, comma-separated
+func queryFields(c echo.Context) map[string]bool {
+	fields := map[string]bool{}
+	for _, f := range strings.Split(c.QueryParam("fields"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields[f] = true
+		}
+	}
+	return fields
+}
+
+func projectResponse(c echo.Context, body []byte, fields map[string]bool) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// keep the precision of the numbers
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	paged := c.QueryParam("sort") != "" || c.QueryParam("offset") != "" || c.QueryParam("limit") != ""
+	records := func(r interface{}) interface{} {
+		if m, ok := r.(map[string]interface{}); ok && paged {
+			if cves, ok := m["cves"]; ok {
+				m["cves"] = projectRecords(cves, fields)
+				return m
+			}
+		}
+		return projectRecords(r, fields)
+	}
+	if m, ok := v.(map[string]interface{}); ok && c.QueryParam("debug") == "true" {
+		if r, ok := m["result"]; ok {
+			m["result"] = records(r)
+			return marshalProjected(m)
+		}
+	}
+	return marshalProjected(records(v))
+}
+
+// projectRecords keeps the fields of the records in v.
+// An object whose values are all objects and whose keys are none of the fields is regarded as a map of the records.
+func projectRecords(v interface{}, fields map[string]bool) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i, e := range v {
+			v[i] = projectRecords(e, fields)
+		}
+		return v
+	case map[string]interface{}:
+		if isRecordMap(v, fields) {
+			for k, e := range v {
+				v[k] = projectRecords(e, fields)
+			}
+			return v
+		}
+		projected := map[string]interface{}{}
+		for k, e := range v {
+			if fields[k] {
+				projected[k] = e
+			}
+		}
+		return projected
+	}
+	return v
+}
+
+func isRecordMap(m map[string]interface{}, fields map[string]bool) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for k, e := range m {
+		if fields[k] {
+			return false
+		}
+		if _, ok := e.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func marshalProjected(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fieldsRecorder holds the body of the response of 200 in JSON until the handler returns, and passes the others through
+type fieldsRecorder struct {
+	http.ResponseWriter
+	header   http.Header
+	status   int
+	buffered bool
+	body     bytes.Buffer
+}
+
+func (w *fieldsRecorder) WriteHeader(code int) {
+	if code == http.StatusOK && strings.HasPrefix(w.header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		w.status, w.buffered = code, true
+		w.header.Del(echo.HeaderContentLength)
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *fieldsRecorder) Write(b []byte) (int, error) {
+	if w.buffered {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *fieldsRecorder) Flush() {
+	if w.buffered {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
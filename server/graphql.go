@@ -0,0 +1,331 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/models"
+	"github.com/labstack/echo"
+	"golang.org/x/xerrors"
+)
+
+// graphqlDriverKey is the key of the driver of the request in the context of the resolvers
+type graphqlDriverKey struct{}
+
+// graphqlCve is the source of the type Cve. The sources not in records are got from the DB only when they are selected
+type graphqlCve struct {
+	id      string
+	records map[string]interface{}
+}
+
+// graphqlRequest : the body of POST /graphql
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Handler
+// @summary Query the CVEs by GraphQL
+// It is served with --graphql.
+// @query query The query of GET
+// @body graphqlRequest
+// @response map[string]interface{}
+func getGraphQL(schema graphql.Schema, driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := graphqlRequest{Query: c.QueryParam("query")}
+		if c.Request().Method == http.MethodPost {
+			if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid body: %s", err))
+			}
+		}
+		if req.Query == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "query is required")
+		}
+
+		driver, explain := explainDriver(c, driver)
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        context.WithValue(c.Request().Context(), graphqlDriverKey{}, driver),
+		})
+		return responseJSON(c, explain, result)
+	}
+}
+
+// newGraphQLSchema builds the schema of /graphql.
+// The types of the records are generated from the structs of models, and their fields are the keys of the JSON of the HTTP server.
+func newGraphQLSchema() (graphql.Schema, error) {
+	g := graphqlTypes{objects: map[reflect.Type]*graphql.Object{}, names: map[string]bool{}}
+
+	sourceTypes := map[string]reflect.Type{}
+	detail := reflect.TypeOf(models.CveDetail{})
+	for i := 0; i < detail.NumField(); i++ {
+		f := detail.Field(i)
+		sourceTypes[strings.Split(f.Tag.Get("json"), ",")[0]] = f.Type
+	}
+	cveFields := graphql.Fields{
+		"cve_id": &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*graphqlCve).id, nil
+			},
+		},
+	}
+	for _, source := range db.CveSources {
+		t, ok := sourceTypes[source]
+		if !ok {
+			return graphql.Schema{}, xerrors.Errorf("Failed to build GraphQL schema. source %s is not in CveDetail", source)
+		}
+		source := source
+		cveFields[source] = &graphql.Field{
+			Type: g.output(t, ""),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				c := p.Source.(*graphqlCve)
+				if r, ok := c.records[source]; ok {
+					return r, nil
+				}
+				return db.GetCveOfSource(graphqlDriver(p), source, c.id), nil
+			},
+		}
+	}
+	cve := graphql.NewObject(graphql.ObjectConfig{Name: "Cve", Fields: cveFields})
+
+	packageArgs := graphql.FieldConfigArgument{
+		"family":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"release":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"package":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		"channels": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"cve": &graphql.Field{
+				Type: cve,
+				Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return &graphqlCve{id: p.Args["id"].(string)}, nil
+				},
+			},
+			"cves": &graphql.Field{
+				Type: graphql.NewList(cve),
+				Args: graphql.FieldConfigArgument{"ids": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					cves := []*graphqlCve{}
+					for _, id := range p.Args["ids"].([]interface{}) {
+						cves = append(cves, &graphqlCve{id: id.(string)})
+					}
+					return cves, nil
+				},
+			},
+			"unfixedCves": &graphql.Field{
+				Type:    graphql.NewList(cve),
+				Args:    packageArgs,
+				Resolve: resolvePackageCves(db.GetUnfixedCves),
+			},
+			"fixedCves": &graphql.Field{
+				Type:    graphql.NewList(cve),
+				Args:    packageArgs,
+				Resolve: resolvePackageCves(db.GetFixedCves),
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func graphqlDriver(p graphql.ResolveParams) db.DB {
+	return p.Context.Value(graphqlDriverKey{}).(db.DB)
+}
+
+// resolvePackageCves resolves the CVEs of the package got by get (db.GetUnfixedCves or db.GetFixedCves) in the order of CVE-ID.
+// The record of the package is in the field of the upstream distribution, e.g. ubuntu for linuxmint.
+func resolvePackageCves(get func(db.DB, string, string, string, ...string) (interface{}, error)) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		family, release, pkgName := p.Args["family"].(string), p.Args["release"].(string), p.Args["package"].(string)
+		channels := []string{}
+		if cs, ok := p.Args["channels"].([]interface{}); ok {
+			for _, c := range cs {
+				channels = append(channels, c.(string))
+			}
+		}
+		upstream, _, err := db.ResolveDerivative(family, release)
+		if err != nil {
+			return nil, err
+		}
+		if upstream == models.WolfiDistroChainguard {
+			upstream = models.WolfiDistroWolfi
+		}
+		cves, err := get(graphqlDriver(p), family, release, pkgName, channels...)
+		if err != nil {
+			if errors.Is(err, db.ErrNotCovered) {
+				return []*graphqlCve{}, nil
+			}
+			return nil, err
+		}
+
+		m := reflect.ValueOf(cves)
+		results := make([]*graphqlCve, 0, m.Len())
+		for _, k := range m.MapKeys() {
+			results = append(results, &graphqlCve{id: k.String(), records: map[string]interface{}{upstream: m.MapIndex(k).Interface()}})
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].id < results[j].id })
+		return results, nil
+	}
+}
+
+// graphqlTypes generates the GraphQL types of the Go types
+type graphqlTypes struct {
+	objects map[reflect.Type]*graphql.Object
+	names   map[string]bool
+}
+
+var graphqlInvalidName = regexp.MustCompile(`[^_0-9A-Za-z]`)
+
+// output returns the GraphQL type of t. name is the name of the object if t is an anonymous struct
+func (g *graphqlTypes) output(t reflect.Type, name string) graphql.Output {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.output(t.Elem(), name)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return graphql.String
+		}
+		return graphql.NewList(g.output(t.Elem(), name))
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return graphqlDateTime
+		}
+		return g.object(t, name)
+	case reflect.String:
+		return graphql.String
+	case reflect.Bool:
+		return graphql.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return graphql.Int
+	case reflect.Float32, reflect.Float64:
+		return graphql.Float
+	default:
+		return graphqlJSON
+	}
+}
+
+// object returns the object of the struct t. The fields are generated when the schema is built, since the structs may refer to each other.
+func (g *graphqlTypes) object(t reflect.Type, name string) *graphql.Object {
+	if o, ok := g.objects[t]; ok {
+		return o
+	}
+	if t.Name() != "" {
+		name = t.Name()
+	}
+	name = graphqlInvalidName.ReplaceAllString(name, "_")
+	for base, i := name, 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.names[name] = true
+
+	o := graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := graphql.Fields{}
+			g.addFields(fields, t, name, nil)
+			return fields
+		}),
+	})
+	g.objects[t] = o
+	return o
+}
+
+// addFields adds the fields of the struct t by the key of JSON. The fields of the embedded structs are added as the fields of t.
+func (g *graphqlTypes) addFields(fields graphql.Fields, t reflect.Type, objName string, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := strings.Split(f.Tag.Get("json"), ",")[0]
+		if key == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		idx := append(append([]int{}, index...), i)
+		if f.Anonymous && key == "" && f.Type.Kind() == reflect.Struct {
+			g.addFields(fields, f.Type, objName, idx)
+			continue
+		}
+		if key == "" {
+			key = f.Name
+		}
+		key = graphqlInvalidName.ReplaceAllString(key, "_")
+		fields[key] = &graphql.Field{Type: g.output(f.Type, objName+f.Name), Resolve: graphqlFieldResolver(idx)}
+	}
+}
+
+// graphqlFieldResolver resolves the field of the struct at index
+func graphqlFieldResolver(index []int) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		v := reflect.ValueOf(p.Source)
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, nil
+		}
+		f := v.FieldByIndex(index)
+		if (f.Kind() == reflect.Ptr || f.Kind() == reflect.Slice || f.Kind() == reflect.Map || f.Kind() == reflect.Interface) && f.IsNil() {
+			return nil, nil
+		}
+		return f.Interface(), nil
+	}
+}
+
+// graphqlDateTime is the time in RFC 3339
+var graphqlDateTime = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "DateTime",
+	Description: "The time in RFC 3339",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(time.RFC3339)
+		case *time.Time:
+			return v.Format(time.RFC3339)
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if s, ok := valueAST.(*ast.StringValue); ok {
+			if t, err := time.Parse(time.RFC3339, s.Value); err == nil {
+				return t
+			}
+		}
+		return nil
+	},
+})
+
+// graphqlJSON is the value responded as the JSON of the HTTP server (e.g. a map)
+var graphqlJSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "The value as the JSON of the HTTP server",
+	Serialize:    func(value interface{}) interface{} { return value },
+	ParseValue:   func(value interface{}) interface{} { return value },
+	ParseLiteral: func(valueAST ast.Value) interface{} { return valueAST.GetValue() },
+})
@@ -0,0 +1,254 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"sort"
+
+	"github.com/inconshreveable/log15"
+	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/gostpb"
+	"github.com/knqyf263/gost/models"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcMultiChunkSize is the number of CVEs got from the DB at once by GetCves, so the first ones are streamed before the rest are got
+const grpcMultiChunkSize = 100
+
+// StartGRPC starts the gRPC service of the same lookups as the HTTP server, listening on bindURL.
+// If tlsConfig is not nil, it is served over TLS. If tokens is not nil, the calls are authenticated by them.
+func StartGRPC(bindURL string, driver db.DB, tlsConfig *tls.Config, tokens *APITokens) (*grpc.Server, error) {
+	lis, err := net.Listen("tcp", bindURL)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to listen gRPC. URL: %s, err: %w", bindURL, err)
+	}
+	opts := []grpc.ServerOption{}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if tokens != nil {
+		opts = append(opts, tokens.grpcServerOptions()...)
+	}
+	s := grpc.NewServer(opts...)
+	gostpb.RegisterGostServer(s, &grpcServer{driver: driver})
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			log15.Error("Failed to serve gRPC", "err", err)
+		}
+	}()
+	log15.Info("Listening gRPC", "URL", bindURL)
+	return s, nil
+}
+
+type grpcServer struct {
+	gostpb.UnimplementedGostServer
+	driver db.DB
+}
+
+// GetCve :
+func (s *grpcServer) GetCve(ctx context.Context, req *gostpb.CveRequest) (*gostpb.Record, error) {
+	cves, err := s.getCves(req.Source, []string{req.CveId})
+	if err != nil {
+		return nil, err
+	}
+	c, ok := cves[req.CveId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s is not found in %s", req.CveId, req.Source)
+	}
+	return newRecord(req.Source, req.CveId, c)
+}
+
+// GetCves :
+func (s *grpcServer) GetCves(req *gostpb.CvesRequest, stream gostpb.Gost_GetCvesServer) error {
+	for i := 0; i < len(req.CveIds); i += grpcMultiChunkSize {
+		end := i + grpcMultiChunkSize
+		if end > len(req.CveIds) {
+			end = len(req.CveIds)
+		}
+		cves, err := s.getCves(req.Source, req.CveIds[i:end])
+		if err != nil {
+			return err
+		}
+		for _, cveID := range req.CveIds[i:end] {
+			c, ok := cves[cveID]
+			if !ok {
+				continue
+			}
+			r, err := newRecord(req.Source, cveID, c)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getCves gets the CVEs found in the source by CVE-ID
+func (s *grpcServer) getCves(source string, cveIDs []string) (map[string]interface{}, error) {
+	cves := map[string]interface{}{}
+	switch source {
+	case "redhat":
+		for cveID, c := range s.driver.GetRedhatMulti(cveIDs) {
+			cves[cveID] = c
+		}
+	case "microsoft":
+		for cveID, c := range s.driver.GetMicrosoftMulti(cveIDs) {
+			cves[cveID] = c
+		}
+	case "debian":
+		for _, cveID := range cveIDs {
+			if c := s.driver.GetDebian(cveID); c != nil && c.CveID != "" {
+				cves[cveID] = c
+			}
+		}
+	case "ubuntu":
+		for _, cveID := range cveIDs {
+			if c := s.driver.GetUbuntu(cveID); c != nil && c.Candidate != "" {
+				cves[cveID] = c
+			}
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown source: %s", source)
+	}
+	return cves, nil
+}
+
+// GetUnfixedCves :
+func (s *grpcServer) GetUnfixedCves(req *gostpb.PackageRequest, stream gostpb.Gost_GetUnfixedCvesServer) error {
+	cves, err := db.GetUnfixedCves(s.driver, req.Family, req.Release, req.PackageName, req.Channels...)
+	return sendPackageCves(stream, req.Family, cves, err)
+}
+
+// GetFixedCves :
+func (s *grpcServer) GetFixedCves(req *gostpb.PackageRequest, stream gostpb.Gost_GetFixedCvesServer) error {
+	cves, err := db.GetFixedCves(s.driver, req.Family, req.Release, req.PackageName, req.Channels...)
+	return sendPackageCves(stream, req.Family, cves, err)
+}
+
+// sendPackageCves sends the map of CVE-ID to CVE got by db.GetUnfixedCves or db.GetFixedCves in the order of CVE-ID
+func sendPackageCves(stream grpc.ServerStream, family string, cves interface{}, err error) error {
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrNotCovered):
+			stream.SetTrailer(metadata.Pairs("x-gost-not-covered", "true"))
+			return nil
+		case errors.Is(err, db.ErrUnknownSource), errors.Is(err, db.ErrUnknownRelease):
+			return status.Error(codes.NotFound, err.Error())
+		}
+		log15.Error("Failed to get CVEs of the package", "err", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	m := reflect.ValueOf(cves)
+	if m.Kind() != reflect.Map {
+		return nil
+	}
+	cveIDs := make([]string, 0, m.Len())
+	for _, k := range m.MapKeys() {
+		cveIDs = append(cveIDs, k.String())
+	}
+	sort.Strings(cveIDs)
+	for _, cveID := range cveIDs {
+		r, err := newRecord(family, cveID, m.MapIndex(reflect.ValueOf(cveID)).Interface())
+		if err != nil {
+			return err
+		}
+		if err := stream.SendMsg(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetVulnInfos :
+func (s *grpcServer) GetVulnInfos(req *gostpb.VulnInfosRequest, stream gostpb.Gost_GetVulnInfosServer) error {
+	for _, cveID := range req.CveIds {
+		v := db.GetVulnInfo(s.driver, cveID)
+		if len(v.Sources) == 0 {
+			continue
+		}
+		if err := stream.Send(newVulnInfo(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSupersededKBs :
+func (s *grpcServer) GetSupersededKBs(ctx context.Context, req *gostpb.KBRequest) (*gostpb.KBs, error) {
+	kbIDs, err := s.driver.GetSupersededKBs(req.KbId)
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidKBID) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log15.Error("Failed to get superseded KBs", "kbID", req.KbId, "err", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &gostpb.KBs{KbIds: kbIDs}, nil
+}
+
+// GetRequiredKBs :
+func (s *grpcServer) GetRequiredKBs(req *gostpb.BuildRequest, stream gostpb.Gost_GetRequiredKBsServer) error {
+	kbs, err := s.driver.GetRequiredKBsForBuild(req.ProductId, req.Build)
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidBuild) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		log15.Error("Failed to get required KBs", "productID", req.ProductId, "build", req.Build, "err", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	for _, kb := range kbs {
+		if err := stream.Send(&gostpb.KBBuild{KbId: kb.KBID, ProductId: kb.ProductID, FixedBuild: kb.FixedBuild}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newRecord marshals the CVE of the source into the same JSON as the HTTP server
+func newRecord(source, cveID string, cve interface{}) (*gostpb.Record, error) {
+	b, err := json.Marshal(cve)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to marshal %s of %s. err: %s", cveID, source, err)
+	}
+	return &gostpb.Record{Source: source, CveId: cveID, Json: b}, nil
+}
+
+func newVulnInfo(v models.VulnInfo) *gostpb.VulnInfo {
+	info := gostpb.VulnInfo{
+		Id:               v.ID,
+		Sources:          v.Sources,
+		Title:            v.Title,
+		Description:      v.Description,
+		Severity:         v.Severity,
+		SourceSeverities: v.SourceSeverities,
+		References:       v.References,
+		KnownExploited:   v.KnownExploited,
+	}
+	for _, c := range v.Cvss {
+		info.Cvss = append(info.Cvss, &gostpb.VulnCvss{Source: c.Source, Version: c.Version, Score: c.Score, Vector: c.Vector})
+	}
+	if v.PublishedDate != nil {
+		info.PublishedDate = timestamppb.New(*v.PublishedDate)
+	}
+	for _, a := range v.Affected {
+		info.Affected = append(info.Affected, &gostpb.VulnAffected{Source: a.Source, Release: a.Release, PackageName: a.PackageName, Status: a.Status, FixedVersion: a.FixedVersion, Advisory: a.Advisory})
+	}
+	if v.Epss != nil {
+		info.Epss, info.EpssPercentile = v.Epss.Score, v.Epss.Percentile
+	}
+	return &info
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/knqyf263/gost/config"
+	"github.com/labstack/echo"
+	"golang.org/x/xerrors"
+)
+
+//go:generate go run ./openapigen openapi.json
+
+// openAPIJSON is the OpenAPI 3 spec generated from the annotations of the handlers
+//
+//go:embed openapi.json
+var openAPIJSON []byte
+
+// swaggerUIVersion is the version of swagger-ui-dist loaded by GET /swagger
+const swaggerUIVersion = "5.17.14"
+
+// openAPISpec returns the spec with the version of gost
+func openAPISpec() ([]byte, error) {
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(openAPIJSON, &spec); err != nil {
+		return nil, xerrors.Errorf("Failed to unmarshal OpenAPI spec. err: %w", err)
+	}
+	if info, ok := spec["info"].(map[string]interface{}); ok && config.Version != "" {
+		info["version"] = config.Version
+	}
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to marshal OpenAPI spec. err: %w", err)
+	}
+	return b, nil
+}
+
+// Handler
+// @summary Get the OpenAPI 3 spec of this server
+// @response 200 application/json map[string]interface{}
+func getOpenAPI(spec []byte) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSONBlob(http.StatusOK, spec)
+	}
+}
+
+// Handler
+// The assets of Swagger UI are loaded from unpkg.com by the browser.
+// @summary Browse the OpenAPI 3 spec by Swagger UI
+// @response 200 text/html string
+func getSwaggerUI() echo.HandlerFunc {
+	page := `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>gost</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`
+	return func(c echo.Context) error {
+		return c.HTML(http.StatusOK, page)
+	}
+}
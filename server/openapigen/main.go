@@ -0,0 +1,889 @@
+// Command openapigen generates the OpenAPI 3 spec of the HTTP server (server/openapi.json).
+// It is run by go generate in the directory of the server package.
+//
+// The routes are read from the registrations in Start (e.g. e.GET("/redhat/cves/:id", getRedhatCve(driver))),
+// and the operations from the annotations in the doc comments of the handlers:
+//
+//	// Handler
+//	// The other lines are the description of the operation.
+//	// @summary Get the CVE of RedHat
+//	// @param id The CVE-ID, e.g. CVE-2021-44228
+//	// @query arch The architecture of the products, e.g. x64
+//	// @query limit:integer The maximum number of the matches
+//	// @body bulkPackagesRequest
+//	// @response *models.RedhatCVE
+//	// @response 503 models.StalenessReport
+//	// @response 200 text/plain string
+//
+// The types are Go type expressions of the server package, so the schemas are generated from the structs by the keys of JSON.
+// The query parameters read in the handler, ?debug of explainDriver, the paging of responseCVEs, ?fields and ?attribution of the JSON responses and the errors responded are added without the annotations.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const modulePath = "github.com/knqyf263/gost"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("openapigen: ")
+	out := "openapi.json"
+	if len(os.Args) > 1 {
+		out = os.Args[1]
+	}
+	root, err := moduleRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := newGenerator(root)
+	spec, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(spec); err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(out, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// moduleRoot returns the directory of go.mod above the working directory
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod is not found")
+		}
+		dir = parent
+	}
+}
+
+// object is a JSON object of the spec
+type object = map[string]interface{}
+
+// pkg is the parsed package of the module
+type pkg struct {
+	name  string
+	types map[string]*ast.TypeSpec
+	// imports are the import paths by the name in the file declaring each type
+	imports map[*ast.TypeSpec]map[string]string
+	funcs   map[string]*ast.FuncDecl
+	files   []*ast.File
+}
+
+type generator struct {
+	root    string
+	fset    *token.FileSet
+	pkgs    map[string]*pkg
+	schemas object
+	// names are the names of the components by the package and the type
+	names map[string]string
+}
+
+func newGenerator(root string) *generator {
+	return &generator{root: root, fset: token.NewFileSet(), pkgs: map[string]*pkg{}, schemas: object{}, names: map[string]string{}}
+}
+
+// load parses the package of the import path in the module, respecting the build tags
+func (g *generator) load(importPath string) (*pkg, error) {
+	if p, ok := g.pkgs[importPath]; ok {
+		return p, nil
+	}
+	dir := filepath.Join(g.root, strings.TrimPrefix(strings.TrimPrefix(importPath, modulePath), "/"))
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{types: map[string]*ast.TypeSpec{}, imports: map[*ast.TypeSpec]map[string]string{}, funcs: map[string]*ast.FuncDecl{}}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		f, err := parser.ParseFile(g.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		p.name = f.Name.Name
+		p.files = append(p.files, f)
+		imports := map[string]string{}
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			imports[name] = path
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						p.types[ts.Name.Name] = ts
+						p.imports[ts] = imports
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					p.funcs[d.Name.Name] = d
+				}
+			}
+		}
+	}
+	g.pkgs[importPath] = p
+	return p, nil
+}
+
+// route is a registration of a handler in Start
+type route struct {
+	method  string
+	path    string
+	handler string
+	secured bool
+}
+
+var echoMethods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true}
+
+// routes reads the registrations of the handlers in Start in the order
+func (g *generator) routes(server *pkg) ([]route, error) {
+	start, ok := server.funcs["Start"]
+	if !ok {
+		return nil, fmt.Errorf("Start is not found in the server package")
+	}
+	type group struct {
+		prefix  string
+		secured bool
+	}
+	groups := map[string]group{"e": {}}
+	routes := []route{}
+	var err error
+	ast.Inspect(start.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// admin := e.Group("/admin", adminAuth(tokens))
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			call, ok := n.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Group" || len(call.Args) == 0 {
+				return true
+			}
+			parent, ok := groups[exprString(sel.X)]
+			if !ok {
+				return true
+			}
+			prefix, _ := strconv.Unquote(exprString(call.Args[0]))
+			groups[exprString(n.Lhs[0])] = group{prefix: parent.prefix + prefix, secured: parent.secured || len(call.Args) > 1}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || !echoMethods[sel.Sel.Name] || len(n.Args) < 2 {
+				return true
+			}
+			grp, ok := groups[exprString(sel.X)]
+			if !ok {
+				return true
+			}
+			path, e := strconv.Unquote(exprString(n.Args[0]))
+			if e != nil {
+				err = fmt.Errorf("the path of %s is not a literal", exprString(n))
+				return false
+			}
+			h, ok := n.Args[1].(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			name, ok := h.Fun.(*ast.Ident)
+			if !ok {
+				// e.g. echo.WrapHandler(expvar.Handler())
+				return true
+			}
+			routes = append(routes, route{method: sel.Sel.Name, path: grp.prefix + path, handler: name.Name, secured: grp.secured})
+		}
+		return true
+	})
+	return routes, err
+}
+
+func exprString(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.BasicLit:
+		return e.Value
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	case *ast.CallExpr:
+		return exprString(e.Fun) + "(...)"
+	}
+	return fmt.Sprintf("%T", e)
+}
+
+var pathParamRegexp = regexp.MustCompile(`:([^/]+)`)
+
+// generate builds the spec
+func (g *generator) generate() (object, error) {
+	server, err := g.load(modulePath + "/server")
+	if err != nil {
+		return nil, err
+	}
+	routes, err := g.routes(server)
+	if err != nil {
+		return nil, err
+	}
+	registered := map[string]int{}
+	for _, r := range routes {
+		registered[r.handler]++
+	}
+
+	paths := object{}
+	for _, r := range routes {
+		fn, ok := server.funcs[r.handler]
+		if !ok {
+			return nil, fmt.Errorf("the handler %s of %s %s is not found", r.handler, r.method, r.path)
+		}
+		a, err := parseAnnotations(fn)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.handler, err)
+		}
+		op, path, err := g.operation(r, fn, a)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.handler, err)
+		}
+		op["operationId"] = r.handler
+		if registered[r.handler] > 1 {
+			op["operationId"] = r.handler + strings.Title(strings.ToLower(r.method))
+		}
+		item, _ := paths[path].(object)
+		if item == nil {
+			item = object{}
+			paths[path] = item
+		}
+		item[strings.ToLower(r.method)] = op
+	}
+
+	g.schemas["Error"] = object{
+		"type":       "object",
+		"properties": object{"message": object{"type": "string"}},
+	}
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "gost",
+			"description": "The HTTP server of gost (gost server). Generated from the annotations of the handlers by go generate ./server",
+			"version":     "dev",
+		},
+		"paths": paths,
+		"components": object{
+			"schemas": g.schemas,
+			"securitySchemes": object{
+				"token": object{"type": "http", "scheme": "bearer", "description": "A token of the read or the admin role (api-tokens, admin-token or read-token of the config file)"},
+			},
+		},
+	}, nil
+}
+
+// annotations are the annotations in the doc comment of a handler
+type annotations struct {
+	summary     string
+	description []string
+	params      map[string]string
+	// wildcard is the name of the parameter of * at the end of the path
+	wildcard  string
+	queries   []query
+	body      string
+	responses []response
+}
+
+type query struct {
+	name, typ, description string
+}
+
+type response struct {
+	status      string
+	contentType string
+	typ         string
+}
+
+func parseAnnotations(fn *ast.FuncDecl) (annotations, error) {
+	a := annotations{params: map[string]string{}}
+	if fn.Doc == nil {
+		return a, nil
+	}
+	for _, line := range strings.Split(fn.Doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "Handler" {
+			continue
+		}
+		// e.g. ready responds 503 if ... -> Responds 503 if ...
+		if len(a.description) == 0 && strings.HasPrefix(line, fn.Name.Name+" ") {
+			line = strings.TrimPrefix(line, fn.Name.Name+" ")
+			line = strings.ToUpper(line[:1]) + line[1:]
+		}
+		if !strings.HasPrefix(line, "@") {
+			a.description = append(a.description, line)
+			continue
+		}
+		key, rest := splitWord(line[1:])
+		switch key {
+		case "summary":
+			a.summary = rest
+		case "param":
+			name, desc := splitWord(rest)
+			if strings.HasPrefix(name, "*") {
+				name = name[1:]
+				a.wildcard = name
+			}
+			a.params[name] = desc
+		case "query":
+			name, desc := splitWord(rest)
+			typ := "string"
+			if i := strings.IndexRune(name, ':'); i >= 0 {
+				name, typ = name[:i], name[i+1:]
+			}
+			a.queries = append(a.queries, query{name: name, typ: typ, description: desc})
+		case "body":
+			a.body = rest
+		case "response":
+			r := response{status: "200", contentType: "application/json", typ: rest}
+			if first, more := splitWord(r.typ); len(first) == 3 && strings.Trim(first, "0123456789") == "" {
+				r.status, r.typ = first, more
+			}
+			if first, more := splitWord(r.typ); strings.ContainsRune(first, '/') {
+				r.contentType, r.typ = first, more
+			}
+			a.responses = append(a.responses, r)
+		default:
+			return a, fmt.Errorf("unknown annotation: @%s", key)
+		}
+	}
+	return a, nil
+}
+
+func splitWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
+
+// usage is what the handler calls, found in the body
+type usage struct {
+	explain  bool
+	paged    bool
+	channels bool
+	derivErr bool
+	queries  []string
+	statuses map[string]bool
+}
+
+var statusCodes = map[string]string{
+	"StatusBadRequest":          "400",
+	"StatusNotFound":            "404",
+	"StatusInternalServerError": "500",
+	"StatusBadGateway":          "502",
+	"StatusServiceUnavailable":  "503",
+}
+
+func findUsage(fn *ast.FuncDecl) usage {
+	u := usage{statuses: map[string]bool{}}
+	seen := map[string]bool{}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			switch f := n.Fun.(type) {
+			case *ast.Ident:
+				switch f.Name {
+				case "explainDriver":
+					u.explain = true
+				case "responseCVEs":
+					u.paged = true
+				case "queryChannels":
+					u.channels = true
+				case "derivativeError":
+					u.derivErr = true
+				}
+			case *ast.SelectorExpr:
+				if f.Sel.Name == "QueryParam" && len(n.Args) == 1 {
+					if name, err := strconv.Unquote(exprString(n.Args[0])); err == nil && !seen[name] {
+						seen[name] = true
+						u.queries = append(u.queries, name)
+					}
+				}
+			}
+		case *ast.SelectorExpr:
+			if code, ok := statusCodes[n.Sel.Name]; ok && exprString(n.X) == "http" {
+				u.statuses[code] = true
+			}
+		}
+		return true
+	})
+	return u
+}
+
+// operation builds the operation of the route, and returns the path in the syntax of OpenAPI
+func (g *generator) operation(r route, fn *ast.FuncDecl, a annotations) (object, string, error) {
+	path := r.path
+	if strings.HasSuffix(path, "/*") {
+		if a.wildcard == "" {
+			return nil, "", fmt.Errorf("the name of * in %s is not annotated by @param *name", path)
+		}
+		path = strings.TrimSuffix(path, "*") + ":" + a.wildcard
+	}
+	params := []interface{}{}
+	for _, m := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		p := object{"name": m[1], "in": "path", "required": true, "schema": object{"type": "string"}}
+		if d := a.params[m[1]]; d != "" {
+			p["description"] = d
+		}
+		params = append(params, p)
+	}
+	path = pathParamRegexp.ReplaceAllString(path, "{$1}")
+
+	u := findUsage(fn)
+	queries := map[string]query{}
+	for _, q := range a.queries {
+		queries[q.name] = q
+	}
+	addQuery := func(name, typ, desc string) {
+		if q, ok := queries[name]; ok {
+			typ, desc = q.typ, q.description
+			delete(queries, name)
+		}
+		p := object{"name": name, "in": "query", "schema": object{"type": typ}}
+		if desc != "" {
+			p["description"] = desc
+		}
+		params = append(params, p)
+	}
+	for _, name := range u.queries {
+		addQuery(name, "string", "")
+	}
+	if u.channels {
+		addQuery("channel", "string", "The channels of the extended support, comma-separated, e.g. esm-infra,esm-apps or elts")
+	}
+	if u.paged {
+		addQuery("sort", "string", "Respond the page of the CVEs sorted by cve_id or date instead of the map of CVE-ID")
+		addQuery("offset", "integer", "The offset of the page")
+		addQuery("limit", "integer", "The size of the page. 0 is all")
+	}
+	if u.explain {
+		addQuery("debug", "boolean", `Wrap the response as {"result": the response, "debug": the queries and the durations}`)
+	}
+	// ?fields of responseFields, except GraphQL selecting the fields by the query
+	for _, res := range a.responses {
+		if res.status == "200" && res.contentType == "application/json" && r.path != "/graphql" {
+			addQuery("fields", "string", "Respond only the top-level fields of the records, comma-separated, e.g. cvss3,package_state")
+			// ?attribution of responseAttribution
+			if r.path != "/attributions" {
+				addQuery("attribution", "boolean", `Wrap the response as {"result": the response, "attributions": the licenses of the sources}`)
+			}
+			break
+		}
+	}
+	for _, q := range a.queries {
+		if _, ok := queries[q.name]; ok {
+			return nil, "", fmt.Errorf("?%s is annotated but not read", q.name)
+		}
+	}
+
+	op := object{"tags": []string{tag(r.path)}}
+	if a.summary != "" {
+		op["summary"] = a.summary
+	}
+	if len(a.description) > 0 {
+		op["description"] = strings.Join(a.description, "\n")
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	if r.secured {
+		op["security"] = []interface{}{object{"token": []string{}}}
+	}
+	if a.body != "" && r.method != "GET" && r.method != "HEAD" {
+		s, err := g.schemaOf(a.body)
+		if err != nil {
+			return nil, "", err
+		}
+		op["requestBody"] = object{"required": true, "content": object{"application/json": object{"schema": s}}}
+	}
+
+	responses := object{}
+	for _, res := range a.responses {
+		s, err := g.schemaOf(res.typ)
+		if err != nil {
+			return nil, "", err
+		}
+		if res.status == "200" && u.paged {
+			s = object{"oneOf": []interface{}{s, g.pageSchema(s)}}
+		}
+		content, _ := responses[res.status].(object)
+		if content == nil {
+			content = object{"description": statusText(res.status), "content": object{}}
+			responses[res.status] = content
+		}
+		content["content"].(object)[res.contentType] = object{"schema": s}
+	}
+	if _, ok := responses["200"]; !ok {
+		responses["200"] = object{"description": statusText("200")}
+	}
+	if u.derivErr {
+		responses["200"].(object)["headers"] = object{
+			"X-Gost-Not-Covered": object{
+				"description": "true if the package is not covered by the upstream of the derivative distribution, with no CVEs",
+				"schema":      object{"type": "string"},
+			},
+		}
+		u.statuses["404"], u.statuses["500"] = true, true
+	}
+	if r.secured {
+		u.statuses["401"], u.statuses["403"] = true, true
+	}
+	for code := range u.statuses {
+		if _, ok := responses[code]; ok {
+			continue
+		}
+		responses[code] = object{
+			"description": statusText(code),
+			"content":     object{"application/json": object{"schema": object{"$ref": "#/components/schemas/Error"}}},
+		}
+	}
+	op["responses"] = responses
+	return op, path, nil
+}
+
+// tag is the first segment of the path, e.g. redhat of /redhat/cves/:id
+func tag(path string) string {
+	seg := strings.Split(strings.TrimPrefix(path, "/"), "/")[0]
+	if strings.HasPrefix(seg, ":") {
+		return "derivatives"
+	}
+	return seg
+}
+
+func statusText(code string) string {
+	return map[string]string{
+		"200": "OK",
+		"400": "Bad Request",
+		"401": "Unauthorized",
+		"403": "Forbidden",
+		"404": "Not Found",
+		"500": "Internal Server Error",
+		"502": "Bad Gateway",
+		"503": "Service Unavailable",
+	}[code]
+}
+
+// pageSchema is the schema of models.CvePage of the CVEs of the map s
+func (g *generator) pageSchema(s object) object {
+	values := object{}
+	if v, ok := s["additionalProperties"].(object); ok {
+		values = v
+	}
+	return object{
+		"type": "object",
+		"properties": object{
+			"total":  object{"type": "integer"},
+			"offset": object{"type": "integer"},
+			"limit":  object{"type": "integer"},
+			"cves":   object{"type": "array", "items": values},
+		},
+	}
+}
+
+// schemaOf returns the schema of the Go type expression of an annotation, which refers to the imports of the server package
+func (g *generator) schemaOf(typ string) (object, error) {
+	e, err := parser.ParseExpr(typ)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %w", typ, err)
+	}
+	server, err := g.load(modulePath + "/server")
+	if err != nil {
+		return nil, err
+	}
+	imports := map[string]string{}
+	for _, f := range server.files {
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			imports[path[strings.LastIndex(path, "/")+1:]] = path
+		}
+	}
+	return g.schema(modulePath+"/server", imports, e)
+}
+
+// schema returns the schema of the type expression e in the package of importPath. imports are of the file of e.
+func (g *generator) schema(importPath string, imports map[string]string, e ast.Expr) (object, error) {
+	switch e := e.(type) {
+	case *ast.StarExpr:
+		return g.schema(importPath, imports, e.X)
+	case *ast.ParenExpr:
+		return g.schema(importPath, imports, e.X)
+	case *ast.ArrayType:
+		if id, ok := e.Elt.(*ast.Ident); ok && (id.Name == "byte" || id.Name == "uint8") {
+			return object{"type": "string", "format": "byte"}, nil
+		}
+		items, err := g.schema(importPath, imports, e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return object{"type": "array", "items": items}, nil
+	case *ast.MapType:
+		values, err := g.schema(importPath, imports, e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return object{"type": "object", "additionalProperties": values}, nil
+	case *ast.InterfaceType:
+		return object{}, nil
+	case *ast.StructType:
+		return g.structSchema(importPath, imports, e)
+	case *ast.Ident:
+		if s, ok := basicSchema(e.Name); ok {
+			return s, nil
+		}
+		return g.named(importPath, e.Name)
+	case *ast.SelectorExpr:
+		path := imports[exprString(e.X)]
+		switch path + "." + e.Sel.Name {
+		case "time.Time":
+			return object{"type": "string", "format": "date-time"}, nil
+		case "time.Duration":
+			return object{"type": "integer"}, nil
+		case "encoding/json.RawMessage":
+			return object{}, nil
+		}
+		if !strings.HasPrefix(path, modulePath+"/") {
+			return object{}, nil
+		}
+		return g.named(path, e.Sel.Name)
+	}
+	return nil, fmt.Errorf("unsupported type: %s", exprString(e))
+}
+
+func basicSchema(name string) (object, bool) {
+	switch name {
+	case "string":
+		return object{"type": "string"}, true
+	case "bool":
+		return object{"type": "boolean"}, true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune", "uintptr":
+		return object{"type": "integer"}, true
+	case "float32", "float64":
+		return object{"type": "number"}, true
+	case "error", "any":
+		return object{}, true
+	}
+	return nil, false
+}
+
+// named returns the reference to the component of the named struct, or the schema of the underlying type of the others
+func (g *generator) named(importPath, name string) (object, error) {
+	p, err := g.load(importPath)
+	if err != nil {
+		return nil, err
+	}
+	ts, ok := p.types[name]
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not found", importPath, name)
+	}
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		return g.schema(importPath, p.imports[ts], ts.Type)
+	}
+	if embedsTime(st, p.imports[ts]) {
+		return object{"type": "string", "format": "date-time"}, nil
+	}
+
+	key := importPath + "." + name
+	component, ok := g.names[key]
+	if !ok {
+		component = strings.ToUpper(name[:1]) + name[1:]
+		if _, taken := g.schemas[component]; taken {
+			component = strings.Title(p.name) + strings.ToUpper(name[:1]) + name[1:]
+		}
+		g.names[key] = component
+		// reserved before the fields, since the structs may refer to each other
+		g.schemas[component] = object{}
+		s, err := g.structSchema(importPath, p.imports[ts], st)
+		if err != nil {
+			return nil, err
+		}
+		if doc := docOf(p, ts); doc != "" {
+			s["description"] = doc
+		}
+		g.schemas[component] = s
+	}
+	return object{"$ref": "#/components/schemas/" + component}, nil
+}
+
+// docOf returns the doc comment of the type without the name, e.g. Mstime : the time of Microsoft -> the time of Microsoft
+func docOf(p *pkg, ts *ast.TypeSpec) string {
+	doc := ts.Doc
+	if doc == nil {
+		for _, f := range p.files {
+			for _, decl := range f.Decls {
+				if d, ok := decl.(*ast.GenDecl); ok && len(d.Specs) == 1 && d.Specs[0] == ts {
+					doc = d.Doc
+				}
+			}
+		}
+	}
+	if doc == nil {
+		return ""
+	}
+	text := strings.TrimSpace(doc.Text())
+	text = strings.TrimPrefix(text, ts.Name.Name)
+	text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), ":"))
+	return text
+}
+
+// embedsTime reports whether the struct is marshaled as time.Time by embedding it
+func embedsTime(st *ast.StructType, imports map[string]string) bool {
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			if sel, ok := f.Type.(*ast.SelectorExpr); ok && imports[exprString(sel.X)] == "time" && sel.Sel.Name == "Time" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// structSchema returns the schema of the struct by the keys of JSON. The fields of the embedded structs are of the struct.
+func (g *generator) structSchema(importPath string, imports map[string]string, st *ast.StructType) (object, error) {
+	props := object{}
+	if err := g.addFields(props, importPath, imports, st); err != nil {
+		return nil, err
+	}
+	return object{"type": "object", "properties": props}, nil
+}
+
+func (g *generator) addFields(props object, importPath string, imports map[string]string, st *ast.StructType) error {
+	for _, f := range st.Fields.List {
+		key, opts := "", ""
+		if f.Tag != nil {
+			tag, _ := strconv.Unquote(f.Tag.Value)
+			key = reflect.StructTag(tag).Get("json")
+			if i := strings.IndexRune(key, ','); i >= 0 {
+				key, opts = key[:i], key[i+1:]
+			}
+		}
+		if key == "-" && opts == "" {
+			continue
+		}
+
+		if len(f.Names) == 0 {
+			if key == "" {
+				if st, p, i, ok := g.embeddedStruct(importPath, imports, f.Type); ok {
+					if err := g.addFields(props, p, i, st); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			name := exprString(f.Type)
+			name = name[strings.LastIndex(name, ".")+1:]
+			if !ast.IsExported(name) {
+				continue
+			}
+			if key == "" {
+				key = name
+			}
+			s, err := g.schema(importPath, imports, f.Type)
+			if err != nil {
+				return err
+			}
+			props[key] = s
+			continue
+		}
+
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+			k := key
+			if k == "" {
+				k = n.Name
+			}
+			s, err := g.schema(importPath, imports, f.Type)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(opts, "string") {
+				s = object{"type": "string"}
+			}
+			props[k] = s
+		}
+	}
+	return nil
+}
+
+// embeddedStruct returns the struct of the embedded field in the module with its package and imports
+func (g *generator) embeddedStruct(importPath string, imports map[string]string, e ast.Expr) (*ast.StructType, string, map[string]string, bool) {
+	if s, ok := e.(*ast.StarExpr); ok {
+		e = s.X
+	}
+	name := ""
+	switch t := e.(type) {
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		path := imports[exprString(t.X)]
+		if !strings.HasPrefix(path, modulePath+"/") {
+			return nil, "", nil, false
+		}
+		importPath, name = path, t.Sel.Name
+	default:
+		return nil, "", nil, false
+	}
+	p, err := g.load(importPath)
+	if err != nil {
+		return nil, "", nil, false
+	}
+	ts, ok := p.types[name]
+	if !ok {
+		return nil, "", nil, false
+	}
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok || embedsTime(st, p.imports[ts]) {
+		return nil, "", nil, false
+	}
+	return st, importPath, p.imports[ts], true
+}
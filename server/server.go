@@ -1,13 +1,24 @@
 package server
 
 import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"expvar"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/inconshreveable/log15"
 	"github.com/knqyf263/gost/db"
+	"github.com/knqyf263/gost/fetcher"
+	"github.com/knqyf263/gost/models"
 	"github.com/knqyf263/gost/util"
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
@@ -15,7 +26,14 @@ import (
 )
 
 // Start starts CVE dictionary HTTP Server.
-func Start(logDir string, driver db.DB) error {
+// If breaker is not nil, the requests fail fast with 503 while the circuit is open.
+// cache is the CVE cache of driver, published to /debug/vars if not nil.
+func Start(logDir string, driver db.DB, breaker *db.CircuitBreaker, cache *db.CVECache, tlsConfig *tls.Config, tokens *APITokens) error {
+	selfTest, err := runSelfTest(driver)
+	if err != nil {
+		return err
+	}
+
 	e := echo.New()
 	e.Debug = viper.GetBool("debug")
 
@@ -24,34 +42,162 @@ func Start(logDir string, driver db.DB) error {
 	e.Use(middleware.Recover())
 
 	// setup access logger
-	logPath := filepath.Join(logDir, "access.log")
-	if _, err := os.Stat(logPath); os.IsNotExist(err) {
-		if _, err := os.Create(logPath); err != nil {
+	e.Use(middleware.RequestID())
+	var accessLogOut io.Writer = os.Stdout
+	if logPath := viper.GetString("access-log"); logPath != "-" {
+		if logPath == "" {
+			logPath = filepath.Join(logDir, "access.log")
+		}
+		f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
 			return err
 		}
+		defer f.Close()
+		accessLogOut = f
+	}
+	logger, err := accessLog(accessLogOut, viper.GetStringSlice("access-log-fields"))
+	if err != nil {
+		return err
+	}
+	e.Use(logger)
+	if tokens != nil {
+		e.Use(apiTokenAuth(tokens))
+		expvar.Publish("api_tokens", expvar.Func(func() interface{} { return tokens.Stats() }))
+	}
+	if cache != nil {
+		expvar.Publish("cve_cache", expvar.Func(func() interface{} { return cache.Stats() }))
+	}
+	e.Use(responseAttribution(driver))
+	e.Use(responseFields())
+	if breaker != nil {
+		var stale *staleCache
+		if viper.GetBool("serve-stale") {
+			stale = newStaleCache(viper.GetInt("serve-stale-entries"))
+			expvar.Publish("serve_stale", expvar.Func(func() interface{} { return stale.Stats() }))
+		}
+		e.Use(circuitBreaker(breaker, stale))
+		expvar.Publish("circuit_breaker", expvar.Func(func() interface{} { return breaker.Stats() }))
+	} else if viper.GetBool("serve-stale") {
+		log15.Warn("--serve-stale is ignored since the circuit breaker is disabled")
 	}
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0600)
+
+	spec, err := openAPISpec()
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
-		Output: f,
-	}))
 
 	// Routes
+	e.GET("/openapi.json", getOpenAPI(spec))
+	e.GET("/swagger", getSwaggerUI())
 	e.GET("/health", health())
+	e.GET("/ready", ready(selfTest, breaker))
+	e.GET("/healthz", healthz(driver))
+	e.GET("/readyz", readyz(driver, selfTest, breaker, viper.GetDuration("ready-max-age"), viper.GetStringSlice("ready-sources")))
+	e.GET("/stale", getStaleness(driver))
+	e.GET("/tombstones", getTombstones(driver))
+	e.GET("/attributions", getAttributions(driver))
 	e.GET("/redhat/cves/:id", getRedhatCve(driver))
+	e.GET("/redhat/bugzilla/:id", getRedhatCvesByBugzillaID(driver))
 	e.GET("/debian/cves/:id", getDebianCve(driver))
+	e.GET("/debian/bugs/:id", getDebianCvesByBugID(driver))
+	e.GET("/debian/cves/:id/advisories", getDebianAdvisories(driver))
 	e.GET("/ubuntu/cves/:id", getUbuntuCve(driver))
+	e.GET("/ubuntu/bugs/:tracker/:id", getUbuntuCvesByBugID(driver))
+	e.GET("/ubuntu/usns/:id", getCveIDsByUSN(driver))
+	e.GET("/amazon/cves/:id", getAmazonCve(driver))
+	e.GET("/alpine/cves/:id", getAlpineCve(driver))
+	e.GET("/wolfi/cves/:id", getWolfiCve(driver))
+	e.GET("/oracle/cves/:id", getOracleCve(driver))
+	e.GET("/rocky/cves/:id", getRockyCve(driver))
+	e.GET("/alma/cves/:id", getAlmaCve(driver))
+	e.GET("/photon/cves/:id", getPhotonCve(driver))
+	e.GET("/mariner/cves/:id", getMarinerCve(driver))
+	e.GET("/openeuler/cves/:id", getOpenEulerCve(driver))
+	e.GET("/anolis/cves/:id", getAnolisCve(driver))
 	e.GET("/microsoft/cves/:id", getMicrosoftCve(driver))
+	e.GET("/microsoft/kbs/:kbID/superseded", getSupersededKBs(driver))
+	e.GET("/microsoft/products", getMicrosoftProducts(driver))
+	e.GET("/microsoft/products/resolve", resolveMicrosoftProduct(driver))
+	e.GET("/microsoft/products/:productID/builds/:build/required-kbs", getRequiredKBsForBuild(driver))
+	e.GET("/nvd/cves/:id", getNvdCve(driver))
+	e.GET("/cveprogram/cves/:id", getCveProgramCve(driver))
+	e.GET("/kernel/cves/:id", getKernelCve(driver))
+	e.GET("/kernel/cves", getVulnerableKernelCves(driver))
+	e.GET("/kev/cves/:id", getKEV(driver))
+	e.GET("/epss/cves/:id", getEpss(driver))
+	e.GET("/exploits/cves/:id", getExploits(driver))
+	e.GET("/cwes/:id", getCwe(driver))
+	e.GET("/ghsa/advisories/:id", getGhsa(driver))
+	e.GET("/ghsa/cves/:id", getGhsaByCveID(driver))
+	e.GET("/ghsa/:ecosystem/advisories", getVulnerableGhsa(driver))
+	e.GET("/osv/:ecosystem/*", getOsv(driver))
+	e.GET("/govuln/modules/*", getVulnerableGoVulns(driver))
+	e.GET("/jvn/advisories/:id", getJvn(driver))
+	e.GET("/jvn/cves/:id", getJvnByCveID(driver))
+	e.GET("/psirt/cves/:id", getPsirtByCveID(driver))
+	e.GET("/psirt/:vendor/advisories/:id", getPsirt(driver))
+	e.GET("/psirt/:vendor/advisories", getVulnerablePsirt(driver))
+	e.GET("/apple/cves/:id", getAppleByCveID(driver))
+	e.GET("/apple/releases/:id", getApple(driver))
+	e.GET("/apple/:product/unfixed-releases", getUnfixedApple(driver))
+	e.GET("/cn/cves/:id", getCnByCveID(driver))
+	e.GET("/cn/:source/advisories/:id", getCn(driver))
+	e.GET("/advisorydb/cves/:id", getPackageAdvisoriesByCveID(driver))
+	e.GET("/advisorydb/:source/advisories/:id", getPackageAdvisory(driver))
+	e.GET("/advisorydb/packages/:ecosystem", getVulnerablePackageAdvisories(driver))
+	e.GET("/cves/:id", getCve(driver))
+	e.GET("/cves/:id/timeline", getTimeline(driver))
+	e.GET("/cves/:id/vulninfo", getVulnInfo(driver))
 	e.GET("/redhat/:release/pkgs/:name/unfixed-cves", getUnfixedCvesRedhat(driver))
+	e.GET("/redhat/:release/pkgs/:name/fixed-cves", getFixedCvesRedhat(driver))
 	e.GET("/debian/:release/pkgs/:name/unfixed-cves", getUnfixedCvesDebian(driver))
 	e.GET("/debian/:release/pkgs/:name/fixed-cves", getFixedCvesDebian(driver))
 	e.GET("/ubuntu/:release/pkgs/:name/unfixed-cves", getUnfixedCvesUbuntu(driver))
 	e.GET("/ubuntu/:release/pkgs/:name/fixed-cves", getFixedCvesUbuntu(driver))
+	e.GET("/ubuntu/snaps/:name/unfixed-cves", getUnfixedCvesUbuntuSnap(driver))
+	e.GET("/amazon/:release/pkgs/:name/unfixed-cves", getUnfixedCvesAmazon(driver))
+	e.GET("/amazon/:release/pkgs/:name/fixed-cves", getFixedCvesAmazon(driver))
+	e.GET("/alpine/:release/pkgs/:name/fixed-cves", getFixedCvesAlpine(driver))
+	e.GET("/wolfi/:distro/pkgs/:name/unfixed-cves", getUnfixedCvesWolfi(driver))
+	e.GET("/wolfi/:distro/pkgs/:name/fixed-cves", getFixedCvesWolfi(driver))
+	e.GET("/oracle/:release/pkgs/:name/fixed-cves", getFixedCvesOracle(driver))
+	e.GET("/rocky/:release/pkgs/:name/fixed-cves", getFixedCvesRocky(driver))
+	e.GET("/alma/:release/pkgs/:name/fixed-cves", getFixedCvesAlma(driver))
+	e.GET("/photon/:release/pkgs/:name/fixed-cves", getFixedCvesPhoton(driver))
+	e.GET("/mariner/:release/pkgs/:name/fixed-cves", getFixedCvesMariner(driver))
+	e.GET("/openeuler/:release/pkgs/:name/fixed-cves", getFixedCvesOpenEuler(driver))
+	e.GET("/anolis/:release/pkgs/:name/fixed-cves", getFixedCvesAnolis(driver))
+	e.GET("/derivatives", getDerivatives())
+	e.GET("/pkgs", getPackageNames(driver))
+	e.GET("/cveids/:source", getCveIDs(driver))
+	e.POST("/pkgs/unfixed-cves", getUnfixedCvesMulti(driver))
+	e.GET("/:family/:release/pkgs/:name/unfixed-cves", getUnfixedCvesDerivative(driver))
+	e.GET("/:family/:release/pkgs/:name/fixed-cves", getFixedCvesDerivative(driver))
+
+	if viper.GetBool("graphql") {
+		schema, err := newGraphQLSchema()
+		if err != nil {
+			return err
+		}
+		e.GET("/graphql", getGraphQL(schema, driver))
+		e.POST("/graphql", getGraphQL(schema, driver))
+	}
+
+	// /debug/vars has the command line and the counters of the tokens, so it is served only to the read and the admin tokens
+	if tokens != nil && tokens.admin {
+		admin := e.Group("/admin", adminAuth(tokens))
+		admin.GET("/raw/:source/:cveID", getRaw(driver))
+		admin.POST("/upsert/:source", upsertRaw(driver))
+		e.GET("/debug/vars", echo.WrapHandler(expvar.Handler()), adminAuth(tokens))
+	}
 
 	bindURL := fmt.Sprintf("%s:%s", viper.GetString("bind"), viper.GetString("port"))
+	if tlsConfig != nil {
+		log15.Info("Listening", "URL", bindURL, "TLS", true, "clientCert", tlsConfig.ClientCAs != nil)
+		e.TLSServer.Addr = bindURL
+		e.TLSServer.TLSConfig = tlsConfig
+		return e.StartServer(e.TLSServer)
+	}
 	log15.Info("Listening", "URL", bindURL)
 
 	e.Start(bindURL)
@@ -59,98 +205,1562 @@ func Start(logDir string, driver db.DB) error {
 }
 
 // Handler
+// @summary Check that the server is up
+// @response 200 text/plain string
 func health() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		return c.String(http.StatusOK, "")
 	}
 }
 
+// runSelfTest runs the self-test as --self-test.
+// strict refuses to start if the self-test fails, ready starts but /ready responds 503, and off skips the self-test.
+func runSelfTest(driver db.DB) (*models.SelfTestReport, error) {
+	mode := viper.GetString("self-test")
+	switch mode {
+	case "off":
+		return nil, nil
+	case "strict", "ready":
+	default:
+		return nil, fmt.Errorf("Unknown self-test mode: %s. Available: strict, ready, off", mode)
+	}
+
+	log15.Info("Running self-test")
+	report := db.SelfTest(driver)
+	for _, s := range report.Sources {
+		if !s.OK {
+			log15.Error("Self-test failed", "source", s.Source, "cveID", s.CveID, "err", s.Error)
+		}
+	}
+	if !report.OK && mode == "strict" {
+		return nil, errors.New("Failed to start server. Self-test failed. The DB may be corrupt or mismatched")
+	}
+	return &report, nil
+}
+
+// ready responds 503 if the self-test on start failed, or the circuit breaker is open
+// @summary Check that the server is ready to respond
+// @response models.SelfTestReport
+// @response 503 models.SelfTestReport
+func ready(report *models.SelfTestReport, breaker *db.CircuitBreaker) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if breaker != nil {
+			if stats := breaker.Stats(); stats.State == db.CircuitOpen {
+				return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{"circuit_breaker": stats})
+			}
+		}
+		if report == nil {
+			return c.String(http.StatusOK, "")
+		}
+		if !report.OK {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+// Handler
+// It responds 503 if any source is older than ?max-age (default: 48h), so that a blackbox probe can alert on stale data.
+// ?sources=debian,nvd checks the sources only, including the ones never fetched.
+// @summary Check that the sources are fetched recently
+// @query max-age The maximum age of the sources, e.g. 72h
+// @query sources The sources to check, comma-separated, e.g. debian,nvd
+// @response models.StalenessReport
+// @response 503 models.StalenessReport
+func getStaleness(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		maxAge := 48 * time.Hour
+		if v := c.QueryParam("max-age"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid max-age: %s", v))
+			}
+			maxAge = d
+		}
+		sources := []string{}
+		for _, s := range strings.Split(c.QueryParam("sources"), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				sources = append(sources, s)
+			}
+		}
+
+		report, err := db.CheckStaleness(driver, maxAge, sources, time.Now())
+		if err != nil {
+			log15.Error("Failed to check staleness", "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		if !report.OK {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+// Handler
+// The downstream caches poll it with the removed_at of the last tombstone as ?since to delete the CVEs removed by fetch --remove-missing.
+// @summary Get the CVEs removed from the sources
+// @query since The tombstones removed after the time are returned, in RFC3339, e.g. 2024-05-01T00:00:00Z. All the tombstones without it
+// @response []models.Tombstone
+func getTombstones(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		since := time.Time{}
+		if v := c.QueryParam("since"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid since: %s", v))
+			}
+			since = t
+		}
+		tombstones, err := driver.GetTombstones(since)
+		if err != nil {
+			log15.Error("Failed to get tombstones", "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(http.StatusOK, &tombstones)
+	}
+}
+
+// checkHealth queries the fetch times of the sources to check the DB, and reports their staleness by maxAge
+func checkHealth(driver db.DB, maxAge time.Duration, sources []string) models.HealthReport {
+	start := time.Now()
+	fetched, err := driver.GetFetchSources()
+	report := models.HealthReport{DB: models.DBHealth{OK: err == nil, Latency: time.Since(start).String()}}
+	if err != nil {
+		log15.Error("Health check: DB is unavailable", "err", err)
+		report.DB.Error = err.Error()
+		return report
+	}
+	staleness := db.Staleness(fetched, maxAge, sources, time.Now())
+	report.OK, report.Staleness = true, &staleness
+	return report
+}
+
+// Handler
+// It responds 503 only if the DB does not respond. The fetch times of the sources are reported without a limit of the age.
+// @summary Check that the server and the DB are up
+// @response models.HealthReport
+// @response 503 models.HealthReport
+func healthz(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := checkHealth(driver, 0, nil)
+		if !report.OK {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+// Handler
+// It responds 503 if the DB does not respond, the self-test on start failed, the circuit breaker is open,
+// or a source is older than --ready-max-age or never fetched.
+// @summary Check that the server is ready to respond with fresh data
+// @response models.HealthReport
+// @response 503 models.HealthReport
+func readyz(driver db.DB, selfTest *models.SelfTestReport, breaker *db.CircuitBreaker, maxAge time.Duration, sources []string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := checkHealth(driver, maxAge, sources)
+		if report.Staleness != nil && !report.Staleness.OK {
+			report.OK = false
+		}
+		if selfTest != nil {
+			report.SelfTest = &selfTest.OK
+			report.OK = report.OK && selfTest.OK
+		}
+		if breaker != nil {
+			report.CircuitBreaker = breaker.Stats().State
+			report.OK = report.OK && report.CircuitBreaker != db.CircuitOpen
+		}
+		if !report.OK {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+// circuitBreaker responds 503 with Retry-After without querying the DB while the circuit is open.
+// While half-open, one request at a time is passed as the probe. The endpoints not querying the DB are always passed.
+// If stale is not nil, the rejected requests are answered with the last successful responses kept in stale if any.
+func circuitBreaker(breaker *db.CircuitBreaker, stale *staleCache) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Path() {
+			case "/health", "/ready", "/healthz", "/readyz", "/debug/vars", "/openapi.json", "/swagger":
+				return next(c)
+			}
+			if !breaker.Allow() {
+				retryAfter := int(breaker.RetryAfter().Seconds()) + 1
+				c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				if stale != nil {
+					if ok, err := stale.respond(c); ok {
+						return err
+					}
+				}
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "DB is unavailable")
+			}
+			defer breaker.Done()
+			if stale != nil {
+				return stale.record(c, breaker, next)
+			}
+			return next(c)
+		}
+	}
+}
+
+// explainDriver returns the driver of the request, which records the queries when the request has ?debug=true
+func explainDriver(c echo.Context, driver db.DB) (db.DB, *db.Explain) {
+	driver = requestDriver(c, driver)
+	if c.QueryParam("debug") != "true" {
+		return driver, nil
+	}
+	explain := db.NewExplain()
+	return driver.WithExplain(explain), explain
+}
+
+// responseJSON wraps the result with the debug information if explain is not nil
+func responseJSON(c echo.Context, explain *db.Explain, result interface{}) error {
+	if explain == nil {
+		return c.JSON(http.StatusOK, result)
+	}
+	explain.Finish()
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": result,
+		"debug":  explain,
+	})
+}
+
+// responseCVEs responds the map of CVE-ID to CVE as it is.
+// If ?sort, ?offset or ?limit is given, it responds the page of the CVEs sorted by ?sort (cve_id or date).
+func responseCVEs(c echo.Context, explain *db.Explain, cves interface{}) error {
+	by, offset, limit := c.QueryParam("sort"), c.QueryParam("offset"), c.QueryParam("limit")
+	if by == "" && offset == "" && limit == "" {
+		return responseJSON(c, explain, cves)
+	}
+
+	p := models.CvePage{}
+	var err error
+	if offset != "" {
+		if p.Offset, err = strconv.Atoi(offset); err != nil || p.Offset < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid offset: %s", offset))
+		}
+	}
+	if limit != "" {
+		if p.Limit, err = strconv.Atoi(limit); err != nil || p.Limit < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid limit: %s", limit))
+		}
+	}
+	sorted, err := db.SortCVEs(cves, by)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	p.CVEs, p.Total = db.Paginate(sorted, p.Offset, p.Limit)
+	return responseJSON(c, explain, p)
+}
+
 // Handler
+// @summary Get the CVE of RedHat
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.RedhatCVE
 func getRedhatCve(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
 		cveid := c.Param("id")
 		cveDetail := driver.GetRedhat(cveid)
 		//TODO error
-		return c.JSON(http.StatusOK, &cveDetail)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVEs of RedHat by the bug of Red Hat Bugzilla
+// @param id The ID of the bug, e.g. 2030932
+// @response map[string]models.RedhatCVE
+func getRedhatCvesByBugzillaID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		bugzillaID := c.Param("id")
+		cveDetail := driver.GetRedhatByBugzillaID(bugzillaID)
+		return responseCVEs(c, explain, cveDetail)
 	}
 }
 
 // Handler
+// @summary Get the CVE of Debian
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.DebianCVE
 func getDebianCve(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
 		cveid := c.Param("id")
 		//TODO error
 		cveDetail := driver.GetDebian(cveid)
-		return c.JSON(http.StatusOK, &cveDetail)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the DSAs and DLAs of the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response []models.DebianAdvisory
+func getDebianAdvisories(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		advisories := driver.GetAdvisoriesDebian(cveid)
+		return responseJSON(c, explain, &advisories)
+	}
+}
+
+// Handler
+// @summary Get the CVE-IDs of the Ubuntu Security Notice
+// @param id The USN-ID, e.g. USN-5000-1
+// @response []string
+func getCveIDsByUSN(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		usnID := c.Param("id")
+		cveIDs := driver.GetCveIDsByUSN(usnID)
+		return responseJSON(c, explain, &cveIDs)
+	}
+}
+
+// Handler
+// @summary Get the CVE of all the sources
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.CveDetail
+func getCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetCveByID(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the timeline of the CVE over the sources
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response models.Timeline
+func getTimeline(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		timeline := db.GetTimeline(driver, cveid)
+		return responseJSON(c, explain, &timeline)
+	}
+}
+
+// Handler
+// @summary Get the CVE summarized over the sources
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response models.VulnInfo
+func getVulnInfo(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		info := db.GetVulnInfo(driver, cveid)
+		return responseJSON(c, explain, &info)
+	}
+}
+
+// Handler
+// @summary Get the CVEs of Debian by the bug of the Debian BTS
+// @param id The number of the bug, e.g. 1001478
+// @response map[string]models.DebianCVE
+func getDebianCvesByBugID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		bugID := c.Param("id")
+		cveDetail := driver.GetDebianByBugID(bugID)
+		return responseCVEs(c, explain, cveDetail)
 	}
 }
 
 // Handler
+// @summary Get the CVE of Ubuntu
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.UbuntuCVE
 func getUbuntuCve(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
 		cveid := c.Param("id")
 		// TODO error
 		cveDetail := driver.GetUbuntu(cveid)
-		return c.JSON(http.StatusOK, &cveDetail)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVEs of Ubuntu by the bug of the tracker
+// @param tracker The bug tracker, e.g. launchpad or debian
+// @param id The ID of the bug
+// @response map[string]models.UbuntuCVE
+func getUbuntuCvesByBugID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		tracker := c.Param("tracker")
+		bugID := c.Param("id")
+		cveDetail := driver.GetUbuntuByBugID(tracker, bugID)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of Amazon Linux
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.AmazonCVE
+func getAmazonCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		// TODO error
+		cveDetail := driver.GetAmazon(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of Wolfi and Chainguard
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.WolfiCVE
+func getWolfiCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetWolfi(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of Alpine Linux
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.AlpineCVE
+func getAlpineCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		// TODO error
+		cveDetail := driver.GetAlpine(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of Oracle Linux
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.OracleCVE
+func getOracleCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		// TODO error
+		cveDetail := driver.GetOracle(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of Rocky Linux
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.RockyCVE
+func getRockyCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		// TODO error
+		cveDetail := driver.GetRocky(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of AlmaLinux
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.AlmaCVE
+func getAlmaCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		// TODO error
+		cveDetail := driver.GetAlma(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of Photon OS
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.PhotonCVE
+func getPhotonCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetPhoton(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of CBL-Mariner
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.MarinerCVE
+func getMarinerCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetMariner(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of openEuler
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.OpenEulerCVE
+func getOpenEulerCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetOpenEuler(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of Anolis OS
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.AnolisCVE
+func getAnolisCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetAnolis(cveid)
+		return responseJSON(c, explain, &cveDetail)
 	}
 }
 
 // Handler
+// @summary Get the CVE of Microsoft
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @query arch The architecture to filter the products, e.g. x64, arm64 or x86
+// @response *models.MicrosoftCVE
 func getMicrosoftCve(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
 		cveid := c.Param("id")
 		//TODO error
 		cveDetail := driver.GetMicrosoft(cveid)
-		return c.JSON(http.StatusOK, &cveDetail)
+		if arch := c.QueryParam("arch"); arch != "" && cveDetail != nil {
+			filtered := db.FilterMicrosoftCveByArch(*cveDetail, db.NormalizeMicrosoftArch(arch))
+			cveDetail = &filtered
+		}
+		return responseJSON(c, explain, &cveDetail)
 	}
 }
 
 // Handler
-func getUnfixedCvesRedhat(driver db.DB) echo.HandlerFunc {
+// @summary Get the products of Microsoft
+// @query arch The architecture to filter the products, e.g. x64, arm64 or x86
+// @response []models.MicrosoftProduct
+func getMicrosoftProducts(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		release := util.Major(c.Param("release"))
-		pkgName := c.Param("name")
-		cveDetail := driver.GetUnfixedCvesRedhat(release, pkgName, false)
-		return c.JSON(http.StatusOK, &cveDetail)
+		driver, explain := explainDriver(c, driver)
+		products, err := driver.GetMicrosoftProducts()
+		if err != nil {
+			log15.Error("Failed to get Microsoft products", "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		products = db.FilterMicrosoftProductsByArch(products, db.NormalizeMicrosoftArch(c.QueryParam("arch")))
+		return responseJSON(c, explain, &products)
 	}
 }
 
 // Handler
-func getUnfixedCvesDebian(driver db.DB) echo.HandlerFunc {
+// @summary Resolve the product of Microsoft by the name
+// @query name The name of the product, e.g. Windows 10 Version 21H2
+// @query arch The architecture of the product, e.g. x64
+// @query limit:integer The maximum number of the matches. The default is 5
+// @response []models.MicrosoftProductMatch
+func resolveMicrosoftProduct(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		release := util.Major(c.Param("release"))
-		pkgName := c.Param("name")
-		cveDetail := driver.GetUnfixedCvesDebian(release, pkgName)
-		return c.JSON(http.StatusOK, &cveDetail)
+		driver, explain := explainDriver(c, driver)
+		name := c.QueryParam("name")
+		if name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+		}
+		limit := 5
+		if l := c.QueryParam("limit"); l != "" {
+			var err error
+			if limit, err = strconv.Atoi(l); err != nil || limit < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid limit: %s", l))
+			}
+		}
+		matches, err := db.ResolveMicrosoftProduct(driver, name, db.NormalizeMicrosoftArch(c.QueryParam("arch")), limit)
+		if err != nil {
+			log15.Error("Failed to resolve Microsoft product", "name", name, "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return responseJSON(c, explain, &matches)
 	}
 }
 
 // Handler
-func getFixedCvesDebian(driver db.DB) echo.HandlerFunc {
+// @summary Get the KBs superseded by the KB
+// @param kbID The KB-ID, e.g. 5009543 or KB5009543
+// @response []string
+func getSupersededKBs(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		release := util.Major(c.Param("release"))
-		pkgName := c.Param("name")
-		cveDetail := driver.GetFixedCvesDebian(release, pkgName)
-		return c.JSON(http.StatusOK, &cveDetail)
+		driver, explain := explainDriver(c, driver)
+		kbID := c.Param("kbID")
+		kbIDs, err := driver.GetSupersededKBs(kbID)
+		if err != nil {
+			if errors.Is(err, db.ErrInvalidKBID) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			log15.Error("Failed to get superseded KBs", "kbID", kbID, "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return responseJSON(c, explain, &kbIDs)
 	}
 }
 
 // Handler
-func getUnfixedCvesUbuntu(driver db.DB) echo.HandlerFunc {
+// @summary Get the KBs required for the build of the product
+// @param productID The product ID of Microsoft
+// @param build The build, e.g. 10.0.19044.1466
+// @response []models.MicrosoftKBBuild
+func getRequiredKBsForBuild(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		release := util.Major(c.Param("release"))
-		pkgName := c.Param("name")
-		cveDetail := driver.GetUnfixedCvesUbuntu(release, pkgName)
-		return c.JSON(http.StatusOK, &cveDetail)
+		driver, explain := explainDriver(c, driver)
+		productID, build := c.Param("productID"), c.Param("build")
+		kbs, err := driver.GetRequiredKBsForBuild(productID, build)
+		if err != nil {
+			if errors.Is(err, db.ErrInvalidBuild) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			log15.Error("Failed to get required KBs", "productID", productID, "build", build, "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return responseJSON(c, explain, &kbs)
 	}
 }
 
 // Handler
-func getFixedCvesUbuntu(driver db.DB) echo.HandlerFunc {
+// @summary Get the CVE of NVD
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.NvdCVE
+func getNvdCve(driver db.DB) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		release := util.Major(c.Param("release"))
-		pkgName := c.Param("name")
-		cveDetail := driver.GetFixedCvesUbuntu(release, pkgName)
-		return c.JSON(http.StatusOK, &cveDetail)
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetNvd(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of the CVE Program
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.CveProgramCVE
+func getCveProgramCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetCveProgram(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the CVE of the Linux kernel CNA
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.KernelCVE
+func getKernelCve(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetKernelCve(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// All CVEs of the kernel are returned without ?version
+// @summary Get the CVEs of the Linux kernel affecting the version
+// @query version The version of the kernel, e.g. 5.15.10
+// @response map[string]models.KernelCVE
+func getVulnerableKernelCves(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cves := db.GetVulnerableKernelCves(driver, c.QueryParam("version"))
+		return responseCVEs(c, explain, cves)
+	}
+}
+
+// Handler
+// @summary Get the entry of the CVE in the Known Exploited Vulnerabilities catalog of CISA
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.KEVEntry
+func getKEV(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetKEV(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the exploits of the CVE in Exploit-DB and Metasploit
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response []models.Exploit
+func getExploits(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		exploits := driver.GetExploits(cveid)
+		return responseJSON(c, explain, exploits)
+	}
+}
+
+// Handler
+// The CWE-ID is accepted as 79, CWE-79 or cwe-79
+// @summary Get the CWE
+// @param id The CWE-ID, e.g. 79 or CWE-79
+// @response *models.Cwe
+func getCwe(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cweID := db.NormalizeCweID(c.Param("id"))
+		if cweID == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid CWE-ID")
+		}
+		cwe := driver.GetCwe(cweID)
+		return responseJSON(c, explain, &cwe)
+	}
+}
+
+// Handler
+// @summary Get the EPSS score of the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response *models.EpssScore
+func getEpss(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		cveDetail := driver.GetEpss(cveid)
+		return responseJSON(c, explain, &cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the GitHub Security Advisory
+// @param id The GHSA-ID, e.g. GHSA-jfh8-c2jp-5v3q
+// @response *models.GhsaAdvisory
+func getGhsa(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		ghsaID := c.Param("id")
+		advisory := driver.GetGhsa(ghsaID)
+		return responseJSON(c, explain, &advisory)
+	}
+}
+
+// Handler
+// @summary Get the GitHub Security Advisories of the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response map[string]models.GhsaAdvisory
+func getGhsaByCveID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		advisories := driver.GetGhsaByCveID(cveid)
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// Package names may have slashes (e.g. github.com/gin-gonic/gin, @babel/core), so the package is in the query
+// @summary Get the GitHub Security Advisories affecting the package
+// @param ecosystem The ecosystem, e.g. npm, pip or go
+// @query package The name of the package
+// @query version The version of the package. All the advisories of the package without it
+// @response map[string]models.GhsaAdvisory
+func getVulnerableGhsa(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		pkgName := c.QueryParam("package")
+		if pkgName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "package is required")
+		}
+		advisories := db.GetVulnerableGhsa(driver, c.Param("ecosystem"), pkgName, c.QueryParam("version"))
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// @summary Get the advisory of JVN iPedia
+// @param id The JVNDB-ID, e.g. JVNDB-2021-005748
+// @response *models.JvnAdvisory
+func getJvn(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		jvnID := c.Param("id")
+		advisory := driver.GetJvn(jvnID)
+		return responseJSON(c, explain, &advisory)
+	}
+}
+
+// Handler
+// @summary Get the advisories of JVN iPedia of the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response map[string]models.JvnAdvisory
+func getJvnByCveID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		advisories := driver.GetJvnByCveID(cveid)
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// @summary Get the advisory of the vendor PSIRT
+// @param vendor The vendor, e.g. fortinet or cisco
+// @param id The ID of the advisory, e.g. FG-IR-22-398
+// @response *models.PsirtAdvisory
+func getPsirt(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		advisory := driver.GetPsirt(strings.ToLower(c.Param("vendor")), c.Param("id"))
+		return responseJSON(c, explain, &advisory)
+	}
+}
+
+// Handler
+// @summary Get the advisories of the vendor PSIRTs of the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response map[string]models.PsirtAdvisory
+func getPsirtByCveID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		advisories := driver.GetPsirtByCveID(cveid)
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// @summary Get the security release of Apple
+// @param id The ID of the release, e.g. 120894
+// @response *models.AppleSecurityRelease
+func getApple(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := driver.GetApple(c.Param("id"))
+		return responseJSON(c, explain, &release)
+	}
+}
+
+// Handler
+// @summary Get the security releases of Apple fixing the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response map[string]models.AppleSecurityRelease
+func getAppleByCveID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		releases := driver.GetAppleByCveID(cveid)
+		return responseCVEs(c, explain, releases)
+	}
+}
+
+// Handler
+// The version (e.g. 14.3.1) or the build (e.g. 23D60) is in the query
+// @summary Get the security releases of Apple not applied to the version of the product
+// @param product The product, e.g. macos or ios
+// @query version The version, e.g. 14.3.1
+// @query build The build, e.g. 23D60
+// @response map[string]models.AppleSecurityRelease
+func getUnfixedApple(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		product := c.Param("product")
+		releases, err := db.GetUnfixedApple(driver, product, c.QueryParam("version"), c.QueryParam("build"))
+		if err != nil {
+			if errors.Is(err, db.ErrInvalidAppleVersion) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			log15.Error("Failed to get unfixed Apple security releases", "product", product, "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return responseCVEs(c, explain, releases)
+	}
+}
+
+// Handler
+// @summary Get the advisory of the Chinese vulnerability database
+// @param source The database, e.g. cnnvd
+// @param id The ID of the advisory, e.g. CNNVD-202112-799
+// @response *models.CnAdvisory
+func getCn(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		advisory := driver.GetCn(strings.ToLower(c.Param("source")), c.Param("id"))
+		return responseJSON(c, explain, &advisory)
+	}
+}
+
+// Handler
+// @summary Get the advisories of the Chinese vulnerability databases of the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response map[string]models.CnAdvisory
+func getCnByCveID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		advisories := driver.GetCnByCveID(cveid)
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// @summary Get the advisory of the package advisory database
+// @param source The database, e.g. rubysec
+// @param id The ID of the advisory, e.g. CVE-2020-8164
+// @response *models.PackageAdvisory
+func getPackageAdvisory(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		advisory := driver.GetPackageAdvisory(strings.ToLower(c.Param("source")), c.Param("id"))
+		return responseJSON(c, explain, &advisory)
+	}
+}
+
+// Handler
+// @summary Get the advisories of the package advisory databases of the CVE
+// @param id The CVE-ID, e.g. CVE-2021-44228
+// @response map[string]models.PackageAdvisory
+func getPackageAdvisoriesByCveID(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveid := c.Param("id")
+		advisories := driver.GetPackageAdvisoriesByCveID(cveid)
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// The package is in the query as GHSA, since npm package names may have slashes (e.g. @babel/core)
+// @summary Get the advisories of the package advisory databases affecting the package
+// @param ecosystem The ecosystem, e.g. rubygems or npm
+// @query package The name of the package
+// @query version The version of the package
+// @response map[string]models.PackageAdvisory
+func getVulnerablePackageAdvisories(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		pkgName := c.QueryParam("package")
+		if pkgName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "package is required")
+		}
+		advisories := db.GetVulnerablePackageAdvisories(driver, c.Param("ecosystem"), pkgName, c.QueryParam("version"))
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// Product names have spaces (e.g. Cisco IOS XE Software), so the product is in the query
+// @summary Get the advisories of the vendor PSIRT affecting the product
+// @param vendor The vendor, e.g. fortinet or cisco
+// @query product The name of the product, e.g. FortiOS
+// @query version The version of the product
+// @response map[string]models.PsirtAdvisory
+func getVulnerablePsirt(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		product := c.QueryParam("product")
+		if product == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "product is required")
+		}
+		advisories := db.GetVulnerablePsirt(driver, c.Param("vendor"), product, c.QueryParam("version"))
+		return responseCVEs(c, explain, advisories)
+	}
+}
+
+// Handler
+// The rest of the path is the package name, which may have slashes (e.g. github.com/gin-gonic/gin)
+// If osv-passthrough is set and no vulnerability is found locally, api.osv.dev is queried.
+// @summary Get the OSV entries affecting the package
+// @param ecosystem The ecosystem of OSV, e.g. PyPI or Go
+// @param *package The name of the package
+// @query version The version of the package
+// @response map[string][]json.RawMessage
+func getOsv(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		ecosystem := db.NormalizeOsvEcosystem(c.Param("ecosystem"))
+		pkgName := c.Param("*")
+		if pkgName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "package is required")
+		}
+		ver := c.QueryParam("version")
+
+		vulns, err := db.GetOsv(driver, ecosystem, pkgName, ver)
+		if err != nil {
+			log15.Error("Failed to get OSV", "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get OSV")
+		}
+		if len(vulns) == 0 && viper.GetBool("osv-passthrough") {
+			if vulns, err = fetcher.QueryOsvAPI(ecosystem, pkgName, ver); err != nil {
+				log15.Error("Failed to query OSV API", "err", err)
+				return echo.NewHTTPError(http.StatusBadGateway, "Failed to query OSV API")
+			}
+		}
+		return responseJSON(c, explain, map[string][]json.RawMessage{"vulns": vulns})
+	}
+}
+
+// Handler
+// The rest of the path is the module path (e.g. golang.org/x/net, stdlib)
+// @summary Get the Go vulnerabilities affecting the module
+// @param *module The module path, e.g. golang.org/x/net or stdlib
+// @query version The version of the module, e.g. v0.7.0
+// @response map[string]models.GoVuln
+func getVulnerableGoVulns(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		modulePath := c.Param("*")
+		if modulePath == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "module path is required")
+		}
+		vulns := db.GetVulnerableGoVulns(driver, modulePath, c.QueryParam("version"))
+		return responseCVEs(c, explain, vulns)
+	}
+}
+
+// Handler
+// @summary Get the unfixed CVEs of the package of RedHat
+// @param release The release, e.g. 8 or 8.4
+// @param name The name of the source package
+// @query module The module stream of the package, e.g. nodejs:14
+// @response map[string]models.RedhatCVE
+func getUnfixedCvesRedhat(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := util.Major(c.Param("release"))
+		pkgName := c.Param("name")
+		if module := c.QueryParam("module"); module != "" {
+			// the package of the module stream, e.g. nodejs:14/nodejs
+			pkgName = module + "/" + pkgName
+		}
+		cveDetail := driver.GetUnfixedCvesRedhat(release, pkgName, false)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of RedHat
+// @param release The release, e.g. 8 or 8.4
+// @param name The name of the source package
+// @response map[string]models.RedhatCVE
+func getFixedCvesRedhat(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := util.Major(c.Param("release"))
+		cveDetail := driver.GetFixedCvesRedhat(release, c.Param("name"))
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the unfixed CVEs of the package of Debian
+// @param release The release, e.g. 12 or bookworm
+// @param name The name of the source package
+// @response map[string]models.DebianCVE
+func getUnfixedCvesDebian(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeDebianRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetUnfixedCvesDebian(release, pkgName, queryChannels(c)...)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Debian
+// @param release The release, e.g. 12 or bookworm
+// @param name The name of the source package
+// @response map[string]models.DebianCVE
+func getFixedCvesDebian(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeDebianRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesDebian(release, pkgName, queryChannels(c)...)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the unfixed CVEs of the package of Ubuntu
+// @param release The release, e.g. 22.04 or jammy
+// @param name The name of the source package
+// @response map[string]models.UbuntuCVE
+func getUnfixedCvesUbuntu(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeUbuntuRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetUnfixedCvesUbuntu(release, pkgName, queryChannels(c)...)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Ubuntu
+// @param release The release, e.g. 22.04 or jammy
+// @param name The name of the source package
+// @response map[string]models.UbuntuCVE
+func getFixedCvesUbuntu(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeUbuntuRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesUbuntu(release, pkgName, queryChannels(c)...)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the unfixed CVEs of the snap of Ubuntu
+// @param name The name of the snap
+// @response map[string]models.UbuntuCVE
+func getUnfixedCvesUbuntuSnap(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveDetail := driver.GetUnfixedCvesUbuntuSnap(c.Param("name"))
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the unfixed CVEs of the package of Amazon Linux
+// @param release The release, e.g. 2 or 2023
+// @param name The name of the source package
+// @response map[string]models.AmazonCVE
+func getUnfixedCvesAmazon(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeAmazonRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetUnfixedCvesAmazon(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Amazon Linux
+// @param release The release, e.g. 2 or 2023
+// @param name The name of the source package
+// @response map[string]models.AmazonCVE
+func getFixedCvesAmazon(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeAmazonRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesAmazon(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Alpine Linux
+// @param release The release, e.g. 3.18
+// @param name The name of the source package
+// @response map[string]models.AlpineCVE
+func getFixedCvesAlpine(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeAlpineRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesAlpine(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// The distro is wolfi or chainguard
+// @summary Get the unfixed CVEs of the package of Wolfi or Chainguard
+// @param distro wolfi or chainguard
+// @param name The name of the package
+// @response map[string]models.WolfiCVE
+func getUnfixedCvesWolfi(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		distro := strings.ToLower(c.Param("distro"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetUnfixedCvesWolfi(distro, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Wolfi or Chainguard
+// @param distro wolfi or chainguard
+// @param name The name of the package
+// @response map[string]models.WolfiCVE
+func getFixedCvesWolfi(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		distro := strings.ToLower(c.Param("distro"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesWolfi(distro, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Oracle Linux
+// @param release The release, e.g. 8
+// @param name The name of the source package
+// @response map[string]models.OracleCVE
+func getFixedCvesOracle(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeOracleRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesOracle(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Rocky Linux
+// @param release The release, e.g. 8
+// @param name The name of the source package
+// @response map[string]models.RockyCVE
+func getFixedCvesRocky(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeRockyRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesRocky(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of AlmaLinux
+// @param release The release, e.g. 8
+// @param name The name of the source package
+// @response map[string]models.AlmaCVE
+func getFixedCvesAlma(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeAlmaRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesAlma(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Photon OS
+// @param release The release, e.g. 4.0
+// @param name The name of the source package
+// @response map[string]models.PhotonCVE
+func getFixedCvesPhoton(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizePhotonRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesPhoton(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of CBL-Mariner
+// @param release The release, e.g. 2.0
+// @param name The name of the source package
+// @response map[string]models.MarinerCVE
+func getFixedCvesMariner(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeMarinerRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesMariner(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of openEuler
+// @param release The release, e.g. 22.03-LTS
+// @param name The name of the source package
+// @response map[string]models.OpenEulerCVE
+func getFixedCvesOpenEuler(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeOpenEulerRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesOpenEuler(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of Anolis OS
+// @param release The release, e.g. 8
+// @param name The name of the source package
+// @response map[string]models.AnolisCVE
+func getFixedCvesAnolis(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		release := db.NormalizeAnolisRelease(c.Param("release"))
+		pkgName := c.Param("name")
+		cveDetail := driver.GetFixedCvesAnolis(release, pkgName)
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the derivative distributions mapped to the upstreams
+// @response []db.Derivative
+func getDerivatives() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, db.Derivatives())
+	}
+}
+
+// Handler
+// @summary Get the unfixed CVEs of the package of the distribution, including the derivatives
+// @param family The distribution, e.g. linuxmint or ubuntu
+// @param release The release of the distribution, e.g. 21.2
+// @param name The name of the source package
+// @response map[string]interface{}
+func getUnfixedCvesDerivative(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveDetail, err := db.GetUnfixedCves(driver, c.Param("family"), c.Param("release"), c.Param("name"), queryChannels(c)...)
+		if err != nil {
+			return derivativeError(c, err)
+		}
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the fixed CVEs of the package of the distribution, including the derivatives
+// @param family The distribution, e.g. linuxmint or ubuntu
+// @param release The release of the distribution, e.g. 21.2
+// @param name The name of the source package
+// @response map[string]interface{}
+func getFixedCvesDerivative(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		driver, explain := explainDriver(c, driver)
+		cveDetail, err := db.GetFixedCves(driver, c.Param("family"), c.Param("release"), c.Param("name"), queryChannels(c)...)
+		if err != nil {
+			return derivativeError(c, err)
+		}
+		return responseCVEs(c, explain, cveDetail)
+	}
+}
+
+// Handler
+// @summary Get the names of the packages of the distribution
+// @query distro The distribution, e.g. debian
+// @query release The release. All the releases without it
+// @query prefix The prefix of the names
+// @response []string
+func getPackageNames(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		distro := c.QueryParam("distro")
+		if distro == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "distro is required")
+		}
+		driver, explain := explainDriver(c, driver)
+		names, err := db.GetPackageNames(driver, distro, c.QueryParam("release"), c.QueryParam("prefix"))
+		if err != nil {
+			return derivativeError(c, err)
+		}
+		return responseJSON(c, explain, &names)
+	}
+}
+
+// cveIDsFlushLines is the number of the lines of GET /cveids/:source written between the flushes
+const cveIDsFlushLines = 1000
+
+// Handler
+// @summary Stream all the CVE-IDs of the source
+// @param source The source, e.g. debian or nvd
+// @query format text (one CVE-ID per line, the default) or ndjson
+// @response 200 text/plain string
+// @response 200 application/x-ndjson map[string]string
+func getCveIDs(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		source := c.Param("source")
+		format := c.QueryParam("format")
+		contentType := echo.MIMETextPlainCharsetUTF8
+		switch format {
+		case "", "text":
+		case "ndjson":
+			contentType = "application/x-ndjson"
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown format: %s", format))
+		}
+
+		// The header is written with the first CVE-ID, so an error before it is responded as usual
+		res := c.Response()
+		lines := 0
+		err := driver.ForEachCveID(source, func(cveID string) error {
+			if lines == 0 {
+				res.Header().Set(echo.HeaderContentType, contentType)
+				res.WriteHeader(http.StatusOK)
+			}
+			line := cveID + "\n"
+			if format == "ndjson" {
+				b, err := json.Marshal(map[string]string{"cve_id": cveID})
+				if err != nil {
+					return err
+				}
+				line = string(b) + "\n"
+			}
+			if _, err := res.Write([]byte(line)); err != nil {
+				return err
+			}
+			if lines++; lines%cveIDsFlushLines == 0 {
+				res.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			if lines > 0 {
+				log15.Error("Failed to stream CVE-IDs", "source", source, "err", err)
+				return nil
+			}
+			if errors.Is(err, db.ErrUnknownSource) {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unknown source: %s", source))
+			}
+			log15.Error("Failed to get CVE-IDs", "source", source, "err", err)
+			return err
+		}
+		if lines == 0 {
+			return c.Blob(http.StatusOK, contentType, nil)
+		}
+		return nil
+	}
+}
+
+// maxBulkPackages is the maximum number of the packages in a request of POST /pkgs/unfixed-cves
+const maxBulkPackages = 1000
+
+// bulkPackagesRequest : the body of POST /pkgs/unfixed-cves
+type bulkPackagesRequest struct {
+	Family   string   `json:"family"`
+	Release  string   `json:"release"`
+	Packages []string `json:"packages"`
+	Channels []string `json:"channels"`
+}
+
+// Handler
+// @summary Get the unfixed CVEs of many packages at once
+// @body bulkPackagesRequest
+// @response *models.PackagesCves
+func getUnfixedCvesMulti(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := bulkPackagesRequest{}
+		if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid body: %s", err))
+		}
+		if req.Family == "" || len(req.Packages) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "family and packages are required")
+		}
+		if len(req.Packages) > maxBulkPackages {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Too many packages: %d > %d", len(req.Packages), maxBulkPackages))
+		}
+
+		driver, explain := explainDriver(c, driver)
+		result, err := db.GetUnfixedCvesMulti(driver, req.Family, req.Release, req.Packages, req.Channels...)
+		if err != nil {
+			return derivativeError(c, err)
+		}
+		return responseJSON(c, explain, result)
+	}
+}
+
+// queryChannels returns the channels of the extended support in ?channel= (e.g. esm-infra,esm-apps or elts)
+func queryChannels(c echo.Context) []string {
+	channels := []string{}
+	for _, ch := range strings.Split(c.QueryParam("channel"), ",") {
+		if ch = strings.TrimSpace(ch); ch != "" {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
+// derivativeError responds 404 for the family or the release of a derivative distribution not mapped.
+// The packages not covered by the upstream respond no CVEs with the header X-Gost-Not-Covered, not to be taken as not vulnerable silently.
+func derivativeError(c echo.Context, err error) error {
+	if errors.Is(err, db.ErrNotCovered) {
+		c.Response().Header().Set("X-Gost-Not-Covered", "true")
+		return c.JSON(http.StatusOK, map[string]interface{}{})
+	}
+	if errors.Is(err, db.ErrUnknownSource) || errors.Is(err, db.ErrUnknownRelease) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	log15.Error("Failed to get CVEs of the derivative", "err", err)
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// Handler
+// @summary Get the document of the source of the CVE as it is stored
+// @param source The source, e.g. redhat or debian
+// @param cveID The CVE-ID
+// @response map[string]interface{}
+func getRaw(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		source := c.Param("source")
+		cveid := c.Param("cveID")
+		raw, err := driver.GetRaw(source, cveid)
+		if err != nil {
+			if errors.Is(err, db.ErrUnknownSource) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			log15.Error("Failed to get raw document", "source", source, "cveID", cveid, "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		if raw == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("%s is not found in %s", cveid, source))
+		}
+		return c.JSONBlob(http.StatusOK, raw)
+	}
+}
+
+// Handler
+// @summary Load the document of the source into the DB
+// @param source The source, e.g. redhat or debian
+// @body interface{}
+// @response map[string]interface{}
+func upsertRaw(driver db.DB) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		source := c.Param("source")
+		doc, err := ioutil.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		cveIDs, err := db.Hotload(driver, source, doc)
+		if err != nil {
+			if errors.Is(err, db.ErrUnknownSource) || errors.Is(err, db.ErrInvalidDocument) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			log15.Error("Failed to hotload document", "source", source, "err", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		log15.Info("Hotloaded", "source", source, "cveIDs", cveIDs)
+		return c.JSON(http.StatusOK, map[string]interface{}{"source": source, "cve_ids": cveIDs})
 	}
 }
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knqyf263/gost/db"
+	"github.com/labstack/echo"
+)
+
+// staleMaxBodySize is the max size of a response kept for serve-stale, so that a huge page does not occupy the memory
+const staleMaxBodySize = 1 << 20
+
+// staleCache keeps the last successful responses of the GET requests in memory,
+// and answers the same requests from them while the circuit breaker is open (e.g. during Redis maintenance).
+type staleCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	stats   staleStats
+}
+
+type staleEntry struct {
+	key         string
+	contentType string
+	body        []byte
+	storedAt    time.Time
+}
+
+// staleStats : the metric of staleCache
+type staleStats struct {
+	Entries int `json:"entries"`
+	// Served is the number of the requests answered with the stale responses
+	Served int64 `json:"served"`
+	// Missed is the number of the requests rejected while open since no stale response is kept
+	Missed int64 `json:"missed"`
+}
+
+func newStaleCache(maxEntries int) *staleCache {
+	return &staleCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// staleKey returns the key of the request, or empty if the response of the request is not kept
+func staleKey(c echo.Context) string {
+	if c.Request().Method != http.MethodGet || c.QueryParam("debug") == "true" || strings.HasPrefix(c.Path(), "/admin") {
+		return ""
+	}
+	return c.Request().URL.RequestURI()
+}
+
+func (s *staleCache) store(key, contentType string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elm, ok := s.entries[key]; ok {
+		s.order.Remove(elm)
+	}
+	s.entries[key] = s.order.PushFront(&staleEntry{key: key, contentType: contentType, body: body, storedAt: time.Now()})
+	for s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*staleEntry).key)
+	}
+}
+
+func (s *staleCache) load(key string) (*staleEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elm, ok := s.entries[key]
+	if !ok {
+		s.stats.Missed++
+		return nil, false
+	}
+	s.stats.Served++
+	return elm.Value.(*staleEntry), true
+}
+
+// Stats returns the number of the responses kept and the counters
+func (s *staleCache) Stats() staleStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.Entries = s.order.Len()
+	return stats
+}
+
+// record passes the request to next, and keeps the response if it succeeded without a failure of the DB.
+// Since most handlers respond an empty result on a DB error, the response is not kept while the breaker counts failures.
+func (s *staleCache) record(c echo.Context, breaker *db.CircuitBreaker, next echo.HandlerFunc) error {
+	key := staleKey(c)
+	if key == "" {
+		return next(c)
+	}
+
+	res := c.Response()
+	w := &staleRecorder{ResponseWriter: res.Writer}
+	res.Writer = w
+	defer func() { res.Writer = w.ResponseWriter }()
+
+	if err := next(c); err != nil {
+		return err
+	}
+	if res.Status != http.StatusOK || w.overflow {
+		return nil
+	}
+	if stats := breaker.Stats(); stats.State != db.CircuitClosed || stats.Failures > 0 {
+		return nil
+	}
+	s.store(key, res.Header().Get(echo.HeaderContentType), w.body.Bytes())
+	return nil
+}
+
+// respond answers the request with the stale response if kept.
+// The response has the Warning and Age headers, so that the clients can tell it from a fresh one.
+func (s *staleCache) respond(c echo.Context) (bool, error) {
+	key := staleKey(c)
+	if key == "" {
+		return false, nil
+	}
+	e, ok := s.load(key)
+	if !ok {
+		return false, nil
+	}
+	h := c.Response().Header()
+	h.Set("Warning", `110 gost "Response is Stale"`)
+	h.Set("Age", strconv.Itoa(int(time.Since(e.storedAt).Seconds())))
+	h.Set("X-Gost-Stale-Since", e.storedAt.UTC().Format(http.TimeFormat))
+	return true, c.Blob(http.StatusOK, e.contentType, e.body)
+}
+
+// staleRecorder copies the body written to ResponseWriter up to staleMaxBodySize
+type staleRecorder struct {
+	http.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *staleRecorder) Write(b []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(b) > staleMaxBodySize {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *staleRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
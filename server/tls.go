@@ -0,0 +1,48 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"golang.org/x/xerrors"
+)
+
+// NewTLSConfig returns the TLS config of the server with the certificate and the key in PEM.
+// If caFile is given, the clients must present a certificate signed by the CAs in it (mutual TLS).
+// It returns nil if neither the certificate nor the key is given.
+func NewTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		if caFile != "" {
+			return nil, xerrors.New("--tls-ca requires --tls-cert and --tls-key")
+		}
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, xerrors.New("Both --tls-cert and --tls-key are required")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to load the certificate. cert: %s, key: %s, err: %w", certFile, keyFile, err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if caFile == "" {
+		return config, nil
+	}
+
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to read the CA certificates. path: %s, err: %w", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, xerrors.Errorf("No CA certificate in PEM. path: %s", caFile)
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// ParseDate parses the date of an upstream with the layouts in the order, and returns it in UTC.
+// The date without the time zone (e.g. 2021-03-25T00:00:00) is taken as UTC,
+// so the dates of the sources are compared with each other regardless of the time zone of the upstream and the host.
+func ParseDate(date string, layouts ...string) (time.Time, error) {
+	date = strings.TrimSpace(date)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, xerrors.Errorf("Failed to parse date: %s, layouts: %q", date, layouts)
+}
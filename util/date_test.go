@@ -0,0 +1,77 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	var tests = []struct {
+		name    string
+		in      string
+		layouts []string
+		out     time.Time
+		wantErr bool
+	}{
+		{
+			name:    "Red Hat public_date",
+			in:      "2021-03-25T00:00:00Z",
+			layouts: []string{time.RFC3339, "2006-01-02T15:04:05"},
+			out:     time.Date(2021, 3, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Red Hat public_date without time zone",
+			in:      "2021-03-25T00:00:00",
+			layouts: []string{time.RFC3339, "2006-01-02T15:04:05"},
+			out:     time.Date(2021, 3, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "MSRC with offset",
+			in:      "2023-01-10T08:00:00-08:00",
+			layouts: []string{time.RFC3339, "2006-01-02T15:04:05"},
+			out:     time.Date(2023, 1, 10, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "MSRC bulletin",
+			in:      "3-8-16",
+			layouts: []string{"1/2/2006", "1-2-06"},
+			out:     time.Date(2016, 3, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Debian DSA",
+			in:      "21 Mar 2021",
+			layouts: []string{"2 Jan 2006"},
+			out:     time.Date(2021, 3, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "EPSS score_date",
+			in:      "2023-03-07T00:00:00+0900",
+			layouts: []string{"2006-01-02T15:04:05-0700"},
+			out:     time.Date(2023, 3, 6, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "spaces",
+			in:      " 2023-03-07\n",
+			layouts: []string{"2006-01-02"},
+			out:     time.Date(2023, 3, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "unknown layout",
+			in:      "2023/03/07",
+			layouts: []string{"2006-01-02"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ParseDate(tt.in, tt.layouts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !out.Equal(tt.out) || out.Location() != time.UTC {
+				t.Errorf("expected: %s\n  actual: %s", tt.out, out)
+			}
+		})
+	}
+}
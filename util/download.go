@@ -0,0 +1,42 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DownloadURL writes HTTP response body to w as it is received, for the files too large to hold in memory (e.g. a pre-built DB).
+// The mirrors of the URL are tried if the upstream fails before the body is received.
+func DownloadURL(u string, w io.Writer) error {
+	return WithMirrors(u, func(u string) error { return downloadURL(u, w) })
+}
+
+func downloadURL(u string, w io.Writer) error {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if httpProxy != "" {
+		proxy, err := url.Parse(httpProxy)
+		if err != nil {
+			return fmt.Errorf("Invalid proxy. err: %v, proxy: %s", err, httpProxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get(u)
+	if err != nil {
+		return &FallbackError{Err: fmt.Errorf("HTTP error. err: %v, url: %s", err, u)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		err := fmt.Errorf("HTTP error. status code: %d, url: %s", resp.StatusCode, u)
+		if fallbackStatus(resp.StatusCode) {
+			return &FallbackError{StatusCode: resp.StatusCode, Err: err}
+		}
+		return err
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("Failed to download. err: %v, url: %s", err, u)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"golang.org/x/xerrors"
+)
+
+// Mirror : the mirrors of an upstream endpoint, configured by mirrors in the config file
+type Mirror struct {
+	// Upstream is the prefix of the URLs of the upstream (e.g. https://security-tracker.debian.org/tracker/)
+	Upstream string `mapstructure:"upstream"`
+	// URLs are the prefixes replacing Upstream, tried in order when the upstream fails
+	URLs []string `mapstructure:"urls"`
+}
+
+// mirrors is used by FetchURL, FetchConcurrently, DownloadURL and git.CloneOrPull
+var mirrors []Mirror
+
+// SetMirrors sets the mirrors of the upstreams. The longest Upstream matching a URL is used
+func SetMirrors(ms []Mirror) error {
+	for _, m := range ms {
+		if _, err := url.Parse(m.Upstream); err != nil || m.Upstream == "" {
+			return xerrors.Errorf("Invalid upstream of mirrors: %q", m.Upstream)
+		}
+		if len(m.URLs) == 0 {
+			return xerrors.Errorf("No URL of the mirrors of %s", m.Upstream)
+		}
+		for _, u := range m.URLs {
+			if _, err := url.Parse(u); err != nil || u == "" {
+				return xerrors.Errorf("Invalid mirror of %s: %q", m.Upstream, u)
+			}
+		}
+	}
+	mirrors = ms
+	return nil
+}
+
+// MirrorURLs returns the URL and the same URL of its mirrors, in the order tried
+func MirrorURLs(u string) []string {
+	var found *Mirror
+	for i, m := range mirrors {
+		if strings.HasPrefix(u, m.Upstream) && (found == nil || len(m.Upstream) > len(found.Upstream)) {
+			found = &mirrors[i]
+		}
+	}
+	urls := []string{u}
+	if found == nil {
+		return urls
+	}
+	for _, m := range found.URLs {
+		urls = append(urls, m+strings.TrimPrefix(u, found.Upstream))
+	}
+	return urls
+}
+
+// FallbackError is the error of a request worth retrying at the mirrors: the connection failed,
+// or the server responded 5xx, 408 or 429
+type FallbackError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *FallbackError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FallbackError) Unwrap() error {
+	return e.Err
+}
+
+// fallbackStatus reports whether the status of the response is worth retrying at the mirrors
+func fallbackStatus(code int) bool {
+	return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
+
+// WithMirrors calls fetch with the URL, then with its mirrors in order while fetch fails with FallbackError.
+// It returns the last error if all fail.
+func WithMirrors(u string, fetch func(u string) error) error {
+	urls := MirrorURLs(u)
+	var err error
+	for i, mu := range urls {
+		if err = fetch(mu); err == nil {
+			return nil
+		}
+		var fe *FallbackError
+		if !xerrors.As(err, &fe) || i == len(urls)-1 {
+			return err
+		}
+		log15.Warn("Failed to fetch. Falling back to the mirror", "url", mu, "mirror", urls[i+1], "err", err)
+	}
+	return err
+}
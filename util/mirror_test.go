@@ -0,0 +1,73 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFetchURLWithMirrors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		statuses []int
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "upstream",
+			statuses: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+			want:     "0",
+		},
+		{
+			name:     "fallback on 503 and 429",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			want:     "2",
+		},
+		{
+			name:     "no fallback on 404",
+			statuses: []int{http.StatusNotFound, http.StatusOK, http.StatusOK},
+			wantErr:  true,
+		},
+		{
+			name:     "all fail",
+			statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantErr:  true,
+		},
+	}
+
+	defer func() { mirrors = nil }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prefixes []string
+			for i, status := range tt.statuses {
+				i, status := i, status
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != "/data/a.json" {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					w.WriteHeader(status)
+					fmt.Fprint(w, i)
+				}))
+				defer ts.Close()
+				prefixes = append(prefixes, ts.URL+"/data/")
+			}
+			if err := SetMirrors([]Mirror{{Upstream: prefixes[0], URLs: prefixes[1:]}}); err != nil {
+				t.Fatal(err)
+			}
+			if got := MirrorURLs(prefixes[0] + "a.json"); !reflect.DeepEqual(got, []string{prefixes[0] + "a.json", prefixes[1] + "a.json", prefixes[2] + "a.json"}) {
+				t.Errorf("MirrorURLs() = %v", got)
+			}
+
+			body, err := FetchURL(prefixes[0]+"a.json", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(body) != tt.want {
+				t.Errorf("FetchURL() = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
@@ -3,12 +3,38 @@ package util
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/knqyf263/gost/config"
 	"github.com/knqyf263/gost/models"
 )
 
+var (
+	// e.g. openssl-libs-1:3.0.7-18.el9_2, tzdata-2023c-1.el9
+	redhatNEVRRegexp = regexp.MustCompile(`^([^:/\s]+)-(?:(\d+):)?([^-:/\s]+)-([^-:/\s]+)$`)
+	redhatArchRegexp = regexp.MustCompile(`\.(src|noarch|x86_64|i686|aarch64|ppc64le|ppc64|s390x)$`)
+)
+
+// ParseRedhatNEVRA parses the NEVRA (e.g. openssl-libs-1:3.0.7-18.el9_2.x86_64) or the NEVR (e.g. openssl-1:3.0.7-18.el9_2) of the RPM package.
+// The epoch is 0 if it is omitted. It returns false for the others, e.g. the container images and the module streams (nodejs:18-8090020230905112710.a75119d5).
+func ParseRedhatNEVRA(nevra string) (models.RedhatFixedPackage, bool) {
+	arch := ""
+	if m := redhatArchRegexp.FindStringSubmatch(nevra); m != nil {
+		arch = m[1]
+		nevra = strings.TrimSuffix(nevra, "."+arch)
+	}
+	m := redhatNEVRRegexp.FindStringSubmatch(nevra)
+	if m == nil {
+		return models.RedhatFixedPackage{}, false
+	}
+	epoch := m[2]
+	if epoch == "" {
+		epoch = "0"
+	}
+	return models.RedhatFixedPackage{Name: m[1], Epoch: epoch, Version: m[3], Release: m[4], Arch: arch}, true
+}
+
 // DiffRedhat returns the difference between the old and new CVE information
 func DiffRedhat(old, new *models.RedhatCVE, config config.RedhatWatchCve) (body string) {
 	if config.ThreatSeverity {
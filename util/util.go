@@ -1,6 +1,7 @@
 package util
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -15,7 +16,6 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/inconshreveable/log15"
 	"github.com/parnurzeal/gorequest"
-	"github.com/spf13/viper"
 	"golang.org/x/xerrors"
 	pb "gopkg.in/cheggaaa/pb.v1"
 	"gorm.io/gorm"
@@ -70,25 +70,84 @@ func TrimSpaceNewline(str string) string {
 	return strings.Trim(str, "\r\n")
 }
 
+// httpProxy is used by FetchURL and FetchConcurrently
+var httpProxy string
+
+// SetHTTPProxy sets the proxy (e.g. http://proxy-url:port) used to fetch the sources
+func SetHTTPProxy(proxy string) {
+	httpProxy = proxy
+}
+
 // FetchURL returns HTTP response body
 func FetchURL(url, apikey string) ([]byte, error) {
-	var errs []error
-	httpProxy := viper.GetString("http-proxy")
+	header := map[string]string{}
+	if apikey != "" {
+		header["api-key"] = apikey
+	}
+	return FetchURLWithHeader(url, header)
+}
 
+// FetchURLWithHeader returns HTTP response body requested with the header, from the mirrors of the URL if the upstream fails
+func FetchURLWithHeader(url string, header map[string]string) (body []byte, err error) {
+	err = WithMirrors(url, func(u string) error {
+		var ferr error
+		body, ferr = fetchURLWithHeader(u, header)
+		return ferr
+	})
+	return body, err
+}
+
+func fetchURLWithHeader(url string, header map[string]string) ([]byte, error) {
 	req := gorequest.New().Proxy(httpProxy).Get(url)
-	if apikey != "" {
-		req.Header["api-key"] = []string{apikey}
+	for k, v := range header {
+		req.Header[k] = []string{v}
 	}
-	resp, body, err := req.Type("text").EndBytes()
+	resp, body, errs := req.Type("text").EndBytes()
 	if len(errs) > 0 || resp == nil {
-		return nil, fmt.Errorf("HTTP error. errs: %v, url: %s", err, url)
+		return nil, &FallbackError{Err: fmt.Errorf("HTTP error. errs: %v, url: %s", errs, url)}
 	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP error. errs: %v, status code: %d, url: %s", err, resp.StatusCode, url)
+		err := fmt.Errorf("HTTP error. status code: %d, url: %s", resp.StatusCode, url)
+		if fallbackStatus(resp.StatusCode) {
+			return nil, &FallbackError{StatusCode: resp.StatusCode, Err: err}
+		}
+		return nil, err
 	}
 	return body, nil
 }
 
+// PostURLWithHeader returns HTTP response body of POST request of the JSON body with the header
+func PostURLWithHeader(url string, header map[string]string, body []byte) ([]byte, error) {
+	req := gorequest.New().Proxy(httpProxy).Post(url)
+	for k, v := range header {
+		req.Header[k] = []string{v}
+	}
+	resp, respBody, errs := req.Type("json").Send(string(body)).EndBytes()
+	if len(errs) > 0 || resp == nil {
+		return nil, fmt.Errorf("HTTP error. errs: %v, url: %s", errs, url)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP error. status code: %d, url: %s", resp.StatusCode, url)
+	}
+	return respBody, nil
+}
+
+// PostFormURL returns HTTP response body of POST request of the form
+func PostFormURL(url string, form map[string]string) ([]byte, error) {
+	req := gorequest.New().Proxy(httpProxy).Post(url).Type("form")
+	for k, v := range form {
+		req = req.Send(map[string]string{k: v})
+	}
+	resp, respBody, errs := req.EndBytes()
+	if len(errs) > 0 || resp == nil {
+		return nil, fmt.Errorf("HTTP error. errs: %v, url: %s", errs, url)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP error. status code: %d, url: %s", resp.StatusCode, url)
+	}
+	return respBody, nil
+}
+
 // FetchConcurrently fetches concurrently
 func FetchConcurrently(urls []string, concurrency, wait int) (responses [][]byte, err error) {
 	reqChan := make(chan string, len(urls))
@@ -256,6 +315,27 @@ func Exists(path string) (bool, error) {
 	return true, err
 }
 
+// ReadLines reads the non-empty lines of the file. The lines starting with # are skipped
+func ReadLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to open file. path: %s, err: %w", path, err)
+	}
+	defer f.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("Failed to read file. path: %s, err: %w", path, err)
+	}
+	return lines, nil
+}
+
 // StringInSlice search within Slice by String
 func StringInSlice(a string, list []string) bool {
 	for _, b := range list {
@@ -297,6 +377,20 @@ func FilterTargets(prefixPath string, targets map[string]struct{}) (map[string]s
 	return filtered, nil
 }
 
+// CveIDTargets returns the relative paths of the CVEs in vuln-list (e.g. 2021/CVE-2021-1234.json)
+func CveIDTargets(cveIDs []string) map[string]struct{} {
+	targets := map[string]struct{}{}
+	for _, cveID := range cveIDs {
+		ss := strings.Split(cveID, "-")
+		if len(ss) != 3 {
+			log15.Warn("Invalid CVE-ID", "CVE-ID", cveID)
+			continue
+		}
+		targets[filepath.Join(ss[1], cveID+".json")] = struct{}{}
+	}
+	return targets
+}
+
 var (
 	// Quiet manages the display of NewSpinner, ProgressBar
 	Quiet = false
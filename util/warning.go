@@ -0,0 +1,65 @@
+package util
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+
+	"github.com/inconshreveable/log15"
+	"golang.org/x/xerrors"
+)
+
+// Warning is a malformed upstream entry which was skipped or partially converted
+type Warning struct {
+	Source string `json:"source"`
+	// CveID is the CVE-ID, or the advisory ID if the entry is not tied to a CVE
+	CveID  string `json:"cve_id"`
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Warnings collects the warnings of a fetch to be written into the report at the end of it.
+// The DB converts the entries with it by db.WithWarnings. nil only logs the warnings, e.g. in the server.
+type Warnings struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// NewWarnings returns empty Warnings
+func NewWarnings() *Warnings {
+	return &Warnings{warnings: []Warning{}}
+}
+
+// Add logs and collects a warning
+func (w *Warnings) Add(source, cveID, field, reason string) {
+	log15.Warn(reason, "source", source, "cveID", cveID, "field", field)
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.warnings = append(w.warnings, Warning{Source: source, CveID: cveID, Field: field, Reason: reason})
+}
+
+// List returns the collected warnings
+func (w *Warnings) List() []Warning {
+	if w == nil {
+		return []Warning{}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Warning{}, w.warnings...)
+}
+
+// Write writes the collected warnings into path as JSON
+func (w *Warnings) Write(path string) error {
+	b, err := json.MarshalIndent(w.List(), "", "  ")
+	if err != nil {
+		return xerrors.Errorf("Failed to marshal warnings. err: %w", err)
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return xerrors.Errorf("Failed to write warnings. path: %s, err: %w", path, err)
+	}
+	return nil
+}